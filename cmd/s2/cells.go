@@ -0,0 +1,271 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/rubenpoppe/geo/s2"
+	"github.com/rubenpoppe/geo/s2/geojson"
+)
+
+const maxLevel = 30
+
+// parseCellID parses a cell given in any of the forms accepted by the
+// command. Points are converted to the cell at the given level that contains
+// them.
+func parseCellID(s string, level int) (s2.CellID, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.ContainsAny(s, ":,"):
+		ll, err := parseLatLng(strings.Replace(s, ",", ":", 1))
+		if err != nil {
+			return 0, err
+		}
+		return s2.CellIDFromLatLng(ll).Parent(level), nil
+	case strings.Contains(s, "/"):
+		return parseFacePos(s)
+	case len(s) > 16 && strings.Trim(s, "0123456789") == "":
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || !s2.CellID(v).IsValid() {
+			return 0, fmt.Errorf("invalid cell ID %q", s)
+		}
+		return s2.CellID(v), nil
+	}
+	id := s2.CellIDFromToken(s)
+	if !id.IsValid() {
+		return 0, fmt.Errorf("invalid cell token %q", s)
+	}
+	return id, nil
+}
+
+// parseFacePos parses a cell given as "face/quadrants", the form written by
+// CellID.String, or as "face/pos/level".
+func parseFacePos(s string) (s2.CellID, error) {
+	parts := strings.Split(s, "/")
+	face, err := strconv.Atoi(parts[0])
+	if err != nil || face < 0 || face > 5 {
+		return 0, fmt.Errorf("invalid face in cell %q", s)
+	}
+	switch len(parts) {
+	case 2:
+		if len(parts[1]) > maxLevel || strings.Trim(parts[1], "0123") != "" {
+			return 0, fmt.Errorf("invalid cell %q, want face/quadrants with up to %d quadrants 0-3", s, maxLevel)
+		}
+		id := s2.CellIDFromFace(face)
+		for _, c := range parts[1] {
+			id = id.Children()[c-'0']
+		}
+		return id, nil
+	case 3:
+		pos, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil || pos >= 1<<61 {
+			return 0, fmt.Errorf("invalid position in cell %q", s)
+		}
+		level, err := strconv.Atoi(parts[2])
+		if err != nil || level < 0 || level > maxLevel {
+			return 0, fmt.Errorf("invalid level in cell %q", s)
+		}
+		return s2.CellIDFromFacePosLevel(face, pos, level), nil
+	}
+	return 0, fmt.Errorf("invalid cell %q, want face/quadrants or face/pos/level", s)
+}
+
+// formatFlag defines the -o flag, which selects the output format.
+func formatFlag(fs *flag.FlagSet) *string {
+	return fs.String("o", "text", "output `format`: text or geojson")
+}
+
+// checkFormat returns a usage error if the format is not known.
+func checkFormat(e *env, fs *flag.FlagSet, format string) error {
+	if format != "text" && format != "geojson" {
+		return usageErrorf(e, fs, "unknown output format %q", format)
+	}
+	return nil
+}
+
+// writeGeoJSON writes the geometry as a line of JSON.
+func writeGeoJSON(w io.Writer, g *geojson.Geometry) error {
+	data, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// cellGeometry returns the GeoJSON polygon of a cell.
+func cellGeometry(id s2.CellID) (*geojson.Geometry, error) {
+	return geojson.FromPolygon(s2.PolygonFromCell(s2.CellFromCellID(id)))
+}
+
+// writeCells writes a list of cells, either as tokens, one per line, or as a
+// GeoJSON GeometryCollection of their polygons.
+func writeCells(w io.Writer, format string, ids []s2.CellID) error {
+	if format == "text" {
+		for _, id := range ids {
+			if _, err := fmt.Fprintln(w, id.ToToken()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	collection := &geojson.Geometry{Type: geojson.TypeGeometryCollection}
+	for _, id := range ids {
+		g, err := cellGeometry(id)
+		if err != nil {
+			return err
+		}
+		collection.Geometries = append(collection.Geometries, g)
+	}
+	return writeGeoJSON(w, collection)
+}
+
+// writeCellInfo writes the description of a cell in the text format.
+func writeCellInfo(w io.Writer, id s2.CellID, unit string) error {
+	cell := s2.CellFromCellID(id)
+	vertices := make([]s2.Point, 4)
+	for k := range vertices {
+		vertices[k] = cell.Vertex(k)
+	}
+	r := earthRadius[unit]
+	_, err := fmt.Fprintf(w, "token     %s\nid        %d\nface      %d\npos       %d\nlevel     %d\ncell      %s\ncenter    %s\nvertices  %s\narea      %s %s²\n",
+		id.ToToken(), uint64(id), id.Face(), id.Pos(), id.Level(), id,
+		formatPoint(cell.Center()), formatPoints(vertices),
+		formatMeasure(cell.ExactArea()*r*r), unit)
+	return err
+}
+
+func runCell(e *env, fs *flag.FlagSet, args []string) error {
+	level := fs.Int("level", maxLevel, "`level` of the cells that contain the given points")
+	unit := unitFlag(fs)
+	format := formatFlag(fs)
+	if err := parseFlags(fs, args, 0, -1); err != nil {
+		return err
+	}
+	if *level < 0 || *level > maxLevel {
+		return usageErrorf(e, fs, "level %d is out of range [0, %d]", *level, maxLevel)
+	}
+	if err := checkUnit(e, fs, *unit); err != nil {
+		return err
+	}
+	if err := checkFormat(e, fs, *format); err != nil {
+		return err
+	}
+	inputs, err := e.inputLines(fs.Args())
+	if err != nil {
+		return err
+	}
+	for i, s := range inputs {
+		id, err := parseCellID(s, *level)
+		if err != nil {
+			return err
+		}
+		if *format == "geojson" {
+			g, err := cellGeometry(id)
+			if err != nil {
+				return err
+			}
+			if err := writeGeoJSON(e.stdout, g); err != nil {
+				return err
+			}
+			continue
+		}
+		if i > 0 {
+			fmt.Fprintln(e.stdout)
+		}
+		if err := writeCellInfo(e.stdout, id, *unit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runNeighbors(e *env, fs *flag.FlagSet, args []string) error {
+	kind := fs.String("kind", "edge", "`kind` of neighbors: edge for the four cells across the edges, "+
+		"vertex for the cells around the closest vertex, or all for every cell that touches the cell")
+	level := fs.Int("level", -1, "`level` of the vertex or all neighbors; "+
+		"by default the level of the cell for all, and one less for vertex")
+	format := formatFlag(fs)
+	if err := parseFlags(fs, args, 1, 1); err != nil {
+		return err
+	}
+	if err := checkFormat(e, fs, *format); err != nil {
+		return err
+	}
+	id, err := parseCellID(fs.Arg(0), maxLevel)
+	if err != nil {
+		return err
+	}
+
+	var neighbors []s2.CellID
+	switch *kind {
+	case "edge":
+		if *level >= 0 {
+			return usageErrorf(e, fs, "-level cannot be used with edge neighbors")
+		}
+		edges := id.EdgeNeighbors()
+		neighbors = edges[:]
+	case "vertex":
+		l := *level
+		if l < 0 {
+			l = id.Level() - 1
+		}
+		if l < 0 || l >= id.Level() {
+			return fmt.Errorf("vertex neighbors of a level %d cell need a level in [0, %d)", id.Level(), id.Level())
+		}
+		neighbors = id.VertexNeighbors(l)
+	case "all":
+		l := *level
+		if l < 0 {
+			l = id.Level()
+		}
+		if l < id.Level() || l > maxLevel {
+			return fmt.Errorf("all neighbors of a level %d cell need a level in [%d, %d]", id.Level(), id.Level(), maxLevel)
+		}
+		neighbors = id.AllNeighbors(l)
+	default:
+		return usageErrorf(e, fs, "unknown kind of neighbors %q", *kind)
+	}
+	return writeCells(e.stdout, *format, neighbors)
+}
+
+func runParents(e *env, fs *flag.FlagSet, args []string) error {
+	minLevel := fs.Int("min_level", 0, "lowest `level` of the parents")
+	format := formatFlag(fs)
+	if err := parseFlags(fs, args, 1, 1); err != nil {
+		return err
+	}
+	if *minLevel < 0 || *minLevel > maxLevel {
+		return usageErrorf(e, fs, "level %d is out of range [0, %d]", *minLevel, maxLevel)
+	}
+	if err := checkFormat(e, fs, *format); err != nil {
+		return err
+	}
+	id, err := parseCellID(fs.Arg(0), maxLevel)
+	if err != nil {
+		return err
+	}
+	var parents []s2.CellID
+	for level := id.Level() - 1; level >= *minLevel; level-- {
+		parents = append(parents, id.Parent(level))
+	}
+	return writeCells(e.stdout, *format, parents)
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rubenpoppe/geo/s2"
+)
+
+func TestParseCellID(t *testing.T) {
+	want := s2.CellIDFromToken("89c25")
+	leaf := s2.CellIDFromLatLng(s2.LatLngFromDegrees(40.7, -74))
+	tests := []struct {
+		have  string
+		level int
+		want  s2.CellID
+	}{
+		{"89c25", 30, want},
+		{"9926584489608216576", 30, want},
+		{want.String(), 30, want},
+		{"4/703212452753440768/8", 30, want},
+		{"40.7:-74", 30, leaf},
+		{"40.7, -74", 8, leaf.Parent(8)},
+		// Tokens may consist of decimal digits only.
+		{"1", 30, s2.CellIDFromToken("1")},
+		{"0/", 30, s2.CellIDFromFace(0)},
+	}
+	for _, test := range tests {
+		got, err := parseCellID(test.have, test.level)
+		if err != nil || got != test.want {
+			t.Errorf("parseCellID(%q, %d) = %v, %v, want %v", test.have, test.level, got, err, test.want)
+		}
+	}
+
+	for _, have := range []string{"", "X", "zz", "6/", "1/0124", "1/5/31", "1/1/2/3", "99999999999999999999", "91:0"} {
+		if got, err := parseCellID(have, 30); err == nil {
+			t.Errorf("parseCellID(%q) = %v, want an error", have, got)
+		}
+	}
+}
+
+func TestRunCell(t *testing.T) {
+	stdout, stderr, status := runCommand("89c25\n\n4/0010\n", "cell")
+	if status != 0 {
+		t.Fatalf("s2 cell failed: %s", stderr)
+	}
+	for _, want := range []string{
+		"token     89c25\n", "id        9926584489608216576\n", "face      4\n",
+		"level     8\n", "cell      4/10320102\n", "center    40.6430766286765:-74.0300122498385\n",
+		"\n\ntoken     809\n", "area      277816.4952 km²\n",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("s2 cell = %q, want it to contain %q", stdout, want)
+		}
+	}
+
+	stdout, _, _ = runCommand("", "cell", "-level", "3", "-o", "geojson", "0:0", "10:10")
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("s2 cell -o geojson wrote %d lines, want 2", len(lines))
+	}
+	var g struct {
+		Type        string
+		Coordinates [][][2]float64
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &g); err != nil || g.Type != "Polygon" || len(g.Coordinates) != 1 || len(g.Coordinates[0]) != 5 {
+		t.Errorf("s2 cell -o geojson = %s, want a polygon with 4 vertices", lines[0])
+	}
+
+	if _, stderr, status := runCommand("", "cell", "-level", "31", "0:0"); status != 2 || !strings.Contains(stderr, "out of range") {
+		t.Errorf("s2 cell -level 31 exited with %d and wrote %q, want a usage error", status, stderr)
+	}
+}
+
+func TestRunNeighbors(t *testing.T) {
+	id := s2.CellIDFromToken("89c25")
+	tests := []struct {
+		args []string
+		want []s2.CellID
+	}{
+		{[]string{"89c25"}, func() []s2.CellID { n := id.EdgeNeighbors(); return n[:] }()},
+		{[]string{"-kind", "vertex", "89c25"}, id.VertexNeighbors(7)},
+		{[]string{"-kind", "vertex", "-level", "5", "89c25"}, id.VertexNeighbors(5)},
+		{[]string{"-kind", "all", "89c25"}, id.AllNeighbors(8)},
+		{[]string{"-kind", "all", "-level", "9", "89c25"}, id.AllNeighbors(9)},
+	}
+	for _, test := range tests {
+		stdout, stderr, status := runCommand("", append([]string{"neighbors"}, test.args...)...)
+		if status != 0 {
+			t.Errorf("s2 neighbors %q failed: %s", test.args, stderr)
+			continue
+		}
+		if got, want := stdout, tokenLines(test.want); got != want {
+			t.Errorf("s2 neighbors %q = %q, want %q", test.args, got, want)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"-kind", "vertex", "-level", "8", "89c25"},
+		{"-kind", "vertex", "1"},
+		{"-kind", "all", "-level", "7", "89c25"},
+	} {
+		if _, _, status := runCommand("", append([]string{"neighbors"}, args...)...); status != 1 {
+			t.Errorf("s2 neighbors %q exited with %d, want 1", args, status)
+		}
+	}
+	for _, args := range [][]string{
+		{"-level", "9", "89c25"},
+		{"-kind", "diagonal", "89c25"},
+	} {
+		if _, _, status := runCommand("", append([]string{"neighbors"}, args...)...); status != 2 {
+			t.Errorf("s2 neighbors %q exited with %d, want 2", args, status)
+		}
+	}
+}
+
+func TestRunParents(t *testing.T) {
+	id := s2.CellIDFromToken("89c25")
+	stdout, _, status := runCommand("", "parents", "-min_level", "5", "89c25")
+	if want := tokenLines([]s2.CellID{id.Parent(7), id.Parent(6), id.Parent(5)}); status != 0 || stdout != want {
+		t.Errorf("s2 parents -min_level 5 89c25 = %q, want %q", stdout, want)
+	}
+	stdout, _, _ = runCommand("", "parents", "-o", "geojson", "89c25")
+	var g struct {
+		Type       string
+		Geometries []json.RawMessage
+	}
+	if err := json.Unmarshal([]byte(stdout), &g); err != nil || g.Type != "GeometryCollection" || len(g.Geometries) != 8 {
+		t.Errorf("s2 parents -o geojson = %s, want a collection of 8 cells", stdout)
+	}
+	if stdout, _, _ := runCommand("", "parents", "2/"); stdout != "" {
+		t.Errorf("s2 parents of a face cell = %q, want no parents", stdout)
+	}
+}
+
+// tokenLines returns the tokens of the cells, one per line.
+func tokenLines(ids []s2.CellID) string {
+	var b strings.Builder
+	for _, id := range ids {
+		b.WriteString(id.ToToken() + "\n")
+	}
+	return b.String()
+}
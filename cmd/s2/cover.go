@@ -0,0 +1,66 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+
+	"github.com/rubenpoppe/geo/s2"
+)
+
+func runCover(e *env, fs *flag.FlagSet, args []string) error {
+	rc := s2.NewRegionCoverer()
+	kind := fs.String("kind", "polygon", "`kind` of region in the text format: point, polyline, polygon or rect, "+
+		"where a rect is the bound of its points; GeoJSON input is read according to its type")
+	fs.IntVar(&rc.MinLevel, "min_level", rc.MinLevel, "minimum `level` of the cells")
+	fs.IntVar(&rc.MaxLevel, "max_level", rc.MaxLevel, "maximum `level` of the cells")
+	fs.IntVar(&rc.LevelMod, "level_mod", rc.LevelMod, "only use levels that are a multiple of `n` above min_level, between 1 and 3")
+	fs.IntVar(&rc.MaxCells, "max_cells", rc.MaxCells, "desired maximum `number` of cells")
+	interior := fs.Bool("interior", false, "compute an interior covering, whose cells are all contained in the region")
+	format := formatFlag(fs)
+	if err := parseFlags(fs, args, 0, -1); err != nil {
+		return err
+	}
+	switch {
+	case rc.MinLevel < 0 || rc.MinLevel > maxLevel:
+		return usageErrorf(e, fs, "min_level %d is out of range [0, %d]", rc.MinLevel, maxLevel)
+	case rc.MaxLevel < 0 || rc.MaxLevel > maxLevel:
+		return usageErrorf(e, fs, "max_level %d is out of range [0, %d]", rc.MaxLevel, maxLevel)
+	case rc.MinLevel > rc.MaxLevel:
+		return usageErrorf(e, fs, "min_level %d is greater than max_level %d", rc.MinLevel, rc.MaxLevel)
+	case rc.LevelMod < 1 || rc.LevelMod > 3:
+		return usageErrorf(e, fs, "level_mod %d is out of range [1, 3]", rc.LevelMod)
+	case rc.MaxCells < 1:
+		return usageErrorf(e, fs, "max_cells must be at least 1")
+	}
+	if err := checkFormat(e, fs, *format); err != nil {
+		return err
+	}
+	s, err := e.input(fs.Args())
+	if err != nil {
+		return err
+	}
+	region, err := readRegion(*kind, s)
+	if err != nil {
+		return err
+	}
+	var covering s2.CellUnion
+	if *interior {
+		covering = rc.InteriorCovering(region)
+	} else {
+		covering = rc.Covering(region)
+	}
+	return writeCells(e.stdout, *format, covering)
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rubenpoppe/geo/s2"
+)
+
+func TestRunCover(t *testing.T) {
+	const square = "0:0, 0:10, 10:10, 10:0"
+	polygon, _ := parsePolygon(square)
+	tests := []struct {
+		args  []string
+		stdin string
+		rc    s2.RegionCoverer
+		inner bool
+	}{
+		{[]string{square}, "", s2.RegionCoverer{MinLevel: 0, MaxLevel: 30, LevelMod: 1, MaxCells: 8}, false},
+		{nil, square, s2.RegionCoverer{MinLevel: 0, MaxLevel: 30, LevelMod: 1, MaxCells: 8}, false},
+		{[]string{"-max_cells", "20", "-min_level", "4", "-max_level", "10", "-level_mod", "2", square}, "",
+			s2.RegionCoverer{MinLevel: 4, MaxLevel: 10, LevelMod: 2, MaxCells: 20}, false},
+		{[]string{"-interior", "-max_level", "8", square}, "", s2.RegionCoverer{MinLevel: 0, MaxLevel: 8, LevelMod: 1, MaxCells: 8}, true},
+	}
+	for _, test := range tests {
+		stdout, stderr, status := runCommand(test.stdin, append([]string{"cover"}, test.args...)...)
+		if status != 0 {
+			t.Errorf("s2 cover %q failed: %s", test.args, stderr)
+			continue
+		}
+		want := test.rc.Covering(polygon)
+		if test.inner {
+			want = test.rc.InteriorCovering(polygon)
+		}
+		if got := stdout; got != tokenLines(want) {
+			t.Errorf("s2 cover %q = %q, want %q", test.args, got, tokenLines(want))
+		}
+	}
+
+	// GeoJSON regions are covered according to their type.
+	point := s2.PointFromLatLng(s2.LatLngFromDegrees(10, 20))
+	stdout, _, _ := runCommand(`{"type": "Point", "coordinates": [20, 10]}`, "cover")
+	if want := s2.CellIDFromLatLng(s2.LatLngFromPoint(point)).ToToken() + "\n"; stdout != want {
+		t.Errorf("s2 cover of a GeoJSON point = %q, want %q", stdout, want)
+	}
+	stdout, _, _ = runCommand("", "cover", "-kind", "rect", "-max_cells", "1", "0:0, 1:1")
+	if n := strings.Count(stdout, "\n"); n != 1 {
+		t.Errorf("s2 cover -kind rect -max_cells 1 = %q, want one cell", stdout)
+	}
+	stdout, _, _ = runCommand("", "cover", "-o", "geojson", square)
+	if !strings.HasPrefix(stdout, `{"type":"GeometryCollection","geometries":[{"type":"Polygon"`) {
+		t.Errorf("s2 cover -o geojson = %q, want a collection of polygons", stdout)
+	}
+}
+
+func TestRunCoverErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"-min_level", "-1", "0:0"},
+		{"-max_level", "31", "0:0"},
+		{"-min_level", "10", "-max_level", "5", "0:0"},
+		{"-level_mod", "4", "0:0"},
+		{"-max_cells", "0", "0:0"},
+	} {
+		if _, _, status := runCommand("", append([]string{"cover"}, args...)...); status != 2 {
+			t.Errorf("s2 cover %q exited with %d, want 2", args, status)
+		}
+	}
+	if _, stderr, status := runCommand("", "cover", "-kind", "point", "0:0, 1:1"); status != 1 || !strings.Contains(stderr, "got 2 points") {
+		t.Errorf("s2 cover -kind point with 2 points exited with %d and wrote %q", status, stderr)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command s2 performs everyday tasks with the s2 library from the command
+// line.
+//
+// Usage:
+//
+//	s2 <command> [flags] [arguments]
+//
+// The commands are:
+//
+//	cell       describe cells given as points, tokens, IDs or face/pos/level
+//	cover      compute the covering of a region
+//	validate   check a polygon and report all of its errors
+//	neighbors  print the neighbors of a cell
+//	parents    print the parents of a cell
+//	distance   compute the distance between two geometries
+//	area       compute the area of a polygon
+//
+// Geometry is read in the text format of the s2 tests, where points are
+// written as "lat:lng" in degrees, separated by commas, and the loops of a
+// polygon are separated by semicolons. Input that starts with "{" is read as
+// GeoJSON instead. Commands that take a single geometry read it from standard
+// input if it is not given as an argument.
+//
+// Cells are given as "lat:lng" or "lat,lng" points, tokens such as "89c25",
+// decimal IDs, "face/quadrants" strings such as "4/0013", or "face/pos/level"
+// triples. Since tokens may consist of decimal digits only, a decimal ID must
+// have more than 16 digits; smaller IDs have to be given as tokens.
+//
+// Run "s2 <command> -h" for the flags of a command.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// env holds the input and outputs of a run of the command.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// A command is a subcommand of s2.
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(e *env, fs *flag.FlagSet, args []string) error
+}
+
+var commands = []*command{
+	{"cell", "[-level n] [-unit u] [-o format] cell...", "describe cells given as points, tokens, IDs or face/pos/level", runCell},
+	{"cover", "[-kind k] [-min_level n] [-max_level n] [-level_mod n] [-max_cells n] [-interior] [-o format] [region]", "compute the covering of a region", runCover},
+	{"validate", "[polygon]", "check a polygon and report all of its errors", runValidate},
+	{"neighbors", "[-kind k] [-level n] [-o format] cell", "print the neighbors of a cell", runNeighbors},
+	{"parents", "[-min_level n] [-o format] cell", "print the parents of a cell", runParents},
+	{"distance", "[-unit u] a b", "compute the distance between two geometries", runDistance},
+	{"area", "[-unit u] [polygon]", "compute the area of a polygon", runArea},
+}
+
+var (
+	// errUsage is returned by commands that were given invalid arguments,
+	// after the usage has been printed.
+	errUsage = errors.New("usage error")
+
+	// errInvalid is returned by commands whose input was checked and found
+	// to be invalid, after the problems have been printed.
+	errInvalid = errors.New("invalid input")
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], &env{os.Stdin, os.Stdout, os.Stderr}))
+}
+
+// run runs the command line given by args and returns the exit status: 0 on
+// success, 1 if the command failed and 2 if it was not used correctly.
+func run(args []string, e *env) int {
+	if len(args) == 0 {
+		usage(e.stderr)
+		return 2
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		usage(e.stdout)
+		return 0
+	}
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+		fs.SetOutput(e.stderr)
+		fs.Usage = func() {
+			fmt.Fprintf(e.stderr, "usage: s2 %s %s\n\n%s.\n", cmd.name, cmd.args, strings.ToUpper(cmd.summary[:1])+cmd.summary[1:])
+			if hasFlags(fs) {
+				fmt.Fprintln(e.stderr, "\nFlags:")
+				fs.PrintDefaults()
+			}
+		}
+		err := cmd.run(e, fs, args[1:])
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUsage):
+			return 2
+		case errors.Is(err, errInvalid):
+			return 1
+		}
+		fmt.Fprintf(e.stderr, "s2 %s: %v\n", cmd.name, err)
+		return 1
+	}
+	fmt.Fprintf(e.stderr, "s2: unknown command %q\n", args[0])
+	usage(e.stderr)
+	return 2
+}
+
+// usage writes the list of commands to w.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: s2 <command> [flags] [arguments]")
+	fmt.Fprintln(w, "\nThe commands are:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "\nRun \"s2 <command> -h\" for the flags of a command.")
+}
+
+// hasFlags reports whether any flags are defined in fs.
+func hasFlags(fs *flag.FlagSet) bool {
+	found := false
+	fs.VisitAll(func(*flag.Flag) { found = true })
+	return found
+}
+
+// parseFlags parses the flags in args and checks that the number of remaining
+// arguments is within [min, max], where a negative max means no limit.
+func parseFlags(fs *flag.FlagSet, args []string, min, max int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if n := fs.NArg(); n < min || max >= 0 && n > max {
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// usageErrorf reports a problem with the flags or arguments of the command.
+func usageErrorf(e *env, fs *flag.FlagSet, format string, args ...interface{}) error {
+	fmt.Fprintf(e.stderr, "s2 %s: %s\n", fs.Name(), fmt.Sprintf(format, args...))
+	fs.Usage()
+	return errUsage
+}
+
+// input returns the arguments joined by spaces, or all of standard input if
+// there are no arguments.
+func (e *env) input(args []string) (string, error) {
+	if len(args) > 0 {
+		return strings.Join(args, " "), nil
+	}
+	data, err := io.ReadAll(e.stdin)
+	if err != nil {
+		return "", fmt.Errorf("reading input: %v", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// inputLines returns the arguments, or the non-empty lines of standard input
+// if there are no arguments.
+func (e *env) inputLines(args []string) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+	data, err := io.ReadAll(e.stdin)
+	if err != nil {
+		return nil, fmt.Errorf("reading input: %v", err)
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
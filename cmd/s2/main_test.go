@@ -0,0 +1,78 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// runCommand runs the command line with the given standard input, and returns
+// its output and exit status.
+func runCommand(stdin string, args ...string) (stdout, stderr string, status int) {
+	var out, errOut bytes.Buffer
+	status = run(args, &env{strings.NewReader(stdin), &out, &errOut})
+	return out.String(), errOut.String(), status
+}
+
+func TestRunUsage(t *testing.T) {
+	tests := []struct {
+		args       []string
+		wantStatus int
+		wantStderr string
+	}{
+		{nil, 2, "usage: s2 <command>"},
+		{[]string{"frobnicate"}, 2, `unknown command "frobnicate"`},
+		{[]string{"parents", "-bogus", "89c25"}, 2, "flag provided but not defined"},
+		{[]string{"parents"}, 2, "usage: s2 parents"},
+		{[]string{"parents", "89c25", "89c27"}, 2, "usage: s2 parents"},
+		{[]string{"parents", "-h"}, 0, "-min_level level"},
+		{[]string{"cell", "-o", "xml", "89c25"}, 2, `unknown output format "xml"`},
+		{[]string{"cell", "nonsense"}, 1, `s2 cell: invalid cell token "nonsense"`},
+	}
+	for _, test := range tests {
+		_, stderr, status := runCommand("", test.args...)
+		if status != test.wantStatus || !strings.Contains(stderr, test.wantStderr) {
+			t.Errorf("s2 %q exited with %d and wrote %q, want %d and %q", test.args, status, stderr, test.wantStatus, test.wantStderr)
+		}
+	}
+}
+
+func TestRunHelp(t *testing.T) {
+	stdout, _, status := runCommand("", "help")
+	if status != 0 {
+		t.Errorf("s2 help exited with %d, want 0", status)
+	}
+	for _, cmd := range commands {
+		if !strings.Contains(stdout, "  "+cmd.name) {
+			t.Errorf("s2 help = %q, want it to list %q", stdout, cmd.name)
+		}
+	}
+}
+
+func TestInputLines(t *testing.T) {
+	e := &env{stdin: strings.NewReader("  89c25\n\n4/0010  \n")}
+	got, err := e.inputLines(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"89c25", "4/0010"}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("inputLines(nil) = %q, want %q", got, want)
+	}
+	if got, _ := e.inputLines([]string{"a b"}); len(got) != 1 || got[0] != "a b" {
+		t.Errorf(`inputLines(["a b"]) = %q, want the arguments`, got)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/rubenpoppe/geo/s1"
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// earthRadius is the mean radius of the Earth in each of the supported
+// units of length.
+var earthRadius = map[string]float64{
+	"km": 6371.01,
+	"m":  6371010,
+	"mi": 6371.01 / 1.609344,
+}
+
+// unitFlag defines the -unit flag, which selects the unit of length.
+func unitFlag(fs *flag.FlagSet) *string {
+	return fs.String("unit", "km", "`unit` of length: km, m or mi")
+}
+
+// checkUnit returns a usage error if the unit is not known.
+func checkUnit(e *env, fs *flag.FlagSet, unit string) error {
+	if _, ok := earthRadius[unit]; !ok {
+		return usageErrorf(e, fs, "unknown unit %q", unit)
+	}
+	return nil
+}
+
+// formatMeasure formats a distance or an area with 10 significant digits.
+func formatMeasure(v float64) string {
+	return strconv.FormatFloat(v, 'g', 10, 64)
+}
+
+// shapes returns the shapes of the index.
+func shapes(index *s2.ShapeIndex) []s2.Shape {
+	var result []s2.Shape
+	for id := int32(0); len(result) < index.Len(); id++ {
+		if shape := index.Shape(id); shape != nil {
+			result = append(result, shape)
+		}
+	}
+	return result
+}
+
+// indexDistance returns the distance between the geometry in two indexes,
+// which is zero if they intersect or if one contains the other, and infinite
+// if either is empty.
+func indexDistance(a, b *s2.ShapeIndex) s1.ChordAngle {
+	shapesA, shapesB := shapes(a), shapes(b)
+	if len(shapesA) == 0 || len(shapesB) == 0 {
+		return s1.InfChordAngle()
+	}
+	for _, shape := range append(shapesA, shapesB...) {
+		if shape.IsFull() {
+			return 0
+		}
+	}
+
+	opts := s2.NewClosestEdgeQueryOptions().IncludeInteriors(true)
+	queryA := s2.NewClosestEdgeQuery(a, opts)
+	queryB := s2.NewClosestEdgeQuery(b, opts)
+	dist := s1.InfChordAngle()
+	// The distances from the edges of b to a cover every case except for a
+	// shape of a that is inside b, which is found by the distance from one of
+	// its vertices to b.
+	for _, shape := range shapesB {
+		for i := 0; i < shape.NumEdges(); i++ {
+			e := shape.Edge(i)
+			var d s1.ChordAngle
+			if e.V0 == e.V1 {
+				d = queryA.Distance(s2.NewMinDistanceToPointTarget(e.V0))
+			} else {
+				d = queryA.Distance(s2.NewMinDistanceToEdgeTarget(e))
+			}
+			if d < dist {
+				dist = d
+			}
+		}
+	}
+	for _, shape := range shapesA {
+		if shape.NumEdges() == 0 {
+			continue
+		}
+		if d := queryB.Distance(s2.NewMinDistanceToPointTarget(shape.Edge(0).V0)); d < dist {
+			dist = d
+		}
+	}
+	return dist
+}
+
+func runDistance(e *env, fs *flag.FlagSet, args []string) error {
+	unit := unitFlag(fs)
+	if err := parseFlags(fs, args, 2, 2); err != nil {
+		return err
+	}
+	if err := checkUnit(e, fs, *unit); err != nil {
+		return err
+	}
+	var indexes [2]*s2.ShapeIndex
+	for i := range indexes {
+		index, err := readShapeIndex(fs.Arg(i))
+		if err != nil {
+			return err
+		}
+		indexes[i] = index
+	}
+	dist := indexDistance(indexes[0], indexes[1])
+	if dist == s1.InfChordAngle() {
+		return fmt.Errorf("cannot measure the distance to empty geometry")
+	}
+	angle := dist.Angle()
+	_, err := fmt.Fprintf(e.stdout, "%s %s (%s°)\n", formatMeasure(angle.Radians()*earthRadius[*unit]), *unit, formatMeasure(angle.Degrees()))
+	return err
+}
+
+func runArea(e *env, fs *flag.FlagSet, args []string) error {
+	unit := unitFlag(fs)
+	if err := parseFlags(fs, args, 0, -1); err != nil {
+		return err
+	}
+	if err := checkUnit(e, fs, *unit); err != nil {
+		return err
+	}
+	s, err := e.input(fs.Args())
+	if err != nil {
+		return err
+	}
+	p, err := readPolygon(s)
+	if err != nil {
+		return err
+	}
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("invalid polygon: %v", err)
+	}
+	r := earthRadius[*unit]
+	_, err = fmt.Fprintf(e.stdout, "%s %s²\n", formatMeasure(p.Area()*r*r), *unit)
+	return err
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIndexDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64 // in degrees
+	}{
+		{"0:0", "0:1", 1},
+		{"0:0 | 0:5", "0:3", 2},
+		// From a point to a line and to a polygon.
+		{"0:2", "# -5:0, 5:0 #", 2},
+		{"# # 0:0, 0:10, 10:10, 10:0", "0:13", 3},
+		// Intersecting and nested geometry.
+		{"# 5:-5, 5:15 #", "# # 0:0, 0:10, 10:10, 10:0", 0},
+		{"# # 0:0, 0:10, 10:10, 10:0", "4:4", 0},
+		{"4:4", "# # 0:0, 0:10, 10:10, 10:0", 0},
+		{"# # 4:4, 4:5, 5:5", "# # 0:0, 0:10, 10:10, 10:0", 0},
+		{"# # full", "50:50", 0},
+	}
+	for _, test := range tests {
+		a, err := parseShapeIndex(test.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := parseShapeIndex(test.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := indexDistance(a, b).Angle().Degrees()
+		if math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("indexDistance(%q, %q) = %v°, want %v°", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestRunDistance(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"0:0", "0:1"}, "111.1951012 km (1°)\n"},
+		{[]string{"-unit", "m", "0:0", `{"type": "Point", "coordinates": [1, 0]}`}, "111195.1012 m (1°)\n"},
+		{[]string{"-unit", "mi", "0:0", "0:1"}, "69.09343259 mi (1°)\n"},
+	}
+	for _, test := range tests {
+		stdout, stderr, status := runCommand("", append([]string{"distance"}, test.args...)...)
+		if status != 0 || stdout != test.want {
+			t.Errorf("s2 distance %q = %q (%s), want %q", test.args, stdout, stderr, test.want)
+		}
+	}
+	if _, _, status := runCommand("", "distance", "0:0", "# # empty"); status != 1 {
+		t.Errorf("s2 distance to empty geometry exited with %d, want 1", status)
+	}
+	if _, _, status := runCommand("", "distance", "-unit", "ly", "0:0", "0:1"); status != 2 {
+		t.Errorf("s2 distance -unit ly exited with %d, want 2", status)
+	}
+}
+
+func TestRunArea(t *testing.T) {
+	stdout, stderr, status := runCommand("0:0, 0:1, 1:1, 1:0", "area")
+	if want := "12364.03657 km²\n"; status != 0 || stdout != want {
+		t.Errorf("s2 area = %q (%s), want %q", stdout, stderr, want)
+	}
+	stdout, _, _ = runCommand("", "area", "-unit", "m", "full")
+	if want := "5.100660731e+14 m²\n"; stdout != want {
+		t.Errorf("s2 area -unit m full = %q, want %q", stdout, want)
+	}
+	if _, stderr, status := runCommand("", "area", "0:0, 0:10, 10:10, 10:0; 5:5, 5:15, 15:15, 15:5"); status != 1 || stderr == "" {
+		t.Errorf("s2 area of an invalid polygon exited with %d, want 1 and an error", status)
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+// This file reads and writes the text format used by the s2 tests, and
+// decodes GeoJSON input. Geometry in the text format is a comma separated
+// list of latitude-longitude pairs in degrees:
+//
+//     "-20:150"                          // one point
+//     "-20:150, 10:-120, 0.123:-170.652" // three points
+//
+// Polygons are lists of loops separated by semicolons, or "empty" or "full".
+// A shape index is written as "points # polylines # polygons", where the
+// shapes of each kind are separated by "|".
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/rubenpoppe/geo/s2"
+	"github.com/rubenpoppe/geo/s2/geojson"
+)
+
+// parseLatLng parses a single "lat:lng" pair in degrees.
+func parseLatLng(s string) (s2.LatLng, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return s2.LatLng{}, fmt.Errorf("invalid point %q, want lat:lng", s)
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return s2.LatLng{}, fmt.Errorf("invalid latitude in %q", s)
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || math.IsNaN(lng) || math.IsInf(lng, 0) {
+		return s2.LatLng{}, fmt.Errorf("invalid longitude in %q", s)
+	}
+	return s2.LatLngFromDegrees(lat, lng), nil
+}
+
+// parsePoints parses a comma separated list of "lat:lng" pairs. An empty
+// string has no points.
+func parsePoints(s string) ([]s2.Point, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	var points []s2.Point
+	for _, field := range strings.Split(s, ",") {
+		ll, err := parseLatLng(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, s2.PointFromLatLng(ll))
+	}
+	return points, nil
+}
+
+// parseLoops parses the loops of a polygon, as they are written, without
+// normalizing them. The polygon "empty" has no loops. Within a list of loops,
+// "empty" and "full" are the empty and full loops.
+func parseLoops(s string) ([]*s2.Loop, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "empty" {
+		return nil, nil
+	}
+	var loops []*s2.Loop
+	for i, field := range strings.Split(s, ";") {
+		switch strings.TrimSpace(field) {
+		case "":
+			// A trailing semicolon is allowed.
+			continue
+		case "empty":
+			loops = append(loops, s2.EmptyLoop())
+			continue
+		case "full":
+			loops = append(loops, s2.FullLoop())
+			continue
+		}
+		points, err := parsePoints(field)
+		if err != nil {
+			return nil, fmt.Errorf("loop %d: %v", i, err)
+		}
+		loops = append(loops, s2.LoopFromPoints(points))
+	}
+	return loops, nil
+}
+
+// parsePolygon parses a polygon. Each loop is normalized to enclose at most
+// half the sphere, so the orientation of the loops does not matter.
+func parsePolygon(s string) (*s2.Polygon, error) {
+	loops, err := parseLoops(s)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range loops {
+		if !l.IsFull() {
+			l.Normalize()
+		}
+	}
+	return s2.PolygonFromLoops(loops), nil
+}
+
+// parseRect parses a rectangle given by two or more points, as the bound of
+// those points.
+func parseRect(s string) (s2.Rect, error) {
+	points, err := parsePoints(s)
+	if err != nil {
+		return s2.Rect{}, err
+	}
+	if len(points) < 2 {
+		return s2.Rect{}, fmt.Errorf("rectangle has %d points, want at least 2", len(points))
+	}
+	rect := s2.EmptyRect()
+	for _, p := range points {
+		rect = rect.AddPoint(s2.LatLngFromPoint(p))
+	}
+	return rect, nil
+}
+
+// parseShapeIndex parses a shape index in the "points # polylines #
+// polygons" form. Input without "#" is read as points.
+func parseShapeIndex(s string) (*s2.ShapeIndex, error) {
+	fields := strings.Split(s, "#")
+	switch len(fields) {
+	case 1:
+		fields = append(fields, "", "")
+	case 3:
+	default:
+		return nil, fmt.Errorf("shape index has %d '#' characters, want 2", len(fields)-1)
+	}
+	index := s2.NewShapeIndex()
+	var points s2.PointVector
+	for _, field := range strings.Split(fields[0], "|") {
+		p, err := parsePoints(field)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p...)
+	}
+	if len(points) > 0 {
+		index.Add(&points)
+	}
+	for _, field := range strings.Split(fields[1], "|") {
+		if strings.TrimSpace(field) == "" {
+			continue
+		}
+		p, err := parsePoints(field)
+		if err != nil {
+			return nil, err
+		}
+		line := s2.Polyline(p)
+		index.Add(&line)
+	}
+	for _, field := range strings.Split(fields[2], "|") {
+		if strings.TrimSpace(field) == "" {
+			continue
+		}
+		p, err := parsePolygon(field)
+		if err != nil {
+			return nil, err
+		}
+		index.Add(p)
+	}
+	return index, nil
+}
+
+// formatPoint formats a point as "lat:lng".
+func formatPoint(p s2.Point) string {
+	ll := s2.LatLngFromPoint(p)
+	return fmt.Sprintf("%.15g:%.15g", ll.Lat.Degrees(), ll.Lng.Degrees())
+}
+
+// formatPoints formats a list of points as comma separated "lat:lng" pairs.
+func formatPoints(points []s2.Point) string {
+	parts := make([]string, len(points))
+	for i, p := range points {
+		parts[i] = formatPoint(p)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// isGeoJSON reports whether the input is a GeoJSON object rather than text.
+func isGeoJSON(s string) bool {
+	return strings.HasPrefix(strings.TrimSpace(s), "{")
+}
+
+// decodeGeoJSON decodes a GeoJSON geometry. A Feature is also accepted, in
+// which case its geometry is returned.
+func decodeGeoJSON(s string) (*geojson.Geometry, error) {
+	var object struct {
+		Type     string          `json:"type"`
+		Geometry json.RawMessage `json:"geometry"`
+	}
+	data := []byte(s)
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %v", err)
+	}
+	if object.Type == "Feature" {
+		if len(object.Geometry) == 0 || string(object.Geometry) == "null" {
+			return nil, fmt.Errorf("invalid GeoJSON: feature has no geometry")
+		}
+		data = object.Geometry
+	}
+	g := new(geojson.Geometry)
+	if err := json.Unmarshal(data, g); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %v", err)
+	}
+	return g, nil
+}
+
+// readPolygon reads a polygon in the text format or from a GeoJSON Polygon
+// or MultiPolygon.
+func readPolygon(s string) (*s2.Polygon, error) {
+	if !isGeoJSON(s) {
+		return parsePolygon(s)
+	}
+	g, err := decodeGeoJSON(s)
+	if err != nil {
+		return nil, err
+	}
+	return g.Polygon()
+}
+
+// readShapeIndex reads a shape index in the text format or from any GeoJSON
+// geometry.
+func readShapeIndex(s string) (*s2.ShapeIndex, error) {
+	if !isGeoJSON(s) {
+		return parseShapeIndex(s)
+	}
+	g, err := decodeGeoJSON(s)
+	if err != nil {
+		return nil, err
+	}
+	return g.ShapeIndex()
+}
+
+// readRegion reads a region of the given kind in the text format, which is
+// one of "point", "polyline", "polygon" and "rect". GeoJSON input is read
+// according to its type instead: a Point as a point, a LineString as a
+// polyline and a Polygon or MultiPolygon as a polygon.
+func readRegion(kind, s string) (s2.Region, error) {
+	if isGeoJSON(s) {
+		g, err := decodeGeoJSON(s)
+		if err != nil {
+			return nil, err
+		}
+		switch g.Type {
+		case geojson.TypePoint:
+			p, err := g.Point()
+			return p, err
+		case geojson.TypeLineString, geojson.TypeMultiLineString:
+			return g.Polyline()
+		case geojson.TypePolygon, geojson.TypeMultiPolygon:
+			return g.Polygon()
+		}
+		return nil, fmt.Errorf("cannot use a GeoJSON %s as a region", g.Type)
+	}
+	switch kind {
+	case "point":
+		points, err := parsePoints(s)
+		if err != nil {
+			return nil, err
+		}
+		if len(points) != 1 {
+			return nil, fmt.Errorf("got %d points, want 1", len(points))
+		}
+		return points[0], nil
+	case "polyline":
+		points, err := parsePoints(s)
+		if err != nil {
+			return nil, err
+		}
+		line := s2.Polyline(points)
+		return &line, nil
+	case "polygon":
+		return parsePolygon(s)
+	case "rect":
+		return parseRect(s)
+	}
+	return nil, fmt.Errorf("unknown region kind %q, want point, polyline, polygon or rect", kind)
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/rubenpoppe/geo/s2"
+)
+
+func TestParsePoints(t *testing.T) {
+	tests := []struct {
+		have    string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"-20:150", "-20:150", false},
+		{" -20:150,10:-120 , 0.125:-170.5 ", "-20:150, 10:-120, 0.125:-170.5", false},
+		{"91:0", "", true},
+		{"10:NaN", "", true},
+		{"10", "", true},
+		{"10:20:30", "", true},
+		{"10:20,", "", true},
+	}
+	for _, test := range tests {
+		points, err := parsePoints(test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("parsePoints(%q) error = %v, want error %v", test.have, err, test.wantErr)
+			continue
+		}
+		if got := formatPoints(points); err == nil && got != test.want {
+			t.Errorf("parsePoints(%q) = %q, want %q", test.have, got, test.want)
+		}
+	}
+}
+
+func TestParsePolygon(t *testing.T) {
+	tests := []struct {
+		have      string
+		wantLoops int
+	}{
+		{"empty", 0},
+		{"", 0},
+		{"full", 1},
+		// Clockwise loops are normalized, and a trailing semicolon is allowed.
+		{"0:0, 10:0, 0:10;", 1},
+		{"0:0, 0:10, 10:0; 1:1, 1:2, 2:1", 2},
+	}
+	for _, test := range tests {
+		p, err := parsePolygon(test.have)
+		if err != nil {
+			t.Errorf("parsePolygon(%q) failed: %v", test.have, err)
+			continue
+		}
+		if p.NumLoops() != test.wantLoops {
+			t.Errorf("parsePolygon(%q) has %d loops, want %d", test.have, p.NumLoops(), test.wantLoops)
+		}
+		if err := p.Validate(); err != nil {
+			t.Errorf("parsePolygon(%q) is invalid: %v", test.have, err)
+		}
+		if !p.IsFull() && p.Area() > 2*3.1416 {
+			t.Errorf("parsePolygon(%q).Area() = %v, want at most half the sphere", test.have, p.Area())
+		}
+	}
+	if _, err := parsePolygon("0:0, 0:10, 10:0; 1:x"); err == nil {
+		t.Errorf("parsePolygon with an invalid point succeeded")
+	}
+}
+
+func TestParseShapeIndex(t *testing.T) {
+	index, err := parseShapeIndex("1:1 | 2:2 # 0:0, 0:5 | 5:5, 6:6 # 0:0, 0:1, 1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dims []int
+	for _, shape := range shapes(index) {
+		dims = append(dims, shape.Dimension())
+	}
+	if want := []int{0, 1, 1, 2}; len(dims) != len(want) || dims[0] != 0 || dims[1] != 1 || dims[2] != 1 || dims[3] != 2 {
+		t.Errorf("shape dimensions = %v, want %v", dims, want)
+	}
+	if index, err := parseShapeIndex("1:1, 2:2"); err != nil || index.Len() != 1 || index.Shape(0).NumEdges() != 2 {
+		t.Errorf("parseShapeIndex without '#' = %v, %v, want 2 points", index, err)
+	}
+	if _, err := parseShapeIndex("1:1 # 2:2"); err == nil {
+		t.Errorf("parseShapeIndex with one '#' succeeded")
+	}
+}
+
+func TestReadRegion(t *testing.T) {
+	tests := []struct {
+		kind, have string
+		want       string
+	}{
+		{"point", "10:20", "s2.Point"},
+		{"polyline", "10:20, 11:21", "*s2.Polyline"},
+		{"polygon", "0:0, 0:1, 1:0", "*s2.Polygon"},
+		{"rect", "0:0, 10:20, 5:30", "s2.Rect"},
+		{"polygon", `{"type": "Point", "coordinates": [20, 10]}`, "s2.Point"},
+		{"point", `{"type": "LineString", "coordinates": [[20, 10], [21, 11]]}`, "*s2.Polyline"},
+		{"point", `{"type": "Feature", "properties": {}, "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [0, 1], [0, 0]]]}}`, "*s2.Polygon"},
+	}
+	for _, test := range tests {
+		region, err := readRegion(test.kind, test.have)
+		if err != nil {
+			t.Errorf("readRegion(%q, %q) failed: %v", test.kind, test.have, err)
+			continue
+		}
+		if got := typeName(region); got != test.want {
+			t.Errorf("readRegion(%q, %q) is a %s, want %s", test.kind, test.have, got, test.want)
+		}
+	}
+
+	rect, _ := readRegion("rect", "0:0, 10:20, 5:30")
+	if want := s2.RectFromLatLng(s2.LatLngFromDegrees(0, 0)).AddPoint(s2.LatLngFromDegrees(10, 30)); !rect.(s2.Rect).ApproxEqual(want) {
+		t.Errorf(`readRegion("rect", ...) = %v, want %v`, rect, want)
+	}
+
+	for _, test := range []struct{ kind, have string }{
+		{"point", "10:20, 11:21"},
+		{"rect", "10:20"},
+		{"circle", "10:20"},
+		{"polygon", `{"type": "MultiPoint", "coordinates": [[20, 10]]}`},
+		{"polygon", `{"type": "Feature", "geometry": null}`},
+		{"polygon", `{"type": `},
+	} {
+		if _, err := readRegion(test.kind, test.have); err == nil {
+			t.Errorf("readRegion(%q, %q) succeeded, want an error", test.kind, test.have)
+		}
+	}
+}
+
+// typeName returns the name of the type of the region.
+func typeName(r s2.Region) string {
+	switch r.(type) {
+	case s2.Point:
+		return "s2.Point"
+	case *s2.Polyline:
+		return "*s2.Polyline"
+	case *s2.Polygon:
+		return "*s2.Polygon"
+	case s2.Rect:
+		return "s2.Rect"
+	}
+	return "unknown"
+}
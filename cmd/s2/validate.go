@@ -0,0 +1,115 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// loopProblems returns all the problems with the loops of a polygon, as they
+// were written. Unlike Polygon.Validate, which stops at the first problem, it
+// reports every invalid loop and every pair of crossing edges, and only checks
+// the nesting of the loops if there are no other problems.
+func loopProblems(loops []*s2.Loop) []string {
+	var problems []string
+	for i, l := range loops {
+		switch {
+		case l.IsEmpty():
+			problems = append(problems, fmt.Sprintf("loop %d: empty loops are not allowed", i))
+		case l.IsFull() && len(loops) > 1:
+			problems = append(problems, fmt.Sprintf("loop %d: full loop appears in non-full polygon", i))
+		}
+		if err := l.Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("loop %d: %v", i, err))
+		}
+	}
+	if len(problems) > 0 {
+		// Crossings are only meaningful between well-formed loops.
+		return problems
+	}
+
+	// The loops are added in order, so their shape IDs are their indexes.
+	index := s2.NewShapeIndex()
+	for _, l := range loops {
+		index.Add(l)
+	}
+	s2.VisitCrossingEdgePairs(index, s2.CrossingTypeInterior, func(a, b s2.ShapeEdge, _ bool) bool {
+		if a.ID.ShapeID == b.ID.ShapeID {
+			problems = append(problems, fmt.Sprintf("loop %d: edge %d crosses edge %d", a.ID.ShapeID, a.ID.EdgeID, b.ID.EdgeID))
+		} else {
+			problems = append(problems, fmt.Sprintf("loop %d edge %d crosses loop %d edge %d", a.ID.ShapeID, a.ID.EdgeID, b.ID.ShapeID, b.ID.EdgeID))
+		}
+		return true
+	})
+	if len(problems) > 0 {
+		return problems
+	}
+
+	// The loops are normalized as in parsePolygon, which reorders them.
+	for _, l := range loops {
+		if !l.IsFull() {
+			l.Normalize()
+		}
+	}
+	if err := s2.PolygonFromLoops(loops).Validate(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	return problems
+}
+
+func runValidate(e *env, fs *flag.FlagSet, args []string) error {
+	if err := parseFlags(fs, args, 0, -1); err != nil {
+		return err
+	}
+	s, err := e.input(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	var numLoops, numVertices int
+	if isGeoJSON(s) {
+		// The GeoJSON decoder validates the polygon, and reports the first
+		// problem with the ring and position where it was found.
+		p, err := readPolygon(s)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else {
+			numLoops, numVertices = p.NumLoops(), p.NumEdges()
+		}
+	} else {
+		loops, err := parseLoops(s)
+		if err != nil {
+			return err
+		}
+		problems = loopProblems(loops)
+		numLoops = len(loops)
+		for _, l := range loops {
+			numVertices += l.NumEdges()
+		}
+	}
+
+	if len(problems) == 0 {
+		_, err := fmt.Fprintf(e.stdout, "valid polygon: %d loops, %d vertices\n", numLoops, numVertices)
+		return err
+	}
+	for _, p := range problems {
+		fmt.Fprintln(e.stdout, p)
+	}
+	return errInvalid
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunValidate(t *testing.T) {
+	tests := []struct {
+		have       string
+		wantStatus int
+		want       []string
+	}{
+		{"0:0, 0:10, 10:10, 10:0; 2:2, 2:3, 3:3", 0, []string{"valid polygon: 2 loops, 7 vertices"}},
+		{"full", 0, []string{"valid polygon: 1 loops, 0 vertices"}},
+		{`{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [0, 1], [0, 0]]]}`, 0, []string{"valid polygon: 1 loops, 3 vertices"}},
+		{"0:0, 0:10, 10:10, 10:0; 20:20, 20:20, 21:21", 1, []string{
+			"loop 1: edge 0 is degenerate (duplicate vertex)",
+		}},
+		// Every problem is reported, not just the first.
+		{"0:0, 0:10, 10:0, 10:10; 20:20, 20:21, 21:20, 21:21", 1, []string{
+			"loop 0: edge 1 crosses edge 3",
+			"loop 1: edge 1 crosses edge 3",
+		}},
+		{"0:0, 0:10, 10:10, 10:0; 5:5, 5:15, 15:15, 15:5", 1, []string{
+			"loop 0 edge 1 crosses loop 1 edge 0",
+			"loop 0 edge 2 crosses loop 1 edge 3",
+		}},
+		{"full; 0:0, 0:1, 1:0", 1, []string{"loop 0: full loop appears in non-full polygon"}},
+		{`{"type": "Polygon", "coordinates": [[[0, 0], [1, 1], [1, 0], [0, 1], [0, 0]]]}`, 1, []string{
+			"geojson: ring 0: invalid ring: edge 0 crosses edge 2",
+		}},
+	}
+	for _, test := range tests {
+		stdout, stderr, status := runCommand(test.have, "validate")
+		if status != test.wantStatus {
+			t.Errorf("s2 validate %q exited with %d, want %d: %s", test.have, status, test.wantStatus, stderr)
+		}
+		if got, want := stdout, strings.Join(test.want, "\n")+"\n"; got != want {
+			t.Errorf("s2 validate %q = %q, want %q", test.have, got, want)
+		}
+	}
+	if _, stderr, status := runCommand("0:0, x", "validate"); status != 1 || !strings.Contains(stderr, "invalid point") {
+		t.Errorf("s2 validate with a syntax error exited with %d and wrote %q", status, stderr)
+	}
+}
@@ -15,8 +15,11 @@
 package s1
 
 import (
+	"encoding/json"
+	"fmt"
 	"math"
 	"strconv"
+	"strings"
 )
 
 // Angle represents a 1D angle. The internal representation is a double precision
@@ -116,5 +119,50 @@ func (a Angle) ApproxEqual(other Angle) bool {
 	return math.Abs(float64(a)-float64(other)) <= epsilon
 }
 
+// FormatDegrees returns the angle as a decimal number of degrees with 15
+// significant digits, such as "-12.5", which removes the rounding noise of the
+// conversion from radians. Infinite and NaN angles are written as "+Inf",
+// "-Inf" and "NaN", which ParseDegrees does not accept.
+func FormatDegrees(a Angle) string {
+	return strconv.FormatFloat(a.Degrees(), 'g', 15, 64)
+}
+
+// ParseDegrees returns the angle with the given finite decimal number of
+// degrees, optionally with an exponent, as written by FormatDegrees. Other
+// forms that strconv.ParseFloat accepts, such as "Inf" or hexadecimal numbers,
+// are rejected.
+func ParseDegrees(s string) (Angle, error) {
+	if s == "" || strings.Trim(s, "0123456789+-.eE") != "" {
+		return 0, fmt.Errorf("s1: invalid angle %q", s)
+	}
+	d, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(d, 0) {
+		return 0, fmt.Errorf("s1: invalid angle %q", s)
+	}
+	return Angle(d) * Degree, nil
+}
+
+// MarshalJSON implements json.Marshaler. The angle is written as a JSON
+// number of radians, exactly as encoding/json writes a float64, so that the
+// encoding is exact and the same as that of an Angle without this method.
+// Infinite and NaN angles cannot be marshaled.
+func (a Angle) MarshalJSON() ([]byte, error) {
+	return json.Marshal(float64(a))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a JSON number of
+// radians. A JSON null leaves the angle unchanged.
+func (a *Angle) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var rad float64
+	if err := json.Unmarshal(data, &rad); err != nil {
+		return fmt.Errorf("s1: invalid angle %s", data)
+	}
+	*a = Angle(rad)
+	return nil
+}
+
 // BUG(dsymonds): The major differences from the C++ version are:
 //   - no unsigned E5/E6/E7 methods
@@ -15,7 +15,9 @@
 package s1
 
 import (
+	"encoding/json"
 	"math"
+	"math/rand"
 	"testing"
 )
 
@@ -167,5 +169,103 @@ func TestDegreesVsRadians(t *testing.T) {
 	}
 }
 
+func TestFormatParseDegrees(t *testing.T) {
+	tests := []struct {
+		have Angle
+		want string
+	}{
+		{0, "0"},
+		{45 * Degree, "45"},
+		{60 * Degree, "60"},
+		{-12.5 * Degree, "-12.5"},
+		{1e-7 * Degree, "1e-07"},
+		{E7 * 1234567, "0.1234567"},
+		{Angle(math.Pi), "180"},
+		{1 * Radian, "57.2957795130823"},
+	}
+	for _, test := range tests {
+		got := FormatDegrees(test.have)
+		if got != test.want {
+			t.Errorf("FormatDegrees(%v) = %q, want %q", test.have, got, test.want)
+			continue
+		}
+		back, err := ParseDegrees(got)
+		if err != nil {
+			t.Errorf("ParseDegrees(%q) failed: %v", got, err)
+		}
+		if !float64Near(float64(back), float64(test.have), 1e-15) {
+			t.Errorf("ParseDegrees(%q) = %v, want %v", got, back, test.have)
+		}
+		// The encoding is stable.
+		if again := FormatDegrees(back); again != got {
+			t.Errorf("round trip of %q gives %q", got, again)
+		}
+	}
+
+	for _, have := range []Angle{InfAngle(), -InfAngle(), Angle(math.NaN())} {
+		if text := FormatDegrees(have); text == "" {
+			t.Errorf("FormatDegrees(%v) is empty", have)
+		} else if _, err := ParseDegrees(text); err == nil {
+			t.Errorf("ParseDegrees(%q) succeeded, want an error", text)
+		}
+	}
+}
+
+func TestParseDegreesErrors(t *testing.T) {
+	for _, have := range []string{"", " 1", "1 ", "1°", "Inf", "NaN", "0x1p-2", "1_000", "1e400", "--1", "1.2.3"} {
+		if a, err := ParseDegrees(have); err == nil {
+			t.Errorf("ParseDegrees(%q) = %v, want an error", have, a)
+		}
+	}
+}
+
+func TestAngleJSON(t *testing.T) {
+	type value struct {
+		A Angle  `json:"a"`
+		B *Angle `json:"b"`
+	}
+	// The encoding is exact, and the same as that of a float64 of radians.
+	b := -90 * Degree
+	data, err := json.Marshal(value{45 * Degree, &b})
+	if want := `{"a":0.7853981633974483,"b":-1.5707963267948966}`; err != nil || string(data) != want {
+		t.Errorf("json.Marshal() = %s, %v, want %s", data, err, want)
+	}
+	var got value
+	if err := json.Unmarshal(data, &got); err != nil || got.A != 45*Degree || *got.B != b {
+		t.Errorf("json.Unmarshal(%s) = %+v, %v", data, got, err)
+	}
+	for i := 0; i < 100; i++ {
+		want := Angle(rand.NormFloat64())
+		data, err := json.Marshal(want)
+		var got Angle
+		if err != nil || json.Unmarshal(data, &got) != nil || got != want {
+			t.Errorf("JSON round trip of %v = %v, %v", float64(want), float64(got), err)
+		}
+	}
+
+	got = value{A: 1}
+	if err := json.Unmarshal([]byte(`{"a":null}`), &got); err != nil || got.A != 1 {
+		t.Errorf("json.Unmarshal of null = %v, %v, want the angle unchanged", got.A, err)
+	}
+	for _, have := range []string{`{"a":"45"}`, `{"a":true}`, `{"a":1e999}`} {
+		if err := json.Unmarshal([]byte(have), &got); err == nil {
+			t.Errorf("json.Unmarshal(%s) succeeded, want an error", have)
+		}
+	}
+	if _, err := json.Marshal(InfAngle()); err == nil {
+		t.Errorf("json.Marshal(InfAngle()) succeeded, want an error")
+	}
+
+	// Angles are keys of JSON objects in radians, as for a float64.
+	data, err = json.Marshal(map[Angle]int{0.5: 1})
+	if want := `{"0.5":1}`; err != nil || string(data) != want {
+		t.Errorf("json.Marshal(map) = %s, %v, want %s", data, err, want)
+	}
+	var keys map[Angle]int
+	if err := json.Unmarshal([]byte(`{"0.5":1}`), &keys); err != nil || keys[0.5] != 1 {
+		t.Errorf("json.Unmarshal of a map = %v, %v, want the key 0.5 radians", keys, err)
+	}
+}
+
 // TODO(roberts): Differences from C++
 //   Benchmarking code.
@@ -503,7 +503,7 @@ func (c Cap) encode(e *encoder) {
 
 // Decode decodes the Cap.
 func (c *Cap) Decode(r io.Reader) error {
-	d := &decoder{r: asByteReader(r)}
+	d := newDecoder(r, DecodeOptions{})
 	c.decode(d)
 	return d.err
 }
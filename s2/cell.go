@@ -15,6 +15,7 @@
 package s2
 
 import (
+	"fmt"
 	"io"
 	"math"
 
@@ -406,14 +407,22 @@ func (c Cell) encode(e *encoder) {
 
 // Decode decodes the Cell.
 func (c *Cell) Decode(r io.Reader) error {
-	d := &decoder{r: asByteReader(r)}
+	d := newDecoder(r, DecodeOptions{})
 	c.decode(d)
 	return d.err
 }
 
 func (c *Cell) decode(d *decoder) {
-	c.id.decode(d)
-	*c = CellFromCellID(c.id)
+	var id CellID
+	id.decode(d)
+	if d.err != nil {
+		return
+	}
+	if !id.IsValid() {
+		d.err = fmt.Errorf("%w: %v is not a valid cell", ErrDecodeInvalid, id)
+		return
+	}
+	*c = CellFromCellID(id)
 }
 
 // vertexChordDist2 returns the squared chord distance from point P to the
@@ -483,7 +483,7 @@ func (ci CellID) encode(e *encoder) {
 
 // Decode decodes the CellID.
 func (ci *CellID) Decode(r io.Reader) error {
-	d := &decoder{r: asByteReader(r)}
+	d := newDecoder(r, DecodeOptions{})
 	ci.decode(d)
 	return d.err
 }
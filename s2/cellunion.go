@@ -560,7 +560,7 @@ func (cu *CellUnion) encode(e *encoder) {
 
 // Decode decodes the CellUnion.
 func (cu *CellUnion) Decode(r io.Reader) error {
-	d := &decoder{r: asByteReader(r)}
+	d := newDecoder(r, DecodeOptions{})
 	cu.decode(d)
 	return d.err
 }
@@ -571,7 +571,7 @@ func (cu *CellUnion) decode(d *decoder) {
 		return
 	}
 	if version != encodingVersion {
-		d.err = fmt.Errorf("only version %d is supported", encodingVersion)
+		d.err = fmt.Errorf("%w: only version %d is supported", ErrDecodeVersion, encodingVersion)
 		return
 	}
 	n := d.readInt64()
@@ -579,12 +579,41 @@ func (cu *CellUnion) decode(d *decoder) {
 		return
 	}
 	const maxCells = 1000000
-	if n > maxCells {
-		d.err = fmt.Errorf("too many cells (%d; max is %d)", n, maxCells)
+	if n < 0 {
+		d.err = fmt.Errorf("%w: negative number of cells (%d)", ErrDecodeInvalid, n)
 		return
 	}
-	*cu = make([]CellID, n)
-	for i := range *cu {
-		(*cu)[i].decode(d)
+	if !d.checkCount(uint64(n), maxCells, "cells") {
+		return
+	}
+	ids := make([]CellID, 0, decodeCapacity(uint64(n)))
+	for i := int64(0); i < n && d.err == nil; i++ {
+		var id CellID
+		id.decode(d)
+		ids = append(ids, id)
+	}
+	if d.err != nil {
+		return
+	}
+	*cu = ids
+}
+
+// EncodeCompressed encodes the CellUnion in the format read by
+// EncodedCellIDVector and DecodeCompressed. The cells are encoded in the
+// order they have in the union, which does not need to be normalized,
+// although only sorted vectors can be searched with LowerBound.
+func (cu *CellUnion) EncodeCompressed(w io.Writer) error {
+	e := &encoder{w: w}
+	encodeCellIDVector(e, *cu)
+	return e.err
+}
+
+// DecodeCompressed decodes a CellUnion written by EncodeCompressed.
+func (cu *CellUnion) DecodeCompressed(r io.Reader) error {
+	var v EncodedCellIDVector
+	if err := v.Decode(r); err != nil {
+		return err
 	}
+	*cu = v.CellIDs()
+	return nil
 }
@@ -0,0 +1,1179 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/rubenpoppe/geo/r3"
+	"github.com/rubenpoppe/geo/s1"
+)
+
+// The Delaunay triangulation of a set of points on the sphere is closely
+// related to their three-dimensional convex hull: the circumcircle of a
+// spherical triangle ABC is the intersection of the sphere with the plane
+// through A, B and C, and the circumcircle is empty if and only if no other
+// point lies above that plane. Therefore every face of the convex hull
+// corresponds to an empty circle, and the Delaunay triangles are exactly the
+// hull faces. Similarly the Voronoi vertices are the outward normals of the
+// hull faces, and the Voronoi cell of a site consists of the normals of the
+// faces incident to that site.
+//
+// The hull is computed using an incremental algorithm with conflict lists
+// (Quickhull), where all of the geometric decisions are made using the exact
+// predicate sphereInCircleSign. This computes the hull of the points after
+// projecting them exactly onto the sphere, since otherwise the errors in the
+// lengths of closely spaced points would change which of them are
+// cocircular. Points that are exactly cocircular are treated as not being
+// inside each other's circumcircles, so that sets of cocircular points are
+// triangulated arbitrarily but consistently.
+
+const (
+	// voronoiVertexError is the maximum error in the computed position of a
+	// Voronoi vertex. Vertices whose error could exceed this are computed
+	// using exact arithmetic.
+	voronoiVertexError = 1e-14
+
+	// voronoiMergeAngle is the distance below which adjacent Voronoi
+	// vertices are merged. Such vertices arise when four or more sites are
+	// cocircular, in which case the exact Voronoi vertices coincide but their
+	// computed positions may differ by up to twice voronoiVertexError. It is
+	// kept this small so that it does not distort the cells of closely
+	// spaced sites.
+	voronoiMergeAngle s1.Angle = 4 * voronoiVertexError
+
+	// voronoiClipTolerance is the distance below which adjacent vertices
+	// of a clipped ring are merged.
+	voronoiClipTolerance = 1e-14
+
+	// voronoiClipMaxCells is the maximum number of cells in the covering
+	// used to approximate a clipping region that is not polygonal.
+	voronoiClipMaxCells = 1000
+)
+
+// DelaunayTriangulation represents the Delaunay triangulation of a set of
+// points on the sphere, together with its dual, the spherical Voronoi diagram.
+//
+// Degenerate inputs are handled robustly. Duplicate points are merged into a
+// single vertex (see Vertex), cocircular points are triangulated arbitrarily
+// but consistently, and if all of the points lie on a single circle (such as
+// a great circle) then the Voronoi cells are lunes meeting at the two poles
+// of that circle.
+//
+// Note that if the points do not surround the origin (e.g. they all lie
+// within a single hemisphere) then the triangles only cover the spherical
+// convex hull of the points, since the remainder of the sphere cannot be
+// subdivided into triangles whose vertices are input points. The Voronoi
+// diagram always covers the entire sphere.
+type DelaunayTriangulation struct {
+	// vertices are the distinct input points in order of first appearance.
+	vertices []Point
+
+	// sites maps each input point to the index of its vertex.
+	sites []int
+
+	// faces are the faces of the convex hull of the vertices, oriented CCW
+	// when viewed from outside the hull.
+	faces []hullFace
+
+	// vertexFace[i] is the index of some face that is incident to vertex i.
+	vertexFace []int
+
+	// triangles are the faces that are Delaunay triangles.
+	triangles [][3]int
+
+	// flat is true if all of the vertices lie in a single plane, in which
+	// case axis is the normal of that plane and order lists the vertices in
+	// CCW order around the axis.
+	flat  bool
+	axis  Point
+	order []int
+}
+
+// hullFace represents a triangular face of a convex hull.
+type hullFace struct {
+	// The face vertices in CCW order when viewed from outside the hull.
+	v [3]int
+	// adj[k] is the index of the face across the edge (v[k], v[k+1]).
+	adj [3]int
+}
+
+// NewDelaunayTriangulation computes the Delaunay triangulation of the given
+// points. The points should be unit length; duplicate points are allowed.
+func NewDelaunayTriangulation(points []Point) *DelaunayTriangulation {
+	d := &DelaunayTriangulation{
+		sites: make([]int, len(points)),
+	}
+	index := make(map[Point]int)
+	for i, p := range points {
+		v, ok := index[p]
+		if !ok {
+			v = len(d.vertices)
+			index[p] = v
+			d.vertices = append(d.vertices, p)
+		}
+		d.sites[i] = v
+	}
+
+	if len(d.vertices) < 3 {
+		return d
+	}
+
+	simplex, ok := initialSimplex(d.vertices)
+	if !ok {
+		d.initFlat()
+		return d
+	}
+
+	b := &hullBuilder{points: d.vertices}
+	b.build(simplex)
+	d.faces = b.compact()
+
+	d.vertexFace = make([]int, len(d.vertices))
+	for f, face := range d.faces {
+		for _, v := range face.v {
+			d.vertexFace[v] = f
+		}
+	}
+
+	// A hull face is a Delaunay triangle if the origin is below it, i.e. the
+	// triangle is smaller than a hemisphere.
+	var origin Point
+	for _, face := range d.faces {
+		a, b, c := d.vertices[face.v[0]], d.vertices[face.v[1]], d.vertices[face.v[2]]
+		if inCircleSign(a, b, c, origin) < 0 {
+			d.triangles = append(d.triangles, face.v)
+		}
+	}
+	return d
+}
+
+// initialSimplex returns the indices of four vertices that are not coplanar,
+// and reports whether such vertices exist. The first three vertices are
+// ordered so that the fourth lies below the plane through them.
+func initialSimplex(vertices []Point) ([4]int, bool) {
+	var s [4]int
+	s[1] = 1
+	found := false
+	for i := 2; i < len(vertices); i++ {
+		if !collinear(vertices[0], vertices[1], vertices[i]) {
+			s[2] = i
+			found = true
+			break
+		}
+	}
+	if !found {
+		return s, false
+	}
+	for i := s[2] + 1; i < len(vertices); i++ {
+		// The vertices are treated as coplanar if they are coplanar either
+		// before or after being projected onto the sphere, so that points on
+		// a circle are handled as such even if the errors in their lengths
+		// mean that only one of these holds exactly.
+		a, b, c := vertices[s[0]], vertices[s[1]], vertices[s[2]]
+		sign := sphereInCircleSign(a, b, c, vertices[i])
+		if sign == 0 || inCircleSign(a, b, c, vertices[i]) == 0 {
+			continue
+		}
+		s[3] = i
+		if sign > 0 {
+			s[1], s[2] = s[2], s[1]
+		}
+		return s, true
+	}
+	return s, false
+}
+
+// collinear reports whether the three points lie exactly on a straight line in
+// three-dimensional space.
+func collinear(a, b, c Point) bool {
+	xa := r3.PreciseVectorFromVector(a.Vector)
+	n := r3.PreciseVectorFromVector(b.Vector).Sub(xa).Cross(r3.PreciseVectorFromVector(c.Vector).Sub(xa))
+	return n.X.Sign() == 0 && n.Y.Sign() == 0 && n.Z.Sign() == 0
+}
+
+// initFlat initializes a triangulation whose vertices all lie in one plane.
+func (d *DelaunayTriangulation) initFlat() {
+	a, b, c := d.vertices[0], d.vertices[1], d.vertices[2]
+	for _, v := range d.vertices[2:] {
+		if !collinear(a, b, v) {
+			c = v
+			break
+		}
+	}
+	n := b.Sub(a.Vector).Cross(c.Sub(a.Vector))
+	if n.Norm2() == 0 {
+		n = a.PointCross(b).Vector
+	}
+	if n.Dot(a.Vector) < 0 {
+		n = n.Mul(-1)
+	}
+	d.flat = true
+	d.axis = Point{n.Normalize()}
+
+	frame := getFrame(d.axis)
+	angles := make([]float64, len(d.vertices))
+	d.order = make([]int, len(d.vertices))
+	for i, v := range d.vertices {
+		p := toFrame(frame, v)
+		angles[i] = math.Atan2(p.Y, p.X)
+		d.order[i] = i
+	}
+	sort.SliceStable(d.order, func(i, j int) bool {
+		return angles[d.order[i]] < angles[d.order[j]]
+	})
+
+	// Unless the vertices lie on a great circle, they can be triangulated as
+	// a fan that covers the smaller of the two caps bounded by their circle.
+	var origin Point
+	o := d.order
+	if inCircleSign(d.vertices[o[0]], d.vertices[o[1]], d.vertices[o[2]], origin) == 0 {
+		return
+	}
+	for i := 1; i+1 < len(o); i++ {
+		d.triangles = append(d.triangles, [3]int{o[0], o[i], o[i+1]})
+	}
+}
+
+// NumVertices returns the number of distinct input points.
+func (d *DelaunayTriangulation) NumVertices() int {
+	return len(d.vertices)
+}
+
+// Vertex returns the vertex with the given index. Vertices are numbered in
+// order of their first appearance in the input.
+func (d *DelaunayTriangulation) Vertex(i int) Point {
+	return d.vertices[i]
+}
+
+// Site returns the index of the vertex that corresponds to the given input
+// point. Duplicate input points share a single vertex.
+func (d *DelaunayTriangulation) Site(i int) int {
+	return d.sites[i]
+}
+
+// Triangles returns the Delaunay triangles as triples of vertex indices.
+// The vertices of each triangle are in CCW order, and the circumcircle of
+// each triangle does not contain any vertex in its interior.
+func (d *DelaunayTriangulation) Triangles() [][3]int {
+	return d.triangles
+}
+
+// VoronoiCell returns the Voronoi cell of the given vertex, i.e. the set of
+// points that are at least as close to this vertex as to any other vertex.
+// The result is a convex loop, except when there are fewer than three
+// vertices (in which case the cell is either the full loop or a hemisphere)
+// or all of the vertices lie on a single circle (in which case the cell is a
+// lune between two great circles through the poles of that circle). A
+// triangulation without vertices has no cells, so its cells are all empty.
+func (d *DelaunayTriangulation) VoronoiCell(i int) *Loop {
+	switch {
+	case len(d.vertices) == 0:
+		return EmptyLoop()
+	case len(d.vertices) == 1:
+		return FullLoop()
+	case len(d.vertices) == 2:
+		// The cell is the hemisphere closer to this vertex.
+		other := d.vertices[1-i]
+		center := Point{d.vertices[i].Sub(other.Vector).Normalize()}
+		return RegularLoop(center, math.Pi/2, 4)
+	case d.flat:
+		return d.flatVoronoiCell(i)
+	}
+
+	// Visit the faces around the vertex in CCW order. The circumcenters of
+	// these faces are the vertices of the cell, also in CCW order.
+	var vertices []Point
+	start := d.vertexFace[i]
+	f := start
+	for n := 0; n < len(d.faces); n++ {
+		face := &d.faces[f]
+		k := 0
+		for face.v[k] != i {
+			k++
+		}
+		vertices = append(vertices, d.circumcenter(f))
+		if f = face.adj[(k+2)%3]; f == start {
+			break
+		}
+	}
+	return LoopFromPoints(mergeVoronoiVertices(vertices))
+}
+
+// VoronoiCells returns the Voronoi cells of all of the vertices.
+func (d *DelaunayTriangulation) VoronoiCells() []*Loop {
+	cells := make([]*Loop, len(d.vertices))
+	for i := range cells {
+		cells[i] = d.VoronoiCell(i)
+	}
+	return cells
+}
+
+// ClippedVoronoiCell returns the intersection of the Voronoi cell of the given
+// vertex with the given region.
+//
+// Polygons, loops, cells and cell unions are clipped exactly. Any other
+// region (such as a Cap or a Rect) is first approximated by the border of a
+// covering with at most voronoiClipMaxCells cells, so the result may extend
+// slightly beyond such a region.
+func (d *DelaunayTriangulation) ClippedVoronoiCell(i int, clip Region) *Polygon {
+	return clipPolygonToLoop(regionPolygon(clip), d.VoronoiCell(i))
+}
+
+// ClippedVoronoiCells returns the intersections of the Voronoi cells of all
+// of the vertices with the given region, which is handled as described in
+// ClippedVoronoiCell. Together the results partition the region.
+func (d *DelaunayTriangulation) ClippedVoronoiCells(clip Region) []*Polygon {
+	p := regionPolygon(clip)
+	cells := make([]*Polygon, len(d.vertices))
+	for i := range cells {
+		cells[i] = clipPolygonToLoop(p, d.VoronoiCell(i))
+	}
+	return cells
+}
+
+// regionPolygon returns a polygon that represents the given region, which
+// is exact for polygons, loops, cells and cell unions and otherwise the
+// border of a covering of the region.
+func regionPolygon(r Region) *Polygon {
+	switch r := r.(type) {
+	case *Polygon:
+		return r
+	case *Loop:
+		return PolygonFromLoops([]*Loop{LoopFromPoints(append([]Point(nil), r.Vertices()...))})
+	case Cell:
+		return PolygonFromCell(r)
+	case *CellUnion:
+		return PolygonFromCellUnionBorder(*r)
+	}
+	rc := &RegionCoverer{MaxLevel: maxLevel, LevelMod: 1, MaxCells: voronoiClipMaxCells}
+	return PolygonFromCellUnionBorder(rc.Covering(r))
+}
+
+// circumcenter returns the circumcenter of the given face, i.e. the point
+// on the outer side of the face that is equidistant from its vertices.
+func (d *DelaunayTriangulation) circumcenter(f int) Point {
+	v := d.faces[f].v
+	a, b, c := d.vertices[v[0]], d.vertices[v[1]], d.vertices[v[2]]
+	// The circumcenter is the intersection of the perpendicular bisectors of
+	// AB and AC, which we choose to be the two shortest edges by rotating the
+	// vertices so that the longest edge is BC. The angle between the
+	// bisectors is then the largest angle of the triangle, which is only
+	// close to 180 degrees for degenerate triangles.
+	ab := a.Sub(b.Vector).Norm2()
+	bc := b.Sub(c.Vector).Norm2()
+	ca := c.Sub(a.Vector).Norm2()
+	if ab >= bc && ab >= ca {
+		a, b, c = c, a, b
+	} else if ca >= bc {
+		a, b, c = b, c, a
+	}
+	mab, mac := bisectorNormal(a, b), bisectorNormal(a, c)
+	z := mab.Cross(mac)
+
+	// The bisector normals have an error of a few dblEpsilon, so the error in
+	// the direction of z is inversely proportional to its length.
+	if 16*dblEpsilon > voronoiVertexError*z.Norm() {
+		pab, pac := exactBisectorNormal(a, b), exactBisectorNormal(a, c)
+		z = pab.Cross(pac).Vector()
+	}
+	if b.Sub(a.Vector).Cross(c.Sub(a.Vector)).Dot(z) < 0 {
+		z = z.Mul(-1)
+	}
+	return Point{z.Normalize()}
+}
+
+// bisectorNormal returns the unit normal of the perpendicular bisector of AB.
+//
+// The bisector is the great circle through the midpoint A+B that is
+// perpendicular to the plane of A and B. Computing its normal as A-B instead
+// would make it sensitive to the errors in the lengths of A and B, which tilt
+// that plane by an angle of about dblEpsilon divided by the edge length. The
+// plane normal A×B is computed as (A-B)×(A+B), which is more accurate for
+// closely spaced points.
+func bisectorNormal(a, b Point) r3.Vector {
+	sum := a.Add(b.Vector)
+	return a.Sub(b.Vector).Cross(sum).Normalize().Cross(sum).Normalize()
+}
+
+// exactBisectorNormal returns a (non-unit) normal of the perpendicular
+// bisector of AB, computed exactly as in bisectorNormal.
+func exactBisectorNormal(a, b Point) r3.PreciseVector {
+	pa, pb := r3.PreciseVectorFromVector(a.Vector), r3.PreciseVectorFromVector(b.Vector)
+	sum := pa.Add(pb)
+	return pa.Sub(pb).Cross(sum).Cross(sum)
+}
+
+// flatVoronoiCell returns the Voronoi cell of a vertex when all of the
+// vertices are coplanar. Every bisector then contains the plane normal, so
+// the cell is a lune between the bisectors with the two adjacent vertices.
+func (d *DelaunayTriangulation) flatVoronoiCell(i int) *Loop {
+	frame := getFrame(d.axis)
+	angle := func(v int) float64 {
+		p := toFrame(frame, d.vertices[v])
+		return math.Atan2(p.Y, p.X)
+	}
+	pos := 0
+	for d.order[pos] != i {
+		pos++
+	}
+	n := len(d.order)
+	theta := angle(i)
+	prev := angle(d.order[(pos+n-1)%n])
+	if prev >= theta {
+		prev -= 2 * math.Pi
+	}
+	next := angle(d.order[(pos+1)%n])
+	if next <= theta {
+		next += 2 * math.Pi
+	}
+	pointAt := func(a float64) Point {
+		return Point{fromFrame(frame, PointFromCoords(math.Cos(a), math.Sin(a), 0)).Normalize()}
+	}
+	return LoopFromPoints([]Point{
+		d.axis,
+		pointAt(0.5 * (prev + theta)),
+		{d.axis.Mul(-1)},
+		pointAt(0.5 * (theta + next)),
+	})
+}
+
+// mergeVoronoiVertices removes vertices that are within voronoiMergeAngle
+// of the previous vertex, unless that would leave fewer than three vertices.
+func mergeVoronoiVertices(vertices []Point) []Point {
+	merged := make([]Point, 0, len(vertices))
+	for _, v := range vertices {
+		if len(merged) == 0 || merged[len(merged)-1].Distance(v) > voronoiMergeAngle {
+			merged = append(merged, v)
+		}
+	}
+	for len(merged) > 1 && merged[len(merged)-1].Distance(merged[0]) <= voronoiMergeAngle {
+		merged = merged[:len(merged)-1]
+	}
+	if len(merged) < 3 {
+		return vertices
+	}
+	return merged
+}
+
+// hullBuilder computes the convex hull of a set of points.
+type hullBuilder struct {
+	points []Point
+	faces  []hullFace
+	alive  []bool
+
+	// outside[f] is the conflict list of face f, i.e. the points that have
+	// not been added yet and that lie strictly above face f. Every such point
+	// is assigned to exactly one face.
+	outside [][]int
+
+	// inside are the points that were found to lie inside the hull. Since
+	// the points are projected onto the sphere, this only happens for points
+	// that are exactly cocircular with other points, or that have the same
+	// direction as another point.
+	inside []int
+
+	// mark is used to track the visibility of faces while adding a point.
+	mark  []int
+	stamp int
+}
+
+// build computes the hull starting from the given simplex.
+func (b *hullBuilder) build(s [4]int) {
+	f0 := b.addFace(s[0], s[1], s[2])
+	f1 := b.addFace(s[0], s[3], s[1])
+	f2 := b.addFace(s[1], s[3], s[2])
+	f3 := b.addFace(s[2], s[3], s[0])
+	b.faces[f0].adj = [3]int{f1, f2, f3}
+	b.faces[f1].adj = [3]int{f3, f2, f0}
+	b.faces[f2].adj = [3]int{f1, f3, f0}
+	b.faces[f3].adj = [3]int{f2, f1, f0}
+
+	initial := []int{f0, f1, f2, f3}
+	for p := range b.points {
+		if p == s[0] || p == s[1] || p == s[2] || p == s[3] {
+			continue
+		}
+		if !b.assign(p, initial) {
+			b.inside = append(b.inside, p)
+		}
+	}
+
+	// New faces are appended as points are added, so this loop also
+	// processes all of the faces created along the way.
+	for f := 0; f < len(b.faces); f++ {
+		for b.alive[f] && len(b.outside[f]) > 0 {
+			b.addPoint(f, b.furthest(f))
+		}
+	}
+
+	// Points that are inside the hull only due to rounding errors are added
+	// by splitting the face that contains them, so that every distinct point
+	// is a vertex of the result. Edges are then flipped to restore the
+	// Delaunay property as far as possible.
+	for _, p := range b.inside {
+		b.flipEdges(b.splitFace(b.locate(p), p), p)
+	}
+}
+
+// locate returns the face that contains the point p when projected onto the
+// sphere, or if there is no such face, the face that p is closest to lying
+// above.
+func (b *hullBuilder) locate(p int) int {
+	best, bestHeight := -1, math.Inf(-1)
+	for f := range b.faces {
+		if !b.alive[f] {
+			continue
+		}
+		v := b.faces[f].v
+		x, y, z, q := b.points[v[0]], b.points[v[1]], b.points[v[2]], b.points[p]
+		if RobustSign(x, y, q) == CounterClockwise && RobustSign(y, z, q) == CounterClockwise && RobustSign(z, x, q) == CounterClockwise {
+			return f
+		}
+		if h := b.height(f, p); h > bestHeight {
+			best, bestHeight = f, h
+		}
+	}
+	return best
+}
+
+// addFace adds a new face with the given vertices and returns its index.
+func (b *hullBuilder) addFace(v0, v1, v2 int) int {
+	b.faces = append(b.faces, hullFace{v: [3]int{v0, v1, v2}, adj: [3]int{-1, -1, -1}})
+	b.alive = append(b.alive, true)
+	b.outside = append(b.outside, nil)
+	b.mark = append(b.mark, 0)
+	return len(b.faces) - 1
+}
+
+// isAbove reports whether point p lies strictly above face f.
+func (b *hullBuilder) isAbove(f, p int) bool {
+	v := b.faces[f].v
+	return sphereInCircleSign(b.points[v[0]], b.points[v[1]], b.points[v[2]], b.points[p]) > 0
+}
+
+// height returns an approximation of the (scaled) height of point p above
+// face f. It is only used to choose among points and faces heuristically.
+func (b *hullBuilder) height(f, p int) float64 {
+	v := b.faces[f].v
+	a := b.points[v[0]]
+	return b.points[v[1]].Sub(a.Vector).Cross(b.points[v[2]].Sub(a.Vector)).Dot(b.points[p].Sub(a.Vector))
+}
+
+// assign adds the point to the conflict list of the first of the given faces
+// that it lies above, and reports whether there was such a face.
+func (b *hullBuilder) assign(p int, faces []int) bool {
+	for _, f := range faces {
+		if b.isAbove(f, p) {
+			b.outside[f] = append(b.outside[f], p)
+			return true
+		}
+	}
+	return false
+}
+
+// furthest returns the point in the conflict list of face f that is furthest
+// above it.
+func (b *hullBuilder) furthest(f int) int {
+	best, bestHeight := -1, math.Inf(-1)
+	for _, p := range b.outside[f] {
+		if h := b.height(f, p); h > bestHeight {
+			best, bestHeight = p, h
+		}
+	}
+	return best
+}
+
+// addPoint adds the point p, which lies above the face seed, to the hull.
+func (b *hullBuilder) addPoint(seed, p int) {
+	// Find the connected set of faces that p lies above, and the horizon
+	// edges that separate them from the remaining faces.
+	b.stamp++
+	visible := []int{seed}
+	b.mark[seed] = b.stamp
+	type horizonEdge struct{ face, k int }
+	var horizon []horizonEdge
+	for i := 0; i < len(visible); i++ {
+		f := visible[i]
+		for k, g := range b.faces[f].adj {
+			switch b.mark[g] {
+			case b.stamp:
+				continue
+			case -b.stamp:
+			default:
+				if b.isAbove(g, p) {
+					b.mark[g] = b.stamp
+					visible = append(visible, g)
+					continue
+				}
+				b.mark[g] = -b.stamp
+			}
+			horizon = append(horizon, horizonEdge{f, k})
+		}
+	}
+
+	// Connect each horizon edge to the new point.
+	var newFaces []int
+	startsAt := make(map[int]int, len(horizon))
+	endsAt := make(map[int]int, len(horizon))
+	for _, h := range horizon {
+		face := b.faces[h.face]
+		u, w, g := face.v[h.k], face.v[(h.k+1)%3], face.adj[h.k]
+		nf := b.addFace(u, w, p)
+		b.faces[nf].adj[0] = g
+		b.replaceAdjacent(g, w, u, nf)
+		startsAt[u] = nf
+		endsAt[w] = nf
+		newFaces = append(newFaces, nf)
+	}
+	for _, nf := range newFaces {
+		face := &b.faces[nf]
+		face.adj[1] = startsAt[face.v[1]]
+		face.adj[2] = endsAt[face.v[0]]
+	}
+
+	// Remove the visible faces and reassign their conflict points. Vertices
+	// of the visible faces that are not on the horizon are now inside the
+	// hull, which can only happen due to rounding errors.
+	removed := make(map[int]bool)
+	for _, f := range visible {
+		b.alive[f] = false
+		for _, v := range b.faces[f].v {
+			if _, ok := startsAt[v]; !ok && !removed[v] {
+				removed[v] = true
+				b.inside = append(b.inside, v)
+			}
+		}
+		for _, q := range b.outside[f] {
+			if q != p && !b.assign(q, newFaces) {
+				b.inside = append(b.inside, q)
+			}
+		}
+		b.outside[f] = nil
+	}
+}
+
+// splitFace replaces face f with three faces that share the point p, and
+// returns the new faces.
+func (b *hullBuilder) splitFace(f, p int) []int {
+	face := b.faces[f]
+	a, c, e := face.v[0], face.v[1], face.v[2]
+	f0 := b.addFace(a, c, p)
+	f1 := b.addFace(c, e, p)
+	f2 := b.addFace(e, a, p)
+	b.faces[f0].adj = [3]int{face.adj[0], f1, f2}
+	b.faces[f1].adj = [3]int{face.adj[1], f2, f0}
+	b.faces[f2].adj = [3]int{face.adj[2], f0, f1}
+	b.replaceAdjacent(face.adj[0], c, a, f0)
+	b.replaceAdjacent(face.adj[1], e, c, f1)
+	b.replaceAdjacent(face.adj[2], a, e, f2)
+	b.alive[f] = false
+	return []int{f0, f1, f2}
+}
+
+// flipEdges performs Lawson flips on the edges opposite the point p, starting
+// with the given faces (whose last vertex must be p). An edge is flipped if
+// the vertex on its other side lies above the face containing p and the two
+// edges cross on the sphere. Every flip adds an edge incident to p, and
+// edges incident to p are never flipped, so this terminates.
+func (b *hullBuilder) flipEdges(stack []int, p int) {
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !b.alive[f] {
+			continue
+		}
+		u, w, g := b.faces[f].v[0], b.faces[f].v[1], b.faces[f].adj[0]
+		j := 0
+		for b.faces[g].v[j] != w {
+			j++
+		}
+		q := b.faces[g].v[(j+2)%3]
+		if !b.isAbove(f, q) || CrossingSign(b.points[u], b.points[w], b.points[p], b.points[q]) != Cross {
+			continue
+		}
+
+		// Replace the faces (u, w, p) and (w, u, q) with (u, q, p) and
+		// (q, w, p).
+		a1, a2 := b.faces[f].adj[1], b.faces[f].adj[2]
+		b1, b2 := b.faces[g].adj[(j+1)%3], b.faces[g].adj[(j+2)%3]
+		b.faces[f] = hullFace{v: [3]int{u, q, p}, adj: [3]int{b1, g, a2}}
+		b.faces[g] = hullFace{v: [3]int{q, w, p}, adj: [3]int{b2, a1, f}}
+		b.replaceAdjacent(b1, q, u, f)
+		b.replaceAdjacent(a1, p, w, g)
+		stack = append(stack, f, g)
+	}
+}
+
+// replaceAdjacent sets the face across the edge (u, w) of face g to nf.
+func (b *hullBuilder) replaceAdjacent(g, u, w, nf int) {
+	face := &b.faces[g]
+	for k := 0; k < 3; k++ {
+		if face.v[k] == u && face.v[(k+1)%3] == w {
+			face.adj[k] = nf
+			return
+		}
+	}
+}
+
+// compact returns the faces of the hull, renumbered so that they are
+// contiguous.
+func (b *hullBuilder) compact() []hullFace {
+	ids := make([]int, len(b.faces))
+	var faces []hullFace
+	for f, face := range b.faces {
+		if b.alive[f] {
+			ids[f] = len(faces)
+			faces = append(faces, face)
+		}
+	}
+	for i := range faces {
+		for k := range faces[i].adj {
+			faces[i].adj[k] = ids[faces[i].adj[k]]
+		}
+	}
+	return faces
+}
+
+// clipPolygonToLoop returns the intersection of the polygon with the region
+// bounded by the given loop, which must be convex (or full, or a lune).
+func clipPolygonToLoop(p *Polygon, l *Loop) *Polygon {
+	if p.IsEmpty() || (!l.IsFull() && !l.RectBound().Intersects(p.RectBound())) {
+		return PolygonFromLoops(nil)
+	}
+	if p.IsFull() {
+		if l.IsFull() {
+			return FullPolygon()
+		}
+		return PolygonFromLoops([]*Loop{l})
+	}
+	rings := polygonRings(p)
+	if l.IsFull() {
+		return polygonFromRings(rings)
+	}
+
+	// The loop is the intersection of the hemispheres to the left of its
+	// edges, so we clip the polygon to each of these hemispheres in turn.
+	// The normal of each hemisphere is computed exactly, so that the
+	// endpoints of the edge (and their antipodes) lie exactly on its
+	// boundary.
+	c := &hemisphereClipper{rings: rings, contains: p.ContainsPoint}
+	var normals []r3.Vector
+	for i := 0; i < l.NumVertices(); i++ {
+		a, b := l.Vertex(i), l.Vertex(i+1)
+		n := a.PointCross(b).Normalize()
+		duplicate := false
+		for _, m := range normals {
+			if float64(m.Angle(n)) <= voronoiClipTolerance {
+				duplicate = true
+			}
+		}
+		if duplicate {
+			continue
+		}
+		m := r3.PreciseVectorFromVector(a.Vector).Cross(r3.PreciseVectorFromVector(b.Vector))
+		c.clip(m, []Point{b, {b.Mul(-1)}, a, {a.Mul(-1)}})
+		normals = append(normals, n)
+	}
+	return polygonFromRings(c.rings)
+}
+
+// polygonRings returns the vertices of the polygon's loops, oriented so
+// that the polygon interior is on the left. Empty and full loops are
+// omitted.
+func polygonRings(p *Polygon) [][]Point {
+	var rings [][]Point
+	for _, l := range p.Loops() {
+		if l.isEmptyOrFull() {
+			continue
+		}
+		ring := append([]Point(nil), l.Vertices()...)
+		if l.IsHole() {
+			for i, j := 0, len(ring)-1; i < j; i, j = i+1, j-1 {
+				ring[i], ring[j] = ring[j], ring[i]
+			}
+		}
+		rings = append(rings, ring)
+	}
+	return rings
+}
+
+// polygonFromRings returns a polygon whose interior is to the left of the
+// given rings. Duplicate adjacent vertices are removed, and rings with
+// fewer than three vertices are discarded.
+func polygonFromRings(rings [][]Point) *Polygon {
+	var loops []*Loop
+	for _, ring := range rings {
+		var vertices []Point
+		for _, v := range ring {
+			if len(vertices) == 0 || !vertices[len(vertices)-1].approxEqual(v, voronoiClipTolerance) {
+				vertices = append(vertices, v)
+			}
+		}
+		for len(vertices) > 1 && vertices[len(vertices)-1].approxEqual(vertices[0], voronoiClipTolerance) {
+			vertices = vertices[:len(vertices)-1]
+		}
+		if len(vertices) >= 3 {
+			loops = append(loops, LoopFromPoints(vertices))
+		}
+	}
+	if len(loops) == 0 {
+		return PolygonFromLoops(nil)
+	}
+	return PolygonFromOrientedLoops(loops)
+}
+
+// withoutRepeatedVertices returns the ring with adjacent repeated vertices
+// removed.
+func withoutRepeatedVertices(ring []Point) []Point {
+	var vertices []Point
+	for _, v := range ring {
+		if len(vertices) == 0 || vertices[len(vertices)-1] != v {
+			vertices = append(vertices, v)
+		}
+	}
+	for len(vertices) > 1 && vertices[len(vertices)-1] == vertices[0] {
+		vertices = vertices[:len(vertices)-1]
+	}
+	return vertices
+}
+
+// hemisphereClipper clips the region to the left of a set of rings to a
+// sequence of hemispheres. All decisions about the topology of the result
+// are made using exact arithmetic; only the positions of the new vertices
+// are subject to rounding errors.
+//
+// Rings are split into pieces by classifying their edges. An edge that lies
+// exactly on the boundary of a hemisphere is inside it if the region is on
+// the side of the hemisphere, i.e. if the edge goes CCW around its normal,
+// and an edge that only touches the boundary at a vertex is classified by
+// its other vertex. Where a piece starts or ends at a vertex on the boundary,
+// the vertex is conceptually perturbed by an infinitesimal amount into the
+// hemisphere, which determines the order of the pieces that meet there.
+type hemisphereClipper struct {
+	// rings are the rings of the region clipped so far, with the region on
+	// the left.
+	rings [][]Point
+
+	// contains reports whether the region contains a point before any
+	// clipping is done.
+	contains func(Point) bool
+
+	// planes are the normals of the hemispheres clipped so far.
+	planes []r3.PreciseVector
+}
+
+// regionContains reports whether the region clipped so far contains the
+// given point.
+func (c *hemisphereClipper) regionContains(x Point) bool {
+	px := r3.PreciseVectorFromVector(x.Vector)
+	for _, m := range c.planes {
+		if m.Dot(px).Sign() < 0 {
+			return false
+		}
+	}
+	return c.contains(x)
+}
+
+// boundaryDistance returns the distance from the given point to the nearest
+// ring edge or boundary of a hemisphere clipped so far.
+func (c *hemisphereClipper) boundaryDistance(x Point) s1.Angle {
+	dist := s1.InfAngle()
+	for _, m := range c.planes {
+		if d := s1.Angle(math.Abs(math.Asin(m.Vector().Dot(x.Vector)))); d < dist {
+			dist = d
+		}
+	}
+	for _, ring := range c.rings {
+		for i, a := range ring {
+			if d := DistanceFromSegment(x, a, ring[(i+1)%len(ring)]); d < dist {
+				dist = d
+			}
+		}
+	}
+	return dist
+}
+
+// boundaryPoint is a point where a ring enters or leaves a hemisphere.
+type boundaryPoint struct {
+	// x is a vector in the direction of the point that lies exactly on the
+	// boundary of the hemisphere, and p is the point itself.
+	x r3.PreciseVector
+	p Point
+
+	// vertex reports whether p is a ring vertex on the boundary, in which
+	// case outside is the adjacent ring vertex outside the hemisphere.
+	vertex  bool
+	outside Point
+
+	piece int
+	entry bool
+
+	// half is 0 or 1 according to whether the point is in the first or
+	// second half of the boundary, measured from a reference point, and
+	// angle is its approximate angle from the reference point.
+	half  int
+	angle float64
+}
+
+// crossingBoundaryPoint returns the point where the edge between a vertex
+// inside the hemisphere with normal m and a vertex outside it crosses the
+// boundary of the hemisphere.
+func crossingBoundaryPoint(m r3.PreciseVector, in, out Point) boundaryPoint {
+	xi, xo := r3.PreciseVectorFromVector(in.Vector), r3.PreciseVectorFromVector(out.Vector)
+	di := m.Dot(xi)
+	if di.Sign() == 0 {
+		return boundaryPoint{x: xi, p: in, vertex: true, outside: out}
+	}
+	do := m.Dot(xo)
+	// This is a positive combination of the two vertices whose dot product
+	// with m is exactly zero.
+	x := xi.Mul(new(big.Float).Abs(do)).Add(xo.Mul(di))
+	return boundaryPoint{x: x, p: Point{x.Vector().Normalize()}}
+}
+
+// clip clips the region to the hemisphere {x : m.x >= 0}. The given probes
+// are points that lie exactly on the boundary of the hemisphere, which are
+// used (together with other points on the boundary) to decide whether the
+// boundary of the hemisphere is inside the region when no ring crosses it.
+func (c *hemisphereClipper) clip(m r3.PreciseVector, probes []Point) {
+	side := func(x Point) int { return m.Dot(r3.PreciseVectorFromVector(x.Vector)).Sign() }
+
+	// Split the rings into pieces that lie within the hemisphere. Each
+	// piece starts where its ring enters the hemisphere and ends where it
+	// leaves it.
+	var result, pieces [][]Point
+	var ends []boundaryPoint
+	for _, ring := range c.rings {
+		if ring = withoutRepeatedVertices(ring); len(ring) < 3 {
+			continue
+		}
+		sides := make([]int, len(ring))
+		for i, v := range ring {
+			sides[i] = side(v)
+		}
+		// Classify each edge, and find an edge that starts a piece, i.e.
+		// that is at least partly inside while the previous edge is not.
+		inside := make([]bool, len(ring))
+		numInside, numCrossing, start := 0, 0, -1
+		for i := range ring {
+			j := (i + 1) % len(ring)
+			switch {
+			case sides[i] == 0 && sides[j] == 0:
+				// The edge lies on the boundary, and the region is to its
+				// left, so it is inside the hemisphere if it goes CCW.
+				pa, pb := r3.PreciseVectorFromVector(ring[i].Vector), r3.PreciseVectorFromVector(ring[j].Vector)
+				inside[i] = pa.Cross(pb).Dot(m).Sign() > 0
+			case sides[i] < 0 && sides[j] > 0, sides[i] > 0 && sides[j] < 0:
+				numCrossing++
+			default:
+				inside[i] = sides[i] >= 0 && sides[j] >= 0
+			}
+			if inside[i] {
+				numInside++
+			}
+		}
+		if numInside == len(ring) {
+			result = append(result, ring)
+			continue
+		}
+		if numInside == 0 && numCrossing == 0 {
+			continue
+		}
+		for i := range ring {
+			h := (i + len(ring) - 1) % len(ring)
+			if (inside[i] || sides[i] < 0 && sides[(i+1)%len(ring)] > 0) && !inside[h] && !(sides[h] < 0 && sides[i] > 0) {
+				start = i
+				break
+			}
+		}
+
+		// Each piece starts either where an edge crosses into the
+		// hemisphere, or at a boundary vertex where an inside edge follows
+		// an outside one, and similarly for where it ends.
+		var piece []Point
+		var entry boundaryPoint
+		for k := 0; k < len(ring); k++ {
+			i, j := (start+k)%len(ring), (start+k+1)%len(ring)
+			a, b := ring[i], ring[j]
+			var exit boundaryPoint
+			switch {
+			case sides[i] < 0 && sides[j] > 0:
+				entry = crossingBoundaryPoint(m, b, a)
+				entry.entry = true
+				piece = []Point{entry.p, b}
+				continue
+			case inside[i]:
+				if piece == nil {
+					entry = crossingBoundaryPoint(m, a, ring[(i+len(ring)-1)%len(ring)])
+					entry.entry = true
+					piece = []Point{a}
+				}
+				piece = append(piece, b)
+				continue
+			case sides[i] > 0 && sides[j] < 0:
+				exit = crossingBoundaryPoint(m, a, b)
+				piece = append(piece, exit.p)
+			case piece != nil:
+				exit = crossingBoundaryPoint(m, a, b)
+			default:
+				continue
+			}
+			entry.piece, exit.piece = len(pieces), len(pieces)
+			ends = append(ends, entry, exit)
+			pieces = append(pieces, piece)
+			piece = nil
+		}
+	}
+
+	if len(pieces) == 0 {
+		// The boundary of the hemisphere is either entirely inside or
+		// entirely outside the region, which is decided by testing a point
+		// on it. The given probes are supplemented by points spaced around
+		// the boundary, and the one furthest from the boundary of the region
+		// is used, since the region may touch the boundary of the hemisphere
+		// (and the probes) without crossing it.
+		frame := getFrame(Point{m.Vector()})
+		candidates := append([]Point(nil), probes...)
+		for k := 0; k < 16; k++ {
+			a := (float64(k) + 0.5) * math.Pi / 8
+			candidates = append(candidates, Point{fromFrame(frame, PointFromCoords(math.Cos(a), math.Sin(a), 0)).Normalize()})
+		}
+		probe, best := candidates[0], s1.Angle(-1)
+		for _, x := range candidates {
+			if d := c.boundaryDistance(x); d > best {
+				probe, best = x, d
+			}
+		}
+		if c.regionContains(probe) {
+			var ring []Point
+			for k := 0; k < 4; k++ {
+				a := float64(k) * math.Pi / 2
+				ring = append(ring, Point{fromFrame(frame, PointFromCoords(math.Cos(a), math.Sin(a), 0)).Normalize()})
+			}
+			result = append(result, ring)
+		}
+		c.rings, c.planes = result, append(c.planes, m)
+		return
+	}
+
+	// Sort the endpoints of the pieces in CCW order around m, starting from
+	// one of them. Each piece is connected to the piece whose entry point
+	// follows its exit point, since the hemisphere is on the left of its
+	// boundary when traversed CCW.
+	ref := ends[0].x
+	refPerp := m.Cross(ref)
+	frame := getFrame(Point{m.Vector().Normalize()})
+	angleOf := func(x Point) float64 {
+		q := toFrame(frame, x)
+		return math.Atan2(q.Y, q.X)
+	}
+	base := angleOf(ends[0].p)
+	for i := range ends {
+		e := &ends[i]
+		if y := e.x.Dot(refPerp).Sign(); y < 0 || (y == 0 && e.x.Dot(ref).Sign() < 0) {
+			e.half = 1
+		}
+		e.angle = math.Mod(angleOf(e.p)-base+4*math.Pi, 2*math.Pi)
+		// Keep the angle consistent with the exact half, by moving it to the
+		// nearest end of that half.
+		switch {
+		case e.half == 0 && e.angle > 3*math.Pi/2:
+			e.angle = 0
+		case e.half == 0 && e.angle > math.Pi, e.half == 1 && e.angle >= math.Pi/2 && e.angle < math.Pi:
+			e.angle = math.Pi
+		case e.half == 1 && e.angle < math.Pi/2:
+			e.angle = 2 * math.Pi
+		}
+	}
+	sort.SliceStable(ends, func(i, j int) bool {
+		return boundaryPointLess(m, ends[i], ends[j])
+	})
+	exitPos := make([]int, len(pieces))
+	for i := range ends {
+		if i > 0 && ends[i].angle < ends[i-1].angle {
+			ends[i].angle = ends[i-1].angle
+		}
+		if !ends[i].entry {
+			exitPos[ends[i].piece] = i
+		}
+	}
+	pointAt := func(a float64) Point {
+		a += base
+		return Point{fromFrame(frame, PointFromCoords(math.Cos(a), math.Sin(a), 0)).Normalize()}
+	}
+
+	used := make([]bool, len(pieces))
+	for i := range pieces {
+		if used[i] {
+			continue
+		}
+		var ring []Point
+		for p := i; !used[p]; {
+			used[p] = true
+			ring = append(ring, pieces[p]...)
+
+			// Find the next entry point after this exit point.
+			pos, next := exitPos[p], 0
+			for k := 1; k <= len(ends); k++ {
+				if next = (pos + k) % len(ends); ends[next].entry {
+					break
+				}
+			}
+			// Follow the boundary of the hemisphere, adding vertices so that
+			// no edge is longer than 90 degrees.
+			from, to := ends[pos].angle, ends[next].angle
+			if next <= pos {
+				to += 2 * math.Pi
+			}
+			steps := int(math.Ceil((to - from) / (math.Pi / 2)))
+			for k := 1; k < steps; k++ {
+				ring = append(ring, pointAt(from+(to-from)*float64(k)/float64(steps)))
+			}
+			p = ends[next].piece
+		}
+		result = append(result, ring)
+	}
+	c.rings, c.planes = result, append(c.planes, m)
+}
+
+// boundaryPointLess reports whether a comes before b in CCW order around m.
+// Points at the same position are ordered according to the perturbation
+// of vertices on the boundary into the hemisphere, and otherwise exit
+// points come before entry points.
+func boundaryPointLess(m r3.PreciseVector, a, b boundaryPoint) bool {
+	if a.half != b.half {
+		return a.half < b.half
+	}
+	if s := a.x.Cross(b.x).Dot(m).Sign(); s != 0 {
+		return s > 0
+	}
+	if a.vertex && b.vertex {
+		// Moving the vertex v into the hemisphere moves each crossing point
+		// along the boundary in the direction of the outside vertex o, by an
+		// amount proportional to (o.t)/(-m.o) where t = m x v is the CCW
+		// direction at v.
+		t := m.Cross(a.x)
+		oa, ob := r3.PreciseVectorFromVector(a.outside.Vector), r3.PreciseVectorFromVector(b.outside.Vector)
+		ka := new(big.Float).Mul(oa.Dot(t), new(big.Float).Neg(m.Dot(ob)))
+		kb := new(big.Float).Mul(ob.Dot(t), new(big.Float).Neg(m.Dot(oa)))
+		if s := ka.Cmp(kb); s != 0 {
+			return s < 0
+		}
+	}
+	return !a.entry && b.entry
+}
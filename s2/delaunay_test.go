@@ -0,0 +1,464 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/rubenpoppe/geo/r3"
+	"github.com/rubenpoppe/geo/s1"
+)
+
+// checkDelaunayTriangles verifies that the triangles are CCW and that no
+// vertex lies strictly inside the circumcircle of any triangle.
+func checkDelaunayTriangles(t *testing.T, d *DelaunayTriangulation) {
+	t.Helper()
+	for _, tri := range d.Triangles() {
+		a, b, c := d.Vertex(tri[0]), d.Vertex(tri[1]), d.Vertex(tri[2])
+		if RobustSign(a, b, c) != CounterClockwise {
+			t.Errorf("triangle %v is not CCW", tri)
+		}
+		for i := 0; i < d.NumVertices(); i++ {
+			if sphereInCircleSign(a, b, c, d.Vertex(i)) > 0 {
+				t.Errorf("vertex %d is inside the circumcircle of triangle %v", i, tri)
+			}
+		}
+	}
+}
+
+// checkVoronoiCells verifies that the Voronoi cells are valid, contain their
+// sites, and cover the sphere.
+func checkVoronoiCells(t *testing.T, d *DelaunayTriangulation) {
+	t.Helper()
+	var area float64
+	for i, cell := range d.VoronoiCells() {
+		if err := cell.Validate(); err != nil {
+			t.Errorf("VoronoiCell(%d) is invalid: %v", i, err)
+		}
+		if !cell.ContainsPoint(d.Vertex(i)) {
+			t.Errorf("VoronoiCell(%d) does not contain its site %v", i, d.Vertex(i))
+		}
+		area += cell.Area()
+	}
+	if !float64Near(area, 4*math.Pi, 1e-9) {
+		t.Errorf("total area of the Voronoi cells = %v, want %v", area, 4*math.Pi)
+	}
+}
+
+func triangleAreaSum(d *DelaunayTriangulation) float64 {
+	var sum float64
+	for _, tri := range d.Triangles() {
+		sum += SignedArea(d.Vertex(tri[0]), d.Vertex(tri[1]), d.Vertex(tri[2]))
+	}
+	return sum
+}
+
+func polygonBoundaryDistance(p *Polygon, x Point) s1.Angle {
+	dist := s1.InfAngle()
+	for _, l := range p.Loops() {
+		for i := 0; i < l.NumVertices(); i++ {
+			if d := DistanceFromSegment(x, l.Vertex(i), l.Vertex(i+1)); d < dist {
+				dist = d
+			}
+		}
+	}
+	return dist
+}
+
+func TestDelaunayTriangulationEmpty(t *testing.T) {
+	d := NewDelaunayTriangulation(nil)
+	if got := d.NumVertices(); got != 0 {
+		t.Errorf("NumVertices() = %d, want 0", got)
+	}
+	if got := len(d.Triangles()); got != 0 {
+		t.Errorf("len(Triangles()) = %d, want 0", got)
+	}
+	if got := len(d.VoronoiCells()); got != 0 {
+		t.Errorf("len(VoronoiCells()) = %d, want 0", got)
+	}
+	if got := d.VoronoiCell(0); !got.IsEmpty() {
+		t.Errorf("VoronoiCell(0) = %v, want empty loop", got)
+	}
+}
+
+func TestDelaunayTriangulationOnePoint(t *testing.T) {
+	p := parsePoint("10:20")
+	d := NewDelaunayTriangulation([]Point{p, p})
+	if got := d.NumVertices(); got != 1 {
+		t.Errorf("NumVertices() = %d, want 1", got)
+	}
+	if !d.VoronoiCell(0).IsFull() {
+		t.Errorf("VoronoiCell(0) = %v, want full loop", d.VoronoiCell(0))
+	}
+}
+
+func TestDelaunayTriangulationTwoPoints(t *testing.T) {
+	d := NewDelaunayTriangulation(parsePoints("0:0, 0:90"))
+	if got := len(d.Triangles()); got != 0 {
+		t.Errorf("len(Triangles()) = %d, want 0", got)
+	}
+	checkVoronoiCells(t, d)
+	if got := d.VoronoiCell(0); !got.ContainsPoint(parsePoint("0:-80")) || got.ContainsPoint(parsePoint("0:50")) {
+		t.Errorf("VoronoiCell(0) = %v is not the hemisphere closest to site 0", got)
+	}
+}
+
+func TestDelaunayTriangulationRandomPoints(t *testing.T) {
+	for _, n := range []int{3, 4, 5, 10, 100, 1000} {
+		points := make([]Point, n)
+		for i := range points {
+			points[i] = randomPoint()
+		}
+		d := NewDelaunayTriangulation(points)
+		checkDelaunayTriangles(t, d)
+		checkVoronoiCells(t, d)
+
+		// Random points with n >= 100 almost certainly surround the origin.
+		if n >= 100 {
+			if got := triangleAreaSum(d); !float64Near(got, 4*math.Pi, 1e-9) {
+				t.Errorf("n=%d: total area of the triangles = %v, want %v", n, got, 4*math.Pi)
+			}
+			if got, want := len(d.Triangles()), 2*n-4; got != want {
+				t.Errorf("n=%d: len(Triangles()) = %d, want %d", n, got, want)
+			}
+		}
+
+		// Each query point should be contained by the cell of its nearest site.
+		for iter := 0; iter < 100; iter++ {
+			q := randomPoint()
+			nearest := 0
+			for i, p := range points {
+				if q.Distance(p) < q.Distance(points[nearest]) {
+					nearest = i
+				}
+			}
+			if !d.VoronoiCell(nearest).ContainsPoint(q) {
+				t.Errorf("n=%d: VoronoiCell(%d) does not contain %v", n, nearest, q)
+			}
+		}
+	}
+}
+
+func TestDelaunayTriangulationHemisphere(t *testing.T) {
+	// When the points lie in a small cap, the triangles cover their convex hull.
+	c := CapFromCenterAngle(randomPoint(), s1.Angle(0.1))
+	query := NewConvexHullQuery()
+	var points []Point
+	for i := 0; i < 50; i++ {
+		p := samplePointFromCap(c)
+		points = append(points, p)
+		query.AddPoint(p)
+	}
+	d := NewDelaunayTriangulation(points)
+	checkDelaunayTriangles(t, d)
+	checkVoronoiCells(t, d)
+	if got, want := triangleAreaSum(d), query.ConvexHull().Area(); !float64Near(got, want, 1e-12) {
+		t.Errorf("total area of the triangles = %v, want %v", got, want)
+	}
+}
+
+func TestDelaunayTriangulationDuplicates(t *testing.T) {
+	points := parsePoints("0:0, 10:0, 0:10, 0:0, -10:-10, 10:0, 45:45, -45:135, 0:0")
+	d := NewDelaunayTriangulation(points)
+	if got, want := d.NumVertices(), 6; got != want {
+		t.Errorf("NumVertices() = %d, want %d", got, want)
+	}
+	for i, p := range points {
+		if got := d.Vertex(d.Site(i)); got != p {
+			t.Errorf("Vertex(Site(%d)) = %v, want %v", i, got, p)
+		}
+	}
+	checkDelaunayTriangles(t, d)
+	checkVoronoiCells(t, d)
+}
+
+func TestDelaunayTriangulationCocircular(t *testing.T) {
+	// A regular latitude/longitude grid has many sets of cocircular points.
+	var points []Point
+	for lat := -80; lat <= 80; lat += 20 {
+		for lng := -180; lng < 180; lng += 30 {
+			points = append(points, PointFromLatLng(LatLngFromDegrees(float64(lat), float64(lng))))
+		}
+	}
+	points = append(points, parsePoint("90:0"), parsePoint("-90:0"))
+	d := NewDelaunayTriangulation(points)
+	checkDelaunayTriangles(t, d)
+	checkVoronoiCells(t, d)
+	if got := triangleAreaSum(d); !float64Near(got, 4*math.Pi, 1e-9) {
+		t.Errorf("total area of the triangles = %v, want %v", got, 4*math.Pi)
+	}
+
+	// The vertices of a cube are all cocircular in groups of four.
+	var cube []Point
+	for _, x := range []float64{-1, 1} {
+		for _, y := range []float64{-1, 1} {
+			for _, z := range []float64{-1, 1} {
+				cube = append(cube, PointFromCoords(x, y, z))
+			}
+		}
+	}
+	d = NewDelaunayTriangulation(cube)
+	checkDelaunayTriangles(t, d)
+	checkVoronoiCells(t, d)
+	for i, cell := range d.VoronoiCells() {
+		if got := cell.NumVertices(); got != 3 {
+			t.Errorf("VoronoiCell(%d).NumVertices() = %d, want 3", i, got)
+		}
+	}
+}
+
+func TestDelaunayTriangulationInsideHull(t *testing.T) {
+	// Closely spaced points on a small circle, most of which are moved
+	// slightly towards the origin so that they lie inside the convex hull.
+	// Such points are still vertices of the triangulation, and the
+	// triangles do not overlap.
+	var points []Point
+	for lng := 0.0; lng < 360; lng += 30 {
+		points = append(points, PointFromLatLng(LatLngFromDegrees(30, lng)))
+		for k := 1; k < 10; k++ {
+			points = append(points, Point{PointFromLatLng(LatLngFromDegrees(30, lng+float64(k)*1e-6)).Mul(1 - 1e-15)})
+		}
+		points = append(points, PointFromLatLng(LatLngFromDegrees(30, lng+1e-5)))
+	}
+	points = append(points, parsePoint("90:0"), parsePoint("-90:0"))
+	d := NewDelaunayTriangulation(points)
+	used := make([]bool, d.NumVertices())
+	for _, tri := range d.Triangles() {
+		if RobustSign(d.Vertex(tri[0]), d.Vertex(tri[1]), d.Vertex(tri[2])) != CounterClockwise {
+			t.Errorf("triangle %v is not CCW", tri)
+		}
+		for _, v := range tri {
+			used[v] = true
+		}
+	}
+	for i, ok := range used {
+		if !ok {
+			t.Errorf("vertex %d is not used by any triangle", i)
+		}
+	}
+	if got := triangleAreaSum(d); !float64Near(got, 4*math.Pi, 1e-9) {
+		t.Errorf("total area of the triangles = %v, want %v", got, 4*math.Pi)
+	}
+}
+
+func TestDelaunayTriangulationClusteredPoints(t *testing.T) {
+	// Points that are about 1e-6 degrees apart, so that the errors in their
+	// lengths are comparable to the differences between the circumcircles
+	// of nearby triangles. Both a regular grid (which has many nearly
+	// cocircular points) and random points are tested, together with a
+	// distant point so that some cells are bounded by the cluster.
+	var grid []Point
+	for i := 0; i < 8; i++ {
+		for j := 0; j < 8; j++ {
+			grid = append(grid, PointFromLatLng(LatLngFromDegrees(30+1e-6*float64(i), 40+1e-6*float64(j))))
+		}
+	}
+	var random []Point
+	c := CapFromCenterAngle(PointFromLatLng(LatLngFromDegrees(30, 40)), s1.Angle(1e-5)*s1.Degree)
+	for i := 0; i < 64; i++ {
+		random = append(random, samplePointFromCap(c))
+	}
+	for _, points := range [][]Point{grid, random} {
+		d := NewDelaunayTriangulation(append(points, parsePoint("-60:-140")))
+		checkDelaunayTriangles(t, d)
+		checkVoronoiCells(t, d)
+	}
+}
+
+func TestDelaunayTriangulationGreatCircle(t *testing.T) {
+	d := NewDelaunayTriangulation(parsePoints("0:0, 0:50, 0:100, 0:-170, 0:-90"))
+	if got := len(d.Triangles()); got != 0 {
+		t.Errorf("len(Triangles()) = %d, want 0", got)
+	}
+	checkVoronoiCells(t, d)
+	// The cell of 0:0 is the lune between the meridians at -45 and 25.
+	cell := d.VoronoiCell(0)
+	if !cell.ContainsPoint(parsePoint("60:-40")) || !cell.ContainsPoint(parsePoint("-60:20")) {
+		t.Errorf("VoronoiCell(0) = %v does not contain expected points", cell)
+	}
+	if cell.ContainsPoint(parsePoint("60:-50")) || cell.ContainsPoint(parsePoint("-60:30")) {
+		t.Errorf("VoronoiCell(0) = %v contains unexpected points", cell)
+	}
+}
+
+func TestDelaunayTriangulationSmallCircle(t *testing.T) {
+	d := NewDelaunayTriangulation(parsePoints("30:0, 30:90, 30:180, 30:-90"))
+	checkDelaunayTriangles(t, d)
+	checkVoronoiCells(t, d)
+	if got, want := triangleAreaSum(d), LoopFromPoints(parsePoints("30:0, 30:90, 30:180, 30:-90")).Area(); !float64Near(got, want, 1e-12) {
+		t.Errorf("total area of the triangles = %v, want %v", got, want)
+	}
+}
+
+func TestDelaunayTriangulationClippedVoronoiCells(t *testing.T) {
+	clip := makePolygon("-10:-10, -10:10, 10:10, 10:-10; -5:-5, -5:5, 5:5, 5:-5", false)
+	points := []Point{parsePoint("0:0")}
+	for i := 0; i < 30; i++ {
+		points = append(points, samplePointFromRect(RectFromLatLng(LatLngFromDegrees(-15, -15)).AddPoint(LatLngFromDegrees(15, 15))))
+	}
+	d := NewDelaunayTriangulation(points)
+	var area float64
+	for i, cell := range d.ClippedVoronoiCells(clip) {
+		if err := cell.Validate(); err != nil {
+			t.Errorf("ClippedVoronoiCell(%d) is invalid: %v", i, err)
+		}
+		// The cell boundary may coincide with the clip boundary, so vertices are
+		// allowed to lie slightly outside the clip polygon.
+		for _, l := range cell.Loops() {
+			for _, v := range l.Vertices() {
+				if !clip.ContainsPoint(v) && polygonBoundaryDistance(clip, v) > 1e-14 {
+					t.Errorf("ClippedVoronoiCell(%d) vertex %v is outside the clip polygon", i, v)
+				}
+			}
+		}
+		area += cell.Area()
+	}
+	if got, want := area, clip.Area(); !float64Near(got, want, 1e-9) {
+		t.Errorf("total area of the clipped cells = %v, want %v", got, want)
+	}
+
+	// The site at the center of the hole has an empty or tiny cell.
+	if got := d.ClippedVoronoiCell(0, clip).Area(); got > clip.Area()/2 {
+		t.Errorf("ClippedVoronoiCell(0).Area() = %v, too large", got)
+	}
+
+	// A cell clipped away entirely is an empty polygon that can be queried.
+	far := makePolygon("60:60, 60:61, 61:61", false)
+	if got := d.ClippedVoronoiCell(0, far); !got.IsEmpty() || got.ContainsPoint(parsePoint("60.5:60.2")) {
+		t.Errorf("ClippedVoronoiCell(0, %v) = %v, want empty polygon", far, got)
+	}
+
+	// Clipping by the full polygon returns the cell itself.
+	full := FullPolygon()
+	for i := 0; i < d.NumVertices(); i++ {
+		if got, want := d.ClippedVoronoiCell(i, full).Area(), d.VoronoiCell(i).Area(); !float64Near(got, want, 1e-12) {
+			t.Errorf("ClippedVoronoiCell(%d, full).Area() = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestDelaunayTriangulationClippedVoronoiCellsRegion(t *testing.T) {
+	var points []Point
+	for i := 0; i < 20; i++ {
+		points = append(points, samplePointFromRect(RectFromLatLng(LatLngFromDegrees(-15, -15)).AddPoint(LatLngFromDegrees(15, 15))))
+	}
+	d := NewDelaunayTriangulation(points)
+	cellID := CellIDFromLatLng(LatLngFromDegrees(0, 0)).Parent(4)
+	cu := CellUnion{cellID.ChildBegin(), cellID.ChildBegin().Next(), cellID.Next()}
+	cu.Normalize()
+	loop := LoopFromPoints(parsePoints("-10:-10, -10:10, 10:10, 10:-10"))
+	cap := CapFromCenterAngle(parsePoint("0:0"), s1.Degree*5)
+	tests := []struct {
+		region    Region
+		want, tol float64
+	}{
+		{CellFromCellID(cellID), CellFromCellID(cellID).ExactArea(), 1e-9},
+		{&cu, cu.ExactArea(), 1e-9},
+		{loop, loop.Area(), 1e-9},
+		// A cap is approximated by a slightly larger covering.
+		{cap, 1.02 * cap.Area(), 0.02 * cap.Area()},
+	}
+	for _, test := range tests {
+		var area float64
+		for i, cell := range d.ClippedVoronoiCells(test.region) {
+			if err := cell.Validate(); err != nil {
+				t.Errorf("ClippedVoronoiCell(%d, %v) is invalid: %v", i, test.region, err)
+			}
+			area += cell.Area()
+		}
+		if !float64Near(area, test.want, test.tol) {
+			t.Errorf("total area of the cells clipped to %v = %v, want %v", test.region, area, test.want)
+		}
+	}
+}
+
+func TestDelaunayTriangulationClippedVoronoiCellsFaces(t *testing.T) {
+	// The sites are placed symmetrically about the cube faces, so that many
+	// Voronoi edges pass through the edges and corners of the faces, and the
+	// cells are clipped to unions of faces and their children.
+	var points []Point
+	for i := -1; i <= 1; i++ {
+		for j := -1; j <= 1; j++ {
+			for k := -1; k <= 1; k++ {
+				points = append(points, PointFromCoords(float64(i), float64(j), float64(k)+0.5))
+			}
+		}
+	}
+	d := NewDelaunayTriangulation(points)
+	for face := 0; face < 6; face++ {
+		for k := 0; k < 4; k++ {
+			cu := CellUnion{
+				CellIDFromFace(face).Children()[k],
+				CellIDFromFace((face + 1) % 6),
+				CellIDFromFace((face + 3) % 6).Children()[(k+1)%4].Children()[k],
+			}
+			cu.Normalize()
+			var area float64
+			for _, cell := range d.ClippedVoronoiCells(&cu) {
+				area += cell.Area()
+			}
+			// Loop.Area loses some precision for the nearly collinear
+			// vertices where the cells meet the cell boundaries.
+			if got, want := area, cu.ExactArea(); !float64Near(got, want, 1e-8) {
+				t.Errorf("total area of the cells clipped to %v = %v, want %v", cu, got, want)
+			}
+		}
+	}
+}
+
+func TestHemisphereClipperBoundary(t *testing.T) {
+	// Clip to the northern hemisphere. Each ring touches the equator, either
+	// at a vertex or along an edge, from one side or the other. The expected
+	// result is given as a loop, where "full" denotes the hemisphere itself.
+	tests := []struct {
+		ring, want string
+	}{
+		// A shell below the equator touching it at a vertex.
+		{"0:0, -10:-5, -10:5", ""},
+		// A hole below the equator touching it at a vertex.
+		{"0:0, -10:5, -10:-5", "full"},
+		// A shell below the equator with an edge along it.
+		{"-10:0, -10:10, 0:10, 0:0", ""},
+		// A hole below the equator with an edge along it.
+		{"0:0, 0:10, -10:10, -10:0", "full"},
+		// A shell above the equator touching it at a vertex.
+		{"0:0, 10:5, 10:-5", "0:0, 10:5, 10:-5"},
+		// A shell crossing the equator that enters it at a vertex.
+		{"0:20, 10:10, 10:0, -10:0", "0:20, 10:10, 10:0, 0:0"},
+	}
+	equator := []Point{parsePoint("0:0"), parsePoint("0:90"), parsePoint("0:180"), parsePoint("0:-90")}
+	for _, test := range tests {
+		ring := parsePoints(test.ring)
+		p := polygonFromRings([][]Point{ring})
+		c := &hemisphereClipper{rings: [][]Point{ring}, contains: p.ContainsPoint}
+		c.clip(r3.NewPreciseVector(0, 0, 1), equator)
+		got := polygonFromRings(c.rings)
+		if err := got.Validate(); err != nil {
+			t.Errorf("clipping %q: result is invalid: %v", test.ring, err)
+		}
+		var want float64
+		switch test.want {
+		case "":
+		case "full":
+			want = 2 * math.Pi
+		default:
+			want = makePolygon(test.want, false).Area()
+		}
+		if !float64Near(got.Area(), want, 1e-12) {
+			t.Errorf("clipping %q: area = %v, want %v", test.ring, got.Area(), want)
+		}
+	}
+}
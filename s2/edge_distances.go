@@ -406,3 +406,87 @@ func EdgePairClosestPoints(a0, a1, b0, b1 Point) (Point, Point) {
 		panic("illegal case reached")
 	}
 }
+
+// IsEdgeBNearEdgeA reports whether every point on edge B=b0b1 is no further
+// than tolerance from some point on edge A=a0a1. Equivalently, it reports
+// whether the directed Hausdorff distance from B to A is no more than
+// tolerance. The tolerance must be in the range (0, Pi/2).
+func IsEdgeBNearEdgeA(a0, a1, b0, b1 Point, tolerance s1.Angle) bool {
+	// The point on edge B=b0b1 furthest from edge A=a0a1 is either b0, b1, or
+	// some interior point on B. If it is an interior point on B, then it must
+	// be one of the two points where the great circle containing B (circ(B))
+	// is furthest from the great circle containing A (circ(A)). At these
+	// points, the distance between circ(B) and circ(A) is the angle between
+	// the planes containing them.
+	aOrtho := Point{a0.PointCross(a1).Normalize()}
+	aNearestB0 := Project(b0, a0, a1)
+	aNearestB1 := Project(b1, a0, a1)
+
+	// If aNearestB0 and aNearestB1 have opposite orientation from a0 and a1,
+	// invert aOrtho so that it points in the same direction as
+	// aNearestB0 x aNearestB1. This handles the case where A and B are
+	// oppositely oriented but otherwise might be near each other. We check
+	// orientation and invert rather than computing aNearestB0 x aNearestB1
+	// because those two points might be equal, and have an unhelpful cross
+	// product.
+	if RobustSign(aOrtho, aNearestB0, aNearestB1) == Clockwise {
+		aOrtho = Point{aOrtho.Mul(-1)}
+	}
+
+	// To check if all points on B are within tolerance of A, we first check
+	// to see if the endpoints of B are near A. If they are not, B is not
+	// near A.
+	if b0.Distance(aNearestB0) > tolerance || b1.Distance(aNearestB1) > tolerance {
+		return false
+	}
+
+	// If b0 and b1 are both within tolerance of A, we check to see if the
+	// angle between the planes containing B and A is greater than tolerance.
+	// If it is not, no point on B can be further than tolerance from A
+	// (recall that we already know that B's furthest point from A is either
+	// b0, b1, or one of the points of maximum separation).
+	bOrtho := Point{b0.PointCross(b1).Normalize()}
+	planarAngle := aOrtho.Distance(bOrtho)
+	if planarAngle <= tolerance {
+		return true
+	}
+
+	// As planarAngle approaches Pi, the projection of aOrtho onto the plane
+	// of B approaches the null vector, and normalizing it is numerically
+	// unstable. This makes it unreliable or impossible to identify pairs of
+	// points where circ(A) is furthest from circ(B). At this point in the
+	// algorithm, this can only occur for two reasons:
+	//
+	//  1. b0 and b1 are closest to A at distinct endpoints of A, in which case
+	//     the opposite orientation of aOrtho and bOrtho means that A and B are
+	//     in opposite hemispheres and hence not close to each other.
+	//
+	//  2. b0 and b1 are closest to A at the same endpoint of A, in which case
+	//     the orientation of aOrtho was chosen arbitrarily to be that of
+	//     a0 x a1. B must be shorter than 2*tolerance and all points in B are
+	//     close to one endpoint of A, and hence to A.
+	//
+	// The logic applies when planarAngle is robustly greater than Pi/2, but
+	// may be more computationally expensive than the logic beyond, so we
+	// choose a value close to Pi.
+	if planarAngle >= s1.Angle(math.Pi-0.01) {
+		return (b0.Distance(a0) < b0.Distance(a1)) == (b1.Distance(a0) < b1.Distance(a1))
+	}
+
+	// Finally, if either of the two points on circ(B) where circ(B) is
+	// furthest from circ(A) lie on edge B, edge B is not near edge A.
+	//
+	// The normalized projection of aOrtho onto the plane of circ(B) is one of
+	// the two points along circ(B) where it is furthest from circ(A). The
+	// other is -1 times the normalized projection.
+	furthest := Point{aOrtho.Sub(bOrtho.Mul(aOrtho.Dot(bOrtho.Vector))).Normalize()}
+	furthestInv := Point{furthest.Mul(-1)}
+
+	// A point p lies on B if you can proceed from bOrtho to b0 to p to b1 and
+	// back to bOrtho without ever turning right. We test this for furthest
+	// and furthestInv, and return true if neither point lies on B.
+	return !((bOrtho.Cross(b0.Vector).Dot(furthest.Vector) > 0 &&
+		furthest.Cross(b1.Vector).Dot(bOrtho.Vector) > 0) ||
+		(bOrtho.Cross(b0.Vector).Dot(furthestInv.Vector) > 0 &&
+			furthestInv.Cross(b1.Vector).Dot(bOrtho.Vector) > 0))
+}
@@ -786,3 +786,36 @@ func TestEdgeDistancesEdgePairMaxDistance(t *testing.T) {
 }
 
 // TestEdgeDistancesEdgeBNearEdgeA
+
+func TestEdgeDistancesIsEdgeBNearEdgeA(t *testing.T) {
+	tests := []struct {
+		a, b      string
+		tolerance float64 // degrees
+		want      bool
+	}{
+		// An edge is near itself and its reverse.
+		{"5:5, 10:-5", "5:5, 10:-5", 1e-6, true},
+		{"5:5, 10:-5", "10:-5, 5:5", 1e-6, true},
+		// A short edge is near a long edge, but not vice versa.
+		{"10:0, -10:0", "2:1, -2:1", 1, true},
+		{"2:1, -2:1", "10:0, -10:0", 1, false},
+		// Orthogonal crossing edges are not near each other, unless all the
+		// points of B are within tolerance of A.
+		{"10:0, -10:0", "0:1.5, 0:-1.5", 1, false},
+		{"10:0, -10:0", "0:1.5, 0:-1.5", 2, true},
+		// Long edges whose endpoints are close can have interior points that
+		// are far apart.
+		{"89:1, -89:1", "89:0, -89:0", 0.5, false},
+		{"89:1, -89:1", "89:0, -89:0", 1.5, true},
+		// B is short and near an endpoint of an A that points the other way.
+		{"0:0, 0:10", "0.1:0.1, -0.1:-0.1", 0.5, true},
+		{"0:0, 0:10", "0.1:-0.1, -0.1:-0.6", 0.5, false},
+	}
+	for _, test := range tests {
+		a := parsePoints(test.a)
+		b := parsePoints(test.b)
+		if got := IsEdgeBNearEdgeA(a[0], a[1], b[0], b[1], s1.Angle(test.tolerance)*s1.Degree); got != test.want {
+			t.Errorf("IsEdgeBNearEdgeA(%s, %s, %v°) = %v, want %v", test.a, test.b, test.tolerance, got, test.want)
+		}
+	}
+}
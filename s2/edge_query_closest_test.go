@@ -212,6 +212,22 @@ func TestClosestEdgeQueryTargetPolygonContainingIndexedPoints(t *testing.T) {
 	}
 }
 
+func TestClosestEdgeQueryIndexSpanningSeveralFaces(t *testing.T) {
+	// The index covering must include every face spanned by the index, so a
+	// loop that spans several faces is used with enough edges to ensure that
+	// the optimized algorithm is used.
+	index := NewShapeIndex()
+	index.Add(RegularLoop(PointFromCoords(1, 1, 1), s1.Angle(0.5), 100))
+	query := NewClosestEdgeQuery(index, NewClosestEdgeQueryOptions())
+	bruteQuery := NewClosestEdgeQuery(index, NewClosestEdgeQueryOptions().UseBruteForce(true))
+	for i := 0; i < 100; i++ {
+		target := NewMinDistanceToPointTarget(randomPoint())
+		if got, want := query.Distance(target), bruteQuery.Distance(target); got != want {
+			t.Errorf("query.Distance(%v) = %v, want %v", target.point, got, want)
+		}
+	}
+}
+
 func BenchmarkEdgeQueryFindEdgesClosestFractal(b *testing.B) {
 	// Test searching within the general vicinity of the indexed shapes.
 	opts := &edgeQueryBenchmarkOptions{
@@ -194,9 +194,11 @@ type EdgeTessellator struct {
 
 // NewEdgeTessellator creates a new edge tessellator for the given projection and tolerance.
 func NewEdgeTessellator(p Projection, tolerance s1.Angle) *EdgeTessellator {
+	// Rather than scaling the error estimate as described above, scale the
+	// tolerance instead.
 	return &EdgeTessellator{
 		projection:      p,
-		scaledTolerance: s1.ChordAngleFromAngle(maxAngle(tolerance, minTessellationTolerance)),
+		scaledTolerance: s1.ChordAngleFromAngle(tessellationScaleFactor * maxAngle(tolerance, minTessellationTolerance)),
 	}
 }
 
@@ -130,5 +130,180 @@ func TestEdgeTessellatorProjectedWrappingMultipleCrossings(t *testing.T) {
 	}
 }
 
+// projectedError returns the largest distance, sampled along the chain, from
+// the geodesic edge AB to the chain of projected vertices.
+func projectedError(proj Projection, a, b Point, vertices []r2.Point) s1.Angle {
+	var maxDist s1.Angle
+	for i := 0; i+1 < len(vertices); i++ {
+		for j := 0; j <= 16; j++ {
+			x := proj.Unproject(proj.Interpolate(float64(j)/16, vertices[i], vertices[i+1]))
+			maxDist = maxAngle(maxDist, DistanceFromSegment(x, a, b))
+		}
+	}
+	return maxDist
+}
+
+// unprojectedError returns the largest distance, sampled along the projected
+// edge AB, from the edge to the chain of geodesic vertices.
+func unprojectedError(proj Projection, pa, pb r2.Point, vertices []Point) s1.Angle {
+	pb = proj.WrapDestination(pa, pb)
+	var maxDist s1.Angle
+	for j := 0; j <= 256; j++ {
+		x := proj.Unproject(proj.Interpolate(float64(j)/256, pa, pb))
+		minDist := s1.InfAngle()
+		for i := 0; i+1 < len(vertices); i++ {
+			minDist = minAngle(minDist, DistanceFromSegment(x, vertices[i], vertices[i+1]))
+		}
+		maxDist = maxAngle(maxDist, minDist)
+	}
+	return maxDist
+}
+
+func TestEdgeTessellatorErrorWithinTolerance(t *testing.T) {
+	// The error estimate is measured at two points along the edge, so the
+	// true error may be larger. The tolerance is scaled to compensate.
+	domain := CapFromCenterAngle(PointFromCoords(1, 0, 0), 80*s1.Degree)
+	for _, proj := range []Projection{NewPlateCarreeProjection(180), NewMercatorProjection(180)} {
+		for i := 0; i < 100; i++ {
+			tolerance := s1.Angle(math.Pow(10, -2-3*randomFloat64())) * s1.Degree
+			tess := NewEdgeTessellator(proj, tolerance)
+			a, b := samplePointFromCap(domain), samplePointFromCap(domain)
+			if got := projectedError(proj, a, b, tess.AppendProjected(a, b, nil)); got > tolerance {
+				t.Errorf("AppendProjected(%v, %v) has error %v, want <= %v", a, b, got, tolerance)
+			}
+			pa, pb := proj.Project(a), proj.Project(b)
+			if got := unprojectedError(proj, pa, pb, tess.AppendUnprojected(pa, pb, nil)); got > tolerance {
+				t.Errorf("AppendUnprojected(%v, %v) has error %v, want <= %v", pa, pb, got, tolerance)
+			}
+		}
+	}
+}
+
+// tessellationProjections are projections with a cap in which random edges
+// are tessellated.
+var tessellationProjections = []struct {
+	name   string
+	proj   Projection
+	domain Cap
+}{
+	{"plate carree", NewPlateCarreeProjection(180), CapFromCenterAngle(PointFromCoords(1, 0, 0), 85*s1.Degree)},
+	{"mercator", NewMercatorProjection(180), CapFromCenterAngle(PointFromCoords(1, 0, 0), 80*s1.Degree)},
+	{"gnomonic", NewGnomonicProjection(PointFromLatLng(LatLngFromDegrees(40, -100))), CapFromCenterAngle(PointFromLatLng(LatLngFromDegrees(40, -100)), 60*s1.Degree)},
+	{"lambert", NewLambertAzimuthalEqualAreaProjection(PointFromLatLng(LatLngFromDegrees(52, 10))), CapFromCenterAngle(PointFromLatLng(LatLngFromDegrees(52, 10)), 150*s1.Degree)},
+	// The domain of the transverse Mercator projection is centered on the
+	// pole, where its y coordinate wraps.
+	{"transverse mercator", NewTransverseMercatorProjection(20*s1.Degree, 1), CapFromCenterAngle(PointFromCoords(0, 0, 1), 80*s1.Degree)},
+	{"utm", NewUTMProjection(33, true), CapFromCenterAngle(PointFromLatLng(LatLngFromDegrees(50, 15)), 10*s1.Degree)},
+	{"face", NewFaceProjection(4), CapFromCenterAngle(PointFromCoords(0, -1, 0), 50*s1.Degree)},
+}
+
+func TestEdgeTessellatorProjectionsRandomEdges(t *testing.T) {
+	for _, test := range tessellationProjections {
+		for i := 0; i < 50; i++ {
+			tolerance := s1.Angle(math.Pow(10, -2-3*randomFloat64())) * s1.Degree
+			tess := NewEdgeTessellator(test.proj, tolerance)
+			a, b := samplePointFromCap(test.domain), samplePointFromCap(test.domain)
+
+			projected := tess.AppendProjected(a, b, nil)
+			if got := projectedError(test.proj, a, b, projected); got > 1.01*tolerance {
+				t.Errorf("%s: AppendProjected(%v, %v) has error %v, want <= %v", test.name, a, b, got, tolerance)
+			}
+
+			pa, pb := test.proj.Project(a), test.proj.Project(b)
+			unprojected := tess.AppendUnprojected(pa, pb, nil)
+			if got := unprojectedError(test.proj, pa, pb, unprojected); got > 1.01*tolerance {
+				t.Errorf("%s: AppendUnprojected(%v, %v) has error %v, want <= %v", test.name, pa, pb, got, tolerance)
+			}
+		}
+	}
+}
+
+func TestEdgeTessellatorStraightGeodesics(t *testing.T) {
+	// Geodesics are straight lines in the gnomonic and face projections, so
+	// every tessellated vertex is on both the geodesic and the projected edge.
+	for _, test := range tessellationProjections {
+		if test.name != "gnomonic" && test.name != "face" {
+			continue
+		}
+		tess := NewEdgeTessellator(test.proj, 1e-3*s1.Degree)
+		for i := 0; i < 50; i++ {
+			a, b := samplePointFromCap(test.domain), samplePointFromCap(test.domain)
+			pa, pb := test.proj.Project(a), test.proj.Project(b)
+			for _, v := range tess.AppendProjected(a, b, nil) {
+				if d := math.Abs(pb.Sub(pa).Normalize().Cross(v.Sub(pa))); d > 1e-14 {
+					t.Errorf("%s: AppendProjected(%v, %v) has vertex %v at %v from the projected edge", test.name, a, b, v, d)
+				}
+			}
+			for _, v := range tess.AppendUnprojected(pa, pb, nil) {
+				if d := DistanceFromSegment(v, a, b); d > 1e-14 {
+					t.Errorf("%s: AppendUnprojected(%v, %v) has vertex %v at %v from the geodesic", test.name, pa, pb, v, d)
+				}
+			}
+		}
+	}
+}
+
+func TestEdgeTessellatorInflectionPoints(t *testing.T) {
+	// Each edge is symmetric about a point where its projection has a point
+	// of inflection, so the projected and geodesic edges have the same
+	// midpoint even though they are far apart elsewhere. The tessellator must
+	// not be fooled by this.
+	tests := []struct {
+		name string
+		proj Projection
+		a, b LatLng
+	}{
+		// Mercator and Plate Carree edges curve towards the equator.
+		{"plate carree", NewPlateCarreeProjection(180), LatLngFromDegrees(30, -50), LatLngFromDegrees(-30, 50)},
+		{"mercator", NewMercatorProjection(180), LatLngFromDegrees(30, -50), LatLngFromDegrees(-30, 50)},
+		// Transverse Mercator edges curve towards the central meridian, which
+		// is at 20 degrees, and the edge is symmetric about 0:20.
+		{"transverse mercator", NewTransverseMercatorProjection(20*s1.Degree, 1), LatLngFromDegrees(40, -10), LatLngFromDegrees(-40, 50)},
+		{"utm", NewUTMProjection(33, true), LatLngFromDegrees(5, 12), LatLngFromDegrees(-5, 18)},
+	}
+	for _, test := range tests {
+		a, b := PointFromLatLng(test.a), PointFromLatLng(test.b)
+		pa, pb := test.proj.Project(a), test.proj.Project(b)
+		mid := test.proj.Unproject(test.proj.Interpolate(0.5, pa, pb))
+		if d := mid.Distance(Interpolate(0.5, a, b)); d > 1e-9*s1.Degree {
+			t.Fatalf("%s: the midpoints of the edge are %v apart, want the same", test.name, d.Degrees())
+		}
+
+		tolerance := 1e-3 * s1.Degree
+		tess := NewEdgeTessellator(test.proj, tolerance)
+		projected := tess.AppendProjected(a, b, nil)
+		if len(projected) <= 2 {
+			t.Errorf("%s: AppendProjected(%v, %v) was not tessellated", test.name, test.a, test.b)
+		}
+		if got := projectedError(test.proj, a, b, projected); got > tolerance {
+			t.Errorf("%s: AppendProjected(%v, %v) has error %v, want <= %v", test.name, test.a, test.b, got, tolerance)
+		}
+		unprojected := tess.AppendUnprojected(pa, pb, nil)
+		if len(unprojected) <= 2 {
+			t.Errorf("%s: AppendUnprojected(%v, %v) was not tessellated", test.name, pa, pb)
+		}
+		if got := unprojectedError(test.proj, pa, pb, unprojected); got > tolerance {
+			t.Errorf("%s: AppendUnprojected(%v, %v) has error %v, want <= %v", test.name, pa, pb, got, tolerance)
+		}
+	}
+}
+
+func TestEdgeTessellatorTransverseMercatorWrapping(t *testing.T) {
+	// The y coordinate runs along the central meridian over the north pole
+	// to the equator at longitude 180, where it wraps from 180 to -180. An
+	// edge across the wrap goes the short way, near the equator, rather than
+	// over both poles.
+	proj := NewTransverseMercatorProjection(0, 180/math.Pi)
+	tess := NewEdgeTessellator(proj, 1e-3*s1.Degree)
+	var vertices []Point
+	vertices = tess.AppendUnprojected(r2.Point{5, 170}, r2.Point{5, -170}, vertices)
+	for i, v := range vertices {
+		ll := LatLngFromPoint(v)
+		if math.Abs(ll.Lat.Degrees()) > 11 || math.Abs(ll.Lng.Degrees()) < 170 {
+			t.Errorf("vertex %d = %v, want it near the equator at longitude 180", i, ll)
+		}
+	}
+}
+
 // TODO(roberts): Differences from C++
 // The DistStats accuracy by exhaustion test cases.
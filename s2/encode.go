@@ -15,7 +15,10 @@
 package s2
 
 import (
+	"bytes"
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"io"
 	"math"
 )
@@ -45,6 +48,13 @@ func (e *encoder) writeUvarint(x uint64) {
 	_, e.err = e.w.Write(buf[:n])
 }
 
+func (e *encoder) writeBytes(b []byte) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.Write(b)
+}
+
 func (e *encoder) writeBool(x bool) {
 	if e.err != nil {
 		return
@@ -143,10 +153,150 @@ func asByteReader(r io.Reader) byteReader {
 	return byteReaderAdapter{r}
 }
 
+// The errors returned when decoding fails. Decode errors wrap one of these,
+// so the cause can be tested with errors.Is.
+var (
+	// ErrDecodeTruncated means that the input ended before the encoded value.
+	ErrDecodeTruncated = errors.New("s2: truncated encoding")
+	// ErrDecodeVersion means that the encoding has an unknown version or format.
+	ErrDecodeVersion = errors.New("s2: unsupported encoding version")
+	// ErrDecodeLimit means that the encoded value is larger than a fixed
+	// maximum of the encoding or than a limit of the DecodeOptions.
+	ErrDecodeLimit = errors.New("s2: decode limit exceeded")
+	// ErrDecodeInvalid means that the encoding is malformed, or that it
+	// describes a value that cannot be constructed.
+	ErrDecodeInvalid = errors.New("s2: invalid encoding")
+)
+
+// DecodeOptions limits the resources used to decode untrusted input.
+//
+// Decoding never allocates memory for elements before their bytes have been
+// read, so the memory used is proportional to the size of the input even
+// without limits. The limits bound that size, and the number of elements.
+type DecodeOptions struct {
+	// MaxElements is the maximum total number of elements, such as
+	// vertices, points, loops or cells, in the decoded value. Zero means
+	// there is no limit other than the fixed maximums of each encoding.
+	MaxElements int
+
+	// MaxBytes is the maximum number of bytes read from the input. Zero
+	// means there is no limit.
+	MaxBytes int64
+}
+
+// decodable is implemented by the types that can be decoded with DecodeOptions.
+type decodable interface {
+	decode(d *decoder)
+}
+
+// Decode decodes v from r within the limits of the options. v must be a
+// pointer to a type of this package that has a Decode method, such as
+// *Polygon, *CellUnion or *EncodedPointVector, and is decoded in the same
+// way as by that method.
+func (o DecodeOptions) Decode(r io.Reader, v interface{}) error {
+	dv, ok := v.(decodable)
+	if !ok {
+		return fmt.Errorf("s2: cannot decode into %T", v)
+	}
+	d := newDecoder(r, o)
+	dv.decode(d)
+	return d.err
+}
+
+// limitedByteReader is a byteReader that fails once more than n bytes have
+// been read.
+type limitedByteReader struct {
+	r     byteReader
+	n     int64 // the number of bytes remaining
+	limit int64
+}
+
+func (l *limitedByteReader) limitError() error {
+	return fmt.Errorf("%w: input is longer than %d bytes", ErrDecodeLimit, l.limit)
+}
+
+func (l *limitedByteReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if l.n <= 0 {
+		return 0, l.limitError()
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
+
+func (l *limitedByteReader) ReadByte() (byte, error) {
+	if l.n <= 0 {
+		return 0, l.limitError()
+	}
+	b, err := l.r.ReadByte()
+	if err == nil {
+		l.n--
+	}
+	return b, err
+}
+
 type decoder struct {
 	r   byteReader // the real reader passed to Decode
 	err error
 	buf []byte
+
+	opts     DecodeOptions
+	elements uint64 // the number of elements counted so far
+}
+
+// newDecoder returns a decoder reading from r within the limits of opts.
+func newDecoder(r io.Reader, opts DecodeOptions) *decoder {
+	br := asByteReader(r)
+	if opts.MaxBytes > 0 {
+		br = &limitedByteReader{r: br, n: opts.MaxBytes, limit: opts.MaxBytes}
+	}
+	return &decoder{r: br, opts: opts}
+}
+
+// setReadErr records an error returned by the reader, if any. Running out
+// of input is reported as ErrDecodeTruncated.
+func (d *decoder) setReadErr(err error) {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = fmt.Errorf("%w: %v", ErrDecodeTruncated, io.ErrUnexpectedEOF)
+	}
+	d.err = err
+}
+
+// checkCount checks a number of elements read from the input against max,
+// the largest number the encoding allows, and against the limit of the
+// options, and counts them towards that limit. It reports whether the
+// elements can be decoded.
+func (d *decoder) checkCount(n, max uint64, what string) bool {
+	if d.err != nil {
+		return false
+	}
+	if n > max {
+		d.err = fmt.Errorf("%w: too many %s (%d; max is %d)", ErrDecodeLimit, what, n, max)
+		return false
+	}
+	d.elements += n
+	if d.opts.MaxElements > 0 && d.elements > uint64(d.opts.MaxElements) {
+		d.err = fmt.Errorf("%w: more than %d elements", ErrDecodeLimit, d.opts.MaxElements)
+		return false
+	}
+	return true
+}
+
+// decodeCapacity returns the capacity to allocate for n elements that are
+// still to be read. Larger slices grow as their elements are read, so that
+// a corrupt count cannot allocate more memory than the input holds.
+func decodeCapacity(n uint64) int {
+	const maxCapacity = 1024
+	if n > maxCapacity {
+		return maxCapacity
+	}
+	return int(n)
 }
 
 // Get a buffer of size 8, to avoid allocating over and over.
@@ -162,7 +312,7 @@ func (d *decoder) readBool() (x bool) {
 		return
 	}
 	var val int8
-	d.err = binary.Read(d.r, binary.LittleEndian, &val)
+	d.setReadErr(binary.Read(d.r, binary.LittleEndian, &val))
 	return val == 1
 }
 
@@ -170,7 +320,7 @@ func (d *decoder) readInt8() (x int8) {
 	if d.err != nil {
 		return
 	}
-	d.err = binary.Read(d.r, binary.LittleEndian, &x)
+	d.setReadErr(binary.Read(d.r, binary.LittleEndian, &x))
 	return
 }
 
@@ -178,7 +328,7 @@ func (d *decoder) readInt64() (x int64) {
 	if d.err != nil {
 		return
 	}
-	d.err = binary.Read(d.r, binary.LittleEndian, &x)
+	d.setReadErr(binary.Read(d.r, binary.LittleEndian, &x))
 	return
 }
 
@@ -186,7 +336,9 @@ func (d *decoder) readUint8() (x uint8) {
 	if d.err != nil {
 		return
 	}
-	x, d.err = d.r.ReadByte()
+	var err error
+	x, err = d.r.ReadByte()
+	d.setReadErr(err)
 	return
 }
 
@@ -194,7 +346,7 @@ func (d *decoder) readUint32() (x uint32) {
 	if d.err != nil {
 		return
 	}
-	d.err = binary.Read(d.r, binary.LittleEndian, &x)
+	d.setReadErr(binary.Read(d.r, binary.LittleEndian, &x))
 	return
 }
 
@@ -202,7 +354,7 @@ func (d *decoder) readUint64() (x uint64) {
 	if d.err != nil {
 		return
 	}
-	d.err = binary.Read(d.r, binary.LittleEndian, &x)
+	d.setReadErr(binary.Read(d.r, binary.LittleEndian, &x))
 	return
 }
 
@@ -211,14 +363,86 @@ func (d *decoder) readFloat64() float64 {
 		return 0
 	}
 	buf := d.buffer()
-	_, d.err = io.ReadFull(d.r, buf)
+	_, err := io.ReadFull(d.r, buf)
+	d.setReadErr(err)
 	return math.Float64frombits(binary.LittleEndian.Uint64(buf))
 }
 
+// readPoints reads n points, each stored as 3 float64s. The count must have
+// been checked with checkCount.
+func (d *decoder) readPoints(n uint64) []Point {
+	points := make([]Point, 0, decodeCapacity(n))
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		var p Point
+		p.X = d.readFloat64()
+		p.Y = d.readFloat64()
+		p.Z = d.readFloat64()
+		points = append(points, p)
+	}
+	return points
+}
+
+// checkPoints checks that the decoded points have finite coordinates, as
+// all points that can be constructed do. It reports whether they have.
+func (d *decoder) checkPoints(points []Point) bool {
+	if d.err != nil {
+		return false
+	}
+	for i, p := range points {
+		if !isFinitePoint(p) {
+			d.err = fmt.Errorf("%w: point %d has non-finite coordinates %v", ErrDecodeInvalid, i, p)
+			return false
+		}
+	}
+	return true
+}
+
+// isFinitePoint reports whether the coordinates of p are neither infinite nor NaN.
+func isFinitePoint(p Point) bool {
+	return !math.IsNaN(p.X) && !math.IsNaN(p.Y) && !math.IsNaN(p.Z) &&
+		!math.IsInf(p.X, 0) && !math.IsInf(p.Y, 0) && !math.IsInf(p.Z, 0)
+}
+
 func (d *decoder) readUvarint() (x uint64) {
 	if d.err != nil {
 		return
 	}
-	x, d.err = binary.ReadUvarint(d.r)
-	return
+	// This is binary.ReadUvarint, with an overflow reported as an invalid
+	// encoding rather than an unexported error.
+	var s uint
+	for i := 0; i < binary.MaxVarintLen64; i++ {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			d.setReadErr(err)
+			return 0
+		}
+		if b < 0x80 {
+			if i == binary.MaxVarintLen64-1 && b > 1 {
+				break
+			}
+			return x | uint64(b)<<s
+		}
+		x |= uint64(b&0x7f) << s
+		s += 7
+	}
+	d.err = fmt.Errorf("%w: varint overflows a 64-bit integer", ErrDecodeInvalid)
+	return 0
+}
+
+// readBytes reads the next n bytes. The result grows as the bytes arrive, so
+// a corrupt length cannot allocate much more memory than the input holds.
+func (d *decoder) readBytes(n uint64) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > math.MaxInt64 {
+		d.err = fmt.Errorf("%w: too many bytes to read (%d)", ErrDecodeLimit, n)
+		return nil
+	}
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, d.r, int64(n)); err != nil {
+		d.setReadErr(err)
+		return nil
+	}
+	return buf.Bytes()
 }
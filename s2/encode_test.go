@@ -17,6 +17,7 @@ package s2
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -354,3 +355,191 @@ func BenchmarkRectDecode(b *testing.B) {
 		}
 	}
 }
+
+func TestDecodeErrors(t *testing.T) {
+	encoded := func(v encodableRegion) []byte {
+		var buf bytes.Buffer
+		if err := v.Encode(&buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	polygon := encoded(makePolygon("0:0, 0:10, 10:10, 10:0", false))
+	cells := CellUnion{CellIDFromFace(1), CellIDFromFace(2)}
+	origin := OriginPoint()
+
+	tests := []struct {
+		label string
+		have  []byte
+		v     decodableRegion
+		want  error
+	}{
+		{"empty point", nil, new(Point), ErrDecodeTruncated},
+		{"short point", encoded(&origin)[:10], new(Point), ErrDecodeTruncated},
+		{"point version", []byte{2}, new(Point), ErrDecodeVersion},
+		{"rect version", []byte{7}, new(Rect), ErrDecodeVersion},
+		{"invalid cell", []byte{0, 0, 0, 0, 0, 0, 0, 0}, new(Cell), ErrDecodeInvalid},
+		{"cell union count", []byte{1, 0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0}, new(CellUnion), ErrDecodeLimit},
+		{"negative cell union count", []byte{1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, new(CellUnion), ErrDecodeInvalid},
+		{"short cell union", encoded(&cells)[:20], new(CellUnion), ErrDecodeTruncated},
+		{"loop vertices", []byte{1, 0xff, 0xff, 0xff, 0xff}, new(Loop), ErrDecodeLimit},
+		// A loop that declares 10 million vertices, but holds none.
+		{"short loop", []byte{1, 0x80, 0x96, 0x98, 0x00}, new(Loop), ErrDecodeTruncated},
+		{"polygon version", []byte{3}, new(Polygon), ErrDecodeVersion},
+		{"short polygon", polygon[:len(polygon)-1], new(Polygon), ErrDecodeTruncated},
+		{"compressed polygon loops", []byte{4, 10, 0xff, 0xff, 0xff, 0xff, 0x0f}, new(Polygon), ErrDecodeLimit},
+		{"compressed polygon level", []byte{4, 31, 0}, new(Polygon), ErrDecodeInvalid},
+		{"varint overflow", []byte{4, 10, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f}, new(Polygon), ErrDecodeInvalid},
+		{"short polyline", []byte{1, 2, 0, 0, 0}, new(Polyline), ErrDecodeTruncated},
+		{"polyline version", []byte{0}, new(Polyline), ErrDecodeVersion},
+		{"compressed polyline level", []byte{2, 31, 0}, new(Polyline), ErrDecodeInvalid},
+		{"compressed polyline vertices", []byte{2, 10, 0xff, 0xff, 0xff, 0xff, 0x0f}, new(Polyline), ErrDecodeLimit},
+		{"point vector format", []byte{5}, new(PointVector), ErrDecodeVersion},
+		{"NaN point", []byte{1, 0, 0, 0, 0, 0, 0, 0xf8, 0x7f, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, new(Point), ErrDecodeInvalid},
+		{"infinite polyline vertex", []byte{1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xf0, 0x7f, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, new(Polyline), ErrDecodeInvalid},
+	}
+	for _, test := range tests {
+		err := test.v.Decode(bytes.NewReader(test.have))
+		if !errors.Is(err, test.want) {
+			t.Errorf("%s: Decode(%x) = %v, want %v", test.label, test.have, err, test.want)
+		}
+	}
+}
+
+func TestDecodeOptions(t *testing.T) {
+	var buf bytes.Buffer
+	polygon := makePolygon("0:0, 0:10, 10:10, 10:0; 1:1, 1:2, 2:2, 2:1", false)
+	if err := polygon.Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+
+	tests := []struct {
+		opts DecodeOptions
+		want error
+	}{
+		{DecodeOptions{}, nil},
+		{DecodeOptions{MaxElements: 10, MaxBytes: int64(len(encoded))}, nil},
+		// Two loops and eight vertices.
+		{DecodeOptions{MaxElements: 9}, ErrDecodeLimit},
+		{DecodeOptions{MaxBytes: int64(len(encoded) - 1)}, ErrDecodeLimit},
+	}
+	for _, test := range tests {
+		var got Polygon
+		err := test.opts.Decode(bytes.NewReader(encoded), &got)
+		if !errors.Is(err, test.want) {
+			t.Errorf("%+v.Decode() = %v, want %v", test.opts, err, test.want)
+			continue
+		}
+		if err == nil && (got.NumLoops() != 2 || got.numVertices != 8 || !got.ContainsPoint(PointFromLatLng(LatLngFromDegrees(5, 5)))) {
+			t.Errorf("%+v.Decode() = %v, want %v", test.opts, &got, polygon)
+		}
+	}
+
+	if err := (DecodeOptions{}).Decode(bytes.NewReader(encoded), polygon.Loop(0).Vertex(0)); err == nil {
+		t.Errorf("DecodeOptions.Decode into a Point value succeeded")
+	}
+}
+
+// fuzzDecode fuzzes the decoding of the values returned by newValue, starting
+// from the encodings of the seeds. Decoding must not panic, must fail with one
+// of the decode errors, and must succeed or fail in the same way with limits
+// that the input does not exceed.
+func fuzzDecode(f *testing.F, newValue func() decodableRegion, seeds ...interface{}) {
+	for _, seed := range seeds {
+		switch seed := seed.(type) {
+		case string:
+			b, err := hex.DecodeString(seed)
+			if err != nil {
+				f.Fatal(err)
+			}
+			f.Add(b)
+		case encodableRegion:
+			var buf bytes.Buffer
+			if err := seed.Encode(&buf); err != nil {
+				f.Fatal(err)
+			}
+			f.Add(buf.Bytes())
+		case []byte:
+			f.Add(seed)
+		}
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v := newValue()
+		err := v.Decode(bytes.NewReader(data))
+		if err != nil && !errors.Is(err, ErrDecodeTruncated) && !errors.Is(err, ErrDecodeVersion) &&
+			!errors.Is(err, ErrDecodeLimit) && !errors.Is(err, ErrDecodeInvalid) {
+			t.Fatalf("Decode(%x) returned an untyped error: %v", data, err)
+		}
+
+		opts := DecodeOptions{MaxElements: 1 << 20, MaxBytes: int64(len(data))}
+		if errLimited := opts.Decode(bytes.NewReader(data), newValue()); (errLimited == nil) != (err == nil) && !errors.Is(errLimited, ErrDecodeLimit) {
+			t.Fatalf("Decode(%x) = %v, but with %+v = %v", data, err, opts, errLimited)
+		}
+		if err == nil {
+			if e, ok := v.(encodableRegion); ok {
+				e.Encode(io.Discard)
+			}
+		}
+	})
+}
+
+func FuzzDecodeCap(f *testing.F) {
+	fuzzDecode(f, func() decodableRegion { return new(Cap) }, encodedCapEmpty, encodedCapFull, encodedCapFromPoint)
+}
+
+func FuzzDecodeCell(f *testing.F) {
+	fuzzDecode(f, func() decodableRegion { return new(Cell) }, encodedCellFromPoint, encodedCellFace0, encodedCellIDInvalid)
+}
+
+func FuzzDecodeCellID(f *testing.F) {
+	fuzzDecode(f, func() decodableRegion { return new(CellID) }, encodedCellIDFace0, encodedCellIDFacePosLevel)
+}
+
+func FuzzDecodeCellUnion(f *testing.F) {
+	fuzzDecode(f, func() decodableRegion { return new(CellUnion) }, encodedCellUnionEmpty, encodedCellUnionFromCells)
+}
+
+func FuzzDecodeLoop(f *testing.F) {
+	fuzzDecode(f, func() decodableRegion { return new(Loop) }, encodedLoopEmpty, encodedLoopFull, encodedLoopCross)
+}
+
+func FuzzDecodePoint(f *testing.F) {
+	fuzzDecode(f, func() decodableRegion { return new(Point) }, encodedPointOrigin, encodedPointTesting)
+}
+
+func FuzzDecodePolygon(f *testing.F) {
+	snapped := makePolygon("0:0, 0:10, 10:10, 10:0; 1:1, 1:2, 2:2, 2:1", false)
+	for _, l := range snapped.loops {
+		for i, v := range l.vertices {
+			l.vertices[i] = cellIDFromPoint(v).Parent(20).Point()
+		}
+	}
+	fuzzDecode(f, func() decodableRegion { return new(Polygon) },
+		encodedPolygonEmpty, encodedPolygonFull, encodedPolygon1Loops, encodedPolygon2Loops,
+		PolygonFromLoops([]*Loop{LoopFromPoints(snapped.loops[0].vertices), LoopFromPoints(snapped.loops[1].vertices)}))
+}
+
+func FuzzDecodePolyline(f *testing.F) {
+	fuzzDecode(f, func() decodableRegion { return new(Polyline) }, encodedPolylineEmpty, makePolyline("0:0, 0:10, 10:20"),
+		makePolyline("0:0, 0:10, 10:20, 10:10").Snapped(20))
+}
+
+func FuzzDecodeRect(f *testing.F) {
+	fuzzDecode(f, func() decodableRegion { return new(Rect) }, encodedRectEmpty, encodedRectFull, encodedRectCentersize)
+}
+
+func FuzzDecodePointVector(f *testing.F) {
+	fuzzDecode(f, func() decodableRegion { return new(PointVector) },
+		&PointVector{PointFromCoords(1, 2, 3)}, &PointVector{CellIDFromFace(1).Point(), CellIDFromFace(2).ChildBeginAtLevel(5).Point()})
+}
+
+func FuzzDecodeEncodedCellIDVector(f *testing.F) {
+	fuzzDecode(f, func() decodableRegion { return new(EncodedCellIDVector) },
+		[]byte{0x00, 0x00}, []byte{0x03, 0x12, 0x34, 0x56, 0x10, 0x01, 0x02})
+}
+
+func FuzzDecodeEncodedPointVector(f *testing.F) {
+	fuzzDecode(f, func() decodableRegion { return new(EncodedPointVector) },
+		&PointVector{PointFromCoords(1, 2, 3)}, &PointVector{CellIDFromFace(1).Point(), CellIDFromFace(2).ChildBeginAtLevel(5).Point()})
+}
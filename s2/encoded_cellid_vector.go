@@ -0,0 +1,192 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"fmt"
+	"io"
+)
+
+// EncodedCellIDVector is a vector of CellIDs that is accessed directly in
+// its compressed encoding, as written by CellUnion.EncodeCompressed. Cells
+// can be looked up by index in constant time, and sorted vectors can be
+// binary searched, without decoding the whole vector.
+//
+// Each CellID is stored as a delta from a base value shared by the whole
+// vector, shifted right to drop the low order bits that all of the cells
+// have in common, using the smallest number of bytes that holds the largest
+// delta. Cells that are close together and at similar levels, such as the
+// cells of a covering, therefore take much less than 8 bytes each.
+//
+// The encoding is compatible with the C++ EncodedS2CellIdVector.
+type EncodedCellIDVector struct {
+	base   uint64
+	shift  uint
+	deltas encodedUintVector
+}
+
+// maxCellIDVectorShift is the largest shift used by the encoding.
+const maxCellIDVectorShift = 57
+
+func encodeCellIDVector(e *encoder, ids []CellID) {
+	// Every value is encoded as (base + delta << shift). The shift is even,
+	// unless all of the cells are at the same level, in which case the
+	// lowest set bit is implied and an odd shift drops it as well.
+	var vOr, vMax uint64
+	vAnd, vMin := ^uint64(0), ^uint64(0)
+	for _, id := range ids {
+		vOr |= uint64(id)
+		vAnd &= uint64(id)
+		if uint64(id) < vMin {
+			vMin = uint64(id)
+		}
+		if uint64(id) > vMax {
+			vMax = uint64(id)
+		}
+	}
+
+	var base uint64
+	var baseLen, shift int
+	if vOr > 0 {
+		shift = findLSBSetNonZero64(vOr) &^ 1
+		if shift > maxCellIDVectorShift-1 {
+			shift = maxCellIDVectorShift - 1
+		}
+		if vAnd&(1<<uint(shift)) != 0 {
+			shift++
+		}
+
+		// The base consists of the baseLen most significant bytes of the
+		// smallest CellID. Choose the length that minimizes the total size.
+		maxDeltaMSB := 0
+		bestBytes := ^uint64(0)
+		for n := 0; n < 8; n++ {
+			tBase := vMin &^ (^uint64(0) >> uint(8*n))
+			tMaxDeltaMSB := 0
+			if delta := (vMax - tBase) >> uint(shift); delta > 0 {
+				tMaxDeltaMSB = findMSBSetNonZero64(delta)
+			}
+			tBytes := uint64(n) + uint64(len(ids))*uint64(tMaxDeltaMSB>>3+1)
+			if tBytes < bestBytes {
+				base, baseLen, maxDeltaMSB, bestBytes = tBase, n, tMaxDeltaMSB, tBytes
+			}
+		}
+		// Odd shifts take an extra byte to encode, so use an even shift if
+		// the deltas still need the same number of bytes.
+		if shift&1 != 0 && maxDeltaMSB&7 != 7 {
+			shift--
+		}
+	}
+
+	// The shift and the base length are encoded in 1 or 2 bytes. The shift
+	// code is 5 bits: values up to 28 are even shifts (shift = code * 2) and
+	// values 29 and up are odd shifts (shift = (code - 29) * 2 + 1), where 31
+	// means the shift is stored in the next byte.
+	shiftCode := shift >> 1
+	if shift&1 != 0 {
+		shiftCode += 29
+		if shiftCode > 31 {
+			shiftCode = 31
+		}
+	}
+	e.writeUint8(uint8(shiftCode<<3 | baseLen))
+	if shiftCode == 31 {
+		e.writeUint8(uint8(shift >> 1))
+	}
+	var buf [8]byte
+	putUintWithLength(buf[:], base>>uint(64-8*maxInt(1, baseLen)), baseLen)
+	e.writeBytes(buf[:baseLen])
+
+	deltas := make([]uint64, len(ids))
+	for i, id := range ids {
+		deltas[i] = (uint64(id) - base) >> uint(shift)
+	}
+	encodeUintVector(e, deltas)
+}
+
+// Decode reads a vector written by CellUnion.EncodeCompressed. The cells
+// themselves are not decoded until they are accessed.
+func (v *EncodedCellIDVector) Decode(r io.Reader) error {
+	d := newDecoder(r, DecodeOptions{})
+	v.decode(d)
+	return d.err
+}
+
+func (v *EncodedCellIDVector) decode(d *decoder) {
+	codePlusLen := d.readUint8()
+	shiftCode := int(codePlusLen >> 3)
+	if shiftCode == 31 {
+		shiftCode = 29 + int(d.readUint8())
+	}
+	baseLen := int(codePlusLen & 7)
+	baseBytes := d.readBytes(uint64(baseLen))
+	if d.err != nil {
+		return
+	}
+	base := uintWithLength(baseBytes, baseLen) << uint(64-8*maxInt(1, baseLen))
+
+	var shift uint
+	if shiftCode >= 29 {
+		shift = uint(2*(shiftCode-29) + 1)
+		base |= 1 << (shift - 1)
+	} else {
+		shift = uint(2 * shiftCode)
+	}
+	if shift > maxCellIDVectorShift {
+		d.err = fmt.Errorf("%w: CellID vector shift %d; max is %d", ErrDecodeInvalid, shift, maxCellIDVectorShift)
+		return
+	}
+
+	var deltas encodedUintVector
+	deltas.decode(d)
+	if d.err != nil {
+		return
+	}
+	*v = EncodedCellIDVector{base: base, shift: shift, deltas: deltas}
+}
+
+// Len returns the number of cells in the vector.
+func (v *EncodedCellIDVector) Len() int { return v.deltas.size }
+
+// At returns the i-th CellID in the vector.
+func (v *EncodedCellIDVector) At(i int) CellID {
+	return CellID(v.deltas.get(i)<<v.shift + v.base)
+}
+
+// LowerBound returns the index of the first CellID in the vector that is
+// greater than or equal to target, or Len if there is no such CellID. The
+// vector must be sorted, as the cells of a normalized CellUnion are.
+func (v *EncodedCellIDVector) LowerBound(target CellID) int {
+	// Search the deltas directly for the smallest delta that decodes to at
+	// least target, which is (target - base) >> shift rounded up.
+	id := uint64(target)
+	if id <= v.base {
+		return 0
+	}
+	delta := (id - v.base) >> v.shift
+	if (id-v.base)&(1<<v.shift-1) != 0 {
+		delta++
+	}
+	return v.deltas.lowerBound(delta)
+}
+
+// CellIDs returns all of the cells in the vector.
+func (v *EncodedCellIDVector) CellIDs() []CellID {
+	ids := make([]CellID, v.Len())
+	for i := range ids {
+		ids[i] = v.At(i)
+	}
+	return ids
+}
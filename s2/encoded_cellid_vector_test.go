@@ -0,0 +1,150 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+)
+
+// encodeDecodeCellIDVector encodes the cells and decodes them as an
+// EncodedCellIDVector, returning the vector and the size of the encoding.
+func encodeDecodeCellIDVector(t *testing.T, ids []CellID) (*EncodedCellIDVector, int) {
+	t.Helper()
+	var buf bytes.Buffer
+	cu := CellUnion(ids)
+	if err := cu.EncodeCompressed(&buf); err != nil {
+		t.Fatalf("EncodeCompressed(%v) failed: %v", ids, err)
+	}
+	size := buf.Len()
+	var v EncodedCellIDVector
+	if err := v.Decode(&buf); err != nil {
+		t.Fatalf("Decode of %v failed: %v", ids, err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Decode of %v left %d bytes unread", ids, buf.Len())
+	}
+	return &v, size
+}
+
+func TestEncodedCellIDVector(t *testing.T) {
+	sentinel := CellID(^uint64(0))
+	tests := []struct {
+		ids      []CellID
+		wantSize int
+	}{
+		{nil, 2},
+		{[]CellID{0}, 3},
+		{[]CellID{0, 0}, 4},
+		{[]CellID{sentinel}, 10},
+		{[]CellID{sentinel, sentinel}, 11},
+		{[]CellID{0, sentinel, 0}, 26},
+		// Cells with an invalid lowest bit can be encoded.
+		{[]CellID{0x6, 0xe, 0x7e}, 5},
+		{[]CellID{0x3}, 3},
+		{[]CellID{0xc}, 3},
+		{[]CellID{0x30}, 3},
+		{[]CellID{0xc0}, 3},
+		// A level 2 cell, which needs the largest shift.
+		{[]CellID{CellIDFromFace(3).ChildBeginAtLevel(2).Next()}, 3},
+		{[]CellID{CellIDFromFace(0), CellIDFromFace(1), CellIDFromFace(2), CellIDFromFace(3), CellIDFromFace(4), CellIDFromFace(5)}, 8},
+		{[]CellID{CellIDFromFace(0).ChildBeginAtLevel(maxLevel), CellIDFromFace(5).ChildBeginAtLevel(maxLevel)}, 18},
+	}
+	for _, test := range tests {
+		v, size := encodeDecodeCellIDVector(t, test.ids)
+		if size != test.wantSize {
+			t.Errorf("encoding of %v has %d bytes, want %d", test.ids, size, test.wantSize)
+		}
+		if v.Len() != len(test.ids) {
+			t.Errorf("decoded %v has %d cells", test.ids, v.Len())
+		}
+		for i, want := range test.ids {
+			if got := v.At(i); got != want {
+				t.Errorf("decoded %v: At(%d) = %v, want %v", test.ids, i, got, want)
+			}
+		}
+	}
+}
+
+func TestEncodedCellIDVectorCoverings(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		// A covering of a random cap, which is normalized and sorted.
+		c := CapFromCenterAngle(randomPoint(), kmToAngle(float64(1+randomUniformInt(1000))))
+		rc := &RegionCoverer{MaxLevel: maxLevel, MaxCells: 1 + randomUniformInt(500)}
+		covering := rc.Covering(c)
+
+		v, size := encodeDecodeCellIDVector(t, covering)
+		if size >= 8*len(covering)+2 {
+			t.Errorf("encoding of a covering with %d cells has %d bytes, want fewer than %d", len(covering), size, 8*len(covering)+2)
+		}
+		if got := CellUnion(v.CellIDs()); !got.Equal(covering) {
+			t.Errorf("decoded covering = %v, want %v", got, covering)
+		}
+
+		for j := 0; j < 20; j++ {
+			var target CellID
+			switch j {
+			case 0:
+				target = 0
+			case 1:
+				target = CellID(^uint64(0))
+			case 2, 3, 4:
+				target = covering[randomUniformInt(len(covering))]
+			default:
+				target = randomCellID()
+			}
+			want := sort.Search(len(covering), func(k int) bool { return covering[k] >= target })
+			if got := v.LowerBound(target); got != want {
+				t.Errorf("LowerBound(%v) = %d, want %d", target, got, want)
+			}
+		}
+	}
+}
+
+func TestCellUnionEncodeDecodeCompressed(t *testing.T) {
+	for _, cu := range []CellUnion{nil, randomCellUnion(100), {CellIDFromFace(1), CellIDFromFace(0)}} {
+		var buf bytes.Buffer
+		if err := cu.EncodeCompressed(&buf); err != nil {
+			t.Fatalf("EncodeCompressed(%v) failed: %v", cu, err)
+		}
+		var got CellUnion
+		if err := got.DecodeCompressed(&buf); err != nil {
+			t.Fatalf("DecodeCompressed failed: %v", err)
+		}
+		if !got.Equal(cu) {
+			t.Errorf("DecodeCompressed(EncodeCompressed(%v)) = %v", cu, got)
+		}
+	}
+}
+
+func TestEncodedCellIDVectorDecodeErrors(t *testing.T) {
+	for _, have := range [][]byte{
+		{},
+		// A base of 3 bytes with only 2 present.
+		{0x03, 0x01, 0x02},
+		// No deltas.
+		{0x00},
+		// An extended shift code of 61.
+		{0xf8, 30, 0x00},
+		// Two deltas of one byte with only one present.
+		{0x00, 2 * 8, 0x01},
+	} {
+		var v EncodedCellIDVector
+		if err := v.Decode(bytes.NewReader(have)); err == nil {
+			t.Errorf("Decode(%x) succeeded, want an error", have)
+		}
+	}
+}
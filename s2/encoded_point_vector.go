@@ -0,0 +1,291 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/rubenpoppe/geo/r3"
+)
+
+// The formats of an encoded point vector.
+const (
+	// encodedPointVectorUncompressed stores every point as 3 float64s.
+	encodedPointVectorUncompressed = uint8(0)
+	// encodedPointVectorCellIDs stores the points snapped to the centers of
+	// cells at one level as an EncodedCellIDVector, and the other points
+	// as exceptions.
+	encodedPointVectorCellIDs = uint8(1)
+)
+
+// encodedPointSize is the size of a point stored as 3 float64s.
+const encodedPointSize = 3 * 8
+
+// EncodedPointVector is a vector of Points that is accessed directly in its
+// encoding, as written by PointVector.Encode. Points can be looked up by index
+// without decoding the whole vector.
+//
+// If most of the points are the centers of cells at one level, as points
+// snapped to a grid are, they are stored as the CellIDs of those cells in an
+// EncodedCellIDVector, and decoded from their (face, si, ti) coordinates in
+// the same way as the compressed vertices of loops and polygons. The other
+// points are stored exactly, as exceptions, and their indexes are kept in a
+// sorted list. Looking up a point takes constant time when there are no
+// exceptions, and logarithmic time in the number of exceptions otherwise.
+// Vectors with few snapped points store every point exactly.
+//
+// The encoding is:
+//
+//	byte: format (0 = uncompressed, 1 = cell IDs)
+//	format 0:
+//	  varint64: number of points
+//	  array of points, each 3 little-endian float64s (x, y, z)
+//	format 1:
+//	  byte: snap level
+//	  EncodedCellIDVector: a cell at the snap level for every point
+//	  encodedUintVector: sorted indexes of the exceptions
+//	  array of exceptions, each 3 little-endian float64s (x, y, z)
+type EncodedPointVector struct {
+	format uint8
+	size   int
+	// points holds every point for the uncompressed format, and the
+	// exceptions for the cell IDs format.
+	points []byte
+
+	// Used by the cell IDs format only.
+	level      int
+	cellIDs    EncodedCellIDVector
+	exceptions encodedUintVector
+}
+
+// encodePointVector encodes the points, choosing the format from the
+// levels at which the points are snapped.
+func encodePointVector(e *encoder, points []Point) {
+	// Compute a histogram of the cell levels at which the points are snapped
+	// (histogram[0] is the number of unsnapped points, histogram[i] the number
+	// of points snapped at level i-1).
+	vertices := make([]xyzFaceSiTi, len(points))
+	histogram := make([]int, maxLevel+2)
+	for i, p := range points {
+		vertices[i].xyz = p
+		vertices[i].face, vertices[i].si, vertices[i].ti, vertices[i].level = xyzToFaceSiTi(p)
+		histogram[vertices[i].level+1]++
+	}
+
+	// If several levels have the most snapped points, choose the lowest one,
+	// since its cells take the fewest bits.
+	var snapLevel, numSnapped int
+	for level, h := range histogram[1:] {
+		if h > numSnapped {
+			snapLevel, numSnapped = level, h
+		}
+	}
+
+	// Choose a format based on a rough estimate of the encoded sizes.
+	numUnsnapped := len(points) - numSnapped
+	compressedSize := 8*len(points) + (encodedPointSize+2)*numUnsnapped
+	if compressedSize < encodedPointSize*len(points) {
+		encodePointVectorCellIDs(e, snapLevel, vertices)
+	} else {
+		encodePointVectorUncompressed(e, points)
+	}
+}
+
+func encodePointVectorUncompressed(e *encoder, points []Point) {
+	e.writeUint8(encodedPointVectorUncompressed)
+	e.writeUvarint(uint64(len(points)))
+	e.writeBytes(appendEncodedPoints(nil, points))
+}
+
+func encodePointVectorCellIDs(e *encoder, level int, vertices []xyzFaceSiTi) {
+	ids := make([]CellID, len(vertices))
+	var exceptions []uint64
+	var exceptionPoints []Point
+	for i, v := range vertices {
+		if v.level == level {
+			ids[i] = cellIDFromFaceIJ(v.face, int(v.si>>1), int(v.ti>>1)).Parent(level)
+			continue
+		}
+		// The cell containing the point keeps the deltas of the cell IDs
+		// small, although its value is never used.
+		ids[i] = cellIDFromPoint(v.xyz).Parent(level)
+		exceptions = append(exceptions, uint64(i))
+		exceptionPoints = append(exceptionPoints, v.xyz)
+	}
+
+	e.writeUint8(encodedPointVectorCellIDs)
+	e.writeUint8(uint8(level))
+	encodeCellIDVector(e, ids)
+	encodeUintVector(e, exceptions)
+	e.writeBytes(appendEncodedPoints(nil, exceptionPoints))
+}
+
+// appendEncodedPoints appends the points to b as 3 little-endian float64s each.
+func appendEncodedPoints(b []byte, points []Point) []byte {
+	var buf [encodedPointSize]byte
+	for _, p := range points {
+		binary.LittleEndian.PutUint64(buf[0:], math.Float64bits(p.X))
+		binary.LittleEndian.PutUint64(buf[8:], math.Float64bits(p.Y))
+		binary.LittleEndian.PutUint64(buf[16:], math.Float64bits(p.Z))
+		b = append(b, buf[:]...)
+	}
+	return b
+}
+
+// encodedPoint returns the i-th point stored as 3 little-endian float64s in b.
+func encodedPoint(b []byte, i int) Point {
+	b = b[i*encodedPointSize:]
+	return Point{r3.Vector{
+		X: math.Float64frombits(binary.LittleEndian.Uint64(b[0:])),
+		Y: math.Float64frombits(binary.LittleEndian.Uint64(b[8:])),
+		Z: math.Float64frombits(binary.LittleEndian.Uint64(b[16:])),
+	}}
+}
+
+// checkEncodedPoints checks that the points stored in b have finite
+// coordinates, as decoder.checkPoints does.
+func checkEncodedPoints(d *decoder, b []byte) bool {
+	if d.err != nil {
+		return false
+	}
+	for i := 0; i < len(b)/encodedPointSize; i++ {
+		if p := encodedPoint(b, i); !isFinitePoint(p) {
+			d.err = fmt.Errorf("%w: point %d has non-finite coordinates %v", ErrDecodeInvalid, i, p)
+			return false
+		}
+	}
+	return true
+}
+
+// Decode reads a vector written by PointVector.Encode. The points
+// themselves are not decoded until they are accessed.
+func (v *EncodedPointVector) Decode(r io.Reader) error {
+	d := newDecoder(r, DecodeOptions{})
+	v.decode(d)
+	return d.err
+}
+
+func (v *EncodedPointVector) decode(d *decoder) {
+	format := d.readUint8()
+	if d.err != nil {
+		return
+	}
+	switch format {
+	case encodedPointVectorUncompressed:
+		v.decodeUncompressed(d)
+	case encodedPointVectorCellIDs:
+		v.decodeCellIDs(d)
+	default:
+		d.err = fmt.Errorf("%w: unknown point vector format %d", ErrDecodeVersion, format)
+	}
+}
+
+func (v *EncodedPointVector) decodeUncompressed(d *decoder) {
+	n := d.readUvarint()
+	if d.err != nil {
+		return
+	}
+	if !d.checkCount(n, maxEncodedVertices, "points") {
+		return
+	}
+	points := d.readBytes(n * encodedPointSize)
+	if !checkEncodedPoints(d, points) {
+		return
+	}
+	*v = EncodedPointVector{format: encodedPointVectorUncompressed, size: int(n), points: points}
+}
+
+func (v *EncodedPointVector) decodeCellIDs(d *decoder) {
+	level := int(d.readUint8())
+	if d.err != nil {
+		return
+	}
+	if level > maxLevel {
+		d.err = fmt.Errorf("%w: snap level too big: %d", ErrDecodeInvalid, level)
+		return
+	}
+	var ids EncodedCellIDVector
+	ids.decode(d)
+	var exceptions encodedUintVector
+	exceptions.decode(d)
+	if d.err != nil {
+		return
+	}
+	if ids.Len() > maxEncodedVertices {
+		d.err = fmt.Errorf("%w: too many points (%d; max is %d)", ErrDecodeLimit, ids.Len(), maxEncodedVertices)
+		return
+	}
+	if exceptions.size > ids.Len() {
+		d.err = fmt.Errorf("%w: %d exceptions for %d points", ErrDecodeInvalid, exceptions.size, ids.Len())
+		return
+	}
+	points := d.readBytes(uint64(exceptions.size) * encodedPointSize)
+	if !checkEncodedPoints(d, points) {
+		return
+	}
+
+	// Check the vector once, so that accessing it never fails.
+	for i := 0; i < ids.Len(); i++ {
+		if id := ids.At(i); !id.IsValid() || id.Level() != level {
+			d.err = fmt.Errorf("%w: point %d is encoded as %v, which is not a valid cell at level %d", ErrDecodeInvalid, i, id, level)
+			return
+		}
+	}
+	for k := 0; k < exceptions.size; k++ {
+		i := exceptions.get(k)
+		if i >= uint64(ids.Len()) || (k > 0 && i <= exceptions.get(k-1)) {
+			d.err = fmt.Errorf("%w: exception %d has an invalid index %d", ErrDecodeInvalid, k, i)
+			return
+		}
+	}
+
+	*v = EncodedPointVector{
+		format:     encodedPointVectorCellIDs,
+		size:       ids.Len(),
+		points:     points,
+		level:      level,
+		cellIDs:    ids,
+		exceptions: exceptions,
+	}
+}
+
+// Len returns the number of points in the vector.
+func (v *EncodedPointVector) Len() int { return v.size }
+
+// At returns the i-th point in the vector.
+func (v *EncodedPointVector) At(i int) Point {
+	if v.format == encodedPointVectorUncompressed {
+		return encodedPoint(v.points, i)
+	}
+	if v.exceptions.size > 0 {
+		if k := v.exceptions.lowerBound(uint64(i)); k < v.exceptions.size && v.exceptions.get(k) == uint64(i) {
+			return encodedPoint(v.points, k)
+		}
+	}
+	face, si, ti := v.cellIDs.At(i).faceSiTi()
+	return Point{faceSiTiToXYZ(face, si, ti).Normalize()}
+}
+
+// Points returns all of the points in the vector.
+func (v *EncodedPointVector) Points() []Point {
+	points := make([]Point, v.size)
+	for i := range points {
+		points[i] = v.At(i)
+	}
+	return points
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncodedPointVector(t *testing.T) {
+	randomPoints := make([]Point, 50)
+	for i := range randomPoints {
+		randomPoints[i] = randomPoint()
+	}
+	leaves := makeSnappedPoints(100, maxLevel)
+	for i, p := range leaves {
+		leaves[i] = cellIDFromPoint(p).Point()
+	}
+	mixed := append([]Point(nil), leaves...)
+	for i := 0; i < 15; i++ {
+		mixed[3*i] = randomPoint()
+	}
+	mixedLevels := makeSnappedPoints(100, 20)
+	for i := 0; i < 10; i++ {
+		mixedLevels[5*i] = CellFromPoint(mixedLevels[5*i]).ID().Parent(12).Point()
+	}
+
+	// The largest size of the headers of the cell IDs format.
+	const headerSize = 12
+	tests := []struct {
+		label      string
+		points     []Point
+		wantFormat uint8
+		maxSize    int
+	}{
+		{"empty", nil, encodedPointVectorUncompressed, 2},
+		{"random", randomPoints, encodedPointVectorUncompressed, 2 + 50*encodedPointSize},
+		{"snapped leaf cells", leaves, encodedPointVectorCellIDs, headerSize + 100*8},
+		{"snapped level 14", makeSnappedPoints(100, 14), encodedPointVectorCellIDs, headerSize + 100*4},
+		{"mixed", mixed, encodedPointVectorCellIDs, headerSize + 100*8 + 15*(encodedPointSize+1)},
+		{"mixed levels", mixedLevels, encodedPointVectorCellIDs, headerSize + 100*8 + 10*(encodedPointSize+1)},
+		{"single snapped", []Point{CellIDFromFace(2).Point()}, encodedPointVectorCellIDs, headerSize},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		pv := PointVector(test.points)
+		if err := pv.Encode(&buf); err != nil {
+			t.Fatalf("%s: Encode failed: %v", test.label, err)
+		}
+		if buf.Len() > test.maxSize {
+			t.Errorf("%s: encoding has %d bytes, want at most %d", test.label, buf.Len(), test.maxSize)
+		}
+
+		var v EncodedPointVector
+		if err := v.Decode(&buf); err != nil {
+			t.Fatalf("%s: Decode failed: %v", test.label, err)
+		}
+		if v.format != test.wantFormat {
+			t.Errorf("%s: format = %d, want %d", test.label, v.format, test.wantFormat)
+		}
+		if v.Len() != len(test.points) {
+			t.Errorf("%s: Len() = %d, want %d", test.label, v.Len(), len(test.points))
+		}
+		// The points are decoded exactly.
+		for i, want := range test.points {
+			if got := v.At(i); got != want {
+				t.Errorf("%s: At(%d) = %v, want %v", test.label, i, got, want)
+			}
+		}
+	}
+}
+
+func TestPointVectorEncodeDecode(t *testing.T) {
+	for _, points := range [][]Point{nil, makeSnappedPoints(10, 5), {randomPoint(), randomPoint()}} {
+		var buf bytes.Buffer
+		pv := PointVector(points)
+		if err := pv.Encode(&buf); err != nil {
+			t.Fatalf("Encode(%v) failed: %v", points, err)
+		}
+		var got PointVector
+		if err := got.Decode(&buf); err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		if len(got) != len(points) {
+			t.Fatalf("Decode(Encode(%v)) = %v", points, got)
+		}
+		for i := range points {
+			if got[i] != points[i] {
+				t.Errorf("Decode(Encode(%v))[%d] = %v", points, i, got[i])
+			}
+		}
+	}
+}
+
+func TestEncodedPointVectorDecodeErrors(t *testing.T) {
+	// A valid encoding of two level 10 cells without exceptions, to corrupt.
+	var buf bytes.Buffer
+	pv := PointVector{CellIDFromFace(1).ChildBeginAtLevel(10).Point(), CellIDFromFace(1).ChildEndAtLevel(10).Prev().Point()}
+	if err := pv.Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	valid := buf.Bytes()
+	if valid[0] != encodedPointVectorCellIDs || valid[1] != 10 {
+		t.Fatalf("encoding %x does not start with the format and the level", valid)
+	}
+	withLevel := func(level byte) []byte {
+		b := append([]byte(nil), valid...)
+		b[1] = level
+		return b
+	}
+
+	for _, have := range [][]byte{
+		{},
+		{2},
+		// Two uncompressed points, with only one present.
+		append([]byte{0, 2}, make([]byte, encodedPointSize)...),
+		valid[:len(valid)-1],
+		// The cells are not at the snap level.
+		withLevel(11),
+		withLevel(maxLevel + 1),
+		// One exception, at index 2 of 2.
+		append(append([]byte(nil), valid[:len(valid)-1]...), append([]byte{8, 2}, make([]byte, encodedPointSize)...)...),
+	} {
+		var v EncodedPointVector
+		if err := v.Decode(bytes.NewReader(have)); err == nil {
+			t.Errorf("Decode(%x) succeeded, want an error", have)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+)
+
+// encodedUintVector is an encoded vector of uint64 values that is accessed
+// directly in its encoded form. Every value is stored with the same number of
+// bytes, the smallest number that can hold the largest value, which allows
+// random access and binary search without decoding the whole vector.
+//
+// The encoding is compatible with the C++ EncodedUintVector<uint64>:
+//
+//	varint64: (number of values * 8) | (bytes per value - 1)
+//	array of values, each little-endian with "bytes per value" bytes
+type encodedUintVector struct {
+	data []byte
+	size int // number of values
+	len  int // bytes per value, in the range [1, 8]
+}
+
+// encodeUintVector encodes the given values.
+func encodeUintVector(e *encoder, v []uint64) {
+	// Zero bytes per value is not allowed, since the length is stored in 3 bits.
+	oneBits := uint64(1)
+	for _, x := range v {
+		oneBits |= x
+	}
+	n := findMSBSetNonZero64(oneBits)>>3 + 1
+
+	e.writeUvarint(uint64(len(v))*8 | uint64(n-1))
+	buf := make([]byte, len(v)*n)
+	for i, x := range v {
+		putUintWithLength(buf[i*n:], x, n)
+	}
+	e.writeBytes(buf)
+}
+
+// putUintWithLength writes the n low order bytes of x to b in little-endian order.
+func putUintWithLength(b []byte, x uint64, n int) {
+	for i := 0; i < n; i++ {
+		b[i] = byte(x)
+		x >>= 8
+	}
+}
+
+// uintWithLength returns the value of the first n bytes of b in little-endian order.
+func uintWithLength(b []byte, n int) uint64 {
+	if n == 8 {
+		return binary.LittleEndian.Uint64(b)
+	}
+	var x uint64
+	for i := n - 1; i >= 0; i-- {
+		x = x<<8 | uint64(b[i])
+	}
+	return x
+}
+
+func (v *encodedUintVector) decode(d *decoder) {
+	sizeLen := d.readUvarint()
+	if d.err != nil {
+		return
+	}
+	size := sizeLen / 8
+	n := int(sizeLen&7) + 1
+	if !d.checkCount(size, math.MaxInt64/8, "values") {
+		return
+	}
+	data := d.readBytes(size * uint64(n))
+	if d.err != nil {
+		return
+	}
+	*v = encodedUintVector{data: data, size: int(size), len: n}
+}
+
+// get returns the value at position i.
+func (v *encodedUintVector) get(i int) uint64 {
+	return uintWithLength(v.data[i*v.len:], v.len)
+}
+
+// lowerBound returns the index of the first value that is greater than or
+// equal to target, or size if there is no such value. The values must be
+// sorted.
+func (v *encodedUintVector) lowerBound(target uint64) int {
+	return sort.Search(v.size, func(i int) bool { return v.get(i) >= target })
+}
+
+// values returns all of the values in the vector.
+func (v *encodedUintVector) values() []uint64 {
+	result := make([]uint64, v.size)
+	for i := range result {
+		result[i] = v.get(i)
+	}
+	return result
+}
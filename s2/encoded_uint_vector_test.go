@@ -0,0 +1,110 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestEncodedUintVector(t *testing.T) {
+	tests := []struct {
+		values   []uint64
+		wantSize int
+	}{
+		{nil, 1},
+		{[]uint64{0}, 2},
+		{[]uint64{0, 0, 0}, 4},
+		{[]uint64{^uint64(0)}, 9},
+		{[]uint64{0, 255, 1, 254}, 5},
+		{[]uint64{0, 255, 256, 254}, 9},
+		{[]uint64{0, 0xffffff, 0x10000, 0xfeffff}, 13},
+		{[]uint64{^uint64(0), 0, 0x0102030405060708}, 25},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		e := &encoder{w: &buf}
+		encodeUintVector(e, test.values)
+		if e.err != nil {
+			t.Fatalf("encodeUintVector(%v) failed: %v", test.values, e.err)
+		}
+		if buf.Len() != test.wantSize {
+			t.Errorf("encodeUintVector(%v) has %d bytes, want %d", test.values, buf.Len(), test.wantSize)
+		}
+
+		var v encodedUintVector
+		d := &decoder{r: &buf}
+		v.decode(d)
+		if d.err != nil {
+			t.Fatalf("decoding %v failed: %v", test.values, d.err)
+		}
+		if v.size != len(test.values) {
+			t.Errorf("decoded %v has size %d", test.values, v.size)
+		}
+		for i, want := range test.values {
+			if got := v.get(i); got != want {
+				t.Errorf("decoded %v: get(%d) = %d, want %d", test.values, i, got, want)
+			}
+		}
+	}
+}
+
+func TestEncodedUintVectorLowerBound(t *testing.T) {
+	for n := 0; n < 20; n++ {
+		values := make([]uint64, n)
+		for i := range values {
+			values[i] = uint64(randomUniformInt(1 << uint(8*(n%4+1))))
+		}
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+		var buf bytes.Buffer
+		encodeUintVector(&encoder{w: &buf}, values)
+		var v encodedUintVector
+		v.decode(&decoder{r: &buf})
+		if got := v.values(); n > 0 && !reflect.DeepEqual(got, values) {
+			t.Errorf("values() = %v, want %v", got, values)
+		}
+
+		targets := append([]uint64{0, ^uint64(0)}, values...)
+		for _, x := range values {
+			targets = append(targets, x+1, x-1)
+		}
+		for _, target := range targets {
+			want := sort.Search(n, func(i int) bool { return values[i] >= target })
+			if got := v.lowerBound(target); got != want {
+				t.Errorf("lowerBound(%d) in %v = %d, want %d", target, values, got, want)
+			}
+		}
+	}
+}
+
+func TestEncodedUintVectorDecodeErrors(t *testing.T) {
+	for _, have := range [][]byte{
+		{},
+		// Two values of one byte, but only one byte follows.
+		{2 * 8, 1},
+		// A size that overflows.
+		{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01},
+	} {
+		var v encodedUintVector
+		d := &decoder{r: bytes.NewReader(have)}
+		v.decode(d)
+		if d.err == nil {
+			t.Errorf("decoding %x succeeded, want an error", have)
+		}
+	}
+}
@@ -16,7 +16,9 @@ package s2_test
 
 import (
 	"fmt"
+	"math"
 
+	"github.com/rubenpoppe/geo/r2"
 	"github.com/rubenpoppe/geo/s1"
 	"github.com/rubenpoppe/geo/s2"
 )
@@ -294,3 +296,62 @@ func ExampleEdgeQuery_FindEdges_findFurthestEdges() {
 	// Polyline 0, Edge 32 is 27.245 degrees from Point (-0.425124, -0.667311, 0.611527)
 	// Polyline 0, Edge 33 is 26.115 degrees from Point (-0.425124, -0.667311, 0.611527)
 }
+
+// equirectangular is the equirectangular projection in degrees with a
+// standard parallel, where the scale is true along the parallel.
+type equirectangular struct {
+	cosLat float64
+}
+
+func (p equirectangular) Project(pt s2.Point) r2.Point {
+	return p.FromLatLng(s2.LatLngFromPoint(pt))
+}
+
+func (p equirectangular) Unproject(pt r2.Point) s2.Point {
+	return s2.PointFromLatLng(p.ToLatLng(pt))
+}
+
+func (p equirectangular) FromLatLng(ll s2.LatLng) r2.Point {
+	return r2.Point{X: ll.Lng.Degrees() * p.cosLat, Y: ll.Lat.Degrees()}
+}
+
+func (p equirectangular) ToLatLng(pt r2.Point) s2.LatLng {
+	lng := math.Remainder(pt.X/p.cosLat, 360)
+	return s2.LatLngFromDegrees(pt.Y, lng)
+}
+
+func (p equirectangular) Interpolate(f float64, a, b r2.Point) r2.Point {
+	return a.Mul(1 - f).Add(b.Mul(f))
+}
+
+func (p equirectangular) WrapDistance() r2.Point {
+	return r2.Point{X: 360 * p.cosLat}
+}
+
+func (p equirectangular) WrapDestination(a, b r2.Point) r2.Point {
+	if w := p.WrapDistance().X; math.Abs(b.X-a.X) > w/2 {
+		b.X = a.X + math.Remainder(b.X-a.X, w)
+	}
+	return b
+}
+
+func ExampleProjection() {
+	// Projections defined outside the package can be used to tessellate
+	// edges. Here the great circle route from Paris to New York is drawn
+	// on an equirectangular map centered on the latitude of Paris, to
+	// within 0.1 degrees.
+	proj := equirectangular{cosLat: math.Cos(49 * math.Pi / 180)}
+	tess := s2.NewEdgeTessellator(proj, 0.1*s1.Degree)
+
+	paris := s2.PointFromLatLng(s2.LatLngFromDegrees(48.86, 2.35))
+	newYork := s2.PointFromLatLng(s2.LatLngFromDegrees(40.71, -74.01))
+	vertices := tess.AppendProjected(paris, newYork, nil)
+
+	maxLat := 0.0
+	for _, v := range vertices {
+		maxLat = math.Max(maxLat, v.Y)
+	}
+	fmt.Printf("%d vertices, reaching latitude %.1f\n", len(vertices), maxLat)
+	// Output:
+	// 17 vertices, reaching latitude 52.3
+}
@@ -0,0 +1,715 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geojson
+
+import (
+	"math"
+	"sort"
+
+	"github.com/rubenpoppe/geo/r3"
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// Cutting geometry at the antimeridian works on "unwrapped" coordinates: the
+// longitude of each vertex is shifted by a multiple of 360 degrees so that
+// consecutive vertices never differ by more than 180 degrees. In these
+// coordinates every geodesic edge is a continuous curve, and the antimeridian
+// becomes the set of vertical lines x = 180 + 360*k. Geometry is cut along
+// these lines and each piece is shifted back into [-180, 180].
+
+// vertex is a vertex in unwrapped coordinates, with x the unwrapped longitude
+// and y the latitude in degrees.
+type vertex struct {
+	x, y float64
+	// lng is the original longitude of the vertex, which is output instead
+	// of the shifted x to avoid rounding errors. It is NaN for vertices that
+	// were created by cutting, and for the vertices at a pole added by unwrap.
+	lng float64
+	// p is the point on the sphere, which is used to compute where edges
+	// cross the antimeridian.
+	p s2.Point
+}
+
+// output returns the position of the vertex within the strip shifted by
+// 360*k degrees.
+func (v vertex) output(k int) position {
+	x := v.x - 360*float64(k)
+	if !math.IsNaN(v.lng) && math.Abs(v.lng) != 180 {
+		x = v.lng
+	}
+	return position{x, v.y}
+}
+
+// isPole reports whether p is one of the poles.
+func isPole(p s2.Point) bool {
+	return math.Abs(s2.LatLngFromPoint(p).Lat.Radians()) == math.Pi/2
+}
+
+// unwrap returns the vertices of the given chain in unwrapped coordinates.
+// An edge that passes through a pole is represented by two vertices at the
+// pole, one for each longitude. If closed is true the chain is a loop, and
+// the return value shift is the unwrapped longitude of the first vertex when
+// it is reached again at the end of the loop minus its starting longitude.
+// It is nonzero for loops that go around a pole.
+func unwrap(points []s2.Point, closed bool) (vs []vertex, shift float64) {
+	n := len(points)
+	// Start at a vertex that is not a pole, so that the first longitude is
+	// well-defined.
+	start := 0
+	for start < n && isPole(points[start]) {
+		start++
+	}
+	if start == n {
+		for _, p := range points {
+			ll := s2.LatLngFromPoint(p)
+			vs = append(vs, vertex{x: 0, y: ll.Lat.Degrees(), lng: math.NaN(), p: p})
+		}
+		return vs, 0
+	}
+	if closed {
+		points = append(append([]s2.Point(nil), points[start:]...), points[:start]...)
+		start = 0
+	}
+
+	lastLng := s2.LatLngFromPoint(points[start]).Lng.Degrees()
+	lastX := lastLng
+	// atPole is set after a pole vertex has been added, and causes a second
+	// pole vertex to be added at the longitude of the next vertex.
+	atPole := false
+	var pole s2.Point
+	visit := func(p s2.Point, appendIt bool) float64 {
+		ll := s2.LatLngFromPoint(p)
+		if isPole(p) {
+			if appendIt {
+				vs = append(vs, vertex{x: lastX, y: ll.Lat.Degrees(), lng: math.NaN(), p: p})
+			}
+			atPole, pole = true, p
+			return lastX
+		}
+		lng := ll.Lng.Degrees()
+		x := lastX + math.Remainder(lng-lastLng, 360)
+		if atPole && x != lastX {
+			vs = append(vs, vertex{x: x, y: s2.LatLngFromPoint(pole).Lat.Degrees(), lng: math.NaN(), p: pole})
+		}
+		atPole = false
+		if appendIt {
+			vs = append(vs, vertex{x: x, y: ll.Lat.Degrees(), lng: lng, p: p})
+		}
+		lastLng, lastX = lng, x
+		return x
+	}
+	// A polyline that starts at a pole uses the longitude of its first
+	// non-pole vertex.
+	for i := 0; i < start; i++ {
+		visit(points[i], true)
+	}
+	for i := start; i < len(points); i++ {
+		visit(points[i], true)
+	}
+	if !closed {
+		return vs, 0
+	}
+	x0 := vs[0].x
+	// The shift is a multiple of 360 degrees up to rounding errors.
+	return vs, 360 * math.Round((visit(points[0], false)-x0)/360)
+}
+
+// crossing returns the point where the edge from a to b crosses the vertical
+// line x = c. The latitude of the crossing is computed from the geodesic edge.
+func crossing(a, b vertex, c float64) vertex {
+	if a.x == c {
+		return a
+	}
+	if b.x == c {
+		return b
+	}
+	v := vertex{x: c, lng: math.NaN()}
+	// The point on the edge that is on the plane of the meridian, with normal
+	// n, on the side of the direction dir. The antimeridian is handled
+	// separately to avoid rounding errors.
+	n, dir := r3.Vector{X: 0, Y: 1, Z: 0}, r3.Vector{X: -1, Y: 0, Z: 0}
+	if lng := math.Remainder(c, 360); math.Abs(lng) != 180 {
+		sin, cos := math.Sincos(lng * math.Pi / 180)
+		n, dir = r3.Vector{X: -sin, Y: cos, Z: 0}, r3.Vector{X: cos, Y: sin, Z: 0}
+	}
+	p := s2.Point{Vector: a.p.Mul(b.p.Dot(n)).Sub(b.p.Mul(a.p.Dot(n)))}
+	if a.p == b.p || p.Norm() == 0 {
+		// The edge runs along a pole or is degenerate, so interpolate.
+		t := (c - a.x) / (b.x - a.x)
+		v.y = a.y + t*(b.y-a.y)
+		v.p = s2.PointFromLatLng(s2.LatLngFromDegrees(v.y, c))
+		return v
+	}
+	p = s2.Point{Vector: p.Normalize()}
+	if p.Dot(dir) < 0 {
+		p = s2.Point{Vector: p.Mul(-1)}
+	}
+	v.p = p
+	v.y = s2.LatLngFromPoint(p).Lat.Degrees()
+	return v
+}
+
+// strip returns the index k of the strip [360*k-180, 360*k+180] that contains
+// x, rounding towards the middle strip when x is on a boundary.
+func strip(x float64) int {
+	k := math.Floor((x + 180) / 360)
+	if x == 360*k-180 && k > 0 {
+		k--
+	}
+	return int(k)
+}
+
+// cutChain cuts a polyline in unwrapped coordinates at the antimeridian, and
+// returns the pieces as GeoJSON positions.
+func cutChain(vs []vertex) [][]position {
+	if len(vs) == 0 {
+		return nil
+	}
+	var lines [][]position
+	k := strip(vs[0].x)
+	line := []position{vs[0].output(k)}
+	for i := 1; i < len(vs); i++ {
+		a, b := vs[i-1], vs[i]
+		if nk := strip(b.x); nk != k {
+			// Cut the edge at the boundary between the two strips.
+			c := 360*float64(k) + 180
+			if nk < k {
+				c = 360*float64(k) - 180
+			}
+			cut := crossing(a, b, c)
+			line = appendPosition(line, cut.output(k))
+			if len(line) > 1 {
+				lines = append(lines, line)
+			}
+			line = []position{cut.output(nk)}
+			k = nk
+		}
+		line = appendPosition(line, b.output(k))
+	}
+	if len(line) > 1 || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// appendPosition appends p to the line unless it is equal to the last
+// position.
+func appendPosition(line []position, p position) []position {
+	if n := len(line); n > 0 && line[n-1] == p {
+		return line
+	}
+	return append(line, p)
+}
+
+// seamLongitude returns the longitude along which rings around a pole are
+// closed. The seam must not cross the other rings, which are given by their
+// ranges of unwrapped longitudes, so that these can be placed inside the
+// rings around the pole. The antimeridian is preferred because no seam is
+// needed there.
+func seamLongitude(ranges [][2]float64) float64 {
+	free := func(lng float64) bool {
+		for _, r := range ranges {
+			if lng+360*math.Ceil((r[0]-lng)/360) <= r[1] {
+				return false
+			}
+		}
+		return true
+	}
+	for d := 0.0; d < 180; d++ {
+		if free(180 - d) {
+			return 180 - d
+		}
+		if free(-180 + d) {
+			return -180 + d
+		}
+	}
+	return 180
+}
+
+// rotateToSeam returns the vertices of a ring that goes around a pole,
+// starting at the point where it crosses the meridian lng closest to the
+// given pole. The ring is shifted so that it starts at x = lng. This makes
+// sure that the edges added by closeAroundPole do not cross the ring.
+func rotateToSeam(vs []vertex, shift, lng float64, north bool) []vertex {
+	n := len(vs)
+	ext := append(append([]vertex(nil), vs...), vs[0])
+	ext[n].x += shift
+	best, bestEdge := vertex{}, -1
+	for i := 0; i < n; i++ {
+		a, b := ext[i], ext[i+1]
+		lo, hi := math.Min(a.x, b.x), math.Max(a.x, b.x)
+		c := lng + 360*math.Ceil((lo-lng)/360)
+		if c > hi {
+			continue
+		}
+		v := crossing(a, b, c)
+		if bestEdge < 0 || north && v.y > best.y || !north && v.y < best.y {
+			best, bestEdge = v, i
+		}
+	}
+	if bestEdge < 0 {
+		return vs
+	}
+	// The crossing becomes the first vertex. It gets the longitude of the
+	// seam, so that its copies in different strips have the same position.
+	offset := lng - best.x
+	best.x, best.lng = lng, math.Remainder(lng, 360)
+	if best.lng == -180 {
+		best.lng = 180
+	}
+	rotated := []vertex{best}
+	for i := bestEdge + 1; i <= n; i++ {
+		v := ext[i]
+		v.x += offset
+		rotated = appendVertex(rotated, v)
+	}
+	for i := 1; i <= bestEdge; i++ {
+		v := ext[i]
+		v.x += offset + shift
+		rotated = appendVertex(rotated, v)
+	}
+	return rotated
+}
+
+// closeAroundPole closes a ring that goes around a pole by adding vertices at
+// the given pole, so that it becomes a simple ring in unwrapped coordinates.
+// The ring is counterclockwise if the pole is on its left, and clockwise
+// otherwise, as for a hole around the pole.
+func closeAroundPole(vs []vertex, shift float64, north bool) []vertex {
+	if shift == 0 {
+		return vs
+	}
+	pole := s2.PointFromCoords(0, 0, 1)
+	lat := 90.0
+	if !north {
+		pole, lat = s2.PointFromCoords(0, 0, -1), -90.0
+	}
+	// The vertices at the pole keep the longitude of the first vertex, so
+	// that the pieces on either side of the closing meridian have the same
+	// positions and can be merged again.
+	first := vs[0]
+	vs = appendVertex(vs, vertex{x: first.x + shift, y: first.y, lng: first.lng, p: first.p})
+	return append(vs,
+		vertex{x: first.x + shift, y: lat, lng: first.lng, p: pole},
+		vertex{x: first.x, y: lat, lng: first.lng, p: pole})
+}
+
+// worldRing returns a counterclockwise ring around the whole sphere in
+// unwrapped coordinates, with the longitudes [x, x+360]. It is used as the
+// shell of polygons whose outermost loop contains everything outside it.
+func worldRing(x float64) []vertex {
+	south, north := s2.PointFromCoords(0, 0, -1), s2.PointFromCoords(0, 0, 1)
+	return []vertex{
+		{x: x, y: -90, lng: math.NaN(), p: south},
+		{x: x + 360, y: -90, lng: math.NaN(), p: south},
+		{x: x + 360, y: 90, lng: math.NaN(), p: north},
+		{x: x, y: 90, lng: math.NaN(), p: north},
+	}
+}
+
+// clipRings clips the region to the left of the given rings to the half-plane
+// x <= c if west is true, or x >= c otherwise. Vertices on the line are
+// inside the half-plane.
+func clipRings(rings [][]vertex, c float64, west bool) [][]vertex {
+	inside := func(v vertex) bool {
+		if west {
+			return v.x <= c
+		}
+		return v.x >= c
+	}
+
+	// An arc is a part of a ring inside the half-plane, from the point where
+	// the ring enters it to the point where it leaves it.
+	type arc struct {
+		vs   []vertex
+		used bool
+	}
+	var result [][]vertex
+	var arcs []*arc
+	for _, ring := range rings {
+		start := -1
+		allOutside := true
+		for i, v := range ring {
+			if !inside(v) {
+				if start < 0 {
+					start = i
+				}
+			} else {
+				allOutside = false
+			}
+		}
+		if start < 0 {
+			result = append(result, ring)
+			continue
+		}
+		if allOutside {
+			continue
+		}
+		n := len(ring)
+		var cur []vertex
+		for i := 0; i < n; i++ {
+			a, b := ring[(start+i)%n], ring[(start+i+1)%n]
+			switch ain, bin := inside(a), inside(b); {
+			case !ain && bin:
+				cur = appendVertex([]vertex{crossing(a, b, c)}, b)
+			case ain && bin:
+				cur = appendVertex(cur, b)
+			case ain && !bin:
+				arcs = append(arcs, &arc{vs: appendVertex(cur, crossing(a, b, c))})
+				cur = nil
+			}
+		}
+	}
+
+	// Connect the arcs along the line. The interior is on the left, so the
+	// boundary runs north along the line for the western half-plane and
+	// south for the eastern one.
+	next := func(y float64) *arc {
+		var best *arc
+		for _, a := range arcs {
+			e := a.vs[0].y
+			if west && e >= y && (best == nil || e < best.vs[0].y) ||
+				!west && e <= y && (best == nil || e > best.vs[0].y) {
+				best = a
+			}
+		}
+		return best
+	}
+	for _, a := range arcs {
+		if a.used {
+			continue
+		}
+		var ring []vertex
+		for cur := a; cur != nil && !cur.used; {
+			cur.used = true
+			for _, v := range cur.vs {
+				ring = appendVertex(ring, v)
+			}
+			cur = next(cur.vs[len(cur.vs)-1].y)
+		}
+		result = append(result, ring)
+	}
+	return result
+}
+
+// appendVertex appends v to vs unless it is at the same position as the last
+// vertex.
+func appendVertex(vs []vertex, v vertex) []vertex {
+	if n := len(vs); n > 0 && vs[n-1].x == v.x && vs[n-1].y == v.y {
+		return vs
+	}
+	return append(vs, v)
+}
+
+// planarArea returns twice the signed area of the ring, which is positive
+// for counterclockwise rings.
+func planarArea(ring []position) float64 {
+	var sum float64
+	for i, a := range ring {
+		b := ring[(i+1)%len(ring)]
+		sum += a[0]*b[1] - b[0]*a[1]
+	}
+	return sum
+}
+
+// ringContains reports whether the point p in unwrapped coordinates is
+// inside the ring. The edges of the ring are geodesics, which may be far from
+// straight lines near the poles, so the test counts the crossings of the ring
+// with the meridian through p north of p.
+func ringContains(ring []vertex, p position) bool {
+	inside := false
+	for i, a := range ring {
+		b := ring[(i+1)%len(ring)]
+		if (a.x > p[0]) != (b.x > p[0]) && crossing(a, b, p[0]).y > p[1] {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// positionVertices returns the vertices of a ring of positions.
+func positionVertices(ring []position) []vertex {
+	vs := make([]vertex, len(ring))
+	for i, p := range ring {
+		// All positions at a pole are the same point.
+		point := s2.PointFromCoords(0, 0, p[1])
+		if math.Abs(p[1]) != 90 {
+			point = s2.PointFromLatLng(s2.LatLngFromDegrees(p[1], p[0]))
+		}
+		vs[i] = vertex{x: p[0], y: p[1], lng: p[0], p: point}
+	}
+	return vs
+}
+
+// vertexPositions returns the unwrapped coordinates of the vertices.
+func vertexPositions(vs []vertex) []position {
+	ring := make([]position, len(vs))
+	for i, v := range vs {
+		ring[i] = position{v.x, v.y}
+	}
+	return ring
+}
+
+// cutRings cuts the region to the left of the given rings in unwrapped
+// coordinates at the antimeridian, and returns the pieces as GeoJSON polygons.
+// Exterior rings are counterclockwise and holes are clockwise. Rings around a
+// pole are closed along the meridian seam.
+func cutRings(rings [][]vertex, seam float64) [][][]position {
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	for _, ring := range rings {
+		for _, v := range ring {
+			minX, maxX = math.Min(minX, v.x), math.Max(maxX, v.x)
+		}
+	}
+	var pieces [][]position
+	for k := strip(minX); k <= strip(maxX); k++ {
+		clipped := clipRings(rings, 360*float64(k)+180, true)
+		clipped = clipRings(clipped, 360*float64(k)-180, false)
+		for _, ring := range clipped {
+			var positions []position
+			for _, v := range ring {
+				positions = appendPosition(positions, v.output(k))
+			}
+			pieces = append(pieces, positions)
+		}
+	}
+	merged := mergeSeams(pieces)
+	if math.Abs(seam) != 180 {
+		for i, ring := range merged {
+			merged[i] = removeSeamVertices(ring, seam)
+		}
+	}
+	return assemblePolygons(merged)
+}
+
+// removeSeamVertices removes the vertices that were added where rings around
+// a pole cross the seam, once the pieces on either side have been merged.
+func removeSeamVertices(ring []position, seam float64) []position {
+	point := func(p position) s2.Point {
+		return s2.PointFromLatLng(s2.LatLngFromDegrees(p[1], p[0]))
+	}
+	n := len(ring)
+	out := make([]position, 0, n)
+	for i, p := range ring {
+		prev, next := ring[(i+n-1)%n], ring[(i+1)%n]
+		if len(out) > 0 {
+			prev = out[len(out)-1]
+		}
+		if p[0] != seam || prev[0] == seam || next[0] == seam ||
+			s2.DistanceFromSegment(point(p), point(prev), point(next)) > cutTolerance {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// mergeSeams merges rings that share edges in opposite directions. These are
+// created where a ring had to be closed along a meridian other than the
+// antimeridian, such as rings around a pole.
+func mergeSeams(rings [][]position) [][]position {
+	type edge [2]position
+	rings = splitSeams(rings)
+	counts := make(map[edge]int)
+	for i := range rings {
+		for k := range rings[i] {
+			counts[edge{rings[i][k], rings[i][(k+1)%len(rings[i])]}]++
+		}
+	}
+	var result [][]position
+	var edges []edge
+	for _, ring := range rings {
+		shared := false
+		for k := 0; k < len(ring) && !shared; k++ {
+			shared = counts[edge{ring[(k+1)%len(ring)], ring[k]}] > 0
+		}
+		if !shared {
+			result = append(result, ring)
+			continue
+		}
+		for k := range ring {
+			edges = append(edges, edge{ring[k], ring[(k+1)%len(ring)]})
+		}
+	}
+	if len(edges) == 0 {
+		return rings
+	}
+
+	pending := make(map[edge]int)
+	for _, e := range edges {
+		if rev := (edge{e[1], e[0]}); pending[rev] > 0 {
+			pending[rev]--
+		} else {
+			pending[e]++
+		}
+	}
+	next := make(map[position][]position)
+	for _, e := range edges {
+		if pending[e] > 0 {
+			pending[e]--
+			next[e[0]] = append(next[e[0]], e[1])
+		}
+	}
+	for _, e := range edges {
+		for len(next[e[0]]) > 0 {
+			start := e[0]
+			var ring []position
+			for v := start; ; {
+				ring = append(ring, v)
+				out := next[v]
+				if len(out) == 0 {
+					break
+				}
+				next[v] = out[1:]
+				if v = out[0]; v == start {
+					break
+				}
+			}
+			result = append(result, ring)
+		}
+	}
+	return result
+}
+
+// splitSeams splits the vertical and horizontal edges of the rings at the
+// positions of other rings that are on them, so that edges along the same
+// meridian or parallel that partially overlap are split into equal edges.
+func splitSeams(rings [][]position) [][]position {
+	byX := make(map[float64][]float64)
+	byY := make(map[float64][]float64)
+	for i, ring := range rings {
+		rings[i] = trimRing(ring)
+		for _, p := range rings[i] {
+			byX[p[0]] = append(byX[p[0]], p[1])
+			byY[p[1]] = append(byY[p[1]], p[0])
+		}
+	}
+	between := func(v, a, b float64) bool {
+		return a < v && v < b || b < v && v < a
+	}
+	result := make([][]position, len(rings))
+	for i, ring := range rings {
+		var out []position
+		for k, a := range ring {
+			out = append(out, a)
+			b := ring[(k+1)%len(ring)]
+			var splits []position
+			switch {
+			case a[0] == b[0]:
+				for _, y := range byX[a[0]] {
+					if between(y, a[1], b[1]) {
+						splits = append(splits, position{a[0], y})
+					}
+				}
+			case a[1] == b[1]:
+				for _, x := range byY[a[1]] {
+					if between(x, a[0], b[0]) {
+						splits = append(splits, position{x, a[1]})
+					}
+				}
+			}
+			sort.Slice(splits, func(i, j int) bool {
+				return math.Abs(splits[i][0]-a[0])+math.Abs(splits[i][1]-a[1]) <
+					math.Abs(splits[j][0]-a[0])+math.Abs(splits[j][1]-a[1])
+			})
+			for _, p := range splits {
+				out = appendPosition(out, p)
+			}
+		}
+		result[i] = out
+	}
+	return result
+}
+
+// trimRing removes positions at the end of the ring that are equal to the
+// first position.
+func trimRing(ring []position) []position {
+	for len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+		ring = ring[:len(ring)-1]
+	}
+	return ring
+}
+
+// assemblePolygons groups counterclockwise shells and clockwise holes into
+// GeoJSON polygons, assigning each hole to the smallest shell that contains
+// it. Degenerate rings are removed.
+func assemblePolygons(rings [][]position) [][][]position {
+	var shells, holes [][]position
+	for _, ring := range rings {
+		if ring = trimRing(ring); len(ring) < 3 || alongParallel(ring) {
+			continue
+		}
+		switch area := planarArea(ring); {
+		case area > 0:
+			shells = append(shells, ring)
+		case area < 0:
+			holes = append(holes, ring)
+		}
+	}
+	shellHoles := make([][][]position, len(shells))
+	for _, hole := range holes {
+		best := -1
+		for i, shell := range shells {
+			if containsRing(shell, hole) && (best < 0 || planarArea(shell) < planarArea(shells[best])) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			shellHoles[best] = append(shellHoles[best], hole)
+		}
+	}
+	var polygons [][][]position
+	for i, shell := range shells {
+		polygon := [][]position{closeRing(shell)}
+		for _, hole := range shellHoles[i] {
+			polygon = append(polygon, closeRing(hole))
+		}
+		polygons = append(polygons, polygon)
+	}
+	return polygons
+}
+
+// alongParallel reports whether all positions of the ring have the same
+// latitude. Such rings are left over where the edges added at a pole by
+// different rings only partially cancel, and have no area.
+func alongParallel(ring []position) bool {
+	for _, p := range ring {
+		if p[1] != ring[0][1] {
+			return false
+		}
+	}
+	return true
+}
+
+// containsRing reports whether the shell contains the hole. Holes may touch
+// their shell, so the test uses the first position of the hole that is not a
+// vertex of the shell, or the midpoint of its first edge.
+func containsRing(shell, hole []position) bool {
+	onShell := make(map[position]bool, len(shell))
+	for _, p := range shell {
+		onShell[p] = true
+	}
+	vs := positionVertices(shell)
+	for _, p := range hole {
+		if !onShell[p] {
+			return ringContains(vs, p)
+		}
+	}
+	return ringContains(vs, position{(hole[0][0] + hole[1][0]) / 2, (hole[0][1] + hole[1][1]) / 2})
+}
+
+// closeRing returns the ring with the first position repeated at the end.
+func closeRing(ring []position) []position {
+	return append(append([]position(nil), ring...), ring[0])
+}
@@ -0,0 +1,352 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geojson
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/rubenpoppe/geo/s1"
+	"github.com/rubenpoppe/geo/s2"
+	"github.com/rubenpoppe/geo/s2/s2testing"
+)
+
+// checkPositions checks that every position of the geometry is within the
+// valid range, and returns the number of positions on the antimeridian. The
+// last position of a closed ring is not counted.
+func checkPositions(t *testing.T, g *Geometry) int {
+	t.Helper()
+	var walk func(v interface{}) int
+	walk = func(v interface{}) int {
+		list, ok := v.([]interface{})
+		if !ok {
+			return 0
+		}
+		if len(list) == 2 {
+			if lng, ok := list[0].(float64); ok {
+				lat := list[1].(float64)
+				if lng < -180 || lng > 180 || lat < -90 || lat > 90 {
+					t.Errorf("position [%v, %v] is out of range", lng, lat)
+				}
+				if math.Abs(lng) == 180 {
+					return 1
+				}
+				return 0
+			}
+		}
+		if len(list) > 1 && reflect.DeepEqual(list[0], list[len(list)-1]) {
+			list = list[:len(list)-1]
+		}
+		n := 0
+		for _, e := range list {
+			n += walk(e)
+		}
+		return n
+	}
+	var coords interface{}
+	if err := json.Unmarshal(g.Coordinates, &coords); err != nil {
+		t.Fatal(err)
+	}
+	return walk(coords)
+}
+
+func TestCutPolyline(t *testing.T) {
+	tests := []struct {
+		name string
+		line s2.Polyline
+		want [][]position
+	}{
+		{
+			name: "eastwards",
+			line: s2.Polyline{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 170)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, -170))},
+			want: [][]position{{{170, 0}, {180, 0}}, {{-180, 0}, {-170, 0}}},
+		},
+		{
+			name: "westwards",
+			line: s2.Polyline{s2.PointFromLatLng(s2.LatLngFromDegrees(0, -170)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 170))},
+			want: [][]position{{{-170, 0}, {-180, 0}}, {{180, 0}, {170, 0}}},
+		},
+		{
+			name: "back and forth",
+			line: s2.Polyline{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 170)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, -170)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 160))},
+			want: [][]position{{{170, 0}, {180, 0}}, {{-180, 0}, {-170, 0}, {-180, 0}}, {{180, 0}, {160, 0}}},
+		},
+		{
+			name: "vertex on the antimeridian",
+			line: s2.Polyline{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 170)), s2.PointFromLatLng(s2.LatLngFromDegrees(5, 180)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, -170))},
+			want: [][]position{{{170, 0}, {180, 5}}, {{-180, 5}, {-170, 0}}},
+		},
+		{
+			name: "not crossing",
+			line: s2.Polyline{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, -10))},
+			want: [][]position{{{10, 0}, {-10, 0}}},
+		},
+	}
+	for _, test := range tests {
+		vs, _ := unwrap(test.line, false)
+		got := cutChain(vs)
+		if len(got) != len(test.want) {
+			t.Errorf("%s: cutChain = %v, want %v", test.name, got, test.want)
+			continue
+		}
+		for i := range got {
+			if len(got[i]) != len(test.want[i]) {
+				t.Errorf("%s: cutChain = %v, want %v", test.name, got, test.want)
+				break
+			}
+			for k := range got[i] {
+				if math.Abs(got[i][k][0]-test.want[i][k][0]) > 1e-12 || math.Abs(got[i][k][1]-test.want[i][k][1]) > 1e-12 {
+					t.Errorf("%s: cutChain = %v, want %v", test.name, got, test.want)
+				}
+			}
+		}
+
+		// Decoding joins the pieces again.
+		enc, err := FromPolyline(&test.line)
+		if err != nil {
+			t.Errorf("%s: FromPolyline failed: %v", test.name, err)
+			continue
+		}
+		dec, err := roundTrip(t, enc).Polyline()
+		if err != nil {
+			t.Errorf("%s: round trip failed: %v", test.name, err)
+			continue
+		}
+		if !dec.ApproxEqual(&test.line) {
+			t.Errorf("%s: round trip = %v, want %v", test.name, dec, test.line)
+		}
+	}
+}
+
+func TestCutPolygon(t *testing.T) {
+	// The latitude where the geodesic from (10, 170) to (10, -170) crosses the
+	// antimeridian.
+	crossLat := s2.LatLngFromPoint(s2.Interpolate(0.5, s2.PointFromLatLng(s2.LatLngFromDegrees(10, 170)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, -170)))).Lat.Degrees()
+
+	tests := []struct {
+		name       string
+		polygon    *s2.Polygon
+		wantPieces int
+		wantCuts   int
+		// large is set for polygons with a ring that bounds more than a
+		// hemisphere, which are decoded as the smaller region.
+		large bool
+	}{
+		{
+			name: "square",
+			polygon: s2.PolygonFromLoops([]*s2.Loop{
+				s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(-10, 170)), s2.PointFromLatLng(s2.LatLngFromDegrees(-10, -170)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, -170)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 170))}),
+			}),
+			wantPieces: 2,
+			wantCuts:   4,
+		},
+		{
+			name: "square with a hole across the antimeridian",
+			polygon: s2.PolygonFromLoops([]*s2.Loop{
+				s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(-10, 170)), s2.PointFromLatLng(s2.LatLngFromDegrees(-10, -170)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, -170)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 170))}),
+				s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(-5, 175)), s2.PointFromLatLng(s2.LatLngFromDegrees(-5, -175)), s2.PointFromLatLng(s2.LatLngFromDegrees(5, -175)), s2.PointFromLatLng(s2.LatLngFromDegrees(5, 175))}),
+			}),
+			wantPieces: 2,
+			wantCuts:   8,
+		},
+		{
+			name: "square with a hole on one side",
+			polygon: s2.PolygonFromLoops([]*s2.Loop{
+				s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(-10, 170)), s2.PointFromLatLng(s2.LatLngFromDegrees(-10, -170)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, -170)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 170))}),
+				s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(-5, -178)), s2.PointFromLatLng(s2.LatLngFromDegrees(-5, -175)), s2.PointFromLatLng(s2.LatLngFromDegrees(5, -175)), s2.PointFromLatLng(s2.LatLngFromDegrees(5, -178))}),
+			}),
+			wantPieces: 2,
+			wantCuts:   4,
+		},
+		{
+			name:       "cap around the north pole",
+			polygon:    s2.PolygonFromLoops([]*s2.Loop{s2.RegularLoop(s2.PointFromLatLng(s2.LatLngFromDegrees(90, 0)), 10*s1.Degree, 12)}),
+			wantPieces: 1,
+			wantCuts:   4,
+		},
+		{
+			name:       "cap around the south pole",
+			polygon:    s2.PolygonFromLoops([]*s2.Loop{s2.RegularLoop(s2.PointFromLatLng(s2.LatLngFromDegrees(-90, 0)), 10*s1.Degree, 7)}),
+			wantPieces: 1,
+			wantCuts:   4,
+		},
+		{
+			name: "complement of a small square",
+			polygon: s2.PolygonFromLoops([]*s2.Loop{
+				s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(-10, 170)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 170)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, -170)), s2.PointFromLatLng(s2.LatLngFromDegrees(-10, -170))}),
+			}),
+			wantPieces: 1,
+			wantCuts:   8,
+			large:      true,
+		},
+	}
+	for _, test := range tests {
+		if err := test.polygon.Validate(); err != nil {
+			t.Fatalf("%s: invalid test polygon: %v", test.name, err)
+		}
+		enc, err := FromPolygon(test.polygon)
+		if err != nil {
+			t.Errorf("%s: FromPolygon failed: %v", test.name, err)
+			continue
+		}
+		var polygons [][][]position
+		if enc.Type == TypePolygon {
+			var rings [][]position
+			json.Unmarshal(enc.Coordinates, &rings)
+			polygons = append(polygons, rings)
+		} else {
+			json.Unmarshal(enc.Coordinates, &polygons)
+		}
+		if len(polygons) != test.wantPieces {
+			t.Errorf("%s: FromPolygon has %d pieces, want %d: %s", test.name, len(polygons), test.wantPieces, enc.Coordinates)
+		}
+		if got := checkPositions(t, enc); got != test.wantCuts {
+			t.Errorf("%s: FromPolygon has %d positions on the antimeridian, want %d: %s", test.name, got, test.wantCuts, enc.Coordinates)
+		}
+		if test.large {
+			continue
+		}
+
+		// Each piece is a valid polygon in its own right, and their areas
+		// add up to the area of the original polygon.
+		var area float64
+		for i, rings := range polygons {
+			g, err := newGeometry(TypePolygon, rings)
+			if err != nil {
+				t.Errorf("%s: piece %d cannot be encoded: %v", test.name, i, err)
+				continue
+			}
+			piece, err := g.Polygon()
+			if err != nil {
+				t.Errorf("%s: piece %d is invalid: %v", test.name, i, err)
+				continue
+			}
+			area += piece.Area()
+		}
+		if math.Abs(area-test.polygon.Area()) > 1e-12 {
+			t.Errorf("%s: the pieces have area %v, want %v", test.name, area, test.polygon.Area())
+		}
+
+		got, err := roundTrip(t, enc).Polygon()
+		if err != nil {
+			t.Errorf("%s: round trip failed: %v", test.name, err)
+			continue
+		}
+		if !polygonsApproxEqual(got, test.polygon) {
+			t.Errorf("%s: round trip = %v, want %v", test.name, got, test.polygon)
+		}
+	}
+
+	// The square is cut at the latitude of the geodesic, not of a straight
+	// line in longitude/latitude coordinates.
+	enc, _ := FromPolygon(tests[0].polygon)
+	var polygons [][][]position
+	json.Unmarshal(enc.Coordinates, &polygons)
+	found := false
+	for _, p := range polygons[0][0] {
+		if math.Abs(p[0]) == 180 && math.Abs(p[1]-crossLat) < 1e-12 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("FromPolygon(square) = %v, want a position at latitude %v on the antimeridian", polygons, crossLat)
+	}
+}
+
+func TestDecodeCutPolygon(t *testing.T) {
+	// A polygon around the north pole as typically produced by GIS software,
+	// i.e. a single ring that runs along the antimeridian to the pole and
+	// back.
+	pole, err := parseGeometry(t, `{"type": "Polygon", "coordinates": [[
+		[-180, 80], [-90, 80], [0, 80], [90, 80], [180, 80], [180, 90], [-180, 90], [-180, 80]]]}`).Polygon()
+	if err != nil {
+		t.Fatalf("Polygon() failed: %v", err)
+	}
+	if pole.NumLoops() != 1 || pole.Loop(0).NumVertices() != 4 {
+		t.Errorf("Polygon() = %v, want a single loop with 4 vertices", pole)
+	}
+	if !pole.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(90, 0))) || pole.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(70, 0))) {
+		t.Errorf("Polygon() does not contain the north pole")
+	}
+
+	// A square cut in two at the antimeridian is merged back into one loop.
+	square, err := parseGeometry(t, `{"type": "MultiPolygon", "coordinates": [
+		[[[170, -10], [180, -10], [180, 10], [170, 10], [170, -10]]],
+		[[[-180, -10], [-170, -10], [-170, 10], [-180, 10], [-180, -10]]]]}`).Polygon()
+	if err != nil {
+		t.Fatalf("Polygon() failed: %v", err)
+	}
+	if square.NumLoops() != 1 {
+		t.Errorf("Polygon() has %d loops, want 1", square.NumLoops())
+	}
+	if !square.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(0, 180))) || !square.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(0, 175))) || !square.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(0, -175))) {
+		t.Errorf("Polygon() does not contain points on both sides of the antimeridian")
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	// A line through the north pole gets two vertices at the pole.
+	vs, _ := unwrap([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(80, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(90, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(80, 90))}, false)
+	var got [][2]float64
+	for _, v := range vs {
+		got = append(got, [2]float64{math.Round(v.x), math.Round(v.y)})
+	}
+	if want := [][2]float64{{0, 80}, {0, 90}, {90, 90}, {90, 80}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unwrap through the pole = %v, want %v", got, want)
+	}
+
+	// A loop around the north pole shifts by 360 degrees.
+	if _, shift := unwrap(s2.RegularLoop(s2.PointFromLatLng(s2.LatLngFromDegrees(90, 0)), 0.1, 5).Vertices(), true); shift != 360 {
+		t.Errorf("unwrap around the north pole shift = %v, want 360", shift)
+	}
+	if _, shift := unwrap(s2.RegularLoop(s2.PointFromLatLng(s2.LatLngFromDegrees(0, 180)), 0.1, 5).Vertices(), true); shift != 0 {
+		t.Errorf("unwrap across the antimeridian shift = %v, want 0", shift)
+	}
+}
+
+func TestRandomPolygonRoundTrip(t *testing.T) {
+	r := s2testing.NewRand(1)
+	for i := 0; i < 200; i++ {
+		// Centers near the antimeridian or a pole are more interesting.
+		var center s2.Point
+		switch i % 3 {
+		case 0:
+			center = s2.PointFromLatLng(s2.LatLngFromDegrees(r.UniformFloat64(-60, 60), 180+r.UniformFloat64(-5, 5)))
+		case 1:
+			center = s2.PointFromLatLng(s2.LatLngFromDegrees(90-r.UniformFloat64(0, 10), r.UniformFloat64(-180, 180)))
+		default:
+			center = r.Point()
+		}
+		radius := s1.Angle(r.UniformFloat64(0.05, 0.5))
+		p := r.Polygon(center, radius, 1+r.Intn(3), 5+r.Intn(20))
+
+		enc, err := FromPolygon(p)
+		if err != nil {
+			t.Errorf("FromPolygon failed: %v", err)
+			continue
+		}
+		checkPositions(t, enc)
+		got, err := roundTrip(t, enc).Polygon()
+		if err != nil {
+			t.Errorf("round trip of %v failed: %v", p, err)
+			continue
+		}
+		if !polygonsApproxEqual(got, p) {
+			t.Errorf("round trip of %v = %v", p, got)
+		}
+	}
+}
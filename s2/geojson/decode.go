@@ -0,0 +1,464 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/rubenpoppe/geo/s1"
+	"github.com/rubenpoppe/geo/s2"
+	"github.com/rubenpoppe/geo/s2/internal/ogc"
+)
+
+// Point returns the point of a Point geometry.
+func (g *Geometry) Point() (s2.Point, error) {
+	if err := g.checkType(TypePoint); err != nil {
+		return s2.Point{}, fmt.Errorf("geojson: %v", err)
+	}
+	var pos []float64
+	if err := g.unmarshalCoordinates(&pos); err != nil {
+		return s2.Point{}, fmt.Errorf("geojson: %v", err)
+	}
+	p, err := pointFromPosition(pos)
+	if err != nil {
+		return s2.Point{}, fmt.Errorf("geojson: %v", err)
+	}
+	return p, nil
+}
+
+// PointVector returns the points of a Point or MultiPoint geometry.
+func (g *Geometry) PointVector() (*s2.PointVector, error) {
+	v, err := g.pointVector()
+	if err != nil {
+		return nil, fmt.Errorf("geojson: %v", err)
+	}
+	return v, nil
+}
+
+// Polyline returns the polyline of a LineString geometry. A MultiLineString
+// is also accepted if its line strings join into a single polyline at the
+// antimeridian.
+func (g *Geometry) Polyline() (*s2.Polyline, error) {
+	lines, err := g.polylines()
+	if err != nil {
+		return nil, fmt.Errorf("geojson: %v", err)
+	}
+	if len(lines) != 1 {
+		return nil, fmt.Errorf("geojson: %s has %d line strings after joining at the antimeridian, want 1", g.Type, len(lines))
+	}
+	return lines[0], nil
+}
+
+// Polylines returns the polylines of a LineString or MultiLineString
+// geometry. Consecutive line strings that meet at the antimeridian are joined
+// into a single polyline.
+func (g *Geometry) Polylines() ([]*s2.Polyline, error) {
+	lines, err := g.polylines()
+	if err != nil {
+		return nil, fmt.Errorf("geojson: %v", err)
+	}
+	return lines, nil
+}
+
+// Polygon returns the polygon of a Polygon or MultiPolygon geometry. The
+// pieces of a polygon that was cut at the antimeridian are merged.
+func (g *Geometry) Polygon() (*s2.Polygon, error) {
+	p, err := g.polygon()
+	if err != nil {
+		return nil, fmt.Errorf("geojson: %v", err)
+	}
+	return p, nil
+}
+
+// ShapeIndex returns a ShapeIndex containing the shapes of any geometry. Each
+// Point and MultiPoint becomes a PointVector, each line string a Polyline, and
+// each Polygon and MultiPolygon a Polygon. The members of a
+// GeometryCollection are added in order.
+func (g *Geometry) ShapeIndex() (*s2.ShapeIndex, error) {
+	index := s2.NewShapeIndex()
+	if err := g.addShapes(index); err != nil {
+		return nil, fmt.Errorf("geojson: %v", err)
+	}
+	return index, nil
+}
+
+// checkType returns an error if the geometry is not one of the given types.
+func (g *Geometry) checkType(types ...string) error {
+	for _, t := range types {
+		if g.Type == t {
+			return nil
+		}
+	}
+	if len(types) == 1 {
+		return fmt.Errorf("geometry type is %q, want %s", g.Type, types[0])
+	}
+	return fmt.Errorf("geometry type is %q, want one of %v", g.Type, types)
+}
+
+// unmarshalCoordinates decodes the coordinates member into v.
+func (g *Geometry) unmarshalCoordinates(v interface{}) error {
+	if len(g.Coordinates) == 0 {
+		return fmt.Errorf("%s has no coordinates", g.Type)
+	}
+	if err := json.Unmarshal(g.Coordinates, v); err != nil {
+		return fmt.Errorf("invalid %s coordinates: %v", g.Type, err)
+	}
+	return nil
+}
+
+func (g *Geometry) pointVector() (*s2.PointVector, error) {
+	if err := g.checkType(TypePoint, TypeMultiPoint); err != nil {
+		return nil, err
+	}
+	var positions [][]float64
+	if g.Type == TypePoint {
+		var pos []float64
+		if err := g.unmarshalCoordinates(&pos); err != nil {
+			return nil, err
+		}
+		positions = [][]float64{pos}
+	} else if err := g.unmarshalCoordinates(&positions); err != nil {
+		return nil, err
+	}
+	points := make(s2.PointVector, 0, len(positions))
+	for i, pos := range positions {
+		p, err := pointFromPosition(pos)
+		if err != nil {
+			return nil, fmt.Errorf("position %d: %v", i, err)
+		}
+		points = append(points, p)
+	}
+	return &points, nil
+}
+
+func (g *Geometry) polylines() ([]*s2.Polyline, error) {
+	if err := g.checkType(TypeLineString, TypeMultiLineString); err != nil {
+		return nil, err
+	}
+	var lines [][][]float64
+	if g.Type == TypeLineString {
+		var line [][]float64
+		if err := g.unmarshalCoordinates(&line); err != nil {
+			return nil, err
+		}
+		lines = [][][]float64{line}
+	} else if err := g.unmarshalCoordinates(&lines); err != nil {
+		return nil, err
+	}
+
+	var result []*s2.Polyline
+	var prevEnd []float64
+	for i, line := range lines {
+		points, err := linePoints(line)
+		if err != nil {
+			if g.Type == TypeLineString {
+				return nil, err
+			}
+			return nil, fmt.Errorf("line string %d: %v", i, err)
+		}
+		// Join this line to the previous one if they meet at the antimeridian.
+		if n := len(result); n > 0 && onAntimeridian(prevEnd) && onAntimeridian(line[0]) {
+			prev := result[n-1]
+			if (*prev)[len(*prev)-1] == points[0] {
+				*prev = removeCutVertices(append(*prev, points[1:]...), false)
+				prevEnd = line[len(line)-1]
+				continue
+			}
+		}
+		polyline := s2.Polyline(points)
+		result = append(result, &polyline)
+		prevEnd = lines[i][len(lines[i])-1]
+	}
+	return result, nil
+}
+
+// linePoints converts the positions of a line string to points, removing
+// consecutive duplicates.
+func linePoints(line [][]float64) ([]s2.Point, error) {
+	if len(line) < 2 {
+		return nil, fmt.Errorf("line string has %d positions, want at least 2", len(line))
+	}
+	points := make([]s2.Point, 0, len(line))
+	for i, pos := range line {
+		p, err := pointFromPosition(pos)
+		if err != nil {
+			return nil, fmt.Errorf("position %d: %v", i, err)
+		}
+		if n := len(points); n > 0 && points[n-1] == p {
+			continue
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+func (g *Geometry) polygon() (*s2.Polygon, error) {
+	if err := g.checkType(TypePolygon, TypeMultiPolygon); err != nil {
+		return nil, err
+	}
+	var loops []*s2.Loop
+	if g.Type == TypePolygon {
+		var rings [][][]float64
+		if err := g.unmarshalCoordinates(&rings); err != nil {
+			return nil, err
+		}
+		for i, ring := range rings {
+			loop, err := loopFromRing(ring, i > 0)
+			if err != nil {
+				return nil, fmt.Errorf("ring %d: %v", i, err)
+			}
+			loops = append(loops, loop)
+		}
+	} else {
+		var polygons [][][][]float64
+		if err := g.unmarshalCoordinates(&polygons); err != nil {
+			return nil, err
+		}
+		for i, rings := range polygons {
+			if len(rings) == 0 {
+				return nil, fmt.Errorf("polygon %d has no rings", i)
+			}
+			for k, ring := range rings {
+				loop, err := loopFromRing(ring, k > 0)
+				if err != nil {
+					return nil, fmt.Errorf("polygon %d, ring %d: %v", i, k, err)
+				}
+				loops = append(loops, loop)
+			}
+		}
+	}
+
+	p := s2.PolygonFromOrientedLoops(joinCutLoops(loops))
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", g.Type, err)
+	}
+	return p, nil
+}
+
+// loopFromRing converts a GeoJSON linear ring to a loop that is oriented for
+// s2.PolygonFromOrientedLoops. The ring is taken as given, following the
+// right-hand rule, unless its orientation is detectably wrong; see
+// ogc.OrientLoop.
+func loopFromRing(ring [][]float64, hole bool) (*s2.Loop, error) {
+	if len(ring) < 4 {
+		return nil, fmt.Errorf("ring has %d positions, want at least 4", len(ring))
+	}
+	points := make([]s2.Point, 0, len(ring)-1)
+	positions := make([]ogc.Position, len(ring))
+	for i, pos := range ring {
+		p, err := pointFromPosition(pos)
+		if err != nil {
+			return nil, fmt.Errorf("position %d: %v", i, err)
+		}
+		if i < len(ring)-1 {
+			points = append(points, p)
+		}
+		positions[i] = ogc.Position{pos[0], pos[1]}
+	}
+	if first, last := ring[0], ring[len(ring)-1]; first[0] != last[0] || first[1] != last[1] {
+		return nil, fmt.Errorf("ring is not closed: first position %v differs from last position %v", first, last)
+	}
+	points = removeCutVertices(cleanRing(points), true)
+	if len(points) < 3 {
+		return nil, fmt.Errorf("ring has %d distinct vertices, want at least 3", len(points))
+	}
+	loop := s2.LoopFromPoints(points)
+	if err := ogc.CheckRing(loop); err != nil {
+		return nil, err
+	}
+	ogc.OrientLoop(loop, positions, hole)
+	return loop, nil
+}
+
+// cleanRing removes consecutive duplicate vertices and spikes of the form
+// ABA from a ring. These are created by converting a ring that runs along the
+// antimeridian or through a pole.
+func cleanRing(points []s2.Point) []s2.Point {
+	var out []s2.Point
+	for _, p := range points {
+		n := len(out)
+		switch {
+		case n > 0 && out[n-1] == p:
+		case n > 1 && out[n-2] == p:
+			out = out[:n-1]
+		default:
+			out = append(out, p)
+		}
+	}
+	// Remove duplicates and spikes that wrap around the end of the ring.
+	for len(out) > 2 {
+		n := len(out)
+		switch {
+		case out[0] == out[n-1]:
+			out = out[:n-1]
+		case out[1] == out[n-1]:
+			out = out[1 : n-1]
+		case out[n-2] == out[0]:
+			out = out[:n-2]
+		default:
+			return out
+		}
+	}
+	return out
+}
+
+// cutTolerance is the maximum distance of a vertex on the antimeridian from
+// the edge between its neighbors for it to be considered a vertex that was
+// added by cutting. It allows for the rounding of positions to degrees.
+const cutTolerance = 1e-13 * s1.Radian
+
+// removeCutVertices removes vertices on the antimeridian that lie on the edge
+// between their neighbors, such as those added by cutting geometry at the
+// antimeridian. If closed is true the points form a loop, otherwise the
+// endpoints are kept.
+func removeCutVertices(points []s2.Point, closed bool) []s2.Point {
+	n := len(points)
+	if n < 3 {
+		return points
+	}
+	out := make([]s2.Point, 0, n)
+	for i, p := range points {
+		if !onAntimeridianPoint(p) || (!closed && (i == 0 || i == n-1)) {
+			out = append(out, p)
+			continue
+		}
+		prev := points[(i+n-1)%n]
+		if len(out) > 0 {
+			prev = out[len(out)-1]
+		}
+		next := points[(i+1)%n]
+		if prev == next || s2.DistanceFromSegment(p, prev, next) > cutTolerance {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// onAntimeridianPoint reports whether p is on the antimeridian.
+func onAntimeridianPoint(p s2.Point) bool {
+	return math.Abs(s2.LatLngFromPoint(p).Lng.Radians()) == math.Pi
+}
+
+// joinCutLoops merges loops that share edges in opposite directions, such as
+// the pieces of a polygon that was cut at the antimeridian. The shared edges
+// are removed and the remaining edges of the affected loops are chained into
+// new loops. Loops that do not share edges are returned unchanged.
+func joinCutLoops(loops []*s2.Loop) []*s2.Loop {
+	counts := make(map[s2.Edge]int)
+	for _, l := range loops {
+		for i := 0; i < l.NumEdges(); i++ {
+			counts[l.Edge(i)]++
+		}
+	}
+	var result []*s2.Loop
+	var edges []s2.Edge
+	for _, l := range loops {
+		shared := false
+		for i := 0; i < l.NumEdges() && !shared; i++ {
+			e := l.Edge(i)
+			shared = counts[s2.Edge{V0: e.V1, V1: e.V0}] > 0
+		}
+		if !shared {
+			result = append(result, l)
+			continue
+		}
+		for i := 0; i < l.NumEdges(); i++ {
+			edges = append(edges, l.Edge(i))
+		}
+	}
+	if len(edges) == 0 {
+		return loops
+	}
+
+	// Cancel pairs of opposite edges.
+	pending := make(map[s2.Edge]int)
+	for _, e := range edges {
+		if rev := (s2.Edge{V0: e.V1, V1: e.V0}); pending[rev] > 0 {
+			pending[rev]--
+		} else {
+			pending[e]++
+		}
+	}
+	next := make(map[s2.Point][]s2.Point)
+	for _, e := range edges {
+		if pending[e] > 0 {
+			pending[e]--
+			next[e.V0] = append(next[e.V0], e.V1)
+		}
+	}
+
+	// Chain the remaining edges into loops.
+	for _, e := range edges {
+		for len(next[e.V0]) > 0 {
+			start := e.V0
+			var points []s2.Point
+			for v := start; ; {
+				points = append(points, v)
+				out := next[v]
+				if len(out) == 0 {
+					break
+				}
+				next[v] = out[1:]
+				if v = out[0]; v == start {
+					break
+				}
+			}
+			if points = removeCutVertices(cleanRing(points), true); len(points) >= 3 {
+				result = append(result, s2.LoopFromPoints(points))
+			}
+		}
+	}
+	return result
+}
+
+// addShapes adds the shapes of the geometry to the index.
+func (g *Geometry) addShapes(index *s2.ShapeIndex) error {
+	switch g.Type {
+	case TypePoint, TypeMultiPoint:
+		points, err := g.pointVector()
+		if err != nil {
+			return err
+		}
+		index.Add(points)
+	case TypeLineString, TypeMultiLineString:
+		lines, err := g.polylines()
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			index.Add(line)
+		}
+	case TypePolygon, TypeMultiPolygon:
+		p, err := g.polygon()
+		if err != nil {
+			return err
+		}
+		index.Add(p)
+	case TypeGeometryCollection:
+		for i, child := range g.Geometries {
+			if child == nil {
+				return fmt.Errorf("geometry %d is null", i)
+			}
+			if err := child.addShapes(index); err != nil {
+				return fmt.Errorf("geometry %d: %v", i, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown geometry type %q", g.Type)
+	}
+	return nil
+}
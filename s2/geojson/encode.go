@@ -0,0 +1,309 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geojson
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// errFull is returned when encoding geometry that covers the whole sphere.
+var errFull = errors.New("the full polygon cannot be represented in GeoJSON")
+
+// FromPoint returns a Point geometry. An error is returned if the point has
+// non-finite coordinates.
+func FromPoint(p s2.Point) (*Geometry, error) {
+	g, err := newGeometry(TypePoint, positionFromLatLng(s2.LatLngFromPoint(p)))
+	if err != nil {
+		return nil, fmt.Errorf("geojson: %v", err)
+	}
+	return g, nil
+}
+
+// FromPointVector returns a MultiPoint geometry. An error is returned if a
+// point has non-finite coordinates.
+func FromPointVector(points s2.PointVector) (*Geometry, error) {
+	g, err := fromPoints(points)
+	if err != nil {
+		return nil, fmt.Errorf("geojson: %v", err)
+	}
+	return g, nil
+}
+
+// fromPoints returns a MultiPoint geometry for the given points.
+func fromPoints(points []s2.Point) (*Geometry, error) {
+	positions := make([]position, 0, len(points))
+	for _, p := range points {
+		positions = append(positions, positionFromLatLng(s2.LatLngFromPoint(p)))
+	}
+	return newGeometry(TypeMultiPoint, positions)
+}
+
+// FromPolyline returns a LineString geometry, or a MultiLineString if the
+// polyline crosses the antimeridian. An error is returned if a vertex has
+// non-finite coordinates.
+func FromPolyline(l *s2.Polyline) (*Geometry, error) {
+	g, err := fromChains([][]s2.Point{*l})
+	if err != nil {
+		return nil, fmt.Errorf("geojson: %v", err)
+	}
+	return g, nil
+}
+
+// fromChains returns a LineString or MultiLineString geometry for the given
+// polylines, cut at the antimeridian.
+func fromChains(chains [][]s2.Point) (*Geometry, error) {
+	var lines [][]position
+	for _, chain := range chains {
+		vs, _ := unwrap(chain, false)
+		lines = append(lines, cutChain(vs)...)
+	}
+	switch len(lines) {
+	case 0:
+		return newGeometry(TypeLineString, []position{})
+	case 1:
+		return newGeometry(TypeLineString, lines[0])
+	}
+	return newGeometry(TypeMultiLineString, lines)
+}
+
+// FromPolygon returns a Polygon geometry, or a MultiPolygon if the polygon
+// has several shells or crosses the antimeridian. Exterior rings are
+// counterclockwise and holes are clockwise. The full polygon cannot be
+// represented in GeoJSON and returns an error, as does a polygon with
+// non-finite coordinates.
+func FromPolygon(p *s2.Polygon) (*Geometry, error) {
+	g, err := fromPolygon(p)
+	if err != nil {
+		return nil, fmt.Errorf("geojson: %v", err)
+	}
+	return g, nil
+}
+
+// fromPolygon returns a Polygon or MultiPolygon geometry for p.
+func fromPolygon(p *s2.Polygon) (*Geometry, error) {
+	if p.IsFull() {
+		return nil, errFull
+	}
+	polygons := polygonPositions(p)
+	switch len(polygons) {
+	case 0:
+		return newGeometry(TypePolygon, [][]position{})
+	case 1:
+		return newGeometry(TypePolygon, polygons[0])
+	}
+	return newGeometry(TypeMultiPolygon, polygons)
+}
+
+// polygonPositions returns the GeoJSON polygons that represent p.
+func polygonPositions(p *s2.Polygon) [][][]position {
+	rings := make([][]vertex, p.NumLoops())
+	shifts := make([]float64, p.NumLoops())
+	var ranges [][2]float64
+	for i := range rings {
+		l := p.Loop(i)
+		points := append([]s2.Point(nil), l.Vertices()...)
+		if l.IsHole() {
+			for a, b := 0, len(points)-1; a < b; a, b = a+1, b-1 {
+				points[a], points[b] = points[b], points[a]
+			}
+		}
+		rings[i], shifts[i] = unwrap(points, true)
+		if shifts[i] == 0 {
+			r := [2]float64{math.Inf(1), math.Inf(-1)}
+			for _, v := range rings[i] {
+				r[0], r[1] = math.Min(r[0], v.x), math.Max(r[1], v.x)
+			}
+			ranges = append(ranges, r)
+		}
+	}
+
+	// Rings around a pole are all closed along the same meridian, which
+	// does not cross the other rings.
+	seam := seamLongitude(ranges)
+	needsCut := false
+	north := s2.PointFromCoords(0, 0, 1)
+	for i, vs := range rings {
+		if shift := shifts[i]; shift != 0 {
+			// The loop contains the pole that it goes around, also for
+			// holes, whose loops contain the hole.
+			containsNorth := p.Loop(i).ContainsPoint(north)
+			vs = closeAroundPole(rotateToSeam(vs, shift, seam, containsNorth), shift, containsNorth)
+			if shift < 0 {
+				for k := range vs {
+					vs[k].x += 360
+				}
+			}
+			needsCut = true
+		}
+		if parent, ok := p.Parent(i); ok && shifts[i] == 0 {
+			vs = alignRing(vs, rings[parent])
+		} else if !ok && planarArea(vertexPositions(vs)) < 0 {
+			// The loop contains everything outside it in unwrapped
+			// coordinates, so it needs a shell around the whole sphere.
+			needsCut = true
+		}
+		for _, v := range vs {
+			if v.x < -180 || v.x > 180 {
+				needsCut = true
+			}
+		}
+		rings[i] = vs
+	}
+	if needsCut {
+		if planarArea(vertexPositions(rings[0])) < 0 {
+			minX := math.Inf(1)
+			for _, ring := range rings {
+				for _, v := range ring {
+					minX = math.Min(minX, v.x)
+				}
+			}
+			rings = append(rings, worldRing(math.Floor(minX)-1))
+		}
+		return cutRings(rings, seam)
+	}
+
+	// Each shell becomes a GeoJSON polygon with its holes.
+	var polygons [][][]position
+	for i := range rings {
+		if p.Loop(i).IsHole() {
+			continue
+		}
+		polygon := [][]position{closeRing(vertexOutputs(rings[i]))}
+		for k := i + 1; k <= p.LastDescendant(i); k++ {
+			if parent, _ := p.Parent(k); parent == i {
+				polygon = append(polygon, closeRing(vertexOutputs(rings[k])))
+			}
+		}
+		polygons = append(polygons, polygon)
+	}
+	return polygons
+}
+
+// vertexOutputs returns the positions of vertices that do not need cutting.
+func vertexOutputs(vs []vertex) []position {
+	ring := make([]position, len(vs))
+	for i, v := range vs {
+		ring[i] = v.output(0)
+	}
+	return ring
+}
+
+// alignRing shifts a ring in unwrapped coordinates by a multiple of 360
+// degrees so that it lies inside its parent ring.
+func alignRing(vs, parent []vertex) []vertex {
+	p := position{vs[0].x, vs[0].y}
+	for _, m := range []float64{0, -1, 1, -2, 2} {
+		if !ringContains(parent, position{p[0] + 360*m, p[1]}) {
+			continue
+		}
+		if m != 0 {
+			for i := range vs {
+				vs[i].x += 360 * m
+			}
+		}
+		break
+	}
+	return vs
+}
+
+// FromShapeIndex returns a GeometryCollection with one geometry for each
+// shape in the index, in order of shape ID. Points, Polylines and Polygons
+// are encoded as by FromPointVector, FromPolyline and FromPolygon. Other
+// shapes are encoded according to their dimension: points as a MultiPoint,
+// the chains of polylines as a LineString or MultiLineString, and the chains
+// of polygons as the loops of a Polygon.
+func FromShapeIndex(index *s2.ShapeIndex) (*Geometry, error) {
+	g := &Geometry{Type: TypeGeometryCollection, Geometries: []*Geometry{}}
+	for id, found := int32(0), 0; found < index.Len(); id++ {
+		shape := index.Shape(id)
+		if shape == nil {
+			continue
+		}
+		found++
+		child, err := fromShape(shape)
+		if err != nil {
+			return nil, fmt.Errorf("geojson: shape %d: %v", id, err)
+		}
+		g.Geometries = append(g.Geometries, child)
+	}
+	return g, nil
+}
+
+// fromShape returns the geometry for a single shape.
+func fromShape(shape s2.Shape) (*Geometry, error) {
+	switch s := shape.(type) {
+	case *s2.PointVector:
+		return fromPoints(*s)
+	case *s2.Polyline:
+		return fromChains([][]s2.Point{*s})
+	case *s2.Polygon:
+		return fromPolygon(s)
+	case *s2.Loop:
+		if s.IsFull() {
+			return nil, errFull
+		}
+		var loops []*s2.Loop
+		if !s.IsEmpty() {
+			loops = append(loops, s2.LoopFromPoints(s.Vertices()))
+		}
+		return fromPolygon(s2.PolygonFromLoops(loops))
+	}
+
+	switch shape.Dimension() {
+	case 0:
+		points := make(s2.PointVector, 0, shape.NumEdges())
+		for i := 0; i < shape.NumEdges(); i++ {
+			points = append(points, shape.Edge(i).V0)
+		}
+		return fromPoints(points)
+	case 1:
+		var chains [][]s2.Point
+		for c := 0; c < shape.NumChains(); c++ {
+			chain := shape.Chain(c)
+			var points []s2.Point
+			for j := 0; j < chain.Length; j++ {
+				e := shape.ChainEdge(c, j)
+				if j == 0 {
+					points = append(points, e.V0)
+				}
+				points = append(points, e.V1)
+			}
+			chains = append(chains, points)
+		}
+		return fromChains(chains)
+	default:
+		if shape.NumEdges() == 0 && shape.NumChains() > 0 {
+			return nil, errFull
+		}
+		var loops []*s2.Loop
+		for c := 0; c < shape.NumChains(); c++ {
+			chain := shape.Chain(c)
+			points := make([]s2.Point, 0, chain.Length)
+			for j := 0; j < chain.Length; j++ {
+				points = append(points, shape.ChainEdge(c, j).V0)
+			}
+			loops = append(loops, s2.LoopFromPoints(points))
+		}
+		p := s2.PolygonFromOrientedLoops(loops)
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+		return fromPolygon(p)
+	}
+}
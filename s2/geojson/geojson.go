@@ -0,0 +1,160 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package geojson converts between GeoJSON geometries (RFC 7946) and the types
+of the s2 package.
+
+A Geometry is the GeoJSON geometry object, and can be marshaled and
+unmarshaled with encoding/json. The From* functions build a Geometry from s2
+values, and the methods of Geometry convert it back.
+
+GeoJSON describes edges as straight lines in longitude/latitude coordinates,
+whereas s2 edges are geodesics. Vertices are converted exactly, and edges are
+reinterpreted as geodesics; clients that need to preserve the planar shape of
+long edges should densify them first.
+
+# Antimeridian
+
+RFC 7946 recommends that geometries crossing the antimeridian are cut in two,
+so that no edge spans more than 180 degrees of longitude. The encoders follow
+this recommendation: polylines that cross the antimeridian become
+MultiLineStrings and polygons become MultiPolygons, with the cut vertices placed
+exactly at longitude 180 and -180. Polygons that contain a pole are closed
+along the pole.
+
+The decoders undo the cutting. Positions at longitude -180 are treated as
+being at longitude 180, line strings that meet at the antimeridian are joined,
+and pairs of polygon edges that run along the antimeridian in opposite
+directions cancel out, which merges the pieces of a cut polygon back into one.
+Vertices on the antimeridian that lie on the edge between their neighbors,
+such as those added by cutting, are removed.
+
+# Ring orientation
+
+RFC 7946 requires exterior rings to be counterclockwise and holes to be
+clockwise, but notes that parsers should not reject rings with the wrong
+orientation. The decoders follow the right-hand rule: the region bounded by an
+exterior ring is on its left, and the hole bounded by an interior ring is on
+its right, so a ring can bound a region larger than a hemisphere. Only a ring
+whose region would be larger than a hemisphere and that also winds the wrong
+way in longitude and latitude is taken to have the wrong orientation, and is
+interpreted as the boundary of the smaller of the two regions it encloses.
+Polygons whose outermost loop is a hole, for example the complement of a
+small region, have no exterior ring in GeoJSON, and do not survive a round
+trip.
+*/
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// The GeoJSON geometry types.
+const (
+	TypePoint              = "Point"
+	TypeMultiPoint         = "MultiPoint"
+	TypeLineString         = "LineString"
+	TypeMultiLineString    = "MultiLineString"
+	TypePolygon            = "Polygon"
+	TypeMultiPolygon       = "MultiPolygon"
+	TypeGeometryCollection = "GeometryCollection"
+)
+
+// Geometry is a GeoJSON geometry object. Coordinates holds the raw
+// coordinates member, whose structure depends on Type; it is empty for a
+// GeometryCollection, which uses Geometries instead.
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates,omitempty"`
+	Geometries  []*Geometry     `json:"geometries,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. A GeometryCollection always has a
+// geometries member, even when it is empty.
+func (g *Geometry) MarshalJSON() ([]byte, error) {
+	if g.Type == TypeGeometryCollection {
+		geometries := g.Geometries
+		if geometries == nil {
+			geometries = []*Geometry{}
+		}
+		return json.Marshal(struct {
+			Type       string      `json:"type"`
+			Geometries []*Geometry `json:"geometries"`
+		}{g.Type, geometries})
+	}
+	coords := g.Coordinates
+	if coords == nil {
+		coords = json.RawMessage("[]")
+	}
+	return json.Marshal(struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}{g.Type, coords})
+}
+
+// newGeometry returns a Geometry of the given type with the given
+// coordinates, which must be a nested slice of positions. An error is
+// returned if a coordinate is not finite, e.g. for a point with NaN
+// components, since JSON has no representation for it.
+func newGeometry(typ string, coords interface{}) (*Geometry, error) {
+	data, err := json.Marshal(coords)
+	if err != nil {
+		return nil, fmt.Errorf("%s has invalid coordinates: %v", typ, err)
+	}
+	return &Geometry{Type: typ, Coordinates: data}, nil
+}
+
+// position is a GeoJSON position, i.e. a [longitude, latitude] pair in
+// degrees.
+type position [2]float64
+
+// positionFromLatLng returns the position of the given LatLng.
+func positionFromLatLng(ll s2.LatLng) position {
+	return position{ll.Lng.Degrees(), ll.Lat.Degrees()}
+}
+
+// pointFromPosition converts a GeoJSON position to a Point. Positions may
+// have extra elements such as an altitude, which are ignored.
+func pointFromPosition(pos []float64) (s2.Point, error) {
+	if len(pos) < 2 {
+		return s2.Point{}, fmt.Errorf("position has %d elements, want at least 2", len(pos))
+	}
+	lng, lat := pos[0], pos[1]
+	if math.IsNaN(lng) || math.IsInf(lng, 0) || lng < -180 || lng > 180 {
+		return s2.Point{}, fmt.Errorf("longitude %v is out of range [-180, 180]", lng)
+	}
+	if math.IsNaN(lat) || math.IsInf(lat, 0) || lat < -90 || lat > 90 {
+		return s2.Point{}, fmt.Errorf("latitude %v is out of range [-90, 90]", lat)
+	}
+	// Make sure that both sides of the antimeridian, and all the longitudes
+	// at a pole, map to the same point.
+	if lng == -180 {
+		lng = 180
+	}
+	if lat == 90 || lat == -90 {
+		lng = 0
+	}
+	return s2.PointFromLatLng(s2.LatLngFromDegrees(lat, lng)), nil
+}
+
+// onAntimeridian reports whether the position is on the antimeridian, which
+// includes the poles.
+func onAntimeridian(pos []float64) bool {
+	return pos[0] == 180 || pos[0] == -180 || pos[1] == 90 || pos[1] == -90
+}
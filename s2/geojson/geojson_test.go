@@ -0,0 +1,446 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geojson
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/rubenpoppe/geo/r3"
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// parseGeometry unmarshals a GeoJSON geometry.
+func parseGeometry(t *testing.T, s string) *Geometry {
+	t.Helper()
+	var g Geometry
+	if err := json.Unmarshal([]byte(s), &g); err != nil {
+		t.Fatalf("json.Unmarshal(%s) failed: %v", s, err)
+	}
+	return &g
+}
+
+// roundTrip marshals the geometry to JSON and unmarshals it again.
+func roundTrip(t *testing.T, g *Geometry) *Geometry {
+	t.Helper()
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("json.Marshal(%v) failed: %v", g, err)
+	}
+	return parseGeometry(t, string(data))
+}
+
+// polygonsApproxEqual reports whether the two polygons have the same area and
+// agree on points close to the vertices of each other's loops.
+func polygonsApproxEqual(a, b *s2.Polygon) bool {
+	if math.Abs(a.Area()-b.Area()) > 1e-12 {
+		return false
+	}
+	for _, pair := range [][2]*s2.Polygon{{a, b}, {b, a}} {
+		for _, l := range pair[0].Loops() {
+			n := l.NumVertices()
+			for i := 0; i < n; i++ {
+				// A point close to vertex i, but not on the boundary.
+				v, next, prev := l.Vertex(i), l.Vertex((i+1)%n), l.Vertex((i+n-1)%n)
+				in := s2.Point{Vector: v.Add(next.Sub(v.Vector).Mul(1e-3)).Add(prev.Sub(v.Vector).Mul(1e-3)).Normalize()}
+				if pair[0].ContainsPoint(in) != pair[1].ContainsPoint(in) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func TestPoint(t *testing.T) {
+	g := parseGeometry(t, `{"type": "Point", "coordinates": [20, 10, 100]}`)
+	p, err := g.Point()
+	if err != nil {
+		t.Fatalf("Point() failed: %v", err)
+	}
+	if want := s2.PointFromLatLng(s2.LatLngFromDegrees(10, 20)); !p.ApproxEqual(want) {
+		t.Errorf("Point() = %v, want %v", p, want)
+	}
+
+	enc, err := FromPoint(p)
+	if err != nil {
+		t.Fatalf("FromPoint failed: %v", err)
+	}
+	got, err := roundTrip(t, enc).Point()
+	if err != nil {
+		t.Fatalf("round trip failed: %v", err)
+	}
+	if !got.ApproxEqual(p) {
+		t.Errorf("FromPoint(%v) round trip = %v", p, got)
+	}
+
+	// Both sides of the antimeridian and all longitudes at a pole are the
+	// same point.
+	a, _ := parseGeometry(t, `{"type": "Point", "coordinates": [180, 5]}`).Point()
+	b, _ := parseGeometry(t, `{"type": "Point", "coordinates": [-180, 5]}`).Point()
+	if a != b {
+		t.Errorf("points at longitude 180 and -180 differ: %v, %v", a, b)
+	}
+	a, _ = parseGeometry(t, `{"type": "Point", "coordinates": [30, 90]}`).Point()
+	b, _ = parseGeometry(t, `{"type": "Point", "coordinates": [-60, 90]}`).Point()
+	if a != b {
+		t.Errorf("points at the north pole differ: %v, %v", a, b)
+	}
+}
+
+func TestPointVector(t *testing.T) {
+	g := parseGeometry(t, `{"type": "MultiPoint", "coordinates": [[0, 0], [10, 20]]}`)
+	v, err := g.PointVector()
+	if err != nil {
+		t.Fatalf("PointVector() failed: %v", err)
+	}
+	if len(*v) != 2 || !(*v)[1].ApproxEqual(s2.PointFromLatLng(s2.LatLngFromDegrees(20, 10))) {
+		t.Errorf("PointVector() = %v", *v)
+	}
+	enc, err := FromPointVector(*v)
+	if err != nil {
+		t.Fatalf("FromPointVector failed: %v", err)
+	}
+	got, err := roundTrip(t, enc).PointVector()
+	if err != nil {
+		t.Fatalf("round trip failed: %v", err)
+	}
+	for i := range *v {
+		if !(*got)[i].ApproxEqual((*v)[i]) {
+			t.Errorf("FromPointVector round trip point %d = %v, want %v", i, (*got)[i], (*v)[i])
+		}
+	}
+	// A single Point is also accepted.
+	if v, err := parseGeometry(t, `{"type": "Point", "coordinates": [1, 2]}`).PointVector(); err != nil || len(*v) != 1 {
+		t.Errorf("PointVector() of a Point = %v, %v", v, err)
+	}
+}
+
+func TestPolyline(t *testing.T) {
+	g := parseGeometry(t, `{"type": "LineString", "coordinates": [[0, 0], [10, 0], [10, 0], [10, 10]]}`)
+	l, err := g.Polyline()
+	if err != nil {
+		t.Fatalf("Polyline() failed: %v", err)
+	}
+	// The duplicate vertex is removed.
+	if len(*l) != 3 {
+		t.Errorf("Polyline() has %d vertices, want 3", len(*l))
+	}
+	enc, err := FromPolyline(l)
+	if err != nil {
+		t.Fatalf("FromPolyline failed: %v", err)
+	}
+	if enc.Type != TypeLineString {
+		t.Errorf("FromPolyline type = %s, want %s", enc.Type, TypeLineString)
+	}
+	got, err := roundTrip(t, enc).Polyline()
+	if err != nil {
+		t.Fatalf("round trip failed: %v", err)
+	}
+	if !got.ApproxEqual(l) {
+		t.Errorf("FromPolyline round trip = %v, want %v", got, l)
+	}
+
+	lines, err := parseGeometry(t, `{"type": "MultiLineString", "coordinates": [[[0, 0], [1, 1]], [[2, 2], [3, 3]]]}`).Polylines()
+	if err != nil || len(lines) != 2 {
+		t.Errorf("Polylines() = %v, %v, want 2 polylines", lines, err)
+	}
+	if _, err := parseGeometry(t, `{"type": "MultiLineString", "coordinates": [[[0, 0], [1, 1]], [[2, 2], [3, 3]]]}`).Polyline(); err == nil {
+		t.Errorf("Polyline() of two separate line strings succeeded, want error")
+	}
+}
+
+func TestPolygon(t *testing.T) {
+	// A square with a square hole, with the right orientation.
+	g := parseGeometry(t, `{"type": "Polygon", "coordinates": [
+		[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]],
+		[[2, 2], [2, 8], [8, 8], [8, 2], [2, 2]]]}`)
+	p, err := g.Polygon()
+	if err != nil {
+		t.Fatalf("Polygon() failed: %v", err)
+	}
+	if p.NumLoops() != 2 {
+		t.Fatalf("Polygon() has %d loops, want 2", p.NumLoops())
+	}
+	if !p.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(1, 1))) || p.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(5, 5))) || p.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(20, 20))) {
+		t.Errorf("Polygon() does not have the expected interior")
+	}
+
+	// The same polygon with both rings reversed.
+	reversed, err := parseGeometry(t, `{"type": "Polygon", "coordinates": [
+		[[0, 0], [0, 10], [10, 10], [10, 0], [0, 0]],
+		[[2, 2], [8, 2], [8, 8], [2, 8], [2, 2]]]}`).Polygon()
+	if err != nil {
+		t.Fatalf("Polygon() with reversed rings failed: %v", err)
+	}
+	if !polygonsApproxEqual(p, reversed) {
+		t.Errorf("the orientation of the rings changed the polygon")
+	}
+
+	enc, err := FromPolygon(p)
+	if err != nil {
+		t.Fatalf("FromPolygon failed: %v", err)
+	}
+	if enc.Type != TypePolygon {
+		t.Errorf("FromPolygon type = %s, want %s", enc.Type, TypePolygon)
+	}
+	// The exterior ring is counterclockwise and the hole clockwise.
+	var rings [][][2]float64
+	if err := json.Unmarshal(enc.Coordinates, &rings); err != nil {
+		t.Fatal(err)
+	}
+	for i, ring := range rings {
+		var area float64
+		for k := 0; k+1 < len(ring); k++ {
+			area += ring[k][0]*ring[k+1][1] - ring[k+1][0]*ring[k][1]
+		}
+		if (area > 0) != (i == 0) {
+			t.Errorf("ring %d has signed area %v", i, area)
+		}
+		if ring[0] != ring[len(ring)-1] {
+			t.Errorf("ring %d is not closed", i)
+		}
+	}
+	got, err := roundTrip(t, enc).Polygon()
+	if err != nil {
+		t.Fatalf("round trip failed: %v", err)
+	}
+	if !polygonsApproxEqual(got, p) {
+		t.Errorf("FromPolygon round trip = %v, want %v", got, p)
+	}
+}
+
+func TestLargePolygon(t *testing.T) {
+	// A counterclockwise shell that bounds a region larger than a
+	// hemisphere, around everything but the poles and a strip near the
+	// antimeridian.
+	const band = `[[-170, -60], [-60, -60], [60, -60], [170, -60], [170, 60], [60, 60], [-60, 60], [-170, 60], [-170, -60]]`
+	p, err := parseGeometry(t, `{"type": "Polygon", "coordinates": [`+band+`]}`).Polygon()
+	if err != nil {
+		t.Fatalf("Polygon() failed: %v", err)
+	}
+	if got := p.Area() / (4 * math.Pi); got < 0.85 || got > 0.9 {
+		t.Errorf("Polygon() covers %v of the sphere, want about 0.88", got)
+	}
+	if !p.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0))) || p.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(90, 0))) || p.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(0, 180))) {
+		t.Errorf("Polygon() does not have the expected interior")
+	}
+
+	// A hole in the shell, which is also found with the wrong orientation.
+	for _, hole := range []string{
+		`[[-10, -10], [-10, 10], [10, 10], [10, -10], [-10, -10]]`,
+		`[[-10, -10], [10, -10], [10, 10], [-10, 10], [-10, -10]]`,
+	} {
+		holed, err := parseGeometry(t, `{"type": "Polygon", "coordinates": [`+band+`, `+hole+`]}`).Polygon()
+		if err != nil {
+			t.Fatalf("Polygon() with hole %s failed: %v", hole, err)
+		}
+		if holed.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0))) || !holed.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(30, 0))) || holed.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(90, 0))) {
+			t.Errorf("Polygon() with hole %s does not have the expected interior", hole)
+		}
+	}
+
+	// A small shell that winds the wrong way is taken to bound the smaller
+	// region.
+	small, err := parseGeometry(t, `{"type": "Polygon", "coordinates": [
+		[[0, 0], [0, 10], [10, 10], [10, 0], [0, 0]]]}`).Polygon()
+	if err != nil {
+		t.Fatalf("Polygon() of a clockwise shell failed: %v", err)
+	}
+	if !small.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(5, 5))) || small.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(20, 20))) {
+		t.Errorf("Polygon() of a clockwise shell does not have the expected interior")
+	}
+
+	// The large shell survives a round trip.
+	enc, err := FromPolygon(p)
+	if err != nil {
+		t.Fatalf("FromPolygon failed: %v", err)
+	}
+	got, err := roundTrip(t, enc).Polygon()
+	if err != nil {
+		t.Fatalf("round trip failed: %v", err)
+	}
+	if !polygonsApproxEqual(got, p) {
+		t.Errorf("FromPolygon round trip = %v, want %v", got, p)
+	}
+}
+
+func TestMultiPolygon(t *testing.T) {
+	// Two squares, the second with a hole containing an island.
+	g := parseGeometry(t, `{"type": "MultiPolygon", "coordinates": [
+		[[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]]],
+		[[[20, 0], [40, 0], [40, 20], [20, 20], [20, 0]], [[22, 2], [22, 18], [38, 18], [38, 2], [22, 2]]],
+		[[[25, 5], [35, 5], [35, 15], [25, 15], [25, 5]]]]}`)
+	p, err := g.Polygon()
+	if err != nil {
+		t.Fatalf("Polygon() failed: %v", err)
+	}
+	if p.NumLoops() != 4 {
+		t.Errorf("Polygon() has %d loops, want 4", p.NumLoops())
+	}
+	enc, err := FromPolygon(p)
+	if err != nil {
+		t.Fatalf("FromPolygon failed: %v", err)
+	}
+	if enc.Type != TypeMultiPolygon {
+		t.Errorf("FromPolygon type = %s, want %s", enc.Type, TypeMultiPolygon)
+	}
+	var polygons [][][][2]float64
+	if err := json.Unmarshal(enc.Coordinates, &polygons); err != nil {
+		t.Fatal(err)
+	}
+	if len(polygons) != 3 {
+		t.Errorf("FromPolygon has %d polygons, want 3", len(polygons))
+	}
+	got, err := roundTrip(t, enc).Polygon()
+	if err != nil {
+		t.Fatalf("round trip failed: %v", err)
+	}
+	if !polygonsApproxEqual(got, p) {
+		t.Errorf("FromPolygon round trip = %v, want %v", got, p)
+	}
+}
+
+func TestEmptyAndFull(t *testing.T) {
+	p, err := parseGeometry(t, `{"type": "Polygon", "coordinates": []}`).Polygon()
+	if err != nil || !p.IsEmpty() {
+		t.Errorf("Polygon() of empty coordinates = %v, %v, want empty polygon", p, err)
+	}
+	enc, err := FromPolygon(s2.PolygonFromLoops(nil))
+	if err != nil {
+		t.Fatalf("FromPolygon(empty) failed: %v", err)
+	}
+	if data, _ := json.Marshal(enc); string(data) != `{"type":"Polygon","coordinates":[]}` {
+		t.Errorf("FromPolygon(empty) = %s", data)
+	}
+	if _, err := FromPolygon(s2.FullPolygon()); err == nil {
+		t.Errorf("FromPolygon(full) succeeded, want error")
+	}
+	if data, _ := json.Marshal(&Geometry{Type: TypeGeometryCollection}); string(data) != `{"type":"GeometryCollection","geometries":[]}` {
+		t.Errorf("empty GeometryCollection = %s", data)
+	}
+}
+
+func TestEncodeNonFinite(t *testing.T) {
+	// JSON cannot represent NaN, so encoding it returns an error.
+	nan := s2.Point{Vector: r3.Vector{X: math.NaN(), Z: 1}}
+	a := s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0))
+	b := s2.PointFromLatLng(s2.LatLngFromDegrees(0, 10))
+	if g, err := FromPoint(nan); err == nil {
+		t.Errorf("FromPoint(%v) = %v, want error", nan, g)
+	}
+	if g, err := FromPointVector(s2.PointVector{a, nan}); err == nil {
+		t.Errorf("FromPointVector with NaN = %v, want error", g)
+	}
+	if g, err := FromPolyline(&s2.Polyline{a, nan, b}); err == nil {
+		t.Errorf("FromPolyline with NaN = %v, want error", g)
+	}
+	index := s2.NewShapeIndex()
+	index.Add(&s2.PointVector{nan})
+	if g, err := FromShapeIndex(index); err == nil {
+		t.Errorf("FromShapeIndex with NaN = %v, want error", g)
+	}
+}
+
+func TestShapeIndex(t *testing.T) {
+	g := parseGeometry(t, `{"type": "GeometryCollection", "geometries": [
+		{"type": "Point", "coordinates": [1, 1]},
+		{"type": "MultiLineString", "coordinates": [[[0, 0], [1, 1]], [[2, 2], [3, 3]]]},
+		{"type": "GeometryCollection", "geometries": [
+			{"type": "Polygon", "coordinates": [[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]]]}]}]}`)
+	index, err := g.ShapeIndex()
+	if err != nil {
+		t.Fatalf("ShapeIndex() failed: %v", err)
+	}
+	wantDims := []int{0, 1, 1, 2}
+	if index.Len() != len(wantDims) {
+		t.Fatalf("ShapeIndex() has %d shapes, want %d", index.Len(), len(wantDims))
+	}
+	for i, want := range wantDims {
+		if got := index.Shape(int32(i)).Dimension(); got != want {
+			t.Errorf("shape %d has dimension %d, want %d", i, got, want)
+		}
+	}
+
+	enc, err := FromShapeIndex(index)
+	if err != nil {
+		t.Fatalf("FromShapeIndex failed: %v", err)
+	}
+	wantTypes := []string{TypeMultiPoint, TypeLineString, TypeLineString, TypePolygon}
+	if len(enc.Geometries) != len(wantTypes) {
+		t.Fatalf("FromShapeIndex has %d geometries, want %d", len(enc.Geometries), len(wantTypes))
+	}
+	for i, want := range wantTypes {
+		if got := enc.Geometries[i].Type; got != want {
+			t.Errorf("geometry %d has type %s, want %s", i, got, want)
+		}
+	}
+	got, err := roundTrip(t, enc).ShapeIndex()
+	if err != nil {
+		t.Fatalf("round trip failed: %v", err)
+	}
+	if got.Len() != index.Len() {
+		t.Errorf("round trip has %d shapes, want %d", got.Len(), index.Len())
+	}
+
+	// Shapes that are not one of the s2 types are encoded by dimension.
+	loop := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 1)), s2.PointFromLatLng(s2.LatLngFromDegrees(1, 0))})
+	other := s2.NewShapeIndex()
+	other.Add(loop)
+	enc, err = FromShapeIndex(other)
+	if err != nil || enc.Geometries[0].Type != TypePolygon {
+		t.Errorf("FromShapeIndex(loop) = %v, %v, want a Polygon", enc, err)
+	}
+	full := s2.NewShapeIndex()
+	full.Add(s2.FullPolygon())
+	if _, err := FromShapeIndex(full); err == nil {
+		t.Errorf("FromShapeIndex(full polygon) succeeded, want error")
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		json string
+		want string
+	}{
+		{`{"type": "LineString", "coordinates": [[0, 0], [1, 1]]}`, `geometry type is "LineString", want one of [Polygon MultiPolygon]`},
+		{`{"type": "Polygon"}`, "Polygon has no coordinates"},
+		{`{"type": "Polygon", "coordinates": [[0, 0]]}`, "invalid Polygon coordinates"},
+		{`{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [0, 0]]]}`, "ring 0: ring has 3 positions, want at least 4"},
+		{`{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 1]]]}`, "ring 0: ring is not closed: first position [0 0] differs from last position [0 1]"},
+		{`{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 91], [0, 0]]]}`, "ring 0: position 2: latitude 91 is out of range [-90, 90]"},
+		{`{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [0, 0], [1, 0], [0, 0]]]}`, "ring 0: ring has 2 distinct vertices, want at least 3"},
+		{`{"type": "Polygon", "coordinates": [[[0, 0], [1, 1], [1, 0], [0, 1], [0, 0]]]}`, "ring 0: invalid ring: edge 0 crosses edge 2"},
+		{`{"type": "MultiPolygon", "coordinates": [[[[0, 0], [1, 0], [1, 1], [0, 0]]], [[[0, 0], [1]]]]}`, "polygon 1, ring 0: ring has 2 positions"},
+		{`{"type": "Polygon", "coordinates": [[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]], [[5, 5], [15, 5], [15, 15], [5, 15], [5, 5]]]}`, "invalid Polygon"},
+	}
+	for _, test := range tests {
+		_, err := parseGeometry(t, test.json).Polygon()
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("Polygon() of %s = %v, want error containing %q", test.json, err, test.want)
+		}
+	}
+
+	_, err := parseGeometry(t, `{"type": "GeometryCollection", "geometries": [{"type": "Point", "coordinates": [0, 0]}, {"type": "Circle"}]}`).ShapeIndex()
+	if want := `geojson: geometry 1: unknown geometry type "Circle"`; err == nil || err.Error() != want {
+		t.Errorf("ShapeIndex() = %v, want %q", err, want)
+	}
+	_, err = parseGeometry(t, `{"type": "LineString", "coordinates": [[0, 0], [200, 0]]}`).Polyline()
+	if want := "geojson: position 1: longitude 200 is out of range [-180, 180]"; err == nil || err.Error() != want {
+		t.Errorf("Polyline() = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ogc converts between s2 geometry and the longitude and latitude
+// positions of the OGC Simple Features geometries, for the wkt and wkb
+// packages. It also decides the orientation of polygon rings, which the
+// geojson package shares.
+package ogc
+
+import (
+	"fmt"
+
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// Position is a [longitude, latitude] pair in degrees.
+type Position [2]float64
+
+// PointFromPosition returns the point at the given position, which has
+// already been checked to be in range.
+func PointFromPosition(pos Position) s2.Point {
+	return s2.PointFromLatLng(s2.LatLngFromDegrees(pos[1], pos[0]))
+}
+
+// PolylineFromPositions returns the polyline through the given positions,
+// which must contain at least two positions unless it is empty.
+func PolylineFromPositions(positions []Position) (*s2.Polyline, error) {
+	if len(positions) == 1 {
+		return nil, fmt.Errorf("line string has 1 position, want at least 2")
+	}
+	lls := make([]s2.LatLng, len(positions))
+	for i, pos := range positions {
+		lls[i] = s2.LatLngFromDegrees(pos[1], pos[0])
+	}
+	return s2.PolylineFromLatLngs(lls), nil
+}
+
+// LaxPolylineFromPositions returns the lax polyline through the given
+// positions, which may have any number of positions.
+func LaxPolylineFromPositions(positions []Position) *s2.LaxPolyline {
+	points := make([]s2.Point, len(positions))
+	for i, pos := range positions {
+		points[i] = PointFromPosition(pos)
+	}
+	return s2.LaxPolylineFromPoints(points)
+}
+
+// LaxPolygonFromRings returns the lax polygon with the given closed rings,
+// which are taken as written without any other checks. Exterior rings must be
+// counterclockwise and holes clockwise, so that the interior is on the left.
+func LaxPolygonFromRings(rings [][]Position) (*s2.LaxPolygon, error) {
+	loops := make([][]s2.Point, len(rings))
+	for i, ring := range rings {
+		if len(ring) == 0 {
+			return nil, fmt.Errorf("ring %d has no positions", i)
+		}
+		if first, last := ring[0], ring[len(ring)-1]; first != last {
+			return nil, fmt.Errorf("ring %d is not closed: first position %v differs from last position %v", i, first, last)
+		}
+		loops[i] = make([]s2.Point, 0, len(ring)-1)
+		for _, pos := range ring[:len(ring)-1] {
+			loops[i] = append(loops[i], PointFromPosition(pos))
+		}
+	}
+	return s2.LaxPolygonFromPoints(loops), nil
+}
+
+// LoopFromRing converts a closed ring to a loop that is oriented for
+// s2.PolygonFromOrientedLoops, as described by OrientLoop.
+func LoopFromRing(ring []Position, hole bool) (*s2.Loop, error) {
+	if len(ring) < 4 {
+		return nil, fmt.Errorf("ring has %d positions, want at least 4", len(ring))
+	}
+	if first, last := ring[0], ring[len(ring)-1]; first != last {
+		return nil, fmt.Errorf("ring is not closed: first position %v differs from last position %v", first, last)
+	}
+	var points []s2.Point
+	for _, pos := range ring[:len(ring)-1] {
+		p := PointFromPosition(pos)
+		if n := len(points); n > 0 && points[n-1] == p {
+			continue
+		}
+		points = append(points, p)
+	}
+	if len(points) > 1 && points[0] == points[len(points)-1] {
+		points = points[:len(points)-1]
+	}
+	if len(points) < 3 {
+		return nil, fmt.Errorf("ring has %d distinct vertices, want at least 3", len(points))
+	}
+	loop := s2.LoopFromPoints(points)
+	if err := CheckRing(loop); err != nil {
+		return nil, err
+	}
+	OrientLoop(loop, ring, hole)
+	return loop, nil
+}
+
+// CheckRing returns an error if the loop built from a ring is not valid. Unlike
+// Loop.Validate, it also reports edges of the loop that cross each other.
+func CheckRing(loop *s2.Loop) error {
+	if err := loop.Validate(); err != nil {
+		return fmt.Errorf("invalid ring: %v", err)
+	}
+	index := s2.NewShapeIndex()
+	index.Add(loop)
+	var err error
+	s2.VisitCrossingEdgePairs(index, s2.CrossingTypeInterior, func(a, b s2.ShapeEdge, _ bool) bool {
+		err = fmt.Errorf("invalid ring: edge %d crosses edge %d", a.ID.EdgeID, b.ID.EdgeID)
+		return false
+	})
+	return err
+}
+
+// OrientLoop inverts a loop built from the vertices of a closed ring as given
+// if the orientation of the ring is detectably wrong. Exterior rings are
+// counterclockwise and holes are clockwise, so the region that a ring bounds
+// is on the left of an exterior ring and on the right of a hole. That region
+// is taken as given if it is at most a hemisphere. A larger region is only
+// taken as given if the ring also winds the right way in longitude and
+// latitude; otherwise the ring is taken to bound the smaller region. This
+// keeps exterior rings larger than a hemisphere, while rings that are simply
+// reversed, as some encoders write them, still bound the region that was
+// meant.
+func OrientLoop(loop *s2.Loop, ring []Position, hole bool) {
+	if loop.IsNormalized() != hole {
+		return
+	}
+	var area float64
+	for i, a := range ring[:len(ring)-1] {
+		b := ring[i+1]
+		area += a[0]*b[1] - b[0]*a[1]
+	}
+	if (hole && area > 0) || (!hole && area < 0) {
+		loop.Invert()
+	}
+}
+
+// PolygonRings returns the rings of each shell of p and its holes, without
+// the closing vertex. Shells are counterclockwise and holes are clockwise.
+func PolygonRings(p *s2.Polygon) [][][]s2.Point {
+	var polygons [][][]s2.Point
+	for i, l := range p.Loops() {
+		if l.IsHole() {
+			continue
+		}
+		rings := [][]s2.Point{l.Vertices()}
+		for k := i + 1; k <= p.LastDescendant(i); k++ {
+			if parent, _ := p.Parent(k); parent == i {
+				rings = append(rings, reversed(p.Loop(k).Vertices()))
+			}
+		}
+		polygons = append(polygons, rings)
+	}
+	return polygons
+}
+
+// reversed returns the points in reverse order.
+func reversed(points []s2.Point) []s2.Point {
+	r := make([]s2.Point, len(points))
+	for i, p := range points {
+		r[len(points)-1-i] = p
+	}
+	return r
+}
+
+// ShapeChains returns the vertices of each chain of the shape. If closed is
+// true the chains are loops, and the last vertex is not repeated.
+func ShapeChains(shape s2.Shape, closed bool) [][]s2.Point {
+	chains := make([][]s2.Point, 0, shape.NumChains())
+	for c := 0; c < shape.NumChains(); c++ {
+		chain := shape.Chain(c)
+		points := make([]s2.Point, 0, chain.Length+1)
+		for j := 0; j < chain.Length; j++ {
+			e := shape.ChainEdge(c, j)
+			points = append(points, e.V0)
+			if j == chain.Length-1 && !closed {
+				points = append(points, e.V1)
+			}
+		}
+		chains = append(chains, points)
+	}
+	return chains
+}
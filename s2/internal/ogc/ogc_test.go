@@ -0,0 +1,127 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// reversedRing returns the closed ring in reverse order.
+func reversedRing(ring []Position) []Position {
+	r := make([]Position, len(ring))
+	for i, pos := range ring {
+		r[len(ring)-1-i] = pos
+	}
+	return r
+}
+
+func TestLoopFromRingOrientation(t *testing.T) {
+	// A counterclockwise square, which is smaller than a hemisphere.
+	square := []Position{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+	// A counterclockwise ring around everything but the poles and a strip
+	// near the antimeridian, which is larger than a hemisphere.
+	band := []Position{{-170, -60}, {-60, -60}, {60, -60}, {170, -60}, {170, 60}, {60, 60}, {-60, 60}, {-170, 60}, {-170, -60}}
+
+	tests := []struct {
+		name string
+		ring []Position
+		hole bool
+		// The points that are inside the region bounded by the ring, and
+		// outside it.
+		inside, outside s2.Point
+	}{
+		{"square shell", square, false, s2.PointFromLatLng(s2.LatLngFromDegrees(5, 5)), s2.PointFromLatLng(s2.LatLngFromDegrees(20, 20))},
+		{"clockwise square shell", reversedRing(square), false, s2.PointFromLatLng(s2.LatLngFromDegrees(5, 5)), s2.PointFromLatLng(s2.LatLngFromDegrees(20, 20))},
+		{"square hole", reversedRing(square), true, s2.PointFromLatLng(s2.LatLngFromDegrees(5, 5)), s2.PointFromLatLng(s2.LatLngFromDegrees(20, 20))},
+		{"counterclockwise square hole", square, true, s2.PointFromLatLng(s2.LatLngFromDegrees(5, 5)), s2.PointFromLatLng(s2.LatLngFromDegrees(20, 20))},
+		{"band shell", band, false, s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(90, 0))},
+		// The region on the left of a clockwise band is smaller than a
+		// hemisphere, so the ring is taken as given.
+		{"clockwise band shell", reversedRing(band), false, s2.PointFromLatLng(s2.LatLngFromDegrees(90, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0))},
+		{"band hole", reversedRing(band), true, s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(90, 0))},
+	}
+	for _, test := range tests {
+		loop, err := LoopFromRing(test.ring, test.hole)
+		if err != nil {
+			t.Errorf("%s: LoopFromRing failed: %v", test.name, err)
+			continue
+		}
+		// The loop of a hole contains the polygon around it.
+		if loop.ContainsPoint(test.inside) == test.hole || loop.ContainsPoint(test.outside) != test.hole {
+			t.Errorf("%s: LoopFromRing(%v, %v) does not bound the expected region", test.name, test.ring, test.hole)
+		}
+	}
+}
+
+func TestLoopFromRingErrors(t *testing.T) {
+	tests := []struct {
+		ring []Position
+		want string
+	}{
+		{[]Position{{0, 0}, {10, 0}, {0, 0}}, "want at least 4"},
+		{[]Position{{0, 0}, {10, 0}, {10, 10}, {0, 1}}, "not closed"},
+		{[]Position{{0, 0}, {10, 0}, {10, 0}, {0, 0}}, "distinct vertices"},
+		{[]Position{{0, 0}, {10, 10}, {10, 0}, {0, 10}, {0, 0}}, "crosses"},
+	}
+	for _, test := range tests {
+		if _, err := LoopFromRing(test.ring, false); err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("LoopFromRing(%v) = %v, want an error containing %q", test.ring, err, test.want)
+		}
+	}
+}
+
+func TestPolygonRings(t *testing.T) {
+	shell := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 0))})
+	hole := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(2, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(2, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(4, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(4, 2))})
+	island := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(20, 20)), s2.PointFromLatLng(s2.LatLngFromDegrees(20, 21)), s2.PointFromLatLng(s2.LatLngFromDegrees(21, 21))})
+	polygons := PolygonRings(s2.PolygonFromLoops([]*s2.Loop{shell, hole, island}))
+	if len(polygons) != 2 || len(polygons[0])+len(polygons[1]) != 3 {
+		t.Fatalf("PolygonRings = %v, want two polygons with three rings", polygons)
+	}
+	for _, rings := range polygons {
+		for i, ring := range rings {
+			// Shells are counterclockwise and holes clockwise, so the
+			// ring read back as given gives a loop with the same
+			// orientation as in the polygon.
+			positions := make([]Position, 0, len(ring)+1)
+			for k := 0; k <= len(ring); k++ {
+				l := s2.LatLngFromPoint(ring[k%len(ring)])
+				positions = append(positions, Position{l.Lng.Degrees(), l.Lat.Degrees()})
+			}
+			loop, err := LoopFromRing(positions, i > 0)
+			if err != nil {
+				t.Errorf("LoopFromRing(%v) failed: %v", positions, err)
+				continue
+			}
+			if loop.IsNormalized() == (i > 0) {
+				t.Errorf("ring %d of %v has the wrong orientation", i, rings)
+			}
+		}
+	}
+}
+
+func TestShapeChains(t *testing.T) {
+	line := s2.Polyline{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 1)), s2.PointFromLatLng(s2.LatLngFromDegrees(1, 1))}
+	if got := ShapeChains(&line, false); len(got) != 1 || len(got[0]) != 3 {
+		t.Errorf("ShapeChains(%v, false) = %v, want one chain of 3 points", line, got)
+	}
+	loop := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 1)), s2.PointFromLatLng(s2.LatLngFromDegrees(1, 1))})
+	if got := ShapeChains(loop, true); len(got) != 1 || len(got[0]) != 3 {
+		t.Errorf("ShapeChains(%v, true) = %v, want one chain of 3 points", loop, got)
+	}
+}
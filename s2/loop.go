@@ -409,6 +409,109 @@ func (l *Loop) BoundaryEqual(o *Loop) bool {
 	return false
 }
 
+// BoundaryApproxEqual reports whether the two loops have the same boundary
+// except for vertex perturbations. More precisely, the vertices in the two
+// loops must be in the same cyclic order, and corresponding vertex pairs must
+// be separated by no more than maxError.
+func (l *Loop) BoundaryApproxEqual(o *Loop, maxError s1.Angle) bool {
+	if len(l.vertices) != len(o.vertices) {
+		return false
+	}
+
+	// Special case to handle empty or full loops.
+	if l.isEmptyOrFull() {
+		return l.IsEmpty() == o.IsEmpty()
+	}
+
+	for offset := range l.vertices {
+		if !l.Vertex(offset).approxEqual(o.Vertex(0), maxError) {
+			continue
+		}
+		success := true
+		for i := 0; i < len(l.vertices); i++ {
+			if !l.Vertex(i+offset).approxEqual(o.Vertex(i), maxError) {
+				success = false
+				break
+			}
+		}
+		if success {
+			return true
+		}
+		// Otherwise continue looping. There may be more than one candidate
+		// starting offset since vertices are only matched approximately.
+	}
+	return false
+}
+
+// BoundaryNear reports whether the two loops have boundaries within maxError
+// of each other along their entire lengths. The two loops may have different
+// numbers of vertices. More precisely, this method returns true if the two
+// loops have parameterizations a:[0,1] -> S^2, b:[0,1] -> S^2 such that
+// distance(a(t), b(t)) <= maxError for all t. You can think of this as
+// testing whether it is possible to drive two cars all the way around the two
+// loops such that no car ever goes backward and the cars are always within
+// maxError of each other.
+func (l *Loop) BoundaryNear(o *Loop, maxError s1.Angle) bool {
+	// Special case to handle empty or full loops.
+	if l.isEmptyOrFull() || o.isEmptyOrFull() {
+		return (l.IsEmpty() && o.IsEmpty()) || (l.IsFull() && o.IsFull())
+	}
+
+	for offset := range l.vertices {
+		if l.matchBoundaries(o, offset, maxError) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchBoundaries reports whether the boundary of this loop, starting at the
+// given vertex offset, can be followed alongside the boundary of o starting
+// at its vertex 0 while staying within maxError of it.
+//
+// The state consists of a pair (i,j). A state transition consists of
+// incrementing either i or j. i can be incremented only if
+// l(i+1+offset) is near the edge from o(j) to o(j+1), and a similar rule
+// applies to j. The function returns true if we can proceed all the way
+// around both loops in this way.
+//
+// Note that when i and j can both be incremented, sometimes only one choice
+// leads to a solution. We handle this using a stack and backtracking. We
+// also keep track of which states have already been explored to avoid
+// duplicating work.
+func (l *Loop) matchBoundaries(o *Loop, offset int, maxError s1.Angle) bool {
+	type state struct{ i, j int }
+	na, nb := len(l.vertices), len(o.vertices)
+	pending := []state{{0, 0}}
+	done := make(map[state]bool)
+	for len(pending) > 0 {
+		s := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if s.i == na && s.j == nb {
+			return true
+		}
+		done[s] = true
+
+		// If i == na and offset == na-1, then i+1+offset overflows the
+		// [0, 2*na-1] range allowed by Vertex, so reduce the range if
+		// necessary.
+		io := s.i + offset
+		if io >= na {
+			io -= na
+		}
+
+		if s.i < na && !done[state{s.i + 1, s.j}] &&
+			DistanceFromSegment(l.Vertex(io+1), o.Vertex(s.j), o.Vertex(s.j+1)) <= maxError {
+			pending = append(pending, state{s.i + 1, s.j})
+		}
+		if s.j < nb && !done[state{s.i, s.j + 1}] &&
+			DistanceFromSegment(o.Vertex(s.j+1), l.Vertex(io), l.Vertex(io+1)) <= maxError {
+			pending = append(pending, state{s.i, s.j + 1})
+		}
+	}
+	return false
+}
+
 // compareBoundary returns +1 if this loop contains the boundary of the other loop,
 // -1 if it excludes the boundary of the other, and 0 if the boundaries of the two
 // loops cross. Shared edges are handled as follows:
@@ -1263,7 +1366,7 @@ func (l Loop) encode(e *encoder) {
 // Decode decodes a loop.
 func (l *Loop) Decode(r io.Reader) error {
 	*l = Loop{}
-	d := &decoder{r: asByteReader(r)}
+	d := newDecoder(r, DecodeOptions{})
 	l.decode(d)
 	return d.err
 }
@@ -1274,29 +1377,27 @@ func (l *Loop) decode(d *decoder) {
 		return
 	}
 	if version != encodingVersion {
-		d.err = fmt.Errorf("cannot decode version %d", version)
+		d.err = fmt.Errorf("%w: cannot decode version %d", ErrDecodeVersion, version)
 		return
 	}
 
 	// Empty loops are explicitly allowed here: a newly created loop has zero vertices
 	// and such loops encode and decode properly.
 	nvertices := d.readUint32()
-	if nvertices > maxEncodedVertices {
-		if d.err == nil {
-			d.err = fmt.Errorf("too many vertices (%d; max is %d)", nvertices, maxEncodedVertices)
-		}
+	if !d.checkCount(uint64(nvertices), maxEncodedVertices, "vertices") {
 		return
 	}
-	l.vertices = make([]Point, nvertices)
-	for i := range l.vertices {
-		l.vertices[i].X = d.readFloat64()
-		l.vertices[i].Y = d.readFloat64()
-		l.vertices[i].Z = d.readFloat64()
+	l.vertices = d.readPoints(uint64(nvertices))
+	if !d.checkPoints(l.vertices) {
+		return
 	}
 	l.index = NewShapeIndex()
 	l.originInside = d.readBool()
 	l.depth = int(d.readUint32())
 	l.bound.decode(d)
+	if d.err != nil {
+		return
+	}
 	l.subregionBound = ExpandForSubregions(l.bound)
 
 	l.index.Add(l)
@@ -1361,15 +1462,13 @@ func (l *Loop) compressedEncodingProperties() uint64 {
 
 func (l *Loop) decodeCompressed(d *decoder, snapLevel int) {
 	nvertices := d.readUvarint()
-	if d.err != nil {
+	if !d.checkCount(nvertices, maxEncodedVertices, "vertices") {
 		return
 	}
-	if nvertices > maxEncodedVertices {
-		d.err = fmt.Errorf("too many vertices (%d; max is %d)", nvertices, maxEncodedVertices)
+	l.vertices = decodePointsCompressed(d, snapLevel, int(nvertices))
+	if !d.checkPoints(l.vertices) {
 		return
 	}
-	l.vertices = make([]Point, nvertices)
-	decodePointsCompressed(d, snapLevel, l.vertices)
 	properties := d.readUvarint()
 
 	// Make sure values are valid before using.
@@ -1564,9 +1663,9 @@ func (l *loopCrosser) cellCrossesAnySubcell(aClipped *clippedShape, bID CellID)
 }
 
 // hasCrossing reports whether given two iterators positioned such that
-// ai.cellID().ContainsCellID(bi.cellID()), there is an edge or wedge crossing
-// anywhere within ai.cellID(). This function advances bi only past ai.cellID().
-func (l *loopCrosser) hasCrossing(ai, bi *rangeIterator) bool {
+// ai.CellID().ContainsCellID(bi.CellID()), there is an edge or wedge crossing
+// anywhere within ai.CellID(). This function advances bi only past ai.CellID().
+func (l *loopCrosser) hasCrossing(ai, bi *RangeIterator) bool {
 	// If ai.CellID() intersects many edges of B, then it is faster to use
 	// CrossingEdgeQuery to narrow down the candidates. But if it intersects
 	// only a few edges, it is faster to check all the crossings directly.
@@ -1581,16 +1680,16 @@ func (l *loopCrosser) hasCrossing(ai, bi *rangeIterator) bool {
 			totalEdges += n
 			if totalEdges >= edgeQueryMinEdges {
 				// There are too many edges to test them directly, so use CrossingEdgeQuery.
-				if l.cellCrossesAnySubcell(ai.it.IndexCell().shapes[0], ai.cellID()) {
+				if l.cellCrossesAnySubcell(ai.it.IndexCell().shapes[0], ai.CellID()) {
 					return true
 				}
-				bi.seekBeyond(ai)
+				bi.SeekBeyond(ai)
 				return false
 			}
-			l.bCells = append(l.bCells, bi.indexCell())
+			l.bCells = append(l.bCells, bi.IndexCell())
 		}
-		bi.next()
-		if bi.cellID() > ai.rangeMax {
+		bi.Next()
+		if bi.CellID() > ai.rangeMax {
 			break
 		}
 	}
@@ -1614,39 +1713,39 @@ func containsCenterMatches(a *clippedShape, target crossingTarget) bool {
 }
 
 // hasCrossingRelation reports whether given two iterators positioned such that
-// ai.cellID().ContainsCellID(bi.cellID()), there is a crossing relationship
-// anywhere within ai.cellID(). Specifically, this method returns true if there
+// ai.CellID().ContainsCellID(bi.CellID()), there is a crossing relationship
+// anywhere within ai.CellID(). Specifically, this method returns true if there
 // is an edge crossing, a wedge crossing, or a point P that matches both relations
-// crossing targets. This function advances both iterators past ai.cellID.
-func (l *loopCrosser) hasCrossingRelation(ai, bi *rangeIterator) bool {
+// crossing targets. This function advances both iterators past ai.CellID().
+func (l *loopCrosser) hasCrossingRelation(ai, bi *RangeIterator) bool {
 	aClipped := ai.it.IndexCell().shapes[0]
 	if aClipped.numEdges() != 0 {
 		// The current cell of A has at least one edge, so check for crossings.
 		if l.hasCrossing(ai, bi) {
 			return true
 		}
-		ai.next()
+		ai.Next()
 		return false
 	}
 
 	if containsCenterMatches(aClipped, l.aCrossingTarget) {
 		// The crossing target for A is not satisfied, so we skip over these cells of B.
-		bi.seekBeyond(ai)
-		ai.next()
+		bi.SeekBeyond(ai)
+		ai.Next()
 		return false
 	}
 
-	// All points within ai.cellID() satisfy the crossing target for A, so it's
+	// All points within ai.CellID() satisfy the crossing target for A, so it's
 	// worth iterating through the cells of B to see whether any cell
 	// centers also satisfy the crossing target for B.
-	for bi.cellID() <= ai.rangeMax {
+	for bi.CellID() <= ai.rangeMax {
 		bClipped := bi.it.IndexCell().shapes[0]
 		if containsCenterMatches(bClipped, l.bCrossingTarget) {
 			return true
 		}
-		bi.next()
+		bi.Next()
 	}
-	ai.next()
+	ai.Next()
 	return false
 }
 
@@ -1667,19 +1766,19 @@ func (l *loopCrosser) hasCrossingRelation(ai, bi *rangeIterator) bool {
 func hasCrossingRelation(a, b *Loop, relation loopRelation) bool {
 	// We look for CellID ranges where the indexes of A and B overlap, and
 	// then test those edges for crossings.
-	ai := newRangeIterator(a.index)
-	bi := newRangeIterator(b.index)
+	ai := NewRangeIterator(a.index)
+	bi := NewRangeIterator(b.index)
 
 	ab := newLoopCrosser(a, b, relation, false) // Tests edges of A against B
 	ba := newLoopCrosser(b, a, relation, true)  // Tests edges of B against A
 
-	for !ai.done() || !bi.done() {
+	for !ai.Done() || !bi.Done() {
 		if ai.rangeMax < bi.rangeMin {
 			// The A and B cells don't overlap, and A precedes B.
-			ai.seekTo(bi)
+			ai.SeekTo(bi)
 		} else if bi.rangeMax < ai.rangeMin {
 			// The A and B cells don't overlap, and B precedes A.
-			bi.seekTo(ai)
+			bi.SeekTo(ai)
 		} else {
 			// One cell contains the other. Determine which cell is larger.
 			abRelation := int64(ai.it.CellID().lsb() - bi.it.CellID().lsb())
@@ -1707,8 +1806,8 @@ func hasCrossingRelation(a, b *Loop, relation loopRelation) bool {
 				if aClipped.numEdges() > 0 && bClipped.numEdges() > 0 && ab.cellCrossesCell(aClipped, bClipped) {
 					return true
 				}
-				ai.next()
-				bi.next()
+				ai.Next()
+				bi.Next()
 			}
 		}
 	}
@@ -1832,5 +1931,3 @@ func (l *Loop) containsNonCrossingBoundary(other *Loop, reverseOther bool) bool
 // DistanceToBoundary
 // Project
 // ProjectToBoundary
-// BoundaryApproxEqual
-// BoundaryNear
@@ -713,6 +713,71 @@ func TestLoopEqual(t *testing.T) {
 	}
 }
 
+func TestLoopBoundaryApproxEqual(t *testing.T) {
+	perturbed := func(l *Loop, eps float64) *Loop {
+		vertices := make([]Point, len(l.vertices))
+		for i, v := range l.vertices {
+			vertices[i] = Point{v.Add(r3.Vector{X: eps, Y: -eps, Z: eps}).Normalize()}
+		}
+		return LoopFromPoints(vertices)
+	}
+
+	tests := []struct {
+		a, b *Loop
+		want bool
+	}{
+		{a: EmptyLoop(), b: EmptyLoop(), want: true},
+		{a: FullLoop(), b: FullLoop(), want: true},
+		{a: EmptyLoop(), b: FullLoop(), want: false},
+		{a: candyCane, b: candyCane, want: true},
+		{a: candyCane, b: rotate(rotate(candyCane)), want: true},
+		{a: candyCane, b: perturbed(rotate(candyCane), 1e-16), want: true},
+		{a: candyCane, b: perturbed(candyCane, 1e-6), want: false},
+		{a: candyCane, b: smallNECW, want: false},
+	}
+
+	for _, test := range tests {
+		if got := test.a.BoundaryApproxEqual(test.b, 1e-15); got != test.want {
+			t.Errorf("%v.BoundaryApproxEqual(%v, 1e-15) = %t, want %t", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestLoopBoundaryNear(t *testing.T) {
+	const (
+		// Two triangles that backtrack a bit on different edges. A simple
+		// greedy matching algorithm would fail on this example.
+		t1 = "0.1:0, 0.1:1, 0.1:2, 0.1:3, 0.1:4, 1:4, 2:4, 3:4, 2:4.1, 1:4.1, 2:4.2, 3:4.2, 4:4.2, 5:4.2"
+		t2 = "0:0, 0:1, 0:2, 0:3, 0.1:2, 0.1:1, 0.2:2, 0.2:3, 0.2:4, 1:4.1, 2:4, 3:4, 4:4, 5:4"
+	)
+	tests := []struct {
+		a, b     string
+		maxError s1.Angle
+		want     bool
+	}{
+		{"empty", "empty", s1.Degree, true},
+		{"full", "full", s1.Degree, true},
+		{"empty", "full", s1.Degree, false},
+		{"empty", "0:0, 0:10, 5:5", s1.Degree, false},
+		{"0:0, 0:10, 5:5", "0:0.1, -0.1:9.9, 5:5.2", 0.5 * s1.Degree, true},
+		{"0:0, 0:3, 0:7, 0:10, 3:7, 5:5", "0:0, 0:10, 2:8, 5:5, 4:4, 3:3, 1:1", 0.5 * s1.Degree, true},
+		// All vertices close to some edge, but not equivalent.
+		{"0:0, 0:2, 2:2, 2:0", "0:0, 1.9999:1, 0:2, 2:2, 2:0", 0.5 * s1.Degree, false},
+		{t1, t2, 1.5 * s1.Degree, true},
+		{t1, t2, 0.5 * s1.Degree, false},
+	}
+
+	for _, test := range tests {
+		a, b := makeLoop(test.a), makeLoop(test.b)
+		if got := a.BoundaryNear(b, test.maxError); got != test.want {
+			t.Errorf("%v.BoundaryNear(%v, %v) = %t, want %t", a, b, test.maxError, got, test.want)
+		}
+		if got := b.BoundaryNear(a, test.maxError); got != test.want {
+			t.Errorf("%v.BoundaryNear(%v, %v) = %t, want %t", b, a, test.maxError, got, test.want)
+		}
+	}
+}
+
 func TestLoopContainsMatchesCrossingSign(t *testing.T) {
 	// This test demonstrates a former incompatibility between CrossingSign
 	// and ContainsPoint. It constructs a Cell-based loop L and
@@ -0,0 +1,779 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+// This file implements encoding.TextMarshaler, encoding.TextUnmarshaler,
+// json.Marshaler and json.Unmarshaler for the basic types. The text encodings
+// write angles in degrees with 15 significant digits, as by
+// s1.FormatDegrees, which is precise to about 10 nanometers on the Earth.
+// The JSON encodings of s1.Angle, LatLng, Point, CellID and Rect are the ones
+// that encoding/json gives them by default, with angles in radians and points
+// as their exact coordinates, so they lose no precision. The text encodings
+// are:
+//
+//   Point          "x,y,z", such as "1,0,0"
+//   CellUnion      tokens separated by commas, such as "89c25,89c27"
+//   Cap            "lat:lng,radius", such as "37.5:-122.25,0.5"
+//   Rect           "lo,hi" with the lo and hi corners as LatLngs
+//   Polyline       LatLngs separated by commas, such as "0:0, 0:1"
+//   Polygon        loops separated by semicolons, or "empty" or "full"
+//
+// and the JSON encodings are:
+//
+//   LatLng         {"Lat": 0.5, "Lng": -1.25}
+//   Point          {"X": 1, "Y": 0, "Z": 0}
+//   CellID         the cell ID as a number, such as 9926584489608216576
+//   CellUnion      an array of CellIDs
+//   Cap            {"center": LatLng, "radius": 0.5}
+//   Rect           {"Lat": {"Lo": 0.25, "Hi": 0.75}, "Lng": {"Lo": 0.5, "Hi": 1}}
+//   Polyline       an array of Points
+//   Polygon        an array of loops, each of which is an array of Points
+//
+// where a LatLng is written as "lat:lng" in degrees, such as "37.5:-122.25".
+//
+// CellID has no text encoding, so that it keeps its numbers as keys of JSON
+// objects; ToToken and CellIDFromToken convert it to and from its token, and
+// its JSON decoding also accepts the token as a string. Neither s1.Angle nor
+// LatLng has a text encoding, so that they keep their radians as keys of JSON
+// objects and in encoding/xml.
+//
+// Whitespace is allowed around the commas and semicolons that separate the
+// parts of the text encodings. Parsing is otherwise strict: numbers must be
+// finite, coordinates must be within range, JSON objects must have all of
+// their members and no others, and the resulting value must be valid. The
+// exceptions are the JSON encodings of LatLng and Rect, which accept and
+// write any finite values, as encoding/json does, so that values that are
+// not normalized are kept as they are.
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/rubenpoppe/geo/r1"
+	"github.com/rubenpoppe/geo/r3"
+	"github.com/rubenpoppe/geo/s1"
+)
+
+// unmarshalJSONStrict decodes the JSON value in data into v, rejecting unknown
+// object members.
+func unmarshalJSONStrict(data []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	return d.Decode(v)
+}
+
+// appendDegrees appends the text encoding of the angle.
+func appendDegrees(b []byte, a s1.Angle) ([]byte, error) {
+	if d := a.Degrees(); math.IsInf(d, 0) || math.IsNaN(d) {
+		return nil, fmt.Errorf("s2: cannot marshal angle %v", d)
+	}
+	return append(b, s1.FormatDegrees(a)...), nil
+}
+
+// splitList splits a text encoding at each separator, and trims whitespace
+// around the parts. An empty string has no parts.
+func splitList(s, sep string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	parts := strings.Split(s, sep)
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+// appendText appends the text encoding of the LatLng to b, which is
+// "lat:lng" in degrees. Invalid LatLngs cannot be marshaled.
+func (ll LatLng) appendText(b []byte) ([]byte, error) {
+	if !ll.IsValid() {
+		return nil, fmt.Errorf("s2: cannot marshal invalid LatLng %v", ll)
+	}
+	b, err := appendDegrees(b, ll.Lat)
+	if err != nil {
+		return nil, err
+	}
+	return appendDegrees(append(b, ':'), ll.Lng)
+}
+
+// parseLatLngText parses the text encoding of a LatLng, "lat:lng" in
+// degrees, where the latitude is in [-90, 90] and the longitude is in
+// [-180, 180].
+func parseLatLngText(s string) (LatLng, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return LatLng{}, fmt.Errorf("s2: invalid LatLng %q, want lat:lng", s)
+	}
+	lat, err := s1.ParseDegrees(parts[0])
+	if err != nil {
+		return LatLng{}, fmt.Errorf("s2: invalid latitude in LatLng %q", s)
+	}
+	lng, err := s1.ParseDegrees(parts[1])
+	if err != nil {
+		return LatLng{}, fmt.Errorf("s2: invalid longitude in LatLng %q", s)
+	}
+	ll := LatLng{lat, lng}
+	if !ll.IsValid() {
+		return LatLng{}, fmt.Errorf("s2: LatLng %q is out of range", s)
+	}
+	return ll, nil
+}
+
+// latLngJSON is the JSON encoding of a LatLng. The members are pointers so
+// that missing members can be detected.
+type latLngJSON struct {
+	Lat, Lng *s1.Angle
+}
+
+// MarshalJSON implements json.Marshaler. The LatLng is written as an object
+// with "Lat" and "Lng" members in radians, which is the encoding that
+// encoding/json gives a LatLng without this method. LatLngs that are not
+// normalized are written as they are.
+func (ll LatLng) MarshalJSON() ([]byte, error) {
+	return json.Marshal(latLngJSON{&ll.Lat, &ll.Lng})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts an object with "Lat"
+// and "Lng" members in radians, which are kept as they are even if they are
+// out of range. A JSON null leaves the LatLng unchanged.
+func (ll *LatLng) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var v latLngJSON
+	if err := unmarshalJSONStrict(data, &v); err != nil {
+		return fmt.Errorf("s2: invalid LatLng: %v", err)
+	}
+	if v.Lat == nil || v.Lng == nil {
+		return fmt.Errorf("s2: invalid LatLng %s, want Lat and Lng members", data)
+	}
+	*ll = LatLng{*v.Lat, *v.Lng}
+	return nil
+}
+
+// pointLatLng returns the LatLng of a point that is to be marshaled as one.
+func pointLatLng(p Point) (LatLng, error) {
+	if n := p.Norm2(); n == 0 || math.IsNaN(n) || math.IsInf(n, 0) {
+		return LatLng{}, fmt.Errorf("s2: cannot marshal point %v", p)
+	}
+	return LatLngFromPoint(p), nil
+}
+
+// parseCoordinate parses a finite decimal number, optionally with an
+// exponent, as written by strconv.FormatFloat.
+func parseCoordinate(s string) (float64, error) {
+	if s == "" || strings.Trim(s, "0123456789+-.eE") != "" {
+		return 0, fmt.Errorf("s2: invalid coordinate %q", s)
+	}
+	x, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(x, 0) {
+		return 0, fmt.Errorf("s2: invalid coordinate %q", s)
+	}
+	return x, nil
+}
+
+// MarshalText implements encoding.TextMarshaler. The point is written as
+// "x,y,z" with the shortest decimal numbers that parse back to its exact
+// coordinates. Points with infinite or NaN coordinates cannot be marshaled.
+func (p Point) MarshalText() ([]byte, error) {
+	var b []byte
+	for i, x := range []float64{p.X, p.Y, p.Z} {
+		if math.IsInf(x, 0) || math.IsNaN(x) {
+			return nil, fmt.Errorf("s2: cannot marshal point %v", p)
+		}
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = strconv.AppendFloat(b, x, 'g', -1, 64)
+	}
+	return b, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts "x,y,z" with
+// finite decimal coordinates, which are kept exactly as given.
+func (p *Point) UnmarshalText(text []byte) error {
+	parts := splitList(string(text), ",")
+	if len(parts) != 3 {
+		return fmt.Errorf("s2: invalid Point %q, want x,y,z", text)
+	}
+	var v [3]float64
+	for i, part := range parts {
+		x, err := parseCoordinate(part)
+		if err != nil {
+			return fmt.Errorf("s2: invalid Point %q: %v", text, err)
+		}
+		v[i] = x
+	}
+	*p = Point{r3.Vector{X: v[0], Y: v[1], Z: v[2]}}
+	return nil
+}
+
+// pointJSON is the JSON encoding of a Point. The members are pointers so that
+// missing members can be detected.
+type pointJSON struct {
+	X, Y, Z *float64
+}
+
+// MarshalJSON implements json.Marshaler. The point is written as an object
+// with its exact "X", "Y" and "Z" coordinates, which is the encoding that
+// encoding/json gives a Point without this method.
+func (p Point) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Vector)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts an object with "X",
+// "Y" and "Z" members, which are kept exactly as given. A JSON null leaves the
+// point unchanged.
+func (p *Point) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var v pointJSON
+	if err := unmarshalJSONStrict(data, &v); err != nil {
+		return fmt.Errorf("s2: invalid Point: %v", err)
+	}
+	if v.X == nil || v.Y == nil || v.Z == nil {
+		return fmt.Errorf("s2: invalid Point %s, want X, Y and Z members", data)
+	}
+	*p = Point{r3.Vector{X: *v.X, Y: *v.Y, Z: *v.Z}}
+	return nil
+}
+
+// cellIDFromToken returns the cell ID with the given token, which must be
+// exactly as written by ToToken. The token "X" gives the zero CellID.
+func cellIDFromToken(token string) (CellID, error) {
+	id := CellIDFromToken(token)
+	if (id == 0 && token != "X") || (id != 0 && !id.IsValid()) || id.ToToken() != token {
+		return 0, fmt.Errorf("s2: invalid CellID token %q", token)
+	}
+	return id, nil
+}
+
+// MarshalJSON implements json.Marshaler. The cell ID is written as its
+// number, which is the encoding that encoding/json gives a CellID without
+// this method.
+func (ci CellID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(uint64(ci))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts the number of the cell
+// ID, or its token as a string, exactly as written by ToToken. A JSON null
+// leaves the cell ID unchanged.
+func (ci *CellID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var token string
+		if err := json.Unmarshal(data, &token); err != nil {
+			return fmt.Errorf("s2: invalid CellID: %v", err)
+		}
+		id, err := cellIDFromToken(token)
+		if err != nil {
+			return err
+		}
+		*ci = id
+		return nil
+	}
+	var id uint64
+	if err := json.Unmarshal(data, &id); err != nil {
+		return fmt.Errorf("s2: invalid CellID: %v", err)
+	}
+	*ci = CellID(id)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler. The cell union is written as
+// the tokens of its cells, separated by commas. The cells are written in the
+// order they have in the union, which is not normalized.
+func (cu CellUnion) MarshalText() ([]byte, error) {
+	var b []byte
+	for i, id := range cu {
+		if !id.IsValid() {
+			return nil, fmt.Errorf("s2: cannot marshal invalid CellID %v in CellUnion", id)
+		}
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, id.ToToken()...)
+	}
+	return b, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the tokens of
+// valid cell IDs separated by commas. The cells are kept in the given order.
+func (cu *CellUnion) UnmarshalText(text []byte) error {
+	var ids CellUnion
+	for _, token := range splitList(string(text), ",") {
+		id, err := cellIDFromToken(token)
+		if err != nil {
+			return err
+		}
+		if !id.IsValid() {
+			return fmt.Errorf("s2: invalid CellID token %q in CellUnion", token)
+		}
+		ids = append(ids, id)
+	}
+	*cu = ids
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The cell union is written as an array
+// of CellIDs.
+func (cu CellUnion) MarshalJSON() ([]byte, error) {
+	ids := []CellID(cu)
+	if ids == nil {
+		ids = []CellID{}
+	}
+	return json.Marshal(ids)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts an array of valid
+// CellIDs. A JSON null leaves the cell union unchanged.
+func (cu *CellUnion) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var ids []CellID
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return fmt.Errorf("s2: invalid CellUnion: %v", err)
+	}
+	for _, id := range ids {
+		if !id.IsValid() {
+			return fmt.Errorf("s2: invalid CellID %v in CellUnion", id)
+		}
+	}
+	if ids == nil {
+		ids = []CellID{}
+	}
+	*cu = ids
+	return nil
+}
+
+// capRadius returns the radius of a cap in its encodings, which is -1 in the
+// given unit for the empty cap.
+func capRadius(c Cap, unit s1.Angle) s1.Angle {
+	if c.IsEmpty() {
+		return -1 * unit
+	}
+	return c.Radius()
+}
+
+// capFromEncoding returns the cap with the given decoded center and radius,
+// where a negative radius gives the empty cap.
+func capFromEncoding(center LatLng, radius s1.Angle) (Cap, error) {
+	switch {
+	case radius < 0:
+		return EmptyCap(), nil
+	case radius > 180*s1.Degree:
+		return Cap{}, fmt.Errorf("s2: cap radius %v is greater than 180 degrees", radius)
+	}
+	return CapFromCenterAngle(PointFromLatLng(center), radius), nil
+}
+
+// MarshalText implements encoding.TextMarshaler. The cap is written as
+// "lat:lng,radius" in degrees. The empty cap has a radius of -1 and the full
+// cap has a radius of 180.
+func (c Cap) MarshalText() ([]byte, error) {
+	center, err := pointLatLng(c.Center())
+	if err != nil {
+		return nil, err
+	}
+	b, err := center.appendText(nil)
+	if err != nil {
+		return nil, err
+	}
+	return appendDegrees(append(b, ','), capRadius(c, s1.Degree))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts
+// "lat:lng,radius" in degrees, where any negative radius gives the empty cap
+// and the radius is at most 180.
+func (c *Cap) UnmarshalText(text []byte) error {
+	parts := splitList(string(text), ",")
+	if len(parts) != 2 {
+		return fmt.Errorf("s2: invalid Cap %q, want lat:lng,radius", text)
+	}
+	center, err := parseLatLngText(parts[0])
+	if err != nil {
+		return err
+	}
+	radius, err := s1.ParseDegrees(parts[1])
+	if err != nil {
+		return fmt.Errorf("s2: invalid radius in Cap %q", text)
+	}
+	v, err := capFromEncoding(center, radius)
+	if err != nil {
+		return err
+	}
+	*c = v
+	return nil
+}
+
+// capJSON is the JSON encoding of a Cap.
+type capJSON struct {
+	Center *LatLng   `json:"center"`
+	Radius *s1.Angle `json:"radius"`
+}
+
+// MarshalJSON implements json.Marshaler. The cap is written as an object with
+// a "center" LatLng and a "radius" in radians. The empty cap has a radius of
+// -1 and the full cap has a radius of pi.
+func (c Cap) MarshalJSON() ([]byte, error) {
+	center, err := pointLatLng(c.Center())
+	if err != nil {
+		return nil, err
+	}
+	radius := capRadius(c, s1.Radian)
+	return json.Marshal(capJSON{&center, &radius})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts an object with a
+// "center" LatLng and a "radius" in radians, where any negative radius gives
+// the empty cap and the radius is at most pi. A JSON null leaves the cap
+// unchanged.
+func (c *Cap) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var v capJSON
+	if err := unmarshalJSONStrict(data, &v); err != nil {
+		return fmt.Errorf("s2: invalid Cap: %v", err)
+	}
+	if v.Center == nil || v.Radius == nil {
+		return fmt.Errorf("s2: invalid Cap %s, want center and radius members", data)
+	}
+	if !v.Center.IsValid() {
+		return fmt.Errorf("s2: Cap center %v is out of range", *v.Center)
+	}
+	result, err := capFromEncoding(*v.Center, *v.Radius)
+	if err != nil {
+		return err
+	}
+	*c = result
+	return nil
+}
+
+// rectCorners returns the corners of a rectangle in its text encoding. The
+// empty rectangle, whose latitude interval has no natural endpoints, is
+// written with corners 90:180 and -90:-180 in degrees.
+func rectCorners(r Rect) (lo, hi LatLng) {
+	if r.IsEmpty() {
+		return LatLngFromDegrees(90, 180), LatLngFromDegrees(-90, -180)
+	}
+	return r.Lo(), r.Hi()
+}
+
+// rectFromCorners returns the rectangle with the given decoded corners. A
+// latitude interval with lo > hi is empty, and a longitude interval with
+// lo > hi wraps around the antimeridian, except for lo = 180 and hi = -180,
+// which is empty.
+func rectFromCorners(lo, hi LatLng) (Rect, error) {
+	r := Rect{
+		Lat: r1.Interval{Lo: lo.Lat.Radians(), Hi: hi.Lat.Radians()},
+		Lng: s1.IntervalFromEndpoints(lo.Lng.Radians(), hi.Lng.Radians()),
+	}
+	if r.Lat.IsEmpty() {
+		r.Lat = r1.EmptyInterval()
+	}
+	if !r.IsValid() {
+		return Rect{}, fmt.Errorf("s2: invalid Rect with corners %v and %v", lo, hi)
+	}
+	return r, nil
+}
+
+// MarshalText implements encoding.TextMarshaler. The rectangle is written as
+// its lo and hi corners, "lo_lat:lo_lng,hi_lat:hi_lng", in degrees. A
+// rectangle that crosses the antimeridian has lo_lng > hi_lng.
+func (r Rect) MarshalText() ([]byte, error) {
+	lo, hi := rectCorners(r)
+	b, err := lo.appendText(nil)
+	if err != nil {
+		return nil, err
+	}
+	return hi.appendText(append(b, ','))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the lo and hi
+// corners as written by MarshalText.
+func (r *Rect) UnmarshalText(text []byte) error {
+	parts := splitList(string(text), ",")
+	if len(parts) != 2 {
+		return fmt.Errorf("s2: invalid Rect %q, want lo_lat:lo_lng,hi_lat:hi_lng", text)
+	}
+	lo, err := parseLatLngText(parts[0])
+	if err != nil {
+		return err
+	}
+	hi, err := parseLatLngText(parts[1])
+	if err != nil {
+		return err
+	}
+	v, err := rectFromCorners(lo, hi)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+// intervalJSON is the JSON encoding of the latitude or longitude interval of
+// a Rect. The members are pointers so that missing members can be detected.
+type intervalJSON struct {
+	Lo, Hi *float64
+}
+
+// rectJSON is the JSON encoding of a Rect.
+type rectJSON struct {
+	Lat, Lng *intervalJSON
+}
+
+// MarshalJSON implements json.Marshaler. The rectangle is written as an
+// object with "Lat" and "Lng" intervals, each with "Lo" and "Hi" members in
+// radians, which is the encoding that encoding/json gives a Rect without this
+// method. Rectangles that are not valid are written as they are.
+func (r Rect) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rectJSON{
+		Lat: &intervalJSON{&r.Lat.Lo, &r.Lat.Hi},
+		Lng: &intervalJSON{&r.Lng.Lo, &r.Lng.Hi},
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts an object with "Lat"
+// and "Lng" intervals, as written by MarshalJSON, which are kept as they are
+// even if the rectangle is not valid. A JSON null leaves the rectangle
+// unchanged.
+func (r *Rect) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var v rectJSON
+	if err := unmarshalJSONStrict(data, &v); err != nil {
+		return fmt.Errorf("s2: invalid Rect: %v", err)
+	}
+	if v.Lat == nil || v.Lng == nil || v.Lat.Lo == nil || v.Lat.Hi == nil || v.Lng.Lo == nil || v.Lng.Hi == nil {
+		return fmt.Errorf("s2: invalid Rect %s, want Lat and Lng members with Lo and Hi", data)
+	}
+	*r = Rect{
+		Lat: r1.Interval{Lo: *v.Lat.Lo, Hi: *v.Lat.Hi},
+		Lng: s1.Interval{Lo: *v.Lng.Lo, Hi: *v.Lng.Hi},
+	}
+	return nil
+}
+
+// appendPointsText appends the text encodings of the LatLngs of the points,
+// separated by commas.
+func appendPointsText(b []byte, points []Point) ([]byte, error) {
+	for i, p := range points {
+		ll, err := pointLatLng(p)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		if b, err = ll.appendText(b); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// parsePointsText parses a list of LatLngs separated by commas.
+func parsePointsText(s string) ([]Point, error) {
+	var points []Point
+	for _, part := range splitList(s, ",") {
+		ll, err := parseLatLngText(part)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, PointFromLatLng(ll))
+	}
+	return points, nil
+}
+
+// MarshalText implements encoding.TextMarshaler. The polyline is written as
+// the LatLngs of its vertices separated by commas, such as "0:0, 0:1". The
+// empty polyline is written as an empty string.
+func (p Polyline) MarshalText() ([]byte, error) {
+	return appendPointsText([]byte{}, p)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts LatLngs
+// separated by commas, and returns an error if the polyline is not valid.
+func (p *Polyline) UnmarshalText(text []byte) error {
+	points, err := parsePointsText(string(text))
+	if err != nil {
+		return err
+	}
+	line := Polyline(points)
+	if err := line.Validate(); err != nil {
+		return fmt.Errorf("s2: invalid Polyline: %v", err)
+	}
+	*p = line
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The polyline is written as an array
+// of Points.
+func (p Polyline) MarshalJSON() ([]byte, error) {
+	points := []Point(p)
+	if points == nil {
+		points = []Point{}
+	}
+	return json.Marshal(points)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts an array of Points,
+// and returns an error if the polyline is not valid. A JSON null leaves the
+// polyline unchanged.
+func (p *Polyline) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var points []Point
+	if err := json.Unmarshal(data, &points); err != nil {
+		return fmt.Errorf("s2: invalid Polyline: %v", err)
+	}
+	line := Polyline(points)
+	if err := line.Validate(); err != nil {
+		return fmt.Errorf("s2: invalid Polyline: %v", err)
+	}
+	if line == nil {
+		line = Polyline{}
+	}
+	*p = line
+	return nil
+}
+
+// polygonFromEncoding returns the polygon with the given decoded loops, where
+// an empty list of vertices is the full loop, and checks that it is valid.
+func polygonFromEncoding(loops [][]Point) (*Polygon, error) {
+	var result []*Loop
+	for i, vertices := range loops {
+		if len(vertices) == 0 {
+			result = append(result, FullLoop())
+			continue
+		}
+		if len(vertices) < 3 {
+			return nil, fmt.Errorf("s2: invalid Polygon: loop %d has %d vertices, want at least 3", i, len(vertices))
+		}
+		result = append(result, LoopFromPoints(vertices))
+	}
+	p := PolygonFromLoops(result)
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("s2: invalid Polygon: %v", err)
+	}
+	return p, nil
+}
+
+// MarshalText implements encoding.TextMarshaler. The polygon is written as its
+// loops separated by semicolons, with the LatLngs of the vertices of each loop
+// separated by commas, such as "0:0, 0:1, 1:0; 0.2:0.2, 0.2:0.3, 0.3:0.2". The loops are
+// written as they are stored in the polygon, where every loop has the region
+// it bounds on its left and holes are determined by nesting, as for
+// PolygonFromLoops. The empty and full polygons are written as "empty" and
+// "full".
+func (p *Polygon) MarshalText() ([]byte, error) {
+	switch {
+	case p.IsEmpty():
+		return []byte("empty"), nil
+	case p.IsFull():
+		return []byte("full"), nil
+	}
+	var b []byte
+	for i, l := range p.loops {
+		if i > 0 {
+			b = append(b, "; "...)
+		}
+		var err error
+		if b, err = appendPointsText(b, l.vertices); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the encoding
+// written by MarshalText, and returns an error if the polygon is not valid.
+func (p *Polygon) UnmarshalText(text []byte) error {
+	var loops [][]Point
+	switch strings.TrimSpace(string(text)) {
+	case "empty":
+	case "full":
+		loops = [][]Point{nil}
+	default:
+		parts := strings.Split(string(text), ";")
+		for i, part := range parts {
+			if strings.TrimSpace(part) == "" {
+				return fmt.Errorf("s2: invalid Polygon: loop %d has no vertices", i)
+			}
+			points, err := parsePointsText(part)
+			if err != nil {
+				return err
+			}
+			loops = append(loops, points)
+		}
+	}
+	result, err := polygonFromEncoding(loops)
+	if err != nil {
+		return err
+	}
+	*p = *result
+	// The index of the polygon refers to the polygon itself.
+	p.initEdgesAndIndex()
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The polygon is written as an array of
+// loops, each of which is an array of Points, in the order of MarshalText. The empty
+// polygon has no loops, and the full polygon has a single loop without
+// vertices.
+func (p *Polygon) MarshalJSON() ([]byte, error) {
+	loops := [][]Point{}
+	for _, l := range p.loops {
+		if l.IsFull() {
+			loops = append(loops, []Point{})
+		} else {
+			loops = append(loops, l.vertices)
+		}
+	}
+	return json.Marshal(loops)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts the encoding written
+// by MarshalJSON, and returns an error if the polygon is not valid. A JSON
+// null leaves the polygon unchanged.
+func (p *Polygon) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var loops [][]Point
+	if err := json.Unmarshal(data, &loops); err != nil {
+		return fmt.Errorf("s2: invalid Polygon: %v", err)
+	}
+	result, err := polygonFromEncoding(loops)
+	if err != nil {
+		return err
+	}
+	*p = *result
+	// The index of the polygon refers to the polygon itself.
+	p.initEdgesAndIndex()
+	return nil
+}
@@ -0,0 +1,600 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"math"
+	"testing"
+
+	"github.com/rubenpoppe/geo/r1"
+	"github.com/rubenpoppe/geo/r3"
+	"github.com/rubenpoppe/geo/s1"
+)
+
+// marshalTolerance is the error allowed in round trips through the text
+// encodings, which write degrees with 15 significant digits.
+const marshalTolerance = 1e-14
+
+// checkTextRoundTrip marshals v, checks the result against want, and
+// unmarshals it into back. It returns false if any step failed.
+func checkTextRoundTrip(t *testing.T, v encoding.TextMarshaler, want string, back encoding.TextUnmarshaler) bool {
+	t.Helper()
+	got, err := v.MarshalText()
+	if err != nil {
+		t.Errorf("%v.MarshalText() failed: %v", v, err)
+		return false
+	}
+	if string(got) != want {
+		t.Errorf("%v.MarshalText() = %q, want %q", v, got, want)
+		return false
+	}
+	if err := back.UnmarshalText(got); err != nil {
+		t.Errorf("UnmarshalText(%q) failed: %v", got, err)
+		return false
+	}
+	// The encoding is stable.
+	again, err := back.(encoding.TextMarshaler).MarshalText()
+	if err != nil || string(again) != want {
+		t.Errorf("round trip of %q gives %q, %v", want, again, err)
+		return false
+	}
+	return true
+}
+
+// checkJSONRoundTrip marshals v to JSON, checks the result against want, and
+// unmarshals it into back. It returns false if any step failed.
+func checkJSONRoundTrip(t *testing.T, v interface{}, want string, back interface{}) bool {
+	t.Helper()
+	got, err := json.Marshal(v)
+	if err != nil {
+		t.Errorf("json.Marshal(%v) failed: %v", v, err)
+		return false
+	}
+	if string(got) != want {
+		t.Errorf("json.Marshal(%v) = %s, want %s", v, got, want)
+		return false
+	}
+	if err := json.Unmarshal(got, back); err != nil {
+		t.Errorf("json.Unmarshal(%s) failed: %v", got, err)
+		return false
+	}
+	again, err := json.Marshal(back)
+	if err != nil || string(again) != want {
+		t.Errorf("round trip of %s gives %s, %v", want, again, err)
+		return false
+	}
+	return true
+}
+
+func TestLatLngMarshal(t *testing.T) {
+	tests := []struct {
+		have     LatLng
+		wantText string
+		wantJSON string
+	}{
+		{LatLngFromDegrees(0, 0), "0:0", `{"Lat":0,"Lng":0}`},
+		{LatLngFromDegrees(37.5, -122.25), "37.5:-122.25", `{"Lat":0.6544984694978736,"Lng":-2.133665010563068}`},
+		{LatLngFromDegrees(-90, 180), "-90:180", `{"Lat":-1.5707963267948966,"Lng":3.141592653589793}`},
+		{LatLng{0.5, -1.25}, "28.6478897565412:-71.6197243913529", `{"Lat":0.5,"Lng":-1.25}`},
+		{LatLngFromDegrees(1e-9, 60), "1e-09:60", `{"Lat":1.7453292519943298e-11,"Lng":1.0471975511965976}`},
+	}
+	for _, test := range tests {
+		text, err := test.have.appendText(nil)
+		if err != nil || string(text) != test.wantText {
+			t.Errorf("%v.appendText() = %q, %v, want %q", test.have, text, err, test.wantText)
+		} else if back, err := parseLatLngText(test.wantText); err != nil || !back.ApproxEqual(test.have) {
+			t.Errorf("parseLatLngText(%q) = %v, %v, want %v", test.wantText, back, err, test.have)
+		}
+		// The JSON encoding is exact.
+		var back LatLng
+		if checkJSONRoundTrip(t, test.have, test.wantJSON, &back) && back != test.have {
+			t.Errorf("json.Unmarshal(%s) = %v, want %v", test.wantJSON, back, test.have)
+		}
+	}
+
+	if _, err := LatLngFromDegrees(91, 0).appendText(nil); err == nil {
+		t.Errorf("appendText of an invalid LatLng succeeded")
+	}
+	// LatLngs that are not normalized keep their JSON encoding.
+	var back LatLng
+	if checkJSONRoundTrip(t, LatLng{Lng: 4}, `{"Lat":0,"Lng":4}`, &back) && back != (LatLng{Lng: 4}) {
+		t.Errorf("json.Unmarshal of a LatLng that is not normalized = %v, want %v", back, LatLng{Lng: 4})
+	}
+	if _, err := json.Marshal(LatLng{Lat: s1.InfAngle()}); err == nil {
+		t.Errorf("json.Marshal of an infinite LatLng succeeded")
+	}
+}
+
+func TestLatLngEncodingCompatibility(t *testing.T) {
+	// LatLngs are written in radians by encoding/xml, as they were before
+	// LatLng had other encodings.
+	type record struct{ LL LatLng }
+	data, err := xml.Marshal(record{LatLng{0.5, -1.25}})
+	if want := "<record><LL><Lat>0.5</Lat><Lng>-1.25</Lng></LL></record>"; err != nil || string(data) != want {
+		t.Errorf("xml.Marshal(LatLng) = %s, %v, want it to contain %s", data, err, want)
+	}
+}
+
+func TestLatLngUnmarshalErrors(t *testing.T) {
+	for _, have := range []string{"", "10", "10:20:30", "10,20", " 10:20", "10: 20", "91:0", "0:180.5", "NaN:0", "0:Inf", "a:b"} {
+		if ll, err := parseLatLngText(have); err == nil {
+			t.Errorf("parseLatLngText(%q) = %v, want an error", have, ll)
+		}
+	}
+	for _, have := range []string{
+		`{"Lat":10}`,
+		`{"Lng":10}`,
+		`{"Lat":1,"Lng":2,"alt":0}`,
+		`{"Lat":"1","Lng":2}`,
+		`[10,20]`,
+		`"10:20"`,
+	} {
+		var ll LatLng
+		if err := json.Unmarshal([]byte(have), &ll); err == nil {
+			t.Errorf("json.Unmarshal(%s) = %v, want an error", have, ll)
+		}
+	}
+}
+
+func TestPointMarshal(t *testing.T) {
+	// Both encodings keep the exact coordinates of the point.
+	for i := 0; i < 100; i++ {
+		p := randomPoint()
+		text, err := p.MarshalText()
+		if err != nil {
+			t.Fatalf("%v.MarshalText() failed: %v", p, err)
+		}
+		var back Point
+		if err := back.UnmarshalText(text); err != nil {
+			t.Fatalf("UnmarshalText(%q) failed: %v", text, err)
+		}
+		if back != p {
+			t.Errorf("text round trip of %v = %v", p, back)
+		}
+		data, err := json.Marshal(p)
+		if err != nil {
+			t.Fatalf("json.Marshal(%v) failed: %v", p, err)
+		}
+		back = Point{}
+		if err := json.Unmarshal(data, &back); err != nil {
+			t.Fatalf("json.Unmarshal(%s) failed: %v", data, err)
+		}
+		if back != p {
+			t.Errorf("JSON round trip of %v = %v", p, back)
+		}
+	}
+
+	// The JSON encoding is the one encoding/json gives the vector of a point.
+	p := PointFromLatLng(LatLngFromDegrees(10, 20))
+	want, err := json.Marshal(p.Vector)
+	if err != nil {
+		t.Fatalf("json.Marshal(%v) failed: %v", p.Vector, err)
+	}
+	var back Point
+	checkTextRoundTrip(t, p, "0.9254165783983235,0.33682408883346515,0.17364817766693033", &back)
+	checkJSONRoundTrip(t, p, string(want), &back)
+	checkJSONRoundTrip(t, Point{}, `{"X":0,"Y":0,"Z":0}`, &back)
+	if err := back.UnmarshalText([]byte("1 , 0, -0")); err != nil || back != (Point{r3.Vector{X: 1}}) {
+		t.Errorf("UnmarshalText with spaces = %v, %v, want (1, 0, 0)", back, err)
+	}
+
+	if _, err := (Point{r3.Vector{X: math.NaN()}}).MarshalText(); err == nil {
+		t.Errorf("MarshalText of a NaN point succeeded")
+	}
+	if _, err := json.Marshal(Point{r3.Vector{X: math.Inf(1)}}); err == nil {
+		t.Errorf("json.Marshal of an infinite point succeeded")
+	}
+	for _, have := range []string{"", "1,0", "1,0,0,0", "10:20", "1,0,Inf", "1,0,0x1p-2", "1,,0"} {
+		back := Point{r3.Vector{X: 7}}
+		if err := back.UnmarshalText([]byte(have)); err == nil {
+			t.Errorf("UnmarshalText(%q) = %v, want an error", have, back)
+		}
+		if back != (Point{r3.Vector{X: 7}}) {
+			t.Errorf("failed UnmarshalText(%q) changed the point to %v", have, back)
+		}
+	}
+	for _, have := range []string{
+		`{"X":1,"Y":0}`,
+		`{"X":1,"Y":0,"Z":0,"W":0}`,
+		`{"X":"1","Y":0,"Z":0}`,
+		`{"Lat":0,"Lng":0}`,
+		`[1,0,0]`,
+	} {
+		if err := json.Unmarshal([]byte(have), &back); err == nil {
+			t.Errorf("json.Unmarshal(%s) = %v, want an error", have, back)
+		}
+	}
+}
+
+func TestCellIDMarshal(t *testing.T) {
+	tests := []struct {
+		have     CellID
+		wantJSON string
+	}{
+		{CellIDFromToken("89c25"), "9926584489608216576"},
+		{CellIDFromFace(5), "12682136550675316736"},
+		{0, "0"},
+	}
+	for _, test := range tests {
+		var back CellID
+		if checkJSONRoundTrip(t, test.have, test.wantJSON, &back) && back != test.have {
+			t.Errorf("json.Unmarshal(%s) = %v, want %v", test.wantJSON, back, test.have)
+		}
+		// Tokens are accepted as strings.
+		back = 1
+		if err := json.Unmarshal([]byte(`"`+test.have.ToToken()+`"`), &back); err != nil || back != test.have {
+			t.Errorf("json.Unmarshal of token %q = %v, %v, want %v", test.have.ToToken(), back, err, test.have)
+		}
+	}
+
+	// Only tokens exactly as written by ToToken are accepted.
+	for _, have := range []string{"", "x", "89C25", "89c250", "89c2", "zz", "ffffffffffffffff", " 89c25"} {
+		var id CellID
+		if err := json.Unmarshal([]byte(`"`+have+`"`), &id); err == nil {
+			t.Errorf("json.Unmarshal of token %q = %v, want an error", have, id)
+		}
+	}
+	for _, have := range []string{`-1`, `1.5`, `18446744073709551616`, `[1]`, `{}`} {
+		var id CellID
+		if err := json.Unmarshal([]byte(have), &id); err == nil {
+			t.Errorf("json.Unmarshal(%s) = %v, want an error", have, id)
+		}
+	}
+}
+
+func TestCellIDJSONCompatibility(t *testing.T) {
+	// CellIDs are encoded as numbers, also as keys of JSON objects, as they
+	// were before CellID implemented json.Marshaler.
+	type record struct {
+		ID    CellID
+		Names map[CellID]string
+	}
+	const data = `{"ID":3458764513820540928,"Names":{"3458764513820540928":"face 1"}}`
+	var got record
+	if err := json.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s) failed: %v", data, err)
+	}
+	want := CellIDFromFace(1)
+	if got.ID != want || got.Names[want] != "face 1" || len(got.Names) != 1 {
+		t.Errorf("json.Unmarshal(%s) = %+v, want ID and key %v", data, got, want)
+	}
+	if again, err := json.Marshal(got); err != nil || string(again) != data {
+		t.Errorf("json.Marshal(%+v) = %s, %v, want %s", got, again, err, data)
+	}
+}
+
+func TestCellUnionMarshal(t *testing.T) {
+	tests := []struct {
+		have     CellUnion
+		wantText string
+		wantJSON string
+	}{
+		{nil, "", "[]"},
+		{CellUnion{CellIDFromToken("89c25")}, "89c25", `[9926584489608216576]`},
+		// The order is kept, even if the union is not normalized.
+		{CellUnion{CellIDFromToken("89c27"), CellIDFromToken("89c25"), CellIDFromFace(0)}, "89c27,89c25,1",
+			`[9926619673980305408,9926584489608216576,1152921504606846976]`},
+	}
+	for _, test := range tests {
+		var back CellUnion
+		if checkTextRoundTrip(t, test.have, test.wantText, &back) && !back.Equal(test.have) {
+			t.Errorf("UnmarshalText(%q) = %v, want %v", test.wantText, back, test.have)
+		}
+		back = nil
+		if checkJSONRoundTrip(t, test.have, test.wantJSON, &back) && !back.Equal(test.have) {
+			t.Errorf("json.Unmarshal(%s) = %v, want %v", test.wantJSON, back, test.have)
+		}
+	}
+
+	var cu CellUnion
+	if err := cu.UnmarshalText([]byte("89c25 , 89c27")); err != nil || len(cu) != 2 {
+		t.Errorf("UnmarshalText with spaces = %v, %v, want 2 cells", cu, err)
+	}
+	for _, have := range []string{"89c25,", "89c25,,89c27", "X", "89c25,zz"} {
+		if err := cu.UnmarshalText([]byte(have)); err == nil {
+			t.Errorf("UnmarshalText(%q) = %v, want an error", have, cu)
+		}
+	}
+	if err := json.Unmarshal([]byte(`["89c25",9926619673980305408]`), &cu); err != nil ||
+		!cu.Equal(CellUnion{CellIDFromToken("89c25"), CellIDFromToken("89c27")}) {
+		t.Errorf("json.Unmarshal of tokens and numbers = %v, %v", cu, err)
+	}
+	for _, have := range []string{`"89c25"`, `["X"]`, `[2]`, `[0]`, `["89c25",null]`} {
+		if err := json.Unmarshal([]byte(have), &cu); err == nil {
+			t.Errorf("json.Unmarshal(%s) = %v, want an error", have, cu)
+		}
+	}
+}
+
+func TestCapMarshal(t *testing.T) {
+	center := PointFromLatLng(LatLngFromDegrees(37.5, -122.25))
+	tests := []struct {
+		have     Cap
+		wantText string
+		wantJSON string
+	}{
+		{CapFromCenterAngle(center, 0.5*s1.Degree), "37.5:-122.25,0.5",
+			`{"center":{"Lat":0.6544984694978735,"Lng":-2.1336650105630675},"radius":0.008726646259971648}`},
+		{CapFromPoint(center), "37.5:-122.25,0", `{"center":{"Lat":0.6544984694978735,"Lng":-2.1336650105630675},"radius":0}`},
+		{EmptyCap(), "0:0,-1", `{"center":{"Lat":0,"Lng":0},"radius":-1}`},
+		{FullCap(), "0:0,180", `{"center":{"Lat":0,"Lng":0},"radius":3.141592653589793}`},
+	}
+	for _, test := range tests {
+		var back Cap
+		if checkTextRoundTrip(t, test.have, test.wantText, &back) && !back.ApproxEqual(test.have) {
+			t.Errorf("UnmarshalText(%q) = %v, want %v", test.wantText, back, test.have)
+		}
+		back = Cap{}
+		if checkJSONRoundTrip(t, test.have, test.wantJSON, &back) && !back.ApproxEqual(test.have) {
+			t.Errorf("json.Unmarshal(%s) = %v, want %v", test.wantJSON, back, test.have)
+		}
+	}
+
+	var c Cap
+	if err := c.UnmarshalText([]byte("10:10,-5")); err != nil || !c.IsEmpty() {
+		t.Errorf("UnmarshalText with a negative radius = %v, %v, want the empty cap", c, err)
+	}
+	if err := c.UnmarshalText([]byte("10:10, 180")); err != nil || !c.IsFull() {
+		t.Errorf("UnmarshalText with a radius of 180 = %v, %v, want the full cap", c, err)
+	}
+	for _, have := range []string{"", "10:10", "10:10,1,2", "10:10,180.5", "10:10,x", "100:10,1"} {
+		if err := c.UnmarshalText([]byte(have)); err == nil {
+			t.Errorf("UnmarshalText(%q) = %v, want an error", have, c)
+		}
+	}
+	for _, have := range []string{
+		`{"center":{"Lat":0,"Lng":0}}`,
+		`{"radius":1}`,
+		`{"center":{"Lat":0,"Lng":0},"radius":1,"height":2}`,
+		`{"center":{"Lat":0,"Lng":0},"radius":3.2}`,
+		`{"center":"0:0","radius":1}`,
+	} {
+		if err := json.Unmarshal([]byte(have), &c); err == nil {
+			t.Errorf("json.Unmarshal(%s) = %v, want an error", have, c)
+		}
+	}
+}
+
+func TestRectMarshal(t *testing.T) {
+	tests := []struct {
+		have     Rect
+		wantText string
+		wantJSON string
+	}{
+		{RectFromLatLng(LatLng{0.25, 0.5}).AddPoint(LatLng{0.75, 1}),
+			"14.3239448782706:28.6478897565412,42.9718346348117:57.2957795130823",
+			`{"Lat":{"Lo":0.25,"Hi":0.75},"Lng":{"Lo":0.5,"Hi":1}}`},
+		// Across the antimeridian.
+		{RectFromLatLng(LatLng{-0.25, 3}).AddPoint(LatLng{0.25, -3}),
+			"-14.3239448782706:171.887338539247,14.3239448782706:-171.887338539247",
+			`{"Lat":{"Lo":-0.25,"Hi":0.25},"Lng":{"Lo":3,"Hi":-3}}`},
+		{EmptyRect(), "90:180,-90:-180",
+			`{"Lat":{"Lo":1,"Hi":0},"Lng":{"Lo":3.141592653589793,"Hi":-3.141592653589793}}`},
+		{FullRect(), "-90:-180,90:180",
+			`{"Lat":{"Lo":-1.5707963267948966,"Hi":1.5707963267948966},"Lng":{"Lo":-3.141592653589793,"Hi":3.141592653589793}}`},
+	}
+	for _, test := range tests {
+		var back Rect
+		if checkTextRoundTrip(t, test.have, test.wantText, &back) && !back.ApproxEqual(test.have) {
+			t.Errorf("UnmarshalText(%q) = %v, want %v", test.wantText, back, test.have)
+		}
+		back = Rect{}
+		// The JSON encoding is exact.
+		if checkJSONRoundTrip(t, test.have, test.wantJSON, &back) && back != test.have {
+			t.Errorf("json.Unmarshal(%s) = %v, want %v", test.wantJSON, back, test.have)
+		}
+	}
+
+	// The JSON encoding is the one encoding/json gives the Rect struct.
+	type rectFields struct {
+		Lat r1.Interval
+		Lng s1.Interval
+	}
+	for _, r := range []Rect{RectFromLatLng(LatLng{0.25, 0.5}).AddPoint(LatLng{0.75, 1}), EmptyRect(), FullRect()} {
+		got, err := json.Marshal(r)
+		want, _ := json.Marshal(rectFields{r.Lat, r.Lng})
+		if err != nil || string(got) != string(want) {
+			t.Errorf("json.Marshal(%v) = %s, %v, want %s", r, got, err, want)
+		}
+	}
+	// Rectangles that are not valid keep their JSON encoding.
+	invalid := Rect{r1.Interval{Lo: 0, Hi: 2}, s1.FullInterval()}
+	var back Rect
+	if checkJSONRoundTrip(t, invalid, `{"Lat":{"Lo":0,"Hi":2},"Lng":{"Lo":-3.141592653589793,"Hi":3.141592653589793}}`, &back) && back != invalid {
+		t.Errorf("json.Unmarshal of an invalid Rect = %v, want %v", back, invalid)
+	}
+
+	var r Rect
+	if err := r.UnmarshalText([]byte("90:180,-90:-180")); err != nil || !r.IsEmpty() || r.Lat != r1.EmptyInterval() {
+		t.Errorf("UnmarshalText of the empty rectangle = %v, %v", r, err)
+	}
+	for _, have := range []string{
+		"",
+		"10:20",
+		"10:20,30:40,50:60",
+		// An empty latitude interval with a non-empty longitude interval.
+		"20:20,10:30",
+		"10:20,30:400",
+	} {
+		if err := r.UnmarshalText([]byte(have)); err == nil {
+			t.Errorf("UnmarshalText(%q) = %v, want an error", have, r)
+		}
+	}
+	for _, have := range []string{
+		`{"Lat":{"Lo":0.25,"Hi":0.75}}`,
+		`{"Lat":{"Lo":0.25},"Lng":{"Lo":0.5,"Hi":1}}`,
+		`{"Lat":{"Lo":0.25,"Hi":0.75},"Lng":{"Lo":0.5,"Hi":1},"Name":"x"}`,
+		`{"Lat":{"Lo":0.25,"Hi":0.75,"Mid":0.5},"Lng":{"Lo":0.5,"Hi":1}}`,
+		`{"lo":{"Lat":0.25,"Lng":0.5},"hi":{"Lat":0.75,"Lng":1}}`,
+	} {
+		if err := json.Unmarshal([]byte(have), &r); err == nil {
+			t.Errorf("json.Unmarshal(%s) = %v, want an error", have, r)
+		}
+	}
+}
+
+func TestPolylineMarshal(t *testing.T) {
+	x, y, z := Point{r3.Vector{X: 1}}, Point{r3.Vector{Y: 1}}, Point{r3.Vector{Z: 1}}
+	tests := []struct {
+		have     Polyline
+		wantText string
+		wantJSON string
+	}{
+		{nil, "", "[]"},
+		{Polyline{x}, "0:0", `[{"X":1,"Y":0,"Z":0}]`},
+		{Polyline{x, y, z}, "0:0, 0:90, 90:0", `[{"X":1,"Y":0,"Z":0},{"X":0,"Y":1,"Z":0},{"X":0,"Y":0,"Z":1}]`},
+	}
+	for _, test := range tests {
+		var back Polyline
+		if checkTextRoundTrip(t, test.have, test.wantText, &back) && !back.approxEqual(&test.have, marshalTolerance) {
+			t.Errorf("UnmarshalText(%q) = %v, want %v", test.wantText, back, test.have)
+		}
+		back = nil
+		if checkJSONRoundTrip(t, test.have, test.wantJSON, &back) && !back.approxEqual(&test.have, 0) {
+			t.Errorf("json.Unmarshal(%s) = %v, want %v", test.wantJSON, back, test.have)
+		}
+	}
+
+	var p Polyline
+	for _, have := range []string{"0:0, 0:0", "0:0,, 0:1", "0:0, 0:1,", "0:0; 0:1"} {
+		if err := p.UnmarshalText([]byte(have)); err == nil {
+			t.Errorf("UnmarshalText(%q) = %v, want an error", have, p)
+		}
+	}
+	for _, have := range []string{`[{"X":1,"Y":0,"Z":0},{"X":1,"Y":0,"Z":0}]`, `{"X":1,"Y":0,"Z":0}`, `[{"Lat":0,"Lng":0}]`} {
+		if err := json.Unmarshal([]byte(have), &p); err == nil {
+			t.Errorf("json.Unmarshal(%s) = %v, want an error", have, p)
+		}
+	}
+}
+
+func TestPolygonMarshal(t *testing.T) {
+	shell := "0:0, 0:10, 10:10, 10:0"
+	hole := "2:2, 2:3, 3:3, 3:2"
+	withHole := makePolygon(shell+"; "+hole, true)
+	triangle := PolygonFromLoops([]*Loop{LoopFromPoints([]Point{{r3.Vector{X: 1}}, {r3.Vector{Y: 1}}, {r3.Vector{Z: 1}}})})
+	tests := []struct {
+		have     *Polygon
+		wantText string
+		wantJSON string
+	}{
+		{&Polygon{}, "empty", "[]"},
+		{FullPolygon(), "full", "[[]]"},
+		{triangle, "0:0, 0:90, 90:0", `[[{"X":1,"Y":0,"Z":0},{"X":0,"Y":1,"Z":0},{"X":0,"Y":0,"Z":1}]]`},
+		{withHole, "0:0, 0:10, 10:10, 10:0; 2:2, 2:3, 3:3, 3:2", ""},
+	}
+	for _, test := range tests {
+		back := new(Polygon)
+		if checkTextRoundTrip(t, test.have, test.wantText, back) && !polygonVerticesApproxEqual(back, test.have) {
+			t.Errorf("UnmarshalText(%q) = %v, want %v", test.wantText, back, test.have)
+		}
+		if test.wantJSON == "" {
+			continue
+		}
+		back = new(Polygon)
+		if checkJSONRoundTrip(t, test.have, test.wantJSON, back) && !polygonVerticesApproxEqual(back, test.have) {
+			t.Errorf("json.Unmarshal(%s) = %v, want %v", test.wantJSON, back, test.have)
+		}
+	}
+
+	// The decoded polygon is usable, and its hole is a hole.
+	var p Polygon
+	data, _ := json.Marshal(withHole)
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatalf("json.Unmarshal(%s) failed: %v", data, err)
+	}
+	if !p.Loop(1).IsHole() || p.ContainsPoint(PointFromLatLng(LatLngFromDegrees(2.5, 2.5))) ||
+		!p.ContainsPoint(PointFromLatLng(LatLngFromDegrees(5, 5))) {
+		t.Errorf("decoded polygon %v does not have the hole of %v", &p, withHole)
+	}
+	if p.index.Shape(0) != &p {
+		t.Errorf("decoded polygon is not indexed as itself")
+	}
+
+	for _, have := range []string{
+		"",
+		"0:0, 0:1",
+		"0:0, 0:1, 1:0;",
+		"0:0, 0:1, 1:0; full",
+		"0:0, 0:0, 1:0",
+		"0:0, 0:1, x",
+	} {
+		if err := p.UnmarshalText([]byte(have)); err == nil {
+			t.Errorf("UnmarshalText(%q) = %v, want an error", have, &p)
+		}
+	}
+	for _, have := range []string{
+		`[[{"X":1,"Y":0,"Z":0},{"X":0,"Y":1,"Z":0}]]`,
+		`[[], [{"X":1,"Y":0,"Z":0},{"X":0,"Y":1,"Z":0},{"X":0,"Y":0,"Z":1}]]`,
+		`[{"X":1,"Y":0,"Z":0}]`,
+		`[[{"Lat":0,"Lng":0},{"Lat":0,"Lng":1},{"Lat":1,"Lng":0}]]`,
+		`"empty"`,
+	} {
+		if err := json.Unmarshal([]byte(have), &p); err == nil {
+			t.Errorf("json.Unmarshal(%s) = %v, want an error", have, &p)
+		}
+	}
+}
+
+// polygonVerticesApproxEqual reports whether the polygons have the same loops
+// with the same vertices in the same order, up to marshalTolerance.
+func polygonVerticesApproxEqual(a, b *Polygon) bool {
+	if a.NumLoops() != b.NumLoops() {
+		return false
+	}
+	for i := 0; i < a.NumLoops(); i++ {
+		la, lb := a.Loop(i), b.Loop(i)
+		if la.NumVertices() != lb.NumVertices() {
+			return false
+		}
+		for j := 0; j < la.NumVertices(); j++ {
+			if la.Vertex(j).Distance(lb.Vertex(j)) > marshalTolerance {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestMarshalJSONStruct(t *testing.T) {
+	// The types can be used directly as members of other types.
+	type record struct {
+		Where   LatLng     `json:"where"`
+		Heading s1.Angle   `json:"heading"`
+		Cell    CellID     `json:"cell"`
+		Cover   CellUnion  `json:"cover"`
+		Near    Cap        `json:"near"`
+		Bound   Rect       `json:"bound"`
+		Route   Polyline   `json:"route"`
+		Area    *Polygon   `json:"area"`
+		Stops   []Point    `json:"stops"`
+		Extra   *CellUnion `json:"extra,omitempty"`
+	}
+	want := `{"where":{"Lat":1,"Lng":2},"heading":1.5,"cell":9926584489608216576,"cover":[9926584489608216576],` +
+		`"near":{"center":{"Lat":1,"Lng":2},"radius":0.5},"bound":{"Lat":{"Lo":0.5,"Hi":1},"Lng":{"Lo":1,"Hi":2}},` +
+		`"route":[{"X":1,"Y":0,"Z":0},{"X":0,"Y":1,"Z":0}],"area":[[{"X":1,"Y":0,"Z":0},{"X":0,"Y":1,"Z":0},{"X":0,"Y":0,"Z":1}]],` +
+		`"stops":[{"X":0,"Y":0,"Z":1}]}`
+	var got record
+	if err := json.Unmarshal([]byte(want), &got); err != nil {
+		t.Fatalf("json.Unmarshal failed: %v", err)
+	}
+	data, err := json.Marshal(got)
+	if err != nil || string(data) != want {
+		t.Errorf("json.Marshal(json.Unmarshal(%s)) = %s, %v", want, data, err)
+	}
+}
@@ -521,7 +521,7 @@ func TestDistanceTargetMaxShapeIndexTargetVisitContainingShapes(t *testing.T) {
 		reflectPoints(parsePoints("20:20, 20:21, 21:20")),
 		reflectPoints(parsePoints("10:10, 10:11, 11:10")),
 	}
-	laxPoly := laxPolygonFromPoints(loops)
+	laxPoly := LaxPolygonFromPoints(loops)
 	targetIndex.Add(laxPoly)
 
 	target := NewMaxDistanceToShapeIndexTarget(targetIndex)
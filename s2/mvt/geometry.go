@@ -0,0 +1,424 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvt
+
+import (
+	"math"
+
+	"github.com/rubenpoppe/geo/r2"
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// The geometry types of features.
+const (
+	geomPoint      = 1
+	geomLineString = 2
+	geomPolygon    = 3
+)
+
+// tilePoint is a point in integer tile coordinates.
+type tilePoint struct {
+	X, Y int64
+}
+
+// geometry is the geometry of a feature in tile coordinates. For points,
+// parts has a single part with all the points. For line strings, each part is
+// a line string. For polygons, each part is a ring without the closing
+// vertex, and each exterior ring is followed by its holes.
+type geometry struct {
+	typ   int
+	parts [][]tilePoint
+}
+
+func (g geometry) empty() bool { return len(g.parts) == 0 }
+
+// points returns the points of a shape of dimension 0 that are within the
+// clipping rectangle.
+func (t *tile) points(shape s2.Shape) geometry {
+	var points []tilePoint
+	for i := 0; i < shape.NumEdges(); i++ {
+		p := t.toTile(t.tess.ProjectChain([]s2.Point{shape.Edge(i).V0}, false)[0])
+		for _, k := range t.shifts(p.X, p.X) {
+			if q := (r2.Point{X: p.X + k, Y: p.Y}); t.clip.ContainsPoint(q) {
+				points = append(points, round(q))
+			}
+		}
+	}
+	if len(points) == 0 {
+		return geometry{}
+	}
+	return geometry{typ: geomPoint, parts: [][]tilePoint{points}}
+}
+
+// lines returns the chains of a shape of dimension 1, clipped to the
+// clipping rectangle.
+func (t *tile) lines(shape s2.Shape) geometry {
+	g := geometry{typ: geomLineString}
+	for c := 0; c < shape.NumChains(); c++ {
+		line := t.project(chainVertices(shape, c, false), false)
+		if len(line) < 2 {
+			continue
+		}
+		minX, maxX := xRange(line)
+		for _, k := range t.shifts(minX, maxX) {
+			for _, part := range clipLine(shifted(line, k), t.clip) {
+				if q := quantize(part, false); len(q) >= 2 {
+					g.parts = append(g.parts, q)
+				}
+			}
+		}
+	}
+	return g
+}
+
+// polygons returns the loops of a shape of dimension 2, clipped to the
+// clipping rectangle. The contains function reports whether the shape
+// contains a point, which determines whether the tile is inside a shape whose
+// boundary does not cross it.
+func (t *tile) polygons(shape s2.Shape, contains func(s2.Point) bool) geometry {
+	var rings [][]r2.Point
+	for c := 0; c < shape.NumChains(); c++ {
+		ring := t.projectLoop(chainVertices(shape, c, true))
+		if len(ring) < 3 {
+			continue
+		}
+		minX, maxX := xRange(ring)
+		for _, k := range t.shifts(minX, maxX) {
+			// The loops of a shape are counterclockwise in world coordinates,
+			// but MVT wants exterior rings to be clockwise with y pointing
+			// down, which is the same orientation in tile coordinates.
+			if clipped := clipRing(reversedRing(shifted(ring, k)), t.clip); len(clipped) >= 3 {
+				rings = append(rings, clipped)
+			}
+		}
+	}
+
+	// Clipping preserves the winding number of the rings around every point
+	// of the tile, but that only determines the shape up to a constant: the
+	// loops of a shape that contains the whole tile may not cross it, and the
+	// loop of a complement winds negatively around its outside. The tile
+	// center fixes the constant, and the tile is added as an exterior ring if
+	// it is inside the shape where the rings do not wind around it.
+	center := r2.Point{X: float64(t.extent) / 2, Y: float64(t.extent) / 2}
+	wind := 0
+	for _, ring := range rings {
+		wind += winding(ring, center)
+	}
+	inside := 0
+	if contains(s2.PointFromLatLng(t.toSphere(center))) {
+		inside = 1
+	}
+	if inside-wind == 1 {
+		rings = append(rings, []r2.Point{
+			{X: t.clip.X.Lo, Y: t.clip.Y.Lo},
+			{X: t.clip.X.Hi, Y: t.clip.Y.Lo},
+			{X: t.clip.X.Hi, Y: t.clip.Y.Hi},
+			{X: t.clip.X.Lo, Y: t.clip.Y.Hi},
+		})
+	}
+	return geometry{typ: geomPolygon, parts: assembleRings(rings)}
+}
+
+// shifts returns the multiples of the world width by which coordinates in
+// the range [minX, maxX] are shifted to overlap the clipping rectangle.
+func (t *tile) shifts(minX, maxX float64) []float64 {
+	width := t.scale
+	var shifts []float64
+	for k := math.Ceil((t.clip.X.Lo - maxX) / width); k <= math.Floor((t.clip.X.Hi-minX)/width); k++ {
+		shifts = append(shifts, k*width)
+	}
+	return shifts
+}
+
+// project tessellates a chain of vertices and returns it in tile coordinates.
+// Longitudes are unwrapped along the chain, so x coordinates may extend
+// beyond the world.
+func (t *tile) project(vertices []s2.Point, closed bool) []r2.Point {
+	world := t.tess.ProjectChain(vertices, closed)
+	points := make([]r2.Point, len(world))
+	for i, p := range world {
+		points[i] = t.toTile(p)
+	}
+	return points
+}
+
+// projectLoop returns a loop as a ring in tile coordinates, without the
+// closing vertex. The projection of a loop around a pole does not close but
+// ends one world width away from where it started. Such a loop is closed
+// along a horizontal line beyond the pole side of the clipping rectangle.
+func (t *tile) projectLoop(vertices []s2.Point) []r2.Point {
+	ring := t.project(vertices, true)
+	if len(ring) < 2 {
+		return nil
+	}
+	first, last := ring[0], ring[len(ring)-1]
+	if math.Abs(last.X-first.X) < t.scale/2 {
+		return ring[:len(ring)-1]
+	}
+	// The interior is on the left of the loop, so a loop that goes east
+	// around a pole contains the north pole, which is at the top.
+	minY, maxY := ring[0].Y, ring[0].Y
+	for _, p := range ring {
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+	y := math.Max(maxY, t.clip.Y.Hi) + 1
+	if last.X > first.X {
+		y = math.Min(minY, t.clip.Y.Lo) - 1
+	}
+	return append(ring, r2.Point{X: last.X, Y: y}, r2.Point{X: first.X, Y: y})
+}
+
+// chainVertices returns the vertices of a chain of the shape. If closed is
+// true the chain is a loop, and the last vertex is not repeated.
+func chainVertices(shape s2.Shape, c int, closed bool) []s2.Point {
+	chain := shape.Chain(c)
+	vertices := make([]s2.Point, 0, chain.Length+1)
+	for j := 0; j < chain.Length; j++ {
+		e := shape.ChainEdge(c, j)
+		vertices = append(vertices, e.V0)
+		if j == chain.Length-1 && !closed {
+			vertices = append(vertices, e.V1)
+		}
+	}
+	return vertices
+}
+
+// xRange returns the range of x coordinates of the points.
+func xRange(points []r2.Point) (minX, maxX float64) {
+	minX, maxX = points[0].X, points[0].X
+	for _, p := range points {
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+	}
+	return minX, maxX
+}
+
+// shifted returns the points shifted horizontally by dx.
+func shifted(points []r2.Point, dx float64) []r2.Point {
+	out := make([]r2.Point, len(points))
+	for i, p := range points {
+		out[i] = r2.Point{X: p.X + dx, Y: p.Y}
+	}
+	return out
+}
+
+// reversedRing returns the points in reverse order.
+func reversedRing(points []r2.Point) []r2.Point {
+	out := make([]r2.Point, len(points))
+	for i, p := range points {
+		out[len(points)-1-i] = p
+	}
+	return out
+}
+
+// clipLine returns the parts of a line string inside the clipping rectangle.
+func clipLine(line []r2.Point, clip r2.Rect) [][]r2.Point {
+	var parts [][]r2.Point
+	var part []r2.Point
+	for i := 0; i+1 < len(line); i++ {
+		a, b, ok := s2.ClipEdge(line[i], line[i+1], clip)
+		if !ok {
+			if part != nil {
+				parts = append(parts, part)
+				part = nil
+			}
+			continue
+		}
+		if part == nil {
+			part = []r2.Point{a}
+		}
+		part = append(part, b)
+		if !clip.ContainsPoint(line[i+1]) {
+			parts = append(parts, part)
+			part = nil
+		}
+	}
+	if part != nil {
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// clipRing clips a ring to the clipping rectangle with the Sutherland-Hodgman
+// algorithm. Where the ring leaves and reenters the rectangle, the result
+// follows the boundary of the rectangle, which preserves the winding number
+// of every point inside the rectangle.
+func clipRing(ring []r2.Point, clip r2.Rect) []r2.Point {
+	// Each boundary is given by an axis, its coordinate, and the sign of the
+	// side to keep.
+	boundaries := []struct {
+		y     bool
+		bound float64
+		sign  float64
+	}{
+		{false, clip.X.Lo, 1},
+		{false, clip.X.Hi, -1},
+		{true, clip.Y.Lo, 1},
+		{true, clip.Y.Hi, -1},
+	}
+	for _, b := range boundaries {
+		coord := func(p r2.Point) float64 {
+			if b.y {
+				return p.Y
+			}
+			return p.X
+		}
+		inside := func(p r2.Point) bool { return (coord(p)-b.bound)*b.sign >= 0 }
+		intersect := func(p, q r2.Point) r2.Point {
+			f := (b.bound - coord(p)) / (coord(q) - coord(p))
+			r := p.Add(q.Sub(p).Mul(f))
+			if b.y {
+				r.Y = b.bound
+			} else {
+				r.X = b.bound
+			}
+			return r
+		}
+		if len(ring) == 0 {
+			return nil
+		}
+		var out []r2.Point
+		prev := ring[len(ring)-1]
+		for _, p := range ring {
+			if inside(p) {
+				if !inside(prev) {
+					out = append(out, intersect(prev, p))
+				}
+				out = append(out, p)
+			} else if inside(prev) {
+				out = append(out, intersect(prev, p))
+			}
+			prev = p
+		}
+		ring = out
+	}
+	return ring
+}
+
+// winding returns the winding number of the ring around p, which is
+// positive for rings that are counterclockwise when y points up.
+func winding(ring []r2.Point, p r2.Point) int {
+	w := 0
+	for i, a := range ring {
+		b := ring[(i+1)%len(ring)]
+		cross := (b.X-a.X)*(p.Y-a.Y) - (p.X-a.X)*(b.Y-a.Y)
+		if a.Y <= p.Y {
+			if b.Y > p.Y && cross > 0 {
+				w++
+			}
+		} else if b.Y <= p.Y && cross < 0 {
+			w--
+		}
+	}
+	return w
+}
+
+// round returns the nearest point with integer coordinates.
+func round(p r2.Point) tilePoint {
+	return tilePoint{int64(math.Round(p.X)), int64(math.Round(p.Y))}
+}
+
+// quantize rounds the points to integer coordinates and removes consecutive
+// duplicates. If closed is true the points form a ring, and the last point
+// must also differ from the first.
+func quantize(points []r2.Point, closed bool) []tilePoint {
+	var out []tilePoint
+	for _, p := range points {
+		q := round(p)
+		if n := len(out); n > 0 && out[n-1] == q {
+			continue
+		}
+		out = append(out, q)
+	}
+	for closed && len(out) > 1 && out[0] == out[len(out)-1] {
+		out = out[:len(out)-1]
+	}
+	return out
+}
+
+// area returns twice the signed area of a ring, which is positive for rings
+// that are clockwise in tile coordinates, where y points down.
+func area(ring []tilePoint) int64 {
+	var a int64
+	for i, p := range ring {
+		q := ring[(i+1)%len(ring)]
+		a += p.X*q.Y - q.X*p.Y
+	}
+	return a
+}
+
+// ringContains reports whether p is inside the ring, by the even-odd rule.
+func ringContains(ring []tilePoint, x, y float64) bool {
+	inside := false
+	for i, a := range ring {
+		b := ring[(i+1)%len(ring)]
+		ax, ay, bx, by := float64(a.X), float64(a.Y), float64(b.X), float64(b.Y)
+		if (ay > y) != (by > y) && x < ax+(y-ay)*(bx-ax)/(by-ay) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// assembleRings quantizes the clipped rings and orders them as MVT requires,
+// with each exterior ring followed by its holes. Exterior rings have a
+// positive area and holes a negative one. Each hole is assigned to the
+// smallest exterior ring that contains it, and holes outside every exterior
+// ring are dropped.
+func assembleRings(rings [][]r2.Point) [][]tilePoint {
+	type exterior struct {
+		ring  []tilePoint
+		area  int64
+		holes [][]tilePoint
+	}
+	var exteriors []*exterior
+	var holes [][]tilePoint
+	for _, ring := range rings {
+		q := quantize(ring, true)
+		if len(q) < 3 {
+			continue
+		}
+		switch a := area(q); {
+		case a > 0:
+			exteriors = append(exteriors, &exterior{ring: q, area: a})
+		case a < 0:
+			holes = append(holes, q)
+		}
+	}
+
+	for _, hole := range holes {
+		// Test a point just inside the hole, next to its first edge. The
+		// interior of a hole is on the left of its edges when y points down.
+		a, b := hole[0], hole[1]
+		dx, dy := float64(b.X-a.X), float64(b.Y-a.Y)
+		d := 1e-3 / math.Hypot(dx, dy)
+		x, y := (float64(a.X+b.X))/2+dy*d, (float64(a.Y+b.Y))/2-dx*d
+		var best *exterior
+		for _, e := range exteriors {
+			if (best == nil || e.area < best.area) && ringContains(e.ring, x, y) {
+				best = e
+			}
+		}
+		if best != nil {
+			best.holes = append(best.holes, hole)
+		}
+	}
+
+	var parts [][]tilePoint
+	for _, e := range exteriors {
+		parts = append(parts, e.ring)
+		parts = append(parts, e.holes...)
+	}
+	return parts
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvt
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/rubenpoppe/geo/r1"
+	"github.com/rubenpoppe/geo/r2"
+)
+
+var unitSquare = r2.Rect{X: r1.Interval{Lo: 0, Hi: 10}, Y: r1.Interval{Lo: 0, Hi: 10}}
+
+func TestClipLine(t *testing.T) {
+	tests := []struct {
+		line []r2.Point
+		want [][]r2.Point
+	}{
+		{
+			[]r2.Point{{X: 2, Y: 2}, {X: 5, Y: 5}},
+			[][]r2.Point{{{X: 2, Y: 2}, {X: 5, Y: 5}}},
+		},
+		{
+			[]r2.Point{{X: -5, Y: 5}, {X: 5, Y: 5}, {X: 5, Y: 15}},
+			[][]r2.Point{{{X: 0, Y: 5}, {X: 5, Y: 5}, {X: 5, Y: 10}}},
+		},
+		{
+			// The line leaves and reenters the rectangle.
+			[]r2.Point{{X: 2, Y: 5}, {X: 15, Y: 5}, {X: 15, Y: 8}, {X: 2, Y: 8}},
+			[][]r2.Point{{{X: 2, Y: 5}, {X: 10, Y: 5}}, {{X: 10, Y: 8}, {X: 2, Y: 8}}},
+		},
+		{
+			[]r2.Point{{X: 20, Y: 20}, {X: 30, Y: 20}},
+			nil,
+		},
+	}
+	for _, test := range tests {
+		if got := clipLine(test.line, unitSquare); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("clipLine(%v) = %v, want %v", test.line, got, test.want)
+		}
+	}
+}
+
+func TestClipRing(t *testing.T) {
+	// A square that overlaps the corner of the rectangle.
+	ring := []r2.Point{{X: 5, Y: 5}, {X: 15, Y: 5}, {X: 15, Y: 15}, {X: 5, Y: 15}}
+	if got := quantize(clipRing(ring, unitSquare), true); len(got) != 4 || area(got) != 50 {
+		t.Errorf("clipRing(%v) = %v, want the square from 5 to 10", ring, got)
+	}
+
+	// A ring around the rectangle becomes the rectangle.
+	ring = []r2.Point{{X: -5, Y: -5}, {X: 15, Y: -5}, {X: 15, Y: 15}, {X: -5, Y: 15}}
+	if got := clipRing(ring, unitSquare); math.Abs(float64(area(quantize(got, true)))) != 200 {
+		t.Errorf("clipRing(%v) = %v, want the whole rectangle", ring, got)
+	}
+
+	if got := clipRing([]r2.Point{{X: 20, Y: 20}, {X: 30, Y: 20}, {X: 30, Y: 30}}, unitSquare); len(got) != 0 {
+		t.Errorf("clipRing of a ring outside the rectangle = %v, want empty", got)
+	}
+}
+
+func TestWinding(t *testing.T) {
+	ccw := []r2.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+	if got := winding(ccw, r2.Point{X: 5, Y: 5}); got != 1 {
+		t.Errorf("winding(ccw, inside) = %d, want 1", got)
+	}
+	if got := winding(reversedRing(ccw), r2.Point{X: 5, Y: 5}); got != -1 {
+		t.Errorf("winding(cw, inside) = %d, want -1", got)
+	}
+	if got := winding(ccw, r2.Point{X: 15, Y: 5}); got != 0 {
+		t.Errorf("winding(ccw, outside) = %d, want 0", got)
+	}
+}
+
+func TestAssembleRings(t *testing.T) {
+	square := func(lo, hi float64) []r2.Point {
+		return []r2.Point{{X: lo, Y: lo}, {X: hi, Y: lo}, {X: hi, Y: hi}, {X: lo, Y: hi}}
+	}
+	outer := square(0, 100)
+	hole := reversedRing(square(10, 90))
+	island := square(20, 80)
+	islandHole := reversedRing(square(30, 70))
+	stray := reversedRing(square(200, 300))
+
+	parts := assembleRings([][]r2.Point{islandHole, outer, stray, island, hole, {{X: 1, Y: 1}, {X: 1, Y: 1}}})
+	want := [][]tilePoint{
+		quantize(outer, true),
+		quantize(hole, true),
+		quantize(island, true),
+		quantize(islandHole, true),
+	}
+	if !reflect.DeepEqual(parts, want) {
+		t.Errorf("assembleRings = %v, want %v", parts, want)
+	}
+}
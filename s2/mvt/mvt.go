@@ -0,0 +1,278 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package mvt encodes the contents of a ShapeIndex as a Mapbox Vector Tile
+(MVT), the protobuf format used to serve vector map tiles.
+
+Tiles are addressed by zoom level z and column x and row y in the XYZ scheme
+of web maps, where tile 0/0/0 covers the whole Web Mercator square and y
+increases southwards.
+
+Each shape of a layer that intersects the tile becomes one feature, whose
+geometry type follows the dimension of the shape. The geodesic edges of the
+shape are tessellated with an EdgeTessellator under the Mercator projection,
+so that the projected curves are accurate to half a tile unit. The result is
+clipped to the tile plus a buffer, and rounded to the integer coordinates of
+the tile extent. Polygons keep their holes, and shapes that contain a pole or
+are larger than a hemisphere are handled like any other.
+*/
+package mvt
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/rubenpoppe/geo/r1"
+	"github.com/rubenpoppe/geo/r2"
+	"github.com/rubenpoppe/geo/s1"
+	"github.com/rubenpoppe/geo/s2"
+)
+
+const (
+	// DefaultExtent is the number of integer coordinates across a tile if
+	// Encoder.Extent is zero.
+	DefaultExtent = 4096
+
+	// DefaultBuffer is the width in tile coordinates of the buffer around the
+	// tile if Encoder.Buffer is zero.
+	DefaultBuffer = 64
+
+	// maxZoom is the largest supported zoom level, at which tile coordinates
+	// are still far more precise than the projection.
+	maxZoom = 30
+)
+
+// A Layer is a named layer of a tile, whose features are the shapes of an
+// index.
+type Layer struct {
+	// Name is the name of the layer, which must be unique within a tile.
+	Name string
+
+	// Index holds the shapes of the layer.
+	Index *s2.ShapeIndex
+
+	// Attributes, if not nil, returns the attributes of the feature for the
+	// shape with the given ID. Values must be strings, booleans, integers or
+	// floating point numbers. Attributes with nil values are omitted.
+	Attributes func(shapeID int32) map[string]interface{}
+}
+
+// An Encoder encodes layers as a vector tile.
+type Encoder struct {
+	// Extent is the number of integer coordinates across the tile. If zero,
+	// DefaultExtent is used.
+	Extent int
+
+	// Buffer is the width in tile coordinates of the margin around the tile
+	// in which geometry is kept, so that lines and polygon outlines that cross
+	// the tile boundary are drawn without gaps. If zero, DefaultBuffer is
+	// used, and if negative there is no buffer.
+	Buffer int
+}
+
+// Encode returns the vector tile z/x/y containing the given layers, using the
+// default extent and buffer.
+func Encode(z, x, y int, layers ...Layer) ([]byte, error) {
+	return new(Encoder).Encode(z, x, y, layers...)
+}
+
+// Encode returns the vector tile z/x/y containing the given layers. Each shape
+// that intersects the tile, including its buffer, becomes a feature whose ID
+// is the shape ID. Layers without any features are omitted.
+func (e *Encoder) Encode(z, x, y int, layers ...Layer) ([]byte, error) {
+	if z < 0 || z > maxZoom {
+		return nil, fmt.Errorf("mvt: zoom %d is out of range [0, %d]", z, maxZoom)
+	}
+	if n := 1 << uint(z); x < 0 || x >= n || y < 0 || y >= n {
+		return nil, fmt.Errorf("mvt: tile %d/%d/%d does not exist", z, x, y)
+	}
+	t := e.tile(z, x, y)
+
+	var b buffer
+	names := make(map[string]bool)
+	for _, l := range layers {
+		if names[l.Name] {
+			return nil, fmt.Errorf("mvt: duplicate layer name %q", l.Name)
+		}
+		names[l.Name] = true
+		data, err := t.layer(l)
+		if err != nil {
+			return nil, fmt.Errorf("mvt: layer %q: %v", l.Name, err)
+		}
+		if data != nil {
+			b.bytesField(tileLayers, data)
+		}
+	}
+	return b, nil
+}
+
+// tile holds the transformation between Mercator world coordinates and the
+// coordinates of a tile.
+type tile struct {
+	proj   s2.Projection
+	tess   *s2.EdgeTessellator
+	extent int
+
+	// origin is the world coordinate of the top left corner of the tile, and
+	// scale the number of tile coordinates per world coordinate.
+	origin r2.Point
+	scale  float64
+
+	// clip is the tile plus its buffer, in tile coordinates.
+	clip r2.Rect
+}
+
+// tile returns the transformation for tile z/x/y.
+func (e *Encoder) tile(z, x, y int) *tile {
+	extent := e.Extent
+	if extent <= 0 {
+		extent = DefaultExtent
+	}
+	buf := float64(e.Buffer)
+	if e.Buffer == 0 {
+		buf = DefaultBuffer
+	} else if e.Buffer < 0 {
+		buf = 0
+	}
+
+	// World coordinates span [-0.5, 0.5] in both directions, with y
+	// increasing northwards.
+	n := float64(int(1) << uint(z))
+	t := &tile{
+		proj:   s2.NewMercatorProjection(0.5),
+		extent: extent,
+		origin: r2.Point{X: -0.5 + float64(x)/n, Y: 0.5 - float64(y)/n},
+		scale:  n * float64(extent),
+		clip: r2.Rect{
+			X: r1.Interval{Lo: -buf, Hi: float64(extent) + buf},
+			Y: r1.Interval{Lo: -buf, Hi: float64(extent) + buf},
+		},
+	}
+
+	// Tessellate edges to within half a tile coordinate. A distance on the
+	// sphere is magnified by 1/cos(lat) in the Mercator projection, so the
+	// tolerance is set for the latitude of the tile farthest from the
+	// equator.
+	var maxLat float64
+	for _, ty := range []float64{t.clip.Y.Lo, t.clip.Y.Hi} {
+		maxLat = math.Max(maxLat, math.Abs(t.toSphere(r2.Point{X: 0, Y: ty}).Lat.Radians()))
+	}
+	tolerance := 0.5 / t.scale * 2 * math.Pi * math.Cos(maxLat)
+	t.tess = s2.NewEdgeTessellator(t.proj, s1.Angle(tolerance))
+	return t
+}
+
+// toTile converts a point in world coordinates to tile coordinates.
+func (t *tile) toTile(p r2.Point) r2.Point {
+	return r2.Point{X: (p.X - t.origin.X) * t.scale, Y: (t.origin.Y - p.Y) * t.scale}
+}
+
+// toSphere converts a point in tile coordinates to a LatLng.
+func (t *tile) toSphere(p r2.Point) s2.LatLng {
+	world := r2.Point{X: t.origin.X + p.X/t.scale, Y: t.origin.Y - p.Y/t.scale}
+	return t.proj.(*s2.MercatorProjection).ToLatLng(world)
+}
+
+// bound returns a LatLng rectangle containing the tile and its buffer.
+func (t *tile) bound() s2.Rect {
+	lo := t.toSphere(r2.Point{X: t.clip.X.Lo, Y: t.clip.Y.Hi})
+	hi := t.toSphere(r2.Point{X: t.clip.X.Hi, Y: t.clip.Y.Lo})
+	lat := r1.Interval{Lo: lo.Lat.Radians(), Hi: hi.Lat.Radians()}
+	if t.clip.X.Length() >= t.scale {
+		return s2.Rect{Lat: lat, Lng: s1.FullInterval()}
+	}
+	return s2.Rect{Lat: lat, Lng: s1.IntervalFromEndpoints(lo.Lng.Radians(), hi.Lng.Radians())}
+}
+
+// layer returns the encoding of a layer, or nil if it has no features.
+func (t *tile) layer(l Layer) ([]byte, error) {
+	var b buffer
+	b.stringField(layerName, l.Name)
+	b.varintField(layerExtent, uint64(t.extent))
+	b.varintField(layerVersion, 2)
+
+	tags := newTagger()
+	features := 0
+	var contains *s2.ContainsPointQuery
+	for _, id := range shapesIntersecting(l.Index, t.bound()) {
+		shape := l.Index.Shape(id)
+		if shape == nil {
+			continue
+		}
+		var g geometry
+		switch shape.Dimension() {
+		case 0:
+			g = t.points(shape)
+		case 1:
+			g = t.lines(shape)
+		default:
+			if contains == nil {
+				contains = s2.NewContainsPointQuery(l.Index, s2.VertexModelSemiOpen)
+			}
+			g = t.polygons(shape, func(p s2.Point) bool { return contains.ShapeContains(shape, p) })
+		}
+		if g.empty() {
+			continue
+		}
+
+		var f buffer
+		f.varintField(featureID, uint64(id))
+		if l.Attributes != nil {
+			ids, err := tags.tags(l.Attributes(id))
+			if err != nil {
+				return nil, fmt.Errorf("shape %d: %v", id, err)
+			}
+			f.packedField(featureTags, ids)
+		}
+		f.varintField(featureType, uint64(g.typ))
+		f.packedField(featureGeometry, g.commands())
+		b.bytesField(layerFeatures, f)
+		features++
+	}
+	if features == 0 {
+		return nil, nil
+	}
+	tags.encode(&b)
+	return b, nil
+}
+
+// shapesIntersecting returns the IDs of the shapes in the index that may
+// intersect the given rectangle, in increasing order.
+func shapesIntersecting(index *s2.ShapeIndex, rect s2.Rect) []int32 {
+	coverer := &s2.RegionCoverer{MaxLevel: 30, LevelMod: 1, MaxCells: 8}
+	seen := make(map[int32]bool)
+	it := index.Iterator()
+	for _, id := range coverer.Covering(rect) {
+		switch it.LocateCellID(id) {
+		case s2.Indexed:
+			for _, shapeID := range it.IndexCell().ShapeIDs() {
+				seen[shapeID] = true
+			}
+		case s2.Subdivided:
+			for ; !it.Done() && it.CellID() <= id.RangeMax(); it.Next() {
+				for _, shapeID := range it.IndexCell().ShapeIDs() {
+					seen[shapeID] = true
+				}
+			}
+		}
+	}
+	ids := make([]int32, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,410 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvt
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rubenpoppe/geo/r2"
+	"github.com/rubenpoppe/geo/s1"
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// testLayer and testFeature are decoded layers and features of a tile.
+type testLayer struct {
+	name     string
+	extent   int
+	version  int
+	keys     []string
+	values   [][]protoField
+	features []testFeature
+}
+
+type testFeature struct {
+	id    uint64
+	tags  []uint32
+	typ   int
+	parts [][]tilePoint
+}
+
+// decodeTile decodes the layers of a tile.
+func decodeTile(t *testing.T, data []byte) []testLayer {
+	t.Helper()
+	var layers []testLayer
+	for _, lf := range decodeMessage(t, data) {
+		if lf.num != tileLayers {
+			t.Fatalf("unexpected tile field %d", lf.num)
+		}
+		l := testLayer{extent: DefaultExtent}
+		for _, f := range decodeMessage(t, lf.data) {
+			switch f.num {
+			case layerName:
+				l.name = string(f.data)
+			case layerExtent:
+				l.extent = int(f.x)
+			case layerVersion:
+				l.version = int(f.x)
+			case layerKeys:
+				l.keys = append(l.keys, string(f.data))
+			case layerValues:
+				l.values = append(l.values, decodeMessage(t, f.data))
+			case layerFeatures:
+				l.features = append(l.features, decodeFeature(t, f.data))
+			}
+		}
+		layers = append(layers, l)
+	}
+	return layers
+}
+
+// decodeFeature decodes a feature and its geometry commands.
+func decodeFeature(t *testing.T, data []byte) testFeature {
+	t.Helper()
+	var f testFeature
+	var cmds []uint32
+	for _, field := range decodeMessage(t, data) {
+		switch field.num {
+		case featureID:
+			f.id = field.x
+		case featureTags:
+			f.tags = decodePacked(t, field.data)
+		case featureType:
+			f.typ = int(field.x)
+		case featureGeometry:
+			cmds = decodePacked(t, field.data)
+		}
+	}
+
+	var cursor tilePoint
+	unzigzag := func(x uint32) int64 { return int64(x>>1) ^ -int64(x&1) }
+	for i := 0; i < len(cmds); {
+		id, count := int(cmds[i]&7), int(cmds[i]>>3)
+		i++
+		switch id {
+		case cmdMoveTo, cmdLineTo:
+			if id == cmdMoveTo && (f.typ != geomPoint || len(f.parts) == 0) {
+				f.parts = append(f.parts, nil)
+			}
+			for j := 0; j < count; j++ {
+				cursor.X += unzigzag(cmds[i])
+				cursor.Y += unzigzag(cmds[i+1])
+				i += 2
+				f.parts[len(f.parts)-1] = append(f.parts[len(f.parts)-1], cursor)
+			}
+		case cmdClosePath:
+		default:
+			t.Fatalf("unknown command %d", id)
+		}
+	}
+	return f
+}
+
+// featureContains reports whether the polygon feature contains the point by
+// the even-odd rule.
+func featureContains(f testFeature, p r2.Point) bool {
+	inside := false
+	for _, ring := range f.parts {
+		if ringContains(ring, p.X, p.Y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// encodeShapes encodes a tile with a single layer containing the shapes.
+func encodeShapes(t *testing.T, e *Encoder, z, x, y int, shapes ...s2.Shape) testLayer {
+	t.Helper()
+	index := s2.NewShapeIndex()
+	for _, s := range shapes {
+		index.Add(s)
+	}
+	data, err := e.Encode(z, x, y, Layer{Name: "test", Index: index})
+	if err != nil {
+		t.Fatalf("Encode(%d, %d, %d) failed: %v", z, x, y, err)
+	}
+	layers := decodeTile(t, data)
+	if len(layers) == 0 {
+		return testLayer{}
+	}
+	return layers[0]
+}
+
+func TestEncodeErrors(t *testing.T) {
+	index := s2.NewShapeIndex()
+	tests := []struct {
+		z, x, y int
+		layers  []Layer
+		want    string
+	}{
+		{-1, 0, 0, nil, "zoom -1 is out of range"},
+		{31, 0, 0, nil, "zoom 31 is out of range"},
+		{1, 2, 0, nil, "tile 1/2/0 does not exist"},
+		{1, 0, -1, nil, "tile 1/0/-1 does not exist"},
+		{0, 0, 0, []Layer{{Name: "a", Index: index}, {Name: "a", Index: index}}, `duplicate layer name "a"`},
+	}
+	for _, test := range tests {
+		_, err := Encode(test.z, test.x, test.y, test.layers...)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("Encode(%d, %d, %d) = %v, want error containing %q", test.z, test.x, test.y, err, test.want)
+		}
+	}
+
+	index.Add(&s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0))})
+	_, err := Encode(0, 0, 0, Layer{
+		Name:       "bad",
+		Index:      index,
+		Attributes: func(int32) map[string]interface{} { return map[string]interface{}{"x": struct{}{}} },
+	})
+	if err == nil || !strings.Contains(err.Error(), `layer "bad": shape 0: attribute "x"`) {
+		t.Errorf("Encode with an unsupported attribute = %v, want error", err)
+	}
+}
+
+func TestEncodeLayer(t *testing.T) {
+	points := s2.NewShapeIndex()
+	points.Add(&s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0))})
+	points.Add(&s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(10, 10))})
+	empty := s2.NewShapeIndex()
+	empty.Add(&s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(-60, -120))})
+
+	data, err := Encode(1, 1, 0,
+		Layer{
+			Name:  "points",
+			Index: points,
+			Attributes: func(id int32) map[string]interface{} {
+				return map[string]interface{}{"id": int(id), "kind": "poi"}
+			},
+		},
+		Layer{Name: "empty", Index: empty},
+	)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	layers := decodeTile(t, data)
+	if len(layers) != 1 {
+		t.Fatalf("Encode returned %d layers, want 1", len(layers))
+	}
+	l := layers[0]
+	if l.name != "points" || l.extent != DefaultExtent || l.version != 2 {
+		t.Errorf("layer = %q, extent %d, version %d, want %q, %d, 2", l.name, l.extent, l.version, "points", DefaultExtent)
+	}
+	if want := []string{"id", "kind"}; !reflect.DeepEqual(l.keys, want) {
+		t.Errorf("keys = %v, want %v", l.keys, want)
+	}
+	if len(l.values) != 3 {
+		t.Errorf("values = %v, want 3 values", l.values)
+	}
+
+	// Tile 1/1/0 is the north east quarter of the world, so the point at 0:0
+	// is in its bottom left corner.
+	want := []testFeature{
+		{id: 0, tags: []uint32{0, 0, 1, 1}, typ: geomPoint, parts: [][]tilePoint{{{0, 4096}}}},
+		{id: 1, tags: []uint32{0, 2, 1, 1}, typ: geomPoint, parts: [][]tilePoint{{{228, 3867}}}},
+	}
+	if !reflect.DeepEqual(l.features, want) {
+		t.Errorf("features = %+v, want %+v", l.features, want)
+	}
+}
+
+func TestEncodeBuffer(t *testing.T) {
+	// The point at 0:-0.5 is just outside tile 1/1/0 but within its buffer.
+	p := &s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(0, -0.5))}
+	if l := encodeShapes(t, &Encoder{}, 1, 1, 0, p); len(l.features) != 1 {
+		t.Errorf("features with the default buffer = %v, want 1", l.features)
+	}
+	if l := encodeShapes(t, &Encoder{Buffer: -1}, 1, 1, 0, p); len(l.features) != 0 {
+		t.Errorf("features without a buffer = %v, want none", l.features)
+	}
+	l := encodeShapes(t, &Encoder{Extent: 256, Buffer: 16}, 1, 1, 0, p)
+	if len(l.features) != 1 || l.extent != 256 {
+		t.Fatalf("features with extent 256 = %v, want 1", l.features)
+	}
+	if got, want := l.features[0].parts, [][]tilePoint{{{-1, 256}}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("point with extent 256 = %v, want %v", got, want)
+	}
+}
+
+func TestEncodeLineString(t *testing.T) {
+	// A line along the equator through tile 2/1/1, which spans longitudes
+	// [-90, 0] and latitudes [0, 66.5].
+	line := s2.PolylineFromLatLngs([]s2.LatLng{s2.LatLngFromDegrees(0, -120), s2.LatLngFromDegrees(0, 30)})
+	l := encodeShapes(t, &Encoder{}, 2, 1, 1, line)
+	if len(l.features) != 1 {
+		t.Fatalf("features = %v, want 1", l.features)
+	}
+	f := l.features[0]
+	if f.typ != geomLineString || len(f.parts) != 1 {
+		t.Fatalf("line = %v, want a single line string", f.parts)
+	}
+	line0 := f.parts[0]
+	if first, last := line0[0], line0[len(line0)-1]; first != (tilePoint{-64, 4096}) || last != (tilePoint{4160, 4096}) {
+		t.Errorf("line = %v, want from (-64, 4096) to (4160, 4096)", line0)
+	}
+	for _, p := range line0 {
+		if p.Y != 4096 {
+			t.Errorf("line = %v, want it along the equator", line0)
+			break
+		}
+	}
+
+	// A geodesic between two points at the same latitude bulges towards the
+	// pole in the Mercator projection.
+	line = s2.PolylineFromLatLngs([]s2.LatLng{s2.LatLngFromDegrees(45, -85), s2.LatLngFromDegrees(45, -5)})
+	l = encodeShapes(t, &Encoder{}, 2, 1, 1, line)
+	if len(l.features) != 1 || len(l.features[0].parts) != 1 {
+		t.Fatalf("features = %v, want 1 line", l.features)
+	}
+	var minY int64 = math.MaxInt64
+	for _, p := range l.features[0].parts[0] {
+		if p.Y < minY {
+			minY = p.Y
+		}
+	}
+	// The midpoint of the geodesic is at latitude atan(tan(45°)/cos(40°)).
+	top := r2.Point{X: 2048, Y: float64(minY)}
+	e := &Encoder{}
+	if lat := e.tile(2, 1, 1).toSphere(top).Lat.Degrees(); math.Abs(lat-52.55) > 0.01 {
+		t.Errorf("northernmost latitude of the line = %v, want 52.55", lat)
+	}
+}
+
+func TestEncodeAntimeridian(t *testing.T) {
+	// The line crosses the antimeridian, and appears in the tiles on both
+	// sides of it.
+	line := s2.PolylineFromLatLngs([]s2.LatLng{s2.LatLngFromDegrees(10, 170), s2.LatLngFromDegrees(10, -170)})
+	for _, x := range []int{0, 1} {
+		l := encodeShapes(t, &Encoder{}, 1, x, 0, line)
+		if len(l.features) != 1 {
+			t.Errorf("tile 1/%d/0 has features %v, want 1", x, l.features)
+		}
+	}
+
+	// A polygon across the antimeridian covers the edge of both tiles.
+	loop := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(5, 170)), s2.PointFromLatLng(s2.LatLngFromDegrees(5, -170)), s2.PointFromLatLng(s2.LatLngFromDegrees(15, -170)), s2.PointFromLatLng(s2.LatLngFromDegrees(15, 170))})
+	for _, tc := range []struct {
+		x  int
+		at r2.Point
+	}{{0, r2.Point{X: 20, Y: 3900}}, {1, r2.Point{X: 4076, Y: 3900}}} {
+		l := encodeShapes(t, &Encoder{}, 1, tc.x, 0, loop)
+		if len(l.features) != 1 || !featureContains(l.features[0], tc.at) {
+			t.Errorf("tile 1/%d/0 polygon %v does not contain %v", tc.x, l.features, tc.at)
+		}
+	}
+}
+
+func TestEncodePolygonRings(t *testing.T) {
+	shell := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(10, -80)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, -10)), s2.PointFromLatLng(s2.LatLngFromDegrees(60, -10)), s2.PointFromLatLng(s2.LatLngFromDegrees(60, -80))})
+	hole := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(20, -70)), s2.PointFromLatLng(s2.LatLngFromDegrees(20, -20)), s2.PointFromLatLng(s2.LatLngFromDegrees(50, -20)), s2.PointFromLatLng(s2.LatLngFromDegrees(50, -70))})
+	polygon := s2.PolygonFromLoops([]*s2.Loop{shell, hole})
+	l := encodeShapes(t, &Encoder{}, 2, 1, 1, polygon)
+	if len(l.features) != 1 {
+		t.Fatalf("features = %v, want 1", l.features)
+	}
+	f := l.features[0]
+	if f.typ != geomPolygon || len(f.parts) != 2 {
+		t.Fatalf("polygon = %v, want an exterior ring and a hole", f.parts)
+	}
+	if area(f.parts[0]) <= 0 || area(f.parts[1]) >= 0 {
+		t.Errorf("ring areas = %d, %d, want a positive exterior and a negative hole", area(f.parts[0]), area(f.parts[1]))
+	}
+}
+
+func TestEncodePolygonContainsTile(t *testing.T) {
+	// The tile is inside the polygon, so the feature is the tile and its
+	// buffer.
+	loop := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(-40, -60)), s2.PointFromLatLng(s2.LatLngFromDegrees(-40, 60)), s2.PointFromLatLng(s2.LatLngFromDegrees(40, 60)), s2.PointFromLatLng(s2.LatLngFromDegrees(40, -60))})
+	l := encodeShapes(t, &Encoder{}, 4, 8, 8, loop)
+	if len(l.features) != 1 || len(l.features[0].parts) != 1 {
+		t.Fatalf("features = %v, want a single ring", l.features)
+	}
+	if ring := l.features[0].parts[0]; len(ring) != 4 || area(ring) != 2*4224*4224 {
+		t.Errorf("ring = %v, want the tile and its buffer", ring)
+	}
+
+	// A hole that contains the tile leaves nothing.
+	shell := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(-60, -80)), s2.PointFromLatLng(s2.LatLngFromDegrees(-60, 80)), s2.PointFromLatLng(s2.LatLngFromDegrees(60, 80)), s2.PointFromLatLng(s2.LatLngFromDegrees(60, -80))})
+	polygon := s2.PolygonFromLoops([]*s2.Loop{shell, loop})
+	if l := encodeShapes(t, &Encoder{}, 4, 8, 8, polygon); len(l.features) != 0 {
+		t.Errorf("features = %v, want none", l.features)
+	}
+}
+
+// checkContainment checks that the polygon features of a tile contain the
+// points of the tile that the shape contains, except close to its boundary.
+func checkContainment(t *testing.T, name string, shape s2.Shape, contains func(s2.Point) bool, z, x, y int) {
+	t.Helper()
+	e := &Encoder{}
+	l := encodeShapes(t, e, z, x, y, shape)
+	tl := e.tile(z, x, y)
+	for i := 0; i <= 16; i++ {
+		for j := 0; j <= 16; j++ {
+			p := r2.Point{X: float64(i) * 256, Y: float64(j) * 256}
+			want := contains(s2.PointFromLatLng(tl.toSphere(p)))
+			nearBoundary := false
+			for _, d := range []r2.Point{{X: 3}, {X: -3}, {Y: 3}, {Y: -3}} {
+				if contains(s2.PointFromLatLng(tl.toSphere(p.Add(d)))) != want {
+					nearBoundary = true
+				}
+			}
+			if nearBoundary {
+				continue
+			}
+			got := len(l.features) == 1 && featureContains(l.features[0], p)
+			if got != want {
+				t.Errorf("%s: tile %d/%d/%d contains %v = %v, want %v", name, z, x, y, p, got, want)
+			}
+		}
+	}
+}
+
+func TestEncodePolygonContainment(t *testing.T) {
+	capAt := func(lat, lng, radius float64) *s2.Loop {
+		return s2.RegularLoop(s2.PointFromLatLng(s2.LatLngFromDegrees(lat, lng)), s1.Angle(radius)*s1.Degree, 64)
+	}
+	northCap := capAt(90, 0, 30)
+	southCap := capAt(-90, 0, 30)
+	complement := capAt(20, 30, 40)
+	complement.Invert()
+	withPole := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(50, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(50, 120)), s2.PointFromCoords(0, 0, 1)})
+	big := s2.PolygonFromLoops([]*s2.Loop{capAt(0, 0, 80), capAt(10, 10, 20)})
+
+	shapes := []struct {
+		name  string
+		shape s2.Shape
+	}{
+		{"north cap", northCap},
+		{"south cap", southCap},
+		{"complement", complement},
+		{"vertex at pole", withPole},
+		{"big with hole", big},
+		{"antimeridian", capAt(40, 180, 25)},
+	}
+	tiles := [][3]int{
+		{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {1, 0, 1}, {1, 1, 1},
+		{2, 0, 0}, {2, 2, 0}, {2, 3, 1}, {2, 1, 3}, {3, 4, 2}, {3, 0, 1}, {3, 7, 2},
+	}
+	for _, s := range shapes {
+		index := s2.NewShapeIndex()
+		index.Add(s.shape)
+		q := s2.NewContainsPointQuery(index, s2.VertexModelSemiOpen)
+		contains := func(p s2.Point) bool { return q.ShapeContains(s.shape, p) }
+		for _, tl := range tiles {
+			checkContainment(t, s.name, s.shape, contains, tl[0], tl[1], tl[2])
+		}
+	}
+}
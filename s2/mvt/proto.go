@@ -0,0 +1,264 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// The field numbers of the vector tile protobuf messages.
+const (
+	tileLayers = 3
+
+	layerName     = 1
+	layerFeatures = 2
+	layerKeys     = 3
+	layerValues   = 4
+	layerExtent   = 5
+	layerVersion  = 15
+
+	featureID       = 1
+	featureTags     = 2
+	featureType     = 3
+	featureGeometry = 4
+
+	valueString = 1
+	valueFloat  = 2
+	valueDouble = 3
+	valueUint   = 5
+	valueSint   = 6
+	valueBool   = 7
+)
+
+// The protobuf wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// The geometry commands.
+const (
+	cmdMoveTo    = 1
+	cmdLineTo    = 2
+	cmdClosePath = 7
+)
+
+// buffer is an encoded protobuf message.
+type buffer []byte
+
+func (b *buffer) varint(x uint64) {
+	*b = binary.AppendUvarint(*b, x)
+}
+
+func (b *buffer) key(field, wireType int) {
+	b.varint(uint64(field<<3 | wireType))
+}
+
+func (b *buffer) varintField(field int, x uint64) {
+	b.key(field, wireVarint)
+	b.varint(x)
+}
+
+func (b *buffer) bytesField(field int, data []byte) {
+	b.key(field, wireBytes)
+	b.varint(uint64(len(data)))
+	*b = append(*b, data...)
+}
+
+func (b *buffer) stringField(field int, s string) {
+	b.bytesField(field, []byte(s))
+}
+
+func (b *buffer) fixed32Field(field int, x uint32) {
+	b.key(field, wireFixed32)
+	*b = binary.LittleEndian.AppendUint32(*b, x)
+}
+
+func (b *buffer) fixed64Field(field int, x uint64) {
+	b.key(field, wireFixed64)
+	*b = binary.LittleEndian.AppendUint64(*b, x)
+}
+
+// packedField writes a packed repeated field of varints.
+func (b *buffer) packedField(field int, xs []uint32) {
+	var packed buffer
+	for _, x := range xs {
+		packed.varint(uint64(x))
+	}
+	b.bytesField(field, packed)
+}
+
+// zigzag encodes a signed integer so that small magnitudes have short
+// varints.
+func zigzag(x int64) uint64 {
+	return uint64(x<<1) ^ uint64(x>>63)
+}
+
+// command returns a command integer with the given id and count.
+func command(id, count int) uint32 {
+	return uint32(id&0x7 | count<<3)
+}
+
+// commands returns the geometry commands of g, whose coordinates are relative
+// to the previous point.
+func (g geometry) commands() []uint32 {
+	var cmds []uint32
+	var cursor tilePoint
+	move := func(p tilePoint) {
+		cmds = append(cmds, uint32(zigzag(p.X-cursor.X)), uint32(zigzag(p.Y-cursor.Y)))
+		cursor = p
+	}
+	for _, part := range g.parts {
+		if g.typ == geomPoint {
+			cmds = append(cmds, command(cmdMoveTo, len(part)))
+			for _, p := range part {
+				move(p)
+			}
+			continue
+		}
+		cmds = append(cmds, command(cmdMoveTo, 1))
+		move(part[0])
+		cmds = append(cmds, command(cmdLineTo, len(part)-1))
+		for _, p := range part[1:] {
+			move(p)
+		}
+		if g.typ == geomPolygon {
+			cmds = append(cmds, command(cmdClosePath, 1))
+		}
+	}
+	return cmds
+}
+
+// value is an attribute value, in the form in which it is encoded.
+type value struct {
+	field int
+	s     string
+	x     uint64
+}
+
+// tagger assigns indexes to the attribute keys and values of a layer.
+type tagger struct {
+	keys       []string
+	keyIndex   map[string]uint32
+	values     []value
+	valueIndex map[value]uint32
+}
+
+func newTagger() *tagger {
+	return &tagger{
+		keyIndex:   make(map[string]uint32),
+		valueIndex: make(map[value]uint32),
+	}
+}
+
+// tags returns the tags of a feature with the given attributes, which are
+// pairs of key and value indexes, in order of increasing key.
+func (t *tagger) tags(attrs map[string]interface{}) ([]uint32, error) {
+	names := make([]string, 0, len(attrs))
+	for k, v := range attrs {
+		if v != nil {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+
+	tags := make([]uint32, 0, 2*len(names))
+	for _, k := range names {
+		v, err := encodeValue(attrs[k])
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %v", k, err)
+		}
+		ki, ok := t.keyIndex[k]
+		if !ok {
+			ki = uint32(len(t.keys))
+			t.keyIndex[k] = ki
+			t.keys = append(t.keys, k)
+		}
+		vi, ok := t.valueIndex[v]
+		if !ok {
+			vi = uint32(len(t.values))
+			t.valueIndex[v] = vi
+			t.values = append(t.values, v)
+		}
+		tags = append(tags, ki, vi)
+	}
+	return tags, nil
+}
+
+// encode writes the keys and values of the layer.
+func (t *tagger) encode(b *buffer) {
+	for _, k := range t.keys {
+		b.stringField(layerKeys, k)
+	}
+	for _, v := range t.values {
+		var vb buffer
+		switch v.field {
+		case valueString:
+			vb.stringField(valueString, v.s)
+		case valueFloat:
+			vb.fixed32Field(valueFloat, uint32(v.x))
+		case valueDouble:
+			vb.fixed64Field(valueDouble, v.x)
+		default:
+			vb.varintField(v.field, v.x)
+		}
+		b.bytesField(layerValues, vb)
+	}
+}
+
+// encodeValue converts an attribute value to the value message field that
+// represents it. Signed integers use the zigzag encoded sint field.
+func encodeValue(v interface{}) (value, error) {
+	switch v := v.(type) {
+	case string:
+		return value{field: valueString, s: v}, nil
+	case bool:
+		var x uint64
+		if v {
+			x = 1
+		}
+		return value{field: valueBool, x: x}, nil
+	case float32:
+		return value{field: valueFloat, x: uint64(math.Float32bits(v))}, nil
+	case float64:
+		return value{field: valueDouble, x: math.Float64bits(v)}, nil
+	case int:
+		return value{field: valueSint, x: zigzag(int64(v))}, nil
+	case int8:
+		return value{field: valueSint, x: zigzag(int64(v))}, nil
+	case int16:
+		return value{field: valueSint, x: zigzag(int64(v))}, nil
+	case int32:
+		return value{field: valueSint, x: zigzag(int64(v))}, nil
+	case int64:
+		return value{field: valueSint, x: zigzag(v)}, nil
+	case uint:
+		return value{field: valueUint, x: uint64(v)}, nil
+	case uint8:
+		return value{field: valueUint, x: uint64(v)}, nil
+	case uint16:
+		return value{field: valueUint, x: uint64(v)}, nil
+	case uint32:
+		return value{field: valueUint, x: uint64(v)}, nil
+	case uint64:
+		return value{field: valueUint, x: v}, nil
+	}
+	return value{}, fmt.Errorf("unsupported value type %T", v)
+}
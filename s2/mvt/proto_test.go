@@ -0,0 +1,237 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvt
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// protoField is a decoded protobuf field. Varint and fixed fields are stored
+// in x, and length-delimited fields in data.
+type protoField struct {
+	num  int
+	wire int
+	x    uint64
+	data []byte
+}
+
+// decodeMessage splits a protobuf message into its fields.
+func decodeMessage(t *testing.T, data []byte) []protoField {
+	t.Helper()
+	var fields []protoField
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			t.Fatalf("invalid key in %X", data)
+		}
+		data = data[n:]
+		f := protoField{num: int(key >> 3), wire: int(key & 7)}
+		switch f.wire {
+		case wireVarint:
+			f.x, n = binary.Uvarint(data)
+			if n <= 0 {
+				t.Fatalf("invalid varint in %X", data)
+			}
+			data = data[n:]
+		case wireFixed64:
+			f.x = binary.LittleEndian.Uint64(data)
+			data = data[8:]
+		case wireFixed32:
+			f.x = uint64(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		case wireBytes:
+			l, n := binary.Uvarint(data)
+			if n <= 0 || int(l) > len(data)-n {
+				t.Fatalf("invalid length in %X", data)
+			}
+			f.data = data[n : n+int(l)]
+			data = data[n+int(l):]
+		default:
+			t.Fatalf("unknown wire type %d", f.wire)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// decodePacked decodes a packed repeated field of varints.
+func decodePacked(t *testing.T, data []byte) []uint32 {
+	t.Helper()
+	var xs []uint32
+	for len(data) > 0 {
+		x, n := binary.Uvarint(data)
+		if n <= 0 {
+			t.Fatalf("invalid packed varint in %X", data)
+		}
+		xs = append(xs, uint32(x))
+		data = data[n:]
+	}
+	return xs
+}
+
+func TestBufferFields(t *testing.T) {
+	var b buffer
+	b.varintField(1, 300)
+	b.stringField(2, "s2")
+	b.fixed32Field(3, 7)
+	b.fixed64Field(4, 9)
+	b.packedField(5, []uint32{1, 150})
+
+	want := []protoField{
+		{num: 1, wire: wireVarint, x: 300},
+		{num: 2, wire: wireBytes, data: []byte("s2")},
+		{num: 3, wire: wireFixed32, x: 7},
+		{num: 4, wire: wireFixed64, x: 9},
+		{num: 5, wire: wireBytes, data: []byte{1, 150, 1}},
+	}
+	if got := decodeMessage(t, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("fields = %+v, want %+v", got, want)
+	}
+	// The example from the protobuf documentation.
+	var v buffer
+	v.varintField(1, 150)
+	if want := []byte{0x08, 0x96, 0x01}; !reflect.DeepEqual([]byte(v), want) {
+		t.Errorf("varintField(1, 150) = %X, want %X", []byte(v), want)
+	}
+}
+
+func TestZigzag(t *testing.T) {
+	tests := []struct {
+		x    int64
+		want uint64
+	}{
+		{0, 0},
+		{-1, 1},
+		{1, 2},
+		{-2, 3},
+		{2147483647, 4294967294},
+		{-2147483648, 4294967295},
+	}
+	for _, test := range tests {
+		if got := zigzag(test.x); got != test.want {
+			t.Errorf("zigzag(%d) = %d, want %d", test.x, got, test.want)
+		}
+	}
+}
+
+func TestCommands(t *testing.T) {
+	// The examples from the vector tile specification.
+	tests := []struct {
+		g    geometry
+		want []uint32
+	}{
+		{
+			geometry{geomPoint, [][]tilePoint{{{25, 17}}}},
+			[]uint32{9, 50, 34},
+		},
+		{
+			geometry{geomPoint, [][]tilePoint{{{5, 7}, {3, 2}}}},
+			[]uint32{17, 10, 14, 3, 9},
+		},
+		{
+			geometry{geomLineString, [][]tilePoint{{{2, 2}, {2, 10}, {10, 10}}}},
+			[]uint32{9, 4, 4, 18, 0, 16, 16, 0},
+		},
+		{
+			geometry{geomLineString, [][]tilePoint{{{2, 2}, {2, 10}, {10, 10}}, {{1, 1}, {3, 5}}}},
+			[]uint32{9, 4, 4, 18, 0, 16, 16, 0, 9, 17, 17, 10, 4, 8},
+		},
+		{
+			geometry{geomPolygon, [][]tilePoint{{{3, 6}, {8, 12}, {20, 34}}}},
+			[]uint32{9, 6, 12, 18, 10, 12, 24, 44, 15},
+		},
+	}
+	for _, test := range tests {
+		if got := test.g.commands(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%v.commands() = %v, want %v", test.g, got, test.want)
+		}
+	}
+}
+
+func TestTagger(t *testing.T) {
+	tg := newTagger()
+	tags, err := tg.tags(map[string]interface{}{"name": "a", "rank": 3, "skip": nil})
+	if err != nil {
+		t.Fatalf("tags failed: %v", err)
+	}
+	if want := []uint32{0, 0, 1, 1}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("tags = %v, want %v", tags, want)
+	}
+	// Keys and values are shared between features.
+	tags, err = tg.tags(map[string]interface{}{"rank": 3, "area": 1.5})
+	if err != nil {
+		t.Fatalf("tags failed: %v", err)
+	}
+	if want := []uint32{2, 2, 1, 1}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("tags = %v, want %v", tags, want)
+	}
+	if _, err := tg.tags(map[string]interface{}{"bad": []int{1}}); err == nil {
+		t.Errorf("tags with a slice value succeeded, want error")
+	}
+
+	var b buffer
+	tg.encode(&b)
+	var keys []string
+	var values [][]protoField
+	for _, f := range decodeMessage(t, b) {
+		switch f.num {
+		case layerKeys:
+			keys = append(keys, string(f.data))
+		case layerValues:
+			values = append(values, decodeMessage(t, f.data))
+		}
+	}
+	if want := []string{"name", "rank", "area"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+	wantValues := [][]protoField{
+		{{num: valueString, wire: wireBytes, data: []byte("a")}},
+		{{num: valueSint, wire: wireVarint, x: 6}},
+		{{num: valueDouble, wire: wireFixed64, x: math.Float64bits(1.5)}},
+	}
+	if !reflect.DeepEqual(values, wantValues) {
+		t.Errorf("values = %+v, want %+v", values, wantValues)
+	}
+}
+
+func TestEncodeValue(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		want value
+	}{
+		{"x", value{field: valueString, s: "x"}},
+		{true, value{field: valueBool, x: 1}},
+		{false, value{field: valueBool}},
+		{float32(0.5), value{field: valueFloat, x: uint64(math.Float32bits(0.5))}},
+		{-2.5, value{field: valueDouble, x: math.Float64bits(-2.5)}},
+		{-3, value{field: valueSint, x: 5}},
+		{int64(4), value{field: valueSint, x: 8}},
+		{uint8(7), value{field: valueUint, x: 7}},
+		{uint64(1 << 40), value{field: valueUint, x: 1 << 40}},
+	}
+	for _, test := range tests {
+		got, err := encodeValue(test.v)
+		if err != nil {
+			t.Errorf("encodeValue(%v) failed: %v", test.v, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("encodeValue(%v) = %+v, want %+v", test.v, got, test.want)
+		}
+	}
+}
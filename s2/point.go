@@ -221,7 +221,7 @@ func (p Point) encode(e *encoder) {
 
 // Decode decodes the Point.
 func (p *Point) Decode(r io.Reader) error {
-	d := &decoder{r: asByteReader(r)}
+	d := newDecoder(r, DecodeOptions{})
 	p.decode(d)
 	return d.err
 }
@@ -232,12 +232,14 @@ func (p *Point) decode(d *decoder) {
 		return
 	}
 	if version != encodingVersion {
-		d.err = fmt.Errorf("only version %d is supported", encodingVersion)
+		d.err = fmt.Errorf("%w: only version %d is supported", ErrDecodeVersion, encodingVersion)
 		return
 	}
-	p.X = d.readFloat64()
-	p.Y = d.readFloat64()
-	p.Z = d.readFloat64()
+	points := d.readPoints(1)
+	if !d.checkPoints(points) {
+		return
+	}
+	*p = points[0]
 }
 
 // Rotate the given point about the given axis by the given angle. p and
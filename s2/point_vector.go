@@ -14,6 +14,10 @@
 
 package s2
 
+import (
+	"io"
+)
+
 // Shape interface enforcement
 var (
 	_ Shape = (*PointVector)(nil)
@@ -40,3 +44,29 @@ func (p *PointVector) IsEmpty() bool                     { return defaultShapeIs
 func (p *PointVector) IsFull() bool                      { return defaultShapeIsFull(p) }
 func (p *PointVector) typeTag() typeTag                  { return typeTagPointVector }
 func (p *PointVector) privateInterface()                 {}
+
+// Encode encodes the PointVector in the format read by EncodedPointVector.
+// Points that are the centers of cells at a common level, such as points
+// snapped to a grid, are encoded compactly, and the other points are stored
+// exactly.
+func (p *PointVector) Encode(w io.Writer) error {
+	e := &encoder{w: w}
+	encodePointVector(e, *p)
+	return e.err
+}
+
+// Decode decodes a PointVector written by Encode.
+func (p *PointVector) Decode(r io.Reader) error {
+	d := newDecoder(r, DecodeOptions{})
+	p.decode(d)
+	return d.err
+}
+
+func (p *PointVector) decode(d *decoder) {
+	var v EncodedPointVector
+	v.decode(d)
+	if d.err != nil {
+		return
+	}
+	*p = v.Points()
+}
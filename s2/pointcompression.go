@@ -15,7 +15,6 @@
 package s2
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/rubenpoppe/geo/r3"
@@ -150,7 +149,7 @@ func decodeFaceRun(d *decoder) faceRun {
 		count: int(faceAndCount / numFaces),
 	}
 	if ret.count <= 0 && d.err == nil {
-		d.err = errors.New("non-positive count for face run")
+		d.err = fmt.Errorf("%w: non-positive count for face run", ErrDecodeInvalid)
 	}
 	return ret
 }
@@ -206,47 +205,55 @@ func (fi *facesIterator) next() (ok bool) {
 	return true
 }
 
-func decodePointsCompressed(d *decoder, level int, target []Point) {
-	faces := decodeFaces(len(target), d)
+// decodePointsCompressed decodes n points encoded by encodePointsCompressed.
+func decodePointsCompressed(d *decoder, level, n int) []Point {
+	faces := decodeFaces(n, d)
 
 	piCoder := newNthDerivativeCoder(derivativeEncodingOrder)
 	qiCoder := newNthDerivativeCoder(derivativeEncodingOrder)
 
+	// The points are appended as they are read, so that a corrupt count
+	// cannot allocate more memory than the input holds.
+	target := make([]Point, 0, decodeCapacity(uint64(n)))
 	iter := facesIterator{faces: faces}
-	for i := range target {
+	for i := 0; i < n && d.err == nil; i++ {
 		decodeFn := decodePointCompressed
 		if i == 0 {
 			decodeFn = decodeFirstPointFixedLength
 		}
 		pi, qi := decodeFn(d, level, piCoder, qiCoder)
 		if ok := iter.next(); !ok && d.err == nil {
-			d.err = fmt.Errorf("ran out of faces at target %d", i)
-			return
+			d.err = fmt.Errorf("%w: ran out of faces at target %d", ErrDecodeInvalid, i)
+			return nil
 		}
-		target[i] = Point{facePiQitoXYZ(iter.curFace, pi, qi, level)}
+		target = append(target, Point{facePiQitoXYZ(iter.curFace, pi, qi, level)})
 	}
 
-	numOffCenter := int(d.readUvarint())
+	numOffCenter := d.readUvarint()
 	if d.err != nil {
-		return
+		return nil
 	}
-	if numOffCenter > len(target) {
-		d.err = fmt.Errorf("numOffCenter = %d, should be at most len(target) = %d", numOffCenter, len(target))
-		return
+	if numOffCenter > uint64(len(target)) {
+		d.err = fmt.Errorf("%w: numOffCenter = %d, should be at most len(target) = %d", ErrDecodeInvalid, numOffCenter, len(target))
+		return nil
 	}
-	for i := 0; i < numOffCenter; i++ {
-		idx := int(d.readUvarint())
+	for i := uint64(0); i < numOffCenter; i++ {
+		idx := d.readUvarint()
 		if d.err != nil {
-			return
+			return nil
 		}
-		if idx >= len(target) {
-			d.err = fmt.Errorf("off center index = %d, should be < len(target) = %d", idx, len(target))
-			return
+		if idx >= uint64(len(target)) {
+			d.err = fmt.Errorf("%w: off center index = %d, should be < len(target) = %d", ErrDecodeInvalid, idx, len(target))
+			return nil
 		}
 		target[idx].X = d.readFloat64()
 		target[idx].Y = d.readFloat64()
 		target[idx].Z = d.readFloat64()
 	}
+	if d.err != nil {
+		return nil
+	}
+	return target
 }
 
 func decodeFirstPointFixedLength(d *decoder, level int, piCoder, qiCoder *nthDerivativeCoder) (pi, qi uint32) {
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"io"
 	"math"
+
+	"github.com/rubenpoppe/geo/s1"
 )
 
 // Polygon represents a sequence of zero or more loops; recall that the
@@ -276,6 +278,122 @@ func PolygonFromCell(cell Cell) *Polygon {
 	return PolygonFromLoops([]*Loop{LoopFromCell(cell)})
 }
 
+// PolygonFromCellUnionBorder returns a Polygon whose boundary is the border of
+// the given CellUnion. Adjacent cells are merged, so the loops of the polygon
+// follow only the edges between cells of the union and cells outside it, and
+// any cells missing from the interior of the union become holes. Loops that
+// touch only at a cell corner are kept as separate loops sharing that vertex.
+//
+// Every vertex of the result is a cell corner computed exactly as in
+// LoopFromCell, so a large cell next to several smaller ones has its edge
+// split at the corners of the smaller cells. The empty union gives the empty
+// polygon, and a union of all six faces gives the full polygon.
+func PolygonFromCellUnionBorder(cu CellUnion) *Polygon {
+	cells := make(CellUnion, len(cu))
+	copy(cells, cu)
+	cells.Normalize()
+
+	// Collect the directed border edges, each with the union on its left.
+	edges := make(map[Point][]Point)
+	var starts []Point
+	for _, id := range cells {
+		cell := CellFromCellID(id)
+		for k := 0; k < 4; k++ {
+			addCellUnionBorderEdges(&cells, cell, k, edges, &starts)
+		}
+	}
+
+	var loops []*Loop
+	for _, start := range starts {
+		loops = append(loops, cellUnionBorderLoops(start, edges)...)
+	}
+
+	if len(loops) == 0 {
+		if len(cells) == 0 {
+			return PolygonFromLoops(nil)
+		}
+		// With no border edges, the union covers all six faces.
+		return FullPolygon()
+	}
+	return PolygonFromOrientedLoops(loops)
+}
+
+// addCellUnionBorderEdges adds to edges the parts of edge k of the given cell
+// that separate it from cells outside the union. When the union covers only
+// part of the neighboring cell across this edge, the edge is split between
+// the two children of the cell that lie along it.
+func addCellUnionBorderEdges(cells *CellUnion, cell Cell, k int, edges map[Point][]Point, starts *[]Point) {
+	neighbor := cell.id.EdgeNeighbors()[k]
+	if cells.ContainsCellID(neighbor) {
+		return
+	}
+	if !cells.IntersectsCellID(neighbor) {
+		a, b := cell.Vertex(k), cell.Vertex((k+1)%4)
+		edges[a] = append(edges[a], b)
+		*starts = append(*starts, a)
+		return
+	}
+	for _, child := range cell.id.Children() {
+		c := CellFromCellID(child)
+		var alongEdge bool
+		switch k {
+		case 0:
+			alongEdge = c.uv.Y.Lo == cell.uv.Y.Lo
+		case 1:
+			alongEdge = c.uv.X.Hi == cell.uv.X.Hi
+		case 2:
+			alongEdge = c.uv.Y.Hi == cell.uv.Y.Hi
+		case 3:
+			alongEdge = c.uv.X.Lo == cell.uv.X.Lo
+		}
+		if alongEdge {
+			addCellUnionBorderEdges(cells, c, k, edges, starts)
+		}
+	}
+}
+
+// cellUnionBorderLoops removes from edges every edge reachable from the
+// given vertex, and returns the loops they form. Where two cells of the union
+// touch only at a corner, the walk can come back to a vertex it has already
+// visited, and the part of the walk since then is split off as a separate
+// loop so that no loop repeats a vertex.
+func cellUnionBorderLoops(start Point, edges map[Point][]Point) []*Loop {
+	if len(edges[start]) == 0 {
+		return nil
+	}
+
+	var loops []*Loop
+	path := []Point{start}
+	pathIndex := map[Point]int{start: 0}
+	for {
+		v := path[len(path)-1]
+		out := edges[v]
+		next := out[len(out)-1]
+		if out = out[:len(out)-1]; len(out) == 0 {
+			delete(edges, v)
+		} else {
+			edges[v] = out
+		}
+
+		i, ok := pathIndex[next]
+		if !ok {
+			pathIndex[next] = len(path)
+			path = append(path, next)
+			continue
+		}
+		loops = append(loops, LoopFromPoints(append([]Point(nil), path[i:]...)))
+		for _, u := range path[i+1:] {
+			delete(pathIndex, u)
+		}
+		path = path[:i+1]
+		// Edges into and out of each vertex are balanced, so only the start
+		// vertex can run out of edges once a loop is closed.
+		if i == 0 && len(edges[start]) == 0 {
+			return loops
+		}
+	}
+}
+
 // initNested takes the set of loops in this polygon and performs the nesting
 // computations to set the proper nesting and parent/child relationships.
 func (p *Polygon) initNested() {
@@ -409,6 +527,8 @@ func (p *Polygon) initEdgesAndIndex() {
 	p.numEdges = 0
 	p.cumulativeEdges = nil
 	if p.IsFull() {
+		p.index = NewShapeIndex()
+		p.index.Add(p)
 		return
 	}
 	const maxLinearSearchLoops = 12 // Based on benchmarks.
@@ -554,7 +674,7 @@ func (p *Polygon) Parent(k int) (index int, ok bool) {
 	// we don't know how many may be next to us before we get back to our parent loop.)
 	// Move up one position from us, and then begin traversing back through the set of loops
 	// until we find the one that is our parent or we get to the top of the polygon.
-	for k--; k >= 0 && p.loops[k].depth <= depth; k-- {
+	for k--; k >= 0 && p.loops[k].depth >= depth; k-- {
 	}
 	return k, true
 }
@@ -1014,6 +1134,195 @@ func (p *Polygon) Area() float64 {
 	return area
 }
 
+// Centroid returns the true centroid of the polygon multiplied by the area of
+// the polygon. The result is not unit length, so you may want to normalize it.
+// Also note that in general, the centroid may not be contained by the polygon.
+//
+// We prescale by the polygon area for two reasons: (1) it is cheaper to
+// compute this way, and (2) it makes it easier to compute the centroid of
+// more complicated shapes (by splitting them into disjoint regions and
+// adding their centroids).
+func (p *Polygon) Centroid() Point {
+	var centroid Point
+	for _, loop := range p.loops {
+		centroid = Point{centroid.Add(loop.Centroid().Mul(float64(loop.Sign())))}
+	}
+	return centroid
+}
+
+// SnapLevel returns the level at which all of the vertices of the polygon
+// are centers of cells, or -1 if there is no such level. Polygons with a snap
+// level can be encoded in about 4 bytes per vertex instead of 24.
+//
+// The empty polygon has a snap level of -1.
+func (p *Polygon) SnapLevel() int {
+	snapLevel := -1
+	for _, l := range p.loops {
+		for _, v := range l.vertices {
+			_, _, _, level := xyzToFaceSiTi(v)
+			if level < 0 || (snapLevel >= 0 && level != snapLevel) {
+				return -1
+			}
+			snapLevel = level
+		}
+	}
+	return snapLevel
+}
+
+// IsNormalized reports whether the polygon is in its normalized form, i.e.
+// whether each group of child loops that are connected to each other through
+// shared vertices shares at most one vertex with their parent loop. For
+// example, if loop A has children B, C and D, and the pairs AB, BC, CD and DA
+// share vertices, the polygon is not normalized even though each child shares
+// only one vertex with A. Every polygon has a unique normalized form.
+func (p *Polygon) IsNormalized() bool {
+	for i, parent := range p.loops {
+		var children []int
+		for k := i + 1; k <= p.LastDescendant(i); k++ {
+			if p.loops[k].depth == parent.depth+1 {
+				children = append(children, k)
+			}
+		}
+		if len(children) == 0 {
+			continue
+		}
+
+		// Group the children into connected components, using a union-find
+		// structure indexed by position in children.
+		component := make([]int, len(children))
+		var find func(c int) int
+		find = func(c int) int {
+			if component[c] != c {
+				component[c] = find(component[c])
+			}
+			return component[c]
+		}
+		owner := make(map[Point]int)
+		for c, k := range children {
+			component[c] = c
+			for _, v := range p.loops[k].vertices {
+				if o, ok := owner[v]; ok {
+					component[find(o)] = find(c)
+				} else {
+					owner[v] = c
+				}
+			}
+		}
+
+		// Each component may share at most one vertex with the parent.
+		shared := make(map[int]Point)
+		for _, v := range parent.vertices {
+			c, ok := owner[v]
+			if !ok {
+				continue
+			}
+			root := find(c)
+			if w, ok := shared[root]; ok && w != v {
+				return false
+			}
+			shared[root] = v
+		}
+	}
+	return true
+}
+
+// Equal reports whether the two polygons have the same loops in the same
+// order, with each pair of loops having the same vertices in the same linear
+// order and the same nesting depth.
+func (p *Polygon) Equal(o *Polygon) bool {
+	if len(p.loops) != len(o.loops) {
+		return false
+	}
+	for i, l := range p.loops {
+		if l.depth != o.loops[i].depth || !l.Equal(o.loops[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// BoundaryEqual reports whether the two polygons have the same boundary. This
+// is true if every loop of each polygon has a loop of the other polygon at the
+// same nesting depth with the same boundary (see Loop.BoundaryEqual). The
+// order of the loops and their starting vertices do not matter.
+func (p *Polygon) BoundaryEqual(o *Polygon) bool {
+	return p.matchLoops(o, (*Loop).BoundaryEqual)
+}
+
+// BoundaryApproxEqual reports whether the two polygons have the same boundary
+// except for vertex perturbations. Both polygons must have loops with the same
+// cyclic vertex order and the same nesting depth, but the vertex locations are
+// allowed to differ by up to maxError. The order of the loops and their
+// starting vertices do not matter.
+func (p *Polygon) BoundaryApproxEqual(o *Polygon, maxError s1.Angle) bool {
+	return p.matchLoops(o, func(a, b *Loop) bool {
+		return a.BoundaryApproxEqual(b, maxError)
+	})
+}
+
+// BoundaryNear reports whether the two polygons have boundaries within
+// maxError of each other along their entire lengths. More precisely, there
+// must be a bijective mapping between the loops of the two polygons such
+// that corresponding loop pairs have the same nesting depth and satisfy
+// Loop.BoundaryNear. The loops may have different numbers of vertices.
+func (p *Polygon) BoundaryNear(o *Polygon, maxError s1.Angle) bool {
+	return p.matchLoops(o, func(a, b *Loop) bool {
+		return a.BoundaryNear(b, maxError)
+	})
+}
+
+// matchLoops reports whether there is a bijective mapping between the loops of
+// the polygons such that each loop of p is mapped to a loop of o at the same
+// depth for which match is true. The mapping is found as a perfect matching in
+// the bipartite graph of the loop pairs that match, so that a loop of o that
+// matches several loops of p is not used more than once.
+func (p *Polygon) matchLoops(o *Polygon, match func(a, b *Loop) bool) bool {
+	if len(p.loops) != len(o.loops) {
+		return false
+	}
+	candidates := make([][]int, len(p.loops))
+	for i, a := range p.loops {
+		for j, b := range o.loops {
+			if a.depth == b.depth && match(b, a) {
+				candidates[i] = append(candidates[i], j)
+			}
+		}
+		if len(candidates[i]) == 0 {
+			return false
+		}
+	}
+
+	// matchedTo[j] is the loop of p that loop j of o is mapped to, or -1.
+	matchedTo := make([]int, len(o.loops))
+	for j := range matchedTo {
+		matchedTo[j] = -1
+	}
+	// augment looks for an augmenting path from loop i of p, which remaps
+	// already mapped loops of p along the way if necessary.
+	var visited []bool
+	var augment func(i int) bool
+	augment = func(i int) bool {
+		for _, j := range candidates[i] {
+			if visited[j] {
+				continue
+			}
+			visited[j] = true
+			if matchedTo[j] < 0 || augment(matchedTo[j]) {
+				matchedTo[j] = i
+				return true
+			}
+		}
+		return false
+	}
+	for i := range p.loops {
+		visited = make([]bool, len(o.loops))
+		if !augment(i) {
+			return false
+		}
+	}
+	return true
+}
+
 // Encode encodes the Polygon
 func (p *Polygon) Encode(w io.Writer) error {
 	e := &encoder{w: w}
@@ -1113,26 +1422,38 @@ func (p *Polygon) encodeCompressed(e *encoder, snapLevel int, vertices []xyzFace
 
 // Decode decodes the Polygon.
 func (p *Polygon) Decode(r io.Reader) error {
-	d := &decoder{r: asByteReader(r)}
+	d := newDecoder(r, DecodeOptions{})
+	p.decode(d)
+	return d.err
+}
+
+func (p *Polygon) decode(d *decoder) {
 	version := int8(d.readUint8())
-	var dec func(*decoder)
+	if d.err != nil {
+		return
+	}
+	var result Polygon
 	switch version {
 	case encodingVersion:
-		dec = p.decode
+		result.decodeLossless(d)
 	case encodingCompressedVersion:
-		dec = p.decodeCompressed
+		result.decodeCompressed(d)
 	default:
-		return fmt.Errorf("unsupported version %d", version)
+		d.err = fmt.Errorf("%w: unsupported version %d", ErrDecodeVersion, version)
 	}
-	dec(d)
-	return d.err
+	if d.err != nil {
+		return
+	}
+	*p = result
+	// The index refers to the polygon, which has moved.
+	p.initEdgesAndIndex()
 }
 
 // maxEncodedLoops is the biggest supported number of loops in a polygon during encoding.
 // Setting a maximum guards an allocation: it prevents an attacker from easily pushing us OOM.
 const maxEncodedLoops = 10000000
 
-func (p *Polygon) decode(d *decoder) {
+func (p *Polygon) decodeLossless(d *decoder) {
 	*p = Polygon{}
 	d.readUint8() // Ignore irrelevant serialized owns_loops_ value.
 
@@ -1141,18 +1462,15 @@ func (p *Polygon) decode(d *decoder) {
 	// Polygons with no loops are explicitly allowed here: a newly created
 	// polygon has zero loops and such polygons encode and decode properly.
 	nloops := d.readUint32()
-	if d.err != nil {
+	if !d.checkCount(uint64(nloops), maxEncodedLoops, "loops") {
 		return
 	}
-	if nloops > maxEncodedLoops {
-		d.err = fmt.Errorf("too many loops (%d; max is %d)", nloops, maxEncodedLoops)
-		return
-	}
-	p.loops = make([]*Loop, nloops)
-	for i := range p.loops {
-		p.loops[i] = new(Loop)
-		p.loops[i].decode(d)
-		p.numVertices += len(p.loops[i].vertices)
+	p.loops = make([]*Loop, 0, decodeCapacity(uint64(nloops)))
+	for i := uint32(0); i < nloops && d.err == nil; i++ {
+		l := new(Loop)
+		l.decode(d)
+		p.loops = append(p.loops, l)
+		p.numVertices += len(l.vertices)
 	}
 
 	p.bound.decode(d)
@@ -1167,26 +1485,28 @@ func (p *Polygon) decodeCompressed(d *decoder) {
 	snapLevel := int(d.readUint8())
 
 	if snapLevel > maxLevel {
-		d.err = fmt.Errorf("snaplevel too big: %d", snapLevel)
+		d.err = fmt.Errorf("%w: snaplevel too big: %d", ErrDecodeInvalid, snapLevel)
 		return
 	}
 	// Polygons with no loops are explicitly allowed here: a newly created
 	// polygon has zero loops and such polygons encode and decode properly.
-	nloops := int(d.readUvarint())
-	if nloops > maxEncodedLoops {
-		d.err = fmt.Errorf("too many loops (%d; max is %d)", nloops, maxEncodedLoops)
+	nloops := d.readUvarint()
+	if !d.checkCount(nloops, maxEncodedLoops, "loops") {
+		return
 	}
-	p.loops = make([]*Loop, nloops)
-	for i := range p.loops {
-		p.loops[i] = new(Loop)
-		p.loops[i].decodeCompressed(d, snapLevel)
+	p.loops = make([]*Loop, 0, decodeCapacity(nloops))
+	for i := uint64(0); i < nloops && d.err == nil; i++ {
+		l := new(Loop)
+		l.decodeCompressed(d, snapLevel)
+		p.loops = append(p.loops, l)
+	}
+	if d.err != nil {
+		return
 	}
 	p.initLoopProperties()
 }
 
 // TODO(roberts): Differences from C++
-// Centroid
-// SnapLevel
 // DistanceToPoint
 // DistanceToBoundary
 // Project
@@ -1201,9 +1521,6 @@ func (p *Polygon) decodeCompressed(d *decoder) {
 // ApproxSubtractFromPolyline
 // DestructiveUnion
 // DestructiveApproxUnion
-// InitToCellUnionBorder
-// IsNormalized
-// Equal/BoundaryEqual/BoundaryApproxEqual/BoundaryNear Polygons
 // BreakEdgesAndAddToBuilder
 //
 // clearLoops
@@ -0,0 +1,326 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"sort"
+
+	"github.com/rubenpoppe/geo/r3"
+)
+
+// RepairRule selects which points RepairPolygon considers to be enclosed by
+// a set of rings.
+type RepairRule int
+
+// These are the rules supported by RepairPolygon.
+const (
+	// RepairEvenOdd keeps the points enclosed by an odd number of rings. The
+	// orientation of the rings does not matter, so a ring inside another ring
+	// is always a hole.
+	RepairEvenOdd RepairRule = iota
+	// RepairWinding keeps the points that the rings wind around a positive
+	// number of times, once they are oriented to be mostly counter-clockwise.
+	// A ring nested directly inside a ring with the same orientation is
+	// reversed, so that it is a hole rather than being filled in.
+	// Overlapping shells are merged, and overlapping holes are all removed
+	// from the shells.
+	RepairWinding
+)
+
+// PolygonRepairReport describes the problems that RepairPolygon found in its
+// input and fixed.
+type PolygonRepairReport struct {
+	// DuplicateVertices is the number of vertices removed because they were
+	// equal to the previous vertex of their ring.
+	DuplicateVertices int
+	// DegenerateEdges is the number of edges removed because they doubled
+	// back along the previous edge or joined two antipodal vertices.
+	DegenerateEdges int
+	// DegenerateRings is the number of rings dropped because they had fewer
+	// than three vertices left after removing degeneracies.
+	DegenerateRings int
+	// ReversedRings is the number of rings reversed by RepairWinding because
+	// the rings were mostly clockwise. All the rings are reversed together,
+	// so that holes stay opposite to their shells. RepairEvenOdd ignores the
+	// orientation of the rings and never reverses them.
+	ReversedRings int
+	// ReversedHoles is the number of rings reversed by RepairWinding because
+	// they were nested directly inside a ring with the same orientation.
+	ReversedHoles int
+	// SelfIntersections is the number of pairs of edges of the same ring
+	// that cross each other.
+	SelfIntersections int
+	// RingIntersections is the number of pairs of edges of different rings
+	// that cross each other.
+	RingIntersections int
+}
+
+// Repaired reports whether any problems were fixed.
+func (r PolygonRepairReport) Repaired() bool {
+	return r != PolygonRepairReport{}
+}
+
+// RepairPolygon builds a valid Polygon from rings of vertices that may not
+// form a valid polygon, such as data read from external sources. The rings
+// may contain duplicate vertices and degenerate edges, have any orientation,
+// and cross themselves or each other. The points that the result contains are
+// chosen by the given rule, and the report lists the fixes that were needed.
+//
+// A closing vertex equal to the first vertex of its ring, as used by formats
+// such as GeoJSON and WKT, is removed without being reported.
+//
+// Each ring is taken to enclose the smaller of the two regions it bounds, so
+// a polygon with a ring that should enclose more than a hemisphere cannot be
+// repaired. The vertices of the result are snapped to the centers of leaf
+// cells, as described for WindingOperation.
+func RepairPolygon(rings [][]Point, rule RepairRule) (*Polygon, PolygonRepairReport, error) {
+	var report PolygonRepairReport
+	var cleaned [][]Point
+	for _, ring := range rings {
+		if len(ring) == 0 {
+			continue
+		}
+		if vertices := report.removeDegeneracies(ring); len(vertices) >= 3 {
+			cleaned = append(cleaned, vertices)
+		} else {
+			report.DegenerateRings++
+		}
+	}
+	if len(cleaned) == 0 {
+		return PolygonFromLoops(nil), report, nil
+	}
+	crossing := report.countIntersections(cleaned)
+	// Rings that cross themselves have no well defined interior.
+	interiors := make([]*Loop, len(cleaned))
+	for i, ring := range cleaned {
+		if !crossing[[2]int{i, i}] {
+			interiors[i] = ringInterior(ring)
+		}
+	}
+
+	windingRule := WindingOdd
+	if rule == RepairWinding {
+		windingRule = WindingPositive
+		report.orientRings(cleaned)
+		report.orientHoles(cleaned, interiors, crossing)
+	}
+
+	// Any point not on a ring will do as the reference point, since its
+	// winding number is computed from the interiors of the rings. The point
+	// antipodal to the centroid of the vertices is usually outside them all,
+	// which matters for rings that cross themselves: the reference point is
+	// taken to be outside them.
+	op := NewWindingOperation()
+	var sum r3.Vector
+	for _, ring := range cleaned {
+		op.loops = append(op.loops, ring)
+		for _, v := range ring {
+			sum = sum.Add(v.Vector)
+		}
+	}
+	ref := OriginPoint()
+	if sum.Norm2() > 0 {
+		ref = Point{sum.Mul(-1).Normalize()}
+	}
+	refWinding := 0
+	for i, ring := range cleaned {
+		if interiors[i] != nil && interiors[i].ContainsPoint(ref) {
+			if loopSignedArea(ring) < 0 {
+				refWinding--
+			} else {
+				refWinding++
+			}
+		}
+	}
+
+	p, err := op.Build(ref, refWinding, windingRule)
+	if err != nil {
+		return nil, report, err
+	}
+	return p, report, nil
+}
+
+// removeDegeneracies returns a copy of the ring without its closing vertex,
+// duplicate vertices, edges that double back on themselves, and edges
+// between antipodal vertices, counting what it removes.
+func (r *PolygonRepairReport) removeDegeneracies(ring []Point) []Point {
+	if n := len(ring); n > 1 && ring[0] == ring[n-1] {
+		ring = ring[:n-1]
+	}
+
+	var vertices []Point
+	for _, v := range ring {
+		n := len(vertices)
+		switch {
+		case n > 0 && v == vertices[n-1]:
+			r.DuplicateVertices++
+		case n > 0 && v.Vector == vertices[n-1].Mul(-1):
+			r.DegenerateEdges++
+		case n > 1 && v == vertices[n-2]:
+			// The edge to the last vertex comes straight back to v.
+			vertices = vertices[:n-1]
+			r.DegenerateEdges += 2
+		default:
+			vertices = append(vertices, v)
+		}
+	}
+
+	// Apply the same rules to the vertices where the ring wraps around.
+	for len(vertices) >= 3 {
+		n := len(vertices)
+		switch {
+		case vertices[n-1] == vertices[0]:
+			vertices = vertices[:n-1]
+			r.DuplicateVertices++
+		case vertices[n-1].Vector == vertices[0].Mul(-1):
+			vertices = vertices[:n-1]
+			r.DegenerateEdges++
+		case vertices[n-2] == vertices[0]:
+			vertices = vertices[:n-2]
+			r.DegenerateEdges += 2
+		case vertices[n-1] == vertices[1]:
+			vertices = vertices[2:]
+			r.DegenerateEdges += 2
+		default:
+			return vertices
+		}
+	}
+	return vertices
+}
+
+// orientRings reverses all the rings if they are mostly clockwise.
+func (r *PolygonRepairReport) orientRings(rings [][]Point) {
+	var area float64
+	for _, ring := range rings {
+		area += loopSignedArea(ring)
+	}
+	if area < 0 {
+		for _, ring := range rings {
+			reverseRing(ring)
+		}
+		r.ReversedRings += len(rings)
+	}
+}
+
+// orientHoles reverses the rings nested directly inside a ring with the same
+// orientation, working inwards from the outermost rings. A ring is nested
+// inside another if it is inside its interior and neither of them crosses
+// itself or the other.
+func (r *PolygonRepairReport) orientHoles(rings [][]Point, interiors []*Loop, crossing map[[2]int]bool) {
+	parents := make([][]int, len(rings))
+	for i := range rings {
+		if interiors[i] == nil {
+			continue
+		}
+		for j := range rings {
+			if j != i && interiors[j] != nil && !crossing[[2]int{minInt(i, j), maxInt(i, j)}] &&
+				ringInside(rings[i], rings[j], interiors[j]) {
+				parents[i] = append(parents[i], j)
+			}
+		}
+	}
+
+	// The depth of a ring is the number of rings around it, and its parent
+	// is the deepest of them.
+	order := make([]int, len(rings))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return len(parents[order[a]]) < len(parents[order[b]])
+	})
+	ccw := make([]bool, len(rings))
+	for i, ring := range rings {
+		ccw[i] = loopSignedArea(ring) >= 0
+	}
+	for _, i := range order {
+		if len(parents[i]) == 0 {
+			continue
+		}
+		parent := parents[i][0]
+		for _, j := range parents[i][1:] {
+			if len(parents[j]) > len(parents[parent]) {
+				parent = j
+			}
+		}
+		if ccw[i] == ccw[parent] {
+			reverseRing(rings[i])
+			ccw[i] = !ccw[i]
+			r.ReversedHoles++
+		}
+	}
+}
+
+// ringInterior returns a loop for the smaller of the two regions bounded by
+// the ring.
+func ringInterior(ring []Point) *Loop {
+	vertices := append([]Point(nil), ring...)
+	if loopSignedArea(vertices) < 0 {
+		reverseRing(vertices)
+	}
+	return LoopFromPoints(vertices)
+}
+
+// ringInside reports whether ring a is inside the interior of ring b, given
+// that they do not cross. It tests the first vertex of a that is not also a
+// vertex of b, so a ring with the same vertices as b is not inside it.
+func ringInside(a, b []Point, interior *Loop) bool {
+	shared := make(map[Point]bool, len(b))
+	for _, v := range b {
+		shared[v] = true
+	}
+	for _, v := range a {
+		if !shared[v] {
+			return interior.ContainsPoint(v)
+		}
+	}
+	return false
+}
+
+// reverseRing reverses the order of the vertices of the ring in place.
+func reverseRing(ring []Point) {
+	for i, j := 0, len(ring)-1; i < j; i, j = i+1, j-1 {
+		ring[i], ring[j] = ring[j], ring[i]
+	}
+}
+
+// countIntersections counts the pairs of edges that cross each other, and
+// returns the pairs of rings {i, j} with i <= j that have crossing edges.
+func (r *PolygonRepairReport) countIntersections(rings [][]Point) map[[2]int]bool {
+	var edges []Edge
+	var edgeRings []int
+	for i, ring := range rings {
+		for j, v := range ring {
+			edges = append(edges, Edge{v, ring[(j+1)%len(ring)]})
+			edgeRings = append(edgeRings, i)
+		}
+	}
+
+	index := NewShapeIndex()
+	shape := edgeListShape(edges)
+	index.Add(&shape)
+	crossing := make(map[[2]int]bool)
+	VisitCrossingEdgePairs(index, CrossingTypeInterior, func(a, b ShapeEdge, _ bool) bool {
+		i, j := edgeRings[a.ID.EdgeID], edgeRings[b.ID.EdgeID]
+		if i == j {
+			r.SelfIntersections++
+		} else {
+			r.RingIntersections++
+		}
+		crossing[[2]int{minInt(i, j), maxInt(i, j)}] = true
+		return true
+	})
+	return crossing
+}
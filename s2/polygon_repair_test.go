@@ -0,0 +1,248 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"testing"
+
+	"github.com/rubenpoppe/geo/s1"
+)
+
+func TestRepairPolygon(t *testing.T) {
+	const (
+		square  = "0:0, 0:4, 4:4, 4:0"
+		squareR = "4:0, 4:4, 0:4, 0:0"
+		hole    = "1:1, 1:3, 3:3, 3:1"
+		holeR   = "3:1, 3:3, 1:3, 1:1"
+		holeR2  = "2:2, 3.5:2, 3.5:3.5, 2:3.5"
+		islandR = "2.4:1.6, 2.4:2.4, 1.6:2.4, 1.6:1.6"
+
+		// Rings on opposite sides of the sphere whose vertex centroid is
+		// inside the first ring.
+		east = "-1:-1, -1:1, 1:1, 1:-1"
+		west = "-1:179, -1:180, -1:-179, 0:-179, 1:-179, 1:180, 1:179, 0:179"
+	)
+	inShell := parsePoint("0.5:0.5")
+	inHole := parsePoint("1.5:1.5")
+	inBothHoles := parsePoint("2.5:2.5")
+	inIsland := parsePoint("2:2")
+	outside := parsePoint("5:5")
+
+	tests := []struct {
+		label string
+		rings []string
+		rule  RepairRule
+		in    []Point
+		out   []Point
+		want  PolygonRepairReport
+	}{
+		{
+			label: "valid shell",
+			rings: []string{square},
+			in:    []Point{inShell, inHole},
+			out:   []Point{outside},
+		},
+		{
+			label: "closing vertex",
+			rings: []string{square + ", 0:0"},
+			in:    []Point{inShell},
+			out:   []Point{outside},
+		},
+		{
+			label: "duplicate vertices",
+			rings: []string{"0:0, 0:0, 0:4, 4:4, 4:4, 4:4, 4:0, 0:0, 0:0"},
+			in:    []Point{inShell},
+			out:   []Point{outside},
+			want:  PolygonRepairReport{DuplicateVertices: 4},
+		},
+		{
+			label: "spikes",
+			rings: []string{"-2:-2, 0:0, 0:4, 6:6, 0:4, 4:4, 4:0, 0:0"},
+			in:    []Point{inShell},
+			out:   []Point{outside, parsePoint("3:4.5"), parsePoint("-1:-1.1")},
+			want:  PolygonRepairReport{DegenerateEdges: 4},
+		},
+		{
+			label: "degenerate rings",
+			rings: []string{square, "5:5, 6:6", "7:7, 7:7, 7:7", "8:8, 9:9, 8:8, 9:9"},
+			in:    []Point{inShell},
+			out:   []Point{outside},
+			want:  PolygonRepairReport{DuplicateVertices: 1, DegenerateEdges: 2, DegenerateRings: 3},
+		},
+		{
+			label: "clockwise shell even-odd",
+			rings: []string{squareR},
+			in:    []Point{inShell},
+			out:   []Point{outside},
+		},
+		{
+			label: "clockwise shell winding",
+			rings: []string{squareR},
+			rule:  RepairWinding,
+			in:    []Point{inShell},
+			out:   []Point{outside},
+			want:  PolygonRepairReport{ReversedRings: 1},
+		},
+		{
+			label: "hole with the shell's orientation even-odd",
+			rings: []string{square, hole},
+			in:    []Point{inShell},
+			out:   []Point{outside, inHole},
+		},
+		{
+			label: "hole with the shell's orientation winding",
+			rings: []string{square, hole},
+			rule:  RepairWinding,
+			in:    []Point{inShell},
+			out:   []Point{outside, inHole},
+			want:  PolygonRepairReport{ReversedHoles: 1},
+		},
+		{
+			label: "island with the hole's orientation winding",
+			rings: []string{square, holeR, islandR},
+			rule:  RepairWinding,
+			in:    []Point{inShell, inIsland},
+			out:   []Point{outside, inHole},
+			want:  PolygonRepairReport{ReversedHoles: 1},
+		},
+		{
+			label: "clockwise shell with clockwise hole winding",
+			rings: []string{squareR, holeR},
+			rule:  RepairWinding,
+			in:    []Point{inShell},
+			out:   []Point{outside, inHole},
+			want:  PolygonRepairReport{ReversedRings: 2, ReversedHoles: 1},
+		},
+		{
+			label: "oriented hole winding",
+			rings: []string{square, holeR},
+			rule:  RepairWinding,
+			in:    []Point{inShell},
+			out:   []Point{outside, inHole},
+		},
+		{
+			label: "clockwise shell with hole winding",
+			rings: []string{squareR, hole},
+			rule:  RepairWinding,
+			in:    []Point{inShell},
+			out:   []Point{outside, inHole},
+			want:  PolygonRepairReport{ReversedRings: 2},
+		},
+		{
+			label: "crossing holes even-odd",
+			rings: []string{square, holeR, holeR2},
+			in:    []Point{inShell, inBothHoles},
+			out:   []Point{outside, inHole},
+			want:  PolygonRepairReport{RingIntersections: 2},
+		},
+		{
+			label: "crossing holes winding",
+			rings: []string{square, holeR, holeR2},
+			rule:  RepairWinding,
+			in:    []Point{inShell},
+			out:   []Point{outside, inHole, inBothHoles},
+			want:  PolygonRepairReport{RingIntersections: 2},
+		},
+		{
+			label: "bowtie even-odd",
+			rings: []string{"0:4, 4:0, 4:4, 0:0"},
+			in:    []Point{parsePoint("1:2"), parsePoint("3:2")},
+			out:   []Point{outside, parsePoint("2:1"), parsePoint("2:3")},
+			want:  PolygonRepairReport{SelfIntersections: 1},
+		},
+		{
+			label: "bowtie winding",
+			rings: []string{"0:4, 4:0, 4:4, 0:0"},
+			rule:  RepairWinding,
+			in:    []Point{parsePoint("1:2")},
+			out:   []Point{outside, parsePoint("3:2"), parsePoint("2:1"), parsePoint("2:3")},
+			want:  PolygonRepairReport{SelfIntersections: 1},
+		},
+		{
+			label: "opposite rings even-odd",
+			rings: []string{east, west},
+			in:    []Point{parsePoint("0:0"), parsePoint("0:180")},
+			out:   []Point{outside, parsePoint("0:90"), parsePoint("0:-90")},
+		},
+		{
+			label: "opposite rings winding",
+			rings: []string{east, west},
+			rule:  RepairWinding,
+			in:    []Point{parsePoint("0:0"), parsePoint("0:180")},
+			out:   []Point{outside, parsePoint("0:90"), parsePoint("0:-90")},
+		},
+	}
+
+	for _, test := range tests {
+		var rings [][]Point
+		for _, s := range test.rings {
+			rings = append(rings, parsePoints(s))
+		}
+		p, report, err := RepairPolygon(rings, test.rule)
+		if err != nil {
+			t.Errorf("%s: RepairPolygon() returned error: %v", test.label, err)
+			continue
+		}
+		if err := p.Validate(); err != nil {
+			t.Errorf("%s: RepairPolygon().Validate() = %v", test.label, err)
+		}
+		if report != test.want {
+			t.Errorf("%s: RepairPolygon() report = %+v, want %+v", test.label, report, test.want)
+		}
+		if got, want := report.Repaired(), test.want != (PolygonRepairReport{}); got != want {
+			t.Errorf("%s: report.Repaired() = %t, want %t", test.label, got, want)
+		}
+		for _, pt := range test.in {
+			if !p.ContainsPoint(pt) {
+				t.Errorf("%s: RepairPolygon().ContainsPoint(%v) = false, want true", test.label, pt)
+			}
+		}
+		for _, pt := range test.out {
+			if p.ContainsPoint(pt) {
+				t.Errorf("%s: RepairPolygon().ContainsPoint(%v) = true, want false", test.label, pt)
+			}
+		}
+	}
+}
+
+func TestRepairPolygonEmpty(t *testing.T) {
+	for _, rings := range [][][]Point{nil, {{}}, {parsePoints("1:1, 2:2")}} {
+		p, _, err := RepairPolygon(rings, RepairEvenOdd)
+		if err != nil {
+			t.Errorf("RepairPolygon(%v) returned error: %v", rings, err)
+			continue
+		}
+		if !p.IsEmpty() {
+			t.Errorf("RepairPolygon(%v) = %v, want empty polygon", rings, p)
+		}
+	}
+}
+
+func TestRepairPolygonMatchesValidInput(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		loop := RegularLoop(randomPoint(), s1.Angle(0.1+randomFloat64())*s1.Degree, 3+randomUniformInt(20))
+		p, report, err := RepairPolygon([][]Point{loop.Vertices()}, RepairWinding)
+		if err != nil {
+			t.Fatalf("RepairPolygon(%v) returned error: %v", loop, err)
+		}
+		if report.Repaired() {
+			t.Errorf("RepairPolygon(%v) report = %+v, want no repairs", loop, report)
+		}
+		// The vertices only move by snapping to leaf cell centers.
+		if !p.BoundaryNear(PolygonFromLoops([]*Loop{loop}), s1.Angle(MaxDiagMetric.Value(maxLevel))) {
+			t.Errorf("RepairPolygon(%v) = %v, want %v", loop, p, loop)
+		}
+	}
+}
@@ -211,6 +211,9 @@ func TestPolygonFull(t *testing.T) {
 	if !shape.ReferencePoint().Contained {
 		t.Errorf("shape.ReferencePoint().Contained = false, want true")
 	}
+	if !shape.ContainsPoint(PointFromCoords(1, 2, 3)) {
+		t.Errorf("shape.ContainsPoint(%v) = false, want true", PointFromCoords(1, 2, 3))
+	}
 }
 
 func TestPolygonInitLoopPropertiesGetsRightBounds(t *testing.T) {
@@ -421,6 +424,20 @@ func TestPolygonParent(t *testing.T) {
 			t.Errorf("%v.Parent(%d) = %d,%v, want %d,%v", test.p, test.have, got, ok, test.want, test.ok)
 		}
 	}
+
+	// A shell with two holes, one of which contains an island. The parent of
+	// the second hole comes before the first hole and its island.
+	p := makePolygon("0:0, 0:10, 10:10, 10:0; 1:1, 1:4, 4:4, 4:1; 2:2, 2:3, 3:3, 3:2; 6:6, 6:9, 9:9, 9:6", true)
+	for k := 0; k < p.NumLoops(); k++ {
+		parent, ok := p.Parent(k)
+		if depth := p.Loop(k).depth; depth == 0 {
+			if ok {
+				t.Errorf("Parent(%d) = %d, want no parent", k, parent)
+			}
+		} else if !ok || p.Loop(parent).depth != depth-1 || !p.Loop(parent).ContainsNested(p.Loop(k)) {
+			t.Errorf("Parent(%d) = %d,%v, want the loop that directly contains it", k, parent, ok)
+		}
+	}
 }
 
 func TestPolygonLastDescendant(t *testing.T) {
@@ -1123,6 +1140,291 @@ func TestPolygonInvert(t *testing.T) {
 	}
 }
 
+func TestPolygonCentroid(t *testing.T) {
+	shell := makeLoop("-2:1, -1:1, 1:1, 2:1, 2:-1, 1:-1, -1:-1, -2:-1")
+	hole := makeLoop("-0.5:0.5, 0.5:0.5, 0.5:-0.5, -0.5:-0.5")
+	tests := []struct {
+		have *Polygon
+		want Point
+	}{
+		{have: emptyPolygon, want: Point{}},
+		{have: fullPolygon, want: Point{}},
+		{have: cross1Polygon, want: shell.Centroid()},
+		{
+			// The hole is subtracted from the shell.
+			have: cross1CenterHolePolygon,
+			want: Point{shell.Centroid().Sub(hole.Centroid().Vector)},
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.have.Centroid(); !got.Vector.ApproxEqual(test.want.Vector) {
+			t.Errorf("%v.Centroid() = %v, want %v", test.have, got, test.want)
+		}
+	}
+}
+
+func TestPolygonSnapLevel(t *testing.T) {
+	snappedLoop := func(level int, lls ...LatLng) *Loop {
+		var vertices []Point
+		for _, ll := range lls {
+			vertices = append(vertices, CellIDFromLatLng(ll).Parent(level).Point())
+		}
+		return LoopFromPoints(vertices)
+	}
+	a := LatLngFromDegrees(0, 0)
+	b := LatLngFromDegrees(0, 1)
+	c := LatLngFromDegrees(1, 0)
+	d := LatLngFromDegrees(3, 3)
+	e := LatLngFromDegrees(3, 4)
+	f := LatLngFromDegrees(4, 3)
+
+	tests := []struct {
+		have *Polygon
+		want int
+	}{
+		{have: emptyPolygon, want: -1},
+		{have: cross1Polygon, want: -1},
+		{have: PolygonFromLoops([]*Loop{snappedLoop(10, a, b, c)}), want: 10},
+		{have: PolygonFromLoops([]*Loop{snappedLoop(10, a, b, c), snappedLoop(10, d, e, f)}), want: 10},
+		{have: PolygonFromLoops([]*Loop{snappedLoop(10, a, b, c), snappedLoop(12, d, e, f)}), want: -1},
+	}
+
+	for _, test := range tests {
+		if got := test.have.SnapLevel(); got != test.want {
+			t.Errorf("%v.SnapLevel() = %d, want %d", test.have, got, test.want)
+		}
+	}
+}
+
+func TestPolygonIsNormalized(t *testing.T) {
+	tests := []struct {
+		have string
+		want bool
+	}{
+		{have: "", want: true},
+		{have: loopCross1 + loopCrossCenterHole, want: true},
+		// A hole that touches its shell at one vertex.
+		{have: "0:0, 0:4, 4:4, 4:0; 0:0, 1:2, 2:1", want: true},
+		// A hole that touches its shell at two vertices.
+		{have: "0:0, 0:4, 4:4, 4:0; 0:0, 4:4, 2:1", want: false},
+		// A chain of holes that touch each other, where only the first hole
+		// touches the shell.
+		{have: "0:0, 0:6, 6:6, 6:0; 0:0, 1:2, 2:2, 2:1; 2:2, 3:4, 4:4, 4:3; 4:4, 5:5, 5.5:5.5, 5:5.5", want: true},
+		// A chain of holes that touches the shell at both ends, so the holes
+		// and the shell enclose a region that should be a separate shell.
+		{have: "0:0, 0:6, 6:6, 6:0; 0:0, 1:2, 2:2, 2:1; 2:2, 3:4, 4:4, 4:3; 4:4, 5:5.5, 6:6, 5.5:5", want: false},
+	}
+
+	for _, test := range tests {
+		p := makePolygon(test.have, true)
+		if got := p.IsNormalized(); got != test.want {
+			t.Errorf("makePolygon(%q).IsNormalized() = %t, want %t", test.have, got, test.want)
+		}
+	}
+}
+
+func TestPolygonEqual(t *testing.T) {
+	const (
+		a = "0:0, 0:4, 4:4, 4:0;"
+		b = "1:1, 1:2, 2:2, 2:1;"
+		c = "10:10, 10:12, 12:12, 12:10;"
+	)
+	tests := []struct {
+		a, b                               string
+		equal, boundaryEqual, approx, near bool
+	}{
+		{a + b, a + b, true, true, true, true},
+		{a + b + c, a + b + c, true, true, true, true},
+		// Different loop orders.
+		{a + b + c, c + a + b, false, true, true, true},
+		// Different starting vertices.
+		{a, "4:0, 0:0, 0:4, 4:4", false, true, true, true},
+		// Vertices that differ slightly.
+		{a, "0:0, 0:4, 4:4, 4:0.0000000000001", false, false, true, true},
+		// An extra vertex along one edge.
+		{a, "0:0, 0:2, 0:4, 4:4, 4:0", false, false, false, true},
+		// A different nesting depth.
+		{a + b, a + c, false, false, false, false},
+		{a, a + c, false, false, false, false},
+		{a, c, false, false, false, false},
+	}
+
+	const maxError = 1e-10
+	for _, test := range tests {
+		p, o := makePolygon(test.a, true), makePolygon(test.b, true)
+		if got := p.Equal(o); got != test.equal {
+			t.Errorf("%q.Equal(%q) = %t, want %t", test.a, test.b, got, test.equal)
+		}
+		if got := p.BoundaryEqual(o); got != test.boundaryEqual {
+			t.Errorf("%q.BoundaryEqual(%q) = %t, want %t", test.a, test.b, got, test.boundaryEqual)
+		}
+		if got := p.BoundaryApproxEqual(o, maxError); got != test.approx {
+			t.Errorf("%q.BoundaryApproxEqual(%q, %v) = %t, want %t", test.a, test.b, maxError, got, test.approx)
+		}
+		if got := p.BoundaryNear(o, maxError); got != test.near {
+			t.Errorf("%q.BoundaryNear(%q, %v) = %t, want %t", test.a, test.b, maxError, got, test.near)
+		}
+		// All of these are symmetric.
+		if got := o.BoundaryNear(p, maxError); got != test.near {
+			t.Errorf("%q.BoundaryNear(%q, %v) = %t, want %t", test.b, test.a, maxError, got, test.near)
+		}
+	}
+}
+
+func TestPolygonBoundaryNearMatchesEachLoopOnce(t *testing.T) {
+	const (
+		// Two squares 0.2 degrees apart, both within 1.5 degrees of each other.
+		a    = "0:0, 0:1, 1:1, 1:0;"
+		b    = "0:1.2, 0:2.2, 1:2.2, 1:1.2;"
+		far  = "40:40, 40:41, 41:41, 41:40;"
+		near = "0:0.1, 0:1.1, 1:1.1, 1:0.1;"
+	)
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		// Both loops of a+b are near a, but a can only be matched once.
+		{a + b, a + far, false},
+		{a + b, b + a, true},
+		// The first loop that matches a must be given up to match b.
+		{a + b, near + b, true},
+		{a + b, b + near, true},
+	}
+	const maxError = 1.5 * s1.Degree
+	for _, test := range tests {
+		p, o := makePolygon(test.a, true), makePolygon(test.b, true)
+		if got := p.BoundaryNear(o, maxError); got != test.want {
+			t.Errorf("%q.BoundaryNear(%q, %v) = %t, want %t", test.a, test.b, maxError, got, test.want)
+		}
+		if got := o.BoundaryNear(p, maxError); got != test.want {
+			t.Errorf("%q.BoundaryNear(%q, %v) = %t, want %t", test.b, test.a, maxError, got, test.want)
+		}
+		if got := p.BoundaryApproxEqual(o, maxError); got != test.want {
+			t.Errorf("%q.BoundaryApproxEqual(%q, %v) = %t, want %t", test.a, test.b, maxError, got, test.want)
+		}
+		if got := o.BoundaryApproxEqual(p, maxError); got != test.want {
+			t.Errorf("%q.BoundaryApproxEqual(%q, %v) = %t, want %t", test.b, test.a, maxError, got, test.want)
+		}
+	}
+}
+
+func TestPolygonFromCellUnionBorder(t *testing.T) {
+	id := CellIDFromLatLng(LatLngFromDegrees(10, 20)).Parent(12)
+	// The cell that touches id only at a corner is the one vertex neighbor
+	// that is neither id nor an edge neighbor.
+	var corner CellID
+	edgeNeighbors := id.EdgeNeighbors()
+	for _, n := range id.VertexNeighbors(12) {
+		if n != id && n != edgeNeighbors[0] && n != edgeNeighbors[1] && n != edgeNeighbors[2] && n != edgeNeighbors[3] {
+			corner = n
+		}
+	}
+	children := id.Children()
+	faces := func(fs ...int) CellUnion {
+		var cu CellUnion
+		for _, f := range fs {
+			cu = append(cu, CellIDFromFace(f))
+		}
+		return cu
+	}
+
+	tests := []struct {
+		label     string
+		cu        CellUnion
+		wantLoops int
+		wantHoles int
+	}{
+		{"empty", nil, 0, 0},
+		{"single cell", CellUnion{id}, 1, 0},
+		{"children", CellUnion(children[:]), 1, 0},
+		{"ring of neighbors", CellUnion(id.AllNeighbors(12)), 2, 1},
+		{"touching corners", CellUnion{id, corner}, 2, 0},
+		{"three faces", faces(0, 1, 2), 1, 0},
+		{"five faces", faces(0, 1, 2, 3, 4), 1, 0},
+		{"all faces", faces(0, 1, 2, 3, 4, 5), 1, 0},
+	}
+
+	for _, test := range tests {
+		p := PolygonFromCellUnionBorder(test.cu)
+		if err := p.Validate(); err != nil {
+			t.Errorf("%s: PolygonFromCellUnionBorder(%v).Validate() = %v", test.label, test.cu, err)
+		}
+		if got := p.NumLoops(); got != test.wantLoops {
+			t.Errorf("%s: PolygonFromCellUnionBorder(%v).NumLoops() = %d, want %d", test.label, test.cu, got, test.wantLoops)
+		}
+		holes := 0
+		for _, l := range p.Loops() {
+			if l.IsHole() {
+				holes++
+			}
+		}
+		if holes != test.wantHoles {
+			t.Errorf("%s: PolygonFromCellUnionBorder(%v) has %d holes, want %d", test.label, test.cu, holes, test.wantHoles)
+		}
+		if got, want := p.Area(), test.cu.ExactArea(); !float64Near(got, want, 1e-13) {
+			t.Errorf("%s: PolygonFromCellUnionBorder(%v).Area() = %v, want %v", test.label, test.cu, got, want)
+		}
+	}
+
+	if p := PolygonFromCellUnionBorder(nil); p.ContainsPoint(id.Point()) {
+		t.Errorf("PolygonFromCellUnionBorder(nil).ContainsPoint(%v) = true, want false", id)
+	}
+	if p := PolygonFromCellUnionBorder(faces(0, 1, 2, 3, 4, 5)); !p.IsFull() {
+		t.Errorf("PolygonFromCellUnionBorder(all faces).IsFull() = false, want true")
+	}
+	if got, want := PolygonFromCellUnionBorder(CellUnion{id}), PolygonFromCell(CellFromCellID(id)); !got.BoundaryEqual(want) {
+		t.Errorf("PolygonFromCellUnionBorder(%v) = %v, want %v", id, got, want)
+	}
+	if got, want := PolygonFromCellUnionBorder(CellUnion(children[:])), PolygonFromCell(CellFromCellID(id)); !got.BoundaryEqual(want) {
+		t.Errorf("PolygonFromCellUnionBorder(%v) = %v, want %v", id.Children(), got, want)
+	}
+}
+
+func TestPolygonFromCellUnionBorderCoverings(t *testing.T) {
+	centers := []Point{
+		// Cube corners and edges, where the covering spans several faces.
+		PointFromCoords(1, 1, 1),
+		PointFromCoords(-1, 1, -1),
+		PointFromCoords(1, 0, 1),
+	}
+	for i := 0; i < 20; i++ {
+		centers = append(centers, randomPoint())
+	}
+
+	for _, center := range centers {
+		c := CapFromCenterAngle(center, s1.Angle(randomFloat64()*0.1+1e-4))
+		rc := &RegionCoverer{MinLevel: 0, MaxLevel: 30, LevelMod: 1, MaxCells: 5 + randomUniformInt(50)}
+		cu := rc.Covering(c)
+		// Drop a few cells to make holes and separate pieces.
+		for j := len(cu) - 1; j >= 0; j -= 3 {
+			if oneIn(2) {
+				cu = append(cu[:j], cu[j+1:]...)
+			}
+		}
+
+		p := PolygonFromCellUnionBorder(cu)
+		if err := p.Validate(); err != nil {
+			t.Errorf("PolygonFromCellUnionBorder(%v).Validate() = %v", cu, err)
+		}
+		// Loop.Area loses some precision on loops with many vertices.
+		if got, want := p.Area(), cu.ExactArea(); !float64Near(got, want, 1e-8) {
+			t.Errorf("PolygonFromCellUnionBorder(%v).Area() = %v, want %v", cu, got, want)
+		}
+		for _, id := range cu {
+			if got := p.ContainsPoint(id.Point()); !got {
+				t.Errorf("PolygonFromCellUnionBorder(%v).ContainsPoint(%v) = false, want true", cu, id)
+			}
+		}
+		for j := 0; j < 20; j++ {
+			pt := samplePointFromCap(c)
+			if got, want := p.ContainsPoint(pt), cu.ContainsPoint(pt); got != want {
+				t.Errorf("PolygonFromCellUnionBorder(%v).ContainsPoint(%v) = %t, want %t", cu, pt, got, want)
+			}
+		}
+	}
+}
+
 // TODO(roberts): Remaining Tests
 // TestInit
 // TestMultipleInit
@@ -1142,7 +1444,6 @@ func TestPolygonInvert(t *testing.T) {
 // TestBug1 - Bug14
 // TestPolylineIntersection
 // TestSplitting
-// TestInitToCellUnionBorder
 // TestUnionWithAmbgiuousCrossings
 // TestInitToSloppySupportsEmptyPolygons
 // TestInitToSnappedDoesNotRotateVertices
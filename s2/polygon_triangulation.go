@@ -0,0 +1,582 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/rubenpoppe/geo/r2"
+	"github.com/rubenpoppe/geo/r3"
+	"github.com/rubenpoppe/geo/s1"
+)
+
+// PolygonTriangulation is a decomposition of a polygonal region into
+// non-overlapping spherical triangles. Triangles are represented as triples
+// of indices into Vertices, and their vertices are in CCW order.
+//
+// Neighboring triangles share their vertices, so the triangulation can be
+// used directly as a mesh (e.g. for rendering). The triangulation may contain
+// vertices that are not vertices of the original polygon, for example when
+// the polygon does not fit within a hemisphere or after calling Refine.
+type PolygonTriangulation struct {
+	Vertices  []Point
+	Triangles [][3]int
+
+	// index maps each vertex to its position in Vertices.
+	index map[Point]int
+}
+
+// TriangulatePolygon returns a triangulation of the given polygon. Holes are
+// respected, i.e. no triangle overlaps a hole.
+//
+// Polygons that fit within a hemisphere are triangulated using only their
+// own vertices. Otherwise the polygon is first split along the boundaries of
+// the six cube faces, which adds vertices where its edges cross them.
+//
+// An error is returned if the polygon cannot be triangulated completely. This
+// happens if its loops cross each other or themselves. It can also happen for
+// a valid polygon in the rare case that one of its edges passes within
+// rounding error of another vertex, since the vertices added on the cube face
+// boundaries are rounded. Edges that lie along the face boundaries, and loops
+// that pass through the corners of the faces, are handled exactly.
+func TriangulatePolygon(p *Polygon) (*PolygonTriangulation, error) {
+	t := &PolygonTriangulation{index: make(map[Point]int)}
+	if err := t.addRegion(polygonChains(p), p.ContainsPoint); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// TriangulateShape returns a triangulation of the region bounded by the
+// given two-dimensional shape, such as a lax polygon. The shape's chains must
+// have the region interior on their left and must not cross each other.
+// An error is returned if the shape is not two-dimensional, or if it cannot
+// be triangulated completely, as described for TriangulatePolygon.
+func TriangulateShape(s Shape) (*PolygonTriangulation, error) {
+	if s.Dimension() != 2 {
+		return nil, fmt.Errorf("cannot triangulate shape of dimension %d", s.Dimension())
+	}
+	t := &PolygonTriangulation{index: make(map[Point]int)}
+	if err := t.addRegion(polygonChains(s), func(p Point) bool { return containsBruteForce(s, p) }); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// polygonChains returns the vertices of each non-empty chain of the shape.
+func polygonChains(s Shape) [][]Point {
+	var rings [][]Point
+	for i := 0; i < s.NumChains(); i++ {
+		chain := s.Chain(i)
+		if chain.Length == 0 {
+			continue
+		}
+		ring := make([]Point, chain.Length)
+		for j := range ring {
+			ring[j] = s.ChainEdge(i, j).V0
+		}
+		rings = append(rings, ring)
+	}
+	return rings
+}
+
+// Refine subdivides triangles until no triangle edge is longer than the
+// given angle. Edges are split at their midpoints, and the midpoints are
+// shared between adjacent triangles so that the result remains a valid mesh.
+// The maximum edge length should be positive.
+func (t *PolygonTriangulation) Refine(maxEdge s1.Angle) {
+	if maxEdge <= 0 {
+		return
+	}
+	for {
+		// Find the midpoint of every edge that is too long. Since adjacent
+		// triangles share their vertex indices, they also share midpoints.
+		midpoints := make(map[[2]int]int)
+		for _, tri := range t.Triangles {
+			for k := 0; k < 3; k++ {
+				i, j := tri[k], tri[(k+1)%3]
+				if i > j {
+					i, j = j, i
+				}
+				if _, ok := midpoints[[2]int{i, j}]; ok {
+					continue
+				}
+				a, b := t.Vertices[i], t.Vertices[j]
+				if a.Distance(b) > maxEdge {
+					midpoints[[2]int{i, j}] = t.vertex(Point{a.Add(b.Vector).Normalize()})
+				}
+			}
+		}
+		if len(midpoints) == 0 {
+			return
+		}
+
+		midpoint := func(i, j int) (int, bool) {
+			if i > j {
+				i, j = j, i
+			}
+			m, ok := midpoints[[2]int{i, j}]
+			return m, ok
+		}
+		triangles := make([][3]int, 0, 2*len(t.Triangles))
+		for _, tri := range t.Triangles {
+			var mids [3]int
+			var split [3]bool
+			numSplit := 0
+			for k := 0; k < 3; k++ {
+				if mids[k], split[k] = midpoint(tri[k], tri[(k+1)%3]); split[k] {
+					numSplit++
+				}
+			}
+			switch numSplit {
+			case 0:
+				triangles = append(triangles, tri)
+			case 1:
+				// Connect the midpoint to the opposite vertex.
+				k := 0
+				for !split[k] {
+					k++
+				}
+				a, b, c := tri[k], tri[(k+1)%3], tri[(k+2)%3]
+				triangles = append(triangles, [3]int{a, mids[k], c}, [3]int{mids[k], b, c})
+			case 2:
+				// Cut off the corner between the two split edges, and divide
+				// the remaining quadrilateral along its shorter diagonal.
+				k := 0
+				for split[k] {
+					k++
+				}
+				// Edge k (from a to b) is not split, so edges (b, c) and
+				// (c, a) are.
+				a, b, c := tri[k], tri[(k+1)%3], tri[(k+2)%3]
+				mbc, mca := mids[(k+1)%3], mids[(k+2)%3]
+				triangles = append(triangles, [3]int{mbc, c, mca})
+				if t.Vertices[a].Distance(t.Vertices[mbc]) <= t.Vertices[b].Distance(t.Vertices[mca]) {
+					triangles = append(triangles, [3]int{a, b, mbc}, [3]int{a, mbc, mca})
+				} else {
+					triangles = append(triangles, [3]int{a, b, mca}, [3]int{b, mbc, mca})
+				}
+			case 3:
+				triangles = append(triangles,
+					[3]int{tri[0], mids[0], mids[2]},
+					[3]int{mids[0], tri[1], mids[1]},
+					[3]int{mids[2], mids[1], tri[2]},
+					[3]int{mids[0], mids[1], mids[2]})
+			}
+		}
+		t.Triangles = triangles
+	}
+}
+
+// vertex returns the index of the given vertex, adding it if necessary.
+func (t *PolygonTriangulation) vertex(p Point) int {
+	if t.index == nil {
+		t.index = make(map[Point]int)
+		for i, v := range t.Vertices {
+			t.index[v] = i
+		}
+	}
+	if i, ok := t.index[p]; ok {
+		return i
+	}
+	t.index[p] = len(t.Vertices)
+	t.Vertices = append(t.Vertices, p)
+	return len(t.Vertices) - 1
+}
+
+// addRegion triangulates the region to the left of the given rings. The
+// contains function reports whether a point is contained by the region.
+func (t *PolygonTriangulation) addRegion(rings [][]Point, contains func(Point) bool) error {
+	// Ear clipping requires the region to be contained by an open
+	// hemisphere. This is true if every vertex is in the hemisphere centered
+	// at the centroid of the vertices and the region does not contain the
+	// center of the opposite hemisphere.
+	var sum Point
+	for _, ring := range rings {
+		for _, v := range ring {
+			sum = Point{sum.Add(v.Vector)}
+		}
+	}
+	if sum.Norm2() > 0 {
+		center := Point{sum.Normalize()}
+		inHemisphere := !contains(Point{center.Mul(-1)})
+		for _, ring := range rings {
+			for _, v := range ring {
+				if center.Dot(v.Vector) <= 0 {
+					inHemisphere = false
+				}
+			}
+		}
+		if inHemisphere {
+			return t.addHemisphereRegion(rings, center)
+		}
+	}
+	if len(rings) == 0 && !contains(PointFromCoords(1, 2, 3)) {
+		// The region is empty.
+		return nil
+	}
+
+	// Otherwise split the region into pieces along the boundaries of the
+	// cube faces, each of which fits within a hemisphere.
+	for face := 0; face < 6; face++ {
+		n, u, v := unitNorm(face), uAxis(face), vAxis(face)
+		c := &hemisphereClipper{rings: rings, contains: contains}
+		for _, axes := range [][2]Point{{u, v}, {v, u}} {
+			for _, s := range []float64{-1, 1} {
+				// The boundary of the hemisphere with normal n + s*a contains
+				// the other axis and the direction n - s*a exactly.
+				a, b := axes[0], axes[1]
+				h := n.Add(a.Mul(s))
+				q := Point{n.Sub(a.Mul(s)).Normalize()}
+				c.clip(r3.PreciseVectorFromVector(h), []Point{b, {b.Mul(-1)}, q, {q.Mul(-1)}})
+			}
+		}
+		if err := t.addHemisphereRegion(c.rings, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// triangulationNode is a vertex of a ring being triangulated, stored in a
+// doubly linked list.
+type triangulationNode struct {
+	p          Point
+	uv         r2.Point
+	prev, next int
+}
+
+// addHemisphereRegion triangulates the region to the left of the given
+// rings, which must be contained by the open hemisphere around center.
+func (t *PolygonTriangulation) addHemisphereRegion(rings [][]Point, center Point) error {
+	// The gnomonic projection centered on the hemisphere maps geodesics to
+	// straight lines. It is used to classify rings as shells or holes, and
+	// to quickly reject points when searching for ears; all other decisions
+	// use exact predicates on the sphere.
+	frame := getFrame(center)
+	project := func(p Point) r2.Point {
+		q := toFrame(frame, p)
+		return r2.Point{X: q.X / q.Z, Y: q.Y / q.Z}
+	}
+
+	b := &triangulationBuilder{t: t}
+	var shells, holes []int
+	areas := make(map[int]float64)
+	for _, ring := range rings {
+		start := -1
+		var area float64
+		for i, p := range ring {
+			n := b.addNode(p, project(p))
+			if start < 0 {
+				start = n
+			} else {
+				b.nodes[n].prev, b.nodes[n-1].next = n-1, n
+			}
+			next := project(ring[(i+1)%len(ring)])
+			area += b.nodes[n].uv.Cross(next)
+		}
+		b.nodes[start].prev = len(b.nodes) - 1
+		b.nodes[len(b.nodes)-1].next = start
+		areas[start] = math.Abs(area)
+		if area > 0 {
+			shells = append(shells, start)
+		} else {
+			holes = append(holes, start)
+		}
+	}
+
+	// Assign each hole to the smallest shell that contains it. Since loops
+	// may share vertices, a hole is considered to be inside a shell if any
+	// of its vertices is inside.
+	shellHoles := make(map[int][]int)
+	for _, h := range holes {
+		best := -1
+		for _, s := range shells {
+			if b.ringContainsAny(s, h) && (best < 0 || areas[s] < areas[best]) {
+				best = s
+			}
+		}
+		if best >= 0 {
+			shellHoles[best] = append(shellHoles[best], h)
+		}
+	}
+	for _, s := range shells {
+		ring, err := b.bridgeHoles(s, shellHoles[s])
+		if err != nil {
+			return err
+		}
+		if err := b.earClip(ring); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// triangulationBuilder triangulates polygons using ear clipping.
+type triangulationBuilder struct {
+	t     *PolygonTriangulation
+	nodes []triangulationNode
+}
+
+func (b *triangulationBuilder) addNode(p Point, uv r2.Point) int {
+	b.nodes = append(b.nodes, triangulationNode{p: p, uv: uv, prev: len(b.nodes), next: len(b.nodes)})
+	return len(b.nodes) - 1
+}
+
+// ringContains reports whether the projected point lies inside the ring
+// starting at the given node, using the even-odd rule.
+func (b *triangulationBuilder) ringContains(start int, p r2.Point) bool {
+	inside := false
+	n := start
+	for {
+		a, c := b.nodes[n].uv, b.nodes[b.nodes[n].next].uv
+		if (a.Y > p.Y) != (c.Y > p.Y) && p.X < a.X+(p.Y-a.Y)*(c.X-a.X)/(c.Y-a.Y) {
+			inside = !inside
+		}
+		if n = b.nodes[n].next; n == start {
+			return inside
+		}
+	}
+}
+
+// ringContainsAny reports whether any vertex of the ring starting at node h
+// lies inside the ring starting at node s.
+func (b *triangulationBuilder) ringContainsAny(s, h int) bool {
+	for n := h; ; {
+		if b.ringContains(s, b.nodes[n].uv) {
+			return true
+		}
+		if n = b.nodes[n].next; n == h {
+			return false
+		}
+	}
+}
+
+// bridgeHoles merges the given holes into the shell by connecting each hole
+// to a visible vertex, and returns a node of the resulting ring.
+func (b *triangulationBuilder) bridgeHoles(shell int, holes []int) (int, error) {
+	// Each hole is connected using its rightmost vertex (in the projection),
+	// processing the holes from right to left. This guarantees that a
+	// visible vertex exists.
+	rightmost := make(map[int]int)
+	for _, h := range holes {
+		best, n := h, h
+		for {
+			if b.nodes[n].uv.X > b.nodes[best].uv.X {
+				best = n
+			}
+			if n = b.nodes[n].next; n == h {
+				break
+			}
+		}
+		rightmost[h] = best
+	}
+	sort.SliceStable(holes, func(i, j int) bool {
+		return b.nodes[rightmost[holes[i]]].uv.X > b.nodes[rightmost[holes[j]]].uv.X
+	})
+
+	for i, h := range holes {
+		var candidates []int
+		for n := shell; ; {
+			candidates = append(candidates, n)
+			if n = b.nodes[n].next; n == shell {
+				break
+			}
+		}
+		if b.joinSharedVertex(h, candidates) {
+			continue
+		}
+
+		// Try the vertices of the outer ring in order of increasing distance.
+		hv := rightmost[h]
+		p := b.nodes[hv].p
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return b.nodes[candidates[i]].p.Distance(p) < b.nodes[candidates[j]].p.Distance(p)
+		})
+		bridged := false
+		for _, c := range candidates {
+			if b.canBridge(c, hv, append([]int{shell}, holes[i:]...)) {
+				b.split(c, hv)
+				bridged = true
+				break
+			}
+		}
+		if !bridged {
+			// This can only happen if the hole crosses the shell or another
+			// hole.
+			return 0, fmt.Errorf("cannot triangulate hole at %v: it is not visible from its shell", b.nodes[hv].p)
+		}
+	}
+	return shell, nil
+}
+
+// joinSharedVertex merges the hole into the outer ring if they share a
+// vertex, and reports whether it did so.
+func (b *triangulationBuilder) joinSharedVertex(hole int, outer []int) bool {
+	for h := hole; ; {
+		ph := b.nodes[h].p
+		for _, a := range outer {
+			if b.nodes[a].p != ph {
+				continue
+			}
+			// The hole must be inside the wedge formed by the outer ring at
+			// this vertex (which may be repeated).
+			if !OrderedCCW(b.nodes[b.nodes[a].next].p, b.nodes[b.nodes[h].next].p, b.nodes[b.nodes[a].prev].p, ph) {
+				continue
+			}
+			an, hn := b.nodes[a].next, b.nodes[h].next
+			b.nodes[a].next, b.nodes[hn].prev = hn, a
+			b.nodes[h].next, b.nodes[an].prev = an, h
+			return true
+		}
+		if h = b.nodes[h].next; h == hole {
+			return false
+		}
+	}
+}
+
+// canBridge reports whether the segment between the given nodes lies inside
+// the polygon and does not cross any edge of the given rings.
+func (b *triangulationBuilder) canBridge(a, h int, rings []int) bool {
+	pa, ph := b.nodes[a].p, b.nodes[h].p
+	if pa == ph {
+		return true
+	}
+	// The segment must leave each vertex through the interior of the region.
+	if !OrderedCCW(b.nodes[b.nodes[a].next].p, ph, b.nodes[b.nodes[a].prev].p, pa) ||
+		!OrderedCCW(b.nodes[b.nodes[h].next].p, pa, b.nodes[b.nodes[h].prev].p, ph) {
+		return false
+	}
+	crosser := NewEdgeCrosser(pa, ph)
+	for _, r := range rings {
+		for n := r; ; {
+			c, d := b.nodes[n].p, b.nodes[b.nodes[n].next].p
+			if c != pa && c != ph && d != pa && d != ph && crosser.CrossingSign(c, d) != DoNotCross {
+				return false
+			}
+			if n = b.nodes[n].next; n == r {
+				break
+			}
+		}
+	}
+	return true
+}
+
+// split connects node a to node h, which belong to different rings, by
+// inserting a pair of edges between them. The nodes are duplicated so that
+// the result is a single ring.
+func (b *triangulationBuilder) split(a, h int) {
+	a2 := b.addNode(b.nodes[a].p, b.nodes[a].uv)
+	h2 := b.addNode(b.nodes[h].p, b.nodes[h].uv)
+	an, hp := b.nodes[a].next, b.nodes[h].prev
+
+	b.nodes[a].next, b.nodes[h].prev = h, a
+	b.nodes[a2].next, b.nodes[an].prev = an, a2
+	b.nodes[h2].next, b.nodes[a2].prev = a2, h2
+	b.nodes[hp].next, b.nodes[h2].prev = h2, hp
+}
+
+// earClip triangulates the ring containing the given node. An error is
+// returned if no ear can be found before the ring is reduced to a triangle,
+// which happens if the ring crosses or touches itself.
+func (b *triangulationBuilder) earClip(start int) error {
+	size := 1
+	for n := b.nodes[start].next; n != start; n = b.nodes[n].next {
+		size++
+	}
+	n, stalled := start, 0
+	for size > 3 {
+		prev, next := b.nodes[n].prev, b.nodes[n].next
+		a, c, e := b.nodes[prev].p, b.nodes[n].p, b.nodes[next].p
+		switch {
+		case a == c || c == e || a == e:
+			// A repeated vertex or a spike that doubles back on itself bounds
+			// no area, so the vertex is removed without adding a triangle.
+		case b.isEar(n):
+			b.addTriangle(prev, n, next)
+		default:
+			n = next
+			if stalled++; stalled >= size {
+				// Every vertex has been tested since the last ear was
+				// clipped, so no ears remain.
+				return fmt.Errorf("cannot triangulate ring of %d vertices at %v: no ear found, so the ring crosses or touches itself", size, c)
+			}
+			continue
+		}
+		b.nodes[prev].next, b.nodes[next].prev = next, prev
+		size--
+		n, stalled = next, 0
+	}
+	prev, next := b.nodes[n].prev, b.nodes[n].next
+	a, c, e := b.nodes[prev].p, b.nodes[n].p, b.nodes[next].p
+	switch {
+	case a == c || c == e || a == e:
+	case RobustSign(a, c, e) == CounterClockwise:
+		b.addTriangle(prev, n, next)
+	default:
+		return fmt.Errorf("cannot triangulate ring: the last triangle %v, %v, %v is clockwise, so the ring crosses or touches itself", a, c, e)
+	}
+	return nil
+}
+
+// isEar reports whether the triangle formed by the given node and its two
+// neighbors is convex and does not contain any other vertex of the ring.
+func (b *triangulationBuilder) isEar(n int) bool {
+	pn, nn := b.nodes[n].prev, b.nodes[n].next
+	a, c, e := b.nodes[pn].p, b.nodes[n].p, b.nodes[nn].p
+	if a == c || c == e || RobustSign(a, c, e) != CounterClockwise {
+		return false
+	}
+	// The bound is expanded slightly to allow for errors in the projection.
+	bound := r2.RectFromPoints(b.nodes[pn].uv, b.nodes[n].uv, b.nodes[nn].uv).ExpandedByMargin(1e-13)
+	for m := b.nodes[nn].next; m != pn; m = b.nodes[m].next {
+		p := b.nodes[m].p
+		if !bound.ContainsPoint(b.nodes[m].uv) {
+			continue
+		}
+		// Vertices may be repeated where holes are bridged or loops share a
+		// vertex. In that case the ear is blocked if one of the edges at the
+		// repeated vertex enters the triangle.
+		var x, y, z Point
+		switch p {
+		case a:
+			x, y, z = a, c, e
+		case c:
+			x, y, z = c, e, a
+		case e:
+			x, y, z = e, a, c
+		default:
+			if RobustSign(a, c, p) == CounterClockwise && RobustSign(c, e, p) == CounterClockwise &&
+				RobustSign(e, a, p) == CounterClockwise {
+				return false
+			}
+			continue
+		}
+		for _, q := range []Point{b.nodes[b.nodes[m].prev].p, b.nodes[b.nodes[m].next].p} {
+			if q != y && q != z && RobustSign(x, y, q) == CounterClockwise && RobustSign(x, q, z) == CounterClockwise {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (b *triangulationBuilder) addTriangle(x, y, z int) {
+	b.t.Triangles = append(b.t.Triangles, [3]int{
+		b.t.vertex(b.nodes[x].p), b.t.vertex(b.nodes[y].p), b.t.vertex(b.nodes[z].p),
+	})
+}
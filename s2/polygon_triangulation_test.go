@@ -0,0 +1,230 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/rubenpoppe/geo/s1"
+)
+
+// checkTriangulation verifies that the triangles are CCW, that their total
+// area is within maxError of the given area, and that the centroid of every
+// triangle is contained by the region.
+func checkTriangulation(t *testing.T, name string, tri *PolygonTriangulation, want, maxError float64, contains func(Point) bool) {
+	t.Helper()
+	var sum float64
+	for _, v := range tri.Triangles {
+		a, b, c := tri.Vertices[v[0]], tri.Vertices[v[1]], tri.Vertices[v[2]]
+		if RobustSign(a, b, c) != CounterClockwise {
+			t.Errorf("%s: triangle %v is not CCW", name, v)
+		}
+		// Triangles with collinear vertices have their centroid on the
+		// boundary of the region, so only check triangles that are not slivers.
+		area := PointArea(a, b, c)
+		longest := math.Max(a.Distance(b).Radians(), math.Max(b.Distance(c).Radians(), c.Distance(a).Radians()))
+		centroid := Point{TrueCentroid(a, b, c).Normalize()}
+		if area > 1e-6*longest*longest && !contains(centroid) {
+			t.Errorf("%s: triangle %v is outside the region", name, v)
+		}
+		sum += area
+	}
+	if !float64Near(sum, want, maxError) {
+		t.Errorf("%s: total area of the triangles = %v, want %v", name, sum, want)
+	}
+}
+
+func TestTriangulatePolygon(t *testing.T) {
+	tests := []struct {
+		name string
+		poly string
+	}{
+		{"empty", "empty"},
+		{"triangle", "0:0, 0:1, 1:0"},
+		{"square", "0:0, 0:10, 10:10, 10:0"},
+		{"nonconvex", "0:0, 0:10, 5:5, 10:10, 10:0, 5:2"},
+		{"spiral", "0:0, 0:10, 10:10, 10:2, 4:2, 4:6, 6:6, 6:4, 8:4, 8:8, 2:8, 2:0"},
+		{"hole", "0:0, 0:10, 10:10, 10:0; 2:2, 8:2, 8:8, 2:8"},
+		{"two holes", "0:0, 0:10, 10:10, 10:0; 2:2, 8:2, 8:4, 2:4; 6:6, 8:6, 8:8, 6:8"},
+		{"island in hole", "0:0, 0:10, 10:10, 10:0; 2:2, 8:2, 8:8, 2:8; 4:4, 4:6, 6:6, 6:4"},
+		{"shared vertex", "0:0, 0:10, 10:10, 10:0; 0:0, 5:8, 5:2"},
+		{"two shells", "0:0, 0:1, 1:0; 5:5, 5:6, 6:5"},
+		{"large", "-60:-170, -60:-50, -60:70, 60:70, 60:-50, 60:-170"},
+		{"full", "full"},
+		{"hemisphere", "0:0, 0:90, 0:180, 0:-90"},
+	}
+	for _, test := range tests {
+		p := makePolygon(test.poly, true)
+		if err := p.Validate(); err != nil {
+			t.Fatalf("%s: invalid test polygon: %v", test.name, err)
+		}
+		tri, err := TriangulatePolygon(p)
+		if err != nil {
+			t.Errorf("%s: TriangulatePolygon returned error: %v", test.name, err)
+			continue
+		}
+		checkTriangulation(t, test.name, tri, p.Area(), 1e-14, p.ContainsPoint)
+	}
+
+	// The complement of a square, which contains everything except a hole.
+	p := makePolygon("0:0, 10:0, 10:10, 0:10", false)
+	tri, err := TriangulatePolygon(p)
+	if err != nil {
+		t.Fatalf("complement: TriangulatePolygon returned error: %v", err)
+	}
+	checkTriangulation(t, "complement", tri, p.Area(), 1e-14, p.ContainsPoint)
+}
+
+func TestTriangulatePolygonVertices(t *testing.T) {
+	// A polygon within a hemisphere is triangulated using only its vertices.
+	p := makePolygon("0:0, 0:10, 10:10, 10:0; 2:2, 8:2, 8:8, 2:8", true)
+	tri, err := TriangulatePolygon(p)
+	if err != nil {
+		t.Fatalf("TriangulatePolygon returned error: %v", err)
+	}
+	if got, want := len(tri.Vertices), 8; got != want {
+		t.Errorf("len(Vertices) = %d, want %d", got, want)
+	}
+	// A polygon with n vertices and h holes has n + 2h - 2 triangles.
+	if got, want := len(tri.Triangles), 8; got != want {
+		t.Errorf("len(Triangles) = %d, want %d", got, want)
+	}
+}
+
+func TestTriangulatePolygonCubeFaces(t *testing.T) {
+	// These polygons have edges along the boundaries of the cube faces, and
+	// pass through the corners where three faces meet.
+	face1 := CellIDFromFace(1).Children()[0].Children()[0].Children()[0]
+	face4 := CellIDFromFace(4).Children()[0].Children()[0]
+	tests := []struct {
+		name string
+		cu   CellUnion
+	}{
+		{"three faces", CellUnion{CellIDFromFace(0), CellIDFromFace(1), CellIDFromFace(2)}},
+		{"face and corners", CellUnion{CellIDFromFace(0), face1, face4}},
+	}
+	for _, test := range tests {
+		test.cu.Normalize()
+		p := PolygonFromCellUnionBorder(test.cu)
+		tri, err := TriangulatePolygon(p)
+		if err != nil {
+			t.Errorf("%s: TriangulatePolygon returned error: %v", test.name, err)
+			continue
+		}
+		checkTriangulation(t, test.name, tri, p.Area(), 1e-13, p.ContainsPoint)
+	}
+}
+
+func TestTriangulatePolygonRandom(t *testing.T) {
+	// Fractal loops have many nearly collinear vertices. Both Loop.Area and
+	// PointArea lose precision for the resulting sliver triangles, so their
+	// sums only agree to within about 1e-8.
+	const maxError = 5e-8
+	for iter := 0; iter < 20; iter++ {
+		f := newFractal()
+		f.setLevelForApproxMaxEdges(500)
+		f.dimension = 1.5
+		l := f.makeLoop(randomFrame(), s1.Angle(randomUniformFloat64(0.01, 1.5)))
+		p := PolygonFromLoops([]*Loop{l})
+		tri, err := TriangulatePolygon(p)
+		if err != nil {
+			t.Fatalf("fractal: TriangulatePolygon returned error: %v", err)
+		}
+		checkTriangulation(t, "fractal", tri, p.Area(), maxError, p.ContainsPoint)
+
+		// Also triangulate the complement, which is larger than a hemisphere.
+		p.Invert()
+		if tri, err = TriangulatePolygon(p); err != nil {
+			t.Fatalf("inverted fractal: TriangulatePolygon returned error: %v", err)
+		}
+		checkTriangulation(t, "inverted fractal", tri, p.Area(), maxError, p.ContainsPoint)
+	}
+
+	for iter := 0; iter < 10; iter++ {
+		p := concentricLoopsPolygon(randomPoint(), 1+randomUniformInt(6), 3+randomUniformInt(20))
+		tri, err := TriangulatePolygon(p)
+		if err != nil {
+			t.Fatalf("concentric: TriangulatePolygon returned error: %v", err)
+		}
+		checkTriangulation(t, "concentric", tri, p.Area(), 1e-14, p.ContainsPoint)
+	}
+}
+
+func TestTriangulateShape(t *testing.T) {
+	tests := []struct {
+		name string
+		poly string
+		area float64
+	}{
+		{"square", "0:0, 0:10, 10:10, 10:0", makeLoop("0:0, 0:10, 10:10, 10:0").Area()},
+		{"hole", "0:0, 0:10, 10:10, 10:0; 2:2, 8:2, 8:8, 2:8", makePolygon("0:0, 0:10, 10:10, 10:0; 2:2, 8:2, 8:8, 2:8", true).Area()},
+		{"full", "full", 4 * math.Pi},
+		{"empty", "", 0},
+	}
+	for _, test := range tests {
+		shape := makeLaxPolygon(test.poly)
+		tri, err := TriangulateShape(shape)
+		if err != nil {
+			t.Errorf("%s: TriangulateShape returned error: %v", test.name, err)
+			continue
+		}
+		checkTriangulation(t, test.name, tri, test.area, 1e-14, func(p Point) bool { return containsBruteForce(shape, p) })
+	}
+
+	if _, err := TriangulateShape(makePolyline("0:0, 1:1")); err == nil {
+		t.Errorf("TriangulateShape(polyline) succeeded, want error")
+	}
+
+	// A ring that crosses itself cannot be triangulated without overlapping
+	// triangles.
+	if tri, err := TriangulateShape(makeLaxPolygon("0:0, 0:10, 10:0, 10:10")); err == nil {
+		t.Errorf("TriangulateShape(bowtie) = %v, want error", tri.Triangles)
+	}
+}
+
+func TestPolygonTriangulationRefine(t *testing.T) {
+	for _, s := range []string{
+		"0:0, 0:10, 10:10, 10:0; 2:2, 8:2, 8:8, 2:8",
+		"-60:-170, -60:-50, -60:70, 60:70, 60:-50, 60:-170",
+	} {
+		p := makePolygon(s, true)
+		tri, err := TriangulatePolygon(p)
+		if err != nil {
+			t.Fatalf("%s: TriangulatePolygon returned error: %v", s, err)
+		}
+		maxEdge := s1.Degree
+		tri.Refine(maxEdge)
+		checkTriangulation(t, s, tri, p.Area(), 1e-12, p.ContainsPoint)
+
+		// Every edge must be short enough, and since the triangles form a
+		// consistently oriented mesh, no directed edge may appear twice.
+		edges := make(map[[2]int]int)
+		for _, v := range tri.Triangles {
+			for k := 0; k < 3; k++ {
+				a, b := v[k], v[(k+1)%3]
+				if d := tri.Vertices[a].Distance(tri.Vertices[b]); d > maxEdge {
+					t.Errorf("%s: edge length %v exceeds %v", s, d, maxEdge)
+				}
+				edges[[2]int{a, b}]++
+			}
+		}
+		for e, n := range edges {
+			if n != 1 {
+				t.Errorf("%s: edge %v appears %d times", s, e, n)
+			}
+		}
+	}
+}
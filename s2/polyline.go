@@ -353,7 +353,166 @@ func (p *Polyline) SubsampleVertices(tolerance s1.Angle) []int {
 	return result
 }
 
-// Encode encodes the Polyline.
+// Simplified returns a new polyline made of the vertices of this polyline
+// chosen by SubsampleVertices with the given tolerance. Every vertex of the
+// result is a vertex of this polyline, and the result follows this polyline
+// to within the tolerance.
+func (p *Polyline) Simplified(tolerance s1.Angle) *Polyline {
+	indices := p.SubsampleVertices(tolerance)
+	result := make(Polyline, len(indices))
+	for i, index := range indices {
+		result[i] = (*p)[index]
+	}
+	return &result
+}
+
+// Snapped returns a new polyline whose vertices are the vertices of this
+// polyline snapped to the centers of the cells at the given level that
+// contain them. Adjacent vertices that snap to the same cell are merged.
+//
+// Each vertex moves by at most the diagonal of a cell at the given level, but
+// unlike the C++ version, which uses S2Builder, the edges are not split to
+// keep the result within that distance of the original edges.
+func (p *Polyline) Snapped(level int) *Polyline {
+	result := make(Polyline, 0, len(*p))
+	for _, v := range *p {
+		snapped := cellIDFromPoint(v).Parent(level).Point()
+		if len(result) == 0 || result[len(result)-1] != snapped {
+			result = append(result, snapped)
+		}
+	}
+	return &result
+}
+
+// SnapLevel returns the level at which all of the vertices of the polyline
+// are centers of cells, or -1 if there is no such level. Polylines made by
+// Snapped have a snap level, and encode to about 4 bytes per vertex instead
+// of 24. A decoded polyline has a snap level only if its vertices are exact
+// cell centers, as they are when it was encoded with one.
+//
+// An empty polyline has a snap level of -1.
+func (p *Polyline) SnapLevel() int {
+	snapLevel := -1
+	for _, v := range *p {
+		_, _, _, level := xyzToFaceSiTi(v)
+		if level < 0 || (snapLevel >= 0 && level != snapLevel) {
+			return -1
+		}
+		snapLevel = level
+	}
+	return snapLevel
+}
+
+// nextDistinctVertex returns the first index after the given one whose
+// vertex is not at the same point as the vertex at that index, or
+// len(p) if there is no such index.
+func nextDistinctVertex(p Polyline, index int) int {
+	initial := p[index]
+	for index++; index < len(p) && p[index] == initial; index++ {
+	}
+	return index
+}
+
+// polylineSearchState is a state in the search done by NearlyCoversPolyline.
+type polylineSearchState struct {
+	i, j        int
+	iInProgress bool
+}
+
+// NearlyCoversPolyline reports whether this polyline covers the given
+// polyline to within the given tolerance. Specifically, it reports whether
+// every point of covered is within maxError of some point of this polyline,
+// walking along both of them in order: the covered polyline must follow this
+// polyline from start to end without skipping back. This is useful for
+// checking that a recorded route adheres to a planned one.
+//
+// The covered polyline may start and end anywhere along this polyline, may
+// contain fewer or more vertices, and may include degenerate edges. A
+// polyline with a single vertex is covered if that vertex is within maxError
+// of this polyline, and an empty polyline is always covered. An empty
+// polyline or one with a single vertex covers nothing but empty polylines.
+func (p *Polyline) NearlyCoversPolyline(covered *Polyline, maxError s1.Angle) bool {
+	// The algorithm is a depth first search over pairs of positions, one on
+	// each polyline. A state (i, j, iInProgress) means that the covered
+	// polyline has been followed up to its vertex j, and that vertex is
+	// within maxError of the edge (i, i+1) of this polyline. If iInProgress
+	// is true, the search is at the point of edge i closest to vertex j of
+	// covered; otherwise it is at vertex i of this polyline, and at the point
+	// of covered edge j that is closest to it.
+	//
+	// From each state, the search advances along whichever polylines it can:
+	// to vertex i+1 if the remainder of edge i is near the remainder of
+	// covered edge j, and to covered vertex j+1 if the remainder of covered
+	// edge j is near the remainder of edge i. The search succeeds when it
+	// reaches the end of the covered polyline.
+	if len(*covered) == 0 {
+		return true
+	}
+	if len(*p) == 0 {
+		return false
+	}
+	a, b := *p, *covered
+
+	var pending []polylineSearchState
+	done := make(map[polylineSearchState]bool)
+
+	// Find all possible starting states.
+	for i, nextI := 0, nextDistinctVertex(a, 0); nextI < len(a); {
+		nextNextI := nextDistinctVertex(a, nextI)
+		closest := Project(b[0], a[i], a[nextI])
+
+		// In order to avoid duplicate starting states, we exclude the end
+		// vertex of each edge except for the last non-degenerate edge.
+		if (nextNextI == len(a) || closest != a[nextI]) && closest.Distance(b[0]) <= maxError {
+			pending = append(pending, polylineSearchState{i, 0, true})
+		}
+		i, nextI = nextI, nextNextI
+	}
+
+	for len(pending) > 0 {
+		state := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if done[state] {
+			continue
+		}
+		done[state] = true
+
+		nextI := nextDistinctVertex(a, state.i)
+		nextJ := nextDistinctVertex(b, state.j)
+		if nextJ == len(b) {
+			return true
+		}
+		if nextI == len(a) {
+			continue
+		}
+
+		var iBegin, jBegin Point
+		if state.iInProgress {
+			jBegin = b[state.j]
+			iBegin = Project(jBegin, a[state.i], a[nextI])
+		} else {
+			iBegin = a[state.i]
+			jBegin = Project(iBegin, b[state.j], b[nextJ])
+		}
+
+		if IsEdgeBNearEdgeA(jBegin, b[nextJ], iBegin, a[nextI], maxError) {
+			pending = append(pending, polylineSearchState{nextI, state.j, false})
+		}
+		if IsEdgeBNearEdgeA(iBegin, a[nextI], jBegin, b[nextJ], maxError) {
+			pending = append(pending, polylineSearchState{state.i, nextJ, true})
+		}
+	}
+	return false
+}
+
+// encodingPolylineCompressedVersion is the version of the compressed polyline
+// encoding. It differs from encodingCompressedVersion to stay compatible with
+// the C++ S2Polyline encoding.
+const encodingPolylineCompressedVersion = int8(2)
+
+// Encode encodes the Polyline. Polylines whose vertices are mostly snapped to
+// the centers of cells at one level, such as those made by Snapped, use a
+// compressed encoding that is many times smaller.
 func (p Polyline) Encode(w io.Writer) error {
 	e := &encoder{w: w}
 	p.encode(e)
@@ -361,6 +520,50 @@ func (p Polyline) Encode(w io.Writer) error {
 }
 
 func (p Polyline) encode(e *encoder) {
+	if len(p) == 0 {
+		p.encodeLossless(e)
+		return
+	}
+
+	// Convert all the vertices to XYZFaceSiTi format.
+	vs := make([]xyzFaceSiTi, len(p))
+	for i, v := range p {
+		vs[i].xyz = v
+		vs[i].face, vs[i].si, vs[i].ti, vs[i].level = xyzToFaceSiTi(v)
+	}
+
+	// Computes a histogram of the cell levels at which the vertices are snapped.
+	// (histogram[0] is the number of unsnapped vertices, histogram[i] the number
+	// of vertices snapped at level i-1).
+	histogram := make([]int, maxLevel+2)
+	for _, v := range vs {
+		histogram[v.level+1]++
+	}
+
+	// Compute the level at which most of the vertices are snapped. Ties go
+	// to the lowest level, which has the smallest encoding.
+	var snapLevel, numSnapped int
+	for level, h := range histogram[1:] {
+		if h > numSnapped {
+			snapLevel, numSnapped = level, h
+		}
+	}
+
+	// Choose an encoding format based on the number of unsnapped vertices and a
+	// rough estimate of the encoded sizes.
+	numUnsnapped := len(p) - numSnapped
+	const pointSize = 3 * 8
+	compressedSize := 4*len(p) + (pointSize+2)*numUnsnapped
+	losslessSize := pointSize * len(p)
+	if compressedSize < losslessSize {
+		p.encodeCompressed(e, snapLevel, vs)
+	} else {
+		p.encodeLossless(e)
+	}
+}
+
+// encodeLossless encodes the polyline's Points as float64s.
+func (p Polyline) encodeLossless(e *encoder) {
 	e.writeInt8(encodingVersion)
 	e.writeUint32(uint32(len(p)))
 	for _, v := range p {
@@ -370,36 +573,58 @@ func (p Polyline) encode(e *encoder) {
 	}
 }
 
+func (p Polyline) encodeCompressed(e *encoder, snapLevel int, vertices []xyzFaceSiTi) {
+	if len(vertices) > maxEncodedVertices {
+		if e.err == nil {
+			e.err = fmt.Errorf("too many vertices (%d; max is %d)", len(vertices), maxEncodedVertices)
+		}
+		return
+	}
+	e.writeInt8(encodingPolylineCompressedVersion)
+	e.writeUint8(uint8(snapLevel))
+	e.writeUvarint(uint64(len(vertices)))
+	encodePointsCompressed(e, vertices, snapLevel)
+}
+
 // Decode decodes the polyline.
 func (p *Polyline) Decode(r io.Reader) error {
-	d := decoder{r: asByteReader(r)}
+	d := newDecoder(r, DecodeOptions{})
 	p.decode(d)
 	return d.err
 }
 
-func (p *Polyline) decode(d decoder) {
+func (p *Polyline) decode(d *decoder) {
 	version := d.readInt8()
 	if d.err != nil {
 		return
 	}
-	if int(version) != int(encodingVersion) {
-		d.err = fmt.Errorf("can't decode version %d; my version: %d", version, encodingVersion)
-		return
-	}
-	nvertices := d.readUint32()
-	if d.err != nil {
+	var vertices []Point
+	switch version {
+	case encodingVersion:
+		nvertices := d.readUint32()
+		if !d.checkCount(uint64(nvertices), maxEncodedVertices, "vertices") {
+			return
+		}
+		vertices = d.readPoints(uint64(nvertices))
+	case encodingPolylineCompressedVersion:
+		snapLevel := int(d.readUint8())
+		if d.err == nil && snapLevel > maxLevel {
+			d.err = fmt.Errorf("%w: snaplevel too big: %d", ErrDecodeInvalid, snapLevel)
+			return
+		}
+		nvertices := d.readUvarint()
+		if !d.checkCount(nvertices, maxEncodedVertices, "vertices") {
+			return
+		}
+		vertices = decodePointsCompressed(d, snapLevel, int(nvertices))
+	default:
+		d.err = fmt.Errorf("%w: can't decode version %d; my version: %d", ErrDecodeVersion, version, encodingVersion)
 		return
 	}
-	if nvertices > maxEncodedVertices {
-		d.err = fmt.Errorf("too many vertices (%d; max is %d)", nvertices, maxEncodedVertices)
+	if !d.checkPoints(vertices) {
 		return
 	}
-	*p = make([]Point, nvertices)
-	for i := range *p {
-		(*p)[i].X = d.readFloat64()
-		(*p)[i].Y = d.readFloat64()
-		(*p)[i].Z = d.readFloat64()
-	}
+	*p = vertices
 }
 
 // Project returns a point on the polyline that is closest to the given point,
@@ -486,7 +711,8 @@ func (p *Polyline) Validate() error {
 // polyline endpoint is the only intersection with the other polyline, the
 // function may return true or false arbitrarily.
 //
-// The running time is quadratic in the number of vertices.
+// Small polylines are tested edge pair by edge pair. Larger ones are indexed,
+// which makes the running time roughly linear in the number of vertices.
 func (p *Polyline) Intersects(o *Polyline) bool {
 	if len(*p) == 0 || len(*o) == 0 {
 		return false
@@ -496,7 +722,18 @@ func (p *Polyline) Intersects(o *Polyline) bool {
 		return false
 	}
 
-	// TODO(roberts): Use ShapeIndex here.
+	// Building the indexes has a fixed cost that only pays off once there
+	// are enough edge pairs to test.
+	const maxBruteForceEdgePairs = 1024
+	if (len(*p)-1)*(len(*o)-1) > maxBruteForceEdgePairs {
+		return p.intersectsIndexed(o)
+	}
+	return p.intersectsBruteForce(o)
+}
+
+// intersectsBruteForce is the version of Intersects that tests every pair of
+// edges.
+func (p *Polyline) intersectsBruteForce(o *Polyline) bool {
 	for i := 1; i < len(*p); i++ {
 		crosser := NewChainEdgeCrosser((*p)[i-1], (*p)[i], (*o)[0])
 		for j := 1; j < len(*o); j++ {
@@ -508,6 +745,25 @@ func (p *Polyline) Intersects(o *Polyline) bool {
 	return false
 }
 
+// intersectsIndexed is the version of Intersects that uses a ShapeIndex for
+// each polyline, and only tests the pairs of edges that share an index cell.
+func (p *Polyline) intersectsIndexed(o *Polyline) bool {
+	a := NewShapeIndex()
+	a.Add(p)
+	b := NewShapeIndex()
+	b.Add(o)
+	// The visitor stops at the first crossing.
+	return !VisitCrossingEdgePairsBetween(a, b, CrossingTypeAll, func(ShapeEdge, ShapeEdge, bool) bool {
+		return false
+	})
+}
+
+// SelfIntersections returns every point where two non-adjacent edges of the
+// polyline cross or touch. See FindSelfIntersections for details.
+func (p *Polyline) SelfIntersections() []EdgeIntersection {
+	return FindSelfIntersections(p)
+}
+
 // Interpolate returns the point whose distance from vertex 0 along the polyline is
 // the given fraction of the polyline's total length, and the index of
 // the next vertex after the interpolated point P. Fractions less than zero
@@ -580,10 +836,3 @@ func (p *Polyline) Uninterpolate(point Point, nextVertex int) float64 {
 	// point is not exactly on the polyline.
 	return minFloat64(1.0, float64(lengthToPoint/sum))
 }
-
-// TODO(roberts): Differences from C++.
-// NearlyCoversPolyline
-// InitToSnapped
-// InitToSimplified
-// SnapLevel
-// encode/decode compressed
@@ -15,6 +15,8 @@
 package s2
 
 import (
+	"bytes"
+	"fmt"
 	"math"
 	"reflect"
 	"testing"
@@ -627,21 +629,221 @@ func TestPolylineUninterpolate(t *testing.T) {
 	}
 }
 
-// TODO(roberts): Test differences from C++:
-// InitToSnapped
-// InitToSimplified
-//
-// PolylineCoveringTest
-//    PolylineOverlapsSelf
-//    PolylineDoesNotOverlapReverse
-//    PolylineOverlapsEquivalent
-//    ShortCoveredByLong
-//    PartialOverlapOnly
-//    ShortBacktracking
-//    LongBacktracking
-//    IsResilientToDuplicatePoints
-//    CanChooseBetweenTwoPotentialStartingPoints
-//    StraightAndWigglyPolylinesCoverEachOther
-//    MatchStartsAtLastVertex
-//    MatchStartsAtDuplicatedLastVertex
-//    EmptyPolylines
+func TestPolylineNearlyCoversPolyline(t *testing.T) {
+	tests := []struct {
+		a, b string
+		// maxError in degrees.
+		maxError float64
+		// Whether b covers a and whether a covers b.
+		bCoversA, aCoversB bool
+	}{
+		// A polyline covers itself but not its reverse.
+		{"1:1, 2:2, -1:10", "1:1, 2:2, -1:10", 1e-10, true, true},
+		{"1:1, 2:2, -1:10", "-1:10, 2:2, 1:1", 1e-10, false, false},
+		// The same path with a different number of vertices.
+		{"1:1, 2:1", "1:1, 1.5:1, 2:1", 1e-10, true, true},
+		// The second polyline is always within 0.001 degrees of the first,
+		// but the first is too long to be covered by the second.
+		{"-5:1, 10:1, 10:5, 5:10", "9:1, 9.9995:1, 10.0005:5", 1e-3, false, true},
+		// The polylines partially overlap each other, but neither covers
+		// the other.
+		{"-5:1, 10:1", "0:1, 20:1", 1, false, false},
+		// Two polylines that backtrack a little on different edges. A simple
+		// greedy matching algorithm fails on this example.
+		{"0:0, 0:2, 0:1, 0:4, 0:5", "0:0, 0:2, 0:4, 0:3, 0:5", 1.5, true, true},
+		{"0:0, 0:2, 0:1, 0:4, 0:5", "0:0, 0:2, 0:4, 0:3, 0:5", 0.5, false, false},
+		// Arcs in opposite directions only cover each other if the shorter
+		// one is shorter than maxError.
+		{"5:1, -5:1", "1:1, 3:1", 1, false, false},
+		{"5:1, -5:1", "1:1, 3:1", 2.5, false, true},
+		// Duplicate adjacent vertices are allowed.
+		{"0:1, 0:2, 0:2, 0:3", "0:1, 0:1, 0:1, 0:3", 1e-10, true, true},
+		// There are two possible starting points for covering the second
+		// polyline, and only the second of them leads to a match.
+		{"0:11, 0:0, 0:9, 0:20", "0:10, 0:15", 1.5, false, true},
+		// A straight polyline and a wiggly one that stays near it.
+		{"40:1, 20:1", "39.9:0.9, 40:1.1, 30:1.15, 29:0.95, 28:1.1, 27:1.15, 26:1.05, 25:0.85, 24:1.1, 23:0.9, 20:0.99", 0.2, true, true},
+		// The match starts at the last vertex of the first polyline, which
+		// may be duplicated.
+		{"0:0, 0:2", "0:2, 0:3", 1.5, false, true},
+		{"0:0, 0:2, 0:2, 0:2", "0:2, 0:3", 1.5, false, true},
+		// Everything covers an empty polyline, and an empty polyline covers
+		// nothing else.
+		{"0:1, 0:2", "", 0, false, true},
+		{"", "", 0, true, true},
+		// A single vertex is covered if it is near the polyline.
+		{"0:0, 0:10", "0.5:5", 1, false, true},
+		{"0:0, 0:10", "2:5", 1, false, false},
+	}
+	for _, test := range tests {
+		a := makePolyline(test.a)
+		b := makePolyline(test.b)
+		maxError := s1.Angle(test.maxError) * s1.Degree
+		if got := b.NearlyCoversPolyline(a, maxError); got != test.bCoversA {
+			t.Errorf("%q.NearlyCoversPolyline(%q, %v) = %v, want %v", test.b, test.a, maxError, got, test.bCoversA)
+		}
+		if got := a.NearlyCoversPolyline(b, maxError); got != test.aCoversB {
+			t.Errorf("%q.NearlyCoversPolyline(%q, %v) = %v, want %v", test.a, test.b, maxError, got, test.aCoversB)
+		}
+	}
+}
+
+func TestPolylineSnapped(t *testing.T) {
+	p := makePolyline("0:0, 0:10, 0:10.0000001, 10:20, 20:20")
+	for _, level := range []int{5, 14, maxLevel} {
+		snapped := p.Snapped(level)
+		if got := snapped.SnapLevel(); got != level {
+			t.Errorf("%v.Snapped(%d).SnapLevel() = %d, want %d", p, level, got, level)
+		}
+		maxError := s1.Angle(MaxDiagMetric.Value(level))
+		if !snapped.NearlyCoversPolyline(p, 2*maxError) {
+			t.Errorf("%v.Snapped(%d) = %v does not cover the original polyline", p, level, snapped)
+		}
+		for i := 1; i < len(*snapped); i++ {
+			if (*snapped)[i-1] == (*snapped)[i] {
+				t.Errorf("%v.Snapped(%d) = %v has duplicate vertices at %d", p, level, snapped, i)
+			}
+		}
+	}
+	// The two nearby vertices snap to the same cell below the leaf level.
+	if got := len(*p.Snapped(14)); got != 4 {
+		t.Errorf("%v.Snapped(14) has %d vertices, want 4", p, got)
+	}
+}
+
+func TestPolylineSnapLevel(t *testing.T) {
+	id := CellIDFromFace(2).ChildBeginAtLevel(10)
+	tests := []struct {
+		p    Polyline
+		want int
+	}{
+		{Polyline{}, -1},
+		{Polyline{id.Point()}, 10},
+		{Polyline{id.Point(), id.Next().Point(), id.Next().Next().Point()}, 10},
+		{Polyline{id.Point(), id.Parent(9).Point()}, -1},
+		{Polyline{id.Point(), PointFromCoords(1, 2, 3)}, -1},
+		{Polyline{CellIDFromFace(0).Point(), CellIDFromFace(1).Point()}, 0},
+	}
+	for _, test := range tests {
+		if got := test.p.SnapLevel(); got != test.want {
+			t.Errorf("%v.SnapLevel() = %d, want %d", test.p, got, test.want)
+		}
+	}
+}
+
+func TestPolylineSimplified(t *testing.T) {
+	p := makePolyline("0:0, 0:1, 0:2, 0:3, 0:4, 1:4, 2:4, 2:5")
+	simplified := p.Simplified(s1.Angle(0.1) * s1.Degree)
+	if want := makePolyline("0:0, 0:4, 2:4, 2:5"); !simplified.Equal(want) {
+		t.Errorf("%v.Simplified(0.1°) = %v, want %v", p, simplified, want)
+	}
+	if !simplified.NearlyCoversPolyline(p, s1.Angle(0.1)*s1.Degree) {
+		t.Errorf("%v.Simplified(0.1°) = %v does not cover the original polyline", p, simplified)
+	}
+	if got := (&Polyline{}).Simplified(s1.Degree); len(*got) != 0 {
+		t.Errorf("Simplified of an empty polyline = %v, want empty", got)
+	}
+}
+
+func TestPolylineEncodeDecode(t *testing.T) {
+	long := make(Polyline, 1000)
+	for i := range long {
+		long[i] = PointFromLatLng(LatLngFromDegrees(float64(i)*0.01, float64(i%7)*0.01))
+	}
+	mixed := append(Polyline(nil), *long.Snapped(20)...)
+	mixed[10] = randomPoint()
+
+	tests := []struct {
+		label string
+		p     Polyline
+		// The largest size of the encoding, in bytes per vertex.
+		maxVertexSize float64
+	}{
+		{"unsnapped", long, 24.01},
+		{"snapped level 20", *long.Snapped(20), 4},
+		{"snapped level 30", *long.Snapped(maxLevel), 8},
+		{"snapped with an exception", mixed, 5},
+		{"single snapped vertex", Polyline{CellIDFromFace(3).Point()}, 24},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := test.p.Encode(&buf); err != nil {
+			t.Fatalf("%s: Encode failed: %v", test.label, err)
+		}
+		if got, max := float64(buf.Len())/float64(len(test.p)), test.maxVertexSize; got > max {
+			t.Errorf("%s: encoding has %.1f bytes per vertex, want at most %v", test.label, got, max)
+		}
+
+		var got Polyline
+		if err := got.Decode(&buf); err != nil {
+			t.Fatalf("%s: Decode failed: %v", test.label, err)
+		}
+		if !got.Equal(&test.p) {
+			t.Errorf("%s: Decode(Encode(%v)) = %v", test.label, test.p, got)
+		}
+	}
+}
+
+// randomWalkPolyline returns a polyline of n vertices that wanders around
+// the given cap.
+func randomWalkPolyline(c Cap, n int) *Polyline {
+	p := make(Polyline, n)
+	for i := range p {
+		p[i] = samplePointFromCap(c)
+	}
+	return &p
+}
+
+func TestPolylineIntersectsIndexed(t *testing.T) {
+	for iter := 0; iter < 50; iter++ {
+		c := CapFromCenterAngle(randomPoint(), kmToAngle(10))
+		a := randomWalkPolyline(c, 2+randomUniformInt(40))
+		// Polylines near the edge of the cap cross a only sometimes.
+		b := randomWalkPolyline(CapFromCenterAngle(samplePointFromCap(c), kmToAngle(float64(1+randomUniformInt(3)))), 2+randomUniformInt(4))
+		if iter%5 == 0 {
+			// Share a vertex.
+			(*b)[1] = (*a)[randomUniformInt(len(*a))]
+		}
+
+		want := false
+		for i := 0; i < a.NumEdges(); i++ {
+			for j := 0; j < b.NumEdges(); j++ {
+				ea, eb := a.Edge(i), b.Edge(j)
+				if CrossingSign(ea.V0, ea.V1, eb.V0, eb.V1) != DoNotCross {
+					want = true
+				}
+			}
+		}
+		if got := a.intersectsIndexed(b); got != want {
+			t.Errorf("%v.intersectsIndexed(%v) = %v, want %v", a, b, got, want)
+		}
+		if got := a.Intersects(b); got != want {
+			t.Errorf("%v.Intersects(%v) = %v, want %v", a, b, got, want)
+		}
+	}
+}
+
+func BenchmarkPolylineIntersects(b *testing.B) {
+	for _, n := range []int{10, 32, 100, 1000, 10000} {
+		// Two parallel tracks that do not cross, which is the worst case.
+		p := make(Polyline, n)
+		o := make(Polyline, n)
+		for i := range p {
+			p[i] = PointFromLatLng(LatLngFromDegrees(float64(i)*1e-3, 0))
+			o[i] = PointFromLatLng(LatLngFromDegrees(float64(i)*1e-3, 1e-3))
+		}
+		b.Run(fmt.Sprintf("bruteforce/%d", n), func(b *testing.B) {
+			if n > 1000 {
+				b.Skip("too slow")
+			}
+			for i := 0; i < b.N; i++ {
+				p.intersectsBruteForce(&o)
+			}
+		})
+		b.Run(fmt.Sprintf("indexed/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				p.intersectsIndexed(&o)
+			}
+		})
+	}
+}
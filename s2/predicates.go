@@ -675,6 +675,154 @@ func exactCompareDistance(x, y r3.PreciseVector, r2 *big.Float) int {
 	return xySign * cmp.Sign()
 }
 
+// inCircleSign reports the position of the point D relative to the circle
+// passing through the points A, B and C. It returns +1 if D lies strictly
+// inside the circle, -1 if it lies strictly outside, and 0 if the four points
+// are exactly cocircular. Here "inside" refers to the side of the circle that
+// lies to the left of the edges of the triangle ABC, i.e. the smaller side
+// when ABC is counterclockwise.
+//
+// Equivalently this is the sign of the determinant (B-A)x(C-A).(D-A), which
+// is positive when D is above the plane through A, B and C with respect to
+// the plane normal (B-A)x(C-A). This interpretation also holds for points that
+// are not unit length (e.g. D may be the origin), which makes this predicate
+// suitable for computing three-dimensional convex hulls.
+//
+// The result is computed exactly, so it is consistent for all inputs.
+func inCircleSign(a, b, c, d Point) int {
+	if sign := triageInCircleSign(a, b, c, d); sign != 0 {
+		return sign
+	}
+	return exactInCircleSign(a, b, c, d)
+}
+
+// triageInCircleSign returns the sign of (B-A)x(C-A).(D-A) if it can be
+// determined with certainty using floating-point arithmetic, or 0 otherwise.
+func triageInCircleSign(a, b, c, d Point) int {
+	ba := b.Sub(a.Vector)
+	ca := c.Sub(a.Vector)
+	da := d.Sub(a.Vector)
+	det := ba.Cross(ca).Dot(da)
+
+	// The error bound is proportional to the "permanent" of the determinant,
+	// i.e. the sum of the absolute values of all of its terms. The constant
+	// also accounts for the rounding errors in the initial subtractions.
+	ba, ca, da = ba.Abs(), ca.Abs(), da.Abs()
+	permanent := da.X*(ba.Y*ca.Z+ba.Z*ca.Y) + da.Y*(ba.Z*ca.X+ba.X*ca.Z) +
+		da.Z*(ba.X*ca.Y+ba.Y*ca.X)
+	maxErr := 8 * dblEpsilon * permanent
+	if det > maxErr {
+		return 1
+	}
+	if det < -maxErr {
+		return -1
+	}
+	return 0
+}
+
+// exactInCircleSign returns the sign of (B-A)x(C-A).(D-A) computed using
+// exact arithmetic.
+func exactInCircleSign(a, b, c, d Point) int {
+	xa := r3.PreciseVectorFromVector(a.Vector)
+	ba := r3.PreciseVectorFromVector(b.Vector).Sub(xa)
+	ca := r3.PreciseVectorFromVector(c.Vector).Sub(xa)
+	da := r3.PreciseVectorFromVector(d.Vector).Sub(xa)
+	return ba.Cross(ca).Dot(da).Sign()
+}
+
+// sphereInCircleSign is like inCircleSign, except that the points are first
+// projected onto the unit sphere. In other words it returns the sign of the
+// determinant (B'-A')x(C'-A').(D'-A'), where X' denotes X/|X|.
+//
+// Unlike inCircleSign, the result does not depend on the small errors in the
+// lengths of the points. This matters for closely spaced points, since
+// points whose spacing is d are cocircular to within a relative error of
+// about dblEpsilon/d^2 due to their length errors alone.
+func sphereInCircleSign(a, b, c, d Point) int {
+	if sign := triageSphereInCircleSign(a, b, c, d); sign != 0 {
+		return sign
+	}
+	return exactSphereInCircleSign(a, b, c, d)
+}
+
+// triageSphereInCircleSign returns the sign of (B'-A')x(C'-A').(D'-A') if it
+// can be determined with certainty using floating-point arithmetic, or 0
+// otherwise.
+func triageSphereInCircleSign(a, b, c, d Point) int {
+	na := a.Normalize()
+	ba := b.Normalize().Sub(na)
+	ca := c.Normalize().Sub(na)
+	da := d.Normalize().Sub(na)
+	det := ba.Cross(ca).Dot(da)
+
+	// In addition to the rounding errors of triageInCircleSign, each of the
+	// normalized points has an error of at most 2*dblEpsilon. Moving one of
+	// them by e changes the determinant by at most e times the sum of the
+	// pairwise products of the lengths of the edge vectors.
+	lba, lca, lda := ba.Norm(), ca.Norm(), da.Norm()
+	ba, ca, da = ba.Abs(), ca.Abs(), da.Abs()
+	permanent := da.X*(ba.Y*ca.Z+ba.Z*ca.Y) + da.Y*(ba.Z*ca.X+ba.X*ca.Z) +
+		da.Z*(ba.X*ca.Y+ba.Y*ca.X)
+	maxErr := 8*dblEpsilon*permanent + 16*dblEpsilon*(lba*lca+lba*lda+lca*lda)
+	if det > maxErr {
+		return 1
+	}
+	if det < -maxErr {
+		return -1
+	}
+	return 0
+}
+
+// exactSphereInCircleSign returns the sign of (B'-A')x(C'-A').(D'-A').
+// Multiplying by |A||B||C||D|, this is the sign of
+//
+//	|A|(B.C×D) - |B|(A.C×D) + |C|(A.B×D) - |D|(A.B×C),
+//
+// where the triple products are computed exactly and the square roots are
+// computed with increasing precision until the sign is certain. If the sign
+// is still uncertain at maxPrec bits of precision, the points are treated as
+// cocircular and 0 is returned.
+func exactSphereInCircleSign(a, b, c, d Point) int {
+	const maxPrec = 4096
+	// The determinant is zero if two of the points are equal, which would
+	// otherwise only be detected at the maximum precision.
+	if a == b || a == c || a == d || b == c || b == d || c == d {
+		return 0
+	}
+	xa := r3.PreciseVectorFromVector(a.Vector)
+	xb := r3.PreciseVectorFromVector(b.Vector)
+	xc := r3.PreciseVectorFromVector(c.Vector)
+	xd := r3.PreciseVectorFromVector(d.Vector)
+	norm2 := []*big.Float{xa.Norm2(), xb.Norm2(), xc.Norm2(), xd.Norm2()}
+	triple := []*big.Float{
+		xb.Dot(xc.Cross(xd)),
+		newBigFloat().Neg(xa.Dot(xc.Cross(xd))),
+		xa.Dot(xb.Cross(xd)),
+		newBigFloat().Neg(xa.Dot(xb.Cross(xc))),
+	}
+	for prec := uint(64); prec <= maxPrec; prec *= 4 {
+		sum := new(big.Float).SetPrec(prec)
+		bound := new(big.Float).SetPrec(prec)
+		for i, t := range triple {
+			term := new(big.Float).SetPrec(prec).Sqrt(norm2[i])
+			term.Mul(term, t)
+			sum.Add(sum, term)
+			bound.Add(bound, term.Abs(term))
+		}
+		// Each term has a relative error of at most 2^(1-prec) from the
+		// square root and the product, and each addition adds another
+		// 2^-prec relative to the sum of the absolute values.
+		bound.SetMantExp(bound, 3-int(prec))
+		if sum.Cmp(bound) > 0 {
+			return 1
+		}
+		if sum.Neg(sum).Cmp(bound) > 0 {
+			return -1
+		}
+	}
+	return 0
+}
+
 // TODO(roberts): Differences from C++
 // CompareEdgeDistance
 // CompareEdgeDirections
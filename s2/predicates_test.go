@@ -937,6 +937,86 @@ func TestPredicatesCompareDistanceConsistency(t *testing.T) {
 	}
 }
 
+func TestPredicatesInCircleSign(t *testing.T) {
+	// Four points on a circle of latitude, and points just inside and outside.
+	a := PointFromLatLng(LatLngFromDegrees(10, 0))
+	b := PointFromLatLng(LatLngFromDegrees(10, 90))
+	c := PointFromLatLng(LatLngFromDegrees(10, 180))
+	tests := []struct {
+		d    Point
+		want int
+	}{
+		{PointFromLatLng(LatLngFromDegrees(10, -90)), 0},
+		{PointFromLatLng(LatLngFromDegrees(10, 45)), 0},
+		{PointFromLatLng(LatLngFromDegrees(11, -90)), 1},
+		{PointFromLatLng(LatLngFromDegrees(90, 0)), 1},
+		{PointFromLatLng(LatLngFromDegrees(9, -90)), -1},
+		{PointFromLatLng(LatLngFromDegrees(-90, 0)), -1},
+		{Point{r3.Vector{0, 0, 0}}, -1},
+	}
+	for _, test := range tests {
+		if got := inCircleSign(a, b, c, test.d); got != test.want {
+			t.Errorf("inCircleSign(%v, %v, %v, %v) = %d, want %d", a, b, c, test.d, got, test.want)
+		}
+		// Reversing the orientation of the circle swaps inside and outside.
+		if got := inCircleSign(a, c, b, test.d); got != -test.want {
+			t.Errorf("inCircleSign(%v, %v, %v, %v) = %d, want %d", a, c, b, test.d, got, -test.want)
+		}
+	}
+
+	// The result must agree with the exact computation for random points
+	// that are nearly cocircular.
+	for iter := 0; iter < 1000; iter++ {
+		a, b, c := randomPoint(), randomPoint(), randomPoint()
+		// Choose a point on the circumcircle and perturb it slightly.
+		n := Point{b.Sub(a.Vector).Cross(c.Sub(a.Vector)).Normalize()}
+		d := InterpolateAtDistance(n.Distance(a), n, randomPoint())
+		d = Point{d.Add(randomPoint().Mul(1e-15 * randomFloat64())).Normalize()}
+		if got, want := inCircleSign(a, b, c, d), exactInCircleSign(a, b, c, d); got != want {
+			t.Errorf("inCircleSign(%v, %v, %v, %v) = %d, want %d", a, b, c, d, got, want)
+		}
+	}
+}
+
+func TestPredicatesSphereInCircleSign(t *testing.T) {
+	// Four vertices of a cube are exactly cocircular, even when some of them
+	// are scaled (unlike with inCircleSign).
+	a := Point{r3.Vector{1, 1, 1}}
+	b := Point{r3.Vector{-1, 1, 1}}
+	c := Point{r3.Vector{-1, -1, 1}}
+	d := Point{r3.Vector{2, -2, 2}}
+	if got := sphereInCircleSign(a, b, c, d); got != 0 {
+		t.Errorf("sphereInCircleSign(%v, %v, %v, %v) = %d, want 0", a, b, c, d, got)
+	}
+	if got := inCircleSign(a, b, c, d); got != 1 {
+		t.Errorf("inCircleSign(%v, %v, %v, %v) = %d, want 1", a, b, c, d, got)
+	}
+
+	// The result must not depend on the lengths of the points, and the
+	// triage must agree with the exact computation, for points that are
+	// nearly cocircular. Scaling by powers of two is exact.
+	for iter := 0; iter < 1000; iter++ {
+		a, b, c := randomPoint(), randomPoint(), randomPoint()
+		if iter%2 == 1 {
+			// Closely spaced points, where the errors in the lengths of the
+			// points are significant.
+			b = Point{a.Add(b.Mul(1e-7)).Normalize()}
+			c = Point{a.Add(c.Mul(1e-7)).Normalize()}
+		}
+		n := Point{b.Sub(a.Vector).Cross(c.Sub(a.Vector)).Normalize()}
+		d := InterpolateAtDistance(n.Distance(a), n, randomPoint())
+		d = Point{d.Add(randomPoint().Mul(1e-15 * randomFloat64())).Normalize()}
+		want := exactSphereInCircleSign(a, b, c, d)
+		if got := triageSphereInCircleSign(a, b, c, d); got != 0 && got != want {
+			t.Errorf("triageSphereInCircleSign(%v, %v, %v, %v) = %d, want %d", a, b, c, d, got, want)
+		}
+		sb, sc, sd := Point{b.Mul(2)}, Point{c.Mul(0.5)}, Point{d.Mul(4)}
+		if got := sphereInCircleSign(a, sb, sc, sd); got != want {
+			t.Errorf("sphereInCircleSign(%v, %v, %v, %v) = %d, want %d", a, sb, sc, sd, got, want)
+		}
+	}
+}
+
 func BenchmarkSign(b *testing.B) {
 	p1 := Point{r3.Vector{-3, -1, 4}}
 	p2 := Point{r3.Vector{2, -1, -3}}
@@ -0,0 +1,522 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"math"
+
+	"github.com/rubenpoppe/geo/r1"
+	"github.com/rubenpoppe/geo/r2"
+	"github.com/rubenpoppe/geo/s1"
+)
+
+// The methods in this file project whole polylines, loops and polygons. The
+// edges of a chain are tessellated with AppendProjected, which keeps each
+// vertex close to the previous one along the axes of the projection that
+// wrap, so the chain is continuous in "unwrapped" coordinates that may extend
+// beyond the range of Project. The chain is then cut where it crosses the
+// seam of the projection, which is the boundary of that range, and each
+// piece is shifted back into the range.
+
+// poleVertexOffset is the distance from the pole of the points that replace
+// a vertex at a pole when the projection maps the poles to infinity, as the
+// Mercator projection does. It is small enough to be invisible at any
+// reasonable scale, but large enough for the projection to be finite.
+const poleVertexOffset = 1e-6
+
+// ProjectPolyline returns the polyline as chains of planar edges in the
+// projection, which are within the tolerance of its geodesic edges.
+//
+// If the projection has an axis that wraps, the polyline is split into
+// several chains where it crosses the seam of the projection, such as the
+// antimeridian in the plate carree projection, so that every chain is within
+// the range of coordinates returned by Project. A polyline with a single
+// vertex is returned as a chain with one point.
+func (e *EdgeTessellator) ProjectPolyline(p *Polyline) [][]r2.Point {
+	if len(*p) == 0 {
+		return nil
+	}
+	chains := [][]r2.Point{e.ProjectChain(*p, false)}
+	for _, s := range e.seams() {
+		var cut [][]r2.Point
+		for _, chain := range chains {
+			cut = append(cut, s.cutChain(chain)...)
+		}
+		chains = cut
+	}
+	return chains
+}
+
+// ProjectLoop returns the loop as rings of planar edges in the projection,
+// which are within the tolerance of its geodesic edges. Rings do not repeat
+// their first vertex at the end. The interior of the loop is on the left of
+// the rings, as long as the projection preserves orientation.
+//
+// If the projection has an axis that wraps, a loop that crosses the seam of
+// the projection is split into several rings along the seam, so that every
+// ring is within the range of coordinates returned by Project. A loop that
+// goes around a pole of the projection, where the seam ends, does not close
+// when it is unwrapped, and is closed along the line through that pole. If
+// the projection maps the pole to infinity, as the Mercator projection does,
+// the line is half the wrap distance from the origin, where the Web Mercator
+// square ends, or beyond the loop if it extends further. Vertices exactly at
+// such a pole are replaced by points a few meters away from it.
+//
+// The empty loop has no rings. The full loop is returned as the rectangle
+// between the seams and the closing lines of both poles if the projection
+// wraps, and has no rings otherwise.
+func (e *EdgeTessellator) ProjectLoop(l *Loop) [][]r2.Point {
+	return e.projectLoops([]*Loop{l})[0]
+}
+
+// ProjectPolygon returns the loops of the polygon as rings in the projection
+// like ProjectLoop, in the order of the loops. The rings of holes are
+// reversed so that the interior of the polygon is on their left, which makes
+// the rings of shells counterclockwise and those of holes clockwise in a
+// projection that preserves orientation. The rings can be drawn with either
+// the nonzero or the even-odd fill rule.
+func (e *EdgeTessellator) ProjectPolygon(p *Polygon) [][]r2.Point {
+	var rings [][]r2.Point
+	for i, pieces := range e.projectLoops(p.Loops()) {
+		for _, ring := range pieces {
+			if p.Loop(i).IsHole() {
+				ring = reversedPoints(ring)
+			}
+			rings = append(rings, ring)
+		}
+	}
+	return rings
+}
+
+// projectLoops returns the rings of each loop. The loops are projected
+// together so that the lines along which loops around a pole are closed are
+// beyond all of them.
+func (e *EdgeTessellator) projectLoops(loops []*Loop) [][][]r2.Point {
+	seams := e.seams()
+	chains := make([][]r2.Point, len(loops))
+	// extent is the largest absolute coordinate of any chain along each axis.
+	var extent r2.Point
+	for i, l := range loops {
+		if l.IsEmpty() || l.IsFull() {
+			continue
+		}
+		chains[i] = e.ProjectChain(l.Vertices(), true)
+		for _, p := range chains[i] {
+			extent.X = math.Max(extent.X, math.Abs(p.X))
+			extent.Y = math.Max(extent.Y, math.Abs(p.Y))
+		}
+	}
+
+	rings := make([][][]r2.Point, len(loops))
+	for i, l := range loops {
+		var unwrapped [][]r2.Point
+		switch {
+		case l.IsEmpty():
+			continue
+		case l.IsFull():
+			unwrapped = append(unwrapped, e.fullRing(seams, extent))
+		default:
+			ring, around := e.closeRing(chains[i], seams, extent)
+			unwrapped = append(unwrapped, ring)
+			// A ring that does not go around a pole but is clockwise has the
+			// interior of the loop outside it, which includes both poles. The
+			// full ring makes it wind once around the points outside.
+			if !around && planarArea(ring) < 0 {
+				unwrapped = append(unwrapped, e.fullRing(seams, extent))
+			}
+		}
+
+		for _, ring := range unwrapped {
+			if ring == nil {
+				continue
+			}
+			pieces := [][]r2.Point{ring}
+			for _, s := range seams {
+				var cut [][]r2.Point
+				for _, piece := range pieces {
+					cut = append(cut, s.cutRing(piece)...)
+				}
+				pieces = cut
+			}
+			rings[i] = append(rings[i], pieces...)
+		}
+	}
+	return rings
+}
+
+// fullRing returns the counterclockwise ring around the whole range of the
+// projection, between its seams and the lines through the poles. It returns
+// nil unless exactly one axis of the projection wraps.
+func (e *EdgeTessellator) fullRing(seams []seam, extent r2.Point) []r2.Point {
+	if len(seams) != 1 {
+		return nil
+	}
+	s := seams[0]
+	// The corners are counterclockwise, which takes the closing lines in the
+	// opposite order when the y axis wraps.
+	lo, hi := e.poleCoordinate(s, -1, extent), e.poleCoordinate(s, 1, extent)
+	if s.axis == 1 {
+		lo, hi = hi, lo
+	}
+	return []r2.Point{
+		s.point(s.rng.Lo, lo),
+		s.point(s.rng.Hi, lo),
+		s.point(s.rng.Hi, hi),
+		s.point(s.rng.Lo, hi),
+	}
+}
+
+// closeRing returns the ring for the projected chain of a loop, without its
+// closing vertex, and whether the loop goes around a pole of the projection.
+// The chain of such a loop ends one wrap distance away from where it
+// started. It is rotated to start where it crosses the seam, so that it
+// spans exactly one copy of the range, and is closed along the line through
+// the pole.
+func (e *EdgeTessellator) closeRing(chain []r2.Point, seams []seam, extent r2.Point) (ring []r2.Point, around bool) {
+	n := len(chain)
+	first, last := chain[0], chain[n-1]
+	for _, s := range seams {
+		turns := math.Round((s.coord(last) - s.coord(first)) / s.rng.Length())
+		if turns == 0 {
+			continue
+		}
+		shift := s.coord(last) - s.coord(first)
+
+		// Find the first edge that crosses the seam. There is one, since the
+		// chain spans the length of the range.
+		i := 0
+		for ; i+2 < n && s.strip(s.coord(chain[i])) == s.strip(s.coord(chain[i+1])); i++ {
+		}
+		ka, kb := s.strip(s.coord(chain[i])), s.strip(s.coord(chain[i+1]))
+		c := s.rng.Lo + float64(maxInt(ka, kb))*s.rng.Length()
+		start := s.crossing(chain[i], chain[i+1], c)
+
+		ring = []r2.Point{start}
+		for _, p := range chain[i+1:] {
+			ring = appendPoint(ring, p)
+		}
+		for _, p := range chain[1 : i+1] {
+			ring = appendPoint(ring, s.point(s.coord(p)+shift, s.other(p)))
+		}
+		end := s.point(s.coord(start)+shift, s.other(start))
+		ring = appendPoint(ring, end)
+
+		// The interior is on the left of the loop. That is the positive y side
+		// for a loop going in the positive x direction, and the negative x side
+		// for a loop going in the positive y direction.
+		side := turns
+		if s.axis == 1 {
+			side = -side
+		}
+		pole := e.poleCoordinate(s, side, extent)
+		return append(ring, s.point(s.coord(end), pole), s.point(s.coord(start), pole)), true
+	}
+	return chain[:n-1], false
+}
+
+// poleCoordinate returns the coordinate along the axis that does not wrap of
+// the line through the pole on the given side of the seam s. If the
+// projection maps the north and south poles to finite points on the side of
+// the seam, their coordinate is used, and otherwise the line is half the wrap
+// distance from the origin or beyond the given extent of the chains.
+func (e *EdgeTessellator) poleCoordinate(s seam, side float64, extent r2.Point) float64 {
+	if s.axis == 0 {
+		p := e.projection.Project(PointFromCoords(0, 0, side))
+		if !math.IsInf(p.Y, 0) && !math.IsNaN(p.Y) && p.Y*side > 0 {
+			return p.Y
+		}
+		return side * math.Max(s.rng.Length()/2, extent.Y)
+	}
+	return side * math.Max(s.rng.Length()/2, extent.X)
+}
+
+// ProjectChain tessellates the edges between the given vertices like
+// AppendProjected, and returns the chain in unwrapped coordinates, i.e.
+// without splitting it at the seams of the projection. If closed is true, the
+// chain includes the edge from the last vertex back to the first. A single
+// vertex is returned as a chain with one point.
+//
+// If the projection maps the poles to infinity, as the Mercator projection
+// does, each vertex at a pole is replaced by points a few meters away from it
+// at the longitudes of its neighbors, like in ProjectLoop.
+func (e *EdgeTessellator) ProjectChain(vertices []Point, closed bool) []r2.Point {
+	pole := e.projection.Project(PointFromCoords(0, 0, 1))
+	if math.IsInf(pole.X, 0) || math.IsInf(pole.Y, 0) {
+		vertices = e.replacePoleVertices(vertices, closed)
+	}
+	if len(vertices) == 1 {
+		return []r2.Point{e.projection.Project(vertices[0])}
+	}
+	var chain []r2.Point
+	for i := 0; i+1 < len(vertices); i++ {
+		chain = e.AppendProjected(vertices[i], vertices[i+1], chain)
+	}
+	if closed {
+		chain = e.AppendProjected(vertices[len(vertices)-1], vertices[0], chain)
+	}
+	return chain
+}
+
+// replacePoleVertices replaces each vertex at a pole, whose projection is
+// infinite, by points close to the pole at the longitudes of its neighbors.
+// The edges then follow the same meridians towards the pole, and cross from
+// one to the other right next to it.
+func (e *EdgeTessellator) replacePoleVertices(vertices []Point, closed bool) []Point {
+	n := len(vertices)
+	isPole := func(p Point) bool {
+		q := e.projection.Project(p)
+		return math.IsInf(q.X, 0) || math.IsInf(q.Y, 0)
+	}
+	var out []Point
+	for i, v := range vertices {
+		if !isPole(v) {
+			out = append(out, v)
+			continue
+		}
+		lat := s1.Angle(math.Pi/2 - poleVertexOffset)
+		if v.Z < 0 {
+			lat = -lat
+		}
+		var lngs []s1.Angle
+		for _, j := range []int{i - 1, i + 1} {
+			if closed {
+				j = (j + n) % n
+			}
+			if j >= 0 && j < n && j != i && !isPole(vertices[j]) {
+				lngs = append(lngs, LatLngFromPoint(vertices[j]).Lng)
+			}
+		}
+		if len(lngs) == 0 {
+			lngs = append(lngs, 0)
+		}
+		for _, lng := range lngs {
+			out = append(out, PointFromLatLng(LatLng{Lat: lat, Lng: lng}))
+		}
+	}
+	return out
+}
+
+// A seam is the boundary of the range of coordinates along an axis of a
+// projection that wraps. Coordinates in the range rng are returned by
+// Project, and coordinates outside it are equivalent to those shifted by a
+// multiple of its length.
+type seam struct {
+	axis int
+	rng  r1.Interval
+}
+
+// seams returns the seams of the projection, one for each axis that wraps.
+func (e *EdgeTessellator) seams() []seam {
+	var seams []seam
+	wrap := e.projection.WrapDistance()
+	for axis, period := range []float64{wrap.X, wrap.Y} {
+		if period > 0 {
+			seams = append(seams, seam{axis, e.wrapRange(axis, period)})
+		}
+	}
+	return seams
+}
+
+// wrapRange returns the range of coordinates along an axis that wraps with
+// the given period. Starting from a projected point, the coordinate along the
+// axis is increased until the projection wraps it around, which is found by
+// bisection. The end of the range is rounded to a small fraction of the
+// period, which makes it exact for projections whose range is centered at the
+// origin.
+func (e *EdgeTessellator) wrapRange(axis int, period float64) r1.Interval {
+	s := seam{axis: axis}
+	var q r2.Point
+	for _, p := range []Point{PointFromCoords(1, 0, 0), PointFromCoords(0, 1, 0), PointFromCoords(0, 0, 1)} {
+		if q = e.projection.Project(p); !math.IsInf(q.X, 0) && !math.IsInf(q.Y, 0) {
+			break
+		}
+	}
+	q0 := s.coord(q)
+	wrapped := func(t float64) bool {
+		p := e.projection.Project(e.projection.Unproject(s.point(q0+t, s.other(q))))
+		return s.coord(p) < q0+t-period/2
+	}
+	lo, hi := 0.0, period
+	for i := 0; i < 64; i++ {
+		mid := (lo + hi) / 2
+		if wrapped(mid) {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	const fraction = 1 << 32
+	end := period * math.Round((q0+hi)/period*fraction) / fraction
+	return r1.Interval{Lo: end - period, Hi: end}
+}
+
+// coord returns the coordinate of p along the axis of the seam.
+func (s seam) coord(p r2.Point) float64 {
+	if s.axis == 0 {
+		return p.X
+	}
+	return p.Y
+}
+
+// other returns the coordinate of p along the other axis.
+func (s seam) other(p r2.Point) float64 {
+	if s.axis == 0 {
+		return p.Y
+	}
+	return p.X
+}
+
+// point returns the point with coordinate c along the axis of the seam and
+// coordinate o along the other axis.
+func (s seam) point(c, o float64) r2.Point {
+	if s.axis == 0 {
+		return r2.Point{X: c, Y: o}
+	}
+	return r2.Point{X: o, Y: c}
+}
+
+// strip returns the number k of the copy of the range, shifted by k times
+// its length, that contains the coordinate c.
+func (s seam) strip(c float64) int {
+	return int(math.Floor((c - s.rng.Lo) / s.rng.Length()))
+}
+
+// shift returns p shifted from copy k of the range into the range.
+func (s seam) shift(p r2.Point, k int) r2.Point {
+	return s.point(s.coord(p)-float64(k)*s.rng.Length(), s.other(p))
+}
+
+// crossing returns the point where the segment from a to b crosses the line
+// at coordinate c along the axis of the seam.
+func (s seam) crossing(a, b r2.Point, c float64) r2.Point {
+	ca, cb := s.coord(a), s.coord(b)
+	if ca == c {
+		return a
+	}
+	if cb == c {
+		return b
+	}
+	t := (c - ca) / (cb - ca)
+	return s.point(c, s.other(a)+t*(s.other(b)-s.other(a)))
+}
+
+// cutChain cuts a chain in unwrapped coordinates where it crosses the seam,
+// and returns the pieces shifted into the range.
+func (s seam) cutChain(chain []r2.Point) [][]r2.Point {
+	k := s.strip(s.coord(chain[0]))
+	if len(chain) == 1 {
+		return [][]r2.Point{{s.shift(chain[0], k)}}
+	}
+	var chains [][]r2.Point
+	cur := []r2.Point{s.shift(chain[0], k)}
+	for i := 1; i < len(chain); i++ {
+		a, b := chain[i-1], chain[i]
+		for kb := s.strip(s.coord(b)); kb != k; {
+			next := k + 1
+			c := s.rng.Lo + float64(next)*s.rng.Length()
+			if kb < k {
+				next = k - 1
+				c = s.rng.Lo + float64(k)*s.rng.Length()
+			}
+			p := s.crossing(a, b, c)
+			if cur = appendPoint(cur, s.shift(p, k)); len(cur) > 1 {
+				chains = append(chains, cur)
+			}
+			cur = []r2.Point{s.shift(p, next)}
+			k = next
+		}
+		cur = appendPoint(cur, s.shift(b, k))
+	}
+	if len(cur) > 1 || len(chains) == 0 {
+		chains = append(chains, cur)
+	}
+	return chains
+}
+
+// cutRing cuts a ring in unwrapped coordinates along the seam, and returns
+// the pieces in each copy of the range shifted into the range. A piece may
+// have edges along the seam where the ring crosses it several times.
+func (s seam) cutRing(ring []r2.Point) [][]r2.Point {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, p := range ring {
+		lo, hi = math.Min(lo, s.coord(p)), math.Max(hi, s.coord(p))
+	}
+	var rings [][]r2.Point
+	for k := s.strip(lo); k <= s.strip(hi); k++ {
+		c := s.rng.Lo + float64(k)*s.rng.Length()
+		piece := s.clipRing(s.clipRing(ring, c, 1), c+s.rng.Length(), -1)
+		if len(piece) < 3 {
+			continue
+		}
+		// Skip pieces that only touch the copy of the range along its edge.
+		plo, phi := math.Inf(1), math.Inf(-1)
+		for i, p := range piece {
+			piece[i] = s.shift(p, k)
+			plo, phi = math.Min(plo, s.coord(p)), math.Max(phi, s.coord(p))
+		}
+		if plo < phi {
+			rings = append(rings, piece)
+		}
+	}
+	return rings
+}
+
+// clipRing returns the part of the ring where the coordinate along the axis
+// of the seam is at least c if dir is 1, or at most c if dir is -1.
+func (s seam) clipRing(ring []r2.Point, c, dir float64) []r2.Point {
+	inside := func(p r2.Point) bool { return dir*(s.coord(p)-c) >= 0 }
+	var out []r2.Point
+	for i, b := range ring {
+		a := ring[(i+len(ring)-1)%len(ring)]
+		if inside(a) != inside(b) {
+			out = appendPoint(out, s.crossing(a, b, c))
+		}
+		if inside(b) {
+			out = appendPoint(out, b)
+		}
+	}
+	if n := len(out); n > 1 && out[0] == out[n-1] {
+		out = out[:n-1]
+	}
+	return out
+}
+
+// appendPoint appends p to the points unless it is equal to the last one.
+func appendPoint(points []r2.Point, p r2.Point) []r2.Point {
+	if n := len(points); n > 0 && points[n-1] == p {
+		return points
+	}
+	return append(points, p)
+}
+
+// planarArea returns twice the signed area of the ring, which is positive
+// for counterclockwise rings.
+func planarArea(ring []r2.Point) float64 {
+	var sum float64
+	for i, a := range ring {
+		b := ring[(i+1)%len(ring)]
+		sum += a.Cross(b)
+	}
+	return sum
+}
+
+// reversedPoints returns the points in reverse order.
+func reversedPoints(points []r2.Point) []r2.Point {
+	out := make([]r2.Point, len(points))
+	for i, p := range points {
+		out[len(points)-1-i] = p
+	}
+	return out
+}
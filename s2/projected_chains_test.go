@@ -0,0 +1,317 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/rubenpoppe/geo/r2"
+	"github.com/rubenpoppe/geo/s1"
+)
+
+// windingNumber returns the number of times the rings wind counterclockwise
+// around p.
+func windingNumber(rings [][]r2.Point, p r2.Point) int {
+	w := 0
+	for _, ring := range rings {
+		for i, a := range ring {
+			b := ring[(i+1)%len(ring)]
+			side := b.Sub(a).Cross(p.Sub(a))
+			if a.Y <= p.Y && b.Y > p.Y && side > 0 {
+				w++
+			} else if a.Y > p.Y && b.Y <= p.Y && side < 0 {
+				w--
+			}
+		}
+	}
+	return w
+}
+
+func TestEdgeTessellatorProjectPolyline(t *testing.T) {
+	tess := NewEdgeTessellator(NewPlateCarreeProjection(180), 0.01*s1.Degree)
+	tests := []struct {
+		have string
+		want [][]r2.Point
+	}{
+		{"", nil},
+		{"0:-170", [][]r2.Point{{{-170, 0}}}},
+		{"0:10, 0:20", [][]r2.Point{{{10, 0}, {20, 0}}}},
+		{"0:170, 0:-170", [][]r2.Point{{{170, 0}, {180, 0}}, {{-180, 0}, {-170, 0}}}},
+		{"0:-170, 0:170", [][]r2.Point{{{-170, 0}, {-180, 0}}, {{180, 0}, {170, 0}}}},
+		{"0:170, 0:-170, 0:170", [][]r2.Point{
+			{{170, 0}, {180, 0}},
+			{{-180, 0}, {-170, 0}, {-180, 0}},
+			{{180, 0}, {170, 0}},
+		}},
+		{"0:170, 0:180", [][]r2.Point{{{170, 0}, {180, 0}}}},
+		{"0:180, 0:170", [][]r2.Point{{{180, 0}, {170, 0}}}},
+	}
+	for _, test := range tests {
+		got := tess.ProjectPolyline(makePolyline(test.have))
+		if len(got) != len(test.want) {
+			t.Errorf("ProjectPolyline(%q) = %v, want %v", test.have, got, test.want)
+			continue
+		}
+		for i := range got {
+			if !r2PointSlicesApproxEqual(got[i], test.want[i], 1e-12) {
+				t.Errorf("ProjectPolyline(%q) = %v, want %v", test.have, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestEdgeTessellatorProjectPolylineWrapRange(t *testing.T) {
+	// The chains must be within the range of Project, which for UTM in the
+	// southern hemisphere is offset by the false northing.
+	tests := []struct {
+		proj Projection
+		// center is the central meridian in degrees.
+		center float64
+	}{
+		{NewTransverseMercatorProjection(0, 1), 0},
+		{NewUTMProjection(33, false), 15},
+	}
+	for _, test := range tests {
+		proj := test.proj
+		tess := NewEdgeTessellator(proj, 1e-6*s1.Degree)
+		wrap := proj.WrapDistance().Y
+		rng := tess.wrapRange(1, wrap)
+		center := proj.Project(PointFromLatLng(LatLngFromDegrees(0, test.center))).Y
+		if !float64Near(rng.Center(), center, 1e-6*wrap) || !float64Near(rng.Length(), wrap, 1e-6*wrap) {
+			t.Errorf("wrapRange = %v, want centered at %v with length %v", rng, center, wrap)
+		}
+
+		// The seam is where the meridian opposite the central meridian crosses
+		// the equator.
+		far := LatLngFromDegrees(0, test.center+180).Normalized().Lng.Degrees()
+		polyline := Polyline{
+			PointFromLatLng(LatLngFromDegrees(10, far)),
+			PointFromLatLng(LatLngFromDegrees(-10, far)),
+		}
+		chains := tess.ProjectPolyline(&polyline)
+		if len(chains) != 2 {
+			t.Fatalf("ProjectPolyline crossing the seam returned %d chains, want 2", len(chains))
+		}
+		for _, chain := range chains {
+			for _, p := range chain {
+				if !rng.Contains(p.Y) {
+					t.Errorf("ProjectPolyline vertex %v is outside the range %v", p, rng)
+				}
+			}
+		}
+	}
+}
+
+func TestEdgeTessellatorProjectLoop(t *testing.T) {
+	plateCarree := NewEdgeTessellator(NewPlateCarreeProjection(180), 1e-3*s1.Degree)
+	mercator := NewEdgeTessellator(NewMercatorProjection(180), 1e-3*s1.Degree)
+	northPole := PointFromCoords(0, 0, 1)
+	southPole := PointFromCoords(0, 0, -1)
+
+	if got := plateCarree.ProjectLoop(EmptyLoop()); len(got) != 0 {
+		t.Errorf("ProjectLoop(EmptyLoop()) = %v, want no rings", got)
+	}
+	tests := []struct {
+		tess *EdgeTessellator
+		want []r2.Point
+	}{
+		{plateCarree, []r2.Point{{-180, -90}, {180, -90}, {180, 90}, {-180, 90}}},
+		{mercator, []r2.Point{{-180, -180}, {180, -180}, {180, 180}, {-180, 180}}},
+	}
+	for _, test := range tests {
+		got := test.tess.ProjectLoop(FullLoop())
+		if len(got) != 1 || !r2PointSlicesApproxEqual(got[0], test.want, 0) {
+			t.Errorf("ProjectLoop(FullLoop()) = %v, want %v", got, test.want)
+		}
+	}
+	if got := NewEdgeTessellator(NewGnomonicProjection(northPole), s1.Degree).ProjectLoop(FullLoop()); len(got) != 0 {
+		t.Errorf("ProjectLoop(FullLoop()) in the gnomonic projection = %v, want no rings", got)
+	}
+
+	// A loop across the antimeridian is split in two.
+	rings := plateCarree.ProjectLoop(makeLoop("-10:170, -10:-170, 10:-170, 10:170"))
+	if len(rings) != 2 {
+		t.Fatalf("ProjectLoop across the antimeridian returned %d rings, want 2", len(rings))
+	}
+	for _, ring := range rings {
+		minX, maxX := math.Inf(1), math.Inf(-1)
+		for _, p := range ring {
+			minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		}
+		if !(minX == -180 && maxX == -170 || minX == 170 && maxX == 180) {
+			t.Errorf("ring %v spans x from %v to %v, want one side of the antimeridian", ring, minX, maxX)
+		}
+		if planarArea(ring) <= 0 {
+			t.Errorf("ring %v is not counterclockwise", ring)
+		}
+	}
+
+	// Loops around a pole are closed along the line through it.
+	closing := []struct {
+		tess *EdgeTessellator
+		loop *Loop
+		y    float64
+	}{
+		{plateCarree, RegularLoop(northPole, 30*s1.Degree, 8), 90},
+		{plateCarree, RegularLoop(southPole, 30*s1.Degree, 8), -90},
+		{mercator, RegularLoop(northPole, 30*s1.Degree, 8), 180},
+		// Beyond the Web Mercator square, the ring is closed at its extent.
+		{mercator, RegularLoop(southPole, 2*s1.Degree, 8), math.NaN()},
+	}
+	for _, test := range closing {
+		rings := test.tess.ProjectLoop(test.loop)
+		if len(rings) != 1 {
+			t.Errorf("ProjectLoop around a pole returned %d rings, want 1", len(rings))
+			continue
+		}
+		ring := rings[0]
+		y := test.y
+		if math.IsNaN(y) {
+			y = math.Inf(1)
+			for _, p := range ring {
+				y = math.Min(y, p.Y)
+			}
+		}
+		// The ring ends with the closing line, from the seam back to the seam.
+		n := len(ring)
+		if a, b := ring[n-2], ring[n-1]; a.Y != y || b.Y != y || math.Abs(a.X-b.X) != 360 {
+			t.Errorf("ring %v ends with %v, %v, want a closing line at y = %v", ring, a, b, y)
+		}
+		if planarArea(ring) <= 0 {
+			t.Errorf("ring %v is not counterclockwise", ring)
+		}
+	}
+
+	// A vertex at the pole has no finite Mercator projection.
+	rings = mercator.ProjectLoop(makeLoop("90:0, 0:0, 0:90"))
+	if len(rings) != 1 {
+		t.Fatalf("ProjectLoop with a vertex at the pole returned %d rings, want 1", len(rings))
+	}
+	for _, p := range rings[0] {
+		if math.IsInf(p.X, 0) || math.IsInf(p.Y, 0) || math.IsNaN(p.X) || math.IsNaN(p.Y) {
+			t.Errorf("ProjectLoop with a vertex at the pole has vertex %v", p)
+		}
+	}
+	if planarArea(rings[0]) <= 0 {
+		t.Errorf("ring %v is not counterclockwise", rings[0])
+	}
+}
+
+func TestEdgeTessellatorReplacePoleVertices(t *testing.T) {
+	e := NewEdgeTessellator(NewMercatorProjection(0.5), s1.Degree)
+	north := PointFromCoords(0, 0, 1)
+	a := PointFromLatLng(LatLngFromDegrees(80, 10))
+	b := PointFromLatLng(LatLngFromDegrees(80, 100))
+
+	got := e.replacePoleVertices([]Point{a, north, b}, false)
+	if len(got) != 4 || got[0] != a || got[3] != b {
+		t.Fatalf("replacePoleVertices = %v, want a, two points near the pole, b", got)
+	}
+	for i, want := range []float64{10, 100} {
+		ll := LatLngFromPoint(got[i+1])
+		if math.Abs(ll.Lng.Degrees()-want) > 1e-9 || ll.Lat.Degrees() < 89.99 {
+			t.Errorf("replacePoleVertices[%d] = %v, want a point near the pole at longitude %v", i+1, ll, want)
+		}
+	}
+
+	// In a loop, the neighbors of the first vertex include the last one.
+	if got := e.replacePoleVertices([]Point{north, a, b}, true); len(got) != 4 {
+		t.Errorf("replacePoleVertices(loop) = %v, want 4 vertices", got)
+	}
+	if got := e.replacePoleVertices([]Point{a, b}, false); len(got) != 2 || got[0] != a || got[1] != b {
+		t.Errorf("replacePoleVertices without poles = %v, want %v", got, []Point{a, b})
+	}
+
+	// A single vertex at the pole is projected to a finite point.
+	if got := e.ProjectChain([]Point{north}, false); len(got) != 1 || math.IsInf(got[0].Y, 0) {
+		t.Errorf("ProjectChain(north pole) = %v, want one finite point", got)
+	}
+}
+
+func TestEdgeTessellatorProjectPolygonContainment(t *testing.T) {
+	// The rings of random polygons, some of which cross the seam or contain a
+	// pole, must wind once around the projection of the points inside the
+	// polygon, and not around any other point.
+	const tolerance = 1e-5
+	tests := []struct {
+		proj Projection
+		// inRange reports whether the projection of a point is between the
+		// lines along which rings around a pole are closed.
+		inRange func(p r2.Point) bool
+	}{
+		{NewPlateCarreeProjection(180), func(r2.Point) bool { return true }},
+		{NewMercatorProjection(180), func(p r2.Point) bool { return math.Abs(p.Y) < 180 }},
+		{NewTransverseMercatorProjection(0, 1), func(p r2.Point) bool { return math.Abs(p.X) < math.Pi }},
+	}
+	for _, test := range tests {
+		tess := NewEdgeTessellator(test.proj, tolerance)
+		for iter := 0; iter < 20; iter++ {
+			center := randomPoint()
+			radius := s1.Angle(randomUniformFloat64(0.1, 2.5))
+			var loops []*Loop
+			for i := 0; i < 3; i++ {
+				loops = append(loops, RegularLoop(center, radius/s1.Angle(int(1)<<uint(i)), 10))
+			}
+			polygon := PolygonFromLoops(loops)
+			rings := tess.ProjectPolygon(polygon)
+			for j := 0; j < 200; j++ {
+				p := randomPoint()
+				pp := test.proj.Project(p)
+				if !test.inRange(pp) {
+					continue
+				}
+				near := false
+				for _, l := range loops {
+					for k := 0; k < l.NumVertices(); k++ {
+						if DistanceFromSegment(p, l.Vertex(k), l.Vertex(k+1)) < 100*tolerance {
+							near = true
+						}
+					}
+				}
+				if near {
+					continue
+				}
+				want := 0
+				if polygon.ContainsPoint(p) {
+					want = 1
+				}
+				if got := windingNumber(rings, pp); got != want {
+					t.Errorf("%T: winding number of rings of polygon with radius %v around %v at %v = %d, want %d",
+						test.proj, radius, LatLngFromPoint(center), LatLngFromPoint(p), got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestEdgeTessellatorProjectPolygon(t *testing.T) {
+	tess := NewEdgeTessellator(NewPlateCarreeProjection(180), 1e-3*s1.Degree)
+	if got := tess.ProjectPolygon(makePolygon("", false)); len(got) != 0 {
+		t.Errorf("ProjectPolygon(empty) = %v, want no rings", got)
+	}
+
+	// The shell crosses the antimeridian, and the hole is on one side of it.
+	rings := tess.ProjectPolygon(makePolygon("-10:170, -10:-170, 10:-170, 10:170; -5:172, -5:178, 5:178, 5:172", true))
+	if len(rings) != 3 {
+		t.Fatalf("ProjectPolygon returned %d rings, want 3", len(rings))
+	}
+	for i, ring := range rings {
+		if got, want := planarArea(ring) > 0, i < 2; got != want {
+			t.Errorf("ring %d is counterclockwise = %v, want %v", i, got, want)
+		}
+	}
+}
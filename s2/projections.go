@@ -18,11 +18,15 @@ import (
 	"math"
 
 	"github.com/rubenpoppe/geo/r2"
+	"github.com/rubenpoppe/geo/r3"
 	"github.com/rubenpoppe/geo/s1"
 )
 
 // Projection defines an interface for different ways of mapping between s2 and r2 Points.
 // It can also define the coordinate wrapping behavior along each axis.
+//
+// Projections may be implemented outside this package, for example to
+// tessellate edges with an EdgeTessellator in a projection of their own.
 type Projection interface {
 	// Project converts a point on the sphere to a projected 2D point.
 	Project(p Point) r2.Point
@@ -66,9 +70,6 @@ type Projection interface {
 	// Then this function would return [190, 20] for point B (reducing the edge
 	// length in the "x" direction from 340 to 20).
 	WrapDestination(a, b r2.Point) r2.Point
-
-	// We do not support implementations of this interface outside this package.
-	privateInterface()
 }
 
 // PlateCarreeProjection defines the "plate carree" (square plate) projection,
@@ -142,8 +143,6 @@ func (p *PlateCarreeProjection) WrapDestination(a, b r2.Point) r2.Point {
 	return wrapDestination(a, b, p.WrapDistance)
 }
 
-func (p *PlateCarreeProjection) privateInterface() {}
-
 // MercatorProjection defines the spherical Mercator projection. Google Maps
 // uses this projection together with WGS84 coordinates, in which case it is
 // known as the "Web Mercator" projection (see Wikipedia). This class makes
@@ -224,8 +223,6 @@ func (p *MercatorProjection) WrapDestination(a, b r2.Point) r2.Point {
 	return wrapDestination(a, b, p.WrapDistance)
 }
 
-func (p *MercatorProjection) privateInterface() {}
-
 func wrapDestination(a, b r2.Point, wrapDistance func() r2.Point) r2.Point {
 	wrap := wrapDistance()
 	x := b.X
@@ -239,3 +236,301 @@ func wrapDestination(a, b r2.Point, wrapDistance func() r2.Point) r2.Point {
 	}
 	return r2.Point{x, y}
 }
+
+// tangentFrame returns the unit vectors that point east and north in the
+// plane tangent to the sphere at p. At the poles, where east is undefined, it
+// is taken to be the direction of longitude 90 degrees.
+func tangentFrame(p Point) (east, north r3.Vector) {
+	east = r3.Vector{-p.Y, p.X, 0}
+	if east.Norm2() == 0 {
+		east = r3.Vector{0, 1, 0}
+	}
+	east = east.Normalize()
+	return east, p.Cross(east)
+}
+
+// GnomonicProjection projects points from the center of the sphere onto the
+// plane tangent to the sphere at a given center point, with the x and y axes
+// pointing east and north. Geodesics are straight lines in this projection,
+// so there are no points of inflection, and edges tessellated in it lie
+// exactly on the geodesic (though not uniformly parametrized, so the
+// EdgeTessellator still subdivides them).
+//
+// Only the hemisphere around the center can be projected; points 90 degrees
+// or more from the center have no projection. The coordinates are in units
+// of the sphere radius and do not wrap.
+type GnomonicProjection struct {
+	center, east, north r3.Vector
+}
+
+// NewGnomonicProjection constructs a gnomonic projection centered at the
+// given point.
+func NewGnomonicProjection(center Point) Projection {
+	east, north := tangentFrame(center)
+	return &GnomonicProjection{center.Vector, east, north}
+}
+
+// Project converts a point on the sphere to a projected 2D point.
+func (p *GnomonicProjection) Project(pt Point) r2.Point {
+	d := pt.Dot(p.center)
+	return r2.Point{pt.Dot(p.east) / d, pt.Dot(p.north) / d}
+}
+
+// Unproject converts a projected 2D point to a point on the sphere.
+func (p *GnomonicProjection) Unproject(pt r2.Point) Point {
+	return Point{p.center.Add(p.east.Mul(pt.X)).Add(p.north.Mul(pt.Y)).Normalize()}
+}
+
+// FromLatLng returns the LatLng projected into an R2 Point.
+func (p *GnomonicProjection) FromLatLng(ll LatLng) r2.Point {
+	return p.Project(PointFromLatLng(ll))
+}
+
+// ToLatLng returns the LatLng projected from the given R2 Point.
+func (p *GnomonicProjection) ToLatLng(pt r2.Point) LatLng {
+	return LatLngFromPoint(p.Unproject(pt))
+}
+
+// Interpolate returns the point obtained by interpolating the given
+// fraction of the distance along the line from A to B.
+func (p *GnomonicProjection) Interpolate(f float64, a, b r2.Point) r2.Point {
+	return a.Mul(1 - f).Add(b.Mul(f))
+}
+
+// WrapDistance reports the coordinate wrapping distance along each axis,
+// which is zero since neither axis wraps.
+func (p *GnomonicProjection) WrapDistance() r2.Point {
+	return r2.Point{0, 0}
+}
+
+// WrapDestination returns b, since the coordinates never wrap.
+func (p *GnomonicProjection) WrapDestination(a, b r2.Point) r2.Point {
+	return wrapDestination(a, b, p.WrapDistance)
+}
+
+// LambertAzimuthalEqualAreaProjection is the azimuthal projection centered at
+// a given point that preserves areas, with the x and y axes pointing east and
+// north. It maps the whole sphere except the antipode of the center to the
+// disc of radius 2, in units of the sphere radius, and its coordinates do not
+// wrap.
+//
+// Geodesics through the center are straight lines, and other geodesics bulge
+// away from the center. The scale along a geodesic changes with its distance
+// from the center, so an edge through the center whose ends are at different
+// distances from it is straight but not uniformly parametrized.
+type LambertAzimuthalEqualAreaProjection struct {
+	center, east, north r3.Vector
+}
+
+// NewLambertAzimuthalEqualAreaProjection constructs a Lambert azimuthal
+// equal-area projection centered at the given point.
+func NewLambertAzimuthalEqualAreaProjection(center Point) Projection {
+	east, north := tangentFrame(center)
+	return &LambertAzimuthalEqualAreaProjection{center.Vector, east, north}
+}
+
+// Project converts a point on the sphere to a projected 2D point.
+func (p *LambertAzimuthalEqualAreaProjection) Project(pt Point) r2.Point {
+	k := math.Sqrt(2 / (1 + pt.Dot(p.center)))
+	return r2.Point{k * pt.Dot(p.east), k * pt.Dot(p.north)}
+}
+
+// Unproject converts a projected 2D point to a point on the sphere. Points
+// outside the disc of radius 2 are mapped to the antipode of the center.
+func (p *LambertAzimuthalEqualAreaProjection) Unproject(pt r2.Point) Point {
+	// A point at distance rho from the origin is at angle c from the center,
+	// where rho = 2 sin(c/2). This avoids computing c itself.
+	rho2 := math.Min(pt.Dot(pt), 4)
+	tangent := p.east.Mul(pt.X).Add(p.north.Mul(pt.Y)).Mul(math.Sqrt(1 - rho2/4))
+	return Point{p.center.Mul(1 - rho2/2).Add(tangent).Normalize()}
+}
+
+// FromLatLng returns the LatLng projected into an R2 Point.
+func (p *LambertAzimuthalEqualAreaProjection) FromLatLng(ll LatLng) r2.Point {
+	return p.Project(PointFromLatLng(ll))
+}
+
+// ToLatLng returns the LatLng projected from the given R2 Point.
+func (p *LambertAzimuthalEqualAreaProjection) ToLatLng(pt r2.Point) LatLng {
+	return LatLngFromPoint(p.Unproject(pt))
+}
+
+// Interpolate returns the point obtained by interpolating the given
+// fraction of the distance along the line from A to B.
+func (p *LambertAzimuthalEqualAreaProjection) Interpolate(f float64, a, b r2.Point) r2.Point {
+	return a.Mul(1 - f).Add(b.Mul(f))
+}
+
+// WrapDistance reports the coordinate wrapping distance along each axis,
+// which is zero since neither axis wraps.
+func (p *LambertAzimuthalEqualAreaProjection) WrapDistance() r2.Point {
+	return r2.Point{0, 0}
+}
+
+// WrapDestination returns b, since the coordinates never wrap.
+func (p *LambertAzimuthalEqualAreaProjection) WrapDestination(a, b r2.Point) r2.Point {
+	return wrapDestination(a, b, p.WrapDistance)
+}
+
+// earthRadiusMeters is the mean radius of the Earth used by the UTM
+// projections.
+const earthRadiusMeters = 6371010.0
+
+// TransverseMercatorProjection is the spherical Mercator projection rotated
+// so that a central meridian takes the place of the equator. The x axis
+// points east across the central meridian and the y axis north along it.
+//
+// The y coordinate follows the central meridian over the poles and around
+// the sphere, so it wraps. The x coordinate is infinite on the meridian 90
+// degrees from the central one, on the equator. Edges that cross the central
+// meridian curve towards it on both sides, and so have a point of inflection
+// where they cross it.
+type TransverseMercatorProjection struct {
+	// sinLng and cosLng rotate the central meridian to longitude 0.
+	sinLng, cosLng float64
+
+	// scale is the number of coordinates per radian, and falseEasting and
+	// falseNorthing are the coordinates of the point where the central
+	// meridian crosses the equator.
+	scale                       float64
+	falseEasting, falseNorthing float64
+}
+
+// NewTransverseMercatorProjection constructs a transverse Mercator projection
+// with the given central meridian, whose coordinates are scale times their
+// values in radians. The y coordinate wraps with a period of 2*Pi*scale.
+func NewTransverseMercatorProjection(centralMeridian s1.Angle, scale float64) Projection {
+	return &TransverseMercatorProjection{
+		sinLng: math.Sin(centralMeridian.Radians()),
+		cosLng: math.Cos(centralMeridian.Radians()),
+		scale:  scale,
+	}
+}
+
+// NewUTMProjection constructs the transverse Mercator projection of the
+// given Universal Transverse Mercator zone, from 1 to 60, for the northern or
+// southern hemisphere. The coordinates are eastings and northings in meters,
+// with the scale factor and false easting and northing of UTM.
+//
+// The projection is spherical, with the mean radius of the Earth, and so
+// differs from UTM coordinates on the WGS84 ellipsoid by up to about half a
+// percent. It is intended for tessellating edges in a zone, not for exact
+// UTM coordinates.
+func NewUTMProjection(zone int, north bool) Projection {
+	centralMeridian := s1.Angle(6*zone-183) * s1.Degree
+	p := &TransverseMercatorProjection{
+		sinLng:       math.Sin(centralMeridian.Radians()),
+		cosLng:       math.Cos(centralMeridian.Radians()),
+		scale:        0.9996 * earthRadiusMeters,
+		falseEasting: 500000,
+	}
+	if !north {
+		p.falseNorthing = 10000000
+	}
+	return p
+}
+
+// Project converts a point on the sphere to a projected 2D point.
+func (p *TransverseMercatorProjection) Project(pt Point) r2.Point {
+	// Rotate the central meridian to longitude 0, where the projection is
+	// x = atanh(y) and y = atan2(z, x).
+	x := pt.X*p.cosLng + pt.Y*p.sinLng
+	y := pt.Y*p.cosLng - pt.X*p.sinLng
+	y /= pt.Norm()
+	return r2.Point{
+		p.falseEasting + p.scale*math.Atanh(y),
+		p.falseNorthing + p.scale*math.Atan2(pt.Z, x),
+	}
+}
+
+// Unproject converts a projected 2D point to a point on the sphere.
+func (p *TransverseMercatorProjection) Unproject(pt r2.Point) Point {
+	x := (pt.X - p.falseEasting) / p.scale
+	y := (pt.Y - p.falseNorthing) / p.scale
+	sech := 1 / math.Cosh(x)
+	u, v, z := sech*math.Cos(y), math.Tanh(x), sech*math.Sin(y)
+	return Point{r3.Vector{u*p.cosLng - v*p.sinLng, v*p.cosLng + u*p.sinLng, z}.Normalize()}
+}
+
+// FromLatLng returns the LatLng projected into an R2 Point.
+func (p *TransverseMercatorProjection) FromLatLng(ll LatLng) r2.Point {
+	return p.Project(PointFromLatLng(ll))
+}
+
+// ToLatLng returns the LatLng projected from the given R2 Point.
+func (p *TransverseMercatorProjection) ToLatLng(pt r2.Point) LatLng {
+	return LatLngFromPoint(p.Unproject(pt))
+}
+
+// Interpolate returns the point obtained by interpolating the given
+// fraction of the distance along the line from A to B.
+func (p *TransverseMercatorProjection) Interpolate(f float64, a, b r2.Point) r2.Point {
+	return a.Mul(1 - f).Add(b.Mul(f))
+}
+
+// WrapDistance reports the coordinate wrapping distance along each axis.
+// The y coordinate wraps once around the central meridian.
+func (p *TransverseMercatorProjection) WrapDistance() r2.Point {
+	return r2.Point{0, 2 * math.Pi * p.scale}
+}
+
+// WrapDestination wraps the points if needed to get the shortest edge.
+func (p *TransverseMercatorProjection) WrapDestination(a, b r2.Point) r2.Point {
+	return wrapDestination(a, b, p.WrapDistance)
+}
+
+// FaceProjection projects points onto the (u,v) coordinates of one face of
+// the cube used by cells. It is the gnomonic projection centered at the
+// center of the face, with the axes of the face, so geodesics are straight
+// lines just as in GnomonicProjection.
+//
+// Only points in the hemisphere around the face center can be projected.
+// The face itself is the square [-1,1]x[-1,1], but the coordinates extend
+// beyond it and do not wrap.
+type FaceProjection struct {
+	face int
+}
+
+// NewFaceProjection constructs the projection onto the given face, from 0
+// to 5.
+func NewFaceProjection(face int) Projection {
+	return &FaceProjection{face}
+}
+
+// Project converts a point on the sphere to a projected 2D point.
+func (p *FaceProjection) Project(pt Point) r2.Point {
+	u, v := validFaceXYZToUV(p.face, pt.Vector)
+	return r2.Point{u, v}
+}
+
+// Unproject converts a projected 2D point to a point on the sphere.
+func (p *FaceProjection) Unproject(pt r2.Point) Point {
+	return Point{faceUVToXYZ(p.face, pt.X, pt.Y).Normalize()}
+}
+
+// FromLatLng returns the LatLng projected into an R2 Point.
+func (p *FaceProjection) FromLatLng(ll LatLng) r2.Point {
+	return p.Project(PointFromLatLng(ll))
+}
+
+// ToLatLng returns the LatLng projected from the given R2 Point.
+func (p *FaceProjection) ToLatLng(pt r2.Point) LatLng {
+	return LatLngFromPoint(p.Unproject(pt))
+}
+
+// Interpolate returns the point obtained by interpolating the given
+// fraction of the distance along the line from A to B.
+func (p *FaceProjection) Interpolate(f float64, a, b r2.Point) r2.Point {
+	return a.Mul(1 - f).Add(b.Mul(f))
+}
+
+// WrapDistance reports the coordinate wrapping distance along each axis,
+// which is zero since neither axis wraps.
+func (p *FaceProjection) WrapDistance() r2.Point {
+	return r2.Point{0, 0}
+}
+
+// WrapDestination returns b, since the coordinates never wrap.
+func (p *FaceProjection) WrapDestination(a, b r2.Point) r2.Point {
+	return wrapDestination(a, b, p.WrapDistance)
+}
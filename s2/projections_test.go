@@ -20,6 +20,7 @@ import (
 
 	"github.com/rubenpoppe/geo/r2"
 	"github.com/rubenpoppe/geo/r3"
+	"github.com/rubenpoppe/geo/s1"
 )
 
 func TestPlateCarreeProjectionInterpolate(t *testing.T) {
@@ -125,3 +126,195 @@ func TestMercatorProjectionProjectUnproject(t *testing.T) {
 		}
 	}
 }
+
+func TestGnomonicProjectionProjectUnproject(t *testing.T) {
+	proj := NewGnomonicProjection(PointFromLatLng(LatLngFromDegrees(0, 0)))
+	tests := []struct {
+		have Point
+		want r2.Point
+	}{
+		{PointFromLatLng(LatLngFromDegrees(0, 0)), r2.Point{0, 0}},
+		{PointFromLatLng(LatLngFromDegrees(0, 45)), r2.Point{1, 0}},
+		{PointFromLatLng(LatLngFromDegrees(-45, 0)), r2.Point{0, -1}},
+		{PointFromLatLng(LatLngFromDegrees(60, 0)), r2.Point{0, math.Sqrt(3)}},
+	}
+	for _, test := range tests {
+		if got := proj.Project(test.have); !r2PointsApproxEqual(test.want, got, epsilon) {
+			t.Errorf("proj.Project(%v) = %v, want %v", test.have, got, test.want)
+		}
+		if got := proj.Unproject(test.want); !got.ApproxEqual(test.have) {
+			t.Errorf("proj.Unproject(%v) = %v, want %v", test.want, got, test.have)
+		}
+	}
+
+	// Geodesics are straight lines.
+	for i := 0; i < 100; i++ {
+		center := randomPoint()
+		proj := NewGnomonicProjection(center)
+		c := CapFromCenterAngle(center, 80*s1.Degree)
+		a, b := samplePointFromCap(c), samplePointFromCap(c)
+		pa, pb := proj.Project(a), proj.Project(b)
+		if got := proj.Unproject(pa); !got.ApproxEqual(a) {
+			t.Errorf("proj.Unproject(proj.Project(%v)) = %v", a, got)
+		}
+		f := randomFloat64()
+		x := proj.Unproject(proj.Interpolate(f, pa, pb))
+		if d := DistanceFromSegment(x, a, b); d > 1e-13 {
+			t.Errorf("point %v of the projected edge (%v, %v) is %v from the geodesic", f, a, b, d)
+		}
+	}
+}
+
+func TestGnomonicProjectionAtPole(t *testing.T) {
+	// At the pole, east is the direction of longitude 90 degrees.
+	proj := NewGnomonicProjection(PointFromCoords(0, 0, 1))
+	if got, want := proj.FromLatLng(LatLngFromDegrees(45, 90)), (r2.Point{1, 0}); !r2PointsApproxEqual(got, want, epsilon) {
+		t.Errorf("proj.FromLatLng(45:90) = %v, want %v", got, want)
+	}
+	if got, want := proj.FromLatLng(LatLngFromDegrees(45, 180)), (r2.Point{0, 1}); !r2PointsApproxEqual(got, want, epsilon) {
+		t.Errorf("proj.FromLatLng(45:180) = %v, want %v", got, want)
+	}
+}
+
+func TestLambertAzimuthalEqualAreaProjectionProjectUnproject(t *testing.T) {
+	proj := NewLambertAzimuthalEqualAreaProjection(PointFromLatLng(LatLngFromDegrees(0, 0)))
+	tests := []struct {
+		have Point
+		want r2.Point
+	}{
+		{PointFromLatLng(LatLngFromDegrees(0, 0)), r2.Point{0, 0}},
+		{PointFromLatLng(LatLngFromDegrees(0, 90)), r2.Point{math.Sqrt2, 0}},
+		{PointFromLatLng(LatLngFromDegrees(90, 0)), r2.Point{0, math.Sqrt2}},
+		{PointFromLatLng(LatLngFromDegrees(0, -60)), r2.Point{-1, 0}},
+	}
+	for _, test := range tests {
+		if got := proj.Project(test.have); !r2PointsApproxEqual(test.want, got, epsilon) {
+			t.Errorf("proj.Project(%v) = %v, want %v", test.have, got, test.want)
+		}
+		if got := proj.Unproject(test.want); !got.ApproxEqual(test.have) {
+			t.Errorf("proj.Unproject(%v) = %v, want %v", test.want, got, test.have)
+		}
+	}
+	if got, want := proj.Unproject(r2.Point{3, 0}), PointFromCoords(-1, 0, 0); !got.ApproxEqual(want) {
+		t.Errorf("proj.Unproject outside the disc = %v, want the antipode %v", got, want)
+	}
+
+	// The projection preserves the areas of small triangles.
+	for i := 0; i < 100; i++ {
+		center := randomPoint()
+		proj := NewLambertAzimuthalEqualAreaProjection(center)
+		c := CapFromCenterAngle(samplePointFromCap(CapFromCenterAngle(center, 150*s1.Degree)), 1e-4*s1.Degree)
+		a, b, d := samplePointFromCap(c), samplePointFromCap(c), samplePointFromCap(c)
+		if got := proj.Unproject(proj.Project(a)); !pointsApproxEqual(got, a, 1e-13) {
+			t.Errorf("proj.Unproject(proj.Project(%v)) = %v", a, got)
+		}
+		pa, pb, pd := proj.Project(a), proj.Project(b), proj.Project(d)
+		planar := math.Abs(pb.Sub(pa).Cross(pd.Sub(pa))) / 2
+		if want := PointArea(a, b, d); math.Abs(planar-want) > 1e-3*want {
+			t.Errorf("area of the projected triangle (%v, %v, %v) = %v, want %v", a, b, d, planar, want)
+		}
+	}
+}
+
+func TestTransverseMercatorProjectionProjectUnproject(t *testing.T) {
+	proj := NewTransverseMercatorProjection(30*s1.Degree, 180/math.Pi)
+	tests := []struct {
+		have Point
+		want r2.Point
+	}{
+		{PointFromLatLng(LatLngFromDegrees(0, 30)), r2.Point{0, 0}},
+		// The central meridian is the y axis, in degrees.
+		{PointFromLatLng(LatLngFromDegrees(45, 30)), r2.Point{0, 45}},
+		{PointFromLatLng(LatLngFromDegrees(-20, 30)), r2.Point{0, -20}},
+		// Over the pole, y continues beyond 90.
+		{PointFromLatLng(LatLngFromDegrees(80, -150)), r2.Point{0, 100}},
+		// The equator is the x axis, with the Mercator scale.
+		{PointFromLatLng(LatLngFromDegrees(0, 31)), r2.Point{math.Atanh(math.Sin(math.Pi/180)) * 180 / math.Pi, 0}},
+	}
+	for _, test := range tests {
+		if got := proj.Project(test.have); !r2PointsApproxEqual(test.want, got, 1e-13) {
+			t.Errorf("proj.Project(%v) = %v, want %v", test.have, got, test.want)
+		}
+		if got := proj.Unproject(test.want); !got.ApproxEqual(test.have) {
+			t.Errorf("proj.Unproject(%v) = %v, want %v", test.want, got, test.have)
+		}
+	}
+	if got := proj.FromLatLng(LatLngFromDegrees(0, 120)); !math.IsInf(got.X, 1) {
+		t.Errorf("proj.FromLatLng(0:120) = %v, want infinite x", got)
+	}
+
+	// The y coordinate wraps around the central meridian.
+	if got, want := proj.WrapDistance(), (r2.Point{0, 360}); !r2PointsApproxEqual(got, want, epsilon) {
+		t.Errorf("proj.WrapDistance() = %v, want %v", got, want)
+	}
+	if got, want := proj.WrapDestination(r2.Point{0, 170}, r2.Point{0, -170}), (r2.Point{0, 190}); !r2PointsApproxEqual(got, want, 1e-13) {
+		t.Errorf("proj.WrapDestination = %v, want %v", got, want)
+	}
+	for i := 0; i < 100; i++ {
+		p := randomPoint()
+		q := proj.Project(p)
+		if math.IsInf(q.X, 0) || math.Abs(q.X) > 100 {
+			continue
+		}
+		if got := proj.Unproject(q); !pointsApproxEqual(got, p, 1e-13) {
+			t.Errorf("proj.Unproject(proj.Project(%v)) = %v", p, got)
+		}
+		if got := proj.Unproject(r2.Point{q.X, q.Y + 360}); !pointsApproxEqual(got, p, 1e-13) {
+			t.Errorf("proj.Unproject(%v) = %v, want %v", r2.Point{q.X, q.Y + 360}, got, p)
+		}
+	}
+}
+
+func TestUTMProjection(t *testing.T) {
+	// Zone 31 has its central meridian at 3 degrees east.
+	north := NewUTMProjection(31, true)
+	south := NewUTMProjection(31, false)
+	k := 0.9996 * earthRadiusMeters
+	tests := []struct {
+		proj Projection
+		ll   LatLng
+		want r2.Point
+	}{
+		{north, LatLngFromDegrees(0, 3), r2.Point{500000, 0}},
+		{south, LatLngFromDegrees(0, 3), r2.Point{500000, 10000000}},
+		{north, LatLngFromDegrees(45, 3), r2.Point{500000, k * math.Pi / 4}},
+		{south, LatLngFromDegrees(-45, 3), r2.Point{500000, 10000000 - k*math.Pi/4}},
+		{north, LatLngFromDegrees(0, 4), r2.Point{500000 + k*math.Atanh(math.Sin(math.Pi/180)), 0}},
+		{NewUTMProjection(1, true), LatLngFromDegrees(0, -177), r2.Point{500000, 0}},
+		{NewUTMProjection(60, true), LatLngFromDegrees(0, 177), r2.Point{500000, 0}},
+	}
+	for _, test := range tests {
+		if got := test.proj.FromLatLng(test.ll); !r2PointsApproxEqual(got, test.want, 1e-6) {
+			t.Errorf("proj.FromLatLng(%v) = %v, want %v", test.ll, got, test.want)
+		}
+		if got := test.proj.ToLatLng(test.want); !got.ApproxEqual(test.ll) {
+			t.Errorf("proj.ToLatLng(%v) = %v, want %v", test.want, got, test.ll)
+		}
+	}
+}
+
+func TestFaceProjectionProjectUnproject(t *testing.T) {
+	for face := 0; face < 6; face++ {
+		proj := NewFaceProjection(face)
+		if got := proj.Unproject(r2.Point{0, 0}); got != unitNorm(face) {
+			t.Errorf("face %d: proj.Unproject(0, 0) = %v, want %v", face, got, unitNorm(face))
+		}
+		for i := 0; i < 100; i++ {
+			u, v := 2*randomFloat64()-1, 2*randomFloat64()-1
+			p := Point{faceUVToXYZ(face, u, v).Normalize()}
+			if got := proj.Project(p); !r2PointsApproxEqual(got, r2.Point{u, v}, 1e-14) {
+				t.Errorf("face %d: proj.Project(%v) = %v, want %v", face, p, got, r2.Point{u, v})
+			}
+			if got := proj.Unproject(r2.Point{u, v}); !got.ApproxEqual(p) {
+				t.Errorf("face %d: proj.Unproject(%v, %v) = %v, want %v", face, u, v, got, p)
+			}
+		}
+		// The center of a cell on the face projects to its center in (u,v)
+		// coordinates.
+		cell := CellFromCellID(CellIDFromFace(face).ChildBeginAtLevel(5).Advance(123))
+		uv := cell.BoundUV().Center()
+		if got := proj.Project(Point{faceUVToXYZ(face, uv.X, uv.Y).Normalize()}); !r2PointsApproxEqual(got, uv, 1e-14) {
+			t.Errorf("face %d: proj.Project of the center of %v = %v, want %v", face, cell.ID(), got, uv)
+		}
+	}
+}
@@ -445,14 +445,14 @@ func (r Rect) encode(e *encoder) {
 
 // Decode decodes a rectangle.
 func (r *Rect) Decode(rd io.Reader) error {
-	d := &decoder{r: asByteReader(rd)}
+	d := newDecoder(rd, DecodeOptions{})
 	r.decode(d)
 	return d.err
 }
 
 func (r *Rect) decode(d *decoder) {
 	if version := d.readUint8(); int8(version) != encodingVersion && d.err == nil {
-		d.err = fmt.Errorf("can't decode version %d; my version: %d", version, encodingVersion)
+		d.err = fmt.Errorf("%w: can't decode version %d; my version: %d", ErrDecodeVersion, version, encodingVersion)
 		return
 	}
 	r.Lat.Lo = d.readFloat64()
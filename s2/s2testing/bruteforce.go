@@ -0,0 +1,112 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2testing
+
+import (
+	"github.com/rubenpoppe/geo/s1"
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// The functions in this file are deliberately simple implementations of
+// queries that s2 answers using indexes. They take time proportional to the
+// number of edges (or pairs of edges), and are intended to be used as a
+// reference when testing the optimized versions.
+
+// ContainsPoint reports whether the given shape contains the point. Only
+// shapes of dimension 2 can contain points. Boundaries follow the
+// semi-open model used by s2, so every point is contained by exactly one of
+// a polygon and its complement.
+//
+// The result is computed by counting the edges crossed by a segment from the
+// shape's reference point to the given point.
+func ContainsPoint(shape s2.Shape, point s2.Point) bool {
+	if shape.Dimension() != 2 {
+		return false
+	}
+	refPoint := shape.ReferencePoint()
+	if refPoint.Point == point {
+		return refPoint.Contained
+	}
+	crosser := s2.NewEdgeCrosser(refPoint.Point, point)
+	inside := refPoint.Contained
+	for e := 0; e < shape.NumEdges(); e++ {
+		edge := shape.Edge(e)
+		inside = inside != crosser.EdgeOrVertexCrossing(edge.V0, edge.V1)
+	}
+	return inside
+}
+
+// DistanceToPoint returns the minimum distance from the point to the shape,
+// which is zero if the shape contains the point. It returns s1.InfAngle() if
+// the shape is empty.
+func DistanceToPoint(shape s2.Shape, point s2.Point) s1.Angle {
+	if ContainsPoint(shape, point) {
+		return 0
+	}
+	dist := s1.InfAngle()
+	for e := 0; e < shape.NumEdges(); e++ {
+		edge := shape.Edge(e)
+		if d := s2.DistanceFromSegment(point, edge.V0, edge.V1); d < dist {
+			dist = d
+		}
+	}
+	return dist
+}
+
+// DistanceBetweenShapes returns the minimum distance between any two points
+// of the given shapes, where the interiors of two-dimensional shapes are
+// included. It returns s1.InfAngle() if either shape is empty.
+func DistanceBetweenShapes(a, b s2.Shape) s1.Angle {
+	if a.IsEmpty() || b.IsEmpty() {
+		return s1.InfAngle()
+	}
+	// If either shape contains a point of the other, the distance is zero.
+	// Full shapes have no edges, so they are handled here as well.
+	if containsAnyPoint(a, b) || containsAnyPoint(b, a) {
+		return 0
+	}
+	dist := s1.InfAngle()
+	for i := 0; i < a.NumEdges(); i++ {
+		ea := a.Edge(i)
+		for j := 0; j < b.NumEdges(); j++ {
+			eb := b.Edge(j)
+			if s2.CrossingSign(ea.V0, ea.V1, eb.V0, eb.V1) != s2.DoNotCross {
+				return 0
+			}
+			pa, pb := s2.EdgePairClosestPoints(ea.V0, ea.V1, eb.V0, eb.V1)
+			if d := pa.Distance(pb); d < dist {
+				dist = d
+			}
+		}
+	}
+	return dist
+}
+
+// containsAnyPoint reports whether a contains any vertex of b, or any point
+// at all if b is full.
+func containsAnyPoint(a, b s2.Shape) bool {
+	if b.IsFull() {
+		return !a.IsEmpty()
+	}
+	if a.IsFull() {
+		return true
+	}
+	for e := 0; e < b.NumEdges(); e++ {
+		if ContainsPoint(a, b.Edge(e).V0) {
+			return true
+		}
+	}
+	return false
+}
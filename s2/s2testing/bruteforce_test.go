@@ -0,0 +1,95 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2testing
+
+import (
+	"testing"
+
+	"github.com/rubenpoppe/geo/s1"
+	"github.com/rubenpoppe/geo/s2"
+)
+
+func TestContainsPoint(t *testing.T) {
+	r := NewRand(1)
+	for i := 0; i < 20; i++ {
+		p := r.Polygon(r.Point(), 0.5, 1+r.Intn(4), 3+r.Intn(20))
+		for j := 0; j < 100; j++ {
+			// Test points near the polygon as well as random points.
+			q := r.PointInCap(p.CapBound())
+			if j%2 == 0 {
+				q = r.Point()
+			}
+			if got, want := ContainsPoint(p, q), p.ContainsPoint(q); got != want {
+				t.Errorf("ContainsPoint(%v, %v) = %v, want %v", p, q, got, want)
+			}
+		}
+	}
+
+	if !ContainsPoint(s2.FullPolygon(), r.Point()) {
+		t.Errorf("ContainsPoint(full polygon) = false, want true")
+	}
+	if ContainsPoint(&s2.Polyline{s2.PointFromCoords(1, 0, 0), s2.PointFromCoords(0, 1, 0)}, s2.PointFromCoords(1, 1, 0)) {
+		t.Errorf("ContainsPoint(polyline) = true, want false")
+	}
+}
+
+func TestDistanceToPoint(t *testing.T) {
+	r := NewRand(2)
+	for i := 0; i < 20; i++ {
+		p := r.Polygon(r.Point(), 0.5, 1+r.Intn(3), 3+r.Intn(10))
+		index := s2.NewShapeIndex()
+		index.Add(p)
+		query := s2.NewClosestEdgeQuery(index, s2.NewClosestEdgeQueryOptions().IncludeInteriors(true))
+		for j := 0; j < 20; j++ {
+			q := r.Point()
+			target := s2.NewMinDistanceToPointTarget(q)
+			got, want := DistanceToPoint(p, q), query.Distance(target).Angle()
+			if d := got - want; d > 1e-14 || d < -1e-14 {
+				t.Errorf("DistanceToPoint(%v, %v) = %v, want %v", p, q, got, want)
+			}
+		}
+	}
+	if got := DistanceToPoint(s2.PolygonFromLoops(nil), s2.PointFromCoords(1, 0, 0)); got != s1.InfAngle() {
+		t.Errorf("DistanceToPoint(empty polygon) = %v, want infinity", got)
+	}
+}
+
+func TestDistanceBetweenShapes(t *testing.T) {
+	p := func(lat, lng float64) s2.Point { return s2.PointFromLatLng(s2.LatLngFromDegrees(lat, lng)) }
+	square := s2.PolygonFromLoops([]*s2.Loop{s2.LoopFromPoints([]s2.Point{p(0, 0), p(0, 10), p(10, 10), p(10, 0)})})
+
+	tests := []struct {
+		name string
+		a, b s2.Shape
+		want s1.Angle
+	}{
+		{"disjoint polyline", square, &s2.Polyline{p(0, 20), p(10, 20)}, p(10, 10).Distance(p(10, 20))},
+		{"crossing polyline", square, &s2.Polyline{p(5, -5), p(5, 5)}, 0},
+		{"polyline inside", square, &s2.Polyline{p(2, 2), p(3, 3)}, 0},
+		{"point inside", &s2.PointVector{p(5, 5)}, square, 0},
+		{"points", &s2.PointVector{p(0, 0)}, &s2.PointVector{p(0, 1)}, s1.Degree},
+		{"full", s2.FullPolygon(), &s2.PointVector{p(0, 1)}, 0},
+		{"empty", s2.PolygonFromLoops(nil), square, s1.InfAngle()},
+	}
+	for _, test := range tests {
+		got := DistanceBetweenShapes(test.a, test.b)
+		if d := got - test.want; d > 1e-14 || d < -1e-14 {
+			t.Errorf("%s: DistanceBetweenShapes = %v, want %v", test.name, got, test.want)
+		}
+		if got2 := DistanceBetweenShapes(test.b, test.a); got2 != got {
+			t.Errorf("%s: DistanceBetweenShapes is not symmetric: %v vs %v", test.name, got, got2)
+		}
+	}
+}
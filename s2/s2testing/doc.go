@@ -0,0 +1,28 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package s2testing provides utilities for testing code that uses the s2
+package.
+
+It contains seeded generators for random geometry (points, caps, rectangles,
+cell IDs, loops, polygons and polylines), a generator for fractal loops in the
+style of the Koch snowflake, and simple brute-force reference implementations
+of containment and distance queries that can be used for differential testing
+against the optimized implementations in s2.
+
+All generators draw from a Rand, so that results are reproducible for a given
+seed.
+*/
+package s2testing
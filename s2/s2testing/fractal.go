@@ -0,0 +1,200 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2testing
+
+import (
+	"math"
+
+	"github.com/rubenpoppe/geo/r2"
+	"github.com/rubenpoppe/geo/s1"
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// Fractal generates loops that are similar to the Koch snowflake. The
+// snowflake is constructed by starting with an equilateral triangle and
+// recursively replacing each edge with four smaller edges, where the middle
+// two edges form a spike pointing outward.
+//
+// The fractal dimension controls how far the spikes protrude. The number of
+// subdivisions of each edge is chosen at random between MinLevel and
+// MaxLevel, which produces loops with an irregular but non-self-intersecting
+// boundary.
+type Fractal struct {
+	// MaxLevel is the maximum subdivision level of the fractal. A loop
+	// with maximum level n has at most 3*(4**n) edges.
+	MaxLevel int
+
+	// MinLevel is the minimum subdivision level of the fractal. A min level
+	// of 0 should be avoided since this creates a significant chance that
+	// none of the edges will end up subdivided. Negative values, or values
+	// greater than MaxLevel, are treated as equal to MaxLevel.
+	MinLevel int
+
+	// Dimension is the dimension of the fractal. A value of approximately
+	// 1.26 corresponds to the standard Koch curve. The value must lie in the
+	// range [1.0, 2.0).
+	Dimension float64
+}
+
+// NewFractal returns a Fractal for the standard Koch curve with a maximum
+// level of 0, i.e. a triangle.
+func NewFractal() *Fractal {
+	return &Fractal{
+		MaxLevel:  0,
+		MinLevel:  -1,
+		Dimension: math.Log(4) / math.Log(3), // standard Koch curve
+	}
+}
+
+// SetLevelForApproxMinEdges sets the min level to produce approximately the
+// given number of edges. The values are rounded to a nearby value of
+// 3*(4**n).
+func (f *Fractal) SetLevelForApproxMinEdges(minEdges int) {
+	// Map values in the range [3*(4**n)/2, 3*(4**n)*2) to level n.
+	f.MinLevel = int(math.Round(0.5 * math.Log2(float64(minEdges)/3)))
+}
+
+// SetLevelForApproxMaxEdges sets the max level to produce approximately the
+// given number of edges. The values are rounded to a nearby value of
+// 3*(4**n).
+func (f *Fractal) SetLevelForApproxMaxEdges(maxEdges int) {
+	// Map values in the range [3*(4**n)/2, 3*(4**n)*2) to level n.
+	f.MaxLevel = int(math.Round(0.5 * math.Log2(float64(maxEdges)/3)))
+}
+
+// edgeFraction returns the ratio of the sub-edge length to the original edge
+// length at each subdivision step.
+func (f *Fractal) edgeFraction() float64 {
+	return math.Pow(4.0, -1.0/f.Dimension)
+}
+
+// offsetFraction returns the distance from the original edge to the middle
+// vertex at each subdivision step, as a fraction of the original edge length.
+func (f *Fractal) offsetFraction() float64 {
+	return math.Sqrt(f.edgeFraction() - 0.25)
+}
+
+// MinRadiusFactor returns a lower bound on the ratio (Rmin / R), where R is
+// the radius passed to MakeLoop and Rmin is the minimum distance from the
+// fractal boundary to its center, where all distances are measured in the
+// tangent plane at the fractal's center. This can be used to inscribe
+// another geometric figure within the fractal without intersection.
+func (f *Fractal) MinRadiusFactor() float64 {
+	// The minimum radius is attained at one of the vertices created by the
+	// first subdivision step as long as the dimension is not too small (at
+	// least minDimensionForMinRadiusAtLevel1, see below). Otherwise we fall
+	// back on the incircle radius of the original triangle, which is always a
+	// lower bound (and is attained when dimension = 1).
+	//
+	// The value below was obtained by letting AE be an original triangle edge,
+	// letting ABCDE be the corresponding polyline after one subdivision step,
+	// and then letting BC be tangent to the inscribed circle at the center of
+	// the fractal O. This gives rise to a pair of similar triangles whose edge
+	// length ratios can be used to solve for the corresponding "edge fraction".
+	// This method is slightly conservative because it is computed using planar
+	// rather than spherical geometry. The value below is equal to
+	// -math.Log(4)/math.Log((2 + math.Cbrt(2) - math.Cbrt(4))/6).
+	const minDimensionForMinRadiusAtLevel1 = 1.0852230903040407
+	if f.Dimension >= minDimensionForMinRadiusAtLevel1 {
+		e := f.edgeFraction()
+		return math.Sqrt(1 + 3*e*(e-1))
+	}
+	return 0.5
+}
+
+// MaxRadiusFactor returns the ratio (Rmax / R), where R is the radius passed
+// to MakeLoop and Rmax is the maximum distance from the fractal boundary to
+// its center, where all distances are measured in the tangent plane at the
+// fractal's center. This can be used to inscribe the fractal within some
+// other geometric figure without intersection.
+func (f *Fractal) MaxRadiusFactor() float64 {
+	// The maximum radius is always attained at either an original triangle
+	// vertex or at a middle vertex from the first subdivision step.
+	return math.Max(1.0, f.offsetFraction()*math.Sqrt(3)+0.5)
+}
+
+// fractalBuilder holds the state used while generating a single loop.
+type fractalBuilder struct {
+	r                            *Rand
+	minLevel, maxLevel           int
+	edgeFraction, offsetFraction float64
+}
+
+// r2Vertices recursively subdivides the edge to the desired level given the
+// two endpoints (v0,v4) of an edge, and returns all vertices of the resulting
+// curve up to but not including the endpoint v4.
+func (b *fractalBuilder) r2Vertices(v0, v4 r2.Point, level int) []r2.Point {
+	if level >= b.minLevel && b.r.OneIn(b.maxLevel-level+1) {
+		// stop at this level
+		return []r2.Point{v0}
+	}
+
+	// Otherwise compute the intermediate vertices v1, v2, and v3.
+	dir := v4.Sub(v0)
+	v1 := v0.Add(dir.Mul(b.edgeFraction))
+	v2 := v0.Add(v4).Mul(0.5).Sub(dir.Ortho().Mul(b.offsetFraction))
+	v3 := v4.Sub(dir.Mul(b.edgeFraction))
+
+	// And recurse on the four sub-edges.
+	var vertices []r2.Point
+	vertices = append(vertices, b.r2Vertices(v0, v1, level+1)...)
+	vertices = append(vertices, b.r2Vertices(v1, v2, level+1)...)
+	vertices = append(vertices, b.r2Vertices(v2, v3, level+1)...)
+	vertices = append(vertices, b.r2Vertices(v3, v4, level+1)...)
+	return vertices
+}
+
+// R2Vertices returns the vertices of a fractal centered at the origin of the
+// plane with a nominal radius of 1, in CCW order.
+func (f *Fractal) R2Vertices(r *Rand) []r2.Point {
+	b := &fractalBuilder{
+		r:              r,
+		minLevel:       f.MinLevel,
+		maxLevel:       f.MaxLevel,
+		edgeFraction:   f.edgeFraction(),
+		offsetFraction: f.offsetFraction(),
+	}
+	if b.minLevel < 0 || b.minLevel > b.maxLevel {
+		b.minLevel = b.maxLevel
+	}
+
+	// The Koch "snowflake" consists of three Koch curves whose initial edges
+	// form an equilateral triangle.
+	v0 := r2.Point{X: 1.0, Y: 0.0}
+	v1 := r2.Point{X: -0.5, Y: math.Sqrt(3) / 2}
+	v2 := r2.Point{X: -0.5, Y: -math.Sqrt(3) / 2}
+	var vertices []r2.Point
+	vertices = append(vertices, b.r2Vertices(v0, v1, 0)...)
+	vertices = append(vertices, b.r2Vertices(v1, v2, 0)...)
+	vertices = append(vertices, b.r2Vertices(v2, v0, 0)...)
+	return vertices
+}
+
+// MakeLoop returns a fractal loop centered around the z-axis of the given
+// coordinate frame, with the first vertex in the direction of the positive
+// x-axis. In order to avoid self-intersections, the fractal is generated by
+// first drawing it in a 2D tangent plane to the unit sphere (touching at the
+// fractal's center point) and then projecting the edges onto the sphere.
+// This has the side effect of shrinking the fractal slightly compared to its
+// nominal radius.
+func (f *Fractal) MakeLoop(r *Rand, frame Frame, nominalRadius s1.Angle) *s2.Loop {
+	r2pts := f.R2Vertices(r)
+	verts := make([]s2.Point, 0, len(r2pts))
+	radius := nominalRadius.Radians()
+	for _, pt := range r2pts {
+		verts = append(verts, frame.FromFrame(s2.PointFromCoords(pt.X*radius, pt.Y*radius, 1)))
+	}
+	return s2.LoopFromPoints(verts)
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2testing
+
+import (
+	"math"
+	"testing"
+
+	"github.com/rubenpoppe/geo/s1"
+)
+
+// numVerticesAtLevel returns the number of vertices of a fractal in which
+// every edge is subdivided to the given level.
+func numVerticesAtLevel(level int) int {
+	return 3 * (1 << (2 * uint(level))) // 3*(4**level)
+}
+
+func TestFractal(t *testing.T) {
+	tests := []struct {
+		label     string
+		minLevel  int
+		maxLevel  int
+		dimension float64
+	}{
+		{"TriangleFractal", 7, 7, 1.0},
+		{"TriangleMultiFractal", 2, 6, 1.0},
+		{"SpaceFillingFractal", 4, 4, 1.999},
+		{"KochCurveFractal", 7, 7, math.Log(4) / math.Log(3)},
+		{"KochCurveMultiFractal", 4, 8, math.Log(4) / math.Log(3)},
+		{"CesaroFractal", 7, 7, 1.8},
+		{"CesaroMultiFractal", 3, 6, 1.8},
+	}
+
+	// The radius needs to be fairly small to avoid spherical distortions.
+	const nominalRadius = 0.001 // radians, or about 6km
+	// vertexError is an approximate bound on the error when computing vertex
+	// positions of the fractal.
+	const vertexError = 1e-14
+
+	r := NewRand(1)
+	for _, test := range tests {
+		f := NewFractal()
+		f.MinLevel = test.minLevel
+		f.MaxLevel = test.maxLevel
+		f.Dimension = test.dimension
+
+		frame := r.Frame()
+		loop := f.MakeLoop(r, frame, nominalRadius)
+		if err := loop.Validate(); err != nil {
+			t.Errorf("%s: fractal loop was not valid: %v", test.label, err)
+		}
+		if got, want := loop.NumVertices(), numVerticesAtLevel(test.minLevel); got < want {
+			t.Errorf("%s: number of vertices = %d, should be at least %d", test.label, got, want)
+		}
+		if got, want := loop.NumVertices(), numVerticesAtLevel(test.maxLevel); got > want {
+			t.Errorf("%s: number of vertices = %d, should be at most %d", test.label, got, want)
+		}
+
+		// Measure the radius of the fractal in the tangent plane at its center.
+		minRadius, maxRadius := math.Inf(1), 0.0
+		for i := 0; i < loop.NumVertices(); i++ {
+			radius := math.Tan(frame.Z.Angle(loop.Vertex(i).Vector).Radians())
+			minRadius = math.Min(minRadius, radius)
+			maxRadius = math.Max(maxRadius, radius)
+		}
+		// MinRadiusFactor is exact (to within numerical errors) unless the
+		// dimension is in the range (1.0, 1.09).
+		if got, want := f.MinRadiusFactor()*nominalRadius, minRadius; math.Abs(got-want) > vertexError {
+			t.Errorf("%s: MinRadiusFactor()*nominalRadius = %v, want %v", test.label, got, want)
+		}
+		if got, want := f.MaxRadiusFactor()*nominalRadius, maxRadius; math.Abs(got-want) > vertexError {
+			t.Errorf("%s: MaxRadiusFactor()*nominalRadius = %v, want %v", test.label, got, want)
+		}
+	}
+}
+
+func TestFractalSetLevel(t *testing.T) {
+	f := NewFractal()
+	f.SetLevelForApproxMaxEdges(3 * 256)
+	f.SetLevelForApproxMinEdges(3 * 16)
+	if f.MaxLevel != 4 || f.MinLevel != 2 {
+		t.Errorf("levels = [%d, %d], want [2, 4]", f.MinLevel, f.MaxLevel)
+	}
+
+	// The default fractal is a triangle.
+	if got := NewFractal().MakeLoop(NewRand(1), NewRand(2).Frame(), s1.Degree).NumVertices(); got != 3 {
+		t.Errorf("NewFractal().MakeLoop().NumVertices() = %d, want 3", got)
+	}
+}
+
+func TestFractalDeterministic(t *testing.T) {
+	f := NewFractal()
+	f.SetLevelForApproxMaxEdges(1000)
+	f.MinLevel = 1
+	frame := NewRand(1).Frame()
+	a := f.MakeLoop(NewRand(2), frame, s1.Degree)
+	b := f.MakeLoop(NewRand(2), frame, s1.Degree)
+	if !a.Equal(b) {
+		t.Errorf("fractals generated with the same seed are not equal")
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2testing
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/rubenpoppe/geo/s1"
+	"github.com/rubenpoppe/geo/s2"
+)
+
+const (
+	// maxLevel is the maximum level of an s2.CellID.
+	maxLevel = 30
+	// numFaces is the number of faces of the cube.
+	numFaces = 6
+	// posBits is the number of bits used to encode the position of a cell
+	// along the Hilbert curve.
+	posBits = 2*maxLevel + 1
+)
+
+// Rand generates random geometry. It wraps a *rand.Rand, so the generated
+// values are reproducible for a given seed. The embedded *rand.Rand can also
+// be used directly, for example with s2.SamplePointFromCap.
+//
+// A Rand is not safe for concurrent use.
+type Rand struct {
+	*rand.Rand
+}
+
+// NewRand returns a Rand seeded with the given value.
+func NewRand(seed int64) *Rand {
+	return &Rand{rand.New(rand.NewSource(seed))}
+}
+
+// Bits returns a uint64 with the given number of bits set to random values.
+// The number of bits must be at most 64.
+func (r *Rand) Bits(num int) uint64 {
+	if num >= 64 {
+		return r.Uint64()
+	}
+	return r.Uint64() & (1<<uint(num) - 1)
+}
+
+// UniformInt returns a uniformly distributed integer in the range [0,n).
+func (r *Rand) UniformInt(n int) int {
+	return r.Intn(n)
+}
+
+// UniformFloat64 returns a uniformly distributed value in the range
+// [min, max).
+func (r *Rand) UniformFloat64(min, max float64) float64 {
+	return min + r.Float64()*(max-min)
+}
+
+// OneIn returns true with a probability of 1/n.
+func (r *Rand) OneIn(n int) bool {
+	return r.Intn(n) == 0
+}
+
+// SkewedInt returns a number in the range [0,2^maxLog-1] with bias towards
+// smaller numbers.
+func (r *Rand) SkewedInt(maxLog int) int {
+	base := r.Intn(maxLog + 1)
+	return int(r.Bits(31) & (1<<uint(base) - 1))
+}
+
+// Point returns a random unit-length vector, distributed uniformly over the
+// sphere.
+func (r *Rand) Point() s2.Point {
+	// Choosing each coordinate from a normal distribution and normalizing
+	// gives a uniform distribution over the sphere.
+	return s2.PointFromCoords(r.NormFloat64(), r.NormFloat64(), r.NormFloat64())
+}
+
+// LatLng returns a random LatLng, distributed uniformly over the sphere.
+func (r *Rand) LatLng() s2.LatLng {
+	return s2.LatLngFromPoint(r.Point())
+}
+
+// Frame returns a right-handed coordinate frame (three orthonormal vectors)
+// for a randomly generated point.
+func (r *Rand) Frame() Frame {
+	return r.FrameAtPoint(r.Point())
+}
+
+// FrameAtPoint returns a right-handed coordinate frame using the given point
+// as the z-axis. The x- and y-axes are chosen at random such that (x,y,z) is
+// a right-handed coordinate frame (three orthonormal vectors).
+func (r *Rand) FrameAtPoint(z s2.Point) Frame {
+	x := s2.Point{Vector: z.Cross(r.Point().Vector).Normalize()}
+	y := s2.Point{Vector: z.Cross(x.Vector).Normalize()}
+	return Frame{X: x, Y: y, Z: z}
+}
+
+// Cap returns a cap with a random axis such that the log of its area is
+// uniformly distributed between the logs of the two given values. The log of
+// the cap angle is also approximately uniformly distributed.
+func (r *Rand) Cap(minArea, maxArea float64) s2.Cap {
+	capArea := maxArea * math.Pow(minArea/maxArea, r.Float64())
+	return s2.CapFromCenterArea(r.Point(), capArea)
+}
+
+// Rect returns the smallest rectangle containing two random points. Such
+// rectangles span every size from a single point to the full sphere.
+func (r *Rand) Rect() s2.Rect {
+	return s2.RectFromLatLng(r.LatLng()).AddPoint(r.LatLng())
+}
+
+// CellIDForLevel returns a random CellID at the given level. The
+// distribution is uniform over the space of cell ids, but only approximately
+// uniform over the surface of the sphere.
+func (r *Rand) CellIDForLevel(level int) s2.CellID {
+	face := r.Intn(numFaces)
+	pos := r.Bits(posBits)
+	return s2.CellIDFromFacePosLevel(face, pos, level)
+}
+
+// CellID returns a random CellID at a randomly chosen level. The
+// distribution is uniform over the space of cell ids, but only approximately
+// uniform over the surface of the sphere.
+func (r *Rand) CellID() s2.CellID {
+	return r.CellIDForLevel(r.Intn(maxLevel + 1))
+}
+
+// CellUnion returns a normalized CellUnion built from n randomly selected
+// cells.
+func (r *Rand) CellUnion(n int) s2.CellUnion {
+	var cu s2.CellUnion
+	for i := 0; i < n; i++ {
+		cu = append(cu, r.CellID())
+	}
+	cu.Normalize()
+	return cu
+}
+
+// PointInCap returns a point chosen uniformly at random (with respect to
+// area) from the given cap.
+func (r *Rand) PointInCap(c s2.Cap) s2.Point {
+	return s2.SamplePointFromCap(r.Rand, c)
+}
+
+// PointInRect returns a point chosen uniformly at random (with respect to
+// area) from the given rectangle.
+func (r *Rand) PointInRect(rect s2.Rect) s2.Point {
+	return s2.SamplePointFromRect(r.Rand, rect)
+}
+
+// Loop returns a random star-shaped loop with the given number of vertices
+// (at least 3), centered at the given point. Every vertex is between half
+// the given radius and the full radius from the center. The radius must be
+// less than π/2.
+func (r *Rand) Loop(center s2.Point, radius s1.Angle, numVertices int) *s2.Loop {
+	return r.starLoop(r.FrameAtPoint(center), 0.5*radius, radius, numVertices)
+}
+
+// starLoop returns a loop around the z-axis of the given frame whose vertices
+// are at random angles and at random distances in [minRadius, maxRadius] from
+// the center.
+func (r *Rand) starLoop(frame Frame, minRadius, maxRadius s1.Angle, numVertices int) *s2.Loop {
+	// Each vertex is placed at a random angle within its own sector, so the
+	// angles are increasing and consecutive angles differ by less than π.
+	// This makes the loop star-shaped with respect to its center, which
+	// guarantees that it does not self-intersect.
+	angles := make([]float64, numVertices)
+	for i := range angles {
+		angles[i] = 2 * math.Pi * (float64(i) + r.UniformFloat64(0.3, 0.7)) / float64(numVertices)
+	}
+
+	vertices := make([]s2.Point, numVertices)
+	for i, theta := range angles {
+		d := math.Tan(r.UniformFloat64(minRadius.Radians(), maxRadius.Radians()))
+		vertices[i] = frame.FromFrame(s2.PointFromCoords(d*math.Cos(theta), d*math.Sin(theta), 1))
+	}
+	return s2.LoopFromPoints(vertices)
+}
+
+// Polygon returns a random polygon centered at the given point, consisting
+// of numLoops nested star-shaped loops. Each loop is contained by the
+// previous one, so the loops alternate between shells and holes. The radius
+// must be less than π/2.
+func (r *Rand) Polygon(center s2.Point, radius s1.Angle, numLoops, verticesPerLoop int) *s2.Polygon {
+	// Each loop is strictly inside the circle that touches the closest edge
+	// of the previous loop, so the loops are nested.
+	frame := r.FrameAtPoint(center)
+	var loops []*s2.Loop
+	for i := 0; i < numLoops; i++ {
+		loop := r.starLoop(frame, 0.8*radius, radius, verticesPerLoop)
+		loops = append(loops, loop)
+		radius = s1.InfAngle()
+		for j := 0; j < loop.NumVertices(); j++ {
+			if d := s2.DistanceFromSegment(frame.Z, loop.Vertex(j), loop.Vertex(j+1)); d < radius {
+				radius = d
+			}
+		}
+		radius *= 0.9
+	}
+	return s2.PolygonFromLoops(loops)
+}
+
+// Polyline returns a random polyline with the given number of vertices. It
+// starts at a random point, and each edge has a random direction and a
+// length chosen uniformly from (0, maxEdge].
+func (r *Rand) Polyline(numVertices int, maxEdge s1.Angle) *s2.Polyline {
+	if numVertices == 0 {
+		return &s2.Polyline{}
+	}
+	line := s2.Polyline{r.Point()}
+	for len(line) < numVertices {
+		prev := line[len(line)-1]
+		// A random point on the great circle through prev, in a random
+		// direction.
+		dir := s2.Point{Vector: prev.Cross(r.Point().Vector).Normalize()}
+		length := s1.Angle(1-r.Float64()) * maxEdge
+		line = append(line, s2.Point{Vector: prev.Mul(math.Cos(length.Radians())).
+			Add(dir.Mul(math.Sin(length.Radians()))).Normalize()})
+	}
+	return &line
+}
+
+// Frame is a right-handed coordinate frame, given by three orthonormal
+// vectors.
+type Frame struct {
+	X, Y, Z s2.Point
+}
+
+// FromFrame returns the coordinates of the given point in the standard
+// basis, where p is expressed in the coordinates of this frame.
+func (f Frame) FromFrame(p s2.Point) s2.Point {
+	return s2.Point{Vector: f.X.Mul(p.X).Add(f.Y.Mul(p.Y)).Add(f.Z.Mul(p.Z)).Normalize()}
+}
+
+// ToFrame returns the coordinates of the given point with respect to this
+// frame.
+func (f Frame) ToFrame(p s2.Point) s2.Point {
+	return s2.PointFromCoords(p.Dot(f.X.Vector), p.Dot(f.Y.Vector), p.Dot(f.Z.Vector))
+}
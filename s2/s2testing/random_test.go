@@ -0,0 +1,189 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2testing
+
+import (
+	"math"
+	"testing"
+
+	"github.com/rubenpoppe/geo/s1"
+	"github.com/rubenpoppe/geo/s2"
+)
+
+func TestRandDeterministic(t *testing.T) {
+	a, b := NewRand(1), NewRand(1)
+	for i := 0; i < 10; i++ {
+		if p, q := a.Point(), b.Point(); p != q {
+			t.Errorf("Point() = %v and %v for the same seed", p, q)
+		}
+		if p, q := a.CellID(), b.CellID(); p != q {
+			t.Errorf("CellID() = %v and %v for the same seed", p, q)
+		}
+	}
+}
+
+func TestRandPoint(t *testing.T) {
+	r := NewRand(2)
+	var sum s2.Point
+	const n = 10000
+	for i := 0; i < n; i++ {
+		p := r.Point()
+		if !p.IsUnit() {
+			t.Errorf("Point() = %v is not unit length", p)
+		}
+		sum = s2.Point{Vector: sum.Add(p.Vector)}
+	}
+	// The mean of uniformly distributed points is close to the origin.
+	if got := sum.Norm() / n; got > 0.05 {
+		t.Errorf("norm of the mean of %d points = %v, want close to 0", n, got)
+	}
+}
+
+func TestRandFrame(t *testing.T) {
+	r := NewRand(3)
+	for i := 0; i < 10; i++ {
+		f := r.Frame()
+		for _, v := range []s2.Point{f.X, f.Y, f.Z} {
+			if !v.IsUnit() {
+				t.Errorf("frame axis %v is not unit length", v)
+			}
+		}
+		if math.Abs(f.X.Dot(f.Y.Vector)) > 1e-15 || math.Abs(f.Y.Dot(f.Z.Vector)) > 1e-15 || math.Abs(f.Z.Dot(f.X.Vector)) > 1e-15 {
+			t.Errorf("frame %v is not orthogonal", f)
+		}
+		if f.X.Cross(f.Y.Vector).Dot(f.Z.Vector) < 0 {
+			t.Errorf("frame %v is not right-handed", f)
+		}
+		p := r.Point()
+		if got := f.FromFrame(f.ToFrame(p)); !got.ApproxEqual(p) {
+			t.Errorf("FromFrame(ToFrame(%v)) = %v", p, got)
+		}
+	}
+}
+
+func TestRandCap(t *testing.T) {
+	r := NewRand(4)
+	for i := 0; i < 100; i++ {
+		c := r.Cap(1e-10, 1)
+		if got := c.Area(); got < 1e-10*(1-1e-9) || got > 1+1e-9 {
+			t.Errorf("Cap(1e-10, 1).Area() = %v, want in [1e-10, 1]", got)
+		}
+		if p := r.PointInCap(c); !c.ContainsPoint(p) {
+			t.Errorf("PointInCap(%v) = %v, not contained", c, p)
+		}
+	}
+}
+
+func TestRandRect(t *testing.T) {
+	r := NewRand(5)
+	for i := 0; i < 100; i++ {
+		rect := r.Rect()
+		if !rect.IsValid() || rect.IsEmpty() {
+			t.Errorf("Rect() = %v, want valid non-empty rect", rect)
+		}
+		if p := r.PointInRect(rect); !rect.ContainsPoint(p) {
+			t.Errorf("PointInRect(%v) = %v, not contained", rect, p)
+		}
+	}
+}
+
+func TestRandCellID(t *testing.T) {
+	r := NewRand(6)
+	for level := 0; level <= maxLevel; level++ {
+		id := r.CellIDForLevel(level)
+		if !id.IsValid() || id.Level() != level {
+			t.Errorf("CellIDForLevel(%d) = %v, want valid cell at level %d", level, id, level)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		if id := r.CellID(); !id.IsValid() {
+			t.Errorf("CellID() = %v is not valid", id)
+		}
+	}
+	if cu := r.CellUnion(50); !cu.IsNormalized() {
+		t.Errorf("CellUnion(50) = %v is not normalized", cu)
+	}
+}
+
+func TestRandSkewedInt(t *testing.T) {
+	r := NewRand(7)
+	for i := 0; i < 100; i++ {
+		if got := r.SkewedInt(10); got < 0 || got >= 1<<10 {
+			t.Errorf("SkewedInt(10) = %d, want in [0, 1024)", got)
+		}
+	}
+}
+
+func TestRandLoop(t *testing.T) {
+	r := NewRand(8)
+	for _, n := range []int{3, 4, 10, 100} {
+		center := r.Point()
+		l := r.Loop(center, 0.1, n)
+		if err := l.Validate(); err != nil {
+			t.Errorf("Loop(%d) is invalid: %v", n, err)
+		}
+		if l.NumVertices() != n {
+			t.Errorf("Loop(%d).NumVertices() = %d", n, l.NumVertices())
+		}
+		if !l.ContainsPoint(center) {
+			t.Errorf("Loop(%d) does not contain its center", n)
+		}
+		for _, v := range l.Vertices() {
+			if d := center.Distance(v); d < 0.05-1e-15 || d > 0.1+1e-15 {
+				t.Errorf("Loop(%d) vertex %v is %v from the center", n, v, d)
+			}
+		}
+	}
+}
+
+func TestRandPolygon(t *testing.T) {
+	r := NewRand(9)
+	for _, numLoops := range []int{1, 2, 5} {
+		for _, n := range []int{3, 4, 20} {
+			p := r.Polygon(r.Point(), 1, numLoops, n)
+			if err := p.Validate(); err != nil {
+				t.Errorf("Polygon(%d, %d) is invalid: %v", numLoops, n, err)
+			}
+			if got := p.NumLoops(); got != numLoops {
+				t.Errorf("Polygon(%d, %d).NumLoops() = %d", numLoops, n, got)
+			}
+			for i := 0; i < p.NumLoops(); i++ {
+				if got, want := p.Loop(i).IsHole(), i%2 == 1; got != want {
+					t.Errorf("Polygon(%d, %d).Loop(%d).IsHole() = %v, want %v", numLoops, n, i, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestRandPolyline(t *testing.T) {
+	r := NewRand(10)
+	maxEdge := 2 * s1.Degree
+	line := r.Polyline(100, maxEdge)
+	if err := line.Validate(); err != nil {
+		t.Errorf("Polyline(100) is invalid: %v", err)
+	}
+	if len(*line) != 100 {
+		t.Errorf("len(Polyline(100)) = %d", len(*line))
+	}
+	for i := 1; i < len(*line); i++ {
+		if d := (*line)[i-1].Distance((*line)[i]); d > maxEdge+1e-15 {
+			t.Errorf("edge %d has length %v, want <= %v", i-1, d, maxEdge)
+		}
+	}
+	if got := r.Polyline(0, maxEdge); len(*got) != 0 {
+		t.Errorf("Polyline(0) = %v, want empty", got)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/rubenpoppe/geo/s1"
+)
+
+// maxSampleAttempts is the number of candidate points a RegionSampler tries
+// before concluding that the region has no area.
+const maxSampleAttempts = 100000
+
+// SamplePointFromCap returns a point chosen uniformly at random (with respect
+// to area on the sphere) from the given cap. The cap must not be empty.
+func SamplePointFromCap(r *rand.Rand, c Cap) Point {
+	// We consider the cap axis to be the "z" axis. We choose two other axes to
+	// complete the coordinate frame.
+	m := getFrame(c.Center())
+
+	// The surface area of a spherical cap is directly proportional to its
+	// height. First we choose a random height, and then we choose a random
+	// point along the circle at that height.
+	h := r.Float64() * c.Height()
+	theta := 2 * math.Pi * r.Float64()
+	radius := math.Sqrt(h * (2 - h))
+
+	// The result should already be very close to unit-length, but we might as
+	// well make it accurate as possible.
+	return Point{fromFrame(m, PointFromCoords(math.Cos(theta)*radius, math.Sin(theta)*radius, 1-h)).Normalize()}
+}
+
+// SamplePointFromRect returns a point chosen uniformly at random (with respect
+// to area on the sphere) from the given rectangle. The rectangle must not be
+// empty.
+func SamplePointFromRect(r *rand.Rand, rect Rect) Point {
+	// First choose a latitude uniformly with respect to area on the sphere.
+	sinLo := math.Sin(rect.Lat.Lo)
+	sinHi := math.Sin(rect.Lat.Hi)
+	lat := math.Asin(sinLo + r.Float64()*(sinHi-sinLo))
+
+	// Now choose longitude uniformly within the given range.
+	lng := rect.Lng.Lo + r.Float64()*rect.Lng.Length()
+
+	return PointFromLatLng(LatLng{s1.Angle(lat), s1.Angle(lng)}.Normalized())
+}
+
+// SamplePointFromCell returns a point chosen uniformly at random (with respect
+// to area on the sphere) from the given cell.
+func SamplePointFromCell(r *rand.Rand, c Cell) Point {
+	// The bounding cap of a cell is at most a few times larger than the cell,
+	// so rejection sampling from the cap is efficient.
+	bound := c.CapBound()
+	for {
+		if p := SamplePointFromCap(r, bound); c.ContainsPoint(p) {
+			return p
+		}
+	}
+}
+
+// SamplePointOnPolyline returns a point chosen uniformly at random with
+// respect to length along the given polyline. The polyline must not be empty.
+func SamplePointOnPolyline(r *rand.Rand, p *Polyline) Point {
+	point, _ := p.Interpolate(r.Float64())
+	return point
+}
+
+// RegionSampler generates points distributed uniformly with respect to area
+// within a Region, such as a Polygon, Cap, Rect or CellUnion.
+//
+// The region is first covered with cells using a RegionCoverer. Points are
+// then generated by choosing a covering cell with probability proportional
+// to its area, choosing a point uniformly within that cell, and rejecting
+// points that are not contained by the region. Since the cells of a
+// covering do not overlap, the result is uniform over the region.
+//
+// Regions with zero area (such as polylines) cannot be sampled this way;
+// use SamplePointOnPolyline instead.
+type RegionSampler struct {
+	region Region
+	cells  []Cell
+	// contained reports whether each cell is known to be contained by the
+	// region, in which case no containment test is needed.
+	contained []bool
+	// cumulative[i] is the total area of cells[0..i].
+	cumulative []float64
+}
+
+// NewRegionSampler returns a sampler for the given region, using a covering
+// computed by a RegionCoverer with default options.
+func NewRegionSampler(region Region) *RegionSampler {
+	return NewRegionSamplerWithCoverer(region, NewRegionCoverer())
+}
+
+// NewRegionSamplerWithCoverer returns a sampler for the given region that
+// uses the given RegionCoverer to compute its covering. Coverings with more
+// cells fit the region more closely, which reduces the number of rejected
+// points.
+func NewRegionSamplerWithCoverer(region Region, rc *RegionCoverer) *RegionSampler {
+	var covering CellUnion
+	if cu, ok := region.(*CellUnion); ok {
+		// A cell union is its own exact covering.
+		covering = append(CellUnion(nil), *cu...)
+		covering.Normalize()
+	} else {
+		covering = rc.Covering(region)
+	}
+
+	s := &RegionSampler{region: region}
+	var total float64
+	for _, id := range covering {
+		cell := CellFromCellID(id)
+		total += cell.ExactArea()
+		s.cells = append(s.cells, cell)
+		s.contained = append(s.contained, region.ContainsCell(cell))
+		s.cumulative = append(s.cumulative, total)
+	}
+	return s
+}
+
+// Region returns the region being sampled.
+func (s *RegionSampler) Region() Region {
+	return s.region
+}
+
+// Sample returns a point chosen uniformly at random (with respect to area on
+// the sphere) from the region. It returns false if the region is empty, or if
+// no point could be found because the region has no area.
+func (s *RegionSampler) Sample(r *rand.Rand) (Point, bool) {
+	if len(s.cells) == 0 {
+		return Point{}, false
+	}
+	total := s.cumulative[len(s.cumulative)-1]
+	for i := 0; i < maxSampleAttempts; i++ {
+		// Choose a cell with probability proportional to its area.
+		x := r.Float64() * total
+		k := sort.SearchFloat64s(s.cumulative, x)
+		if k == len(s.cells) {
+			k--
+		}
+		p := SamplePointFromCell(r, s.cells[k])
+		if s.contained[k] || s.region.ContainsPoint(p) {
+			return p, true
+		}
+	}
+	return Point{}, false
+}
+
+// SamplePoints returns n points chosen independently and uniformly at random
+// from the region. It returns fewer points only if the region cannot be
+// sampled (see Sample).
+func (s *RegionSampler) SamplePoints(r *rand.Rand, n int) []Point {
+	points := make([]Point, 0, n)
+	for i := 0; i < n; i++ {
+		p, ok := s.Sample(r)
+		if !ok {
+			break
+		}
+		points = append(points, p)
+	}
+	return points
+}
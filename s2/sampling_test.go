@@ -0,0 +1,207 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/rubenpoppe/geo/s1"
+)
+
+const (
+	// numSamples is the number of points generated by each sampling test.
+	numSamples = 10000
+	// samplingTolerance is the allowed difference between the observed
+	// fraction of points in a subregion and its fraction of the area. It is
+	// about six standard deviations for numSamples points.
+	samplingTolerance = 0.03
+)
+
+// checkSampleFraction verifies that the fraction of points contained by sub
+// matches the given expected fraction.
+func checkSampleFraction(t *testing.T, name string, points []Point, sub func(Point) bool, want float64) {
+	t.Helper()
+	if len(points) != numSamples {
+		t.Fatalf("%s: got %d points, want %d", name, len(points), numSamples)
+	}
+	count := 0
+	for _, p := range points {
+		if sub(p) {
+			count++
+		}
+	}
+	if got := float64(count) / float64(len(points)); math.Abs(got-want) > samplingTolerance {
+		t.Errorf("%s: fraction of points in subregion = %v, want %v", name, got, want)
+	}
+}
+
+func TestSamplePointFromCap(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	c := CapFromCenterAngle(PointFromCoords(1, 1, 1), s1.Angle(1.2))
+	inner := CapFromCenterHeight(c.Center(), c.Height()/2)
+	var points []Point
+	for i := 0; i < numSamples; i++ {
+		p := SamplePointFromCap(r, c)
+		if !c.ContainsPoint(p) {
+			t.Errorf("SamplePointFromCap(%v) = %v, not contained by the cap", c, p)
+		}
+		points = append(points, p)
+	}
+	// A cap with half the height has half the area.
+	checkSampleFraction(t, "cap", points, inner.ContainsPoint, 0.5)
+}
+
+func TestSamplePointFromRect(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	// A rectangle that crosses the 180 degree meridian.
+	rect := rectFromDegrees(0, 170, 60, -170)
+	lower := rectFromDegrees(0, 170, 30, -170)
+	var points []Point
+	for i := 0; i < numSamples; i++ {
+		p := SamplePointFromRect(r, rect)
+		if !rect.ContainsPoint(p) {
+			t.Errorf("SamplePointFromRect(%v) = %v, not contained by the rect", rect, p)
+		}
+		points = append(points, p)
+	}
+	checkSampleFraction(t, "rect", points, lower.ContainsPoint, lower.Area()/rect.Area())
+}
+
+func TestSamplePointFromCell(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for _, id := range []CellID{CellIDFromFace(0), CellIDFromFace(5).ChildBeginAtLevel(8), cellIDFromPoint(PointFromCoords(1, 2, 3)).Parent(20)} {
+		cell := CellFromCellID(id)
+		child := CellFromCellID(id.ChildBegin())
+		var points []Point
+		for i := 0; i < numSamples; i++ {
+			p := SamplePointFromCell(r, cell)
+			if !cell.ContainsPoint(p) {
+				t.Errorf("SamplePointFromCell(%v) = %v, not contained by the cell", id, p)
+			}
+			points = append(points, p)
+		}
+		checkSampleFraction(t, id.String(), points, child.ContainsPoint, child.ExactArea()/cell.ExactArea())
+	}
+}
+
+func TestSamplePointOnPolyline(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	line := makePolyline("0:0, 0:10, 20:10")
+	first := (*line)[0].Distance((*line)[1]) / line.Length()
+	var points []Point
+	for i := 0; i < numSamples; i++ {
+		points = append(points, SamplePointOnPolyline(r, line))
+	}
+	checkSampleFraction(t, "polyline", points, func(p Point) bool {
+		q, next := line.Project(p)
+		if q.Distance(p) > 1e-14 {
+			t.Errorf("SamplePointOnPolyline(%v) = %v, not on the polyline", line, p)
+		}
+		return next == 1
+	}, float64(first))
+}
+
+func TestRegionSampler(t *testing.T) {
+	tests := []struct {
+		name   string
+		region Region
+		sub    Region
+	}{
+		{
+			name:   "polygon with hole",
+			region: makePolygon("0:0, 0:10, 10:10, 10:0; 2:2, 8:2, 8:8, 2:8", true),
+			sub:    makePolygon("0:0, 0:10, 2:10, 2:0", true),
+		},
+		{
+			name:   "complement polygon",
+			region: makePolygon("0:0, 10:0, 10:10, 0:10", false),
+			sub:    makePolygon("0:0, -90:0, 0:90", true),
+		},
+		{
+			name:   "cap",
+			region: CapFromCenterAngle(PointFromCoords(0, 0, 1), s1.Angle(0.5)),
+			sub:    CapFromCenterAngle(PointFromCoords(0, 0, 1), s1.Angle(0.25)),
+		},
+		{
+			name:   "rect",
+			region: rectFromDegrees(-10, -10, 10, 10),
+			sub:    rectFromDegrees(0, -10, 10, 0),
+		},
+		{
+			name: "cell union",
+			region: &CellUnion{
+				CellIDFromFace(1).ChildBeginAtLevel(3),
+				CellIDFromFace(3).ChildBeginAtLevel(5),
+			},
+			sub: CellFromCellID(CellIDFromFace(1).ChildBeginAtLevel(3)),
+		},
+	}
+	for _, test := range tests {
+		r := rand.New(rand.NewSource(5))
+		s := NewRegionSampler(test.region)
+		points := s.SamplePoints(r, numSamples)
+		for _, p := range points {
+			if !test.region.ContainsPoint(p) {
+				t.Errorf("%s: sample %v is not contained by the region", test.name, p)
+			}
+		}
+
+		// Each subregion is contained by its region, so the expected fraction
+		// is the ratio of their areas.
+		want := regionArea(t, test.sub) / regionArea(t, test.region)
+		checkSampleFraction(t, test.name, points, test.sub.ContainsPoint, want)
+	}
+}
+
+// regionArea returns the area of the test regions used above.
+func regionArea(t *testing.T, r Region) float64 {
+	switch r := r.(type) {
+	case *Polygon:
+		return r.Area()
+	case Cap:
+		return r.Area()
+	case Rect:
+		return r.Area()
+	case Cell:
+		return r.ExactArea()
+	case *CellUnion:
+		return r.ExactArea()
+	}
+	t.Fatalf("unexpected region type %T", r)
+	return 0
+}
+
+func TestRegionSamplerDeterministic(t *testing.T) {
+	p := makePolygon("0:0, 0:10, 10:10, 10:0", true)
+	a := NewRegionSampler(p).SamplePoints(rand.New(rand.NewSource(6)), 100)
+	b := NewRegionSampler(p).SamplePoints(rand.New(rand.NewSource(6)), 100)
+	if !pointSlicesApproxEqual(a, b, 0) {
+		t.Errorf("samplers with the same seed returned different points")
+	}
+}
+
+func TestRegionSamplerNoArea(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for _, region := range []Region{EmptyCap(), EmptyRect(), makePolygon("empty", true), &CellUnion{}} {
+		if p, ok := NewRegionSampler(region).Sample(r); ok {
+			t.Errorf("NewRegionSampler(%v).Sample() = %v, true, want false", region, p)
+		}
+	}
+	if got := NewRegionSampler(makePolyline("0:0, 1:1")).SamplePoints(r, 10); len(got) != 0 {
+		t.Errorf("sampling a polyline returned %d points, want 0", len(got))
+	}
+}
@@ -223,7 +223,7 @@ type Shape interface {
 	//      pairs consisting of an edge and its corresponding reversed edge).
 	//      A polygon loop may also be full (containing all points on the
 	//      sphere); by convention this is represented as a chain with no edges.
-	//      (See laxPolygon for details.)
+	//      (See LaxPolygon for details.)
 	//
 	// This method allows degenerate geometry of different dimensions
 	// to be distinguished, e.g. it allows a point to be distinguished from a
@@ -0,0 +1,163 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+// This file defines measures for any Shape, such as length, perimeter, area
+// and centroid. Each measure applies to shapes of a given dimension, and
+// returns zero for shapes of other dimensions.
+
+import (
+	"math"
+
+	"github.com/rubenpoppe/geo/r3"
+	"github.com/rubenpoppe/geo/s1"
+)
+
+// ShapeLength returns the total length of all the polylines in the shape.
+// It returns zero for shapes whose dimension is not 1.
+func ShapeLength(shape Shape) s1.Angle {
+	if shape.Dimension() != 1 {
+		return 0
+	}
+	return shapeEdgesLength(shape)
+}
+
+// ShapePerimeter returns the sum of the perimeters of all the loops in the
+// shape. It returns zero for shapes whose dimension is not 2.
+func ShapePerimeter(shape Shape) s1.Angle {
+	if shape.Dimension() != 2 {
+		return 0
+	}
+	return shapeEdgesLength(shape)
+}
+
+// shapeEdgesLength returns the sum of the lengths of the edges of the shape.
+func shapeEdgesLength(shape Shape) s1.Angle {
+	var length s1.Angle
+	for i := 0; i < shape.NumEdges(); i++ {
+		e := shape.Edge(i)
+		length += e.V0.Distance(e.V1)
+	}
+	return length
+}
+
+// ShapeArea returns the area of the interior of the shape. It returns zero
+// for shapes whose dimension is not 2, and 4*pi for the full polygon.
+//
+// Each loop is measured with a signed area in the range [-2*pi, 2*pi] rather
+// than the range [0, 4*pi] used by Loop.Area. Holes typically have areas near
+// 4*pi, which would otherwise cause large cancellation errors when computing
+// the area of small polygons with holes.
+//
+// The shape need not be a valid polygon, but if its loops overlap then the
+// area of the overlap is counted more than once. Rarely, the area of the
+// complementary region (4*pi - area) is returned instead. This can only
+// happen when the true area is very close to zero or 4*pi and the shape has
+// several loops.
+func ShapeArea(shape Shape) float64 {
+	if shape.Dimension() != 2 {
+		return 0
+	}
+	var area float64
+	for i := 0; i < shape.NumChains(); i++ {
+		area += loopSignedArea(chainVertices(shape, i))
+	}
+	// loopSignedArea gives the full loop a very small negative area.
+	if area < 0 {
+		area += 4 * math.Pi
+	}
+	return area
+}
+
+// ShapeCentroid returns the centroid of the shape multiplied by the measure
+// of the shape, which is its number of points, its length or its area for
+// shapes of dimension 0, 1 and 2 respectively. The result is not unit length,
+// so you may want to normalize it.
+//
+// Scaling by the measure of the shape makes it easy to compute the centroid
+// of several shapes of the same dimension, by simply adding up their
+// centroids. Shapes of lower dimension should be ignored when combining them
+// with shapes of higher dimension, since their measure is zero in the higher
+// dimension (see ShapeIndexCentroid).
+func ShapeCentroid(shape Shape) Point {
+	var centroid r3.Vector
+	for i := 0; i < shape.NumChains(); i++ {
+		switch shape.Dimension() {
+		case 0:
+			centroid = centroid.Add(shape.ChainEdge(i, 0).V0.Vector)
+		case 1:
+			centroid = centroid.Add(polylineCentroid(chainVertices(shape, i)).Vector)
+		default:
+			vertices := chainVertices(shape, i)
+			if len(vertices) == 0 {
+				// The integral of position over the full sphere is zero.
+				continue
+			}
+			l := &Loop{vertices: vertices}
+			centroid = centroid.Add(l.surfaceIntegralPoint(TrueCentroid).Vector)
+		}
+	}
+	return Point{centroid}
+}
+
+// chainVertices returns the vertices of the given chain of the shape. For
+// polylines this includes the last vertex of the chain, whereas for polygon
+// loops the last vertex is implicitly the same as the first. A polygon chain
+// with no edges, such as the full loop, has no vertices.
+func chainVertices(shape Shape, chainID int) []Point {
+	chain := shape.Chain(chainID)
+	if chain.Length == 0 {
+		return nil
+	}
+	vertices := make([]Point, 0, chain.Length+1)
+	for i := 0; i < chain.Length; i++ {
+		vertices = append(vertices, shape.ChainEdge(chainID, i).V0)
+	}
+	if shape.Dimension() == 1 {
+		vertices = append(vertices, shape.ChainEdge(chainID, chain.Length-1).V1)
+	}
+	return vertices
+}
+
+// loopSignedArea returns the area of the loop with the given vertices,
+// normalized to the range [-2*pi, 2*pi]. Loops with no vertices are full
+// and have a very small negative area, so that adding 4*pi to a negative
+// total area gives 4*pi. Degenerate loops have an area of zero.
+func loopSignedArea(vertices []Point) float64 {
+	if len(vertices) == 0 {
+		return -math.SmallestNonzeroFloat64
+	}
+	l := &Loop{vertices: vertices}
+	area := math.Remainder(l.surfaceIntegralFloat64(SignedArea), 4*math.Pi)
+	if area == -2*math.Pi {
+		area = 2 * math.Pi
+	}
+
+	// If the area is a small negative or positive number, check that its sign
+	// is consistent with the loop orientation.
+	if math.Abs(area) <= l.turningAngleMaxError() {
+		turningAngle := l.TurningAngle()
+		if turningAngle == 2*math.Pi {
+			return 0
+		}
+		if area <= 0 && turningAngle > 0 {
+			return math.SmallestNonzeroFloat64
+		}
+		if area >= 0 && turningAngle < 0 {
+			return -math.SmallestNonzeroFloat64
+		}
+	}
+	return area
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/rubenpoppe/geo/s1"
+)
+
+func TestShapeLength(t *testing.T) {
+	threeChains := edgeVectorShapeFromPoints(parsePoint("0:0"), parsePoint("1:0"))
+	threeChains.Add(parsePoint("0:1"), parsePoint("0:10"))
+	threeChains.Add(parsePoint("5:5"), parsePoint("6:5"))
+	points := PointVector(parsePoints("0:0, 1:1"))
+
+	tests := []struct {
+		shape Shape
+		want  float64 // in degrees
+	}{
+		{&points, 0},
+		{makeLaxPolygon("0:0, 0:1, 1:0"), 0},
+		{makeLaxPolyline(""), 0},
+		{makePolyline("0:0, 0:1, 0:3"), 3},
+		{threeChains, 11},
+	}
+
+	for _, test := range tests {
+		if got := ShapeLength(test.shape).Degrees(); !float64Near(got, test.want, 1e-13) {
+			t.Errorf("ShapeLength(%v) = %v, want %v", test.shape, got, test.want)
+		}
+	}
+}
+
+func TestShapePerimeter(t *testing.T) {
+	points := PointVector(parsePoints("0:0, 1:1"))
+	tests := []struct {
+		shape Shape
+		want  float64 // in degrees
+	}{
+		{&points, 0},
+		{makePolyline("0:0, 0:1, 0:3"), 0},
+		{makeLaxPolygon(""), 0},
+		{makeLaxPolygon("full"), 0},
+		{makeLaxPolygon("0:0, 0:1, 0:2, 0:1"), 4},
+		// Edges along parallels are slightly shorter than their change in longitude.
+		{makeLaxPolygon("0:0, 0:2, 2:2, 2:0; 0.5:0.5, 1:0.5, 1:1, 0.5:1"), 8.0 + 2.0},
+	}
+
+	for _, test := range tests {
+		if got := ShapePerimeter(test.shape).Degrees(); !float64Near(got, test.want, 1e-2) {
+			t.Errorf("ShapePerimeter(%v) = %v, want %v", test.shape, got, test.want)
+		}
+	}
+
+	// The perimeter of a polygon is the sum of its loop lengths.
+	p := near0231Polygon
+	var want s1.Angle
+	for _, l := range p.Loops() {
+		for i := 0; i < l.NumEdges(); i++ {
+			e := l.Edge(i)
+			want += e.V0.Distance(e.V1)
+		}
+	}
+	if got := ShapePerimeter(p); !float64Near(float64(got), float64(want), 1e-15) {
+		t.Errorf("ShapePerimeter(%v) = %v, want %v", p, got, want)
+	}
+}
+
+func TestShapeArea(t *testing.T) {
+	side := s1.Angle(1e-10) * s1.Degree
+	tinyArea := float64(side * side)
+
+	tests := []struct {
+		label string
+		shape Shape
+		want  float64
+	}{
+		{"polyline", makePolyline("0:0, 0:1, 0:3"), 0},
+		{"no loops", makeLaxPolygon(""), 0},
+		{"full", makeLaxPolygon("full"), 4 * math.Pi},
+		{"full polygon", FullPolygon(), 4 * math.Pi},
+		{"empty polygon", &Polygon{}, 0},
+		{"degenerate", makeLaxPolygon("0:0, 0:1, 0:2, 0:1"), 0},
+		// Two small squares with sides about 10 um long.
+		{"two tiny shells", makeLaxPolygon("0:0, 0:1e-10, 1e-10:1e-10, 1e-10:0; 0:2e-10, 0:3e-10, 1e-10:3e-10, 1e-10:2e-10"), 2 * tinyArea},
+		// A square with sides about 30 um long around a square hole with
+		// sides about 10 um long. Computing the area of the hole as nearly
+		// 4*pi would lose almost all of the accuracy here.
+		{"tiny hole", makeLaxPolygon("0:0, 0:3e-10, 3e-10:3e-10, 3e-10:0; 1e-10:1e-10, 2e-10:1e-10, 2e-10:2e-10, 1e-10:2e-10"), 8 * tinyArea},
+	}
+
+	for _, test := range tests {
+		if got := ShapeArea(test.shape); !float64Near(got, test.want, 1e-6*test.want+1e-15) {
+			t.Errorf("%s: ShapeArea(%v) = %v, want %v", test.label, test.shape, got, test.want)
+		}
+	}
+
+	for _, p := range []*Polygon{near0231Polygon, far2H013Polygon, cross1CenterHolePolygon, south20bH0acPolygon} {
+		if got, want := ShapeArea(p), p.Area(); !float64Near(got, want, 1e-12) {
+			t.Errorf("ShapeArea(%v) = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestShapeCentroid(t *testing.T) {
+	points := PointVector(parsePoints("0:0, 0:90"))
+	if got, want := ShapeCentroid(&points), PointFromCoords(1, 1, 0); !got.Vector.ApproxEqual(want.Vector.Mul(math.Sqrt2)) {
+		t.Errorf("ShapeCentroid(%v) = %v, want %v", points, got, want)
+	}
+
+	polyline := makePolyline("0:0, 0:1, 1:1, 2:3")
+	if got, want := ShapeCentroid(polyline), polyline.Centroid(); !got.Vector.ApproxEqual(want.Vector) {
+		t.Errorf("ShapeCentroid(%v) = %v, want %v", polyline, got, want)
+	}
+
+	for _, p := range []*Polygon{&Polygon{}, FullPolygon(), cross1CenterHolePolygon, near0231Polygon} {
+		if got, want := ShapeCentroid(p), p.Centroid(); !got.Vector.ApproxEqual(want.Vector) {
+			t.Errorf("ShapeCentroid(%v) = %v, want %v", p, got, want)
+		}
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"sort"
 	"sync"
 	"sync/atomic"
+	"unsafe"
 
 	"github.com/rubenpoppe/geo/r1"
 	"github.com/rubenpoppe/geo/r2"
@@ -136,6 +137,16 @@ func (s *ShapeIndexCell) add(c *clippedShape) {
 	s.shapes = append(s.shapes, c)
 }
 
+// ShapeIDs returns the IDs of the shapes that have edges in this cell or
+// contain it entirely.
+func (s *ShapeIndexCell) ShapeIDs() []int32 {
+	ids := make([]int32, len(s.shapes))
+	for i, clipped := range s.shapes {
+		ids[i] = clipped.shapeID
+	}
+	return ids
+}
+
 // findByShapeID returns the clipped shape that contains the given shapeID,
 // or nil if none of the clipped shapes contain it.
 func (s *ShapeIndexCell) findByShapeID(shapeID int32) *clippedShape {
@@ -533,9 +544,20 @@ func (t *tracker) restoreStateBefore(limitShapeID int32) {
 	t.savedIDs = nil
 }
 
-// lowerBound returns the shapeID of the first entry x where x >= shapeID.
+// lowerBound returns the position of the first entry x where x >= shapeID.
 func (t *tracker) lowerBound(shapeID int32) int32 {
-	panic("not implemented")
+	return int32(sort.Search(len(t.shapeIDs), func(i int) bool { return t.shapeIDs[i] >= shapeID }))
+}
+
+// clone returns a copy of the tracker that can be updated independently.
+func (t *tracker) clone() *tracker {
+	c := *t
+	c.shapeIDs = append([]int32(nil), t.shapeIDs...)
+	c.savedIDs = append([]int32(nil), t.savedIDs...)
+	if t.crosser != nil {
+		c.crosser = NewEdgeCrosser(t.a, t.b)
+	}
+	return &c
 }
 
 // removedShape represents a set of edges from the given shape that is queued for removal.
@@ -624,6 +646,35 @@ type ShapeIndex struct {
 	// The set of shapes that have been queued for removal but not processed yet by
 	// applyUpdatesInternal.
 	pendingRemovals []*removedShape
+
+	// workers is the number of goroutines that may apply updates.
+	workers int
+
+	// memoryBudget is the number of bytes of temporary data that applying
+	// an update may use, or zero if there is no limit.
+	memoryBudget int
+
+	// workerSlots limits the number of extra goroutines while updates are
+	// being applied. It is nil when the index is built serially.
+	workerSlots chan struct{}
+}
+
+// ShapeIndexOptions controls how a ShapeIndex applies pending updates.
+type ShapeIndexOptions struct {
+	// Workers is the number of goroutines that may be used to apply pending
+	// updates. The six cube faces, and subtrees within a face that have many
+	// edges, are built concurrently. The resulting index is identical to one
+	// built serially. Values less than 2 build the index on the goroutine
+	// that triggers the update.
+	Workers int
+
+	// MemoryBudget is the approximate number of bytes of temporary data that
+	// applying pending updates may use. Building the index can use up to 20x
+	// as much memory per edge as the final index, so if the pending additions
+	// would exceed the budget, they are split into batches of shapes that are
+	// added one after another. A shape is never split, so a batch with a
+	// single large shape may exceed the budget. Zero means no limit.
+	MemoryBudget int
 }
 
 // NewShapeIndex creates a new ShapeIndex.
@@ -637,6 +688,17 @@ func NewShapeIndex() *ShapeIndex {
 	}
 }
 
+// NewShapeIndexWithOptions creates a new ShapeIndex that applies its updates
+// as specified by the given options. If opts is nil, the defaults are used.
+func NewShapeIndexWithOptions(opts *ShapeIndexOptions) *ShapeIndex {
+	s := NewShapeIndex()
+	if opts != nil {
+		s.workers = opts.Workers
+		s.memoryBudget = opts.MemoryBudget
+	}
+	return s
+}
+
 // Iterator returns an iterator for this index.
 func (s *ShapeIndex) Iterator() *ShapeIndexIterator {
 	s.maybeApplyUpdates()
@@ -670,6 +732,8 @@ func (s *ShapeIndex) Reset() {
 	s.nextID = 0
 	s.cellMap = make(map[CellID]*ShapeIndexCell)
 	s.cells = nil
+	s.pendingAdditionsPos = 0
+	s.pendingRemovals = nil
 	atomic.StoreInt32(&s.status, fresh)
 }
 
@@ -820,10 +884,55 @@ func (s *ShapeIndex) maybeApplyUpdates() {
 // applyUpdatesInternal does the actual work of updating the index by applying all
 // pending additions and removals. It does *not* update the indexes status.
 func (s *ShapeIndex) applyUpdatesInternal() {
-	// TODO(roberts): Building the index can use up to 20x as much memory per
-	// edge as the final index memory size. If this causes issues, add in
-	// batched updating to limit the amount of items per batch to a
-	// configurable memory footprint overhead.
+	if s.workers > 1 {
+		s.workerSlots = make(chan struct{}, s.workers-1)
+		defer func() { s.workerSlots = nil }()
+	}
+
+	// Building the index can use up to 20x as much memory per edge as the
+	// final index, so the additions are applied in batches that fit within
+	// the memory budget. Each batch after the first is an incremental update.
+	for _, end := range s.additionBatches() {
+		s.applyUpdateBatch(end)
+	}
+	// It is the caller's responsibility to update the index status.
+}
+
+// shapeIndexTmpBytesPerEdge estimates the temporary memory used for each edge
+// while applying an update: its faceEdge, and a clippedEdge and a pointer to
+// it at each of the couple of levels where it is typically clipped.
+const shapeIndexTmpBytesPerEdge = int(unsafe.Sizeof(faceEdge{}) +
+	2*(unsafe.Sizeof(clippedEdge{})+unsafe.Sizeof(&clippedEdge{})))
+
+// additionBatches splits the pending additions into batches whose temporary
+// memory fits within the memory budget, and returns the shape ID following
+// the last shape of each batch. There is always at least one batch.
+func (s *ShapeIndex) additionBatches() []int32 {
+	if s.memoryBudget <= 0 {
+		return []int32{s.nextID}
+	}
+	maxEdges := s.memoryBudget / shapeIndexTmpBytesPerEdge
+
+	var ends []int32
+	numEdges := 0
+	for id := s.pendingAdditionsPos; id < s.nextID; id++ {
+		shape := s.shapes[id]
+		if shape == nil {
+			continue
+		}
+		n := shape.NumEdges()
+		if numEdges > 0 && numEdges+n > maxEdges {
+			ends = append(ends, id)
+			numEdges = 0
+		}
+		numEdges += n
+	}
+	return append(ends, s.nextID)
+}
+
+// applyUpdateBatch applies the pending removals and the pending additions
+// with IDs below end.
+func (s *ShapeIndex) applyUpdateBatch(end int32) {
 	t := newTracker()
 
 	// allEdges maps a Face to a collection of faceEdges.
@@ -833,17 +942,211 @@ func (s *ShapeIndex) applyUpdatesInternal() {
 		s.removeShapeInternal(p, allEdges, t)
 	}
 
-	for id := s.pendingAdditionsPos; id < int32(len(s.shapes)); id++ {
-		s.addShapeInternal(id, allEdges, t)
-	}
+	s.addShapesInternal(s.pendingAdditionsPos, end, allEdges, t)
 
-	for face := 0; face < 6; face++ {
-		s.updateFaceEdges(face, allEdges[face], t)
+	var cells indexCellList
+	if s.workerSlots == nil {
+		for face := 0; face < 6; face++ {
+			s.updateFaceEdges(face, allEdges[face], t, &cells)
+		}
+	} else {
+		// Each face is built with its own tracker, which starts out in the
+		// state that a serial build would reach at the start of the face.
+		var faceCells [6]indexCellList
+		var wg sync.WaitGroup
+		for face, ft := range faceTrackers(allEdges, t) {
+			face, ft := face, ft
+			s.goIfWorkerFree(&wg, func() {
+				s.updateFaceEdges(face, allEdges[face], ft, &faceCells[face])
+			})
+		}
+		wg.Wait()
+		for face := range faceCells {
+			cells.appendList(&faceCells[face])
+		}
 	}
+	s.insertCells(&cells)
 
 	s.pendingRemovals = s.pendingRemovals[:0]
-	s.pendingAdditionsPos = int32(len(s.shapes))
-	// It is the caller's responsibility to update the index status.
+	s.pendingAdditionsPos = end
+}
+
+// shapeIndexMinParallelEdges is the minimum number of edges for which part of
+// an update is handed to another goroutine. Smaller parts are not worth the
+// overhead.
+const shapeIndexMinParallelEdges = 4096
+
+// goIfWorkerFree runs f on a new goroutine tracked by wg if a worker is free,
+// and otherwise runs it before returning.
+func (s *ShapeIndex) goIfWorkerFree(wg *sync.WaitGroup, f func()) {
+	select {
+	case s.workerSlots <- struct{}{}:
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-s.workerSlots
+				wg.Done()
+			}()
+			f()
+		}()
+	default:
+		f()
+	}
+}
+
+// faceTrackers returns a copy of the tracker for each face, positioned at the
+// start of that face, given the tracker at the start of face 0. A serial
+// build reaches the same state by the time it gets to each face, since the
+// shapes that contain a point do not depend on the path taken to it.
+func faceTrackers(allEdges [][]faceEdge, t *tracker) []*tracker {
+	trackers := make([]*tracker, 6)
+	for face := range trackers {
+		pcell := PaddedCellFromCellID(CellIDFromFace(face), cellPadding)
+		ft := t.clone()
+		ft.moveTo(pcell.EntryVertex())
+		ft.setNextCellID(pcell.id)
+		trackers[face] = ft
+
+		if t.isActive {
+			// Any edge that crosses the boundary of the face between its
+			// entry and exit vertices intersects the face.
+			t.moveTo(pcell.EntryVertex())
+			t.drawTo(pcell.ExitVertex())
+			for _, fe := range allEdges[face] {
+				if fe.hasInterior {
+					t.testEdge(fe.shapeID, fe.edge)
+				}
+			}
+		}
+	}
+	return trackers
+}
+
+// indexCellList holds the index cells created while applying an update, in
+// increasing order of CellID, along with the existing cells they replace.
+// Cells are collected here rather than in the index so that parts of the
+// update can be applied concurrently.
+type indexCellList struct {
+	ids      []CellID
+	cells    []*ShapeIndexCell
+	absorbed []CellID
+}
+
+// add appends a new index cell to the list.
+func (l *indexCellList) add(id CellID, cell *ShapeIndexCell) {
+	l.ids = append(l.ids, id)
+	l.cells = append(l.cells, cell)
+}
+
+// appendList appends the cells of o, which must all follow the cells in l.
+func (l *indexCellList) appendList(o *indexCellList) {
+	l.ids = append(l.ids, o.ids...)
+	l.cells = append(l.cells, o.cells...)
+	l.absorbed = append(l.absorbed, o.absorbed...)
+}
+
+// insertCells replaces the absorbed cells of the index with the given new
+// cells, keeping the cell IDs in increasing order.
+func (s *ShapeIndex) insertCells(l *indexCellList) {
+	for _, id := range l.absorbed {
+		delete(s.cellMap, id)
+	}
+	if len(l.absorbed) == 0 && (len(s.cells) == 0 || len(l.ids) == 0 || s.cells[len(s.cells)-1] < l.ids[0]) {
+		s.cells = append(s.cells, l.ids...)
+	} else {
+		cells := make([]CellID, 0, len(s.cells)+len(l.ids))
+		i := 0
+		for _, id := range s.cells {
+			if _, ok := s.cellMap[id]; !ok {
+				continue
+			}
+			for ; i < len(l.ids) && l.ids[i] < id; i++ {
+				cells = append(cells, l.ids[i])
+			}
+			cells = append(cells, id)
+		}
+		s.cells = append(cells, l.ids[i:]...)
+	}
+	for i, id := range l.ids {
+		s.cellMap[id] = l.cells[i]
+	}
+}
+
+// addShapesInternal adds the shapes with IDs in the range [begin, end) by
+// calling addShapeInternal on each of them. When there are free workers, the
+// range is split into parts with similar numbers of edges that are clipped
+// concurrently. The edges of each face stay sorted by shape ID.
+func (s *ShapeIndex) addShapesInternal(begin, end int32, allEdges [][]faceEdge, t *tracker) {
+	numEdges := 0
+	for id := begin; id < end; id++ {
+		if shape := s.shapes[id]; shape != nil {
+			numEdges += shape.NumEdges()
+		}
+	}
+	if s.workerSlots == nil || numEdges < shapeIndexMinParallelEdges {
+		for id := begin; id < end; id++ {
+			s.addShapeInternal(id, allEdges, t)
+		}
+		return
+	}
+
+	type part struct {
+		begin, end int32
+		allEdges   [][]faceEdge
+		t          *tracker
+	}
+	var parts []*part
+	partEdges := numEdges/(cap(s.workerSlots)+1) + 1
+	p := &part{begin: begin}
+	n := 0
+	for id := begin; id < end; id++ {
+		if shape := s.shapes[id]; shape != nil {
+			n += shape.NumEdges()
+		}
+		if n >= partEdges || id == end-1 {
+			p.end = id + 1
+			parts = append(parts, p)
+			p = &part{begin: id + 1}
+			n = 0
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, p := range parts {
+		p := p
+		s.goIfWorkerFree(&wg, func() {
+			p.allEdges = make([][]faceEdge, 6)
+			p.t = newTracker()
+			for id := p.begin; id < p.end; id++ {
+				s.addShapeInternal(id, p.allEdges, p.t)
+			}
+		})
+	}
+	wg.Wait()
+
+	for face := range allEdges {
+		size := len(allEdges[face])
+		for _, p := range parts {
+			size += len(p.allEdges[face])
+		}
+		edges := make([]faceEdge, 0, size)
+		edges = append(edges, allEdges[face]...)
+		for _, p := range parts {
+			edges = append(edges, p.allEdges[face]...)
+			p.allEdges[face] = nil
+		}
+		allEdges[face] = edges
+	}
+	for _, p := range parts {
+		// Every tracker starts at the same focus point, and the parts are in
+		// increasing order of shape ID.
+		if p.t.isActive {
+			t.isActive = true
+		}
+		for _, shapeID := range p.t.shapeIDs {
+			t.toggleShape(shapeID)
+		}
+	}
 }
 
 // addShapeInternal clips all edges of the given shape to the six cube faces,
@@ -907,7 +1210,7 @@ func (s *ShapeIndex) addFaceEdge(fe faceEdge, allEdges [][]faceEdge) {
 
 // updateFaceEdges adds or removes the various edges from the index.
 // An edge is added if shapes[id] is not nil, and removed otherwise.
-func (s *ShapeIndex) updateFaceEdges(face int, faceEdges []faceEdge, t *tracker) {
+func (s *ShapeIndex) updateFaceEdges(face int, faceEdges []faceEdge, t *tracker, out *indexCellList) {
 	numEdges := len(faceEdges)
 	if numEdges == 0 && len(t.shapeIDs) == 0 {
 		return
@@ -942,16 +1245,16 @@ func (s *ShapeIndex) updateFaceEdges(face int, faceEdges []faceEdge, t *tracker)
 			// can save a lot of work by starting directly with that cell, but if we
 			// are in the interior of at least one shape then we need to create
 			// index entries for the cells we are skipping over.
-			s.skipCellRange(faceID.RangeMin(), shrunkID.RangeMin(), t, disjointFromIndex)
+			s.skipCellRange(faceID.RangeMin(), shrunkID.RangeMin(), t, disjointFromIndex, out)
 			pcell = PaddedCellFromCellID(shrunkID, cellPadding)
-			s.updateEdges(pcell, clippedEdges, t, disjointFromIndex)
-			s.skipCellRange(shrunkID.RangeMax().Next(), faceID.RangeMax().Next(), t, disjointFromIndex)
+			s.updateEdges(pcell, clippedEdges, t, disjointFromIndex, out)
+			s.skipCellRange(shrunkID.RangeMax().Next(), faceID.RangeMax().Next(), t, disjointFromIndex, out)
 			return
 		}
 	}
 
 	// Otherwise (no edges, or no shrinking is possible), subdivide normally.
-	s.updateEdges(pcell, clippedEdges, t, disjointFromIndex)
+	s.updateEdges(pcell, clippedEdges, t, disjointFromIndex, out)
 }
 
 // shrinkToFit shrinks the PaddedCell to fit within the given bounds.
@@ -960,8 +1263,9 @@ func (s *ShapeIndex) shrinkToFit(pcell *PaddedCell, bound r2.Rect) CellID {
 
 	if !s.isFirstUpdate() && shrunkID != pcell.CellID() {
 		// Don't shrink any smaller than the existing index cells, since we need
-		// to combine the new edges with those cells.
-		iter := s.Iterator()
+		// to combine the new edges with those cells. The iterator must not
+		// apply updates, since we are in the middle of doing so.
+		iter := NewShapeIndexIterator(s)
 		if iter.LocateCellID(shrunkID) == Indexed {
 			shrunkID = iter.CellID()
 		}
@@ -971,7 +1275,7 @@ func (s *ShapeIndex) shrinkToFit(pcell *PaddedCell, bound r2.Rect) CellID {
 
 // skipCellRange skips over the cells in the given range, creating index cells if we are
 // currently in the interior of at least one shape.
-func (s *ShapeIndex) skipCellRange(begin, end CellID, t *tracker, disjointFromIndex bool) {
+func (s *ShapeIndex) skipCellRange(begin, end CellID, t *tracker, disjointFromIndex bool, out *indexCellList) {
 	// If we aren't in the interior of a shape, then skipping over cells is easy.
 	if len(t.shapeIDs) == 0 {
 		return
@@ -982,14 +1286,14 @@ func (s *ShapeIndex) skipCellRange(begin, end CellID, t *tracker, disjointFromIn
 	skipped := CellUnionFromRange(begin, end)
 	for _, cell := range skipped {
 		var clippedEdges []*clippedEdge
-		s.updateEdges(PaddedCellFromCellID(cell, cellPadding), clippedEdges, t, disjointFromIndex)
+		s.updateEdges(PaddedCellFromCellID(cell, cellPadding), clippedEdges, t, disjointFromIndex, out)
 	}
 }
 
 // updateEdges adds or removes the given edges whose bounding boxes intersect a
 // given cell. disjointFromIndex is an optimization hint indicating that cellMap
 // does not contain any entries that overlap the given cell.
-func (s *ShapeIndex) updateEdges(pcell *PaddedCell, edges []*clippedEdge, t *tracker, disjointFromIndex bool) {
+func (s *ShapeIndex) updateEdges(pcell *PaddedCell, edges []*clippedEdge, t *tracker, disjointFromIndex bool, out *indexCellList) {
 	// This function is recursive with a maximum recursion depth of 30 (maxLevel).
 
 	// Incremental updates are handled as follows. All edges being added or
@@ -1015,14 +1319,14 @@ func (s *ShapeIndex) updateEdges(pcell *PaddedCell, edges []*clippedEdge, t *tra
 		// There may be existing index cells contained inside pcell. If we
 		// encounter such a cell, we need to combine the edges being updated with
 		// the existing cell contents by absorbing the cell.
-		iter := s.Iterator()
+		iter := NewShapeIndexIterator(s)
 		r := iter.LocateCellID(pcell.id)
 		if r == Disjoint {
 			disjointFromIndex = true
 		} else if r == Indexed {
 			// Absorb the index cell by transferring its contents to edges and
 			// deleting it. We also start tracking the interior of any new shapes.
-			s.absorbIndexCell(pcell, iter, edges, t)
+			edges = s.absorbIndexCell(pcell, iter, edges, t, out)
 			indexCellAbsorbed = true
 			disjointFromIndex = true
 		} else {
@@ -1034,7 +1338,7 @@ func (s *ShapeIndex) updateEdges(pcell *PaddedCell, edges []*clippedEdge, t *tra
 	// subdividing so that we can merge with those cells. Otherwise,
 	// makeIndexCell checks if the number of edges is small enough, and creates
 	// an index cell if possible (returning true when it does so).
-	if !disjointFromIndex || !s.makeIndexCell(pcell, edges, t) {
+	if !disjointFromIndex || !s.makeIndexCell(pcell, edges, t, out) {
 		// TODO(roberts): If it turns out to have memory problems when there
 		// are 10M+ edges in the index, look into pre-allocating space so we
 		// are not always appending.
@@ -1109,11 +1413,15 @@ func (s *ShapeIndex) updateEdges(pcell *PaddedCell, edges []*clippedEdge, t *tra
 		// Now recursively update the edges in each child. We call the children in
 		// increasing order of CellID so that when the index is first constructed,
 		// all insertions into cellMap are at the end (which is much faster).
-		for pos := 0; pos < 4; pos++ {
-			i, j := pcell.ChildIJ(pos)
-			if len(childEdges[i][j]) > 0 || len(t.shapeIDs) > 0 {
-				s.updateEdges(PaddedCellFromParentIJ(pcell, i, j), childEdges[i][j],
-					t, disjointFromIndex)
+		if s.workerSlots != nil && disjointFromIndex && len(edges) >= shapeIndexMinParallelEdges {
+			s.updateChildEdgesConcurrently(pcell, &childEdges, t, out)
+		} else {
+			for pos := 0; pos < 4; pos++ {
+				i, j := pcell.ChildIJ(pos)
+				if len(childEdges[i][j]) > 0 || len(t.shapeIDs) > 0 {
+					s.updateEdges(PaddedCellFromParentIJ(pcell, i, j), childEdges[i][j],
+						t, disjointFromIndex, out)
+				}
 			}
 		}
 	}
@@ -1124,9 +1432,48 @@ func (s *ShapeIndex) updateEdges(pcell *PaddedCell, edges []*clippedEdge, t *tra
 	}
 }
 
+// updateChildEdgesConcurrently updates the edges in each child of the given
+// cell, which must be disjoint from the existing index cells, handing
+// children to other goroutines when workers are free. Each child gets its
+// own copy of the tracker, in the state that a serial build would reach at
+// the start of that child. When this returns, the tracker is positioned at the
+// end of the cell.
+func (s *ShapeIndex) updateChildEdgesConcurrently(pcell *PaddedCell, childEdges *[2][2][]*clippedEdge, t *tracker, out *indexCellList) {
+	var cells [4]indexCellList
+	var wg sync.WaitGroup
+	for pos := 0; pos < 4; pos++ {
+		i, j := pcell.ChildIJ(pos)
+		child := PaddedCellFromParentIJ(pcell, i, j)
+		id, entry, exit := child.id, child.EntryVertex(), child.ExitVertex()
+		edges := childEdges[i][j]
+		if len(edges) > 0 || len(t.shapeIDs) > 0 {
+			ct := t.clone()
+			ct.moveTo(entry)
+			ct.setNextCellID(id)
+			cells := &cells[pos]
+			s.goIfWorkerFree(&wg, func() {
+				s.updateEdges(child, edges, ct, true, cells)
+			})
+		}
+
+		if t.isActive {
+			// Any edge that crosses the boundary of the child between its
+			// entry and exit vertices is one of the child's edges.
+			t.moveTo(entry)
+			t.drawTo(exit)
+			s.testAllEdges(edges, t)
+			t.setNextCellID(id.Next())
+		}
+	}
+	wg.Wait()
+	for pos := range cells {
+		out.appendList(&cells[pos])
+	}
+}
+
 // makeIndexCell builds an indexCell from the given padded cell and set of edges and adds
 // it to the index. If the cell or edges are empty, no cell is added.
-func (s *ShapeIndex) makeIndexCell(p *PaddedCell, edges []*clippedEdge, t *tracker) bool {
+func (s *ShapeIndex) makeIndexCell(p *PaddedCell, edges []*clippedEdge, t *tracker, out *indexCellList) bool {
 	// If the cell is empty, no index cell is needed. (In most cases this
 	// situation is detected before we get to this point, but this can happen
 	// when all shapes in a cell are removed.)
@@ -1195,7 +1542,7 @@ func (s *ShapeIndex) makeIndexCell(p *PaddedCell, edges []*clippedEdge, t *track
 	for i := 0; i < numShapes; i++ {
 		var clipped *clippedShape
 		// advance to next value base + i
-		eshapeID := int32(s.Len())
+		eshapeID := s.nextID
 		cshapeID := eshapeID // Sentinels
 
 		if eNext != len(edges) {
@@ -1227,9 +1574,8 @@ func (s *ShapeIndex) makeIndexCell(p *PaddedCell, edges []*clippedEdge, t *track
 		cell.shapes[i] = clipped
 	}
 
-	// Add this cell to the map.
-	s.cellMap[p.id] = cell
-	s.cells = append(s.cells, p.id)
+	// Add this cell to the list of new cells.
+	out.add(p.id, cell)
 
 	// Shift the tracker focus point to the exit vertex of this cell.
 	if t.isActive && len(edges) != 0 {
@@ -1347,7 +1693,7 @@ func (s *ShapeIndex) clipVAxis(edge *clippedEdge, middle r1.Interval) (a, b *cli
 // and/or "tracker", and then delete this cell from the index. If edges includes
 // any edges that are being removed, this method also updates their
 // InteriorTracker state to correspond to the exit vertex of this cell.
-func (s *ShapeIndex) absorbIndexCell(p *PaddedCell, iter *ShapeIndexIterator, edges []*clippedEdge, t *tracker) {
+func (s *ShapeIndex) absorbIndexCell(p *PaddedCell, iter *ShapeIndexIterator, edges []*clippedEdge, t *tracker, out *indexCellList) []*clippedEdge {
 	// When we absorb a cell, we erase all the edges that are being removed.
 	// However when we are finished with this cell, we want to restore the state
 	// of those edges (since that is how we find all the index cells that need
@@ -1404,12 +1750,9 @@ func (s *ShapeIndex) absorbIndexCell(p *PaddedCell, iter *ShapeIndexIterator, ed
 		// cell is inside the shape, but we only know whether the center of the
 		// cell is inside the shape, so we need to test all the edges against the
 		// line segment from the cell center to the entry vertex.
-		edge := &faceEdge{
-			shapeID:     shapeID,
-			hasInterior: shape.Dimension() == 2,
-		}
+		hasInterior := shape.Dimension() == 2
 
-		if edge.hasInterior {
+		if hasInterior {
 			t.addShape(shapeID, clipped.containsCenter)
 			// There might not be any edges in this entire cell (i.e., it might be
 			// in the interior of all shapes), so we delay updating the tracker
@@ -1423,8 +1766,12 @@ func (s *ShapeIndex) absorbIndexCell(p *PaddedCell, iter *ShapeIndexIterator, ed
 		}
 		for i := 0; i < numClipped; i++ {
 			edgeID := clipped.edges[i]
-			edge.edgeID = edgeID
-			edge.edge = shape.Edge(edgeID)
+			edge := &faceEdge{
+				shapeID:     shapeID,
+				edgeID:      edgeID,
+				edge:        shape.Edge(edgeID),
+				hasInterior: hasInterior,
+			}
 			edge.maxLevel = maxLevelForEdge(edge.edge)
 			if edge.hasInterior {
 				t.testEdge(shapeID, edge.edge)
@@ -1456,10 +1803,10 @@ func (s *ShapeIndex) absorbIndexCell(p *PaddedCell, iter *ShapeIndexIterator, ed
 		}
 	}
 
-	// Update the edge list and delete this cell from the index.
-	edges, newEdges = newEdges, edges
-	delete(s.cellMap, p.id)
-	// TODO(roberts): delete from s.Cells
+	// Delete this cell from the index once the update is complete, and
+	// return the new edge list.
+	out.absorbed = append(out.absorbed, p.id)
+	return newEdges
 }
 
 // testAllEdges calls the trackers testEdge on all edges from shapes that have interiors.
@@ -0,0 +1,107 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+// This file defines measures over all the shapes in a ShapeIndex. The shapes
+// are not combined geometrically, so for example the area of two overlapping
+// polygons counts their overlap twice.
+
+import (
+	"github.com/rubenpoppe/geo/r3"
+	"github.com/rubenpoppe/geo/s1"
+)
+
+// ShapeIndexDimension returns the maximum dimension of any shape in the
+// index, or -1 if the index contains no shapes.
+func ShapeIndexDimension(index *ShapeIndex) int {
+	dim := -1
+	for _, shape := range indexShapes(index) {
+		if d := shape.Dimension(); d > dim {
+			dim = d
+		}
+	}
+	return dim
+}
+
+// ShapeIndexNumPoints returns the number of points in shapes of dimension 0.
+func ShapeIndexNumPoints(index *ShapeIndex) int {
+	var n int
+	for _, shape := range indexShapes(index) {
+		if shape.Dimension() == 0 {
+			n += shape.NumEdges()
+		}
+	}
+	return n
+}
+
+// ShapeIndexLength returns the total length of the polylines in shapes of
+// dimension 1.
+func ShapeIndexLength(index *ShapeIndex) s1.Angle {
+	var length s1.Angle
+	for _, shape := range indexShapes(index) {
+		length += ShapeLength(shape)
+	}
+	return length
+}
+
+// ShapeIndexPerimeter returns the total perimeter of the loops in shapes of
+// dimension 2.
+func ShapeIndexPerimeter(index *ShapeIndex) s1.Angle {
+	var perimeter s1.Angle
+	for _, shape := range indexShapes(index) {
+		perimeter += ShapePerimeter(shape)
+	}
+	return perimeter
+}
+
+// ShapeIndexArea returns the total area of the shapes of dimension 2. The
+// area of any overlap between shapes is counted more than once, so the result
+// may exceed 4*pi.
+func ShapeIndexArea(index *ShapeIndex) float64 {
+	var area float64
+	for _, shape := range indexShapes(index) {
+		area += ShapeArea(shape)
+	}
+	return area
+}
+
+// ShapeIndexCentroid returns the centroid of the shapes in the index whose
+// dimension is the maximum dimension in the index, multiplied by their
+// measure (see ShapeCentroid). Shapes of lower dimension are ignored, since
+// their measure is zero in the higher dimension. For example, the centroid of
+// an index with a polygon and a point is the centroid of the polygon alone.
+// The result is not unit length, so you may want to normalize it.
+func ShapeIndexCentroid(index *ShapeIndex) Point {
+	dim := ShapeIndexDimension(index)
+	var centroid r3.Vector
+	for _, shape := range indexShapes(index) {
+		if shape.Dimension() == dim {
+			centroid = centroid.Add(ShapeCentroid(shape).Vector)
+		}
+	}
+	return Point{centroid}
+}
+
+// indexShapes returns the shapes in the index in the order of their IDs, so
+// that sums over them are deterministic.
+func indexShapes(index *ShapeIndex) []Shape {
+	shapes := make([]Shape, 0, index.Len())
+	for id := int32(0); id < index.nextID; id++ {
+		if shape := index.Shape(id); shape != nil {
+			shapes = append(shapes, shape)
+		}
+	}
+	return shapes
+}
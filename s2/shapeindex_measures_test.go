@@ -0,0 +1,105 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestShapeIndexMeasures(t *testing.T) {
+	tests := []struct {
+		index     string
+		dimension int
+		numPoints int
+		length    float64 // in degrees
+		perimeter float64 // in degrees
+		area      float64
+	}{
+		{
+			index:     "# #",
+			dimension: -1,
+		},
+		{
+			index:     "0:0 | 0:90 # #",
+			dimension: 0,
+			numPoints: 2,
+		},
+		{
+			index:     "0:0 # 0:0, 0:1, 0:3 | 1:1, 2:1 #",
+			dimension: 1,
+			numPoints: 1,
+			length:    4,
+		},
+		{
+			// The point and polyline do not contribute to the centroid.
+			index:     "0:0 # 0:0, 0:1 # 0:0, 0:1, 0:2, 0:1",
+			dimension: 2,
+			numPoints: 1,
+			length:    1,
+			perimeter: 4,
+		},
+		{
+			index:     "# # full",
+			dimension: 2,
+			area:      4 * math.Pi,
+		},
+	}
+
+	for _, test := range tests {
+		index := makeShapeIndex(test.index)
+		if got := ShapeIndexDimension(index); got != test.dimension {
+			t.Errorf("ShapeIndexDimension(%q) = %d, want %d", test.index, got, test.dimension)
+		}
+		if got := ShapeIndexNumPoints(index); got != test.numPoints {
+			t.Errorf("ShapeIndexNumPoints(%q) = %d, want %d", test.index, got, test.numPoints)
+		}
+		if got := ShapeIndexLength(index).Degrees(); !float64Near(got, test.length, 1e-13) {
+			t.Errorf("ShapeIndexLength(%q) = %v, want %v", test.index, got, test.length)
+		}
+		if got := ShapeIndexPerimeter(index).Degrees(); !float64Near(got, test.perimeter, 1e-13) {
+			t.Errorf("ShapeIndexPerimeter(%q) = %v, want %v", test.index, got, test.perimeter)
+		}
+		if got := ShapeIndexArea(index); !float64Near(got, test.area, 1e-15) {
+			t.Errorf("ShapeIndexArea(%q) = %v, want %v", test.index, got, test.area)
+		}
+	}
+}
+
+func TestShapeIndexCentroid(t *testing.T) {
+	// Only shapes of the highest dimension contribute.
+	index := makeShapeIndex("0:0 | 0:90 # #")
+	if got, want := ShapeIndexCentroid(index), PointFromCoords(1, 1, 0); !got.Vector.ApproxEqual(want.Vector.Mul(math.Sqrt2)) {
+		t.Errorf("ShapeIndexCentroid(%v) = %v, want %v", index, got, want)
+	}
+
+	index = makeShapeIndex("0:0 | 0:90 # 0:0, 0:1 | 1:1, 2:1 #")
+	want := Point{ShapeCentroid(makePolyline("0:0, 0:1")).Add(ShapeCentroid(makePolyline("1:1, 2:1")).Vector)}
+	if got := ShapeIndexCentroid(index); !got.Vector.ApproxEqual(want.Vector) {
+		t.Errorf("ShapeIndexCentroid(%v) = %v, want %v", index, got, want)
+	}
+
+	p := cross1CenterHolePolygon
+	index = NewShapeIndex()
+	index.Add(makePolyline("0:0, 0:1"))
+	index.Add(p)
+	if got, want := ShapeIndexCentroid(index), p.Centroid(); !got.Vector.ApproxEqual(want.Vector) {
+		t.Errorf("ShapeIndexCentroid(%v) = %v, want %v", index, got, want)
+	}
+
+	if got := ShapeIndexCentroid(NewShapeIndex()); got != (Point{}) {
+		t.Errorf("ShapeIndexCentroid(empty index) = %v, want %v", got, Point{})
+	}
+}
@@ -15,6 +15,8 @@
 package s2
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/rubenpoppe/geo/r3"
@@ -40,6 +42,27 @@ func TestShapeIndexBasics(t *testing.T) {
 	}
 }
 
+func TestShapeIndexResetAndRebuild(t *testing.T) {
+	loop := makeLoop("0:0, 0:10, 10:10, 10:0")
+	index := NewShapeIndex()
+	index.Add(loop)
+	index.Build()
+
+	// Shapes added after a Reset must be indexed from scratch.
+	index.Reset()
+	index.Add(loop)
+	if got := index.NumEdges(); got != 4 {
+		t.Errorf("index.NumEdges() = %d, want 4", got)
+	}
+	q := NewContainsPointQuery(index, VertexModelOpen)
+	if p := parsePoint("5:5"); !q.Contains(p) {
+		t.Errorf("ContainsPointQuery(%v) = false after rebuild, want true", p)
+	}
+	if p := parsePoint("-5:-5"); q.Contains(p) {
+		t.Errorf("ContainsPointQuery(%v) = true after rebuild, want false", p)
+	}
+}
+
 func TestShapeEdgeComparisons(t *testing.T) {
 	tests := []struct {
 		a, b Edge
@@ -116,6 +139,10 @@ func TestShapeIndexCellBasics(t *testing.T) {
 	if got := s.findByShapeID(7); got != c2 {
 		t.Errorf("%v.findByShapeID(%v) = %v, want %v", s, 7, got, c2)
 	}
+
+	if got, want := s.ShapeIDs(), []int32{0, 7, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("%v.ShapeIDs() = %v, want %v", s, got, want)
+	}
 }
 
 // validateEdge determines whether or not the edge defined by points A and B should be
@@ -545,6 +572,154 @@ func TestShapeIndexNumEdgesUpTo(t *testing.T) {
 	}
 }
 
+// checkShapeIndexesEqual checks that two indexes of the same shapes have
+// identical cells.
+func checkShapeIndexesEqual(t *testing.T, label string, got, want *ShapeIndex) {
+	t.Helper()
+	gotIt, wantIt := got.Iterator(), want.Iterator()
+	for ; !gotIt.Done() && !wantIt.Done(); gotIt.Next() {
+		if gotIt.CellID() != wantIt.CellID() {
+			t.Errorf("%s: cell = %v, want %v", label, gotIt.CellID(), wantIt.CellID())
+			return
+		}
+		if !reflect.DeepEqual(gotIt.IndexCell(), wantIt.IndexCell()) {
+			t.Errorf("%s: cell %v = %+v, want %+v", label, gotIt.CellID(), gotIt.IndexCell(), wantIt.IndexCell())
+		}
+		wantIt.Next()
+	}
+	if !gotIt.Done() || !wantIt.Done() {
+		t.Errorf("%s: got %d cells, want %d", label, len(got.cells), len(want.cells))
+	}
+}
+
+// addParallelBuildShapes adds shapes to the index that cover many faces and
+// have enough edges to be built concurrently, including loops whose vertices
+// lie on the boundaries of cells.
+func addParallelBuildShapes(index *ShapeIndex) {
+	fractal := newFractal()
+	fractal.setLevelForApproxMaxEdges(20000)
+	index.Add(fractal.makeLoop(randomFrameAtPoint(PointFromCoords(1, -1, -1)), s1.Degree*40))
+	index.Add(RegularLoop(PointFromCoords(0, 0, 1), s1.Degree*80, 10000))
+	index.Add(PolygonFromCellUnionBorder(CellUnion{
+		CellIDFromFace(0).ChildBeginAtLevel(3),
+		CellIDFromFace(2).ChildBeginAtLevel(5),
+		CellIDFromFace(4).ChildBeginAtLevel(1),
+		CellIDFromFace(5),
+	}))
+	index.Add(makePolyline("0:0, 10:170, -20:-100, 45:45"))
+	var points PointVector
+	for i := 0; i < 5000; i++ {
+		points = append(points, randomPoint())
+	}
+	index.Add(&points)
+}
+
+func TestShapeIndexParallelBuild(t *testing.T) {
+	serial := NewShapeIndex()
+	addParallelBuildShapes(serial)
+	serial.Build()
+
+	for _, workers := range []int{2, 4, 16} {
+		index := NewShapeIndexWithOptions(&ShapeIndexOptions{Workers: workers})
+		for i := int32(0); i < serial.nextID; i++ {
+			index.Add(serial.Shape(i))
+		}
+		checkShapeIndexesEqual(t, fmt.Sprintf("%d workers", workers), index, serial)
+	}
+}
+
+func TestShapeIndexParallelBuildUpdates(t *testing.T) {
+	// Shapes that are removed and added again are indexed the same way by a
+	// parallel build with a small memory budget as by a serial build.
+	serial := NewShapeIndex()
+	addParallelBuildShapes(serial)
+	var shapes []Shape
+	for i := int32(0); i < serial.nextID; i++ {
+		shapes = append(shapes, serial.Shape(i))
+	}
+	parallel := NewShapeIndexWithOptions(&ShapeIndexOptions{Workers: 4, MemoryBudget: 1 << 16})
+	for _, shape := range shapes {
+		parallel.Add(shape)
+	}
+	checkShapeIndexesEqual(t, "initial build", parallel, serial)
+
+	for _, index := range []*ShapeIndex{serial, parallel} {
+		index.Remove(shapes[1])
+		index.Remove(shapes[3])
+	}
+	checkShapeIndexesEqual(t, "after removing shapes", parallel, serial)
+
+	// Removals and additions that are pending together.
+	for _, index := range []*ShapeIndex{serial, parallel} {
+		index.Remove(shapes[0])
+		index.Add(shapes[3])
+		index.Add(shapes[1])
+		index.Add(RegularLoop(PointFromCoords(1, 1, 0), s1.Degree*30, 5000))
+	}
+	checkShapeIndexesEqual(t, "after adding shapes again", parallel, serial)
+
+	for _, index := range []*ShapeIndex{serial, parallel} {
+		index.Reset()
+		for _, shape := range shapes {
+			index.Add(shape)
+		}
+	}
+	checkShapeIndexesEqual(t, "after a reset", parallel, serial)
+}
+
+func TestShapeIndexMemoryBudget(t *testing.T) {
+	var shapes []Shape
+	for i := 0; i < 8; i++ {
+		c := CapFromCenterAngle(randomPoint(), s1.Degree*10)
+		shapes = append(shapes, RegularLoop(c.Center(), c.Radius(), 50))
+		shapes = append(shapes, makePolyline(fmt.Sprintf("%d:0, %d:20, %d:40", i, i+5, i)))
+	}
+
+	// A budget of one byte puts every shape in its own batch.
+	budget := &ShapeIndexOptions{MemoryBudget: 1}
+	index := NewShapeIndexWithOptions(budget)
+	for _, shape := range shapes {
+		index.Add(shape)
+	}
+	if got, want := len(index.additionBatches()), len(shapes); got != want {
+		t.Errorf("len(additionBatches()) = %d, want %d", got, want)
+	}
+	quadraticValidate(t, index)
+	testIteratorMethods(t, index)
+
+	// Batches are applied the same way with any number of workers.
+	parallel := NewShapeIndexWithOptions(&ShapeIndexOptions{MemoryBudget: 1, Workers: 4})
+	for _, shape := range shapes {
+		parallel.Add(shape)
+	}
+	checkShapeIndexesEqual(t, "4 workers", parallel, index)
+
+	large := NewShapeIndexWithOptions(&ShapeIndexOptions{MemoryBudget: 1 << 30})
+	for _, shape := range shapes {
+		large.Add(shape)
+	}
+	if got := len(large.additionBatches()); got != 1 {
+		t.Errorf("len(additionBatches()) = %d, want 1", got)
+	}
+}
+
+func TestShapeIndexIncrementalAdditions(t *testing.T) {
+	index := NewShapeIndex()
+	index.Add(makeLoop("0:0, 0:10, 10:10, 10:0"))
+	quadraticValidate(t, index)
+
+	// Each of these overlaps the existing index cells, so they are absorbed
+	// and rebuilt with the new edges.
+	index.Add(makeLoop("5:5, 5:15, 15:15, 15:5"))
+	index.Add(makePolyline("-5:-5, 20:20"))
+	quadraticValidate(t, index)
+
+	index.Add(RegularLoop(parsePoint("3:3"), s1.Degree, 100))
+	index.Add(makeLoop("-60:100, -60:110, -50:110, -50:100"))
+	quadraticValidate(t, index)
+	testIteratorMethods(t, index)
+}
+
 // TODO(roberts): Differences from C++:
 // TestShapeIndexSimpleUpdates(t *testing.T) {}
 // TestShapeIndexRandomUpdates(t *testing.T) {}
@@ -567,3 +742,19 @@ func BenchmarkShapeIndexIteratorLocatePoint(b *testing.B) {
 		it.LocatePoint(randomPoint())
 	}
 }
+
+func BenchmarkShapeIndexBuild(b *testing.B) {
+	fractal := newFractal()
+	fractal.setLevelForApproxMaxEdges(100000)
+	loop := fractal.makeLoop(randomFrameAtPoint(randomPoint()), s1.Degree*60)
+
+	for _, workers := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				index := NewShapeIndexWithOptions(&ShapeIndexOptions{Workers: workers})
+				index.Add(loop)
+				index.Build()
+			}
+		})
+	}
+}
@@ -31,9 +31,14 @@ const (
 	CrossingTypeNonAdjacent
 )
 
-// rangeIterator is a wrapper over ShapeIndexIterator with extra methods
+// RangeIterator is a wrapper over ShapeIndexIterator with extra methods
 // that are useful for merging the contents of two or more ShapeIndexes.
-type rangeIterator struct {
+//
+// A typical merge join advances two iterators in lockstep: whenever the
+// current cells are disjoint, the iterator that is behind is moved forward
+// with SeekTo; otherwise one cell contains the other and the pair can be
+// processed.
+type RangeIterator struct {
 	it *ShapeIndexIterator
 	// The min and max leaf cell ids covered by the current cell. If done() is
 	// true, these methods return a value larger than any valid cell id.
@@ -41,40 +46,61 @@ type rangeIterator struct {
 	rangeMax CellID
 }
 
-// newRangeIterator creates a new rangeIterator positioned at the first cell of the given index.
-func newRangeIterator(index *ShapeIndex) *rangeIterator {
-	r := &rangeIterator{
+// NewRangeIterator creates a new RangeIterator positioned at the first cell of the given index.
+func NewRangeIterator(index *ShapeIndex) *RangeIterator {
+	r := &RangeIterator{
 		it: index.Iterator(),
 	}
 	r.refresh()
 	return r
 }
 
-func (r *rangeIterator) cellID() CellID             { return r.it.CellID() }
-func (r *rangeIterator) indexCell() *ShapeIndexCell { return r.it.IndexCell() }
-func (r *rangeIterator) next()                      { r.it.Next(); r.refresh() }
-func (r *rangeIterator) done() bool                 { return r.it.Done() }
+// CellID returns the CellID of the current index cell.
+func (r *RangeIterator) CellID() CellID { return r.it.CellID() }
+
+// IndexCell returns the current index cell.
+func (r *RangeIterator) IndexCell() *ShapeIndexCell { return r.it.IndexCell() }
+
+// Next advances the iterator to the next index cell.
+func (r *RangeIterator) Next() { r.it.Next(); r.refresh() }
+
+// Done reports if the iterator is positioned past the last index cell.
+func (r *RangeIterator) Done() bool { return r.it.Done() }
 
-// seekTo positions the iterator at the first cell that overlaps or follows
+// RangeMin returns the minimum leaf cell id covered by the current cell. If
+// Done is true, it returns a value larger than any valid cell id.
+func (r *RangeIterator) RangeMin() CellID { return r.rangeMin }
+
+// RangeMax returns the maximum leaf cell id covered by the current cell. If
+// Done is true, it returns a value larger than any valid cell id.
+func (r *RangeIterator) RangeMax() CellID { return r.rangeMax }
+
+// SeekTo positions the iterator at the first cell that overlaps or follows
 // the current range minimum of the target iterator, i.e. such that its
-// rangeMax >= target.rangeMin.
-func (r *rangeIterator) seekTo(target *rangeIterator) {
-	r.it.seek(target.rangeMin)
-	// If the current cell does not overlap target, it is possible that the
-	// previous cell is the one we are looking for. This can only happen when
-	// the previous cell contains target but has a smaller CellID.
-	if r.it.Done() || r.it.CellID().RangeMin() > target.rangeMax {
-		if r.it.Prev() && r.it.CellID().RangeMax() < target.cellID() {
+// RangeMax >= target.RangeMin.
+func (r *RangeIterator) SeekTo(target *RangeIterator) {
+	r.seekToRange(target.rangeMin, target.rangeMax)
+}
+
+// seekToRange positions the iterator at the first cell that overlaps or
+// follows the given range of leaf cells.
+func (r *RangeIterator) seekToRange(rangeMin, rangeMax CellID) {
+	r.it.seek(rangeMin)
+	// If the current cell does not overlap the range, it is possible that
+	// the previous cell is the one we are looking for. This can only happen
+	// when the previous cell contains the range but has a smaller CellID.
+	if r.it.Done() || r.it.CellID().RangeMin() > rangeMax {
+		if r.it.Prev() && r.it.CellID().RangeMax() < rangeMin {
 			r.it.Next()
 		}
 	}
 	r.refresh()
 }
 
-// seekBeyond positions the iterator at the first cell that follows the current
+// SeekBeyond positions the iterator at the first cell that follows the current
 // range minimum of the target iterator. i.e. the first cell such that its
-// rangeMin > target.rangeMax.
-func (r *rangeIterator) seekBeyond(target *rangeIterator) {
+// RangeMin > target.RangeMax.
+func (r *RangeIterator) SeekBeyond(target *RangeIterator) {
 	r.it.seek(target.rangeMax.Next())
 	if !r.it.Done() && r.it.CellID().RangeMin() <= target.rangeMax {
 		r.it.Next()
@@ -83,9 +109,9 @@ func (r *rangeIterator) seekBeyond(target *rangeIterator) {
 }
 
 // refresh updates the iterators min and max values.
-func (r *rangeIterator) refresh() {
-	r.rangeMin = r.cellID().RangeMin()
-	r.rangeMax = r.cellID().RangeMax()
+func (r *RangeIterator) refresh() {
+	r.rangeMin = r.CellID().RangeMin()
+	r.rangeMax = r.CellID().RangeMax()
 }
 
 // referencePointForShape is a helper function for implementing various Shapes
@@ -102,7 +128,7 @@ func (r *rangeIterator) refresh() {
 // is the same as if that edge pair were not present. Therefore shapes that
 // consist only of degenerate loop(s) are either empty or full; by convention,
 // the shape is considered full if and only if it contains an empty loop (see
-// laxPolygon for details).
+// LaxPolygon for details).
 //
 // Determining whether a loop on the sphere contains a point is harder than
 // the corresponding problem in 2D plane geometry. It cannot be implemented
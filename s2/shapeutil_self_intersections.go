@@ -0,0 +1,75 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import "sort"
+
+// EdgeIntersection is a point where two edges of a shape intersect.
+type EdgeIntersection struct {
+	// A and B are the IDs of the two edges within the shape, with A < B.
+	A, B int
+	// Point is the point where the edges intersect. If the edges share a
+	// vertex, it is that vertex.
+	Point Point
+}
+
+// FindSelfIntersections returns every point where two non-adjacent edges of
+// the given shape cross or touch, sorted by edge IDs. Adjacent edges, of the
+// form (AB, BC) or (BC, AB), are not reported at their shared vertex B, but
+// every other pair of edges that shares a vertex is: a polyline that returns
+// to one of its earlier vertices other than the first is reported as
+// intersecting itself there.
+//
+// This is meant for polyline shapes, where the result finds loops and spikes
+// in a path such as a GPS track, but it also works for closed shapes such as
+// loops, whose last edge is adjacent to their first. Any two edges that
+// share a vertex in this way are treated as adjacent, including edges of
+// different chains.
+//
+// The shape is indexed, so the running time is roughly linear in the number
+// of edges plus the number of intersections.
+func FindSelfIntersections(shape Shape) []EdgeIntersection {
+	index := NewShapeIndex()
+	index.Add(shape)
+
+	var result []EdgeIntersection
+	VisitCrossingEdgePairs(index, CrossingTypeNonAdjacent, func(a, b ShapeEdge, isInterior bool) bool {
+		result = append(result, EdgeIntersection{
+			A:     int(a.ID.EdgeID),
+			B:     int(b.ID.EdgeID),
+			Point: edgePairIntersection(a.Edge, b.Edge, isInterior),
+		})
+		return true
+	})
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].A != result[j].A {
+			return result[i].A < result[j].A
+		}
+		return result[i].B < result[j].B
+	})
+	return result
+}
+
+// edgePairIntersection returns the intersection point of two crossing edges.
+// Crossings that are not interior are at a vertex shared by the edges.
+func edgePairIntersection(a, b Edge, isInterior bool) Point {
+	if isInterior {
+		return Intersection(a.V0, a.V1, b.V0, b.V1)
+	}
+	if a.V0 == b.V0 || a.V0 == b.V1 {
+		return a.V0
+	}
+	return a.V1
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"testing"
+)
+
+func TestFindSelfIntersections(t *testing.T) {
+	type crossing struct {
+		a, b  int
+		point string
+	}
+	tests := []struct {
+		label    string
+		polyline string
+		want     []crossing
+	}{
+		{"empty", "", nil},
+		{"single vertex", "0:0", nil},
+		{"straight", "0:0, 0:1, 0:2, 0:3", nil},
+		{"zigzag", "0:0, 1:1, 0:2, 1:3, 0:4", nil},
+		{"figure eight", "0:0, 2:2, 2:0, 0:2", []crossing{{0, 2, "1:1"}}},
+		// The last edge of a closed polyline is adjacent to the first.
+		{"closed", "0:0, 0:2, 2:2, 2:0, 0:0", nil},
+		// A loop back to an earlier vertex touches it.
+		{"touching", "0:0, 0:2, 2:2, 2:0, 0:1", []crossing{{0, 3, "0:1"}}},
+		{"loop", "0:0, 0:4, 2:4, 2:2, -2:2", []crossing{{0, 3, "0:2"}}},
+		{"two crossings", "0:0, 0:4, 2:4, 2:2, -2:2, -2:3, 1:3", []crossing{{0, 3, "0:2"}, {0, 5, "0:3"}}},
+	}
+	for _, test := range tests {
+		p := makePolyline(test.polyline)
+		got := p.SelfIntersections()
+		if len(got) != len(test.want) {
+			t.Errorf("%s: SelfIntersections() = %v, want %v", test.label, got, test.want)
+			continue
+		}
+		for i, want := range test.want {
+			if got[i].A != want.a || got[i].B != want.b {
+				t.Errorf("%s: intersection %d is between edges (%d, %d), want (%d, %d)", test.label, i, got[i].A, got[i].B, want.a, want.b)
+			}
+			if wantPoint := parsePoint(want.point); !got[i].Point.approxEqual(wantPoint, 1e-4) {
+				t.Errorf("%s: intersection %d is at %v, want %v", test.label, i, got[i].Point, wantPoint)
+			}
+		}
+	}
+}
+
+func TestFindSelfIntersectionsLoop(t *testing.T) {
+	// The closing edge of a loop is adjacent to its first edge, so only the
+	// crossing in the middle of the bowtie is reported.
+	got := FindSelfIntersections(makeLoop("0:0, 2:2, 2:0, 0:2"))
+	if len(got) != 1 || got[0].A != 0 || got[0].B != 2 || !got[0].Point.approxEqual(parsePoint("1:1"), 1e-4) {
+		t.Errorf("FindSelfIntersections(bowtie) = %v, want the crossing of edges 0 and 2 at 1:1", got)
+	}
+	if got := FindSelfIntersections(makeLoop("0:0, 0:1, 1:1")); len(got) != 0 {
+		t.Errorf("FindSelfIntersections(triangle) = %v, want none", got)
+	}
+}
+
+func TestFindSelfIntersectionsRandom(t *testing.T) {
+	for iter := 0; iter < 20; iter++ {
+		// A random walk in a small area crosses itself many times.
+		c := CapFromCenterAngle(randomPoint(), kmToAngle(10))
+		p := make(Polyline, 50+randomUniformInt(200))
+		for i := range p {
+			p[i] = samplePointFromCap(c)
+		}
+
+		got := FindSelfIntersections(&p)
+		index := 0
+		for i := 0; i < p.NumEdges(); i++ {
+			a := p.Edge(i)
+			for j := i + 2; j < p.NumEdges(); j++ {
+				b := p.Edge(j)
+				sign := CrossingSign(a.V0, a.V1, b.V0, b.V1)
+				if sign == DoNotCross {
+					continue
+				}
+				if index >= len(got) {
+					t.Fatalf("FindSelfIntersections(%v) is missing the intersection of edges %d and %d", p, i, j)
+				}
+				if got[index].A != i || got[index].B != j {
+					t.Fatalf("FindSelfIntersections(%v)[%d] is between edges (%d, %d), want (%d, %d)", p, index, got[index].A, got[index].B, i, j)
+				}
+				if sign == Cross {
+					if want := Intersection(a.V0, a.V1, b.V0, b.V1); got[index].Point != want {
+						t.Errorf("intersection of edges %d and %d = %v, want %v", i, j, got[index].Point, want)
+					}
+				}
+				index++
+			}
+		}
+		if index != len(got) {
+			t.Errorf("FindSelfIntersections(%v) has %d intersections, want %d", p, len(got), index)
+		}
+	}
+}
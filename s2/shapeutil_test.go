@@ -52,24 +52,24 @@ func TestShapeutilContainsBruteForceConsistentWithLoop(t *testing.T) {
 func TestShapeutilRangeIteratorNext(t *testing.T) {
 	// Create an index with one point each on CellID faces 0, 1, and 2.
 	index := makeShapeIndex("0:0 | 0:90 | 90:0 # #")
-	it := newRangeIterator(index)
+	it := NewRangeIterator(index)
 
-	if got, want := it.cellID().Face(), 0; got != want {
+	if got, want := it.CellID().Face(), 0; got != want {
 		t.Errorf("it.CellID().Face() = %v, want %v", got, want)
 	}
-	it.next()
+	it.Next()
 
-	if got, want := it.cellID().Face(), 1; got != want {
+	if got, want := it.CellID().Face(), 1; got != want {
 		t.Errorf("it.CellID().Face() = %v, want %v", got, want)
 	}
-	it.next()
+	it.Next()
 
-	if got, want := it.cellID().Face(), 2; got != want {
+	if got, want := it.CellID().Face(), 2; got != want {
 		t.Errorf("it.CellID().Face() = %v, want %v", got, want)
 	}
-	it.next()
+	it.Next()
 
-	if !it.done() {
+	if !it.Done() {
 		t.Errorf("iterator over index of three items should be done after 3 calls to next")
 	}
 }
@@ -78,30 +78,30 @@ func TestShapeutilRangeIteratorEmptyIndex(t *testing.T) {
 	empty := makeShapeIndex("# #")
 	nonEmpty := makeShapeIndex("0:0 # #")
 
-	emptyIter := newRangeIterator(empty)
-	nonEmptyIter := newRangeIterator(nonEmpty)
+	emptyIter := NewRangeIterator(empty)
+	nonEmptyIter := NewRangeIterator(nonEmpty)
 
-	if !emptyIter.done() {
-		t.Errorf("the rangeIterator on an empty ShapeIndex should be done at creation")
+	if !emptyIter.Done() {
+		t.Errorf("the RangeIterator on an empty ShapeIndex should be done at creation")
 	}
 
-	emptyIter.seekTo(nonEmptyIter)
-	if !emptyIter.done() {
+	emptyIter.SeekTo(nonEmptyIter)
+	if !emptyIter.Done() {
 		t.Errorf("seeking in the range iterator on an empty index to a cell should hit the end")
 	}
 
-	emptyIter.seekBeyond(nonEmptyIter)
-	if !emptyIter.done() {
+	emptyIter.SeekBeyond(nonEmptyIter)
+	if !emptyIter.Done() {
 		t.Errorf("seeking in the range iterator on an empty index beyond a cell should hit the end")
 	}
 
-	emptyIter.seekTo(emptyIter)
-	if !emptyIter.done() {
+	emptyIter.SeekTo(emptyIter)
+	if !emptyIter.Done() {
 		t.Errorf("seeking in the range iterator on an empty index to a its current position should hit the end")
 	}
 
-	emptyIter.seekBeyond(emptyIter)
-	if !emptyIter.done() {
+	emptyIter.SeekBeyond(emptyIter)
+	if !emptyIter.Done() {
 		t.Errorf("seeking in the range iterator on an empty index beyond itself should hit the end")
 	}
 }
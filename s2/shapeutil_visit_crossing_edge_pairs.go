@@ -0,0 +1,183 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+// EdgePairVisitor is a function that is called for each pair of crossing
+// edges. isInterior reports whether the crossing is at a point interior to
+// both edges. Returning false stops the visit.
+type EdgePairVisitor func(a, b ShapeEdge, isInterior bool) bool
+
+// VisitCrossingEdgePairs visits all pairs of crossing edges in the given
+// ShapeIndex, terminating early if the visitor returns false (in which case
+// VisitCrossingEdgePairs returns false as well). crossType indicates whether
+// all crossings should be visited, or only interior crossings.
+//
+// Each pair is visited once, with the edge that has the smaller ShapeEdgeID
+// first. If crossType is CrossingTypeNonAdjacent, pairs of adjacent edges of
+// the form (AB, BC) or (BC, AB) are not reported. This includes the last and
+// first edges of a closed loop.
+func VisitCrossingEdgePairs(index *ShapeIndex, crossType CrossingType, visitor EdgePairVisitor) bool {
+	v := newCrossingPairVisitor(crossType, visitor)
+	var edges []ShapeEdge
+	for it := index.Iterator(); !it.Done(); it.Next() {
+		edges = appendShapeEdges(edges[:0], index, it.IndexCell())
+		for i := 0; i+1 < len(edges); i++ {
+			if !v.visitCrossings(edges[i], edges[i+1:]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// VisitCrossingEdgePairsBetween visits all pairs of crossing edges where the
+// first edge is from index a and the second is from index b, terminating
+// early if the visitor returns false (in which case the function returns
+// false as well). crossType indicates whether all crossings should be
+// visited, or only interior crossings. CrossingTypeNonAdjacent only excludes
+// edges of the same index, so here it is equivalent to CrossingTypeAll.
+//
+// Each pair is visited once.
+func VisitCrossingEdgePairsBetween(a, b *ShapeIndex, crossType CrossingType, visitor EdgePairVisitor) bool {
+	if crossType == CrossingTypeNonAdjacent {
+		crossType = CrossingTypeAll
+	}
+	v := newCrossingPairVisitor(crossType, visitor)
+	var aEdges, bEdges []ShapeEdge
+	return visitIndexCellPairs(a, b, func(ca, cb *ShapeIndexCell) bool {
+		aEdges = appendShapeEdges(aEdges[:0], a, ca)
+		bEdges = appendShapeEdges(bEdges[:0], b, cb)
+		for _, ae := range aEdges {
+			if !v.visitCrossings(ae, bEdges) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// crossingPairVisitor tests edge pairs for crossings and reports each
+// crossing pair to the visitor at most once. The same pair of edges can be
+// present in several index cells.
+type crossingPairVisitor struct {
+	interiorOnly bool
+	nonAdjacent  bool
+	visitor      EdgePairVisitor
+	visited      map[[2]ShapeEdgeID]bool
+}
+
+func newCrossingPairVisitor(crossType CrossingType, visitor EdgePairVisitor) *crossingPairVisitor {
+	return &crossingPairVisitor{
+		interiorOnly: crossType == CrossingTypeInterior,
+		nonAdjacent:  crossType == CrossingTypeNonAdjacent,
+		visitor:      visitor,
+		visited:      make(map[[2]ShapeEdgeID]bool),
+	}
+}
+
+// visitCrossings calls the visitor for every edge in bs that crosses a.
+// It returns false if the visitor returned false.
+func (v *crossingPairVisitor) visitCrossings(a ShapeEdge, bs []ShapeEdge) bool {
+	if len(bs) == 0 {
+		return true
+	}
+	crosser := NewChainEdgeCrosser(a.Edge.V0, a.Edge.V1, bs[0].Edge.V0)
+	for i, b := range bs {
+		if i > 0 && bs[i-1].Edge.V1 != b.Edge.V0 {
+			crosser.RestartAt(b.Edge.V0)
+		}
+		sign := crosser.ChainCrossingSign(b.Edge.V1)
+		// MaybeCross means that the edges share a vertex, which is a crossing
+		// for every crossing type other than interior.
+		if sign == DoNotCross || sign == MaybeCross && v.interiorOnly {
+			continue
+		}
+		if v.nonAdjacent && (a.Edge.V1 == b.Edge.V0 || b.Edge.V1 == a.Edge.V0) {
+			continue
+		}
+		key := [2]ShapeEdgeID{a.ID, b.ID}
+		if v.visited[key] {
+			continue
+		}
+		v.visited[key] = true
+		if !v.visitor(a, b, sign == Cross) {
+			return false
+		}
+	}
+	return true
+}
+
+// appendShapeEdges appends all the edges in the given index cell to edges,
+// ordered by ShapeEdgeID.
+func appendShapeEdges(edges []ShapeEdge, index *ShapeIndex, cell *ShapeIndexCell) []ShapeEdge {
+	for _, clipped := range cell.shapes {
+		shape := index.Shape(clipped.shapeID)
+		for _, e := range clipped.edges {
+			edges = append(edges, ShapeEdge{
+				ID:   ShapeEdgeID{clipped.shapeID, int32(e)},
+				Edge: shape.Edge(e),
+			})
+		}
+	}
+	return edges
+}
+
+// visitIndexCellPairs calls the visitor for every pair of cells (one from
+// each index) such that one cell contains the other. It returns false if the
+// visitor returned false.
+func visitIndexCellPairs(a, b *ShapeIndex, visitor func(ca, cb *ShapeIndexCell) bool) bool {
+	ai := NewRangeIterator(a)
+	bi := NewRangeIterator(b)
+	for !ai.Done() || !bi.Done() {
+		if ai.rangeMax < bi.rangeMin {
+			// The A and B cells are disjoint, and A is before B.
+			ai.SeekTo(bi)
+		} else if bi.rangeMax < ai.rangeMin {
+			// The A and B cells are disjoint, and B is before A.
+			bi.SeekTo(ai)
+		} else {
+			// One cell contains the other.
+			aLSB, bLSB := ai.CellID().lsb(), bi.CellID().lsb()
+			switch {
+			case aLSB > bLSB:
+				// A is larger, so visit all the B cells it contains.
+				for !bi.Done() && bi.rangeMin <= ai.rangeMax {
+					if !visitor(ai.IndexCell(), bi.IndexCell()) {
+						return false
+					}
+					bi.Next()
+				}
+				ai.Next()
+			case aLSB < bLSB:
+				// B is larger, so visit all the A cells it contains.
+				for !ai.Done() && ai.rangeMin <= bi.rangeMax {
+					if !visitor(ai.IndexCell(), bi.IndexCell()) {
+						return false
+					}
+					ai.Next()
+				}
+				bi.Next()
+			default:
+				// The cells are the same.
+				if !visitor(ai.IndexCell(), bi.IndexCell()) {
+					return false
+				}
+				ai.Next()
+				bi.Next()
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,211 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"reflect"
+	"testing"
+)
+
+// edgePair is a crossing edge pair along with whether it is an interior
+// crossing.
+type edgePair struct {
+	a, b       ShapeEdgeID
+	isInterior bool
+}
+
+// indexEdges returns all the edges of the index ordered by ShapeEdgeID.
+func indexEdges(index *ShapeIndex) []ShapeEdge {
+	var edges []ShapeEdge
+	for it := NewEdgeIterator(index); !it.Done(); it.Next() {
+		edges = append(edges, ShapeEdge{ID: it.ShapeEdgeID(), Edge: it.Edge()})
+	}
+	return edges
+}
+
+// crossingEdgePairsBruteForce returns the edge pairs of as and bs that cross
+// according to crossType. If same is true, as and bs are the same edges and
+// each pair is only tested once.
+func crossingEdgePairsBruteForce(as, bs []ShapeEdge, same bool, crossType CrossingType) map[edgePair]bool {
+	result := make(map[edgePair]bool)
+	for i, a := range as {
+		start := 0
+		if same {
+			start = i + 1
+		}
+		for k := start; k < len(bs); k++ {
+			b := bs[k]
+			if same && crossType == CrossingTypeNonAdjacent && (a.Edge.V1 == b.Edge.V0 || b.Edge.V1 == a.Edge.V0) {
+				continue
+			}
+			sign := CrossingSign(a.Edge.V0, a.Edge.V1, b.Edge.V0, b.Edge.V1)
+			if sign == Cross || sign == MaybeCross && crossType != CrossingTypeInterior {
+				result[edgePair{a.ID, b.ID, sign == Cross}] = true
+			}
+		}
+	}
+	return result
+}
+
+func collectEdgePairs(t *testing.T) (map[edgePair]bool, EdgePairVisitor) {
+	got := make(map[edgePair]bool)
+	return got, func(a, b ShapeEdge, isInterior bool) bool {
+		p := edgePair{a.ID, b.ID, isInterior}
+		if got[p] {
+			t.Errorf("edge pair %v visited twice", p)
+		}
+		got[p] = true
+		return true
+	}
+}
+
+func TestVisitCrossingEdgePairs(t *testing.T) {
+	// Three polylines: the second crosses every edge of the first at an
+	// interior point, and the third touches the first at a vertex. The first
+	// polyline also crosses itself.
+	index := makeShapeIndex("# 0:0, 2:2, 2:0, 0:2 | 0:1, 3:1 | 2:2, 3:3 #")
+	cross := func(s1, e1, s2, e2 int32, interior bool) edgePair {
+		return edgePair{ShapeEdgeID{s1, e1}, ShapeEdgeID{s2, e2}, interior}
+	}
+	tests := []struct {
+		crossType CrossingType
+		want      map[edgePair]bool
+	}{
+		{CrossingTypeInterior, map[edgePair]bool{
+			cross(0, 0, 0, 2, true): true,
+			cross(0, 0, 1, 0, true): true,
+			cross(0, 1, 1, 0, true): true,
+			cross(0, 2, 1, 0, true): true,
+		}},
+		{CrossingTypeAll, map[edgePair]bool{
+			cross(0, 0, 0, 1, false): true,
+			cross(0, 0, 0, 2, true):  true,
+			cross(0, 1, 0, 2, false): true,
+			cross(0, 0, 1, 0, true):  true,
+			cross(0, 1, 1, 0, true):  true,
+			cross(0, 2, 1, 0, true):  true,
+			cross(0, 0, 2, 0, false): true,
+			cross(0, 1, 2, 0, false): true,
+		}},
+		{CrossingTypeNonAdjacent, map[edgePair]bool{
+			cross(0, 0, 0, 2, true):  true,
+			cross(0, 0, 1, 0, true):  true,
+			cross(0, 1, 1, 0, true):  true,
+			cross(0, 2, 1, 0, true):  true,
+			cross(0, 1, 2, 0, false): true,
+		}},
+	}
+	for _, test := range tests {
+		got, visitor := collectEdgePairs(t)
+		if !VisitCrossingEdgePairs(index, test.crossType, visitor) {
+			t.Errorf("VisitCrossingEdgePairs(%v) = false, want true", test.crossType)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("VisitCrossingEdgePairs(%v) = %v, want %v", test.crossType, got, test.want)
+		}
+	}
+
+	// Stopping early.
+	calls := 0
+	if VisitCrossingEdgePairs(index, CrossingTypeAll, func(a, b ShapeEdge, isInterior bool) bool {
+		calls++
+		return false
+	}) {
+		t.Errorf("VisitCrossingEdgePairs with a stopping visitor = true, want false")
+	}
+	if calls != 1 {
+		t.Errorf("stopping visitor was called %d times, want 1", calls)
+	}
+}
+
+func TestVisitCrossingEdgePairsClosedLoop(t *testing.T) {
+	// Every pair of edges of a triangle shares a vertex, including the last
+	// and first edges, so they are all adjacent.
+	index := makeShapeIndex("# # 0:0, 0:1, 1:1")
+	touch := func(e1, e2 int32) edgePair {
+		return edgePair{ShapeEdgeID{0, e1}, ShapeEdgeID{0, e2}, false}
+	}
+	tests := []struct {
+		crossType CrossingType
+		want      map[edgePair]bool
+	}{
+		{CrossingTypeInterior, map[edgePair]bool{}},
+		{CrossingTypeAll, map[edgePair]bool{touch(0, 1): true, touch(0, 2): true, touch(1, 2): true}},
+		{CrossingTypeNonAdjacent, map[edgePair]bool{}},
+	}
+	for _, test := range tests {
+		got, visitor := collectEdgePairs(t)
+		VisitCrossingEdgePairs(index, test.crossType, visitor)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("VisitCrossingEdgePairs(%v) = %v, want %v", test.crossType, got, test.want)
+		}
+	}
+}
+
+// randomCrossingIndex returns an index of random polylines within a cap, with
+// enough edges to be split into many index cells.
+func randomCrossingIndex(c Cap) *ShapeIndex {
+	index := NewShapeIndex()
+	for i := 0; i < 10; i++ {
+		line := make(Polyline, 20)
+		for k := range line {
+			line[k] = samplePointFromCap(c)
+		}
+		index.Add(&line)
+	}
+	return index
+}
+
+func TestVisitCrossingEdgePairsRandom(t *testing.T) {
+	for iter := 0; iter < 5; iter++ {
+		c := CapFromCenterAngle(randomPoint(), 0.01)
+		index := randomCrossingIndex(c)
+		edges := indexEdges(index)
+		for _, crossType := range []CrossingType{CrossingTypeInterior, CrossingTypeAll, CrossingTypeNonAdjacent} {
+			got, visitor := collectEdgePairs(t)
+			VisitCrossingEdgePairs(index, crossType, visitor)
+			if want := crossingEdgePairsBruteForce(edges, edges, true, crossType); !reflect.DeepEqual(got, want) {
+				t.Errorf("VisitCrossingEdgePairs(%v) visited %d pairs, want %d", crossType, len(got), len(want))
+			}
+		}
+	}
+}
+
+func TestVisitCrossingEdgePairsBetween(t *testing.T) {
+	for iter := 0; iter < 5; iter++ {
+		c := CapFromCenterAngle(randomPoint(), 0.01)
+		a, b := randomCrossingIndex(c), randomCrossingIndex(c)
+		// Share a vertex between the indexes, so that there is a crossing
+		// which is not interior.
+		line := Polyline{indexEdges(a)[0].Edge.V0, samplePointFromCap(c)}
+		b.Add(&line)
+		aEdges, bEdges := indexEdges(a), indexEdges(b)
+		for _, crossType := range []CrossingType{CrossingTypeInterior, CrossingTypeAll, CrossingTypeNonAdjacent} {
+			got, visitor := collectEdgePairs(t)
+			VisitCrossingEdgePairsBetween(a, b, crossType, visitor)
+			if want := crossingEdgePairsBruteForce(aEdges, bEdges, false, crossType); !reflect.DeepEqual(got, want) {
+				t.Errorf("VisitCrossingEdgePairsBetween(%v) visited %d pairs, want %d", crossType, len(got), len(want))
+			}
+		}
+	}
+
+	a := makeShapeIndex("# 0:0, 2:2 #")
+	if !VisitCrossingEdgePairsBetween(a, NewShapeIndex(), CrossingTypeAll, func(ShapeEdge, ShapeEdge, bool) bool {
+		t.Errorf("visitor called for an empty index")
+		return true
+	}) {
+		t.Errorf("VisitCrossingEdgePairsBetween(empty) = false, want true")
+	}
+}
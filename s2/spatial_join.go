@@ -0,0 +1,562 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"sort"
+
+	"github.com/rubenpoppe/geo/s1"
+)
+
+// SpatialJoinMode specifies which pairs of shapes are reported by SpatialJoin.
+type SpatialJoinMode int
+
+const (
+	// SpatialJoinIntersects reports pairs of shapes that intersect. Shapes
+	// are treated as closed sets, so shapes that only touch at their
+	// boundaries are reported, and the distance of every pair is zero.
+	SpatialJoinIntersects SpatialJoinMode = iota
+
+	// SpatialJoinWithinDistance reports pairs of shapes whose minimum
+	// distance is less than or equal to the distance limit. Polygon
+	// interiors are included, so the distance is zero whenever the shapes
+	// intersect.
+	SpatialJoinWithinDistance
+
+	// SpatialJoinContains reports pairs where the shape from the first index
+	// contains the shape from the second index. Only shapes of dimension 2
+	// can contain other shapes. Boundaries are treated as closed, so for
+	// example every polygon contains itself.
+	SpatialJoinContains
+)
+
+// SpatialJoinOptions controls the behavior of SpatialJoin.
+type SpatialJoinOptions struct {
+	// Mode selects which pairs of shapes are reported.
+	Mode SpatialJoinMode
+
+	// DistanceLimit is the maximum distance between reported pairs of
+	// shapes in SpatialJoinWithinDistance mode. It is ignored by the other
+	// modes.
+	DistanceLimit s1.ChordAngle
+}
+
+// SpatialJoinResult is a pair of shapes reported by SpatialJoin.
+type SpatialJoinResult struct {
+	// ShapeIDA is the ID of the shape in the first index.
+	ShapeIDA int32
+	// ShapeIDB is the ID of the shape in the second index.
+	ShapeIDB int32
+	// Distance is the minimum distance between the two shapes in
+	// SpatialJoinWithinDistance mode, which is zero for shapes that
+	// intersect. It is zero for every pair in the other modes.
+	Distance s1.ChordAngle
+}
+
+// SpatialJoinVisitor is called for each pair of shapes reported by
+// SpatialJoin. It returns false to stop the join.
+type SpatialJoinVisitor func(r SpatialJoinResult) bool
+
+// SpatialJoin finds all pairs of shapes (A, B), where A is from index a and B
+// is from index b, that satisfy the given options, and calls the visitor for
+// each pair. Each pair is reported at most once. SpatialJoin returns false if
+// the visitor stopped the join early, and true otherwise.
+//
+// Rather than querying one index for each shape of the other, the two
+// indexes are merged by walking their cells in CellID order, so that only
+// edges in overlapping (or nearby) cells are compared.
+//
+// Pairs are reported as soon as they are found, in no particular order. In
+// SpatialJoinWithinDistance mode, the intersecting pairs are found first.
+func SpatialJoin(a, b *ShapeIndex, opts SpatialJoinOptions, visitor SpatialJoinVisitor) bool {
+	j := &spatialJoiner{
+		a:        a,
+		b:        b,
+		opts:     opts,
+		visitor:  visitor,
+		reported: make(map[[2]int32]bool),
+		indexesA: make(map[int32]*ShapeIndex),
+		indexesB: make(map[int32]*ShapeIndex),
+	}
+	switch opts.Mode {
+	case SpatialJoinContains:
+		return j.joinContains()
+	case SpatialJoinWithinDistance:
+		if !j.joinIntersects() {
+			return false
+		}
+		return j.joinWithinDistance()
+	default:
+		return j.joinIntersects()
+	}
+}
+
+// spatialJoiner holds the state of a single SpatialJoin call.
+type spatialJoiner struct {
+	a, b     *ShapeIndex
+	opts     SpatialJoinOptions
+	visitor  SpatialJoinVisitor
+	reported map[[2]int32]bool
+
+	// indexesA and indexesB hold the indexes of single shapes of a and b,
+	// which are used to measure the distance between pairs of shapes.
+	indexesA, indexesB map[int32]*ShapeIndex
+}
+
+// report calls the visitor for the given pair unless it has already been
+// reported, and returns false if the join should stop.
+func (j *spatialJoiner) report(aID, bID int32, dist s1.ChordAngle) bool {
+	key := [2]int32{aID, bID}
+	if j.reported[key] {
+		return true
+	}
+	j.reported[key] = true
+	return j.visitor(SpatialJoinResult{ShapeIDA: aID, ShapeIDB: bID, Distance: dist})
+}
+
+// joinIntersects reports all pairs of intersecting shapes.
+func (j *spatialJoiner) joinIntersects() bool {
+	// Two shapes intersect if their boundaries touch or cross, or if one
+	// contains a vertex of the other.
+	ok := visitIndexCellPairs(j.a, j.b, func(ca, cb *ShapeIndexCell) bool {
+		for _, sa := range ca.shapes {
+			for _, sb := range cb.shapes {
+				if j.reported[[2]int32{sa.shapeID, sb.shapeID}] {
+					continue
+				}
+				if j.clippedEdgesTouch(sa, sb) && !j.report(sa.shapeID, sb.shapeID, 0) {
+					return false
+				}
+			}
+		}
+		return true
+	})
+	if !ok {
+		return false
+	}
+	if !visitContainedShapes(j.a, j.b, func(aID, bID int32) bool {
+		return j.report(aID, bID, 0)
+	}) {
+		return false
+	}
+	return visitContainedShapes(j.b, j.a, func(bID, aID int32) bool {
+		return j.report(aID, bID, 0)
+	})
+}
+
+// clippedEdgesTouch reports whether any edge of sa crosses or touches any
+// edge of sb.
+func (j *spatialJoiner) clippedEdgesTouch(sa, sb *clippedShape) bool {
+	shapeA, shapeB := j.a.Shape(sa.shapeID), j.b.Shape(sb.shapeID)
+	for _, ea := range sa.edges {
+		edgeA := shapeA.Edge(ea)
+		crosser := NewEdgeCrosser(edgeA.V0, edgeA.V1)
+		for _, eb := range sb.edges {
+			edgeB := shapeB.Edge(eb)
+			if crosser.CrossingSign(edgeB.V0, edgeB.V1) != DoNotCross {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// spatialJoinCell is a cell of index b that is merged with index a.
+type spatialJoinCell struct {
+	cell      Cell
+	indexCell *ShapeIndexCell
+}
+
+// spatialJoinRange is a range of leaf cells near one of the cells of index b,
+// given by its position in the list of cells.
+type spatialJoinRange struct {
+	rangeMin, rangeMax CellID
+	cell               int
+}
+
+// joinWithinDistance reports all pairs of shapes that do not intersect but
+// are within the distance limit.
+func (j *spatialJoiner) joinWithinDistance() bool {
+	limit := j.opts.DistanceLimit
+	if limit <= 0 {
+		return true
+	}
+
+	// Every point within the distance limit of a cell lies in the cell or
+	// one of its neighbors, as long as the limit is at most the minimum
+	// width of the cells. So each cell of index b is replaced by the ranges
+	// of its neighborhood at the level of its ancestor that is wide enough,
+	// and the ranges are merged with the cells of index a. When the limit is
+	// wider than a face, the neighborhood is the whole sphere.
+	maxDist := limit.Expanded(limit.MaxPointError()).Angle().Radians()
+	level := -1
+	if maxDist <= MinWidthMetric.Value(0) {
+		level = MinWidthMetric.MaxLevel(maxDist)
+	}
+	var cells []spatialJoinCell
+	var ranges []spatialJoinRange
+	for bi := NewRangeIterator(j.b); !bi.Done(); bi.Next() {
+		var neighborhood []CellID
+		if level < 0 {
+			for face := 0; face < 6; face++ {
+				neighborhood = append(neighborhood, CellIDFromFace(face))
+			}
+		} else {
+			id := bi.CellID().Parent(minInt(bi.CellID().Level(), level))
+			neighborhood = append(id.AllNeighbors(id.Level()), id)
+		}
+		for _, id := range neighborhood {
+			ranges = append(ranges, spatialJoinRange{id.RangeMin(), id.RangeMax(), len(cells)})
+		}
+		cells = append(cells, spatialJoinCell{CellFromCellID(bi.CellID()), bi.IndexCell()})
+	}
+	sort.Slice(ranges, func(i, k int) bool { return ranges[i].rangeMin < ranges[k].rangeMin })
+
+	// The ranges that overlap the current cell of index a are kept active.
+	// A cell of index b can be reached through several of its ranges, so
+	// the cells compared with the current cell are marked in visited.
+	ai := NewRangeIterator(j.a)
+	var active []spatialJoinRange
+	next := 0
+	visited := make([]int, len(cells))
+	for pos := 1; !ai.Done(); pos++ {
+		for ; next < len(ranges) && ranges[next].rangeMin <= ai.RangeMax(); next++ {
+			active = append(active, ranges[next])
+		}
+		n := 0
+		for _, r := range active {
+			if r.rangeMax >= ai.RangeMin() {
+				active[n] = r
+				n++
+			}
+		}
+		active = active[:n]
+		if len(active) == 0 {
+			if next == len(ranges) {
+				break
+			}
+			ai.seekToRange(ranges[next].rangeMin, ranges[next].rangeMax)
+			continue
+		}
+
+		cellA := CellFromCellID(ai.CellID())
+		for _, r := range active {
+			if visited[r.cell] == pos {
+				continue
+			}
+			visited[r.cell] = pos
+			if !j.reportNearbyShapes(cellA, ai.IndexCell(), cells[r.cell]) {
+				return false
+			}
+		}
+		ai.Next()
+	}
+	return true
+}
+
+// reportNearbyShapes reports the pairs of shapes from the given cells of
+// index a and index b that have edges within the distance limit, and returns
+// false if the join should stop. The distance reported is the minimum
+// distance between the two shapes, which may be closer together elsewhere.
+func (j *spatialJoiner) reportNearbyShapes(cellA Cell, indexCellA *ShapeIndexCell, b spatialJoinCell) bool {
+	limit := j.opts.DistanceLimit
+	// The cell distance is expanded slightly to allow for numerical errors,
+	// since it must never exceed the distance between two points in the cells.
+	if cellA.DistanceToCell(b.cell) > limit.Expanded(limit.MaxPointError()) {
+		return true
+	}
+
+	for _, sa := range indexCellA.shapes {
+		shapeA := j.a.Shape(sa.shapeID)
+		for _, sb := range b.indexCell.shapes {
+			if j.reported[[2]int32{sa.shapeID, sb.shapeID}] {
+				continue
+			}
+			shapeB := j.b.Shape(sb.shapeID)
+			// The limit is inclusive, so start with the next larger distance.
+			dist := limit.Successor()
+			found := false
+			for _, ea := range sa.edges {
+				edgeA := shapeA.Edge(ea)
+				for _, eb := range sb.edges {
+					edgeB := shapeB.Edge(eb)
+					var ok bool
+					if dist, ok = updateEdgePairMinDistance(edgeA.V0, edgeA.V1, edgeB.V0, edgeB.V1, dist); ok {
+						found = true
+					}
+				}
+			}
+			if !found {
+				continue
+			}
+			if d := j.shapeDistance(sa.shapeID, sb.shapeID); d < dist {
+				dist = d
+			}
+			if !j.report(sa.shapeID, sb.shapeID, dist) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// shapeDistance returns the minimum distance between the edges of the given
+// shapes of index a and index b. The index of the shape with more edges is
+// queried with each edge of the other shape.
+func (j *spatialJoiner) shapeDistance(aID, bID int32) s1.ChordAngle {
+	shapeA, shapeB := j.a.Shape(aID), j.b.Shape(bID)
+	index, edges := singleShapeIndex(j.b, bID, j.indexesB), shapeA
+	if shapeA.NumEdges() > shapeB.NumEdges() {
+		index, edges = singleShapeIndex(j.a, aID, j.indexesA), shapeB
+	}
+	query := NewClosestEdgeQuery(index, NewClosestEdgeQueryOptions().IncludeInteriors(false))
+	dist := s1.InfChordAngle()
+	for e := 0; e < edges.NumEdges(); e++ {
+		if d := query.Distance(NewMinDistanceToEdgeTarget(edges.Edge(e))); d < dist {
+			dist = d
+		}
+	}
+	return dist
+}
+
+// singleShapeIndex returns an index of the given shape of index, which is
+// built the first time it is needed and then kept in indexes.
+func singleShapeIndex(index *ShapeIndex, id int32, indexes map[int32]*ShapeIndex) *ShapeIndex {
+	if single, ok := indexes[id]; ok {
+		return single
+	}
+	single := NewShapeIndex()
+	single.Add(index.Shape(id))
+	indexes[id] = single
+	return single
+}
+
+// joinContains reports all pairs where the shape from index a contains the
+// shape from index b.
+func (j *spatialJoiner) joinContains() bool {
+	// Find the pairs whose boundaries cross. A shape cannot contain another
+	// shape whose boundary crosses its own.
+	crossed := make(map[[2]int32]bool)
+	visitIndexCellPairs(j.a, j.b, func(ca, cb *ShapeIndexCell) bool {
+		for _, sa := range ca.shapes {
+			shapeA := j.a.Shape(sa.shapeID)
+			if shapeA.Dimension() != 2 {
+				continue
+			}
+			for _, sb := range cb.shapes {
+				key := [2]int32{sa.shapeID, sb.shapeID}
+				if crossed[key] {
+					continue
+				}
+				shapeB := j.b.Shape(sb.shapeID)
+				for _, ea := range sa.edges {
+					edgeA := shapeA.Edge(ea)
+					crosser := NewEdgeCrosser(edgeA.V0, edgeA.V1)
+					for _, eb := range sb.edges {
+						edgeB := shapeB.Edge(eb)
+						if crosser.CrossingSign(edgeB.V0, edgeB.V1) == Cross {
+							crossed[key] = true
+						}
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	queryA := NewContainsPointQuery(j.a, VertexModelClosed)
+	queryB := NewContainsPointQuery(j.b, VertexModelOpen)
+	for bID := int32(0); bID < j.b.nextID; bID++ {
+		shapeB := j.b.Shape(bID)
+		if shapeB == nil || shapeB.IsEmpty() {
+			continue
+		}
+		// Any shape that contains B also contains its first vertex.
+		var candidates []int32
+		if shapeB.IsFull() {
+			for aID := int32(0); aID < j.a.nextID; aID++ {
+				if shapeA := j.a.Shape(aID); shapeA != nil && shapeA.IsFull() {
+					candidates = append(candidates, aID)
+				}
+			}
+		} else {
+			candidates = containingShapeIDs(queryA, shapeB.Edge(0).V0)
+		}
+		for _, aID := range candidates {
+			shapeA := j.a.Shape(aID)
+			if shapeA.Dimension() != 2 || crossed[[2]int32{aID, bID}] {
+				continue
+			}
+			if shapeContainsShape(queryA, aID, shapeA, queryB, bID, shapeB) && !j.report(aID, bID, 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// shapeContainsShape reports whether shape A contains shape B, given that
+// their boundaries do not cross. queryA must use the closed vertex model, and
+// queryB the open vertex model.
+func shapeContainsShape(queryA *ContainsPointQuery, aID int32, shapeA Shape,
+	queryB *ContainsPointQuery, bID int32, shapeB Shape) bool {
+	if shapeB.IsFull() {
+		return shapeA.IsFull()
+	}
+	// Every vertex of B must be contained by A. Since the boundaries do not
+	// cross, an edge of B can only leave A between two of its vertices by
+	// following the boundary of A, which is detected by testing the midpoint
+	// of the edge. Edges that coincide with an edge of A are tested using
+	// their orientation instead, since the interior of a polygon is on the
+	// left of its edges.
+	for e := 0; e < shapeB.NumEdges(); e++ {
+		edge := shapeB.Edge(e)
+		if !shapeIDContainsPoint(queryA, aID, edge.V0) {
+			return false
+		}
+		mid := Point{edge.V0.Add(edge.V1.Vector).Normalize()}
+		switch sharedEdgeDirection(queryA, aID, shapeA, edge, mid) {
+		case 1:
+		case -1:
+			if shapeB.Dimension() == 2 {
+				return false
+			}
+		default:
+			if !shapeIDContainsPoint(queryA, aID, mid) {
+				return false
+			}
+		}
+	}
+	// Finally, the interior of B must not contain any part of the boundary
+	// of A, such as a hole.
+	if shapeB.Dimension() == 2 {
+		for e := 0; e < shapeA.NumEdges(); e++ {
+			if shapeIDContainsPoint(queryB, bID, shapeA.Edge(e).V0) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sharedEdgeDirection returns 1 if the given edge is also an edge of shape A,
+// -1 if its reverse is an edge of shape A, and 0 otherwise. The point mid is
+// used to locate the index cell containing the edge.
+func sharedEdgeDirection(q *ContainsPointQuery, aID int32, shapeA Shape, edge Edge, mid Point) int {
+	if !q.iter.LocatePoint(mid) {
+		return 0
+	}
+	clipped := q.iter.IndexCell().findByShapeID(aID)
+	if clipped == nil {
+		return 0
+	}
+	for _, e := range clipped.edges {
+		switch edgeA := shapeA.Edge(e); {
+		case edgeA.V0 == edge.V0 && edgeA.V1 == edge.V1:
+			return 1
+		case edgeA.V0 == edge.V1 && edgeA.V1 == edge.V0:
+			return -1
+		}
+	}
+	return 0
+}
+
+// visitContainedShapes calls the visitor for each pair (A, B) where shape A
+// from index a contains a reference point of shape B from index b (see
+// shapeReferencePoints), or where one of the shapes is full and the other is
+// not empty. Shapes are treated as closed. It returns false if the visitor
+// returned false.
+func visitContainedShapes(a, b *ShapeIndex, visitor func(aID, bID int32) bool) bool {
+	query := NewContainsPointQuery(a, VertexModelClosed)
+	for bID := int32(0); bID < b.nextID; bID++ {
+		shapeB := b.Shape(bID)
+		if shapeB == nil || shapeB.IsEmpty() {
+			continue
+		}
+		var ids []int32
+		if shapeB.IsFull() {
+			// A full shape intersects every shape that is not empty.
+			for aID := int32(0); aID < a.nextID; aID++ {
+				if shapeA := a.Shape(aID); shapeA != nil && !shapeA.IsEmpty() {
+					ids = append(ids, aID)
+				}
+			}
+		} else {
+			seen := make(map[int32]bool)
+			for _, p := range shapeReferencePoints(shapeB) {
+				for _, aID := range containingShapeIDs(query, p) {
+					if !seen[aID] {
+						seen[aID] = true
+						ids = append(ids, aID)
+					}
+				}
+			}
+		}
+		for _, aID := range ids {
+			if !visitor(aID, bID) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// shapeReferencePoints returns a point of each connected component of the
+// given shape that has edges: every point of a shape of dimension 0, and the
+// first vertex of every chain otherwise. Two shapes whose edges do not touch
+// intersect only if one of them contains a reference point of the other.
+func shapeReferencePoints(s Shape) []Point {
+	var points []Point
+	if s.Dimension() == 0 {
+		for e := 0; e < s.NumEdges(); e++ {
+			points = append(points, s.Edge(e).V0)
+		}
+		return points
+	}
+	for i := 0; i < s.NumChains(); i++ {
+		if chain := s.Chain(i); chain.Length > 0 {
+			points = append(points, s.ChainEdge(i, 0).V0)
+		}
+	}
+	return points
+}
+
+// containingShapeIDs returns the IDs of all shapes in the query's index that
+// contain the given point, in increasing order.
+func containingShapeIDs(q *ContainsPointQuery, p Point) []int32 {
+	if !q.iter.LocatePoint(p) {
+		return nil
+	}
+	var ids []int32
+	for _, clipped := range q.iter.IndexCell().shapes {
+		if q.shapeContains(clipped, q.iter.Center(), p) {
+			ids = append(ids, clipped.shapeID)
+		}
+	}
+	return ids
+}
+
+// shapeIDContainsPoint reports whether the shape with the given ID in the
+// query's index contains the point.
+func shapeIDContainsPoint(q *ContainsPointQuery, id int32, p Point) bool {
+	if !q.iter.LocatePoint(p) {
+		return false
+	}
+	clipped := q.iter.IndexCell().findByShapeID(id)
+	if clipped == nil {
+		return false
+	}
+	return q.shapeContains(clipped, q.iter.Center(), p)
+}
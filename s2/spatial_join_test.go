@@ -0,0 +1,277 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/rubenpoppe/geo/s1"
+)
+
+// spatialJoinPairs runs the join and returns the reported pairs and their
+// distances.
+func spatialJoinPairs(t *testing.T, a, b *ShapeIndex, opts SpatialJoinOptions) map[[2]int32]s1.ChordAngle {
+	t.Helper()
+	got := make(map[[2]int32]s1.ChordAngle)
+	SpatialJoin(a, b, opts, func(r SpatialJoinResult) bool {
+		key := [2]int32{r.ShapeIDA, r.ShapeIDB}
+		if _, ok := got[key]; ok {
+			t.Errorf("pair %v reported twice", key)
+		}
+		got[key] = r.Distance
+		return true
+	})
+	return got
+}
+
+// checkSpatialJoinDistances checks that the reported pairs are the wanted
+// pairs, and that each distance is the wanted minimum distance, up to
+// numerical errors. Pairs at a minimum distance of zero must be reported at
+// zero.
+func checkSpatialJoinDistances(t *testing.T, label string, got, want map[[2]int32]s1.ChordAngle, limit s1.ChordAngle) {
+	t.Helper()
+	for key, d := range got {
+		w, ok := want[key]
+		switch {
+		case !ok:
+			t.Errorf("%s: SpatialJoin reported %v, which is not within %v", label, key, limit)
+		case !float64Near(float64(d), float64(w), 1e-15) || (w == 0) != (d == 0):
+			t.Errorf("%s: SpatialJoin reported %v at distance %v, want %v", label, key, d, w)
+		}
+	}
+	for key := range want {
+		if _, ok := got[key]; !ok {
+			t.Errorf("%s: SpatialJoin did not report %v", label, key)
+		}
+	}
+}
+
+// shapeDistanceBruteForce returns the minimum distance between two shapes,
+// including polygon interiors.
+func shapeDistanceBruteForce(a, b Shape) s1.ChordAngle {
+	for _, p := range shapeReferencePoints(b) {
+		if containsBruteForce(a, p) {
+			return 0
+		}
+	}
+	for _, p := range shapeReferencePoints(a) {
+		if containsBruteForce(b, p) {
+			return 0
+		}
+	}
+	dist := s1.InfChordAngle()
+	for i := 0; i < a.NumEdges(); i++ {
+		ea := a.Edge(i)
+		for k := 0; k < b.NumEdges(); k++ {
+			eb := b.Edge(k)
+			if CrossingSign(ea.V0, ea.V1, eb.V0, eb.V1) != DoNotCross {
+				return 0
+			}
+			dist, _ = updateEdgePairMinDistance(ea.V0, ea.V1, eb.V0, eb.V1, dist)
+		}
+	}
+	return dist
+}
+
+func TestSpatialJoin(t *testing.T) {
+	a := makeShapeIndex("# 0:20, 10:20 # " +
+		"0:0, 0:10, 10:10, 10:0 | " +
+		"20:0, 20:10, 30:10, 30:0 | " +
+		"40:0, 40:10, 50:10, 50:0; 42:2, 48:2, 48:8, 42:8")
+	b := makeShapeIndex("5:5 | 25:25 # 5:-5, 5:15 | 41:1, 41:9 # " +
+		"1:1, 1:2, 2:2, 2:1 | " +
+		"0:10, 0:15, 10:15, 10:10 | " +
+		"0:0, 0:10, 10:10, 10:0 | " +
+		"42:2, 42:8, 48:8, 48:2 | " +
+		"41:1, 41:9, 49:9, 49:1")
+	// The shapes of a are: 0 a polyline, 1 and 2 squares, and 3 a square with
+	// a hole. The shapes of b are: 0 two points, 1 a polyline crossing
+	// square 1, 2 a polyline inside square 3, 3 a square inside square 1, 4
+	// a square adjacent to square 1, 5 a copy of square 1, 6 the hole of
+	// square 3, and 7 a square containing the hole.
+	tests := []struct {
+		name string
+		opts SpatialJoinOptions
+		want map[[2]int32]s1.ChordAngle
+	}{
+		{
+			name: "intersects",
+			opts: SpatialJoinOptions{Mode: SpatialJoinIntersects},
+			want: map[[2]int32]s1.ChordAngle{
+				{1, 0}: 0, {1, 1}: 0, {1, 3}: 0, {1, 4}: 0, {1, 5}: 0,
+				{3, 2}: 0, {3, 6}: 0, {3, 7}: 0,
+			},
+		},
+		{
+			name: "contains",
+			opts: SpatialJoinOptions{Mode: SpatialJoinContains},
+			want: map[[2]int32]s1.ChordAngle{
+				{1, 3}: 0, {1, 5}: 0, {3, 2}: 0,
+			},
+		},
+	}
+	for _, test := range tests {
+		if got := spatialJoinPairs(t, a, b, test.opts); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: SpatialJoin = %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	limit := s1.ChordAngleFromAngle(6 * s1.Degree)
+	got := spatialJoinPairs(t, a, b, SpatialJoinOptions{Mode: SpatialJoinWithinDistance, DistanceLimit: limit})
+	checkSpatialJoinDistances(t, "within distance", got, map[[2]int32]s1.ChordAngle{
+		{1, 0}: 0, {1, 1}: 0, {1, 3}: 0, {1, 4}: 0, {1, 5}: 0,
+		{3, 2}: 0, {3, 6}: 0, {3, 7}: 0,
+		{0, 1}: shapeDistanceBruteForce(a.Shape(0), b.Shape(1)),
+		{0, 4}: shapeDistanceBruteForce(a.Shape(0), b.Shape(4)),
+	}, limit)
+}
+
+func TestSpatialJoinLaterComponents(t *testing.T) {
+	// Only a later point or chain of shape B lies inside shape A, and the
+	// edges of B are far from those of A.
+	a := makeShapeIndex("# # 0:0, 0:10, 10:10, 10:0")
+	tests := []struct {
+		name string
+		b    *ShapeIndex
+	}{
+		{"points", makeShapeIndex("50:50 | 5:5 # #")},
+		{"chains", makeShapeIndex("# # 50:50, 50:51, 51:51; 4:4, 4:5, 5:5")},
+	}
+	for _, test := range tests {
+		want := map[[2]int32]s1.ChordAngle{{0, 0}: 0}
+		if got := spatialJoinPairs(t, a, test.b, SpatialJoinOptions{Mode: SpatialJoinIntersects}); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: SpatialJoin(intersects) = %v, want %v", test.name, got, want)
+		}
+		if got := spatialJoinPairs(t, test.b, a, SpatialJoinOptions{Mode: SpatialJoinIntersects}); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: SpatialJoin(intersects, reversed) = %v, want %v", test.name, got, want)
+		}
+		opts := SpatialJoinOptions{Mode: SpatialJoinWithinDistance, DistanceLimit: s1.ChordAngleFromAngle(s1.Degree)}
+		if got := spatialJoinPairs(t, a, test.b, opts); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: SpatialJoin(within distance) = %v, want %v", test.name, got, want)
+		}
+	}
+}
+
+func TestSpatialJoinDistanceIsMinimum(t *testing.T) {
+	// Polylines with many vertices that are close together along their whole
+	// length and closest at a single vertex, so the pair is found in many
+	// cells but only the cells near that vertex have the minimum distance.
+	line := func(lat float64, closest int) *Polyline {
+		var lls []LatLng
+		for i := 0; i <= 200; i++ {
+			if i == closest {
+				lls = append(lls, LatLngFromDegrees(lat/10, float64(i)*0.2))
+			} else {
+				lls = append(lls, LatLngFromDegrees(lat, float64(i)*0.2))
+			}
+		}
+		return PolylineFromLatLngs(lls)
+	}
+	a := NewShapeIndex()
+	a.Add(line(0, -1))
+	limit := s1.ChordAngleFromAngle(0.02 * s1.Degree)
+	for _, closest := range []int{1, 100, 199} {
+		b := NewShapeIndex()
+		b.Add(line(0.01, closest))
+		got := spatialJoinPairs(t, a, b, SpatialJoinOptions{Mode: SpatialJoinWithinDistance, DistanceLimit: limit})
+		checkSpatialJoinDistances(t, fmt.Sprintf("closest at vertex %d", closest), got, map[[2]int32]s1.ChordAngle{
+			{0, 0}: shapeDistanceBruteForce(a.Shape(0), b.Shape(0)),
+		}, limit)
+	}
+}
+
+func TestSpatialJoinFullAndEmpty(t *testing.T) {
+	a := makeShapeIndex("# # full")
+	b := makeShapeIndex("1:1 # 2:2, 3:3 # full")
+	if got, want := spatialJoinPairs(t, a, b, SpatialJoinOptions{Mode: SpatialJoinIntersects}),
+		(map[[2]int32]s1.ChordAngle{{0, 0}: 0, {0, 1}: 0, {0, 2}: 0}); !reflect.DeepEqual(got, want) {
+		t.Errorf("SpatialJoin(intersects) = %v, want %v", got, want)
+	}
+	if got, want := spatialJoinPairs(t, a, b, SpatialJoinOptions{Mode: SpatialJoinContains}),
+		(map[[2]int32]s1.ChordAngle{{0, 0}: 0, {0, 1}: 0, {0, 2}: 0}); !reflect.DeepEqual(got, want) {
+		t.Errorf("SpatialJoin(contains) = %v, want %v", got, want)
+	}
+	if got := spatialJoinPairs(t, NewShapeIndex(), b, SpatialJoinOptions{Mode: SpatialJoinIntersects}); len(got) != 0 {
+		t.Errorf("SpatialJoin(empty index) = %v, want no pairs", got)
+	}
+}
+
+func TestSpatialJoinStop(t *testing.T) {
+	a := makeShapeIndex("# # 0:0, 0:10, 10:10, 10:0")
+	b := makeShapeIndex("1:1 | 2:2 # 3:3, 4:4 # 5:5, 5:6, 6:6")
+	for _, mode := range []SpatialJoinMode{SpatialJoinIntersects, SpatialJoinWithinDistance, SpatialJoinContains} {
+		calls := 0
+		opts := SpatialJoinOptions{Mode: mode, DistanceLimit: s1.StraightChordAngle}
+		if SpatialJoin(a, b, opts, func(SpatialJoinResult) bool {
+			calls++
+			return false
+		}) {
+			t.Errorf("SpatialJoin(mode %d) = true, want false", mode)
+		}
+		if calls != 1 {
+			t.Errorf("SpatialJoin(mode %d) called the visitor %d times, want 1", mode, calls)
+		}
+	}
+}
+
+func TestSpatialJoinRandom(t *testing.T) {
+	// Random loops and polylines of various sizes, compared against a brute
+	// force computation of the distance between every pair of shapes.
+	makeIndex := func() *ShapeIndex {
+		index := NewShapeIndex()
+		center := randomPoint()
+		for i := 0; i < 20; i++ {
+			c := samplePointFromCap(CapFromCenterAngle(center, 0.3))
+			radius := s1.Angle(randomUniformFloat64(1e-4, 0.05))
+			loop := RegularLoop(c, radius, 3+randomUniformInt(30))
+			if oneIn(3) {
+				line := Polyline(loop.Vertices())
+				index.Add(&line)
+			} else {
+				index.Add(loop)
+			}
+		}
+		return index
+	}
+	for iter := 0; iter < 10; iter++ {
+		a, b := makeIndex(), makeIndex()
+		limit := s1.ChordAngleFromAngle(s1.Angle(randomUniformFloat64(0, 0.1)))
+
+		want := make(map[[2]int32]s1.ChordAngle)
+		for i := int32(0); i < int32(a.Len()); i++ {
+			for k := int32(0); k < int32(b.Len()); k++ {
+				if d := shapeDistanceBruteForce(a.Shape(i), b.Shape(k)); d <= limit {
+					want[[2]int32{i, k}] = d
+				}
+			}
+		}
+		got := spatialJoinPairs(t, a, b, SpatialJoinOptions{Mode: SpatialJoinWithinDistance, DistanceLimit: limit})
+		checkSpatialJoinDistances(t, "random", got, want, limit)
+
+		intersecting := spatialJoinPairs(t, a, b, SpatialJoinOptions{Mode: SpatialJoinIntersects})
+		for key := range intersecting {
+			if d := want[key]; d != 0 {
+				t.Errorf("SpatialJoin(intersects) reported %v at distance %v", key, d)
+			}
+		}
+		for key, d := range want {
+			if _, ok := intersecting[key]; d == 0 && !ok {
+				t.Errorf("SpatialJoin(intersects) did not report %v", key)
+			}
+		}
+	}
+}
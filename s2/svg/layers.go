@@ -0,0 +1,284 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/rubenpoppe/geo/r2"
+	"github.com/rubenpoppe/geo/s1"
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// AddPoints adds a layer with the given points.
+func (c *Canvas) AddPoints(points []s2.Point, style Style) {
+	c.layers = append(c.layers, layer{style: style, items: []item{{points: points}}})
+}
+
+// AddPolyline adds a layer with the given polyline.
+func (c *Canvas) AddPolyline(p *s2.Polyline, style Style) {
+	c.layers = append(c.layers, layer{style: style, items: []item{{lines: []s2.Polyline{*p}}}})
+}
+
+// AddPolygon adds a layer with the given polygon.
+func (c *Canvas) AddPolygon(p *s2.Polygon, style Style) {
+	c.layers = append(c.layers, layer{style: style, items: []item{fixedArea(p)}})
+}
+
+// AddShape adds a layer with the given shape, which is drawn according to its
+// dimension as points, polylines or a polygon.
+func (c *Canvas) AddShape(shape s2.Shape, style Style) {
+	c.layers = append(c.layers, layer{style: style, items: []item{shapeItem(shape)}})
+}
+
+// AddCellUnion adds a layer with the cells of the cell union, which are
+// labelled with their tokens.
+func (c *Canvas) AddCellUnion(cu s2.CellUnion, style Style) {
+	l := layer{style: style}
+	for _, id := range cu {
+		l.items = append(l.items, cellItem(id))
+	}
+	c.layers = append(c.layers, l)
+}
+
+// AddShapeIndex adds a layer with the shapes of the index, which are
+// labelled with their IDs.
+func (c *Canvas) AddShapeIndex(index *s2.ShapeIndex, style Style) {
+	l := layer{style: style}
+	for id, found := int32(0), 0; found < index.Len(); id++ {
+		shape := index.Shape(id)
+		if shape == nil {
+			continue
+		}
+		found++
+		it := shapeItem(shape)
+		it.label = strconv.Itoa(int(id))
+		l.items = append(l.items, it)
+	}
+	c.layers = append(c.layers, l)
+}
+
+// AddIndexCells adds a layer with the cells of the index, which are labelled
+// with their tokens. Together with a layer from AddShapeIndex, it shows how
+// the shapes are distributed over the cells.
+func (c *Canvas) AddIndexCells(index *s2.ShapeIndex, style Style) {
+	l := layer{style: style}
+	for it := index.Iterator(); !it.Done(); it.Next() {
+		l.items = append(l.items, cellItem(it.CellID()))
+	}
+	c.layers = append(c.layers, l)
+}
+
+// AddCap adds a layer with the given cap. Its boundary, which is not a
+// geodesic, is approximated by a loop to within the tolerance of the canvas.
+func (c *Canvas) AddCap(cap s2.Cap, style Style) {
+	it := item{area: func(tolerance s1.Angle) *s2.Polygon { return capPolygon(cap, tolerance) }}
+	c.layers = append(c.layers, layer{style: style, items: []item{it}})
+}
+
+// AddRect adds a layer with the given rectangle. Its parallels, which are not
+// geodesics, are approximated to within the tolerance of the canvas.
+func (c *Canvas) AddRect(rect s2.Rect, style Style) {
+	it := item{area: func(tolerance s1.Angle) *s2.Polygon { return rectPolygon(rect, tolerance) }}
+	c.layers = append(c.layers, layer{style: style, items: []item{it}})
+}
+
+// AddEdgeQueryResults adds a layer with the results of an EdgeQuery on the
+// given index. Each result is drawn as the edge it refers to, or as the whole
+// shape for results that represent the interior of a shape, and is labelled
+// with its shape and edge IDs and its distance in degrees. The target of the
+// query can be drawn in a separate layer.
+func (c *Canvas) AddEdgeQueryResults(index *s2.ShapeIndex, results []s2.EdgeQueryResult, style Style) {
+	l := layer{style: style}
+	for _, r := range results {
+		if r.IsEmpty() {
+			continue
+		}
+		shape := index.Shape(r.ShapeID())
+		if shape == nil {
+			continue
+		}
+		distance := r.Distance().Angle().Degrees()
+		var it item
+		if r.IsInterior() {
+			it = shapeItem(shape)
+			it.label = fmt.Sprintf("%d (%s°)", r.ShapeID(), strconv.FormatFloat(distance, 'g', 6, 64))
+		} else {
+			e := shape.Edge(int(r.EdgeID()))
+			if e.V0 == e.V1 {
+				it.points = []s2.Point{e.V0}
+			} else {
+				it.lines = []s2.Polyline{{e.V0, e.V1}}
+			}
+			it.anchor = s2.Point{Vector: e.V0.Add(e.V1.Vector).Normalize()}
+			it.label = fmt.Sprintf("%d:%d (%s°)", r.ShapeID(), r.EdgeID(), strconv.FormatFloat(distance, 'g', 6, 64))
+		}
+		l.items = append(l.items, it)
+	}
+	c.layers = append(c.layers, l)
+}
+
+// fixedArea returns an item for the given polygon, whose boundary consists of
+// geodesics and does not depend on the tolerance.
+func fixedArea(p *s2.Polygon) item {
+	var anchor s2.Point
+	if p.NumLoops() > 0 && !p.Loop(0).IsEmpty() && !p.Loop(0).IsFull() {
+		anchor = p.Loop(0).Vertex(0)
+	}
+	return item{area: func(s1.Angle) *s2.Polygon { return p }, anchor: anchor}
+}
+
+// cellItem returns an item for the cell with the given ID, labelled with its
+// token.
+func cellItem(id s2.CellID) item {
+	cell := s2.CellFromCellID(id)
+	it := fixedArea(s2.PolygonFromCell(cell))
+	it.label, it.anchor = id.ToToken(), cell.Center()
+	return it
+}
+
+// shapeItem returns an item for the shape, according to its dimension. The
+// label is placed at the first vertex.
+func shapeItem(shape s2.Shape) item {
+	var it item
+	if shape.NumEdges() > 0 {
+		it.anchor = shape.Edge(0).V0
+	}
+	switch shape.Dimension() {
+	case 0:
+		for i := 0; i < shape.NumEdges(); i++ {
+			it.points = append(it.points, shape.Edge(i).V0)
+		}
+	case 1:
+		for c := 0; c < shape.NumChains(); c++ {
+			chain := shape.Chain(c)
+			line := make(s2.Polyline, 0, chain.Length+1)
+			for j := 0; j < chain.Length; j++ {
+				e := shape.ChainEdge(c, j)
+				line = append(line, e.V0)
+				if j == chain.Length-1 {
+					line = append(line, e.V1)
+				}
+			}
+			it.lines = append(it.lines, line)
+		}
+	default:
+		polygon := shapePolygon(shape)
+		it.area = func(s1.Angle) *s2.Polygon { return polygon }
+	}
+	return it
+}
+
+// shapePolygon returns the polygon of a shape of dimension 2.
+func shapePolygon(shape s2.Shape) *s2.Polygon {
+	switch s := shape.(type) {
+	case *s2.Polygon:
+		return s
+	case *s2.Loop:
+		return s2.PolygonFromLoops([]*s2.Loop{s})
+	}
+	if shape.IsFull() {
+		return s2.FullPolygon()
+	}
+	// The interior of the shape is on the left of its chains, as for oriented
+	// loops.
+	var loops []*s2.Loop
+	for c := 0; c < shape.NumChains(); c++ {
+		chain := shape.Chain(c)
+		vertices := make([]s2.Point, chain.Length)
+		for j := range vertices {
+			vertices[j] = shape.ChainEdge(c, j).V0
+		}
+		loops = append(loops, s2.LoopFromPoints(vertices))
+	}
+	return s2.PolygonFromOrientedLoops(loops)
+}
+
+// capPolygon returns a polygon whose edges are within the tolerance of the
+// boundary of the cap.
+func capPolygon(cap s2.Cap, tolerance s1.Angle) *s2.Polygon {
+	switch {
+	case cap.IsEmpty():
+		return nil
+	case cap.IsFull():
+		return s2.FullPolygon()
+	}
+	// A geodesic between two points on a circle of radius r that are an angle
+	// theta apart around the center deviates from the circle by about
+	// sin(r) * theta^2 / 8.
+	radius := cap.Radius().Radians()
+	theta := math.Sqrt(8 * tolerance.Radians() / math.Max(math.Sin(radius), 1e-15))
+	n := int(math.Ceil(2 * math.Pi / theta))
+	n = int(math.Max(8, math.Min(float64(n), 100000)))
+	return s2.PolygonFromLoops([]*s2.Loop{s2.RegularLoop(cap.Center(), cap.Radius(), n)})
+}
+
+// rectPolygon returns a polygon whose edges are within the tolerance of the
+// parallels and meridians that bound the rectangle. The parallels are
+// straight lines in the plate carree projection, and are converted to
+// geodesics with an EdgeTessellator.
+func rectPolygon(rect s2.Rect, tolerance s1.Angle) *s2.Polygon {
+	switch {
+	case rect.IsEmpty():
+		return nil
+	case rect.IsFull():
+		return s2.FullPolygon()
+	}
+	tess := s2.NewEdgeTessellator(s2.NewPlateCarreeProjection(180), tolerance)
+	lat := rect.Lat
+	latLo, latHi := s1.Angle(lat.Lo).Degrees(), s1.Angle(lat.Hi).Degrees()
+
+	// appendParallel appends the vertices along the parallel at lat from
+	// longitude lng0 to lng1 in degrees, in steps of at most 90 degrees so
+	// that the tessellator does not wrap them the other way around.
+	appendParallel := func(vertices []s2.Point, lat, lng0, lng1 float64) []s2.Point {
+		n := int(math.Ceil(math.Abs(lng1-lng0) / 90))
+		for i := 0; i < n; i++ {
+			a := r2.Point{X: lng0 + (lng1-lng0)*float64(i)/float64(n), Y: lat}
+			b := r2.Point{X: lng0 + (lng1-lng0)*float64(i+1)/float64(n), Y: lat}
+			vertices = tess.AppendUnprojected(a, b, vertices)
+		}
+		return vertices
+	}
+
+	if rect.Lng.IsFull() {
+		// The rectangle is the band between two parallels. The loop along the
+		// northern one goes west, and the one along the southern one goes
+		// east, so that the band is on the left of both.
+		var loops []*s2.Loop
+		if lat.Hi < math.Pi/2 {
+			v := appendParallel(nil, latHi, 180, -180)
+			loops = append(loops, s2.LoopFromPoints(v[:len(v)-1]))
+		}
+		if lat.Lo > -math.Pi/2 {
+			v := appendParallel(nil, latLo, -180, 180)
+			loops = append(loops, s2.LoopFromPoints(v[:len(v)-1]))
+		}
+		return s2.PolygonFromOrientedLoops(loops)
+	}
+
+	lngLo, lngHi := s1.Angle(rect.Lng.Lo).Degrees(), s1.Angle(rect.Lng.Hi).Degrees()
+	if rect.Lng.IsInverted() {
+		lngHi += 360
+	}
+	// Counterclockwise from the south west corner.
+	v := appendParallel(nil, latLo, lngLo, lngHi)
+	v = tess.AppendUnprojected(r2.Point{X: lngHi, Y: latLo}, r2.Point{X: lngHi, Y: latHi}, v)
+	v = appendParallel(v, latHi, lngHi, lngLo)
+	v = tess.AppendUnprojected(r2.Point{X: lngLo, Y: latHi}, r2.Point{X: lngLo, Y: latLo}, v)
+	return s2.PolygonFromLoops([]*s2.Loop{s2.LoopFromPoints(v[:len(v)-1])})
+}
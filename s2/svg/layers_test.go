@@ -0,0 +1,181 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svg
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/rubenpoppe/geo/r1"
+	"github.com/rubenpoppe/geo/s1"
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// opaqueShape hides the type of a shape, so that it is handled like any
+// other shape.
+type opaqueShape struct{ s2.Shape }
+
+func TestCapPolygon(t *testing.T) {
+	const tolerance = 1e-4
+	center := s2.PointFromLatLng(s2.LatLngFromDegrees(30, 40))
+	for _, radius := range []s1.Angle{1e-3, 0.1, 1, math.Pi / 2, 2.5} {
+		cap := s2.CapFromCenterAngle(center, radius)
+		polygon := capPolygon(cap, tolerance)
+		loop := polygon.Loop(0)
+		for i := 0; i < loop.NumVertices(); i++ {
+			// The vertices are on the boundary, and the middle of each edge
+			// is within the tolerance of it.
+			a, b := loop.Vertex(i), loop.Vertex(i+1)
+			mid := s2.Point{Vector: a.Add(b.Vector).Normalize()}
+			for _, p := range []s2.Point{a, mid} {
+				if d := math.Abs(float64(center.Distance(p) - radius)); d > tolerance {
+					t.Errorf("cap with radius %v: point %v is %v from the boundary, want <= %v", radius, p, d, tolerance)
+				}
+			}
+		}
+		if !polygon.ContainsPoint(center) {
+			t.Errorf("cap with radius %v: polygon does not contain the center", radius)
+		}
+	}
+	if got := capPolygon(s2.EmptyCap(), tolerance); got != nil {
+		t.Errorf("capPolygon(EmptyCap()) = %v, want nil", got)
+	}
+	if got := capPolygon(s2.FullCap(), tolerance); !got.IsFull() {
+		t.Errorf("capPolygon(FullCap()) = %v, want the full polygon", got)
+	}
+}
+
+func TestRectPolygon(t *testing.T) {
+	const tolerance = 1e-6
+	rects := []s2.Rect{
+		s2.RectFromLatLng(s2.LatLngFromDegrees(10, 20)).AddPoint(s2.LatLngFromDegrees(40, 60)),
+		// Across the antimeridian.
+		{Lat: r1.Interval{Lo: -0.5, Hi: 0.5}, Lng: s1.IntervalFromEndpoints(3, -3)},
+		// Wider than half the world, up to the north pole.
+		{Lat: r1.Interval{Lo: 0.2, Hi: math.Pi / 2}, Lng: s1.IntervalFromEndpoints(-2, 2)},
+		// Bands around the world.
+		{Lat: r1.Interval{Lo: -0.5, Hi: 0.8}, Lng: s1.FullInterval()},
+		{Lat: r1.Interval{Lo: 0.3, Hi: math.Pi / 2}, Lng: s1.FullInterval()},
+		{Lat: r1.Interval{Lo: -math.Pi / 2, Hi: -0.1}, Lng: s1.FullInterval()},
+	}
+	r := rand.New(rand.NewSource(1))
+	for _, rect := range rects {
+		polygon := rectPolygon(rect, tolerance)
+		// Points that are not close to the boundary are inside the polygon
+		// if and only if they are inside the rectangle.
+		for i := 0; i < 1000; i++ {
+			ll := s2.LatLngFromDegrees(r.Float64()*180-90, r.Float64()*360-180)
+			if nearBoundary(rect, ll, 1e-3) {
+				continue
+			}
+			if got, want := polygon.ContainsPoint(s2.PointFromLatLng(ll)), rect.ContainsLatLng(ll); got != want {
+				t.Errorf("rectPolygon(%v).ContainsPoint(%v) = %v, want %v", rect, ll, got, want)
+			}
+		}
+	}
+	if got := rectPolygon(s2.EmptyRect(), tolerance); got != nil {
+		t.Errorf("rectPolygon(EmptyRect()) = %v, want nil", got)
+	}
+	if got := rectPolygon(s2.FullRect(), tolerance); !got.IsFull() {
+		t.Errorf("rectPolygon(FullRect()) = %v, want the full polygon", got)
+	}
+}
+
+// nearBoundary reports whether ll is within the given distance in latitude
+// or longitude of the parallels or meridians that bound the rectangle.
+func nearBoundary(rect s2.Rect, ll s2.LatLng, d float64) bool {
+	lat, lng := ll.Lat.Radians(), ll.Lng.Radians()
+	if math.Abs(lat-rect.Lat.Lo) < d || math.Abs(lat-rect.Lat.Hi) < d {
+		return true
+	}
+	if rect.Lng.IsFull() {
+		return false
+	}
+	return math.Abs(math.Remainder(lng-rect.Lng.Lo, 2*math.Pi)) < d ||
+		math.Abs(math.Remainder(lng-rect.Lng.Hi, 2*math.Pi)) < d
+}
+
+func TestShapePolygon(t *testing.T) {
+	// A polygon with a hole, seen only as a Shape, becomes the same polygon.
+	shell := s2.RegularLoop(s2.PointFromLatLng(s2.LatLngFromDegrees(10, 10)), 0.2, 6)
+	hole := s2.RegularLoop(s2.PointFromLatLng(s2.LatLngFromDegrees(10, 10)), 0.1, 6)
+	want := s2.PolygonFromLoops([]*s2.Loop{shell, hole})
+	got := shapePolygon(opaqueShape{want})
+	if got.NumLoops() != 2 || !got.Loop(0).BoundaryEqual(shell) || !got.Loop(1).BoundaryEqual(hole) {
+		t.Errorf("shapePolygon(%v) = %v", want, got)
+	}
+	if got := shapePolygon(opaqueShape{s2.FullPolygon()}); !got.IsFull() {
+		t.Errorf("shapePolygon(full) = %v, want the full polygon", got)
+	}
+}
+
+func TestAddShapeIndex(t *testing.T) {
+	index := s2.NewShapeIndex()
+	index.Add(&s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(1, 1))})
+	index.Add(&s2.Polyline{
+		s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(5, 5)),
+	})
+	removed := &s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(2, 2))}
+	index.Add(removed)
+	index.Add(s2.PolygonFromLoops([]*s2.Loop{s2.RegularLoop(s2.PointFromLatLng(s2.LatLngFromDegrees(3, 3)), 0.02, 4)}))
+	index.Remove(removed)
+
+	c := new(Canvas)
+	c.AddShapeIndex(index, Style{Fill: "green", Labels: true})
+	c.AddIndexCells(index, Style{})
+	img := render(t, c)
+	shapes, cells := img.Groups[0], img.Groups[1]
+	if len(shapes.Circles) != 1 || len(shapes.Paths) != 3 {
+		t.Errorf("shapes have %d points and %d paths, want 1 point, a line and a filled polygon", len(shapes.Circles), len(shapes.Paths))
+	}
+	if want := []string{"0", "1", "3"}; len(shapes.Texts) != len(want) ||
+		shapes.Texts[0] != want[0] || shapes.Texts[1] != want[1] || shapes.Texts[2] != want[2] {
+		t.Errorf("shape labels = %q, want %q", shapes.Texts, want)
+	}
+
+	numCells := 0
+	for it := index.Iterator(); !it.Done(); it.Next() {
+		numCells++
+	}
+	if len(cells.Paths) != numCells {
+		t.Errorf("index cells have %d paths, want %d", len(cells.Paths), numCells)
+	}
+}
+
+func TestAddEdgeQueryResults(t *testing.T) {
+	index := s2.NewShapeIndex()
+	index.Add(&s2.Polyline{
+		s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(0, 10)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(10, 10)),
+	})
+	index.Add(s2.PolygonFromLoops([]*s2.Loop{s2.RegularLoop(s2.PointFromLatLng(s2.LatLngFromDegrees(5, 20)), 0.05, 4)}))
+
+	target := s2.PointFromLatLng(s2.LatLngFromDegrees(5, 20))
+	opts := s2.NewClosestEdgeQueryOptions().MaxResults(2).IncludeInteriors(true)
+	results := s2.NewClosestEdgeQuery(index, opts).FindEdges(s2.NewMinDistanceToPointTarget(target))
+
+	c := new(Canvas)
+	c.AddPoints([]s2.Point{target}, Style{})
+	c.AddEdgeQueryResults(index, results, Style{Fill: "yellow", Labels: true})
+	img := render(t, c)
+	got := img.Groups[1].Texts
+	want := []string{"1 (0°)", "1:3 (2.02656°)"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("labels of results = %q, want %q", got, want)
+	}
+}
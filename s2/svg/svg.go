@@ -0,0 +1,401 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package svg renders s2 geometry as SVG images for debugging, such as the cells
+of a covering, the shapes and cells of a ShapeIndex, or the results of an
+EdgeQuery.
+
+A Canvas collects geometry in layers, each drawn with its own Style, and
+writes them in order, so later layers are drawn on top. Geometry is projected
+with an s2.Projection, and geodesic edges are tessellated with an
+s2.EdgeTessellator so that they are drawn as the curves they are in the
+projection, to within about half a pixel. Geometry that crosses the seam of
+the projection, such as the antimeridian, is split along it, and areas that
+contain a pole are closed along the edge of the map.
+
+	var c svg.Canvas
+	c.AddCellUnion(covering, svg.Style{Fill: "orange", Labels: true})
+	c.AddShapeIndex(index, svg.Style{Stroke: "blue"})
+	c.WriteTo(os.Stdout)
+*/
+package svg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/rubenpoppe/geo/r1"
+	"github.com/rubenpoppe/geo/r2"
+	"github.com/rubenpoppe/geo/s1"
+	"github.com/rubenpoppe/geo/s2"
+)
+
+const (
+	// DefaultWidth is the width of the image in pixels if Canvas.Width is
+	// zero.
+	DefaultWidth = 800
+
+	// margin is the fraction of the size of the geometry that is added on
+	// each side when the view is fitted to it.
+	margin = 0.05
+
+	// fitTolerance is the tolerance used to project the geometry when fitting
+	// the view to it, before the size of a pixel is known.
+	fitTolerance = 1e-3 * s1.Radian
+
+	// fontSize is the size of labels in pixels.
+	fontSize = 10
+)
+
+// Style determines how the geometry of a layer is drawn. The zero value draws
+// black outlines and points without fill.
+type Style struct {
+	// Stroke is the color of lines, outlines, points and labels, as an SVG
+	// color such as "red" or "#ff0000". If empty, it is "black". Use "none"
+	// to draw areas without outlines.
+	Stroke string
+
+	// StrokeWidth is the width of lines and outlines in pixels. If zero, it
+	// is 1.
+	StrokeWidth float64
+
+	// Fill is the color of the interior of areas. If empty, areas are not
+	// filled.
+	Fill string
+
+	// FillOpacity is the opacity of the fill, from 0 to 1. If zero, it is
+	// 0.3, which keeps the layers underneath visible.
+	FillOpacity float64
+
+	// PointRadius is the radius of points in pixels. If zero, it is 3.
+	PointRadius float64
+
+	// Labels adds text labels to the geometry: tokens to cells, IDs to
+	// shapes, and shape and edge IDs to the results of edge queries.
+	Labels bool
+}
+
+func (s Style) stroke() string {
+	if s.Stroke == "" {
+		return "black"
+	}
+	return s.Stroke
+}
+
+func (s Style) strokeWidth() float64 {
+	if s.StrokeWidth == 0 {
+		return 1
+	}
+	return s.StrokeWidth
+}
+
+func (s Style) fillOpacity() float64 {
+	if s.FillOpacity == 0 {
+		return 0.3
+	}
+	return s.FillOpacity
+}
+
+func (s Style) pointRadius() float64 {
+	if s.PointRadius == 0 {
+		return 3
+	}
+	return s.PointRadius
+}
+
+// A Canvas collects layers of geometry and writes them as an SVG image. The
+// zero value is an empty canvas that shows the plate carree projection of
+// its layers.
+type Canvas struct {
+	// Projection maps the sphere to the plane of the image, with x to the
+	// right and y up. If nil, the plate carree projection with longitude and
+	// latitude in degrees is used.
+	Projection s2.Projection
+
+	// Width is the width of the image in pixels. If zero, DefaultWidth is
+	// used. The height follows from the aspect ratio of the view.
+	Width int
+
+	// View is the rectangle of projected coordinates shown in the image. If
+	// it has no area, the view is fitted to the layers with a small margin.
+	View r2.Rect
+
+	// Tolerance is the largest distance between the geodesic edges and the
+	// lines drawn for them. If zero, it is about half a pixel at the center
+	// of the view.
+	Tolerance s1.Angle
+
+	layers []layer
+}
+
+// layer is geometry drawn with the same style.
+type layer struct {
+	style Style
+	items []item
+}
+
+// item is a piece of geometry with an optional label.
+type item struct {
+	// area, if not nil, returns the polygon to fill and outline. Areas with
+	// curved boundaries, such as caps, are approximated to within the given
+	// tolerance.
+	area func(tolerance s1.Angle) *s2.Polygon
+
+	lines  []s2.Polyline
+	points []s2.Point
+
+	// label is drawn at the point anchor if the style has labels.
+	label  string
+	anchor s2.Point
+}
+
+// projection returns the projection of the canvas.
+func (c *Canvas) projection() s2.Projection {
+	if c.Projection == nil {
+		return s2.NewPlateCarreeProjection(180)
+	}
+	return c.Projection
+}
+
+// width returns the width of the image in pixels.
+func (c *Canvas) width() int {
+	if c.Width <= 0 {
+		return DefaultWidth
+	}
+	return c.Width
+}
+
+// projected is an item in projected coordinates.
+type projected struct {
+	// rings are the rings of the area, and lines its boundary and any other
+	// lines.
+	rings  [][]r2.Point
+	lines  [][]r2.Point
+	points []r2.Point
+	label  string
+	anchor r2.Point
+}
+
+// project returns the items of a layer in projected coordinates.
+func (c *Canvas) project(l layer, tess *s2.EdgeTessellator, tolerance s1.Angle) []projected {
+	proj := c.projection()
+	var out []projected
+	for _, it := range l.items {
+		var p projected
+		if it.area != nil {
+			if polygon := it.area(tolerance); polygon != nil {
+				p.rings = tess.ProjectPolygon(polygon)
+				for _, loop := range polygon.Loops() {
+					if loop.IsEmpty() || loop.IsFull() {
+						continue
+					}
+					boundary := append(s2.Polyline(nil), loop.Vertices()...)
+					boundary = append(boundary, loop.Vertex(0))
+					p.lines = append(p.lines, tess.ProjectPolyline(&boundary)...)
+				}
+			}
+		}
+		for i := range it.lines {
+			p.lines = append(p.lines, tess.ProjectPolyline(&it.lines[i])...)
+		}
+		for _, pt := range it.points {
+			p.points = append(p.points, proj.Project(pt))
+		}
+		if it.label != "" {
+			p.label, p.anchor = it.label, proj.Project(it.anchor)
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// fit returns the bounding rectangle of the layers in projected coordinates,
+// plus a margin.
+func (c *Canvas) fit() r2.Rect {
+	tess := s2.NewEdgeTessellator(c.projection(), fitTolerance)
+	bound := r2.EmptyRect()
+	add := func(p r2.Point) {
+		if finite(p) {
+			bound = bound.AddPoint(p)
+		}
+	}
+	for _, l := range c.layers {
+		for _, p := range c.project(l, tess, fitTolerance) {
+			for _, ring := range p.rings {
+				for _, q := range ring {
+					add(q)
+				}
+			}
+			for _, line := range p.lines {
+				for _, q := range line {
+					add(q)
+				}
+			}
+			for _, q := range p.points {
+				add(q)
+			}
+		}
+	}
+	if bound.IsEmpty() {
+		return r2.Rect{X: r1.Interval{Lo: -1, Hi: 1}, Y: r1.Interval{Lo: -1, Hi: 1}}
+	}
+	// Geometry that is a single point or a line along an axis still gets a
+	// view with some area.
+	size := math.Max(bound.X.Length(), bound.Y.Length())
+	if size == 0 {
+		size = 1
+	}
+	return bound.ExpandedByMargin(margin * size)
+}
+
+// tolerance returns the tolerance of the tessellation for a view in which a
+// pixel is the given number of projected units wide: half the distance on the
+// sphere across a pixel at the center of the view.
+func (c *Canvas) tolerance(view r2.Rect, pixel float64) s1.Angle {
+	if c.Tolerance > 0 {
+		return c.Tolerance
+	}
+	proj := c.projection()
+	center := view.Center()
+	a := proj.Unproject(center)
+	b := proj.Unproject(r2.Point{X: center.X + pixel, Y: center.Y})
+	tolerance := a.Distance(b) / 2
+	if math.IsNaN(float64(tolerance)) || tolerance <= 0 {
+		return fitTolerance
+	}
+	return tolerance
+}
+
+// WriteTo writes the layers of the canvas as an SVG image to w.
+func (c *Canvas) WriteTo(w io.Writer) (int64, error) {
+	view := c.View
+	if view.IsEmpty() || view.X.Length() <= 0 || view.Y.Length() <= 0 {
+		view = c.fit()
+	}
+	width := c.width()
+	scale := float64(width) / view.X.Length()
+	height := int(math.Ceil(view.Y.Length() * scale))
+	tolerance := c.tolerance(view, 1/scale)
+	tess := s2.NewEdgeTessellator(c.projection(), tolerance)
+
+	// The y axis of the image points down.
+	toImage := func(p r2.Point) r2.Point {
+		return r2.Point{X: (p.X - view.X.Lo) * scale, Y: (view.Y.Hi - p.Y) * scale}
+	}
+
+	var buf bytes.Buffer
+	b := bufio.NewWriter(&buf)
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+	for _, l := range c.layers {
+		s := l.style
+		fmt.Fprintf(b, `<g stroke="%s" stroke-width="%s" fill="none">`+"\n",
+			escape(s.stroke()), number(s.strokeWidth()))
+		items := c.project(l, tess, tolerance)
+		for _, p := range items {
+			if s.Fill != "" && len(p.rings) > 0 {
+				fmt.Fprintf(b, `<path d="%s" fill="%s" fill-opacity="%s" stroke="none"/>`+"\n",
+					pathData(p.rings, true, toImage), escape(s.Fill), number(s.fillOpacity()))
+			}
+			if len(p.lines) > 0 {
+				fmt.Fprintf(b, `<path d="%s"/>`+"\n", pathData(p.lines, false, toImage))
+			}
+			for _, q := range p.points {
+				if !finite(q) {
+					continue
+				}
+				q = toImage(q)
+				fmt.Fprintf(b, `<circle cx="%s" cy="%s" r="%s" fill="%s" stroke="none"/>`+"\n",
+					number(q.X), number(q.Y), number(s.pointRadius()), escape(s.stroke()))
+			}
+		}
+		if s.Labels {
+			for _, p := range items {
+				if p.label == "" || !finite(p.anchor) {
+					continue
+				}
+				q := toImage(p.anchor)
+				fmt.Fprintf(b, `<text x="%s" y="%s" font-size="%d" text-anchor="middle" fill="%s" stroke="none">%s</text>`+"\n",
+					number(q.X), number(q.Y), fontSize, escape(s.stroke()), escape(p.label))
+			}
+		}
+		b.WriteString("</g>\n")
+	}
+	b.WriteString("</svg>\n")
+	b.Flush()
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// pathData returns the SVG path data for the given chains, converted to
+// image coordinates. If closed is true, each chain is closed. Chains with
+// points that are not finite are skipped.
+func pathData(chains [][]r2.Point, closed bool, toImage func(r2.Point) r2.Point) string {
+	var b bytes.Buffer
+outer:
+	for _, chain := range chains {
+		for _, p := range chain {
+			if !finite(p) {
+				continue outer
+			}
+		}
+		for i, p := range chain {
+			p = toImage(p)
+			if b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			if i == 0 {
+				b.WriteByte('M')
+			} else if i == 1 {
+				b.WriteByte('L')
+			}
+			b.WriteString(number(p.X))
+			b.WriteByte(' ')
+			b.WriteString(number(p.Y))
+		}
+		if closed {
+			b.WriteString(" Z")
+		}
+	}
+	return b.String()
+}
+
+// number formats a coordinate or size in pixels to a hundredth of a pixel.
+func number(x float64) string {
+	x = math.Round(x*100) / 100
+	if x == 0 {
+		// Avoid "-0".
+		x = 0
+	}
+	return strconv.FormatFloat(x, 'f', -1, 64)
+}
+
+// escape escapes the text for use in an attribute or element.
+func escape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// finite reports whether both coordinates of p are finite.
+func finite(p r2.Point) bool {
+	return !math.IsInf(p.X, 0) && !math.IsNaN(p.X) && !math.IsInf(p.Y, 0) && !math.IsNaN(p.Y)
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/rubenpoppe/geo/r1"
+	"github.com/rubenpoppe/geo/r2"
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// image is the parsed structure of the SVG written by a Canvas.
+type image struct {
+	Width  int `xml:"width,attr"`
+	Height int `xml:"height,attr"`
+	Groups []struct {
+		Stroke      string `xml:"stroke,attr"`
+		StrokeWidth string `xml:"stroke-width,attr"`
+		Paths       []struct {
+			D    string `xml:"d,attr"`
+			Fill string `xml:"fill,attr"`
+		} `xml:"path"`
+		Circles []struct {
+			CX string `xml:"cx,attr"`
+			CY string `xml:"cy,attr"`
+			R  string `xml:"r,attr"`
+		} `xml:"circle"`
+		Texts []string `xml:"text"`
+	} `xml:"g"`
+}
+
+// render writes the canvas and parses the result.
+func render(t *testing.T, c *Canvas) image {
+	t.Helper()
+	var b bytes.Buffer
+	n, err := c.WriteTo(&b)
+	if err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	if n != int64(b.Len()) {
+		t.Errorf("WriteTo() = %d, but wrote %d bytes", n, b.Len())
+	}
+	var img image
+	if err := xml.Unmarshal(b.Bytes(), &img); err != nil {
+		t.Fatalf("WriteTo() wrote invalid XML: %v\n%s", err, b.String())
+	}
+	return img
+}
+
+func TestWriteToEmpty(t *testing.T) {
+	img := render(t, new(Canvas))
+	if img.Width != DefaultWidth || img.Height != DefaultWidth || len(img.Groups) != 0 {
+		t.Errorf("empty canvas = %+v, want a %dx%d image without layers", img, DefaultWidth, DefaultWidth)
+	}
+}
+
+func TestWriteToView(t *testing.T) {
+	c := &Canvas{
+		Width: 200,
+		View:  r2.Rect{X: r1.Interval{Lo: 0, Hi: 10}, Y: r1.Interval{Lo: 0, Hi: 5}},
+	}
+	c.AddPoints([]s2.Point{
+		s2.PointFromLatLng(s2.LatLngFromDegrees(2.5, 5)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(5, 0)),
+	}, Style{PointRadius: 2})
+	img := render(t, c)
+	if img.Width != 200 || img.Height != 100 {
+		t.Errorf("image size = %dx%d, want 200x100", img.Width, img.Height)
+	}
+	if len(img.Groups) != 1 || len(img.Groups[0].Circles) != 2 {
+		t.Fatalf("image = %+v, want one layer with two points", img)
+	}
+	want := [][3]string{{"100", "50", "2"}, {"0", "0", "2"}}
+	for i, circle := range img.Groups[0].Circles {
+		if got := [3]string{circle.CX, circle.CY, circle.R}; got != want[i] {
+			t.Errorf("circle %d = %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestWriteToFit(t *testing.T) {
+	// The view is fitted to the geometry plus a margin, so the points are
+	// inside the image but not on its edge.
+	c := &Canvas{Width: 100}
+	c.AddPoints([]s2.Point{
+		s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(10, 20)),
+	}, Style{})
+	img := render(t, c)
+	if img.Width != 100 || img.Height != 55 {
+		t.Errorf("image size = %dx%d, want 100x55", img.Width, img.Height)
+	}
+	want := [][2]string{{"4.55", "50"}, {"95.45", "4.55"}}
+	for i, circle := range img.Groups[0].Circles {
+		if got := [2]string{circle.CX, circle.CY}; got != want[i] {
+			t.Errorf("circle %d = %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestWriteToStyle(t *testing.T) {
+	cell := s2.CellIDFromLatLng(s2.LatLngFromDegrees(10, 10)).Parent(5)
+	c := new(Canvas)
+	c.AddCellUnion(s2.CellUnion{cell}, Style{})
+	c.AddCellUnion(s2.CellUnion{cell}, Style{Stroke: "red", StrokeWidth: 2.5, Fill: "blue", Labels: true})
+	img := render(t, c)
+	if len(img.Groups) != 2 {
+		t.Fatalf("image has %d layers, want 2", len(img.Groups))
+	}
+
+	plain, styled := img.Groups[0], img.Groups[1]
+	if plain.Stroke != "black" || plain.StrokeWidth != "1" {
+		t.Errorf("default stroke = %q with width %q, want black with width 1", plain.Stroke, plain.StrokeWidth)
+	}
+	if len(plain.Paths) != 1 || len(plain.Texts) != 0 {
+		t.Errorf("layer without fill and labels has %d paths and %d labels, want 1 and 0", len(plain.Paths), len(plain.Texts))
+	}
+	if styled.Stroke != "red" || styled.StrokeWidth != "2.5" {
+		t.Errorf("stroke = %q with width %q, want red with width 2.5", styled.Stroke, styled.StrokeWidth)
+	}
+	if len(styled.Paths) != 2 || styled.Paths[0].Fill != "blue" {
+		t.Errorf("layer with fill = %+v, want a blue area and an outline", styled.Paths)
+	}
+	if len(styled.Texts) != 1 || styled.Texts[0] != cell.ToToken() {
+		t.Errorf("labels = %q, want [%q]", styled.Texts, cell.ToToken())
+	}
+}
+
+func TestWriteToAntimeridian(t *testing.T) {
+	// A line across the antimeridian is drawn as two pieces, one on each
+	// side of the map.
+	c := &Canvas{View: r2.Rect{X: r1.Interval{Lo: -180, Hi: 180}, Y: r1.Interval{Lo: -90, Hi: 90}}}
+	c.AddPolyline(&s2.Polyline{
+		s2.PointFromLatLng(s2.LatLngFromDegrees(0, 170)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(0, -170)),
+	}, Style{})
+	img := render(t, c)
+	want := "M777.78 200 L800 200 M0 200 L22.22 200"
+	if got := img.Groups[0].Paths[0].D; got != want {
+		t.Errorf("path = %q, want %q", got, want)
+	}
+}
+
+func TestWriteToEscaping(t *testing.T) {
+	c := new(Canvas)
+	c.AddPoints([]s2.Point{s2.PointFromCoords(1, 0, 0)}, Style{Stroke: `"red"&<blue>`})
+	var b bytes.Buffer
+	c.WriteTo(&b)
+	if got := b.String(); !strings.Contains(got, `stroke="&#34;red&#34;&amp;&lt;blue&gt;"`) {
+		t.Errorf("WriteTo() = %s, want the stroke color escaped", got)
+	}
+}
+
+func TestNumber(t *testing.T) {
+	tests := []struct {
+		have float64
+		want string
+	}{
+		{0, "0"},
+		{-0.001, "0"},
+		{1.5, "1.5"},
+		{2.345678, "2.35"},
+		{-100, "-100"},
+	}
+	for _, test := range tests {
+		if got := number(test.have); got != test.want {
+			t.Errorf("number(%v) = %q, want %q", test.have, got, test.want)
+		}
+	}
+}
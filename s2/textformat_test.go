@@ -214,28 +214,28 @@ func makePolyline(s string) *Polyline {
 	return &p
 }
 
-// makeLaxPolyline constructs a laxPolyline from the given string.
-func makeLaxPolyline(s string) *laxPolyline {
-	return laxPolylineFromPoints(parsePoints(s))
+// makeLaxPolyline constructs a LaxPolyline from the given string.
+func makeLaxPolyline(s string) *LaxPolyline {
+	return LaxPolylineFromPoints(parsePoints(s))
 }
 
 // laxPolylineToString returns a string representation suitable for reconstruction
 // by the makeLaxPolyline method.
-func laxPolylineToString(l *laxPolyline) string {
+func laxPolylineToString(l *LaxPolyline) string {
 	var buf bytes.Buffer
 	writePoints(&buf, l.vertices)
 	return buf.String()
 }
 
-// makeLaxPolygon creates a laxPolygon from the given debug formatted string.
+// makeLaxPolygon creates a LaxPolygon from the given debug formatted string.
 // Similar to makePolygon, except that loops must be oriented so that the
 // interior of the loop is always on the left, and polygons with degeneracies
 // are supported. As with makePolygon, "full" denotes the full polygon and "empty"
-// is not allowed (instead, simply create a laxPolygon with no loops).
-func makeLaxPolygon(s string) *laxPolygon {
+// is not allowed (instead, simply create a LaxPolygon with no loops).
+func makeLaxPolygon(s string) *LaxPolygon {
 	var points [][]Point
 	if s == "" {
-		return laxPolygonFromPoints(points)
+		return LaxPolygonFromPoints(points)
 	}
 	for _, l := range strings.Split(s, ";") {
 		if l == "full" {
@@ -244,7 +244,7 @@ func makeLaxPolygon(s string) *laxPolygon {
 			points = append(points, parsePoints(l))
 		}
 	}
-	return laxPolygonFromPoints(points)
+	return LaxPolygonFromPoints(points)
 }
 
 // makeShapeIndex builds a ShapeIndex from the given debug string containing
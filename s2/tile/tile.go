@@ -0,0 +1,287 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package tile converts between the tiles of web maps and s2 geometry.
+
+Tiles are addressed by zoom level z and column x and row y in the XYZ scheme
+of web maps, where tile 0/0/0 covers the whole Web Mercator square and y
+increases southwards, or by the equivalent Bing Maps quadkey. The Web Mercator
+square extends to MaxLatitude north and south, and has no tiles closer to the
+poles.
+
+The edges of a tile are a parallel at the top and bottom, and a meridian at
+each side. A tile is therefore exactly an s2.Rect, but not exactly a Loop,
+whose edges are geodesics: the parallels are straight lines under the
+Mercator projection, and are tessellated with an EdgeTessellator to within a
+given tolerance.
+*/
+package tile
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/rubenpoppe/geo/r1"
+	"github.com/rubenpoppe/geo/r2"
+	"github.com/rubenpoppe/geo/s1"
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// MaxZoom is the largest supported zoom level, at which tiles are a few
+// centimeters wide.
+const MaxZoom = 30
+
+// MaxLatitude is the latitude of the top edge of the Web Mercator square,
+// which makes the square as tall as it is wide. It is about 85.05 degrees.
+var MaxLatitude = s1.Angle(math.Atan(math.Sinh(math.Pi)))
+
+// projection maps the Web Mercator square to [-0.5, 0.5] in both directions,
+// with y increasing northwards.
+var projection = s2.NewMercatorProjection(0.5).(*s2.MercatorProjection)
+
+// A Tile is the tile in column X and row Y at zoom level Z.
+type Tile struct {
+	Z, X, Y int
+}
+
+// String returns the tile in z/x/y form.
+func (t Tile) String() string {
+	return fmt.Sprintf("%d/%d/%d", t.Z, t.X, t.Y)
+}
+
+// IsValid reports whether the tile exists.
+func (t Tile) IsValid() bool {
+	if t.Z < 0 || t.Z > MaxZoom {
+		return false
+	}
+	n := 1 << uint(t.Z)
+	return t.X >= 0 && t.X < n && t.Y >= 0 && t.Y < n
+}
+
+// FromLatLng returns the tile at zoom level z that contains the given point.
+// Points beyond MaxLatitude are in the top or bottom row of tiles.
+func FromLatLng(ll s2.LatLng, z int) Tile {
+	n := float64(int(1) << uint(z))
+	p := projection.FromLatLng(ll.Normalized())
+	return Tile{
+		Z: z,
+		X: clamp((p.X+0.5)*n, n),
+		Y: clamp((0.5-p.Y)*n, n),
+	}
+}
+
+// clamp returns the column or row of n tiles that contains the coordinate
+// x, or the nearest one if x is outside them.
+func clamp(x, n float64) int {
+	return int(math.Max(0, math.Min(n-1, math.Floor(x))))
+}
+
+// FromQuadkey returns the tile with the given quadkey. The quadkey of tile
+// 0/0/0 is the empty string.
+func FromQuadkey(quadkey string) (Tile, error) {
+	if len(quadkey) > MaxZoom {
+		return Tile{}, fmt.Errorf("tile: quadkey %q is longer than %d digits", quadkey, MaxZoom)
+	}
+	t := Tile{Z: len(quadkey)}
+	for i := 0; i < len(quadkey); i++ {
+		d := quadkey[i] - '0'
+		if d > 3 {
+			return Tile{}, fmt.Errorf("tile: invalid digit %q in quadkey %q", quadkey[i], quadkey)
+		}
+		t.X = t.X<<1 | int(d&1)
+		t.Y = t.Y<<1 | int(d>>1)
+	}
+	return t, nil
+}
+
+// Quadkey returns the quadkey of the tile, which has one digit per zoom
+// level. Each digit selects one of the four children of the previous tile,
+// numbered 0 to 3 from left to right and top to bottom.
+func (t Tile) Quadkey() string {
+	var b strings.Builder
+	for i := t.Z - 1; i >= 0; i-- {
+		d := byte('0')
+		d += byte(t.X >> uint(i) & 1)
+		d += byte(t.Y>>uint(i)&1) << 1
+		b.WriteByte(d)
+	}
+	return b.String()
+}
+
+// Parent returns the tile at the previous zoom level that contains t. The
+// parent of tile 0/0/0 is itself.
+func (t Tile) Parent() Tile {
+	if t.Z == 0 {
+		return t
+	}
+	return Tile{Z: t.Z - 1, X: t.X >> 1, Y: t.Y >> 1}
+}
+
+// Children returns the four tiles at the next zoom level that t contains, in
+// quadkey order.
+func (t Tile) Children() [4]Tile {
+	var children [4]Tile
+	for i := range children {
+		children[i] = Tile{Z: t.Z + 1, X: t.X<<1 | i&1, Y: t.Y<<1 | i>>1}
+	}
+	return children
+}
+
+// world returns the tile in the coordinates of the projection.
+func (t Tile) world() r2.Rect {
+	n := float64(int(1) << uint(t.Z))
+	return r2.Rect{
+		X: r1.Interval{Lo: -0.5 + float64(t.X)/n, Hi: -0.5 + float64(t.X+1)/n},
+		Y: r1.Interval{Lo: 0.5 - float64(t.Y+1)/n, Hi: 0.5 - float64(t.Y)/n},
+	}
+}
+
+// Rect returns the tile as a latitude-longitude rectangle, which is exact.
+func (t Tile) Rect() s2.Rect {
+	w := t.world()
+	lo := projection.ToLatLng(r2.Point{X: w.X.Lo, Y: w.Y.Lo})
+	hi := projection.ToLatLng(r2.Point{X: w.X.Hi, Y: w.Y.Hi})
+	lat := r1.Interval{Lo: lo.Lat.Radians(), Hi: hi.Lat.Radians()}
+	if t.Z == 0 {
+		return s2.Rect{Lat: lat, Lng: s1.FullInterval()}
+	}
+	return s2.Rect{Lat: lat, Lng: s1.IntervalFromEndpoints(lo.Lng.Radians(), hi.Lng.Radians())}
+}
+
+// Loop returns the boundary of the tile as a loop whose edges are within the
+// given tolerance of the parallels and meridians that bound the tile.
+//
+// Tile 0/0/0 goes all the way around the world and has no meridians on its
+// boundary, so it is not a loop, and Loop returns nil for it. Use Polygon
+// instead.
+func (t Tile) Loop(tolerance s1.Angle) *s2.Loop {
+	if t.Z == 0 {
+		return nil
+	}
+	w := t.world()
+	tess := s2.NewEdgeTessellator(projection, tolerance)
+
+	// Counterclockwise from the south west corner. The parallels are split in
+	// two, since the tessellator would wrap an edge as wide as half the world
+	// the other way around.
+	corners := []r2.Point{
+		{X: w.X.Lo, Y: w.Y.Lo},
+		{X: w.X.Center(), Y: w.Y.Lo},
+		{X: w.X.Hi, Y: w.Y.Lo},
+		{X: w.X.Hi, Y: w.Y.Hi},
+		{X: w.X.Center(), Y: w.Y.Hi},
+		{X: w.X.Lo, Y: w.Y.Hi},
+		{X: w.X.Lo, Y: w.Y.Lo},
+	}
+	var vertices []s2.Point
+	for i := 0; i+1 < len(corners); i++ {
+		vertices = tess.AppendUnprojected(corners[i], corners[i+1], vertices)
+	}
+	return s2.LoopFromPoints(vertices[:len(vertices)-1])
+}
+
+// Polygon returns the tile as a polygon whose edges are within the given
+// tolerance of the parallels and meridians that bound the tile. For tile
+// 0/0/0, the polygon is the band between the parallels at MaxLatitude north
+// and south.
+func (t Tile) Polygon(tolerance s1.Angle) *s2.Polygon {
+	if t.Z != 0 {
+		return s2.PolygonFromLoops([]*s2.Loop{t.Loop(tolerance)})
+	}
+	// The band is the complement of the north polar cap, with the south polar
+	// cap as a hole.
+	tess := s2.NewEdgeTessellator(projection, tolerance)
+	// Both loops go westward along their parallel, in quarters so that the
+	// tessellator does not wrap them.
+	parallel := func(y float64) []s2.Point {
+		var vertices []s2.Point
+		for i := 0; i < 4; i++ {
+			a := r2.Point{X: 0.5 - float64(i)/4, Y: y}
+			b := r2.Point{X: 0.5 - float64(i+1)/4, Y: y}
+			vertices = tess.AppendUnprojected(a, b, vertices)
+		}
+		return vertices[:len(vertices)-1]
+	}
+	north := s2.LoopFromPoints(parallel(0.5))
+	south := s2.LoopFromPoints(parallel(-0.5))
+	return s2.PolygonFromLoops([]*s2.Loop{north, south})
+}
+
+// CellUnion returns a covering of the tile by cells, which is computed from
+// its exact Rect. If coverer is nil, a covering by at most 8 cells is
+// returned.
+func (t Tile) CellUnion(coverer *s2.RegionCoverer) s2.CellUnion {
+	if coverer == nil {
+		coverer = defaultCoverer
+	}
+	return coverer.Covering(t.Rect())
+}
+
+var defaultCoverer = &s2.RegionCoverer{MaxLevel: 30, LevelMod: 1, MaxCells: 8}
+
+// Covering returns the tiles at zoom level z that intersect the region, in
+// quadkey order. Like a covering by cells, the result may include a few
+// tiles close to the region that do not intersect it.
+//
+// The tiles are found by descending from tile 0/0/0, and testing the cells
+// of a covering of each tile against the region.
+func Covering(region s2.Region, z int) []Tile {
+	if z < 0 || z > MaxZoom {
+		return nil
+	}
+	bound := region.RectBound()
+	var tiles []Tile
+	var visit func(t Tile)
+	visit = func(t Tile) {
+		rect := t.Rect()
+		if !bound.Intersects(rect) {
+			return
+		}
+		intersects, contains := false, true
+		for _, id := range defaultCoverer.Covering(rect) {
+			cell := s2.CellFromCellID(id)
+			if region.IntersectsCell(cell) {
+				intersects = true
+			}
+			if !region.ContainsCell(cell) {
+				contains = false
+			}
+		}
+		switch {
+		case !intersects:
+		case contains || t.Z == z:
+			tiles = t.appendDescendants(tiles, z)
+		default:
+			for _, c := range t.Children() {
+				visit(c)
+			}
+		}
+	}
+	visit(Tile{})
+	return tiles
+}
+
+// appendDescendants appends the tiles at zoom level z inside t, in quadkey
+// order.
+func (t Tile) appendDescendants(tiles []Tile, z int) []Tile {
+	if t.Z == z {
+		return append(tiles, t)
+	}
+	for _, c := range t.Children() {
+		tiles = c.appendDescendants(tiles, z)
+	}
+	return tiles
+}
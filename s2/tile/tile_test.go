@@ -0,0 +1,344 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tile
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/rubenpoppe/geo/s1"
+	"github.com/rubenpoppe/geo/s2"
+	"github.com/rubenpoppe/geo/s2/s2testing"
+)
+
+func TestQuadkey(t *testing.T) {
+	tests := []struct {
+		tile    Tile
+		quadkey string
+	}{
+		{Tile{0, 0, 0}, ""},
+		{Tile{1, 1, 0}, "1"},
+		{Tile{1, 0, 1}, "2"},
+		// The example from the Bing Maps documentation.
+		{Tile{3, 3, 5}, "213"},
+		{Tile{4, 15, 15}, "3333"},
+	}
+	for _, test := range tests {
+		if got := test.tile.Quadkey(); got != test.quadkey {
+			t.Errorf("%v.Quadkey() = %q, want %q", test.tile, got, test.quadkey)
+		}
+		got, err := FromQuadkey(test.quadkey)
+		if err != nil || got != test.tile {
+			t.Errorf("FromQuadkey(%q) = %v, %v, want %v", test.quadkey, got, err, test.tile)
+		}
+	}
+
+	for _, q := range []string{"4", "01a", "0123012301230123012301230123012"} {
+		if _, err := FromQuadkey(q); err == nil {
+			t.Errorf("FromQuadkey(%q) succeeded, want error", q)
+		}
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		tile Tile
+		want bool
+	}{
+		{Tile{0, 0, 0}, true},
+		{Tile{2, 3, 3}, true},
+		{Tile{2, 4, 0}, false},
+		{Tile{2, 0, -1}, false},
+		{Tile{-1, 0, 0}, false},
+		{Tile{MaxZoom, 1<<MaxZoom - 1, 0}, true},
+		{Tile{MaxZoom + 1, 0, 0}, false},
+	}
+	for _, test := range tests {
+		if got := test.tile.IsValid(); got != test.want {
+			t.Errorf("%v.IsValid() = %v, want %v", test.tile, got, test.want)
+		}
+	}
+}
+
+func TestParentChildren(t *testing.T) {
+	tl := Tile{3, 5, 2}
+	want := [4]Tile{{4, 10, 4}, {4, 11, 4}, {4, 10, 5}, {4, 11, 5}}
+	if got := tl.Children(); got != want {
+		t.Errorf("%v.Children() = %v, want %v", tl, got, want)
+	}
+	for i, c := range tl.Children() {
+		if c.Parent() != tl {
+			t.Errorf("%v.Parent() = %v, want %v", c, c.Parent(), tl)
+		}
+		if q := c.Quadkey(); q[:3] != tl.Quadkey() || int(q[3]-'0') != i {
+			t.Errorf("%v.Quadkey() = %q, want child %d of %q", c, q, i, tl.Quadkey())
+		}
+	}
+	if got := (Tile{}).Parent(); got != (Tile{}) {
+		t.Errorf("Parent of 0/0/0 = %v, want itself", got)
+	}
+}
+
+func TestFromLatLng(t *testing.T) {
+	tests := []struct {
+		lat, lng float64
+		z        int
+		want     Tile
+	}{
+		{0, 0, 0, Tile{0, 0, 0}},
+		{0, 0, 1, Tile{1, 1, 1}},
+		{1, -1, 1, Tile{1, 0, 0}},
+		{-1, 1, 1, Tile{1, 1, 1}},
+		{90, -180, 3, Tile{3, 0, 0}},
+		{-90, 180, 3, Tile{3, 7, 7}},
+		{89, 179.9, 3, Tile{3, 7, 0}},
+		// The example from the OpenStreetMap wiki.
+		{52.52, 13.405, 10, Tile{10, 550, 335}},
+	}
+	for _, test := range tests {
+		if got := FromLatLng(s2.LatLngFromDegrees(test.lat, test.lng), test.z); got != test.want {
+			t.Errorf("FromLatLng(%v, %v, %d) = %v, want %v", test.lat, test.lng, test.z, got, test.want)
+		}
+	}
+}
+
+func TestRect(t *testing.T) {
+	maxLat := MaxLatitude.Degrees()
+	tests := []struct {
+		tile                       Tile
+		latLo, latHi, lngLo, lngHi float64
+	}{
+		{Tile{0, 0, 0}, -maxLat, maxLat, -180, 180},
+		{Tile{1, 0, 0}, 0, maxLat, -180, 0},
+		{Tile{1, 1, 1}, -maxLat, 0, 0, 180},
+		{Tile{2, 2, 1}, 0, 66.51326044311186, 0, 90},
+	}
+	for _, test := range tests {
+		got := test.tile.Rect()
+		want := s2.RectFromLatLng(s2.LatLngFromDegrees(test.latLo, test.lngLo)).
+			AddPoint(s2.LatLngFromDegrees(test.latHi, test.lngHi))
+		if test.tile.Z == 0 {
+			want.Lng = s1.FullInterval()
+		}
+		if !got.ApproxEqual(want) {
+			t.Errorf("%v.Rect() = %v, want %v", test.tile, got, want)
+		}
+	}
+
+	// Each point is in the rectangle of its tile, and the rectangles of
+	// adjacent tiles meet exactly.
+	r := s2testing.NewRand(1)
+	for i := 0; i < 1000; i++ {
+		ll := s2.LatLngFromPoint(r.Point())
+		if math.Abs(ll.Lat.Radians()) > MaxLatitude.Radians() {
+			continue
+		}
+		tl := FromLatLng(ll, r.UniformInt(MaxZoom+1))
+		if !tl.Rect().ContainsLatLng(ll) {
+			t.Errorf("%v.Rect() = %v does not contain %v", tl, tl.Rect(), ll)
+		}
+		if tl.Y > 0 {
+			above := Tile{tl.Z, tl.X, tl.Y - 1}
+			if tl.Rect().Lat.Hi != above.Rect().Lat.Lo {
+				t.Errorf("%v.Rect() = %v does not meet %v.Rect() = %v", tl, tl.Rect(), above, above.Rect())
+			}
+		}
+	}
+}
+
+func TestLoop(t *testing.T) {
+	if l := (Tile{}).Loop(s1.Degree); l != nil {
+		t.Errorf("Loop of 0/0/0 = %v, want nil", l)
+	}
+
+	tolerance := 0.01 * s1.Degree
+	r := s2testing.NewRand(2)
+	tiles := []Tile{{1, 0, 0}, {1, 1, 1}, {2, 0, 3}, {3, 6, 2}}
+	for i := 0; i < 20; i++ {
+		ll := s2.LatLngFromDegrees(r.UniformFloat64(-85, 85), r.UniformFloat64(-180, 180))
+		tiles = append(tiles, FromLatLng(ll, 2+r.UniformInt(10)))
+	}
+	for _, tl := range tiles {
+		loop := tl.Loop(tolerance)
+		if err := loop.Validate(); err != nil {
+			t.Errorf("%v.Loop() is invalid: %v", tl, err)
+			continue
+		}
+		rect := tl.Rect()
+
+		// Points that are more than the tolerance inside the parallels and
+		// meridians bounding the tile are inside the loop, and points more
+		// than the tolerance outside are not.
+		margin := 2 * tolerance.Radians()
+		for j := 1; j < 20; j++ {
+			f := float64(j) / 20
+			lng := s1.Angle(rect.Lng.Lo+f*rect.Lng.Length()) * s1.Radian
+			lat := s1.Angle(rect.Lat.Lo+f*rect.Lat.Length()) * s1.Radian
+			for _, edge := range []struct {
+				in, out s2.LatLng
+			}{
+				{s2.LatLng{Lat: s1.Angle(rect.Lat.Hi - margin), Lng: lng}, s2.LatLng{Lat: s1.Angle(rect.Lat.Hi + margin), Lng: lng}},
+				{s2.LatLng{Lat: s1.Angle(rect.Lat.Lo + margin), Lng: lng}, s2.LatLng{Lat: s1.Angle(rect.Lat.Lo - margin), Lng: lng}},
+				{s2.LatLng{Lat: lat, Lng: s1.Angle(rect.Lng.Lo + margin)}, s2.LatLng{Lat: lat, Lng: s1.Angle(rect.Lng.Lo - margin)}},
+				{s2.LatLng{Lat: lat, Lng: s1.Angle(rect.Lng.Hi - margin)}, s2.LatLng{Lat: lat, Lng: s1.Angle(rect.Lng.Hi + margin)}},
+			} {
+				if !loop.ContainsPoint(s2.PointFromLatLng(edge.in)) {
+					t.Errorf("%v.Loop() does not contain %v", tl, edge.in)
+				}
+				if rect.ContainsLatLng(edge.out) {
+					continue
+				}
+				if loop.ContainsPoint(s2.PointFromLatLng(edge.out)) {
+					t.Errorf("%v.Loop() contains %v", tl, edge.out)
+				}
+			}
+		}
+	}
+}
+
+func TestLoopTessellation(t *testing.T) {
+	// The parallels of a tile need more vertices for a smaller tolerance.
+	tl := Tile{2, 1, 0}
+	coarse := tl.Loop(s1.Degree)
+	fine := tl.Loop(0.001 * s1.Degree)
+	if coarse.NumVertices() >= fine.NumVertices() {
+		t.Errorf("%v.Loop has %d vertices at 1 degree and %d at 0.001 degrees, want more for the smaller tolerance",
+			tl, coarse.NumVertices(), fine.NumVertices())
+	}
+
+	// Adjacent tiles share the vertices of their common edge.
+	below := Tile{2, 1, 1}.Loop(0.001 * s1.Degree)
+	lat := tl.Rect().Lat.Lo
+	onEdge, shared := 0, 0
+	for j := 0; j < below.NumVertices(); j++ {
+		v := below.Vertex(j)
+		if math.Abs(s2.LatLngFromPoint(v).Lat.Radians()-lat) > 1e-12 {
+			continue
+		}
+		onEdge++
+		for i := 0; i < fine.NumVertices(); i++ {
+			if fine.Vertex(i).ApproxEqual(v) {
+				shared++
+				break
+			}
+		}
+	}
+	if onEdge < 3 || shared != onEdge {
+		t.Errorf("tiles 2/1/0 and 2/1/1 share %d of the %d vertices on their common edge", shared, onEdge)
+	}
+}
+
+func TestPolygon(t *testing.T) {
+	world := Tile{}.Polygon(0.01 * s1.Degree)
+	if err := world.Validate(); err != nil {
+		t.Fatalf("Polygon of 0/0/0 is invalid: %v", err)
+	}
+	tests := []struct {
+		lat, lng float64
+		want     bool
+	}{
+		{0, 0, true},
+		{0, 180, true},
+		{-80, 123, true},
+		{85, 0, true},
+		{85.1, 0, false},
+		{-85.1, -90, false},
+		{90, 0, false},
+		{-90, 0, false},
+	}
+	for _, test := range tests {
+		if got := world.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(test.lat, test.lng))); got != test.want {
+			t.Errorf("Polygon of 0/0/0 contains %v:%v = %v, want %v", test.lat, test.lng, got, test.want)
+		}
+	}
+
+	tl := Tile{3, 4, 2}
+	if p := tl.Polygon(0.01 * s1.Degree); p.NumLoops() != 1 || !p.Loop(0).Equal(tl.Loop(0.01*s1.Degree)) {
+		t.Errorf("%v.Polygon() = %v, want its loop", tl, p)
+	}
+}
+
+func TestCellUnion(t *testing.T) {
+	r := s2testing.NewRand(3)
+	for i := 0; i < 20; i++ {
+		ll := s2.LatLngFromDegrees(r.UniformFloat64(-85, 85), r.UniformFloat64(-180, 180))
+		tl := FromLatLng(ll, r.UniformInt(20))
+		cu := tl.CellUnion(nil)
+		if len(cu) == 0 || len(cu) > 8 {
+			t.Errorf("%v.CellUnion(nil) has %d cells, want 1 to 8", tl, len(cu))
+		}
+		for j := 0; j < 10; j++ {
+			p := r.PointInRect(tl.Rect())
+			if !cu.ContainsPoint(p) {
+				t.Errorf("%v.CellUnion(nil) does not contain %v", tl, s2.LatLngFromPoint(p))
+			}
+		}
+
+		coverer := &s2.RegionCoverer{MaxLevel: 30, MaxCells: 100}
+		if fine := tl.CellUnion(coverer); len(fine) < len(cu) || !cu.Contains(fine) && fine.ApproxArea() > cu.ApproxArea() {
+			t.Errorf("%v.CellUnion with 100 cells = %v, want a finer covering than %v", tl, fine, cu)
+		}
+	}
+}
+
+func TestCovering(t *testing.T) {
+	// All the tiles at a zoom level in quadkey order.
+	var all []Tile
+	for i := 0; i < 16; i++ {
+		all = append(all, Tile{2, i&1 | i>>1&2, i>>1&1 | i>>2&2})
+	}
+	if got := Covering(s2.FullRect(), 2); !reflect.DeepEqual(got, all) {
+		t.Errorf("Covering(FullRect, 2) = %v, want %v", got, all)
+	}
+	if got := Covering(s2.FullRect(), MaxZoom+1); got != nil {
+		t.Errorf("Covering at zoom %d = %v, want nil", MaxZoom+1, got)
+	}
+
+	// A small region inside a tile is covered by that tile.
+	center := s2.LatLngFromDegrees(48.85, 2.35)
+	c := s2.CapFromCenterAngle(s2.PointFromLatLng(center), 0.01*s1.Degree)
+	if got, want := Covering(c, 8), []Tile{FromLatLng(center, 8)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Covering(%v, 8) = %v, want %v", c, got, want)
+	}
+
+	// The covering contains the tile of every point in the region, and each
+	// of its tiles is close to the region.
+	r := s2testing.NewRand(4)
+	for i := 0; i < 10; i++ {
+		c := r.Cap(1e-4, 1e-2)
+		z := 3 + r.UniformInt(5)
+		tiles := Covering(c, z)
+		found := make(map[Tile]bool)
+		for _, tl := range tiles {
+			found[tl] = true
+			if tl.Z != z {
+				t.Errorf("Covering(%v, %d) contains %v", c, z, tl)
+			}
+			if expanded := c.Expanded(tl.Rect().CapBound().Radius()); !tl.Rect().Intersects(expanded.RectBound()) {
+				t.Errorf("Covering(%v, %d) contains %v, which is far from the region", c, z, tl)
+			}
+		}
+		for j := 0; j < 100; j++ {
+			ll := s2.LatLngFromPoint(r.PointInCap(c))
+			if math.Abs(ll.Lat.Radians()) >= MaxLatitude.Radians() {
+				continue
+			}
+			if tl := FromLatLng(ll, z); !found[tl] {
+				t.Errorf("Covering(%v, %d) does not contain %v, the tile of %v", c, z, tl, ll)
+			}
+		}
+	}
+}
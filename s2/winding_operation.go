@@ -0,0 +1,522 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/rubenpoppe/geo/s1"
+)
+
+// WindingRule selects the points of a WindingOperation result by their
+// winding number.
+type WindingRule int
+
+// These are the winding rules supported by WindingOperation.
+const (
+	// WindingPositive selects the points with a positive winding number.
+	WindingPositive WindingRule = iota
+	// WindingNegative selects the points with a negative winding number.
+	WindingNegative
+	// WindingNonZero selects the points with a non-zero winding number.
+	WindingNonZero
+	// WindingOdd selects the points with an odd winding number.
+	WindingOdd
+)
+
+// matches reports whether points with the given winding number are selected
+// by this rule.
+func (r WindingRule) matches(winding int) bool {
+	switch r {
+	case WindingPositive:
+		return winding > 0
+	case WindingNegative:
+		return winding < 0
+	case WindingNonZero:
+		return winding != 0
+	default:
+		return winding%2 != 0
+	}
+}
+
+// maxWindingHotPixelSpacing is the maximum distance between the points along
+// an input edge whose cells are made hot pixels. Splitting long edges this
+// way keeps the snapped edges from bowing away from the input edges.
+const maxWindingHotPixelSpacing = math.Pi / 4
+
+// WindingOperation computes the region of the sphere whose winding number
+// with respect to a set of loops satisfies a WindingRule. The loops may
+// self-intersect, cross or overlap each other, share edges, and have any
+// orientation, so this can be used to turn a set of arbitrary rings into a
+// valid Polygon.
+//
+// The winding number of a point counts how many times the loops wind around
+// it counter-clockwise. It is only defined relative to some reference point:
+// crossing an edge from its right side to its left side adds one to the
+// winding number, and crossing it in the other direction subtracts one. For
+// example, given a set of CCW shells, using a reference point outside all of
+// them with a winding number of 0 and the WindingPositive rule computes their
+// union, while BuildAtLeast with k = 2 computes the region covered by at
+// least two shells.
+//
+// The input is snap rounded to the centers of cells at SnapLevel, which makes
+// the result robust: its loops never cross each other, although they may
+// share vertices. The cells that contain an input vertex or a point where
+// two input edges cross are "hot pixels", and every input edge is replaced
+// by a chain through the centers of the hot pixels that it intersects.
+// Each vertex therefore moves by at most the snap radius, which is half the
+// diagonal of a cell at SnapLevel, and each edge of the result stays within
+// about 1.1 times the snap radius of the input edge it comes from.
+//
+// This type is not safe for concurrent use.
+type WindingOperation struct {
+	// SnapLevel is the level of the cells whose centers the vertices of the
+	// result are snapped to. The default is the leaf cell level.
+	SnapLevel int
+
+	loops [][]Point
+}
+
+// NewWindingOperation creates a new WindingOperation that snaps to leaf cells.
+func NewWindingOperation() *WindingOperation {
+	return &WindingOperation{SnapLevel: maxLevel}
+}
+
+// AddLoop adds the given loop to the input. Its vertices need not form a
+// valid loop. The empty and full loops have no edges, so they do not change
+// the winding number of any point relative to the reference point.
+func (w *WindingOperation) AddLoop(l *Loop) {
+	if l.isEmptyOrFull() {
+		return
+	}
+	w.loops = append(w.loops, l.vertices)
+}
+
+// AddShape adds every chain of the given shape to the input as a loop. Shapes
+// whose dimension is not 2 are ignored. This accepts lax polygons, whose
+// loops may be degenerate or self-intersecting.
+func (w *WindingOperation) AddShape(shape Shape) {
+	if shape.Dimension() != 2 {
+		return
+	}
+	for i := 0; i < shape.NumChains(); i++ {
+		if vertices := chainVertices(shape, i); len(vertices) > 0 {
+			w.loops = append(w.loops, vertices)
+		}
+	}
+}
+
+// Build returns the region of points whose winding number satisfies the given
+// rule, where ref has the winding number refWinding. The reference point
+// must not be a vertex of the snapped input.
+//
+// Build does not reset the input, so more loops can be added and Build
+// called again.
+func (w *WindingOperation) Build(ref Point, refWinding int, rule WindingRule) (*Polygon, error) {
+	g := newWindingGraph(w.snappedEdges())
+	if err := g.computeWindings(ref, refWinding); err != nil {
+		return nil, err
+	}
+
+	loops := g.boundaryLoops(rule)
+	if len(loops) == 0 {
+		// Every edge has the same rule result on both sides, so the rule
+		// selects either every point or none.
+		if rule.matches(refWinding) {
+			return FullPolygon(), nil
+		}
+		return PolygonFromLoops(nil), nil
+	}
+	return PolygonFromOrientedLoops(loops), nil
+}
+
+// BuildAtLeast returns the region of points whose winding number is at least
+// k, where ref has the winding number refWinding.
+func (w *WindingOperation) BuildAtLeast(ref Point, refWinding, k int) (*Polygon, error) {
+	return w.Build(ref, refWinding-(k-1), WindingPositive)
+}
+
+// snappedEdges returns the edges of the input loops snap rounded to the
+// centers of cells at SnapLevel, with degenerate edges removed.
+func (w *WindingOperation) snappedEdges() []Edge {
+	level := w.SnapLevel
+	hot := make(map[CellID]bool)
+	var input []Edge
+	for _, loop := range w.loops {
+		for i, v := range loop {
+			hot[cellIDFromPoint(v).Parent(level)] = true
+			if next := loop[(i+1)%len(loop)]; next != v {
+				input = append(input, Edge{v, next})
+			}
+		}
+	}
+	for _, e := range input {
+		n := int(math.Ceil(float64(e.V0.Distance(e.V1)) / maxWindingHotPixelSpacing))
+		for k := 1; k < n; k++ {
+			hot[cellIDFromPoint(Interpolate(float64(k)/float64(n), e.V0, e.V1)).Parent(level)] = true
+		}
+	}
+
+	// The computed intersection point may be slightly away from the true one,
+	// so every cell within the error bound becomes a hot pixel.
+	index := NewShapeIndex()
+	shape := edgeListShape(input)
+	index.Add(&shape)
+	VisitCrossingEdgePairs(index, CrossingTypeInterior, func(a, b ShapeEdge, _ bool) bool {
+		x := Intersection(a.Edge.V0, a.Edge.V1, b.Edge.V0, b.Edge.V1)
+		for _, id := range SimpleRegionCovering(CapFromCenterAngle(x, intersectionError), x, level) {
+			hot[id] = true
+		}
+		return true
+	})
+
+	ids := make([]CellID, 0, len(hot))
+	for id := range hot {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	centers := make(PointVector, len(ids))
+	for i, id := range ids {
+		centers[i] = id.Point()
+	}
+	centerIndex := NewShapeIndex()
+	centerIndex.Add(&centers)
+
+	// A cell that an edge intersects has its center within one diagonal of
+	// the edge.
+	opts := NewClosestEdgeQueryOptions().DistanceLimit(s1.ChordAngleFromAngle(s1.Angle(MaxDiagMetric.Value(level))).Successor())
+	query := NewClosestEdgeQuery(centerIndex, opts)
+
+	var edges []Edge
+	for _, e := range input {
+		first, last := cellIDFromPoint(e.V0).Parent(level), cellIDFromPoint(e.V1).Parent(level)
+		type hotPixel struct {
+			center Point
+			t, tc  float64
+		}
+		var pixels []hotPixel
+		for _, r := range query.FindEdges(NewMinDistanceToEdgeTarget(e)) {
+			id := ids[r.EdgeID()]
+			if id == first || id == last {
+				continue
+			}
+			if t, ok := edgeEntryParameter(e, id); ok {
+				pixels = append(pixels, hotPixel{centers[r.EdgeID()], t, edgeParameter(e, centers[r.EdgeID()])})
+			}
+		}
+		sort.Slice(pixels, func(i, j int) bool {
+			if pixels[i].t != pixels[j].t {
+				return pixels[i].t < pixels[j].t
+			}
+			return pixels[i].tc < pixels[j].tc
+		})
+
+		prev := first.Point()
+		for _, p := range append(pixels, hotPixel{center: last.Point()}) {
+			if p.center != prev {
+				edges = append(edges, Edge{prev, p.center})
+				prev = p.center
+			}
+		}
+	}
+	return edges
+}
+
+// edgeParameter returns the angle from the start of the edge to the
+// projection of p onto the great circle through the edge, measured in the
+// direction of the edge.
+func edgeParameter(e Edge, p Point) float64 {
+	dir := e.V0.PointCross(e.V1).Cross(e.V0.Vector)
+	return math.Atan2(p.Dot(dir), p.Dot(e.V0.Vector)*dir.Norm())
+}
+
+// edgeEntryParameter returns the parameter (as in edgeParameter) of the
+// point where the edge enters the given cell, and reports whether the edge
+// intersects the cell. The cell is padded by the error of the clipping
+// functions, so edges that pass very close to the cell are included.
+func edgeEntryParameter(e Edge, id CellID) (float64, bool) {
+	face := id.Face()
+	aUV, bUV, ok := ClipToPaddedFace(e.V0, e.V1, face, faceClipErrorUVCoord)
+	if !ok {
+		return 0, false
+	}
+	bound := CellFromCellID(id).BoundUV().ExpandedByMargin(faceClipErrorUVCoord + edgeClipErrorUVCoord)
+	entry, _, ok := ClipEdge(aUV, bUV, bound)
+	if !ok {
+		return 0, false
+	}
+	return edgeParameter(e, Point{faceUVToXYZ(face, entry.X, entry.Y).Normalize()}), true
+}
+
+// edgeListShape is a Shape of dimension 1 whose edges are not connected. It
+// is used to index the edges of a WindingOperation.
+type edgeListShape []Edge
+
+func (s *edgeListShape) NumEdges() int                      { return len(*s) }
+func (s *edgeListShape) Edge(i int) Edge                    { return (*s)[i] }
+func (s *edgeListShape) ReferencePoint() ReferencePoint     { return OriginReferencePoint(false) }
+func (s *edgeListShape) NumChains() int                     { return len(*s) }
+func (s *edgeListShape) Chain(chainID int) Chain            { return Chain{chainID, 1} }
+func (s *edgeListShape) ChainEdge(chainID, offset int) Edge { return (*s)[chainID] }
+func (s *edgeListShape) ChainPosition(edgeID int) ChainPosition {
+	return ChainPosition{edgeID, 0}
+}
+func (s *edgeListShape) IsEmpty() bool     { return defaultShapeIsEmpty(s) }
+func (s *edgeListShape) IsFull() bool      { return defaultShapeIsFull(s) }
+func (s *edgeListShape) Dimension() int    { return 1 }
+func (s *edgeListShape) typeTag() typeTag  { return typeTagNone }
+func (s *edgeListShape) privateInterface() {}
+
+// windingEdge is an undirected edge of a windingGraph, where net is the
+// number of input edges from a to b minus the number from b to a.
+type windingEdge struct {
+	a, b Point
+	net  int
+}
+
+// windingRay is an edge leaving a vertex of a windingGraph.
+type windingRay struct {
+	to  Point
+	net int
+}
+
+// windingVertex is a vertex of a windingGraph, with its edges sorted in CCW
+// order around it. Crossing the ray to its left, from the sector before it to
+// the sector after it, adds its net count to the winding number.
+type windingVertex struct {
+	rays []windingRay
+	// sectors[i] is the winding number between rays i and i+1.
+	sectors []int
+	known   bool
+}
+
+// windingGraph is the planar graph formed by a set of edges that do not cross
+// each other, with the winding number of each region around each vertex.
+type windingGraph struct {
+	edges    []windingEdge
+	vertices map[Point]*windingVertex
+	// order lists the vertices in the order they were first seen, so that
+	// results do not depend on map iteration order.
+	order []Point
+}
+
+// newWindingGraph returns the graph of the given edges. Edges between the
+// same pair of vertices are merged, and dropped if they cancel out.
+func newWindingGraph(edges []Edge) *windingGraph {
+	g := &windingGraph{vertices: make(map[Point]*windingVertex)}
+	type key struct{ a, b Point }
+	net := make(map[key]int)
+	var keys []key
+	for _, e := range edges {
+		k, n := key{e.V0, e.V1}, 1
+		if e.V1.Cmp(e.V0.Vector) < 0 {
+			k, n = key{e.V1, e.V0}, -1
+		}
+		if _, ok := net[k]; !ok {
+			keys = append(keys, k)
+		}
+		net[k] += n
+	}
+
+	for _, k := range keys {
+		if net[k] == 0 {
+			continue
+		}
+		g.edges = append(g.edges, windingEdge{k.a, k.b, net[k]})
+		g.vertex(k.a).rays = append(g.vertex(k.a).rays, windingRay{k.b, net[k]})
+		g.vertex(k.b).rays = append(g.vertex(k.b).rays, windingRay{k.a, -net[k]})
+	}
+	for _, p := range g.order {
+		rays := g.vertices[p].rays
+		first := rays[0].to
+		sort.Slice(rays, func(i, j int) bool {
+			a, b := rays[i].to, rays[j].to
+			return a != b && (a == first || (b != first && OrderedCCW(first, a, b, p)))
+		})
+	}
+	return g
+}
+
+// vertex returns the vertex at the given point, adding it if necessary.
+func (g *windingGraph) vertex(p Point) *windingVertex {
+	v, ok := g.vertices[p]
+	if !ok {
+		v = &windingVertex{}
+		g.vertices[p] = v
+		g.order = append(g.order, p)
+	}
+	return v
+}
+
+// computeWindings sets the winding number of every sector around every vertex.
+func (g *windingGraph) computeWindings(ref Point, refWinding int) error {
+	if _, ok := g.vertices[ref]; ok {
+		return fmt.Errorf("reference point %v is a vertex of the snapped loops", ref)
+	}
+	antipode := Point{ref.Mul(-1)}
+	for _, p := range g.order {
+		if g.vertices[p].known || p == antipode {
+			continue
+		}
+		g.seed(p, ref, refWinding)
+		g.propagate(p)
+	}
+	return nil
+}
+
+// seed sets the winding numbers around the vertex p, by counting the edges
+// that cross the edge from the reference point to p.
+func (g *windingGraph) seed(p, ref Point, refWinding int) {
+	winding := refWinding
+	for _, e := range g.edges {
+		if CrossingSign(ref, p, e.a, e.b) != Cross {
+			continue
+		}
+		// Crossing from the right side of the edge to its left side adds to
+		// the winding number.
+		if RobustSign(e.a, e.b, ref) == Clockwise {
+			winding += e.net
+		} else {
+			winding -= e.net
+		}
+	}
+
+	// The winding number applies to the sector around p that faces ref.
+	v := g.vertices[p]
+	n := len(v.rays)
+	for i := range v.rays {
+		if n == 1 || OrderedCCW(v.rays[i].to, ref, v.rays[(i+1)%n].to, p) {
+			v.setSectors(i, winding)
+			return
+		}
+	}
+}
+
+// propagate sets the winding numbers around every vertex connected to p,
+// whose winding numbers must already be known.
+func (g *windingGraph) propagate(p Point) {
+	queue := []Point{p}
+	for len(queue) > 0 {
+		p, queue = queue[0], queue[1:]
+		v := g.vertices[p]
+		for i, ray := range v.rays {
+			u := g.vertices[ray.to]
+			if u.known {
+				continue
+			}
+			// The left side of the ray from p is the right side of the ray
+			// back to p, which is the sector before it.
+			k := u.rayTo(p)
+			u.setSectors((k+len(u.rays)-1)%len(u.rays), v.sectors[i])
+			queue = append(queue, ray.to)
+		}
+	}
+}
+
+// setSectors sets the winding numbers of all the sectors around the vertex,
+// given the winding number of sector i.
+func (v *windingVertex) setSectors(i, winding int) {
+	n := len(v.rays)
+	v.sectors = make([]int, n)
+	v.sectors[i] = winding
+	for k := 1; k < n; k++ {
+		j := (i + k) % n
+		v.sectors[j] = v.sectors[(j+n-1)%n] + v.rays[j].net
+	}
+	v.known = true
+}
+
+// rayTo returns the index of the ray from this vertex to p.
+func (v *windingVertex) rayTo(p Point) int {
+	for i, ray := range v.rays {
+		if ray.to == p {
+			return i
+		}
+	}
+	panic("no ray to the given point")
+}
+
+// isBoundary reports whether ray i of the vertex is an edge of the result,
+// directed so that the selected region is on its left.
+func (v *windingVertex) isBoundary(i int, rule WindingRule) bool {
+	n := len(v.rays)
+	return rule.matches(v.sectors[i]) && !rule.matches(v.sectors[(i+n-1)%n])
+}
+
+// boundaryLoops returns the loops that bound the region selected by the rule,
+// oriented with the region on their left.
+func (g *windingGraph) boundaryLoops(rule WindingRule) []*Loop {
+	type edge struct{ a, b Point }
+	used := make(map[edge]bool)
+
+	var loops []*Loop
+	for _, start := range g.order {
+		for i, ray := range g.vertices[start].rays {
+			if !g.vertices[start].isBoundary(i, rule) || used[edge{start, ray.to}] {
+				continue
+			}
+			// Follow the boundary, turning at each vertex to the next
+			// boundary edge clockwise from the one we arrived on. This keeps
+			// the selected sectors around a vertex in separate loops.
+			var vertices []Point
+			p, j := start, i
+			for !used[edge{p, g.vertices[p].rays[j].to}] {
+				v := g.vertices[p]
+				next := v.rays[j].to
+				used[edge{p, next}] = true
+				vertices = append(vertices, p)
+
+				u := g.vertices[next]
+				k := u.rayTo(p)
+				for {
+					k = (k + len(u.rays) - 1) % len(u.rays)
+					if u.isBoundary(k, rule) {
+						break
+					}
+				}
+				p, j = next, k
+			}
+			for _, loop := range splitRepeatedVertices(vertices) {
+				loops = append(loops, LoopFromPoints(loop))
+			}
+		}
+	}
+	return loops
+}
+
+// splitRepeatedVertices splits the closed chain of vertices wherever it comes
+// back to a vertex it has already visited, so that no vertex appears twice in
+// any of the resulting loops.
+func splitRepeatedVertices(vertices []Point) [][]Point {
+	var loops [][]Point
+	var stack []Point
+	index := make(map[Point]int)
+	for _, v := range vertices {
+		i, ok := index[v]
+		if !ok {
+			index[v] = len(stack)
+			stack = append(stack, v)
+			continue
+		}
+		loops = append(loops, append([]Point(nil), stack[i:]...))
+		for _, u := range stack[i+1:] {
+			delete(index, u)
+		}
+		stack = stack[:i+1]
+	}
+	return append(loops, stack)
+}
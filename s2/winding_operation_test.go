@@ -0,0 +1,337 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s2
+
+import (
+	"testing"
+
+	"github.com/rubenpoppe/geo/s1"
+)
+
+// bruteForceWinding returns the winding number of p with respect to the
+// given simple loops, where ref has the winding number refWinding.
+func bruteForceWinding(loops []*Loop, ref Point, refWinding int, p Point) int {
+	winding := refWinding
+	for _, l := range loops {
+		if l.ContainsPoint(p) {
+			winding++
+		}
+		if l.ContainsPoint(ref) {
+			winding--
+		}
+	}
+	return winding
+}
+
+func TestWindingOperation(t *testing.T) {
+	const (
+		square1  = "0:0, 0:2, 2:2, 2:0"
+		square2  = "1:1, 1:3, 3:3, 3:1"
+		square2R = "3:1, 3:3, 1:3, 1:1"
+		inner    = "0.5:0.5, 0.5:1.5, 1.5:1.5, 1.5:0.5"
+		innerR   = "0.5:0.5, 1.5:0.5, 1.5:1.5, 0.5:1.5"
+		// Two triangles with opposite orientations that touch at 1:1.
+		bowtie = "0:0, 2:2, 2:0, 0:2"
+	)
+	ref := parsePoint("-10:-10")
+	inSquare1 := parsePoint("0.5:0.2")
+	inBoth := parsePoint("1.5:1.5")
+	inSquare2 := parsePoint("2.5:2.5")
+	inInner := parsePoint("1:1.1")
+
+	tests := []struct {
+		label string
+		loops []string
+		rule  WindingRule
+		// atLeast, if non-zero, uses BuildAtLeast instead of rule.
+		atLeast int
+		in      []Point
+		out     []Point
+	}{
+		{
+			label: "union",
+			loops: []string{square1, square2},
+			rule:  WindingPositive,
+			in:    []Point{inSquare1, inBoth, inSquare2},
+			out:   []Point{ref},
+		},
+		{
+			label:   "intersection",
+			loops:   []string{square1, square2},
+			atLeast: 2,
+			in:      []Point{inBoth},
+			out:     []Point{ref, inSquare1, inSquare2},
+		},
+		{
+			label: "symmetric difference",
+			loops: []string{square1, square2},
+			rule:  WindingOdd,
+			in:    []Point{inSquare1, inSquare2},
+			out:   []Point{ref, inBoth},
+		},
+		{
+			label: "inconsistent orientations",
+			loops: []string{square1, square2R},
+			rule:  WindingNonZero,
+			in:    []Point{inSquare1, inSquare2},
+			out:   []Point{ref, inBoth},
+		},
+		{
+			label: "negative",
+			loops: []string{square1, square2R},
+			rule:  WindingNegative,
+			in:    []Point{inSquare2},
+			out:   []Point{ref, inSquare1, inBoth},
+		},
+		{
+			label: "hole",
+			loops: []string{square1, innerR},
+			rule:  WindingPositive,
+			in:    []Point{inSquare1},
+			out:   []Point{ref, inInner},
+		},
+		{
+			label:   "nested shells",
+			loops:   []string{square1, inner},
+			atLeast: 2,
+			in:      []Point{inInner},
+			out:     []Point{ref, inSquare1},
+		},
+		{
+			label: "bowtie non-zero",
+			loops: []string{bowtie},
+			rule:  WindingNonZero,
+			in:    []Point{parsePoint("0.2:1"), parsePoint("1.8:1")},
+			out:   []Point{ref, parsePoint("1:0.2"), parsePoint("1:1.8")},
+		},
+		{
+			label: "bowtie positive",
+			loops: []string{bowtie},
+			rule:  WindingPositive,
+			in:    []Point{parsePoint("1.8:1")},
+			out:   []Point{ref, parsePoint("0.2:1"), parsePoint("1:0.2")},
+		},
+		{
+			label: "duplicate edges cancel",
+			loops: []string{square1, "2:0, 2:2, 0:2, 0:0"},
+			rule:  WindingNonZero,
+			out:   []Point{ref, inSquare1, inBoth},
+		},
+	}
+
+	for _, test := range tests {
+		op := NewWindingOperation()
+		for _, l := range test.loops {
+			op.AddShape(makeLaxPolygon(l))
+		}
+		var p *Polygon
+		var err error
+		if test.atLeast != 0 {
+			p, err = op.BuildAtLeast(ref, 0, test.atLeast)
+		} else {
+			p, err = op.Build(ref, 0, test.rule)
+		}
+		if err != nil {
+			t.Errorf("%s: Build() returned error: %v", test.label, err)
+			continue
+		}
+		if err := p.Validate(); err != nil {
+			t.Errorf("%s: Build().Validate() = %v", test.label, err)
+		}
+		for _, pt := range test.in {
+			if !p.ContainsPoint(pt) {
+				t.Errorf("%s: Build().ContainsPoint(%v) = false, want true", test.label, LatLngFromPoint(pt))
+			}
+		}
+		for _, pt := range test.out {
+			if p.ContainsPoint(pt) {
+				t.Errorf("%s: Build().ContainsPoint(%v) = true, want false", test.label, LatLngFromPoint(pt))
+			}
+		}
+	}
+}
+
+func TestWindingOperationEmptyAndFull(t *testing.T) {
+	ref := parsePoint("0:0")
+	op := NewWindingOperation()
+	if p, err := op.Build(ref, 0, WindingPositive); err != nil || !p.IsEmpty() {
+		t.Errorf("Build() with no loops and winding 0 = %v, %v, want empty polygon", p, err)
+	}
+	if p, err := op.Build(ref, 1, WindingPositive); err != nil || !p.IsFull() {
+		t.Errorf("Build() with no loops and winding 1 = %v, %v, want full polygon", p, err)
+	}
+
+	// The empty and full loops have no edges.
+	op.AddLoop(EmptyLoop())
+	op.AddLoop(FullLoop())
+	if p, err := op.Build(ref, 0, WindingPositive); err != nil || !p.IsEmpty() {
+		t.Errorf("Build() with empty and full loops = %v, %v, want empty polygon", p, err)
+	}
+
+	// A loop and its reverse cancel out.
+	op.AddShape(makeLaxPolygon("0:1, 0:2, 1:1; 1:1, 0:2, 0:1"))
+	if p, err := op.Build(ref, 0, WindingNonZero); err != nil || !p.IsEmpty() {
+		t.Errorf("Build() with cancelling loops = %v, %v, want empty polygon", p, err)
+	}
+
+	op.AddShape(makeLaxPolygon("1:1, 2:2, 1:2"))
+	if _, err := op.Build(cellIDFromPoint(parsePoint("1:1")).Point(), 0, WindingPositive); err == nil {
+		t.Errorf("Build() with a reference point at a vertex succeeded, want error")
+	}
+}
+
+func TestWindingOperationRandomLoops(t *testing.T) {
+	for iter := 0; iter < 20; iter++ {
+		center := randomPoint()
+		var loops []*Loop
+		op := NewWindingOperation()
+		for i := 0; i < 2+randomUniformInt(6); i++ {
+			c := samplePointFromCap(CapFromCenterAngle(center, 0.1))
+			l := RegularLoop(c, s1.Angle(0.02+0.1*randomFloat64()), 3+randomUniformInt(20))
+			if oneIn(2) {
+				l.Invert()
+			}
+			loops = append(loops, l)
+			op.AddLoop(l)
+		}
+		ref := Point{center.Mul(-1)}
+		rules := []WindingRule{WindingPositive, WindingNegative, WindingNonZero, WindingOdd}
+		rule := rules[randomUniformInt(len(rules))]
+		p, err := op.Build(ref, 0, rule)
+		if err != nil {
+			t.Fatalf("Build() returned error: %v", err)
+		}
+		if err := p.Validate(); err != nil {
+			t.Errorf("Build(%v).Validate() = %v", rule, err)
+		}
+		for i := 0; i < 100; i++ {
+			pt := samplePointFromCap(CapFromCenterAngle(center, 0.25))
+			want := rule.matches(bruteForceWinding(loops, ref, 0, pt))
+			if got := p.ContainsPoint(pt); got != want {
+				t.Errorf("Build(%v).ContainsPoint(%v) = %t, want %t", rule, pt, got, want)
+			}
+		}
+	}
+}
+
+func TestWindingOperationSelfIntersectingLoops(t *testing.T) {
+	// The winding number of p is found by counting the edges that cross the
+	// edge from ref to p, with their orientation.
+	crossingWinding := func(loops [][]Point, ref Point, refWinding int, p Point) int {
+		winding := refWinding
+		for _, loop := range loops {
+			for i, a := range loop {
+				b := loop[(i+1)%len(loop)]
+				if CrossingSign(ref, p, a, b) != Cross {
+					continue
+				}
+				if RobustSign(a, b, ref) == Clockwise {
+					winding++
+				} else {
+					winding--
+				}
+			}
+		}
+		return winding
+	}
+
+	for iter := 0; iter < 20; iter++ {
+		c := CapFromCenterAngle(randomPoint(), 0.1)
+		var loops [][]Point
+		op := NewWindingOperation()
+		for i := 0; i < 1+randomUniformInt(3); i++ {
+			loop := make([]Point, 3+randomUniformInt(10))
+			for j := range loop {
+				loop[j] = samplePointFromCap(c)
+			}
+			loops = append(loops, loop)
+			op.AddShape(LaxPolygonFromPoints([][]Point{loop}))
+		}
+		ref := Point{c.Center().Mul(-1)}
+		refWinding := randomUniformInt(3) - 1
+		k := 1 + randomUniformInt(3)
+		p, err := op.BuildAtLeast(ref, refWinding, k)
+		if err != nil {
+			t.Fatalf("BuildAtLeast() returned error: %v", err)
+		}
+		if err := p.Validate(); err != nil {
+			t.Errorf("BuildAtLeast(%d).Validate() = %v", k, err)
+		}
+		for i := 0; i < 100; i++ {
+			pt := samplePointFromCap(c)
+			want := crossingWinding(loops, ref, refWinding, pt) >= k
+			if got := p.ContainsPoint(pt); got != want {
+				t.Errorf("BuildAtLeast(%d).ContainsPoint(%v) = %t, want %t", k, pt, got, want)
+			}
+		}
+	}
+}
+
+func TestWindingOperationSnapRounding(t *testing.T) {
+	// Snap rounding at a coarse level must not create crossing edges, and
+	// must keep the edges close to the input edges.
+	for iter := 0; iter < 50; iter++ {
+		c := CapFromCenterAngle(randomPoint(), s1.Angle(0.01+0.1*randomFloat64()))
+		op := NewWindingOperation()
+		op.SnapLevel = 8 + randomUniformInt(9)
+		var input []Edge
+		for i := 0; i < 1+randomUniformInt(3); i++ {
+			loop := make([]Point, 3+randomUniformInt(20))
+			for j := range loop {
+				loop[j] = samplePointFromCap(c)
+			}
+			for j, v := range loop {
+				input = append(input, Edge{v, loop[(j+1)%len(loop)]})
+			}
+			op.AddShape(LaxPolygonFromPoints([][]Point{loop}))
+		}
+
+		edges := op.snappedEdges()
+		index := NewShapeIndex()
+		shape := edgeListShape(edges)
+		index.Add(&shape)
+		VisitCrossingEdgePairs(index, CrossingTypeInterior, func(a, b ShapeEdge, _ bool) bool {
+			t.Errorf("level %d: snapped edges %v and %v cross", op.SnapLevel, a.Edge, b.Edge)
+			return false
+		})
+
+		snapRadius := s1.Angle(MaxDiagMetric.Value(op.SnapLevel) / 2)
+		for _, e := range edges {
+			if id := cellIDFromPoint(e.V0).Parent(op.SnapLevel); id.Point() != e.V0 {
+				t.Errorf("level %d: vertex %v is not a cell center", op.SnapLevel, e.V0)
+			}
+			for k := 0; k <= 4; k++ {
+				p := Interpolate(float64(k)/4, e.V0, e.V1)
+				dist := s1.InfAngle()
+				for _, f := range input {
+					if d := DistanceFromSegment(p, f.V0, f.V1); d < dist {
+						dist = d
+					}
+				}
+				if dist > 1.1*snapRadius {
+					t.Errorf("level %d: snapped edge %v is %v from the input, want <= %v", op.SnapLevel, e, dist, 1.1*snapRadius)
+				}
+			}
+		}
+
+		p, err := op.BuildAtLeast(Point{c.Center().Mul(-1)}, 0, 1+randomUniformInt(2))
+		if err != nil {
+			t.Fatalf("BuildAtLeast() returned error: %v", err)
+		}
+		if err := p.Validate(); err != nil {
+			t.Errorf("level %d: BuildAtLeast().Validate() = %v", op.SnapLevel, err)
+		}
+	}
+}
@@ -0,0 +1,412 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wkb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/rubenpoppe/geo/s2"
+	"github.com/rubenpoppe/geo/s2/internal/ogc"
+)
+
+// typeNames are the names of the geometry types, indexed by type code.
+var typeNames = [...]string{
+	typePoint:           "Point",
+	typeLineString:      "LineString",
+	typePolygon:         "Polygon",
+	typeMultiPoint:      "MultiPoint",
+	typeMultiLineString: "MultiLineString",
+	typeMultiPolygon:    "MultiPolygon",
+}
+
+// geometry is a decoded WKB geometry. Points holds the positions of a Point,
+// MultiPoint or LineString, lines the line strings of a MultiLineString or the
+// rings of a Polygon, and polygons the rings of each polygon of a
+// MultiPolygon. All of them are empty for an empty geometry.
+type geometry struct {
+	typ      uint32
+	points   []ogc.Position
+	lines    [][]ogc.Position
+	polygons [][][]ogc.Position
+}
+
+// UnmarshalPoint decodes a Point.
+func UnmarshalPoint(data []byte) (s2.Point, error) {
+	g, err := decode(data, typePoint)
+	if err != nil {
+		return s2.Point{}, err
+	}
+	if len(g.points) == 0 {
+		return s2.Point{}, fmt.Errorf("wkb: an empty Point cannot be converted to a Point")
+	}
+	return ogc.PointFromPosition(g.points[0]), nil
+}
+
+// UnmarshalPointVector decodes a Point or MultiPoint.
+func UnmarshalPointVector(data []byte) (s2.PointVector, error) {
+	g, err := decode(data, typePoint, typeMultiPoint)
+	if err != nil {
+		return nil, err
+	}
+	points := make(s2.PointVector, len(g.points))
+	for i, pos := range g.points {
+		points[i] = ogc.PointFromPosition(pos)
+	}
+	return points, nil
+}
+
+// UnmarshalPolyline decodes a LineString.
+func UnmarshalPolyline(data []byte) (*s2.Polyline, error) {
+	g, err := decode(data, typeLineString)
+	if err != nil {
+		return nil, err
+	}
+	l, err := ogc.PolylineFromPositions(g.points)
+	if err != nil {
+		return nil, fmt.Errorf("wkb: %v", err)
+	}
+	return l, nil
+}
+
+// UnmarshalPolylines decodes a LineString or MultiLineString.
+func UnmarshalPolylines(data []byte) ([]*s2.Polyline, error) {
+	g, err := decode(data, typeLineString, typeMultiLineString)
+	if err != nil {
+		return nil, err
+	}
+	if g.typ == typeLineString {
+		l, err := ogc.PolylineFromPositions(g.points)
+		if err != nil {
+			return nil, fmt.Errorf("wkb: %v", err)
+		}
+		return []*s2.Polyline{l}, nil
+	}
+	lines := make([]*s2.Polyline, len(g.lines))
+	for i, line := range g.lines {
+		if lines[i], err = ogc.PolylineFromPositions(line); err != nil {
+			return nil, fmt.Errorf("wkb: line string %d: %v", i, err)
+		}
+	}
+	return lines, nil
+}
+
+// UnmarshalPolygon decodes a Polygon or MultiPolygon. The polygons of a
+// MultiPolygon must not overlap.
+func UnmarshalPolygon(data []byte) (*s2.Polygon, error) {
+	g, err := decode(data, typePolygon, typeMultiPolygon)
+	if err != nil {
+		return nil, err
+	}
+	var loops []*s2.Loop
+	if g.typ == typePolygon {
+		for i, ring := range g.lines {
+			loop, err := ogc.LoopFromRing(ring, i > 0)
+			if err != nil {
+				return nil, fmt.Errorf("wkb: ring %d: %v", i, err)
+			}
+			loops = append(loops, loop)
+		}
+	} else {
+		for i, rings := range g.polygons {
+			for k, ring := range rings {
+				loop, err := ogc.LoopFromRing(ring, k > 0)
+				if err != nil {
+					return nil, fmt.Errorf("wkb: polygon %d, ring %d: %v", i, k, err)
+				}
+				loops = append(loops, loop)
+			}
+		}
+	}
+	p := s2.PolygonFromOrientedLoops(loops)
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("wkb: invalid %s: %v", typeNames[g.typ], err)
+	}
+	return p, nil
+}
+
+// UnmarshalShapes decodes any geometry into shapes without checking that they
+// are valid, so that geometry with degenerate or self-intersecting rings or
+// overlapping polygons can still be read, for example to be repaired with
+// s2.RepairPolygon. A Point or MultiPoint gives a single PointVector, each line
+// string of a LineString or MultiLineString gives a LaxPolyline, and all the
+// rings of a Polygon or MultiPolygon give a single LaxPolygon. Rings are taken
+// as written: exterior rings must be counterclockwise and holes clockwise.
+func UnmarshalShapes(data []byte) ([]s2.Shape, error) {
+	g, err := decode(data, typePoint, typeLineString, typePolygon, typeMultiPoint, typeMultiLineString, typeMultiPolygon)
+	if err != nil {
+		return nil, err
+	}
+	switch g.typ {
+	case typePoint, typeMultiPoint:
+		points := make(s2.PointVector, len(g.points))
+		for i, pos := range g.points {
+			points[i] = ogc.PointFromPosition(pos)
+		}
+		return []s2.Shape{&points}, nil
+	case typeLineString:
+		return []s2.Shape{ogc.LaxPolylineFromPositions(g.points)}, nil
+	case typeMultiLineString:
+		shapes := make([]s2.Shape, len(g.lines))
+		for i, line := range g.lines {
+			shapes[i] = ogc.LaxPolylineFromPositions(line)
+		}
+		return shapes, nil
+	}
+	rings := g.lines
+	if g.typ == typeMultiPolygon {
+		rings = nil
+		for _, polygon := range g.polygons {
+			rings = append(rings, polygon...)
+		}
+	}
+	polygon, err := ogc.LaxPolygonFromRings(rings)
+	if err != nil {
+		return nil, fmt.Errorf("wkb: %v", err)
+	}
+	return []s2.Shape{polygon}, nil
+}
+
+// decoder reads WKB from a byte slice. Like the s2 decoder, the first error
+// is kept and all later reads return zero values.
+type decoder struct {
+	data  []byte
+	off   int
+	order binary.ByteOrder
+	err   error
+}
+
+// decode decodes data, which must be a geometry of one of the given types.
+func decode(data []byte, types ...uint32) (*geometry, error) {
+	d := &decoder{data: data}
+	g := d.geometry()
+	if d.err == nil && d.off < len(d.data) {
+		d.errorf("%d bytes after the geometry", len(d.data)-d.off)
+	}
+	if d.err != nil {
+		return nil, fmt.Errorf("wkb: %v", d.err)
+	}
+	for _, t := range types {
+		if g.typ == t {
+			return g, nil
+		}
+	}
+	var want string
+	for i, t := range types {
+		if i > 0 {
+			want += " or "
+		}
+		want += typeNames[t]
+	}
+	return nil, fmt.Errorf("wkb: geometry type is %s, want %s", typeNames[g.typ], want)
+}
+
+// errorf records an error at the current offset, unless there already is one.
+func (d *decoder) errorf(format string, args ...interface{}) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%s at offset %d", fmt.Sprintf(format, args...), d.off)
+	}
+}
+
+// read returns the next n bytes, or nil if there are fewer left.
+func (d *decoder) read(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if len(d.data)-d.off < n {
+		d.errorf("unexpected end of data")
+		return nil
+	}
+	b := d.data[d.off : d.off+n]
+	d.off += n
+	return b
+}
+
+func (d *decoder) uint32() uint32 {
+	b := d.read(4)
+	if b == nil {
+		return 0
+	}
+	return d.order.Uint32(b)
+}
+
+func (d *decoder) float64() float64 {
+	b := d.read(8)
+	if b == nil {
+		return 0
+	}
+	return math.Float64frombits(d.order.Uint64(b))
+}
+
+// count reads the number of elements of a list, each of which takes at least
+// size bytes, so that a corrupt count cannot cause a huge allocation.
+func (d *decoder) count(size int) int {
+	n := d.uint32()
+	if d.err == nil && uint64(n)*uint64(size) > uint64(len(d.data)-d.off) {
+		d.errorf("count %d exceeds the remaining data", n)
+		return 0
+	}
+	return int(n)
+}
+
+// header reads the byte order and the type of a geometry, and returns the
+// type and the number of ordinates of each position.
+func (d *decoder) header() (typ uint32, dims int) {
+	b := d.read(1)
+	if b == nil {
+		return 0, 0
+	}
+	switch b[0] {
+	case bigEndian:
+		d.order = binary.BigEndian
+	case littleEndian:
+		d.order = binary.LittleEndian
+	default:
+		d.off--
+		d.errorf("invalid byte order %d", b[0])
+		return 0, 0
+	}
+	typ = d.uint32()
+	if typ&ewkbSRID != 0 {
+		if srid := d.uint32(); srid != 0 && srid != SRID {
+			d.off -= 4
+			d.errorf("SRID %d is not supported, want %d", srid, SRID)
+			return 0, 0
+		}
+	}
+	dims = 2
+	if typ&ewkbZ != 0 {
+		dims++
+	}
+	if typ&ewkbM != 0 {
+		dims++
+	}
+	typ &^= ewkbZ | ewkbM | ewkbSRID
+	switch typ / 1000 {
+	case 1, 2:
+		dims++
+	case 3:
+		dims += 2
+	}
+	if base := typ % 1000; typ < 4000 && base >= typePoint && base <= typeMultiPolygon {
+		return base, dims
+	}
+	if d.err == nil {
+		d.off -= 4
+		d.errorf("geometry type %d is not supported", typ)
+	}
+	return 0, 0
+}
+
+// geometry reads a geometry.
+func (d *decoder) geometry() *geometry {
+	typ, dims := d.header()
+	g := &geometry{typ: typ}
+	switch typ {
+	case typePoint:
+		if pos, ok := d.position(dims); ok {
+			g.points = []ogc.Position{pos}
+		}
+	case typeLineString:
+		g.points = d.positions(dims)
+	case typePolygon:
+		g.lines = d.lines(dims)
+	case typeMultiPoint:
+		for i, n := 0, d.count(5); i < n && d.err == nil; i++ {
+			if dims := d.element(typePoint); d.err == nil {
+				if pos, ok := d.position(dims); ok {
+					g.points = append(g.points, pos)
+				}
+			}
+		}
+	case typeMultiLineString:
+		for i, n := 0, d.count(9); i < n && d.err == nil; i++ {
+			if dims := d.element(typeLineString); d.err == nil {
+				g.lines = append(g.lines, d.positions(dims))
+			}
+		}
+	case typeMultiPolygon:
+		for i, n := 0, d.count(9); i < n && d.err == nil; i++ {
+			if dims := d.element(typePolygon); d.err == nil {
+				g.polygons = append(g.polygons, d.lines(dims))
+			}
+		}
+	}
+	return g
+}
+
+// element reads the header of an element of a multi-geometry, which must have
+// the given type, and returns its number of ordinates.
+func (d *decoder) element(want uint32) int {
+	off := d.off
+	typ, dims := d.header()
+	if d.err == nil && typ != want {
+		d.off = off
+		d.errorf("found %s, want %s", typeNames[typ], typeNames[want])
+	}
+	return dims
+}
+
+// position reads a position with the given number of ordinates, of which the
+// first two are the longitude and latitude in degrees. It returns false for
+// an empty point, whose ordinates are NaN.
+func (d *decoder) position(dims int) (ogc.Position, bool) {
+	off := d.off
+	ords := make([]float64, dims)
+	for i := range ords {
+		ords[i] = d.float64()
+	}
+	if d.err != nil {
+		return ogc.Position{}, false
+	}
+	lng, lat := ords[0], ords[1]
+	if math.IsNaN(lng) && math.IsNaN(lat) {
+		return ogc.Position{}, false
+	}
+	switch {
+	case !(lng >= -180 && lng <= 180):
+		d.off = off
+		d.errorf("longitude %v is out of range [-180, 180]", lng)
+	case !(lat >= -90 && lat <= 90):
+		d.off = off
+		d.errorf("latitude %v is out of range [-90, 90]", lat)
+	}
+	return ogc.Position{lng, lat}, d.err == nil
+}
+
+// positions reads a list of positions.
+func (d *decoder) positions(dims int) []ogc.Position {
+	n := d.count(8 * dims)
+	positions := make([]ogc.Position, 0, n)
+	for i := 0; i < n && d.err == nil; i++ {
+		if pos, ok := d.position(dims); ok {
+			positions = append(positions, pos)
+		} else if d.err == nil {
+			d.errorf("empty position in a list")
+		}
+	}
+	return positions
+}
+
+// lines reads a list of lists of positions.
+func (d *decoder) lines(dims int) [][]ogc.Position {
+	n := d.count(4)
+	lines := make([][]ogc.Position, 0, n)
+	for i := 0; i < n && d.err == nil; i++ {
+		lines = append(lines, d.positions(dims))
+	}
+	return lines
+}
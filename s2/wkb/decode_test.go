@@ -0,0 +1,288 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wkb
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// Little-endian encodings of the coordinates used below.
+const (
+	hex0   = "0000000000000000"
+	hex10  = "0000000000002440"
+	hex90  = "0000000000805640"
+	hex181 = "0000000000A06640"
+	hexNaN = "000000000000F87F"
+)
+
+func TestUnmarshalPoint(t *testing.T) {
+	tests := []struct {
+		name string
+		hex  string
+	}{
+		{"WKB", "01 01000000" + hex10 + hex90},
+		{"big endian", "00 00000001 4024000000000000 4056800000000000"},
+		{"EWKB", "01 01000020 E6100000" + hex10 + hex90},
+		{"EWKB with SRID 0", "01 01000020 00000000" + hex10 + hex90},
+		{"ISO Z", "01 E9030000" + hex10 + hex90 + hex0},
+		{"ISO M", "01 D1070000" + hex10 + hex90 + hex0},
+		{"ISO ZM", "01 B90B0000" + hex10 + hex90 + hex0 + hex0},
+		{"EWKB Z", "01 010000A0 E6100000" + hex10 + hex90 + hex0},
+		{"EWKB ZM", "01 010000C0" + hex10 + hex90 + hex0 + hex0},
+	}
+	want := s2.PointFromLatLng(s2.LatLngFromDegrees(90, 10))
+	for _, test := range tests {
+		got, err := UnmarshalPoint(mustDecodeHex(t, test.hex))
+		if err != nil {
+			t.Errorf("%s: UnmarshalPoint failed: %v", test.name, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: UnmarshalPoint = %v, want %v", test.name, got, want)
+		}
+	}
+}
+
+func TestUnmarshalPointVector(t *testing.T) {
+	tests := []struct {
+		name string
+		hex  string
+		want s2.PointVector
+	}{
+		{"empty", "01 04000000 00000000", s2.PointVector{}},
+		{"point", "01 01000000" + hex10 + hex90, s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(90, 10))}},
+		{
+			"mixed byte order",
+			"01 04000000 02000000" +
+				"01 01000000" + hex10 + hex90 +
+				"00 00000001 0000000000000000 4024000000000000",
+			s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(90, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 0))},
+		},
+		{
+			"empty element",
+			"01 04000000 02000000" +
+				"01 01000000" + hexNaN + hexNaN +
+				"01 01000000" + hex10 + hex90,
+			s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(90, 10))},
+		},
+	}
+	for _, test := range tests {
+		got, err := UnmarshalPointVector(mustDecodeHex(t, test.hex))
+		if err != nil {
+			t.Errorf("%s: UnmarshalPointVector failed: %v", test.name, err)
+			continue
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("%s: UnmarshalPointVector = %v, want %v", test.name, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%s: UnmarshalPointVector[%d] = %v, want %v", test.name, i, got[i], test.want[i])
+			}
+		}
+	}
+}
+
+func TestUnmarshalPolyline(t *testing.T) {
+	data := mustDecodeHex(t, "01 02000000 02000000"+hex0+hex0+hex10+hex10)
+	got, err := UnmarshalPolyline(data)
+	if err != nil {
+		t.Fatalf("UnmarshalPolyline failed: %v", err)
+	}
+	want := s2.PolylineFromLatLngs([]s2.LatLng{s2.LatLngFromDegrees(0, 0), s2.LatLngFromDegrees(10, 10)})
+	if !got.Equal(want) {
+		t.Errorf("UnmarshalPolyline = %v, want %v", got, want)
+	}
+
+	lines, err := UnmarshalPolylines(data)
+	if err != nil || len(lines) != 1 || !lines[0].Equal(want) {
+		t.Errorf("UnmarshalPolylines(LineString) = %v, %v, want [%v]", lines, err, want)
+	}
+}
+
+func TestUnmarshalPolygonOrientation(t *testing.T) {
+	// Clockwise and counterclockwise rings give the same polygon.
+	ccw := "01 03000000 01000000 05000000" + hex0 + hex0 + hex10 + hex0 + hex10 + hex10 + hex0 + hex10 + hex0 + hex0
+	cw := "01 03000000 01000000 05000000" + hex0 + hex0 + hex0 + hex10 + hex10 + hex10 + hex10 + hex0 + hex0 + hex0
+	want := s2.PolygonFromLoops([]*s2.Loop{s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 0))})})
+	for _, s := range []string{ccw, cw} {
+		got, err := UnmarshalPolygon(mustDecodeHex(t, s))
+		if err != nil {
+			t.Errorf("UnmarshalPolygon(%s) failed: %v", s, err)
+			continue
+		}
+		if !polygonsApproxEqual(got, want) {
+			t.Errorf("UnmarshalPolygon(%s) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestUnmarshalShapes(t *testing.T) {
+	// A polygon whose ring crosses itself, which UnmarshalPolygon rejects.
+	bowtie := "01 03000000 01000000 05000000" + hex0 + hex0 + hex10 + hex10 + hex10 + hex0 + hex0 + hex10 + hex0 + hex0
+	if _, err := UnmarshalPolygon(mustDecodeHex(t, bowtie)); err == nil {
+		t.Errorf("UnmarshalPolygon of a ring that crosses itself succeeded")
+	}
+	shapes, err := UnmarshalShapes(mustDecodeHex(t, bowtie))
+	if err != nil || len(shapes) != 1 {
+		t.Fatalf("UnmarshalShapes of a ring that crosses itself = %v, %v, want 1 shape", shapes, err)
+	}
+	if _, ok := shapes[0].(*s2.LaxPolygon); !ok || shapes[0].NumEdges() != 4 {
+		t.Errorf("UnmarshalShapes of a ring that crosses itself = %T with %d edges, want a LaxPolygon with 4 edges", shapes[0], shapes[0].NumEdges())
+	}
+
+	// A MultiLineString with a degenerate line string.
+	lines := "01 05000000 02000000" +
+		"01 02000000 02000000" + hex0 + hex0 + hex0 + hex0 +
+		"01 02000000 03000000" + hex0 + hex0 + hex10 + hex10 + hex0 + hex0
+	shapes, err = UnmarshalShapes(mustDecodeHex(t, lines))
+	if err != nil || len(shapes) != 2 {
+		t.Fatalf("UnmarshalShapes(MultiLineString) = %v, %v, want 2 shapes", shapes, err)
+	}
+	for i, want := range []int{1, 2} {
+		if _, ok := shapes[i].(*s2.LaxPolyline); !ok || shapes[i].NumEdges() != want {
+			t.Errorf("UnmarshalShapes(MultiLineString)[%d] = %T with %d edges, want a LaxPolyline with %d edges", i, shapes[i], shapes[i].NumEdges(), want)
+		}
+	}
+
+	for _, s := range []string{
+		// A ring without positions.
+		"01 03000000 01000000 00000000",
+		// A ring that is not closed.
+		"01 03000000 01000000 03000000" + hex0 + hex0 + hex10 + hex0 + hex10 + hex10,
+	} {
+		if shapes, err := UnmarshalShapes(mustDecodeHex(t, s)); err == nil {
+			t.Errorf("UnmarshalShapes(%s) = %v, want an error", s, shapes)
+		}
+	}
+}
+
+func TestUnmarshalLargePolygon(t *testing.T) {
+	// A counterclockwise shell around everything but the poles and a strip
+	// near the antimeridian, which is larger than a hemisphere.
+	shell := s2.LoopFromPoints([]s2.Point{
+		s2.PointFromLatLng(s2.LatLngFromDegrees(-60, -170)), s2.PointFromLatLng(s2.LatLngFromDegrees(-60, -60)), s2.PointFromLatLng(s2.LatLngFromDegrees(-60, 60)), s2.PointFromLatLng(s2.LatLngFromDegrees(-60, 170)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(60, 170)), s2.PointFromLatLng(s2.LatLngFromDegrees(60, 60)), s2.PointFromLatLng(s2.LatLngFromDegrees(60, -60)), s2.PointFromLatLng(s2.LatLngFromDegrees(60, -170)),
+	})
+	want := s2.PolygonFromLoops([]*s2.Loop{shell})
+	data, err := MarshalPolygon(want)
+	if err != nil {
+		t.Fatalf("MarshalPolygon failed: %v", err)
+	}
+	got, err := UnmarshalPolygon(data)
+	if err != nil {
+		t.Fatalf("UnmarshalPolygon failed: %v", err)
+	}
+	if !polygonsApproxEqual(got, want) {
+		t.Errorf("UnmarshalPolygon = %v, want %v", got, want)
+	}
+	if area := got.Area() / (4 * math.Pi); area < 0.85 || area > 0.9 {
+		t.Errorf("UnmarshalPolygon covers %v of the sphere, want about 0.88", area)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		hex  string
+		f    func([]byte) error
+		want string
+	}{
+		{"no data", "", unmarshalPoint, "unexpected end of data"},
+		{"truncated", "01 01000000" + hex10, unmarshalPoint, "unexpected end of data"},
+		{"byte order", "02 01000000" + hex10 + hex90, unmarshalPoint, "invalid byte order 2 at offset 0"},
+		{"geometry type", "01 07000000 00000000", unmarshalPoint, "geometry type 7 is not supported"},
+		{"dimension", "01 A10F0000" + hex10 + hex90, unmarshalPoint, "geometry type 4001 is not supported"},
+		{"SRID", "01 01000020 110F0000" + hex10 + hex90, unmarshalPoint, "SRID 3857 is not supported"},
+		{"trailing data", "01 01000000" + hex10 + hex90 + "00", unmarshalPoint, "1 bytes after the geometry"},
+		{"wrong type", "01 02000000 00000000", unmarshalPoint, "geometry type is LineString, want Point"},
+		{"empty point", "01 01000000" + hexNaN + hexNaN, unmarshalPoint, "empty Point"},
+		{"longitude", "01 01000000" + hex181 + hex0, unmarshalPoint, "longitude 181 is out of range"},
+		{"latitude", "01 01000000" + hex0 + hex181, unmarshalPoint, "latitude 181 is out of range"},
+		{"NaN", "01 01000000" + hex0 + hexNaN, unmarshalPoint, "latitude NaN is out of range"},
+		{"count", "01 04000000 FFFFFFFF", unmarshalPointVector, "count 4294967295 exceeds the remaining data"},
+		{
+			"element type",
+			"01 04000000 01000000 01 02000000 00000000",
+			unmarshalPointVector, "found LineString, want Point at offset 9",
+		},
+		{"short line string", "01 02000000 01000000" + hex0 + hex0, unmarshalPolyline, "want at least 2"},
+		{
+			"empty position",
+			"01 02000000 02000000" + hex0 + hex0 + hexNaN + hexNaN,
+			unmarshalPolyline, "empty position",
+		},
+		{
+			"short line string in multi",
+			"01 05000000 01000000 01 02000000 01000000" + hex0 + hex0,
+			unmarshalPolylines, "line string 0",
+		},
+		{
+			"open ring",
+			"01 03000000 01000000 04000000" + hex0 + hex0 + hex10 + hex0 + hex10 + hex10 + hex0 + hex10,
+			unmarshalPolygon, "ring 0: ring is not closed",
+		},
+		{
+			"self-intersecting ring",
+			"01 03000000 01000000 05000000" + hex0 + hex0 + hex10 + hex0 + hex0 + hex10 + hex10 + hex10 + hex0 + hex0,
+			unmarshalPolygon, "crosses edge",
+		},
+		{
+			"polygon in multi",
+			"01 06000000 01000000 01 03000000 01000000 03000000" + hex0 + hex0 + hex10 + hex0 + hex0 + hex0,
+			unmarshalPolygon, "polygon 0, ring 0: ring has 3 positions",
+		},
+	}
+	for _, test := range tests {
+		err := test.f(mustDecodeHex(t, test.hex))
+		if err == nil {
+			t.Errorf("%s: decoding succeeded, want error containing %q", test.name, test.want)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), "wkb: ") || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: decoding failed with %q, want error containing %q", test.name, err, test.want)
+		}
+	}
+}
+
+func unmarshalPoint(data []byte) error {
+	_, err := UnmarshalPoint(data)
+	return err
+}
+
+func unmarshalPointVector(data []byte) error {
+	_, err := UnmarshalPointVector(data)
+	return err
+}
+
+func unmarshalPolyline(data []byte) error {
+	_, err := UnmarshalPolyline(data)
+	return err
+}
+
+func unmarshalPolylines(data []byte) error {
+	_, err := UnmarshalPolylines(data)
+	return err
+}
+
+func unmarshalPolygon(data []byte) error {
+	_, err := UnmarshalPolygon(data)
+	return err
+}
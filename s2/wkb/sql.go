@@ -0,0 +1,132 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wkb
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// sqlEncoder writes the EWKB that the adapters pass to the database.
+var sqlEncoder = &Encoder{SRID: SRID}
+
+// Point adapts an s2.Point to database/sql. A NULL value scans as the zero
+// Point.
+type Point struct {
+	s2.Point
+}
+
+// Value implements driver.Valuer. The point is passed as hex-encoded EWKB
+// with SRID 4326, which PostGIS accepts as input for both geometry and
+// geography columns.
+func (p Point) Value() (driver.Value, error) {
+	return hex.EncodeToString(sqlEncoder.Point(p.Point)), nil
+}
+
+// Scan implements sql.Scanner for WKB or EWKB, either binary or hex-encoded
+// as returned by PostGIS.
+func (p *Point) Scan(src interface{}) error {
+	data, err := scanBytes(src)
+	if err != nil || data == nil {
+		p.Point = s2.Point{}
+		return err
+	}
+	p.Point, err = UnmarshalPoint(data)
+	return err
+}
+
+// Polyline adapts an *s2.Polyline to database/sql. A nil Polyline is NULL.
+type Polyline struct {
+	*s2.Polyline
+}
+
+// Value implements driver.Valuer. The polyline is passed as hex-encoded EWKB
+// with SRID 4326.
+func (l Polyline) Value() (driver.Value, error) {
+	if l.Polyline == nil {
+		return nil, nil
+	}
+	return hex.EncodeToString(sqlEncoder.Polyline(l.Polyline)), nil
+}
+
+// Scan implements sql.Scanner for WKB or EWKB, either binary or hex-encoded
+// as returned by PostGIS.
+func (l *Polyline) Scan(src interface{}) error {
+	data, err := scanBytes(src)
+	if err != nil || data == nil {
+		l.Polyline = nil
+		return err
+	}
+	l.Polyline, err = UnmarshalPolyline(data)
+	return err
+}
+
+// Polygon adapts an *s2.Polygon to database/sql. A nil Polygon is NULL.
+type Polygon struct {
+	*s2.Polygon
+}
+
+// Value implements driver.Valuer. The polygon is passed as hex-encoded EWKB
+// with SRID 4326.
+func (p Polygon) Value() (driver.Value, error) {
+	if p.Polygon == nil {
+		return nil, nil
+	}
+	data, err := sqlEncoder.Polygon(p.Polygon)
+	if err != nil {
+		return nil, err
+	}
+	return hex.EncodeToString(data), nil
+}
+
+// Scan implements sql.Scanner for WKB or EWKB, either binary or hex-encoded
+// as returned by PostGIS.
+func (p *Polygon) Scan(src interface{}) error {
+	data, err := scanBytes(src)
+	if err != nil || data == nil {
+		p.Polygon = nil
+		return err
+	}
+	p.Polygon, err = UnmarshalPolygon(data)
+	return err
+}
+
+// scanBytes returns the WKB in a value scanned from the database, or nil for
+// NULL. Binary WKB starts with a byte order marker of 0 or 1, while
+// hex-encoded WKB starts with the character '0'.
+func scanBytes(src interface{}) ([]byte, error) {
+	var data []byte
+	switch src := src.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return nil, fmt.Errorf("wkb: cannot scan %T", src)
+	}
+	if len(data) == 0 || data[0] != '0' {
+		return data, nil
+	}
+	b := make([]byte, hex.DecodedLen(len(data)))
+	if _, err := hex.Decode(b, data); err != nil {
+		return nil, fmt.Errorf("wkb: invalid hex: %v", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wkb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// The adapters must implement the database/sql interfaces.
+var (
+	_ driver.Valuer = Point{}
+	_ sql.Scanner   = (*Point)(nil)
+	_ driver.Valuer = Polyline{}
+	_ sql.Scanner   = (*Polyline)(nil)
+	_ driver.Valuer = Polygon{}
+	_ sql.Scanner   = (*Polygon)(nil)
+)
+
+func TestPointValue(t *testing.T) {
+	v, err := Point{s2.PointFromLatLng(s2.LatLngFromDegrees(90, 0))}.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	// This is the output of ST_AsHexEWKB('SRID=4326;POINT(0 90)') in
+	// lower case.
+	want := "0101000020e6100000" + hex0 + hex90
+	if v != strings.ToLower(want) {
+		t.Errorf("Value = %v, want %v", v, want)
+	}
+}
+
+func TestPointScan(t *testing.T) {
+	want := s2.PointFromLatLng(s2.LatLngFromDegrees(90, 10))
+	binary := mustDecodeHex(t, "01 01000020 E6100000"+hex10+hex90)
+	hex := "0101000020E6100000" + hex10 + hex90
+	for _, src := range []interface{}{binary, hex, []byte(hex), strings.ToLower(hex)} {
+		var p Point
+		if err := p.Scan(src); err != nil {
+			t.Errorf("Scan(%v) failed: %v", src, err)
+			continue
+		}
+		if p.Point != want {
+			t.Errorf("Scan(%v) = %v, want %v", src, p.Point, want)
+		}
+	}
+
+	p := Point{want}
+	if err := p.Scan(nil); err != nil || p.Point != (s2.Point{}) {
+		t.Errorf("Scan(nil) = %v, %v, want zero Point", p.Point, err)
+	}
+	for _, src := range []interface{}{42, "0X", "0102000000 00000000"} {
+		if err := p.Scan(src); err == nil {
+			t.Errorf("Scan(%v) succeeded, want error", src)
+		}
+	}
+}
+
+func TestPolylineValueScan(t *testing.T) {
+	l := s2.PolylineFromLatLngs([]s2.LatLng{s2.LatLngFromDegrees(1, 2), s2.LatLngFromDegrees(3, 4)})
+	v, err := Polyline{l}.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	var got Polyline
+	if err := got.Scan(v); err != nil {
+		t.Fatalf("Scan(%v) failed: %v", v, err)
+	}
+	if !got.ApproxEqual(l) {
+		t.Errorf("Scan(%v) = %v, want %v", v, got.Polyline, l)
+	}
+
+	if v, err := (Polyline{}).Value(); v != nil || err != nil {
+		t.Errorf("Polyline{}.Value() = %v, %v, want nil, nil", v, err)
+	}
+	if err := got.Scan(nil); err != nil || got.Polyline != nil {
+		t.Errorf("Scan(nil) = %v, %v, want nil Polyline", got.Polyline, err)
+	}
+}
+
+func TestPolygonValueScan(t *testing.T) {
+	p := s2.PolygonFromLoops([]*s2.Loop{
+		s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 0))}),
+		s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(2, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(2, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(4, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(4, 2))}),
+	})
+	v, err := Polygon{p}.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	var got Polygon
+	if err := got.Scan(v); err != nil {
+		t.Fatalf("Scan(%v) failed: %v", v, err)
+	}
+	if !polygonsApproxEqual(got.Polygon, p) {
+		t.Errorf("Scan(%v) = %v, want %v", v, got.Polygon, p)
+	}
+
+	if v, err := (Polygon{}).Value(); v != nil || err != nil {
+		t.Errorf("Polygon{}.Value() = %v, %v, want nil, nil", v, err)
+	}
+	if _, err := (Polygon{s2.FullPolygon()}).Value(); err == nil {
+		t.Errorf("Value of the full polygon succeeded, want error")
+	}
+	if err := got.Scan(nil); err != nil || got.Polygon != nil {
+		t.Errorf("Scan(nil) = %v, %v, want nil Polygon", got.Polygon, err)
+	}
+}
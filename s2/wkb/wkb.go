@@ -0,0 +1,332 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package wkb converts between s2 geometry and the Well-Known Binary (WKB)
+representation defined by the OGC Simple Features specification, and its
+Extended WKB (EWKB) variant used by PostGIS, which adds an SRID to the
+geometry.
+
+Points, PointVectors, Polylines and Polygons are written as Point, MultiPoint,
+LineString or MultiLineString, and Polygon or MultiPolygon geometries. Any
+other Shape, such as a lax polyline or lax polygon, can be written according
+to its dimension with Encoder.Shape.
+
+Coordinates are the longitude and latitude in degrees, and are converted to
+and from Points with LatLngFromPoint and PointFromLatLng, so that reading a
+LineString gives the same result as PolylineFromLatLngs. Edges are
+interpreted as geodesics, as for the PostGIS geography type.
+
+The readers accept WKB and EWKB in either byte order, with an SRID of 4326 or
+none, and with Z, M or ZM coordinates in either the ISO or the EWKB encoding,
+whose extra ordinates are ignored. Polygon rings are read with the
+orientation they are written with: exterior rings are counterclockwise and
+holes are clockwise, so an exterior ring can bound a region larger than a
+hemisphere. Only a ring whose region would be larger than a hemisphere and
+that also winds the wrong way in longitude and latitude is read as the
+boundary of the smaller of the two regions it encloses.
+
+These readers return valid geometry only. UnmarshalShapes reads any geometry
+as a PointVector, LaxPolylines or a LaxPolygon without checking it, so that
+rings that are degenerate or cross themselves can still be read.
+
+# Database
+
+Point, Polyline and Polygon wrap the corresponding s2 types to implement
+sql.Scanner and driver.Valuer, so that they can be used directly as query
+arguments and scanned from geometry or geography columns:
+
+	var area wkb.Polygon
+	err := db.QueryRow("SELECT area FROM regions WHERE id = $1", id).Scan(&area)
+*/
+package wkb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/rubenpoppe/geo/s2"
+	"github.com/rubenpoppe/geo/s2/internal/ogc"
+)
+
+// SRID is the spatial reference identifier of longitude and latitude on the
+// WGS 84 ellipsoid, which is the only one supported.
+const SRID = 4326
+
+// The WKB geometry type codes.
+const (
+	typePoint           = 1
+	typeLineString      = 2
+	typePolygon         = 3
+	typeMultiPoint      = 4
+	typeMultiLineString = 5
+	typeMultiPolygon    = 6
+)
+
+// The flags that EWKB adds to the geometry type.
+const (
+	ewkbZ    = 0x80000000
+	ewkbM    = 0x40000000
+	ewkbSRID = 0x20000000
+)
+
+// The byte order markers that start each geometry.
+const (
+	bigEndian    = 0
+	littleEndian = 1
+)
+
+// errFull is returned when writing geometry that covers the whole sphere.
+var errFull = errors.New("the full polygon cannot be represented in WKB")
+
+// An Encoder writes geometry as WKB, or as EWKB if it has an SRID.
+type Encoder struct {
+	// ByteOrder is binary.LittleEndian or binary.BigEndian. If nil,
+	// binary.LittleEndian is used, as by PostGIS on most platforms.
+	ByteOrder binary.ByteOrder
+
+	// SRID is written with the geometry as EWKB if it is nonzero. It should be
+	// 0 or SRID.
+	SRID int
+}
+
+// MarshalPoint returns the WKB representation of p as a Point.
+func MarshalPoint(p s2.Point) []byte {
+	return new(Encoder).Point(p)
+}
+
+// MarshalPointVector returns the WKB representation of the points as a
+// MultiPoint.
+func MarshalPointVector(points s2.PointVector) []byte {
+	return new(Encoder).PointVector(points)
+}
+
+// MarshalPolyline returns the WKB representation of l as a LineString.
+func MarshalPolyline(l *s2.Polyline) []byte {
+	return new(Encoder).Polyline(l)
+}
+
+// MarshalPolylines returns the WKB representation of the polylines as a
+// MultiLineString.
+func MarshalPolylines(lines []*s2.Polyline) []byte {
+	return new(Encoder).Polylines(lines)
+}
+
+// MarshalPolygon returns the WKB representation of p as a Polygon, or as a
+// MultiPolygon if p has more than one shell.
+func MarshalPolygon(p *s2.Polygon) ([]byte, error) {
+	return new(Encoder).Polygon(p)
+}
+
+// MarshalShape returns the WKB representation of an arbitrary shape.
+func MarshalShape(shape s2.Shape) ([]byte, error) {
+	return new(Encoder).Shape(shape)
+}
+
+// Point returns the encoding of p as a Point.
+func (e *Encoder) Point(p s2.Point) []byte {
+	w := e.writer()
+	w.header(typePoint, e.SRID)
+	w.point(p)
+	return w.buf
+}
+
+// PointVector returns the encoding of the points as a MultiPoint.
+func (e *Encoder) PointVector(points s2.PointVector) []byte {
+	w := e.writer()
+	w.header(typeMultiPoint, e.SRID)
+	w.uint32(uint32(len(points)))
+	for _, p := range points {
+		w.header(typePoint, 0)
+		w.point(p)
+	}
+	return w.buf
+}
+
+// Polyline returns the encoding of l as a LineString.
+func (e *Encoder) Polyline(l *s2.Polyline) []byte {
+	w := e.writer()
+	w.header(typeLineString, e.SRID)
+	w.points(*l)
+	return w.buf
+}
+
+// Polylines returns the encoding of the polylines as a MultiLineString.
+func (e *Encoder) Polylines(lines []*s2.Polyline) []byte {
+	chains := make([][]s2.Point, len(lines))
+	for i, l := range lines {
+		chains[i] = *l
+	}
+	return e.chains(chains, true)
+}
+
+// chains returns the encoding of the chains as a MultiLineString, or as a
+// LineString if there is exactly one chain and multi is false.
+func (e *Encoder) chains(chains [][]s2.Point, multi bool) []byte {
+	if len(chains) == 1 && !multi {
+		return e.Polyline((*s2.Polyline)(&chains[0]))
+	}
+	w := e.writer()
+	w.header(typeMultiLineString, e.SRID)
+	w.uint32(uint32(len(chains)))
+	for _, chain := range chains {
+		w.header(typeLineString, 0)
+		w.points(chain)
+	}
+	return w.buf
+}
+
+// Polygon returns the encoding of p as a Polygon, or as a MultiPolygon if p
+// has more than one shell. Exterior rings are counterclockwise and holes are
+// clockwise. The full polygon cannot be represented in WKB and returns an
+// error.
+func (e *Encoder) Polygon(p *s2.Polygon) ([]byte, error) {
+	if p.IsFull() {
+		return nil, fmt.Errorf("wkb: %v", errFull)
+	}
+	polygons := ogc.PolygonRings(p)
+	w := e.writer()
+	switch len(polygons) {
+	case 0:
+		w.header(typePolygon, e.SRID)
+		w.rings(nil)
+	case 1:
+		w.header(typePolygon, e.SRID)
+		w.rings(polygons[0])
+	default:
+		w.header(typeMultiPolygon, e.SRID)
+		w.uint32(uint32(len(polygons)))
+		for _, rings := range polygons {
+			w.header(typePolygon, 0)
+			w.rings(rings)
+		}
+	}
+	return w.buf, nil
+}
+
+// Shape returns the encoding of an arbitrary shape. Points, Polylines,
+// Polygons and Loops are written as by the methods above. Other shapes are
+// written according to their dimension: points as a MultiPoint, the chains of
+// polylines as a LineString or MultiLineString, and the chains of polygons as
+// the oriented loops of a Polygon or MultiPolygon.
+func (e *Encoder) Shape(shape s2.Shape) ([]byte, error) {
+	switch s := shape.(type) {
+	case *s2.PointVector:
+		return e.PointVector(*s), nil
+	case *s2.Polyline:
+		return e.Polyline(s), nil
+	case *s2.Polygon:
+		return e.Polygon(s)
+	case *s2.Loop:
+		if s.IsFull() {
+			return nil, fmt.Errorf("wkb: %v", errFull)
+		}
+		var loops []*s2.Loop
+		if !s.IsEmpty() {
+			loops = append(loops, s2.LoopFromPoints(s.Vertices()))
+		}
+		return e.Polygon(s2.PolygonFromLoops(loops))
+	}
+
+	switch shape.Dimension() {
+	case 0:
+		points := make(s2.PointVector, 0, shape.NumEdges())
+		for i := 0; i < shape.NumEdges(); i++ {
+			points = append(points, shape.Edge(i).V0)
+		}
+		return e.PointVector(points), nil
+	case 1:
+		return e.chains(ogc.ShapeChains(shape, false), false), nil
+	default:
+		if shape.IsFull() {
+			return nil, fmt.Errorf("wkb: %v", errFull)
+		}
+		var loops []*s2.Loop
+		for _, chain := range ogc.ShapeChains(shape, true) {
+			loops = append(loops, s2.LoopFromPoints(chain))
+		}
+		p := s2.PolygonFromOrientedLoops(loops)
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("wkb: invalid polygon shape: %v", err)
+		}
+		return e.Polygon(p)
+	}
+}
+
+// writer appends WKB to a buffer.
+type writer struct {
+	buf   []byte
+	order binary.ByteOrder
+}
+
+// writer returns a writer that uses the byte order of e.
+func (e *Encoder) writer() *writer {
+	if e.ByteOrder == nil {
+		return &writer{order: binary.LittleEndian}
+	}
+	return &writer{order: e.ByteOrder}
+}
+
+func (w *writer) uint32(x uint32) {
+	var b [4]byte
+	w.order.PutUint32(b[:], x)
+	w.buf = append(w.buf, b[:]...)
+}
+
+func (w *writer) float64(x float64) {
+	var b [8]byte
+	w.order.PutUint64(b[:], math.Float64bits(x))
+	w.buf = append(w.buf, b[:]...)
+}
+
+// header writes the byte order and the geometry type, followed by the SRID if
+// it is nonzero.
+func (w *writer) header(typ uint32, srid int) {
+	if w.order == binary.BigEndian {
+		w.buf = append(w.buf, bigEndian)
+	} else {
+		w.buf = append(w.buf, littleEndian)
+	}
+	if srid == 0 {
+		w.uint32(typ)
+		return
+	}
+	w.uint32(typ | ewkbSRID)
+	w.uint32(uint32(srid))
+}
+
+// point writes the longitude and latitude of p.
+func (w *writer) point(p s2.Point) {
+	ll := s2.LatLngFromPoint(p)
+	w.float64(ll.Lng.Degrees())
+	w.float64(ll.Lat.Degrees())
+}
+
+// points writes the number of points followed by the points.
+func (w *writer) points(points []s2.Point) {
+	w.uint32(uint32(len(points)))
+	for _, p := range points {
+		w.point(p)
+	}
+}
+
+// rings writes the number of rings followed by the closed rings.
+func (w *writer) rings(rings [][]s2.Point) {
+	w.uint32(uint32(len(rings)))
+	for _, ring := range rings {
+		w.points(append(ring[:len(ring):len(ring)], ring[0]))
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wkb
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// opaqueShape hides the concrete type of a shape, so that it is written
+// according to its dimension like any other shape, such as a lax polyline or a
+// lax polygon.
+type opaqueShape struct {
+	s2.Shape
+}
+
+// loopsApproxEqual reports whether the two loops have approximately the same
+// vertices in the same cyclic order.
+func loopsApproxEqual(a, b *s2.Loop) bool {
+	n := a.NumVertices()
+	if n != b.NumVertices() {
+		return false
+	}
+	for offset := 0; offset < n; offset++ {
+		equal := true
+		for i := 0; i < n && equal; i++ {
+			equal = a.Vertex(i).ApproxEqual(b.Vertex(i + offset))
+		}
+		if equal {
+			return true
+		}
+	}
+	return false
+}
+
+// polygonsApproxEqual reports whether each loop of a is approximately equal to
+// the loop of b with the same index.
+func polygonsApproxEqual(a, b *s2.Polygon) bool {
+	if a.NumLoops() != b.NumLoops() {
+		return false
+	}
+	for i := 0; i < a.NumLoops(); i++ {
+		if !loopsApproxEqual(a.Loop(i), b.Loop(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// mustDecodeHex decodes a hex string, ignoring spaces.
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		t.Fatalf("hex.DecodeString(%q) failed: %v", s, err)
+	}
+	return b
+}
+
+func TestEncoderPoint(t *testing.T) {
+	p := s2.PointFromLatLng(s2.LatLngFromDegrees(90, 0))
+	tests := []struct {
+		e    *Encoder
+		want string
+	}{
+		{&Encoder{}, "01 01000000 0000000000000000 0000000000805640"},
+		{&Encoder{ByteOrder: binary.BigEndian}, "00 00000001 0000000000000000 4056800000000000"},
+		{&Encoder{SRID: SRID}, "01 01000020 E6100000 0000000000000000 0000000000805640"},
+		{&Encoder{ByteOrder: binary.BigEndian, SRID: SRID}, "00 20000001 000010E6 0000000000000000 4056800000000000"},
+	}
+	for _, test := range tests {
+		want := mustDecodeHex(t, test.want)
+		if got := test.e.Point(p); string(got) != string(want) {
+			t.Errorf("%+v.Point(%v) = %X, want %X", test.e, p, got, want)
+		}
+	}
+	if got, want := MarshalPoint(p), mustDecodeHex(t, tests[0].want); string(got) != string(want) {
+		t.Errorf("MarshalPoint(%v) = %X, want %X", p, got, want)
+	}
+}
+
+func TestEncoderMultiGeometry(t *testing.T) {
+	// The elements of a multi-geometry have their own header, without the
+	// SRID.
+	e := &Encoder{SRID: SRID}
+	got := e.PointVector(s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(90, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(90, 0))})
+	want := mustDecodeHex(t, "01 04000020 E6100000 02000000"+
+		"01 01000000 0000000000000000 0000000000805640"+
+		"01 01000000 0000000000000000 0000000000805640")
+	if string(got) != string(want) {
+		t.Errorf("PointVector = %X, want %X", got, want)
+	}
+
+	if got, want := MarshalPointVector(nil), mustDecodeHex(t, "01 04000000 00000000"); string(got) != string(want) {
+		t.Errorf("MarshalPointVector(nil) = %X, want %X", got, want)
+	}
+	if got, want := MarshalPolylines(nil), mustDecodeHex(t, "01 05000000 00000000"); string(got) != string(want) {
+		t.Errorf("MarshalPolylines(nil) = %X, want %X", got, want)
+	}
+	got, err := MarshalPolygon(&s2.Polygon{})
+	if err != nil {
+		t.Fatalf("MarshalPolygon(empty) failed: %v", err)
+	}
+	if want := mustDecodeHex(t, "01 03000000 00000000"); string(got) != string(want) {
+		t.Errorf("MarshalPolygon(empty) = %X, want %X", got, want)
+	}
+	if _, err := MarshalPolygon(s2.FullPolygon()); err == nil {
+		t.Errorf("MarshalPolygon(full) succeeded, want error")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	lls := []s2.LatLng{
+		s2.LatLngFromDegrees(37.7749, -122.4194),
+		s2.LatLngFromDegrees(40.7128, -74.006),
+		s2.LatLngFromDegrees(-33.8688, 151.2093),
+		s2.LatLngFromDegrees(0.1, 179.9),
+	}
+	l := s2.PolylineFromLatLngs(lls)
+	shell := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 0))})
+	hole := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(2, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(2, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(4, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(4, 2))})
+	island := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(20, 20)), s2.PointFromLatLng(s2.LatLngFromDegrees(20, 21)), s2.PointFromLatLng(s2.LatLngFromDegrees(21, 21))})
+
+	for _, e := range []*Encoder{{}, {ByteOrder: binary.BigEndian}, {SRID: SRID}} {
+		for _, ll := range lls {
+			p := s2.PointFromLatLng(ll)
+			got, err := UnmarshalPoint(e.Point(p))
+			if err != nil {
+				t.Fatalf("UnmarshalPoint(%+v.Point(%v)) failed: %v", e, ll, err)
+			}
+			if !got.ApproxEqual(p) {
+				t.Errorf("UnmarshalPoint(%+v.Point(%v)) = %v, want %v", e, ll, s2.LatLngFromPoint(got), ll)
+			}
+		}
+
+		got, err := UnmarshalPolyline(e.Polyline(l))
+		if err != nil {
+			t.Fatalf("UnmarshalPolyline(%+v.Polyline(%v)) failed: %v", e, l, err)
+		}
+		if !got.ApproxEqual(l) {
+			t.Errorf("UnmarshalPolyline(%+v.Polyline(%v)) = %v, want %v", e, l, got, l)
+		}
+
+		lines := []*s2.Polyline{l, {s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(1, 1))}}
+		gotLines, err := UnmarshalPolylines(e.Polylines(lines))
+		if err != nil {
+			t.Fatalf("UnmarshalPolylines(%+v.Polylines(%v)) failed: %v", e, lines, err)
+		}
+		if len(gotLines) != 2 || !gotLines[0].ApproxEqual(lines[0]) || !gotLines[1].ApproxEqual(lines[1]) {
+			t.Errorf("UnmarshalPolylines(%+v.Polylines(%v)) = %v, want %v", e, lines, gotLines, lines)
+		}
+
+		for _, loops := range [][]*s2.Loop{nil, {shell}, {shell, hole}, {shell, hole, island}} {
+			p := s2.PolygonFromLoops(loops)
+			data, err := e.Polygon(p)
+			if err != nil {
+				t.Fatalf("%+v.Polygon(%v) failed: %v", e, p, err)
+			}
+			got, err := UnmarshalPolygon(data)
+			if err != nil {
+				t.Fatalf("UnmarshalPolygon(%X) failed: %v", data, err)
+			}
+			if !polygonsApproxEqual(got, p) {
+				t.Errorf("UnmarshalPolygon(%+v.Polygon(%v)) = %v, want %v", e, p, got, p)
+			}
+		}
+	}
+}
+
+func TestEncoderShape(t *testing.T) {
+	shell := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 0))})
+	hole := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(2, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(2, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(4, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(4, 2))})
+	island := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(20, 20)), s2.PointFromLatLng(s2.LatLngFromDegrees(20, 21)), s2.PointFromLatLng(s2.LatLngFromDegrees(21, 21))})
+	polygon := s2.PolygonFromLoops([]*s2.Loop{shell, hole, island})
+	line := &s2.Polyline{s2.PointFromLatLng(s2.LatLngFromDegrees(1, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(3, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(5, 6))}
+
+	e := new(Encoder)
+	tests := []struct {
+		name  string
+		shape s2.Shape
+		want  []byte
+	}{
+		{"points", opaqueShape{&s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(1, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(3, 4))}}, e.PointVector(s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(1, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(3, 4))})},
+		{"polyline", opaqueShape{line}, e.Polyline(line)},
+		{"empty polyline", opaqueShape{&s2.Polyline{}}, e.Polylines(nil)},
+		{"loop", shell, mustPolygon(t, s2.PolygonFromLoops([]*s2.Loop{shell}))},
+		{"polygon", opaqueShape{polygon}, mustPolygon(t, polygon)},
+		{"empty polygon", opaqueShape{&s2.Polygon{}}, mustPolygon(t, &s2.Polygon{})},
+	}
+	for _, test := range tests {
+		got, err := e.Shape(test.shape)
+		if err != nil {
+			t.Errorf("%s: Shape failed: %v", test.name, err)
+			continue
+		}
+		if string(got) != string(test.want) {
+			t.Errorf("%s: Shape = %X, want %X", test.name, got, test.want)
+		}
+	}
+
+	if _, err := MarshalShape(opaqueShape{s2.FullPolygon()}); err == nil {
+		t.Errorf("MarshalShape(full polygon shape) succeeded, want error")
+	}
+}
+
+// mustPolygon returns the WKB encoding of p.
+func mustPolygon(t *testing.T, p *s2.Polygon) []byte {
+	t.Helper()
+	data, err := MarshalPolygon(p)
+	if err != nil {
+		t.Fatalf("MarshalPolygon(%v) failed: %v", p, err)
+	}
+	return data
+}
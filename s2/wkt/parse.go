@@ -0,0 +1,416 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wkt
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/rubenpoppe/geo/s2"
+	"github.com/rubenpoppe/geo/s2/internal/ogc"
+)
+
+// The WKT geometry types that are supported.
+const (
+	typePoint           = "POINT"
+	typeLineString      = "LINESTRING"
+	typePolygon         = "POLYGON"
+	typeMultiPoint      = "MULTIPOINT"
+	typeMultiLineString = "MULTILINESTRING"
+	typeMultiPolygon    = "MULTIPOLYGON"
+)
+
+// geometry is a parsed WKT geometry. Points holds the positions of a POINT,
+// MULTIPOINT or LINESTRING, lines the line strings of a MULTILINESTRING or the
+// rings of a POLYGON, and polygons the rings of each polygon of a
+// MULTIPOLYGON. All of them are empty for an EMPTY geometry.
+type geometry struct {
+	typ      string
+	points   []ogc.Position
+	lines    [][]ogc.Position
+	polygons [][][]ogc.Position
+}
+
+// UnmarshalPoint parses a POINT.
+func UnmarshalPoint(s string) (s2.Point, error) {
+	g, err := parse(s, typePoint)
+	if err != nil {
+		return s2.Point{}, err
+	}
+	if len(g.points) == 0 {
+		return s2.Point{}, fmt.Errorf("wkt: POINT EMPTY cannot be converted to a Point")
+	}
+	return ogc.PointFromPosition(g.points[0]), nil
+}
+
+// UnmarshalPointVector parses a POINT or MULTIPOINT.
+func UnmarshalPointVector(s string) (s2.PointVector, error) {
+	g, err := parse(s, typePoint, typeMultiPoint)
+	if err != nil {
+		return nil, err
+	}
+	points := make(s2.PointVector, len(g.points))
+	for i, pos := range g.points {
+		points[i] = ogc.PointFromPosition(pos)
+	}
+	return points, nil
+}
+
+// UnmarshalPolyline parses a LINESTRING.
+func UnmarshalPolyline(s string) (*s2.Polyline, error) {
+	g, err := parse(s, typeLineString)
+	if err != nil {
+		return nil, err
+	}
+	l, err := ogc.PolylineFromPositions(g.points)
+	if err != nil {
+		return nil, fmt.Errorf("wkt: %v", err)
+	}
+	return l, nil
+}
+
+// UnmarshalPolylines parses a LINESTRING or MULTILINESTRING.
+func UnmarshalPolylines(s string) ([]*s2.Polyline, error) {
+	g, err := parse(s, typeLineString, typeMultiLineString)
+	if err != nil {
+		return nil, err
+	}
+	if g.typ == typeLineString {
+		l, err := ogc.PolylineFromPositions(g.points)
+		if err != nil {
+			return nil, fmt.Errorf("wkt: %v", err)
+		}
+		return []*s2.Polyline{l}, nil
+	}
+	lines := make([]*s2.Polyline, len(g.lines))
+	for i, line := range g.lines {
+		if lines[i], err = ogc.PolylineFromPositions(line); err != nil {
+			return nil, fmt.Errorf("wkt: line string %d: %v", i, err)
+		}
+	}
+	return lines, nil
+}
+
+// UnmarshalPolygon parses a POLYGON or MULTIPOLYGON. The polygons of a
+// MULTIPOLYGON must not overlap.
+func UnmarshalPolygon(s string) (*s2.Polygon, error) {
+	g, err := parse(s, typePolygon, typeMultiPolygon)
+	if err != nil {
+		return nil, err
+	}
+	var loops []*s2.Loop
+	if g.typ == typePolygon {
+		for i, ring := range g.lines {
+			loop, err := ogc.LoopFromRing(ring, i > 0)
+			if err != nil {
+				return nil, fmt.Errorf("wkt: ring %d: %v", i, err)
+			}
+			loops = append(loops, loop)
+		}
+	} else {
+		for i, rings := range g.polygons {
+			for k, ring := range rings {
+				loop, err := ogc.LoopFromRing(ring, k > 0)
+				if err != nil {
+					return nil, fmt.Errorf("wkt: polygon %d, ring %d: %v", i, k, err)
+				}
+				loops = append(loops, loop)
+			}
+		}
+	}
+	p := s2.PolygonFromOrientedLoops(loops)
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("wkt: invalid %s: %v", g.typ, err)
+	}
+	return p, nil
+}
+
+// UnmarshalShapes parses any geometry into shapes without checking that they
+// are valid, so that geometry with degenerate or self-intersecting rings or
+// overlapping polygons can still be read, for example to be repaired with
+// s2.RepairPolygon. A POINT or MULTIPOINT gives a single PointVector, each line
+// string of a LINESTRING or MULTILINESTRING gives a LaxPolyline, and all the
+// rings of a POLYGON or MULTIPOLYGON give a single LaxPolygon. Rings are taken
+// as written: exterior rings must be counterclockwise and holes clockwise.
+func UnmarshalShapes(s string) ([]s2.Shape, error) {
+	g, err := parse(s, typePoint, typeLineString, typePolygon, typeMultiPoint, typeMultiLineString, typeMultiPolygon)
+	if err != nil {
+		return nil, err
+	}
+	switch g.typ {
+	case typePoint, typeMultiPoint:
+		points := make(s2.PointVector, len(g.points))
+		for i, pos := range g.points {
+			points[i] = ogc.PointFromPosition(pos)
+		}
+		return []s2.Shape{&points}, nil
+	case typeLineString:
+		return []s2.Shape{ogc.LaxPolylineFromPositions(g.points)}, nil
+	case typeMultiLineString:
+		shapes := make([]s2.Shape, len(g.lines))
+		for i, line := range g.lines {
+			shapes[i] = ogc.LaxPolylineFromPositions(line)
+		}
+		return shapes, nil
+	}
+	rings := g.lines
+	if g.typ == typeMultiPolygon {
+		rings = nil
+		for _, polygon := range g.polygons {
+			rings = append(rings, polygon...)
+		}
+	}
+	polygon, err := ogc.LaxPolygonFromRings(rings)
+	if err != nil {
+		return nil, fmt.Errorf("wkt: %v", err)
+	}
+	return []s2.Shape{polygon}, nil
+}
+
+// parser is a recursive descent parser for WKT.
+type parser struct {
+	s   string
+	pos int
+}
+
+// parse parses s, which must be a geometry of one of the given types.
+func parse(s string, types ...string) (*geometry, error) {
+	p := &parser{s: s}
+	g, err := p.geometry()
+	if err != nil {
+		return nil, fmt.Errorf("wkt: %v", err)
+	}
+	for _, t := range types {
+		if g.typ == t {
+			return g, nil
+		}
+	}
+	if len(types) == 1 {
+		return nil, fmt.Errorf("wkt: geometry type is %s, want %s", g.typ, types[0])
+	}
+	return nil, fmt.Errorf("wkt: geometry type is %s, want one of %s", g.typ, strings.Join(types, ", "))
+}
+
+// errorf returns an error for the token at offset off.
+func (p *parser) errorf(off int, format string, args ...interface{}) error {
+	return fmt.Errorf("%s at offset %d", fmt.Sprintf(format, args...), off)
+}
+
+// next returns the next token and its offset. Tokens are words, numbers and
+// the punctuation characters "(),;=". The token is empty at the end of the
+// input.
+func (p *parser) next() (string, int) {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos == len(p.s) {
+		return "", start
+	}
+	if strings.IndexByte("(),;=", p.s[p.pos]) >= 0 {
+		p.pos++
+		return p.s[start:p.pos], start
+	}
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n(),;=", p.s[p.pos]) < 0 {
+		p.pos++
+	}
+	return p.s[start:p.pos], start
+}
+
+// peek returns the next token without consuming it.
+func (p *parser) peek() string {
+	pos := p.pos
+	tok, _ := p.next()
+	p.pos = pos
+	return tok
+}
+
+// expect consumes the next token, which must be want.
+func (p *parser) expect(want string) error {
+	if tok, off := p.next(); tok != want {
+		return p.errorf(off, "found %s, want %q", describe(tok), want)
+	}
+	return nil
+}
+
+// describe returns a description of a token for error messages.
+func describe(tok string) string {
+	if tok == "" {
+		return "end of input"
+	}
+	return strconv.Quote(tok)
+}
+
+// geometry parses a complete geometry, with an optional SRID prefix.
+func (p *parser) geometry() (*geometry, error) {
+	tok, off := p.next()
+	if strings.EqualFold(tok, "SRID") {
+		if err := p.expect("="); err != nil {
+			return nil, err
+		}
+		tok, off = p.next()
+		srid, err := strconv.Atoi(tok)
+		if err != nil {
+			return nil, p.errorf(off, "found %s, want an SRID", describe(tok))
+		}
+		if srid != SRID {
+			return nil, p.errorf(off, "SRID %d is not supported, want %d", srid, SRID)
+		}
+		if err := p.expect(";"); err != nil {
+			return nil, err
+		}
+		tok, off = p.next()
+	}
+
+	g := &geometry{typ: strings.ToUpper(tok)}
+	switch g.typ {
+	case typePoint, typeLineString, typePolygon, typeMultiPoint, typeMultiLineString, typeMultiPolygon:
+	default:
+		return nil, p.errorf(off, "found %s, want a geometry type", describe(tok))
+	}
+	switch strings.ToUpper(p.peek()) {
+	case "Z", "M", "ZM":
+		p.next()
+	}
+	if strings.EqualFold(p.peek(), "EMPTY") {
+		p.next()
+	} else {
+		var err error
+		switch g.typ {
+		case typePoint:
+			if err = p.expect("("); err == nil {
+				var pos ogc.Position
+				if pos, err = p.position(); err == nil {
+					g.points = []ogc.Position{pos}
+					err = p.expect(")")
+				}
+			}
+		case typeLineString:
+			g.points, err = p.positions(false)
+		case typeMultiPoint:
+			g.points, err = p.positions(true)
+		case typePolygon, typeMultiLineString:
+			g.lines, err = p.lines()
+		case typeMultiPolygon:
+			err = p.list(func() error {
+				rings, err := p.lines()
+				g.polygons = append(g.polygons, rings)
+				return err
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if tok, off := p.next(); tok != "" {
+		return nil, p.errorf(off, "found %s after the geometry", describe(tok))
+	}
+	return g, nil
+}
+
+// list parses a parenthesized, comma-separated list whose elements are
+// parsed by elem.
+func (p *parser) list(elem func() error) error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for {
+		if err := elem(); err != nil {
+			return err
+		}
+		switch tok, off := p.next(); tok {
+		case ")":
+			return nil
+		case ",":
+		default:
+			return p.errorf(off, "found %s, want \",\" or \")\"", describe(tok))
+		}
+	}
+}
+
+// positions parses a list of positions. If multipoint is true, each position
+// may be enclosed in parentheses.
+func (p *parser) positions(multipoint bool) ([]ogc.Position, error) {
+	var positions []ogc.Position
+	err := p.list(func() error {
+		paren := multipoint && p.peek() == "("
+		if paren {
+			p.next()
+		}
+		pos, err := p.position()
+		if err != nil {
+			return err
+		}
+		positions = append(positions, pos)
+		if paren {
+			return p.expect(")")
+		}
+		return nil
+	})
+	return positions, err
+}
+
+// lines parses a list of lists of positions.
+func (p *parser) lines() ([][]ogc.Position, error) {
+	var lines [][]ogc.Position
+	err := p.list(func() error {
+		line, err := p.positions(false)
+		lines = append(lines, line)
+		return err
+	})
+	return lines, err
+}
+
+// position parses a position with two to four ordinates, of which the first
+// two are the longitude and latitude in degrees.
+func (p *parser) position() (ogc.Position, error) {
+	var ords []float64
+	start := p.pos
+	for len(ords) < 4 {
+		switch p.peek() {
+		case ",", ")", "":
+			if len(ords) < 2 {
+				tok, off := p.next()
+				return ogc.Position{}, p.errorf(off, "found %s, want a coordinate", describe(tok))
+			}
+			return p.checkPosition(ords, start)
+		}
+		tok, off := p.next()
+		v, err := strconv.ParseFloat(tok, 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return ogc.Position{}, p.errorf(off, "found %s, want a coordinate", describe(tok))
+		}
+		ords = append(ords, v)
+	}
+	return p.checkPosition(ords, start)
+}
+
+// checkPosition checks that the longitude and latitude of a position starting
+// at offset off are in range.
+func (p *parser) checkPosition(ords []float64, off int) (ogc.Position, error) {
+	for off < len(p.s) && strings.IndexByte(" \t\r\n", p.s[off]) >= 0 {
+		off++
+	}
+	lng, lat := ords[0], ords[1]
+	if lng < -180 || lng > 180 {
+		return ogc.Position{}, p.errorf(off, "longitude %v is out of range [-180, 180]", lng)
+	}
+	if lat < -90 || lat > 90 {
+		return ogc.Position{}, p.errorf(off, "latitude %v is out of range [-90, 90]", lat)
+	}
+	return ogc.Position{lng, lat}, nil
+}
@@ -0,0 +1,313 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wkt
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/rubenpoppe/geo/s2"
+)
+
+func TestUnmarshalPoint(t *testing.T) {
+	tests := []struct {
+		s    string
+		want s2.Point
+	}{
+		{"POINT (20 10)", s2.PointFromLatLng(s2.LatLngFromDegrees(10, 20))},
+		{"point(20 10)", s2.PointFromLatLng(s2.LatLngFromDegrees(10, 20))},
+		{"  POINT ( -120.25   -45.5 ) ", s2.PointFromLatLng(s2.LatLngFromDegrees(-45.5, -120.25))},
+		{"SRID=4326;POINT(20 10)", s2.PointFromLatLng(s2.LatLngFromDegrees(10, 20))},
+		{"POINT Z (20 10 100)", s2.PointFromLatLng(s2.LatLngFromDegrees(10, 20))},
+		{"POINT M (20 10 5)", s2.PointFromLatLng(s2.LatLngFromDegrees(10, 20))},
+		{"POINT ZM (20 10 100 5)", s2.PointFromLatLng(s2.LatLngFromDegrees(10, 20))},
+		{"POINT (20 10 100)", s2.PointFromLatLng(s2.LatLngFromDegrees(10, 20))},
+		{"POINT (1e1 -5E-1)", s2.PointFromLatLng(s2.LatLngFromDegrees(-0.5, 10))},
+	}
+	for _, test := range tests {
+		got, err := UnmarshalPoint(test.s)
+		if err != nil {
+			t.Errorf("UnmarshalPoint(%q) failed: %v", test.s, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("UnmarshalPoint(%q) = %v, want %v", test.s, got, test.want)
+		}
+	}
+}
+
+func TestUnmarshalPointVector(t *testing.T) {
+	tests := []struct {
+		s    string
+		want s2.PointVector
+	}{
+		{"MULTIPOINT EMPTY", s2.PointVector{}},
+		{"POINT (2 1)", s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(1, 2))}},
+		{"MULTIPOINT ((2 1), (4 3))", s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(1, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(3, 4))}},
+		{"MULTIPOINT (2 1, 4 3)", s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(1, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(3, 4))}},
+		{"MULTIPOINT Z ((2 1 0), (4 3 0))", s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(1, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(3, 4))}},
+	}
+	for _, test := range tests {
+		got, err := UnmarshalPointVector(test.s)
+		if err != nil {
+			t.Errorf("UnmarshalPointVector(%q) failed: %v", test.s, err)
+			continue
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("UnmarshalPointVector(%q) = %v, want %v", test.s, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("UnmarshalPointVector(%q)[%d] = %v, want %v", test.s, i, got[i], test.want[i])
+			}
+		}
+	}
+}
+
+func TestUnmarshalPolylines(t *testing.T) {
+	l, err := UnmarshalPolyline("SRID=4326;LINESTRING (2 1, 4 3, 6 5)")
+	if err != nil {
+		t.Fatalf("UnmarshalPolyline failed: %v", err)
+	}
+	want := s2.PolylineFromLatLngs([]s2.LatLng{
+		s2.LatLngFromDegrees(1, 2),
+		s2.LatLngFromDegrees(3, 4),
+		s2.LatLngFromDegrees(5, 6),
+	})
+	if !l.Equal(want) {
+		t.Errorf("UnmarshalPolyline = %v, want %v", l, want)
+	}
+
+	if l, err = UnmarshalPolyline("LINESTRING EMPTY"); err != nil || len(*l) != 0 {
+		t.Errorf("UnmarshalPolyline(LINESTRING EMPTY) = %v, %v, want empty polyline", l, err)
+	}
+
+	lines, err := UnmarshalPolylines("MULTILINESTRING ((0 0, 10 0), (5 5, 6 6, 5 7))")
+	if err != nil {
+		t.Fatalf("UnmarshalPolylines failed: %v", err)
+	}
+	if len(lines) != 2 || len(*lines[0]) != 2 || len(*lines[1]) != 3 {
+		t.Errorf("UnmarshalPolylines = %v, want polylines with 2 and 3 vertices", lines)
+	}
+	if lines, err = UnmarshalPolylines("LINESTRING (0 0, 10 0)"); err != nil || len(lines) != 1 {
+		t.Errorf("UnmarshalPolylines(LINESTRING) = %v, %v, want 1 polyline", lines, err)
+	}
+}
+
+func TestUnmarshalPolygon(t *testing.T) {
+	shell := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 0))})
+	hole := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(2, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(2, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(4, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(4, 2))})
+	island := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(20, 20)), s2.PointFromLatLng(s2.LatLngFromDegrees(20, 21)), s2.PointFromLatLng(s2.LatLngFromDegrees(21, 21))})
+
+	tests := []struct {
+		s    string
+		want []*s2.Loop
+	}{
+		{"POLYGON EMPTY", nil},
+		{"MULTIPOLYGON EMPTY", nil},
+		{"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0))", []*s2.Loop{shell}},
+		// Clockwise rings are read the same way.
+		{"POLYGON ((0 0, 0 10, 10 10, 10 0, 0 0))", []*s2.Loop{shell}},
+		// Consecutive duplicate positions are removed.
+		{"POLYGON ((0 0, 10 0, 10 0, 10 10, 0 10, 0 0))", []*s2.Loop{shell}},
+		{"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (2 2, 2 4, 4 4, 4 2, 2 2))", []*s2.Loop{shell, hole}},
+		{"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (2 2, 4 2, 4 4, 2 4, 2 2))", []*s2.Loop{shell, hole}},
+		{
+			"SRID=4326;MULTIPOLYGON Z (((0 0 1, 10 0 1, 10 10 1, 0 10 1, 0 0 1), (2 2 1, 2 4 1, 4 4 1, 4 2 1, 2 2 1)), ((20 20 1, 21 20 1, 21 21 1, 20 20 1)))",
+			[]*s2.Loop{shell, hole, island},
+		},
+	}
+	for _, test := range tests {
+		got, err := UnmarshalPolygon(test.s)
+		if err != nil {
+			t.Errorf("UnmarshalPolygon(%q) failed: %v", test.s, err)
+			continue
+		}
+		if want := s2.PolygonFromLoops(test.want); !polygonsApproxEqual(got, want) {
+			t.Errorf("UnmarshalPolygon(%q) = %v, want %v", test.s, got, want)
+		}
+	}
+}
+
+func TestUnmarshalLargePolygon(t *testing.T) {
+	// A counterclockwise shell around everything but the poles and a strip
+	// near the antimeridian, which is larger than a hemisphere, with a hole.
+	const s = "POLYGON ((-170 -60, -60 -60, 60 -60, 170 -60, 170 60, 60 60, -60 60, -170 60, -170 -60), " +
+		"(-10 -10, -10 10, 10 10, 10 -10, -10 -10))"
+	got, err := UnmarshalPolygon(s)
+	if err != nil {
+		t.Fatalf("UnmarshalPolygon(%q) failed: %v", s, err)
+	}
+	if area := got.Area() / (4 * math.Pi); area < 0.85 || area > 0.9 {
+		t.Errorf("UnmarshalPolygon(%q) covers %v of the sphere, want about 0.87", s, area)
+	}
+	if !got.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(30, 0))) || got.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0))) || got.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(90, 0))) || got.ContainsPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(0, 180))) {
+		t.Errorf("UnmarshalPolygon(%q) does not have the expected interior", s)
+	}
+
+	text, err := MarshalPolygon(got)
+	if err != nil {
+		t.Fatalf("MarshalPolygon failed: %v", err)
+	}
+	back, err := UnmarshalPolygon(text)
+	if err != nil {
+		t.Fatalf("UnmarshalPolygon(%q) failed: %v", text, err)
+	}
+	if !polygonsApproxEqual(back, got) {
+		t.Errorf("round trip of %q = %v, want %v", s, back, got)
+	}
+}
+
+func TestUnmarshalShapes(t *testing.T) {
+	tests := []struct {
+		s string
+		// The dimension and number of edges of each shape.
+		dims, edges []int
+	}{
+		{"POINT (1 2)", []int{0}, []int{1}},
+		{"MULTIPOINT ((1 2), (3 4), (1 2))", []int{0}, []int{3}},
+		{"LINESTRING (0 0)", []int{1}, []int{0}},
+		{"LINESTRING (0 0, 10 0, 0 0)", []int{1}, []int{2}},
+		{"MULTILINESTRING ((0 0, 0 0), (1 1, 2 2, 3 3))", []int{1, 1}, []int{1, 2}},
+		{"MULTILINESTRING EMPTY", nil, nil},
+		{"POLYGON EMPTY", []int{2}, []int{0}},
+		// A ring that crosses itself.
+		{"POLYGON ((0 0, 10 10, 10 0, 0 10, 0 0))", []int{2}, []int{4}},
+		// A ring with duplicate vertices and a spike.
+		{"POLYGON ((0 0, 10 0, 10 0, 10 10, 20 20, 10 10, 0 10, 0 0))", []int{2}, []int{7}},
+		// Overlapping polygons, one of which is degenerate.
+		{"MULTIPOLYGON (((0 0, 10 0, 10 10, 0 0)), ((1 1, 11 1, 11 11, 1 1)), ((5 5, 6 6, 5 5)))", []int{2}, []int{8}},
+	}
+	for _, test := range tests {
+		shapes, err := UnmarshalShapes(test.s)
+		if err != nil {
+			t.Errorf("UnmarshalShapes(%q) failed: %v", test.s, err)
+			continue
+		}
+		if len(shapes) != len(test.dims) {
+			t.Errorf("UnmarshalShapes(%q) = %d shapes, want %d", test.s, len(shapes), len(test.dims))
+			continue
+		}
+		for i, shape := range shapes {
+			if shape.Dimension() != test.dims[i] || shape.NumEdges() != test.edges[i] {
+				t.Errorf("UnmarshalShapes(%q)[%d] has dimension %d and %d edges, want %d and %d",
+					test.s, i, shape.Dimension(), shape.NumEdges(), test.dims[i], test.edges[i])
+			}
+		}
+	}
+
+	// The rings are taken as written.
+	shapes, err := UnmarshalShapes("POLYGON ((0 0, 10 10, 10 0, 0 10, 0 0))")
+	if err != nil {
+		t.Fatalf("UnmarshalShapes failed: %v", err)
+	}
+	want := []s2.Point{
+		s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(10, 10)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(0, 10)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(10, 0)),
+	}
+	for i, p := range want {
+		if e := shapes[0].Edge(i); e.V0 != p || e.V1 != want[(i+1)%len(want)] {
+			t.Errorf("edge %d = %v, want it to start at %v", i, e, p)
+		}
+	}
+
+	for _, s := range []string{
+		"POLYGON ((0 0, 10 0, 10 10))",
+		"LINESTRING (0 100)",
+		"GEOMETRYCOLLECTION EMPTY",
+	} {
+		if shapes, err := UnmarshalShapes(s); err == nil {
+			t.Errorf("UnmarshalShapes(%q) = %v, want an error", s, shapes)
+		}
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		s    string
+		f    func(string) error
+		want string
+	}{
+		{"", unmarshalPoint, "want a geometry type"},
+		{"CIRCLE (0 0)", unmarshalPoint, `found "CIRCLE", want a geometry type`},
+		{"LINESTRING (0 0, 1 1)", unmarshalPoint, "geometry type is LINESTRING, want POINT"},
+		{"POINT EMPTY", unmarshalPoint, "POINT EMPTY"},
+		{"POINT (0)", unmarshalPoint, "want a coordinate"},
+		{"POINT (0 x)", unmarshalPoint, `found "x", want a coordinate`},
+		{"POINT (0 NaN)", unmarshalPoint, "want a coordinate"},
+		{"POINT (0 0 0 0 0)", unmarshalPoint, `want ")"`},
+		{"POINT (0 0", unmarshalPoint, "found end of input"},
+		{"POINT (0 0) x", unmarshalPoint, "after the geometry"},
+		{"POINT (181 0)", unmarshalPoint, "longitude 181 is out of range"},
+		{"POINT (0 -91)", unmarshalPoint, "latitude -91 is out of range"},
+		{"SRID=3857;POINT (0 0)", unmarshalPoint, "SRID 3857 is not supported"},
+		{"SRID=x;POINT (0 0)", unmarshalPoint, "want an SRID"},
+		{"SRID 4326 POINT (0 0)", unmarshalPoint, `want "="`},
+		{"POLYGON ((0 0, 1 0, 1 1, 0 0))", unmarshalPointVector, "want one of POINT, MULTIPOINT"},
+		{"LINESTRING (0 0)", unmarshalPolyline, "want at least 2"},
+		{"MULTILINESTRING ((0 0, 1 1), (0 0))", unmarshalPolylines, "line string 1"},
+		{"POLYGON ((0 0, 1 0, 0 0))", unmarshalPolygon, "ring 0: ring has 3 positions"},
+		{"POLYGON ((0 0, 1 0, 1 1, 0 1))", unmarshalPolygon, "ring is not closed"},
+		{"POLYGON ((0 0, 1 0, 1 0, 0 0))", unmarshalPolygon, "want at least 3"},
+		{"POLYGON ((0 0, 10 0, 0 10, 10 10, 0 0))", unmarshalPolygon, "crosses edge"},
+		{
+			"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (5 5, 15 5, 15 15, 5 15, 5 5))",
+			unmarshalPolygon, "invalid POLYGON",
+		},
+		{
+			"MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)), ((0 0, 1 0)))",
+			unmarshalPolygon, "polygon 1, ring 0",
+		},
+	}
+	for _, test := range tests {
+		err := test.f(test.s)
+		if err == nil {
+			t.Errorf("parsing %q succeeded, want error containing %q", test.s, test.want)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), "wkt: ") || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("parsing %q failed with %q, want error containing %q", test.s, err, test.want)
+		}
+	}
+}
+
+func unmarshalPoint(s string) error {
+	_, err := UnmarshalPoint(s)
+	return err
+}
+
+func unmarshalPointVector(s string) error {
+	_, err := UnmarshalPointVector(s)
+	return err
+}
+
+func unmarshalPolyline(s string) error {
+	_, err := UnmarshalPolyline(s)
+	return err
+}
+
+func unmarshalPolylines(s string) error {
+	_, err := UnmarshalPolylines(s)
+	return err
+}
+
+func unmarshalPolygon(s string) error {
+	_, err := UnmarshalPolygon(s)
+	return err
+}
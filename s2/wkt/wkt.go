@@ -0,0 +1,253 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package wkt converts between s2 geometry and the Well-Known Text (WKT)
+representation defined by the OGC Simple Features specification, as used by
+PostGIS and most other spatial databases.
+
+Points, PointVectors, Polylines and Polygons are written as POINT, MULTIPOINT,
+LINESTRING or MULTILINESTRING, and POLYGON or MULTIPOLYGON. Any other Shape,
+such as a lax polyline or lax polygon, can be written according to its
+dimension with MarshalShape.
+
+Positions are written as "longitude latitude" in degrees with 15 significant
+digits, and are converted to and from Points with LatLngFromPoint and
+PointFromLatLng, so that reading a LINESTRING gives the same result as
+PolylineFromLatLngs. Edges are interpreted as geodesics, as for the PostGIS
+geography type.
+
+The readers accept the EWKT prefix "SRID=4326;" written by PostGIS, as well
+as Z, M and ZM coordinates, whose extra ordinates are ignored. Polygon rings
+are read with the orientation they are written with: exterior rings are
+counterclockwise and holes are clockwise, so an exterior ring can bound a
+region larger than a hemisphere. Only a ring whose region would be larger than
+a hemisphere and that also winds the wrong way in longitude and latitude is
+read as the boundary of the smaller of the two regions it encloses.
+
+These readers return valid geometry only. UnmarshalShapes reads any geometry
+as a PointVector, LaxPolylines or a LaxPolygon without checking it, so that
+rings that are degenerate or cross themselves can still be read.
+*/
+package wkt
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rubenpoppe/geo/s2"
+	"github.com/rubenpoppe/geo/s2/internal/ogc"
+)
+
+// SRID is the spatial reference identifier of longitude and latitude on the
+// WGS 84 ellipsoid, which is the only one supported.
+const SRID = 4326
+
+// errFull is returned when writing geometry that covers the whole sphere.
+var errFull = errors.New("the full polygon cannot be represented in WKT")
+
+// MarshalPoint returns the WKT representation of p as a POINT.
+func MarshalPoint(p s2.Point) string {
+	var b strings.Builder
+	b.WriteString("POINT (")
+	writePoint(&b, p)
+	b.WriteString(")")
+	return b.String()
+}
+
+// MarshalPointVector returns the WKT representation of the points as a
+// MULTIPOINT.
+func MarshalPointVector(points s2.PointVector) string {
+	if len(points) == 0 {
+		return "MULTIPOINT EMPTY"
+	}
+	var b strings.Builder
+	b.WriteString("MULTIPOINT (")
+	for i, p := range points {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("(")
+		writePoint(&b, p)
+		b.WriteString(")")
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// MarshalPolyline returns the WKT representation of l as a LINESTRING.
+func MarshalPolyline(l *s2.Polyline) string {
+	if len(*l) == 0 {
+		return "LINESTRING EMPTY"
+	}
+	var b strings.Builder
+	b.WriteString("LINESTRING ")
+	writePoints(&b, *l)
+	return b.String()
+}
+
+// MarshalPolylines returns the WKT representation of the polylines as a
+// MULTILINESTRING.
+func MarshalPolylines(lines []*s2.Polyline) string {
+	chains := make([][]s2.Point, len(lines))
+	for i, l := range lines {
+		chains[i] = *l
+	}
+	return marshalChains(chains, true)
+}
+
+// marshalChains returns a MULTILINESTRING for the given chains, or a
+// LINESTRING if there is exactly one chain and multi is false.
+func marshalChains(chains [][]s2.Point, multi bool) string {
+	if len(chains) == 1 && !multi {
+		return MarshalPolyline((*s2.Polyline)(&chains[0]))
+	}
+	if len(chains) == 0 {
+		return "MULTILINESTRING EMPTY"
+	}
+	var b strings.Builder
+	b.WriteString("MULTILINESTRING (")
+	for i, chain := range chains {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		writePoints(&b, chain)
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// MarshalPolygon returns the WKT representation of p as a POLYGON, or as a
+// MULTIPOLYGON if p has more than one shell. Exterior rings are
+// counterclockwise and holes are clockwise. The full polygon cannot be
+// represented in WKT and returns an error.
+func MarshalPolygon(p *s2.Polygon) (string, error) {
+	if p.IsFull() {
+		return "", fmt.Errorf("wkt: %v", errFull)
+	}
+	polygons := ogc.PolygonRings(p)
+	var b strings.Builder
+	switch len(polygons) {
+	case 0:
+		return "POLYGON EMPTY", nil
+	case 1:
+		b.WriteString("POLYGON ")
+		writeRings(&b, polygons[0])
+	default:
+		b.WriteString("MULTIPOLYGON (")
+		for i, rings := range polygons {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeRings(&b, rings)
+		}
+		b.WriteString(")")
+	}
+	return b.String(), nil
+}
+
+// MarshalShape returns the WKT representation of an arbitrary shape.
+// Points, Polylines, Polygons and Loops are written as by the functions
+// above. Other shapes are written according to their dimension: points as a
+// MULTIPOINT, the chains of polylines as a LINESTRING or MULTILINESTRING, and
+// the chains of polygons as the oriented loops of a POLYGON or MULTIPOLYGON.
+func MarshalShape(shape s2.Shape) (string, error) {
+	switch s := shape.(type) {
+	case *s2.PointVector:
+		return MarshalPointVector(*s), nil
+	case *s2.Polyline:
+		return MarshalPolyline(s), nil
+	case *s2.Polygon:
+		return MarshalPolygon(s)
+	case *s2.Loop:
+		if s.IsFull() {
+			return "", fmt.Errorf("wkt: %v", errFull)
+		}
+		var loops []*s2.Loop
+		if !s.IsEmpty() {
+			loops = append(loops, s2.LoopFromPoints(s.Vertices()))
+		}
+		return MarshalPolygon(s2.PolygonFromLoops(loops))
+	}
+
+	switch shape.Dimension() {
+	case 0:
+		points := make(s2.PointVector, 0, shape.NumEdges())
+		for i := 0; i < shape.NumEdges(); i++ {
+			points = append(points, shape.Edge(i).V0)
+		}
+		return MarshalPointVector(points), nil
+	case 1:
+		return marshalChains(ogc.ShapeChains(shape, false), false), nil
+	default:
+		if shape.IsFull() {
+			return "", fmt.Errorf("wkt: %v", errFull)
+		}
+		var loops []*s2.Loop
+		for _, chain := range ogc.ShapeChains(shape, true) {
+			loops = append(loops, s2.LoopFromPoints(chain))
+		}
+		p := s2.PolygonFromOrientedLoops(loops)
+		if err := p.Validate(); err != nil {
+			return "", fmt.Errorf("wkt: invalid polygon shape: %v", err)
+		}
+		return MarshalPolygon(p)
+	}
+}
+
+// writePoint writes the coordinates of p as "longitude latitude".
+func writePoint(b *strings.Builder, p s2.Point) {
+	ll := s2.LatLngFromPoint(p)
+	b.WriteString(formatDegrees(ll.Lng.Degrees()))
+	b.WriteString(" ")
+	b.WriteString(formatDegrees(ll.Lat.Degrees()))
+}
+
+// formatDegrees formats an angle in degrees with 15 significant digits, as
+// PostGIS does. This hides the rounding error of converting a Point back to
+// degrees, so that a Point created from degrees is written with the degrees it
+// was created from.
+func formatDegrees(deg float64) string {
+	deg, _ = strconv.ParseFloat(strconv.FormatFloat(deg, 'g', 15, 64), 64)
+	if deg == 0 {
+		deg = 0 // Avoid writing "-0".
+	}
+	return strconv.FormatFloat(deg, 'f', -1, 64)
+}
+
+// writePoints writes a parenthesized list of points.
+func writePoints(b *strings.Builder, points []s2.Point) {
+	b.WriteString("(")
+	for i, p := range points {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		writePoint(b, p)
+	}
+	b.WriteString(")")
+}
+
+// writeRings writes a parenthesized list of closed rings.
+func writeRings(b *strings.Builder, rings [][]s2.Point) {
+	b.WriteString("(")
+	for i, ring := range rings {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		writePoints(b, append(ring[:len(ring):len(ring)], ring[0]))
+	}
+	b.WriteString(")")
+}
@@ -0,0 +1,289 @@
+// Copyright 2026 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wkt
+
+import (
+	"testing"
+
+	"github.com/rubenpoppe/geo/s2"
+)
+
+// opaqueShape hides the concrete type of a shape, so that it is written
+// according to its dimension like any other shape, such as a lax polyline or a
+// lax polygon.
+type opaqueShape struct {
+	s2.Shape
+}
+
+// loopsApproxEqual reports whether the two loops have approximately the same
+// vertices in the same cyclic order.
+func loopsApproxEqual(a, b *s2.Loop) bool {
+	n := a.NumVertices()
+	if n != b.NumVertices() {
+		return false
+	}
+	for offset := 0; offset < n; offset++ {
+		equal := true
+		for i := 0; i < n && equal; i++ {
+			equal = a.Vertex(i).ApproxEqual(b.Vertex(i + offset))
+		}
+		if equal {
+			return true
+		}
+	}
+	return false
+}
+
+// polygonsApproxEqual reports whether each loop of a is approximately equal to
+// the loop of b with the same index.
+func polygonsApproxEqual(a, b *s2.Polygon) bool {
+	if a.NumLoops() != b.NumLoops() {
+		return false
+	}
+	for i := 0; i < a.NumLoops(); i++ {
+		if !loopsApproxEqual(a.Loop(i), b.Loop(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMarshalPoint(t *testing.T) {
+	tests := []struct {
+		p    s2.Point
+		want string
+	}{
+		{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), "POINT (0 0)"},
+		{s2.PointFromLatLng(s2.LatLngFromDegrees(10, 20)), "POINT (20 10)"},
+		{s2.PointFromLatLng(s2.LatLngFromDegrees(-45.5, -120.25)), "POINT (-120.25 -45.5)"},
+	}
+	for _, test := range tests {
+		if got := MarshalPoint(test.p); got != test.want {
+			t.Errorf("MarshalPoint(%v) = %q, want %q", test.p, got, test.want)
+		}
+	}
+}
+
+func TestMarshalPointVector(t *testing.T) {
+	tests := []struct {
+		points s2.PointVector
+		want   string
+	}{
+		{nil, "MULTIPOINT EMPTY"},
+		{s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(1, 2))}, "MULTIPOINT ((2 1))"},
+		{s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(1, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(-3, 4))}, "MULTIPOINT ((2 1), (4 -3))"},
+	}
+	for _, test := range tests {
+		if got := MarshalPointVector(test.points); got != test.want {
+			t.Errorf("MarshalPointVector(%v) = %q, want %q", test.points, got, test.want)
+		}
+	}
+}
+
+func TestMarshalPolyline(t *testing.T) {
+	tests := []struct {
+		lines []*s2.Polyline
+		want  string
+	}{
+		{nil, "MULTILINESTRING EMPTY"},
+		{
+			[]*s2.Polyline{{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 10))}},
+			"MULTILINESTRING ((0 0, 10 0))",
+		},
+		{
+			[]*s2.Polyline{{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 10))}, {s2.PointFromLatLng(s2.LatLngFromDegrees(5, 5)), s2.PointFromLatLng(s2.LatLngFromDegrees(6, 6)), s2.PointFromLatLng(s2.LatLngFromDegrees(7, 5))}},
+			"MULTILINESTRING ((0 0, 10 0), (5 5, 6 6, 5 7))",
+		},
+	}
+	for _, test := range tests {
+		if got := MarshalPolylines(test.lines); got != test.want {
+			t.Errorf("MarshalPolylines(%v) = %q, want %q", test.lines, got, test.want)
+		}
+	}
+
+	if got, want := MarshalPolyline(&s2.Polyline{}), "LINESTRING EMPTY"; got != want {
+		t.Errorf("MarshalPolyline(empty) = %q, want %q", got, want)
+	}
+	l := s2.PolylineFromLatLngs([]s2.LatLng{s2.LatLngFromDegrees(1, 2), s2.LatLngFromDegrees(3, 4)})
+	if got, want := MarshalPolyline(l), "LINESTRING (2 1, 4 3)"; got != want {
+		t.Errorf("MarshalPolyline(%v) = %q, want %q", l, got, want)
+	}
+}
+
+func TestMarshalPolygon(t *testing.T) {
+	shell := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 0))})
+	hole := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(2, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(2, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(4, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(4, 2))})
+	island := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(20, 20)), s2.PointFromLatLng(s2.LatLngFromDegrees(20, 21)), s2.PointFromLatLng(s2.LatLngFromDegrees(21, 21))})
+
+	tests := []struct {
+		name  string
+		loops []*s2.Loop
+		want  string
+	}{
+		{"empty", nil, "POLYGON EMPTY"},
+		{
+			"shell",
+			[]*s2.Loop{shell},
+			"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0))",
+		},
+		{
+			"shell with hole",
+			[]*s2.Loop{shell, hole},
+			"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (2 4, 4 4, 4 2, 2 2, 2 4))",
+		},
+		{
+			"two shells",
+			[]*s2.Loop{shell, hole, island},
+			"MULTIPOLYGON (((0 0, 10 0, 10 10, 0 10, 0 0), (2 4, 4 4, 4 2, 2 2, 2 4)), ((20 20, 21 20, 21 21, 20 20)))",
+		},
+	}
+	for _, test := range tests {
+		got, err := MarshalPolygon(s2.PolygonFromLoops(test.loops))
+		if err != nil {
+			t.Errorf("%s: MarshalPolygon failed: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: MarshalPolygon = %q, want %q", test.name, got, test.want)
+		}
+	}
+
+	if _, err := MarshalPolygon(s2.FullPolygon()); err == nil {
+		t.Errorf("MarshalPolygon(full) succeeded, want error")
+	}
+}
+
+func TestMarshalShape(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape s2.Shape
+		want  string
+	}{
+		{
+			"point vector",
+			&s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(1, 2))},
+			"MULTIPOINT ((2 1))",
+		},
+		{
+			"polyline",
+			&s2.Polyline{s2.PointFromLatLng(s2.LatLngFromDegrees(1, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(3, 4))},
+			"LINESTRING (2 1, 4 3)",
+		},
+		{
+			"loop",
+			s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 1)), s2.PointFromLatLng(s2.LatLngFromDegrees(1, 1))}),
+			"POLYGON ((0 0, 1 0, 1 1, 0 0))",
+		},
+		{
+			"empty loop",
+			s2.EmptyLoop(),
+			"POLYGON EMPTY",
+		},
+		{
+			"lax points",
+			opaqueShape{&s2.PointVector{s2.PointFromLatLng(s2.LatLngFromDegrees(1, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(3, 4))}},
+			"MULTIPOINT ((2 1), (4 3))",
+		},
+		{
+			"lax polyline",
+			opaqueShape{&s2.Polyline{s2.PointFromLatLng(s2.LatLngFromDegrees(1, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(3, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(5, 6))}},
+			"LINESTRING (2 1, 4 3, 6 5)",
+		},
+		{
+			"lax polygon with hole",
+			opaqueShape{s2.PolygonFromLoops([]*s2.Loop{
+				s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 0))}),
+				s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(2, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(2, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(4, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(4, 2))}),
+			})},
+			"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (2 4, 4 4, 4 2, 2 2, 2 4))",
+		},
+		{
+			"lax polygon with two shells",
+			opaqueShape{s2.PolygonFromLoops([]*s2.Loop{
+				s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 1)), s2.PointFromLatLng(s2.LatLngFromDegrees(1, 1))}),
+				s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(5, 5)), s2.PointFromLatLng(s2.LatLngFromDegrees(5, 6)), s2.PointFromLatLng(s2.LatLngFromDegrees(6, 6))}),
+			})},
+			"MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)), ((5 5, 6 5, 6 6, 5 5)))",
+		},
+		{
+			"empty lax polygon",
+			opaqueShape{&s2.Polygon{}},
+			"POLYGON EMPTY",
+		},
+	}
+	for _, test := range tests {
+		got, err := MarshalShape(test.shape)
+		if err != nil {
+			t.Errorf("%s: MarshalShape failed: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: MarshalShape = %q, want %q", test.name, got, test.want)
+		}
+	}
+
+	if _, err := MarshalShape(s2.FullLoop()); err == nil {
+		t.Errorf("MarshalShape(full loop) succeeded, want error")
+	}
+	if _, err := MarshalShape(opaqueShape{s2.FullPolygon()}); err == nil {
+		t.Errorf("MarshalShape(full polygon shape) succeeded, want error")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	lls := []s2.LatLng{
+		s2.LatLngFromDegrees(37.7749, -122.4194),
+		s2.LatLngFromDegrees(40.7128, -74.006),
+		s2.LatLngFromDegrees(-33.8688, 151.2093),
+		s2.LatLngFromDegrees(0.1, 179.9),
+	}
+	for _, ll := range lls {
+		p := s2.PointFromLatLng(ll)
+		got, err := UnmarshalPoint(MarshalPoint(p))
+		if err != nil {
+			t.Fatalf("UnmarshalPoint(MarshalPoint(%v)) failed: %v", ll, err)
+		}
+		if got != p {
+			t.Errorf("UnmarshalPoint(MarshalPoint(%v)) = %v, want %v", ll, s2.LatLngFromPoint(got), ll)
+		}
+	}
+
+	l := s2.PolylineFromLatLngs(lls)
+	got, err := UnmarshalPolyline(MarshalPolyline(l))
+	if err != nil {
+		t.Fatalf("UnmarshalPolyline(MarshalPolyline(%v)) failed: %v", l, err)
+	}
+	if !got.Equal(l) {
+		t.Errorf("UnmarshalPolyline(MarshalPolyline(%v)) = %v, want %v", l, got, l)
+	}
+
+	shell := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)), s2.PointFromLatLng(s2.LatLngFromDegrees(0, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 10)), s2.PointFromLatLng(s2.LatLngFromDegrees(10, 0))})
+	hole := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(2, 2)), s2.PointFromLatLng(s2.LatLngFromDegrees(2, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(4, 4)), s2.PointFromLatLng(s2.LatLngFromDegrees(4, 2))})
+	island := s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(s2.LatLngFromDegrees(20, 20)), s2.PointFromLatLng(s2.LatLngFromDegrees(20, 21)), s2.PointFromLatLng(s2.LatLngFromDegrees(21, 21))})
+	for _, loops := range [][]*s2.Loop{nil, {shell}, {shell, hole}, {shell, hole, island}} {
+		p := s2.PolygonFromLoops(loops)
+		s, err := MarshalPolygon(p)
+		if err != nil {
+			t.Fatalf("MarshalPolygon(%v) failed: %v", p, err)
+		}
+		got, err := UnmarshalPolygon(s)
+		if err != nil {
+			t.Fatalf("UnmarshalPolygon(%q) failed: %v", s, err)
+		}
+		if !polygonsApproxEqual(got, p) {
+			t.Errorf("UnmarshalPolygon(%q) = %v, want %v", s, got, p)
+		}
+	}
+}